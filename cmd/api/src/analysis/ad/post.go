@@ -27,6 +27,7 @@ import (
 	"github.com/specterops/bloodhound/dawgs/graph"
 	"github.com/specterops/bloodhound/dawgs/util/channels"
 	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
 	"github.com/specterops/bloodhound/log"
 )
 
@@ -39,12 +40,38 @@ func PostProcessedRelationships(localGroupPostProcessingFlag appcfg.FeatureFlag)
 			ad.AdminTo,
 			ad.CanPSRemote,
 			ad.ExecuteDCOM,
+			ad.TrustedForNTAuth,
+			ad.IssuedSignedBy,
+			ad.EnterpriseCAFor,
+			ad.GoldenCert,
+			ad.ADCSESC1,
+			ad.ADCSESC3,
+			ad.ADCSESC4,
+			ad.ADCSESC6,
+			ad.ADCSESC8,
+			ad.CoerceToTGT,
+			ad.AdminToViaRBCD,
+			ad.SyncedToEntraUser,
+			ad.HostsEntraSyncAccount,
 		}
 	}
 
 	return []graph.Kind{
 		ad.SyncLAPSPassword,
 		ad.DCSync,
+		ad.TrustedForNTAuth,
+		ad.IssuedSignedBy,
+		ad.EnterpriseCAFor,
+		ad.GoldenCert,
+		ad.ADCSESC1,
+		ad.ADCSESC3,
+		ad.ADCSESC4,
+		ad.ADCSESC6,
+		ad.ADCSESC8,
+		ad.CoerceToTGT,
+		ad.AdminToViaRBCD,
+		ad.SyncedToEntraUser,
+		ad.HostsEntraSyncAccount,
 	}
 }
 
@@ -176,11 +203,23 @@ func Post(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessin
 		return &aggregateStats, err
 	} else if localGroupStats, err := PostLocalGroups(ctx, db); err != nil {
 		return &aggregateStats, err
+	} else if adcsStats, err := adAnalysis.PostADCS(ctx, db); err != nil {
+		return &aggregateStats, err
+	} else if delegationStats, err := adAnalysis.PostDelegation(ctx, db); err != nil {
+		return &aggregateStats, err
+	} else if hybridDeleteStats, err := analysis.DeleteTransitEdges(ctx, db, ad.Entity, azure.Entity, adAnalysis.HybridPostProcessedRelationships()...); err != nil {
+		return &aggregateStats, err
+	} else if hybridStats, err := adAnalysis.PostHybrid(ctx, db); err != nil {
+		return &aggregateStats, err
 	} else {
 		aggregateStats.Merge(stats)
 		aggregateStats.Merge(syncLAPSStats)
 		aggregateStats.Merge(dcSyncStats)
 		aggregateStats.Merge(localGroupStats)
+		aggregateStats.Merge(adcsStats)
+		aggregateStats.Merge(delegationStats)
+		aggregateStats.Merge(hybridDeleteStats)
+		aggregateStats.Merge(hybridStats)
 		return &aggregateStats, nil
 	}
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/specterops/bloodhound/analysis"
 	azureAnalysis "github.com/specterops/bloodhound/analysis/azure"
 	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
 	"github.com/specterops/bloodhound/graphschema/azure"
 )
 
@@ -37,12 +38,18 @@ func Post(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessin
 		return &aggregateStats, err
 	} else if appRoleAssignmentStats, err := azureAnalysis.AppRoleAssignments(ctx, db); err != nil {
 		return &aggregateStats, err
+	} else if hybridDeleteStats, err := analysis.DeleteTransitEdges(ctx, db, azure.Entity, ad.Entity, azureAnalysis.AzureHybridPostProcessedRelationships()...); err != nil {
+		return &aggregateStats, err
+	} else if syncedUserStats, err := azureAnalysis.PostSyncedToADUser(ctx, db); err != nil {
+		return &aggregateStats, err
 	} else {
 		aggregateStats.Merge(stats)
 		aggregateStats.Merge(userRoleStats)
 		aggregateStats.Merge(addSecretStats)
 		aggregateStats.Merge(executeCommandStats)
 		aggregateStats.Merge(appRoleAssignmentStats)
+		aggregateStats.Merge(hybridDeleteStats)
+		aggregateStats.Merge(syncedUserStats)
 		return &aggregateStats, nil
 	}
 }
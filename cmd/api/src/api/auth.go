@@ -64,6 +64,7 @@ type Authenticator interface {
 	ValidateRequestSignature(tokenID uuid.UUID, request *http.Request, serverTime time.Time) (auth.Context, int, error)
 	CreateSession(user model.User, authProvider any) (string, error)
 	ValidateSession(jwtTokenString string) (auth.Context, error)
+	ValidateSCIMToken(token string) (auth.Context, error)
 }
 
 type authenticator struct {
@@ -232,6 +233,10 @@ func (s authenticator) CreateSession(user model.User, authProvider any) (string,
 	case model.SAMLProvider:
 		userSession.AuthProviderType = model.SessionAuthProviderSAML
 		userSession.AuthProviderID = typedAuthProvider.ID
+
+	case model.OIDCProvider:
+		userSession.AuthProviderType = model.SessionAuthProviderOIDC
+		userSession.AuthProviderID = typedAuthProvider.ID
 	}
 
 	if newSession, err := s.db.CreateUserSession(userSession); err != nil {
@@ -316,6 +321,46 @@ func (s authenticator) ValidateSession(jwtTokenString string) (auth.Context, err
 	}
 }
 
+// ValidateSCIMToken authenticates a request of an identity governance platform against the SCIM provisioning API. The
+// request acts on behalf of the user that created the token and is only granted the SCIM provisioning permission, which
+// requires the user to still be enabled and allowed to manage users.
+func (s authenticator) ValidateSCIMToken(token string) (auth.Context, error) {
+	if scimToken, err := s.db.LookupSCIMToken(auth.SCIMTokenDigest(token)); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return auth.Context{}, ErrInvalidAuth
+		}
+
+		return auth.Context{}, FormatDatabaseError(err)
+	} else if user, err := s.db.GetUser(scimToken.UserID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return auth.Context{}, ErrInvalidAuth
+		}
+
+		return auth.Context{}, FormatDatabaseError(err)
+	} else if user.IsDisabled {
+		return auth.Context{}, ErrUserDisabled
+	} else if !user.Roles.Permissions().Has(auth.Permissions().AuthManageUsers) {
+		log.Infof("SCIM token %d owner %s is no longer allowed to manage users", scimToken.ID, user.PrincipalName)
+		return auth.Context{}, ErrInvalidAuth
+	} else {
+		scimToken.LastAccess = time.Now().UTC()
+
+		if err := s.db.UpdateSCIMToken(scimToken); err != nil {
+			log.Errorf("Error updating last access on SCIMToken: %v", err)
+		}
+
+		return auth.Context{
+			Owner: user,
+			PermissionOverrides: auth.PermissionOverrides{
+				Enabled: true,
+				Permissions: model.Permissions{
+					auth.Permissions().AuthSCIMProvisioning,
+				},
+			},
+		}, nil
+	}
+}
+
 type LoginRequest struct {
 	LoginMethod string `json:"login_method"`
 	Username    string `json:"username"`
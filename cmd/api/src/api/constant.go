@@ -32,10 +32,13 @@ const (
 	AuthorizationSchemeBearer       = "bearer"
 
 	// Query parameters
-	QueryParameterSortBy         = "sort_by"
-	QueryParameterHydrateCounts  = "counts"
-	QueryParameterHydrateDomains = "hydrate_domains"
-	QueryParameterHydrateOUs     = "hydrate_ous"
+	QueryParameterSortBy          = "sort_by"
+	QueryParameterHydrateCounts   = "counts"
+	QueryParameterHydrateDomains  = "hydrate_domains"
+	QueryParameterHydrateOUs      = "hydrate_ous"
+	QueryParameterAsOf            = "as_of"
+	QueryParameterFinding         = "finding"
+	QueryParameterIncludeResolved = "include_resolved"
 
 	// URI path parameters
 	URIPathVariableApplicationConfigurationParameter = "parameter"
@@ -43,6 +46,7 @@ const (
 	URIPathVariableAssetGroupSelectorID              = "asset_group_selector_id"
 	URIPathVariableAttackPathID                      = "attack_path_id"
 	URIPathVariableClientID                          = "client_id"
+	URIPathVariableCompareSnapshotID                 = "compare_snapshot_id"
 	URIPathVariableDomainID                          = "domain_id"
 	URIPathVariableEventID                           = "event_id"
 	URIPathVariableFeatureID                         = "feature_id"
@@ -50,9 +54,16 @@ const (
 	URIPathVariableObjectID                          = "object_id"
 	URIPathVariablePermissionID                      = "permission_id"
 	URIPathVariablePlatformID                        = "platform_id"
+	URIPathVariablePostProcessingRuleID              = "rule_id"
 	URIPathVariableRoleID                            = "role_id"
 	URIPathVariableSAMLProviderID                    = "saml_provider_id"
 	URIPathVariableServiceProviderName               = "saml_provider_name"
+	URIPathVariableOIDCProviderID                    = "oidc_provider_id"
+	URIPathVariableOIDCProviderName                  = "oidc_provider_name"
+	URIPathVariableSCIMGroupID                       = "scim_group_id"
+	URIPathVariableSCIMTokenID                       = "scim_token_id"
+	URIPathVariableSCIMUserID                        = "scim_user_id"
+	URIPathVariableSnapshotID                        = "snapshot_id"
 	URIPathVariableTaskID                            = "task_id"
 	URIPathVariableTenantID                          = "tenant_id"
 	URIPathVariableTokenID                           = "token_id"
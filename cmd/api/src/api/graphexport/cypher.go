@@ -0,0 +1,206 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package graphexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+var cypherSymbolicNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// cypherName renders a label, relationship type or property key, escaping it when required
+func cypherName(name string) string {
+	if cypherSymbolicNamePattern.MatchString(name) {
+		return name
+	}
+
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func cypherString(value string) string {
+	var builder strings.Builder
+
+	builder.WriteRune('\'')
+
+	for _, nextRune := range value {
+		switch nextRune {
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\'':
+			builder.WriteString(`\'`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			builder.WriteRune(nextRune)
+		}
+	}
+
+	builder.WriteRune('\'')
+	return builder.String()
+}
+
+func cypherFloat(value float64) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return cypherString(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+
+	// Keep integral floats as floats when they are read back
+	if !strings.Contains(formatted, ".") {
+		formatted += ".0"
+	}
+
+	return formatted
+}
+
+// cypherLiteral renders a property value as a Cypher literal. Values that Neo4j can not store as a property, such as
+// maps, are rendered as JSON strings.
+func cypherLiteral(value any) string {
+	switch typedValue := value.(type) {
+	case string:
+		return cypherString(typedValue)
+
+	case bool:
+		return strconv.FormatBool(typedValue)
+
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", typedValue)
+
+	case float32:
+		return cypherFloat(float64(typedValue))
+
+	case float64:
+		return cypherFloat(typedValue)
+
+	case time.Time:
+		return "datetime(" + cypherString(typedValue.Format(time.RFC3339Nano)) + ")"
+
+	case []any:
+		elements := make([]string, len(typedValue))
+
+		for idx, element := range typedValue {
+			elements[idx] = cypherLiteral(element)
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+
+	case []string:
+		elements := make([]string, len(typedValue))
+
+		for idx, element := range typedValue {
+			elements[idx] = cypherString(element)
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+
+	default:
+		if content, err := json.Marshal(typedValue); err != nil {
+			return cypherString(fmt.Sprintf("%v", typedValue))
+		} else {
+			return cypherString(string(content))
+		}
+	}
+}
+
+func cypherProperties(properties *graph.Properties) string {
+	if properties == nil || len(properties.Map) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(properties.Map))
+
+	for key, value := range properties.Map {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return ""
+	}
+
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+
+	for idx, key := range keys {
+		entries[idx] = cypherName(key) + ": " + cypherLiteral(properties.Map[key])
+	}
+
+	return " {" + strings.Join(entries, ", ") + "}"
+}
+
+func cypherNodeVariable(id graph.ID) string {
+	return "n" + id.String()
+}
+
+// writeCypher renders the graph as a single CREATE statement that recreates every node and relationship when run
+// against an empty Neo4j database. Relationships whose start or end node is not part of the graph are omitted.
+func writeCypher(exportGraph Graph, writer io.Writer) error {
+	var patterns []string
+
+	for _, node := range exportGraph.sortedNodes() {
+		var pattern strings.Builder
+
+		pattern.WriteString("(")
+		pattern.WriteString(cypherNodeVariable(node.ID))
+
+		for _, kind := range node.Kinds {
+			pattern.WriteString(":")
+			pattern.WriteString(cypherName(kind.String()))
+		}
+
+		pattern.WriteString(cypherProperties(node.Properties))
+		pattern.WriteString(")")
+
+		patterns = append(patterns, pattern.String())
+	}
+
+	for _, relationship := range exportGraph.sortedRelationships() {
+		if !exportGraph.Nodes.ContainsID(relationship.StartID) || !exportGraph.Nodes.ContainsID(relationship.EndID) {
+			continue
+		}
+
+		patterns = append(patterns, fmt.Sprintf("(%s)-[:%s%s]->(%s)",
+			cypherNodeVariable(relationship.StartID),
+			cypherName(relationship.Kind.String()),
+			cypherProperties(relationship.Properties),
+			cypherNodeVariable(relationship.EndID)))
+	}
+
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(writer, "CREATE\n  "+strings.Join(patterns, ",\n  ")+";\n")
+	return err
+}
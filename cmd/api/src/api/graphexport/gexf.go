@@ -0,0 +1,174 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package graphexport
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+const (
+	gexfNamespace = "http://gexf.net/1.3"
+	gexfVersion   = "1.3"
+
+	// gexfKindsAttributeID is the ID of the node attribute that holds node kinds
+	gexfKindsAttributeID = "kinds"
+)
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Kind      string         `xml:"kind,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfMeta struct {
+	Creator string `xml:"creator"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+func gexfAttributeList(attributes []attribute) []gexfAttribute {
+	gexfAttributes := make([]gexfAttribute, len(attributes))
+
+	for idx, attribute := range attributes {
+		gexfAttributes[idx] = gexfAttribute{
+			ID:    attribute.ID,
+			Title: attribute.Name,
+			Type:  attribute.Type,
+		}
+	}
+
+	return gexfAttributes
+}
+
+func writeGEXF(exportGraph Graph, writer io.Writer) error {
+	var (
+		nodes                  = exportGraph.sortedNodes()
+		relationships          = exportGraph.sortedRelationships()
+		nodeAttributes         = collectAttributes("n", nodeProperties(nodes)...)
+		relationshipAttributes = collectAttributes("e", relationshipProperties(relationships)...)
+		document               = gexfDocument{
+			XMLNS:   gexfNamespace,
+			Version: gexfVersion,
+			Meta: gexfMeta{
+				Creator: "BloodHound",
+			},
+			Graph: gexfGraph{
+				DefaultEdgeType: "directed",
+				Mode:            "static",
+				Attributes: []gexfAttributes{{
+					Class: "node",
+					Attributes: append([]gexfAttribute{{
+						ID:    gexfKindsAttributeID,
+						Title: gexfKindsAttributeID,
+						Type:  attributeTypeString,
+					}}, gexfAttributeList(nodeAttributes)...),
+				}, {
+					Class:      "edge",
+					Attributes: gexfAttributeList(relationshipAttributes),
+				}},
+				Nodes: make([]gexfNode, 0, len(nodes)),
+				Edges: make([]gexfEdge, 0, len(relationships)),
+			},
+		}
+	)
+
+	for _, node := range nodes {
+		gexfNode := gexfNode{
+			ID:    node.ID.String(),
+			Label: nodeLabel(node),
+			AttValues: []gexfAttValue{{
+				For:   gexfKindsAttributeID,
+				Value: strings.Join(node.Kinds.Strings(), ","),
+			}},
+		}
+
+		for _, attribute := range nodeAttributes {
+			if value, found := attributeValue(node.Properties, attribute); found {
+				gexfNode.AttValues = append(gexfNode.AttValues, gexfAttValue{
+					For:   attribute.ID,
+					Value: value,
+				})
+			}
+		}
+
+		document.Graph.Nodes = append(document.Graph.Nodes, gexfNode)
+	}
+
+	for _, relationship := range relationships {
+		gexfEdge := gexfEdge{
+			ID:     relationship.ID.String(),
+			Source: relationship.StartID.String(),
+			Target: relationship.EndID.String(),
+			Kind:   relationship.Kind.String(),
+			Label:  relationship.Kind.String(),
+		}
+
+		for _, attribute := range relationshipAttributes {
+			if value, found := attributeValue(relationship.Properties, attribute); found {
+				gexfEdge.AttValues = append(gexfEdge.AttValues, gexfAttValue{
+					For:   attribute.ID,
+					Value: value,
+				})
+			}
+		}
+
+		document.Graph.Edges = append(document.Graph.Edges, gexfEdge)
+	}
+
+	return writeXMLDocument(writer, document)
+}
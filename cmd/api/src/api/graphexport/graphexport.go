@@ -0,0 +1,157 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package graphexport
+
+import (
+	"io"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/graphschema/common"
+)
+
+const (
+	ErrUnsupportedFormat = errors.Error("unsupported graph export format")
+)
+
+// Format is a standard graph interchange format that graph results may be exported as
+type Format string
+
+const (
+	FormatGraphML Format = "application/graphml+xml"
+	FormatGEXF    Format = "application/gexf+xml"
+	FormatCypher  Format = "application/x-cypher-query"
+)
+
+func (s Format) String() string {
+	return string(s)
+}
+
+// FileExtension returns the file extension conventionally used for files of the format
+func (s Format) FileExtension() string {
+	switch s {
+	case FormatGraphML:
+		return ".graphml"
+	case FormatGEXF:
+		return ".gexf"
+	case FormatCypher:
+		return ".cypher"
+	default:
+		return ""
+	}
+}
+
+// NegotiateFormat returns the export format requested by the given Accept header value. Media ranges are considered
+// in the order given and false is returned when a media range that is not an export format, for example
+// application/json or */*, is listed before any export format.
+func NegotiateFormat(accept string) (Format, bool) {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange)); err != nil {
+			continue
+		} else {
+			switch format := Format(mediaType); format {
+			case FormatGraphML, FormatGEXF, FormatCypher:
+				return format, true
+
+			default:
+				return "", false
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Graph is the content of a graph result in the form consumed by the exporters
+type Graph struct {
+	Nodes         graph.NodeSet
+	Relationships graph.RelationshipSet
+}
+
+func NewGraph() Graph {
+	return Graph{
+		Nodes:         graph.NewNodeSet(),
+		Relationships: graph.NewRelationshipSet(),
+	}
+}
+
+// FromPathSet collects every node and relationship of the given paths
+func FromPathSet(paths graph.PathSet) Graph {
+	exportGraph := NewGraph()
+
+	for _, path := range paths.Paths() {
+		exportGraph.Nodes.Add(path.Nodes...)
+		exportGraph.Relationships.Add(path.Edges...)
+	}
+
+	return exportGraph
+}
+
+func (s Graph) sortedNodes() []*graph.Node {
+	nodes := s.Nodes.Slice()
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	return nodes
+}
+
+func (s Graph) sortedRelationships() []*graph.Relationship {
+	relationships := make([]*graph.Relationship, 0, len(s.Relationships))
+
+	for _, relationship := range s.Relationships {
+		relationships = append(relationships, relationship)
+	}
+
+	sort.Slice(relationships, func(i, j int) bool {
+		return relationships[i].ID < relationships[j].ID
+	})
+
+	return relationships
+}
+
+// Write renders the graph in the given format to the writer
+func (s Graph) Write(format Format, writer io.Writer) error {
+	switch format {
+	case FormatGraphML:
+		return writeGraphML(s, writer)
+
+	case FormatGEXF:
+		return writeGEXF(s, writer)
+
+	case FormatCypher:
+		return writeCypher(s, writer)
+
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// nodeLabel returns the human-readable label of a node, falling back to its object ID and then its database ID
+func nodeLabel(node *graph.Node) string {
+	if name, _ := node.Properties.GetOrDefault(common.Name.String(), "").String(); name != "" {
+		return name
+	} else if objectID, _ := node.Properties.GetOrDefault(common.ObjectID.String(), "").String(); objectID != "" {
+		return objectID
+	}
+
+	return node.ID.String()
+}
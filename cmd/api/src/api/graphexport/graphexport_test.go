@@ -0,0 +1,172 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package graphexport_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/src/api/graphexport"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph() graphexport.Graph {
+	var (
+		user = graph.NewNode(1, graph.AsProperties(map[string]any{
+			"objectid":   "S-1-5-21-1-1104",
+			"name":       "O'NEIL@TESTLAB.LOCAL",
+			"admincount": true,
+			"lastlogon":  time.Date(2023, 10, 2, 14, 30, 0, 0, time.UTC),
+			"spns":       []any{"HTTP/web.testlab.local"},
+		}), ad.Entity, ad.User)
+		group = graph.NewNode(2, graph.AsProperties(map[string]any{
+			"objectid":   "S-1-5-21-1-512",
+			"name":       "DOMAIN ADMINS@TESTLAB.LOCAL",
+			"admincount": int64(1),
+		}), ad.Entity, ad.Group)
+		memberOf = graph.NewRelationship(10, user.ID, group.ID, graph.AsProperties(map[string]any{
+			"weight": 2.0,
+		}), ad.MemberOf)
+	)
+
+	return graphexport.FromPathSet(graph.NewPathSet(graph.Path{
+		Nodes: []*graph.Node{user, group},
+		Edges: []*graph.Relationship{memberOf},
+	}))
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	format, requested := graphexport.NegotiateFormat("application/graphml+xml")
+	require.True(t, requested)
+	require.Equal(t, graphexport.FormatGraphML, format)
+
+	_, requested = graphexport.NegotiateFormat("text/html, application/gexf+xml;q=0.9")
+	require.False(t, requested)
+
+	format, requested = graphexport.NegotiateFormat("application/x-cypher-query, application/json")
+	require.True(t, requested)
+	require.Equal(t, graphexport.FormatCypher, format)
+
+	_, requested = graphexport.NegotiateFormat("application/json, application/graphml+xml")
+	require.False(t, requested)
+
+	_, requested = graphexport.NegotiateFormat("")
+	require.False(t, requested)
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var (
+		output   = &bytes.Buffer{}
+		document struct {
+			Keys []struct {
+				ID   string `xml:"id,attr"`
+				For  string `xml:"for,attr"`
+				Name string `xml:"attr.name,attr"`
+				Type string `xml:"attr.type,attr"`
+			} `xml:"key"`
+			Nodes []struct {
+				ID     string `xml:"id,attr"`
+				Labels string `xml:"labels,attr"`
+			} `xml:"graph>node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Label  string `xml:"label,attr"`
+			} `xml:"graph>edge"`
+		}
+	)
+
+	require.Nil(t, testGraph().Write(graphexport.FormatGraphML, output))
+	require.Nil(t, xml.Unmarshal(output.Bytes(), &document))
+
+	require.Len(t, document.Nodes, 2)
+	require.Equal(t, "n1", document.Nodes[0].ID)
+	require.Equal(t, ":Base:User", document.Nodes[0].Labels)
+
+	require.Len(t, document.Edges, 1)
+	require.Equal(t, "n1", document.Edges[0].Source)
+	require.Equal(t, "n2", document.Edges[0].Target)
+	require.Equal(t, "MemberOf", document.Edges[0].Label)
+
+	// Properties with values of different types fall back to strings
+	for _, key := range document.Keys {
+		switch key.Name {
+		case "admincount":
+			require.Equal(t, "string", key.Type)
+		case "weight":
+			require.Equal(t, "double", key.Type)
+			require.Equal(t, "edge", key.For)
+		}
+	}
+
+	require.Contains(t, output.String(), "O&#39;NEIL@TESTLAB.LOCAL")
+}
+
+func TestWriteGEXF(t *testing.T) {
+	var (
+		output   = &bytes.Buffer{}
+		document struct {
+			Version string `xml:"version,attr"`
+			Nodes   []struct {
+				ID    string `xml:"id,attr"`
+				Label string `xml:"label,attr"`
+			} `xml:"graph>nodes>node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Kind   string `xml:"kind,attr"`
+			} `xml:"graph>edges>edge"`
+		}
+	)
+
+	require.Nil(t, testGraph().Write(graphexport.FormatGEXF, output))
+	require.Nil(t, xml.Unmarshal(output.Bytes(), &document))
+
+	require.Equal(t, "1.3", document.Version)
+	require.Len(t, document.Nodes, 2)
+	require.Equal(t, "O'NEIL@TESTLAB.LOCAL", document.Nodes[0].Label)
+	require.Len(t, document.Edges, 1)
+	require.Equal(t, "1", document.Edges[0].Source)
+	require.Equal(t, "2", document.Edges[0].Target)
+	require.Equal(t, "MemberOf", document.Edges[0].Kind)
+}
+
+func TestWriteCypher(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	require.Nil(t, testGraph().Write(graphexport.FormatCypher, output))
+	require.Equal(t, `CREATE
+  (n1:Base:User {admincount: true, lastlogon: datetime('2023-10-02T14:30:00Z'), name: 'O\'NEIL@TESTLAB.LOCAL', objectid: 'S-1-5-21-1-1104', spns: ['HTTP/web.testlab.local']}),
+  (n2:Base:Group {admincount: 1, name: 'DOMAIN ADMINS@TESTLAB.LOCAL', objectid: 'S-1-5-21-1-512'}),
+  (n1)-[:MemberOf {weight: 2.0}]->(n2);
+`, output.String())
+}
+
+func TestWriteCypherEmptyGraph(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	require.Nil(t, graphexport.NewGraph().Write(graphexport.FormatCypher, output))
+	require.Empty(t, output.String())
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	require.ErrorIs(t, testGraph().Write(graphexport.Format("text/plain"), &bytes.Buffer{}), graphexport.ErrUnsupportedFormat)
+}
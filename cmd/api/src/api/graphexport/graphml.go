@@ -0,0 +1,187 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package graphexport
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+const (
+	graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+	// graphMLKindsKey and graphMLKindKey hold node kinds and relationship kinds. They follow the Neo4j APOC GraphML
+	// conventions so that exported files may be imported into Neo4j as well as Gephi and yEd.
+	graphMLKindsKey = "labels"
+	graphMLKindKey  = "label"
+)
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID     string        `xml:"id,attr"`
+	Labels string        `xml:"labels,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Label  string        `xml:"label,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+func graphMLKeys(domain string, attributes []attribute) []graphMLKey {
+	keys := make([]graphMLKey, len(attributes))
+
+	for idx, attribute := range attributes {
+		keys[idx] = graphMLKey{
+			ID:   attribute.ID,
+			For:  domain,
+			Name: attribute.Name,
+			Type: attribute.Type,
+		}
+	}
+
+	return keys
+}
+
+func writeGraphML(exportGraph Graph, writer io.Writer) error {
+	var (
+		nodes                  = exportGraph.sortedNodes()
+		relationships          = exportGraph.sortedRelationships()
+		nodeAttributes         = collectAttributes("n", nodeProperties(nodes)...)
+		relationshipAttributes = collectAttributes("e", relationshipProperties(relationships)...)
+		document               = graphMLDocument{
+			XMLNS: graphMLNamespace,
+			Graph: graphMLGraph{
+				ID:          "G",
+				EdgeDefault: "directed",
+				Nodes:       make([]graphMLNode, 0, len(nodes)),
+				Edges:       make([]graphMLEdge, 0, len(relationships)),
+			},
+		}
+	)
+
+	document.Keys = append(document.Keys, graphMLKey{
+		ID:   graphMLKindsKey,
+		For:  "node",
+		Name: graphMLKindsKey,
+		Type: attributeTypeString,
+	}, graphMLKey{
+		ID:   graphMLKindKey,
+		For:  "edge",
+		Name: graphMLKindKey,
+		Type: attributeTypeString,
+	})
+
+	document.Keys = append(document.Keys, graphMLKeys("node", nodeAttributes)...)
+	document.Keys = append(document.Keys, graphMLKeys("edge", relationshipAttributes)...)
+
+	for _, node := range nodes {
+		var (
+			kinds       = ":" + strings.Join(node.Kinds.Strings(), ":")
+			graphMLNode = graphMLNode{
+				ID:     "n" + node.ID.String(),
+				Labels: kinds,
+				Data: []graphMLData{{
+					Key:   graphMLKindsKey,
+					Value: kinds,
+				}},
+			}
+		)
+
+		for _, attribute := range nodeAttributes {
+			if value, found := attributeValue(node.Properties, attribute); found {
+				graphMLNode.Data = append(graphMLNode.Data, graphMLData{
+					Key:   attribute.ID,
+					Value: value,
+				})
+			}
+		}
+
+		document.Graph.Nodes = append(document.Graph.Nodes, graphMLNode)
+	}
+
+	for _, relationship := range relationships {
+		graphMLEdge := graphMLEdge{
+			ID:     "e" + relationship.ID.String(),
+			Source: "n" + relationship.StartID.String(),
+			Target: "n" + relationship.EndID.String(),
+			Label:  relationship.Kind.String(),
+			Data: []graphMLData{{
+				Key:   graphMLKindKey,
+				Value: relationship.Kind.String(),
+			}},
+		}
+
+		for _, attribute := range relationshipAttributes {
+			if value, found := attributeValue(relationship.Properties, attribute); found {
+				graphMLEdge.Data = append(graphMLEdge.Data, graphMLData{
+					Key:   attribute.ID,
+					Value: value,
+				})
+			}
+		}
+
+		document.Graph.Edges = append(document.Graph.Edges, graphMLEdge)
+	}
+
+	return writeXMLDocument(writer, document)
+}
+
+func writeXMLDocument(writer io.Writer, document any) error {
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
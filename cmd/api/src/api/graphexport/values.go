@@ -0,0 +1,165 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package graphexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+// The attribute types shared by GraphML and GEXF
+const (
+	attributeTypeBoolean = "boolean"
+	attributeTypeLong    = "long"
+	attributeTypeDouble  = "double"
+	attributeTypeString  = "string"
+)
+
+type attribute struct {
+	ID   string
+	Name string
+	Type string
+}
+
+// attributeType returns the attribute type of a property value. Values that GraphML and GEXF have no type for, such
+// as lists and timestamps, are exported as strings.
+func attributeType(value any) string {
+	switch value.(type) {
+	case bool:
+		return attributeTypeBoolean
+
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return attributeTypeLong
+
+	case float32, float64:
+		return attributeTypeDouble
+
+	default:
+		return attributeTypeString
+	}
+}
+
+// formatAttributeValue renders a property value as the text of an attribute of the type returned by attributeType
+func formatAttributeValue(value any) string {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue
+
+	case bool:
+		return strconv.FormatBool(typedValue)
+
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", typedValue)
+
+	case float32:
+		return strconv.FormatFloat(float64(typedValue), 'g', -1, 32)
+
+	case float64:
+		return strconv.FormatFloat(typedValue, 'g', -1, 64)
+
+	case time.Time:
+		return typedValue.UTC().Format(time.RFC3339Nano)
+
+	default:
+		if content, err := json.Marshal(typedValue); err != nil {
+			return fmt.Sprintf("%v", typedValue)
+		} else {
+			return string(content)
+		}
+	}
+}
+
+// collectAttributes returns the attributes of every property found in the given property sets, sorted by name.
+// Properties with values of more than one type are exported as strings.
+func collectAttributes(idPrefix string, propertySets ...*graph.Properties) []attribute {
+	attributeTypes := map[string]string{}
+
+	for _, properties := range propertySets {
+		if properties == nil {
+			continue
+		}
+
+		for key, value := range properties.Map {
+			if value == nil {
+				continue
+			}
+
+			valueType := attributeType(value)
+
+			if existingType, found := attributeTypes[key]; !found {
+				attributeTypes[key] = valueType
+			} else if existingType != valueType {
+				attributeTypes[key] = attributeTypeString
+			}
+		}
+	}
+
+	attributes := make([]attribute, 0, len(attributeTypes))
+
+	for name, attributeType := range attributeTypes {
+		attributes = append(attributes, attribute{
+			Name: name,
+			Type: attributeType,
+		})
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Name < attributes[j].Name
+	})
+
+	for idx := range attributes {
+		attributes[idx].ID = idPrefix + strconv.Itoa(idx)
+	}
+
+	return attributes
+}
+
+func nodeProperties(nodes []*graph.Node) []*graph.Properties {
+	propertySets := make([]*graph.Properties, len(nodes))
+
+	for idx, node := range nodes {
+		propertySets[idx] = node.Properties
+	}
+
+	return propertySets
+}
+
+func relationshipProperties(relationships []*graph.Relationship) []*graph.Properties {
+	propertySets := make([]*graph.Properties, len(relationships))
+
+	for idx, relationship := range relationships {
+		propertySets[idx] = relationship.Properties
+	}
+
+	return propertySets
+}
+
+// attributeValue returns the value of the given attribute in the property set and whether the value is present
+func attributeValue(properties *graph.Properties, attribute attribute) (string, bool) {
+	if properties == nil {
+		return "", false
+	} else if value, found := properties.Map[attribute.Name]; !found || value == nil {
+		return "", false
+	} else {
+		return formatAttributeValue(value), true
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/specterops/bloodhound/headers"
 	"github.com/specterops/bloodhound/log"
 	"github.com/specterops/bloodhound/mediatypes"
+	"github.com/specterops/bloodhound/src/api/graphexport"
 	"github.com/specterops/bloodhound/src/api/stream"
 	"github.com/specterops/bloodhound/src/model"
 	"github.com/specterops/bloodhound/src/utils"
@@ -209,6 +210,22 @@ func WriteCSVResponse(ctx context.Context, message model.CSVWriter, statusCode i
 	}
 }
 
+// WriteGraphExportResponse writes the graph in the given interchange format as a file attachment
+func WriteGraphExportResponse(ctx context.Context, format graphexport.Format, exportGraph graphexport.Graph, statusCode int, response http.ResponseWriter) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		log.Warnf("Writing API Error. Context Deadline Exceeded while writing graph export response.")
+		return
+	}
+
+	response.Header().Set(headers.ContentType.String(), format.String())
+	response.Header().Set(headers.ContentDisposition.String(), fmt.Sprintf(utils.ContentDispositionAttachmentTemplate, "bloodhound-graph"+format.FileExtension()))
+	response.WriteHeader(statusCode)
+
+	if err := exportGraph.Write(format, response); err != nil {
+		log.Errorf("Writing API Error. Failed to write graph export for request: %v", err)
+	}
+}
+
 func WriteBinaryResponse(ctx context.Context, data []byte, filename string, statusCode int, response http.ResponseWriter) {
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		log.Warnf("Writing API Error. Context Deadline Exceeded whil writing binary response.")
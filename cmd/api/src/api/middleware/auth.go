@@ -55,7 +55,8 @@ func parseAuthorizationHeader(request *http.Request) (string, string, *api.Error
 // BloodHound Auth supports the following Authorization schemes:
 //
 //	`bearer`
-//	   Bearer token scheme that contains the user's authenticated session JWT as its parameter.
+//	   Bearer token scheme that contains the user's authenticated session JWT as its parameter. Bearer tokens that start
+//	   with `bhscim_` are SCIM tokens that authenticate identity governance platforms. See: `src/api/scim`
 //	`bhesignature`
 //	   Request signing scheme that contains the BloodHound token ID as its parameter. See: `src/api/v2/signature.go`
 func AuthMiddleware(authenticator api.Authenticator) mux.MiddlewareFunc {
@@ -67,7 +68,13 @@ func AuthMiddleware(authenticator api.Authenticator) mux.MiddlewareFunc {
 			} else {
 				switch authScheme {
 				case api.AuthorizationSchemeBearer:
-					if userAuth, err := authenticator.ValidateSession(schemeParameter); err != nil {
+					validateBearerToken := authenticator.ValidateSession
+
+					if auth.IsSCIMToken(schemeParameter) {
+						validateBearerToken = authenticator.ValidateSCIMToken
+					}
+
+					if userAuth, err := validateBearerToken(schemeParameter); err != nil {
 						api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid, request), response)
 						return
 					} else {
@@ -114,6 +114,21 @@ func (mr *MockAuthenticatorMockRecorder) ValidateRequestSignature(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRequestSignature", reflect.TypeOf((*MockAuthenticator)(nil).ValidateRequestSignature), arg0, arg1, arg2)
 }
 
+// ValidateSCIMToken mocks base method.
+func (m *MockAuthenticator) ValidateSCIMToken(arg0 string) (auth.Context, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateSCIMToken", arg0)
+	ret0, _ := ret[0].(auth.Context)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateSCIMToken indicates an expected call of ValidateSCIMToken.
+func (mr *MockAuthenticatorMockRecorder) ValidateSCIMToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateSCIMToken", reflect.TypeOf((*MockAuthenticator)(nil).ValidateSCIMToken), arg0)
+}
+
 // ValidateSecret mocks base method.
 func (m *MockAuthenticator) ValidateSecret(arg0 context.Context, arg1 string, arg2 model.AuthSecret) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,318 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/auth/bhoidc"
+	"github.com/specterops/bloodhound/src/auth/bhsso"
+	"github.com/specterops/bloodhound/src/config"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	ErrorPrincipalClaimNotFound = errors.Error("principal claim not found")
+	ErrorEmailNotVerified       = errors.Error("email claim is not verified")
+	ErrorAuthRequestInvalid     = errors.Error("authorization request is invalid")
+
+	// authRequestCookieName is the name of the signed cookie that carries the state, nonce and PKCE verifier of an
+	// in-flight authorization request between the login and callback endpoints
+	authRequestCookieName = "oidc_auth_request"
+	authRequestTTL        = 10 * time.Minute
+)
+
+type WriteAPIErrorResponse func(request *http.Request, response http.ResponseWriter, statusCode int, message string)
+
+type authRequestClaims struct {
+	jwt.StandardClaims
+
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+type Resource struct {
+	cfg                   config.Configuration
+	db                    database.Database
+	httpClient            api.HTTPClient
+	authenticator         api.Authenticator
+	provisioner           bhsso.Provisioner
+	writeAPIErrorResponse WriteAPIErrorResponse
+}
+
+func NewOIDCResource(cfg config.Configuration, db database.Database, writeAPIErrorResponse WriteAPIErrorResponse) Resource {
+	return Resource{
+		cfg:                   cfg,
+		db:                    db,
+		httpClient:            api.NewHTTPClient(),
+		authenticator:         api.NewAuthenticator(cfg, db, database.NewContextInitializer(db)),
+		provisioner:           bhsso.NewProvisioner(db),
+		writeAPIErrorResponse: writeAPIErrorResponse,
+	}
+}
+
+func (s Resource) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	pathVars := mux.Vars(request)
+
+	if providerName, hasProviderName := pathVars[api.URIPathVariableOIDCProviderName]; !hasProviderName {
+		s.writeAPIErrorResponse(request, response, http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid)
+	} else if provider, err := s.db.LookupOIDCProviderByName(providerName); err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			log.Errorf("[OIDC] Failed looking up OIDC provider %s: %v", providerName, err)
+		}
+
+		s.writeAPIErrorResponse(request, response, http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid)
+	} else {
+		providerURLs := bhoidc.FormatRelyingPartyURLs(*ctx.FromRequest(request).Host, provider.Name)
+
+		switch request.URL.Path {
+		case providerURLs.RelyingPartyRoot.Path, providerURLs.Login.Path:
+			s.serveStartAuthFlow(response, request, provider, providerURLs)
+
+		case providerURLs.Callback.Path:
+			s.serveCallback(response, request, provider, providerURLs)
+
+		default:
+			s.writeAPIErrorResponse(request, response, http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound)
+		}
+	}
+}
+
+func (s Resource) signAuthRequest(claims authRequestClaims) (string, error) {
+	if signingKeyBytes, err := s.cfg.Crypto.JWT.SigningKeyBytes(); err != nil {
+		return "", err
+	} else {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(signingKeyBytes)
+	}
+}
+
+// readAuthRequest validates the signed authorization request cookie and ensures that it was issued for the given
+// provider
+func (s Resource) readAuthRequest(request *http.Request, providerName string) (authRequestClaims, error) {
+	var claims authRequestClaims
+
+	if cookie, err := request.Cookie(authRequestCookieName); err != nil {
+		return claims, ErrorAuthRequestInvalid
+	} else if token, err := jwt.ParseWithClaims(cookie.Value, &claims, func(token *jwt.Token) (any, error) {
+		return s.cfg.Crypto.JWT.SigningKeyBytes()
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})); err != nil || !token.Valid {
+		return claims, ErrorAuthRequestInvalid
+	} else if claims.Subject != providerName || claims.State == "" || claims.Nonce == "" || claims.CodeVerifier == "" {
+		return claims, ErrorAuthRequestInvalid
+	}
+
+	return claims, nil
+}
+
+func setAuthRequestCookie(response http.ResponseWriter, hostURL url.URL, providerURLs bhoidc.RelyingPartyURLs, value string, expires time.Time) {
+	// The callback is reached by a cross-site, top level redirect from the provider which rules out a strict same
+	// site policy for this cookie
+	http.SetCookie(response, &http.Cookie{
+		Name:     authRequestCookieName,
+		Value:    value,
+		Expires:  expires,
+		Secure:   hostURL.Scheme == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Path:     providerURLs.RelyingPartyRoot.Path,
+	})
+}
+
+func (s Resource) serveStartAuthFlow(response http.ResponseWriter, request *http.Request, provider model.OIDCProvider, providerURLs bhoidc.RelyingPartyURLs) {
+	var (
+		hostURL = *ctx.FromRequest(request).Host
+		expires = time.Now().UTC().Add(authRequestTTL)
+	)
+
+	if document, err := bhoidc.FetchDiscoveryDocument(s.httpClient, request.Context(), provider.IssuerURI); err != nil {
+		log.Errorf("[OIDC] Failed fetching discovery document for OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else if state, err := bhoidc.NewRandomValue(); err != nil {
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else if nonce, err := bhoidc.NewRandomValue(); err != nil {
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else if codeVerifier, err := bhoidc.NewRandomValue(); err != nil {
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else if authRequestJWT, err := s.signAuthRequest(authRequestClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   provider.Name,
+			ExpiresAt: expires.Unix(),
+		},
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}); err != nil {
+		log.Errorf("[OIDC] Failed signing authorization request for OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else if authorizationURL, err := (bhoidc.AuthorizationRequest{
+		ClientID:     provider.ClientID,
+		RedirectURI:  providerURLs.Callback.String(),
+		Scopes:       bhoidc.Scopes(provider.Scopes),
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}).URL(document); err != nil {
+		log.Errorf("[OIDC] Failed formatting authorization URL for OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else {
+		setAuthRequestCookie(response, hostURL, providerURLs, authRequestJWT, expires)
+
+		response.Header().Add(headers.Location.String(), authorizationURL)
+		response.WriteHeader(http.StatusFound)
+	}
+}
+
+func (s Resource) serveCallback(response http.ResponseWriter, request *http.Request, provider model.OIDCProvider, providerURLs bhoidc.RelyingPartyURLs) {
+	var (
+		hostURL = *ctx.FromRequest(request).Host
+		query   = request.URL.Query()
+	)
+
+	if providerError := query.Get("error"); providerError != "" {
+		log.Errorf("[OIDC] OIDC provider %s returned an authorization error: %s - %s", provider.Name, providerError, query.Get("error_description"))
+		s.writeAPIErrorResponse(request, response, http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid)
+	} else if authRequest, err := s.readAuthRequest(request, provider.Name); err != nil {
+		log.Errorf("[OIDC] Failed validating authorization request for OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid)
+	} else if query.Get("state") != authRequest.State || query.Get("code") == "" {
+		log.Errorf("[OIDC] Callback for OIDC provider %s does not match the authorization request", provider.Name)
+		s.writeAPIErrorResponse(request, response, http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid)
+	} else if document, err := bhoidc.FetchDiscoveryDocument(s.httpClient, request.Context(), provider.IssuerURI); err != nil {
+		log.Errorf("[OIDC] Failed fetching discovery document for OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else if tokenResponse, err := bhoidc.ExchangeCode(s.httpClient, request.Context(), document, provider.ClientID, provider.ClientSecret, providerURLs.Callback.String(), query.Get("code"), authRequest.CodeVerifier); err != nil {
+		log.Errorf("[OIDC] Failed exchanging authorization code with OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid)
+	} else if keySet, err := bhoidc.FetchJSONWebKeySet(s.httpClient, request.Context(), document.JWKSURI); err != nil {
+		log.Errorf("[OIDC] Failed fetching signing keys for OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError)
+	} else if claims, err := bhoidc.ValidateIDToken(tokenResponse.IDToken, keySet, document.Issuer, provider.ClientID, authRequest.Nonce, time.Now()); err != nil {
+		log.Errorf("[OIDC] Failed validating ID token from OIDC provider %s: %v", provider.Name, err)
+		s.writeAPIErrorResponse(request, response, http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid)
+	} else {
+		// The authorization request is single use
+		setAuthRequestCookie(response, hostURL, providerURLs, "", time.Unix(0, 0))
+		s.createSessionFromClaims(request, response, provider, time.Now().UTC().Add(auth.SessionTTL), claims)
+	}
+}
+
+func principalClaim(provider model.OIDCProvider) string {
+	if provider.PrincipalClaim != "" {
+		return provider.PrincipalClaim
+	}
+
+	return bhoidc.DefaultPrincipalClaim
+}
+
+func firstClaimValue(claims jwt.MapClaims, name string) string {
+	if values := bhoidc.ClaimValues(claims, name); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+
+	return ""
+}
+
+// lookupOIDCUser resolves the identity asserted by the ID token claims to a user. Users may be provisioned and have
+// their roles synchronized depending on the role mappings of the provider. An email principal is only trusted when the
+// provider asserts that it verified the address since many providers let users set an arbitrary email address.
+func (s Resource) lookupOIDCUser(request *http.Request, provider model.OIDCProvider, claims jwt.MapClaims) (model.User, error) {
+	claimName := principalClaim(provider)
+
+	if principalName := firstClaimValue(claims, claimName); principalName == "" {
+		return model.User{}, ErrorPrincipalClaimNotFound
+	} else if claimName == bhoidc.ClaimEmail && !bhoidc.EmailVerified(claims) {
+		return model.User{}, ErrorEmailNotVerified
+	} else {
+		return s.provisioner.Provision(*ctx.FromRequest(request), provider, bhsso.Identity{
+			PrincipalName: principalName,
+			EmailAddress:  firstClaimValue(claims, bhoidc.ClaimEmail),
+			FirstName:     firstClaimValue(claims, bhoidc.ClaimGivenName),
+			LastName:      firstClaimValue(claims, bhoidc.ClaimFamilyName),
+			Groups:        bhoidc.ClaimValues(claims, provider.RoleClaim),
+		})
+	}
+}
+
+func sameSiteValue(host url.URL) http.SameSite {
+	if host.Scheme == "https" {
+		return http.SameSiteStrictMode
+	} else {
+		return http.SameSiteDefaultMode
+	}
+}
+
+// NOTE: See the SAML resource for why the Domain field of the session cookie is set explicitly for localhost
+func domainValue(host url.URL) string {
+	if strings.Contains(host.Hostname(), "localhost") {
+		return host.Hostname()
+	} else {
+		return ""
+	}
+}
+
+func (s Resource) createSessionFromClaims(request *http.Request, response http.ResponseWriter, provider model.OIDCProvider, expires time.Time, claims jwt.MapClaims) {
+	hostURL := *ctx.FromRequest(request).Host
+
+	if user, err := s.lookupOIDCUser(request, provider, claims); err != nil {
+		log.Errorf("[OIDC] Failed to lookup user for OIDC provider %s: %v", provider.Name, err)
+
+		switch err {
+		case ErrorPrincipalClaimNotFound, ErrorEmailNotVerified:
+			s.writeAPIErrorResponse(request, response, http.StatusBadRequest, "ID token does not meet the requirements for user lookup")
+		case bhsso.ErrUserNotFound, bhsso.ErrUserNotAuthorizedForProvider, bhsso.ErrNoMappedRoles:
+			s.writeAPIErrorResponse(request, response, http.StatusForbidden, "user is not allowed")
+		default:
+			s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, "session creation failure")
+		}
+	} else if sessionJWT, err := s.authenticator.CreateSession(user, provider); err != nil {
+		if locationURL := api.URLJoinPath(hostURL, api.UserDisabledPath); err == api.ErrUserDisabled {
+			response.Header().Add(headers.Location.String(), locationURL.String())
+			response.WriteHeader(http.StatusFound)
+		} else {
+			log.Errorf("[OIDC] Failed to create user session for OIDC provider %s: %v", provider.Name, err)
+			s.writeAPIErrorResponse(request, response, http.StatusInternalServerError, "session creation failure")
+		}
+	} else {
+		locationURL := api.URLJoinPath(hostURL, api.UserInterfacePath)
+
+		http.SetCookie(response, &http.Cookie{
+			Name:     api.AuthTokenCookieName,
+			Value:    sessionJWT,
+			Expires:  expires,
+			Secure:   hostURL.Scheme == "https",
+			SameSite: sameSiteValue(hostURL),
+			Path:     "/",
+			Domain:   domainValue(hostURL),
+		})
+
+		response.Header().Add(headers.Location.String(), locationURL.String())
+		response.WriteHeader(http.StatusFound)
+	}
+}
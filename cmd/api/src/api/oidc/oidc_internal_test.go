@@ -0,0 +1,355 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/src/api"
+	apimocks "github.com/specterops/bloodhound/src/api/mocks"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/auth/bhsso"
+	"github.com/specterops/bloodhound/src/config"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/serde"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResource_createSessionFromClaims(t *testing.T) {
+	const (
+		badUsername  = "bad"
+		goodUsername = "good"
+		goodJWT      = "fake"
+	)
+
+	var (
+		provider = model.OIDCProvider{
+			Name: "corp",
+			Serial: model.Serial{
+				ID: 1,
+			},
+		}
+
+		goodUser = model.User{
+			PrincipalName:  goodUsername,
+			OIDCProviderID: null.Int32From(1),
+		}
+
+		mockCtrl          = gomock.NewController(t)
+		mockDB            = dbmocks.NewMockDatabase(mockCtrl)
+		mockAuthenticator = apimocks.NewMockAuthenticator(mockCtrl)
+		resource          = Resource{
+			db:            mockDB,
+			authenticator: mockAuthenticator,
+			provisioner:   bhsso.NewProvisioner(mockDB),
+			cfg: config.Configuration{
+				RootURL: serde.MustParseURL("https://example.com"),
+			},
+			writeAPIErrorResponse: func(request *http.Request, response http.ResponseWriter, statusCode int, message string) {
+				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(statusCode, message, request), response)
+			},
+		}
+	)
+
+	defer mockCtrl.Finish()
+
+	var (
+		expires               = time.Now().UTC().Add(auth.SessionTTL)
+		response              = httptest.NewRecorder()
+		expectedCookieContent = fmt.Sprintf("token=fake; Path=/; Expires=%s; Secure; SameSite=Strict", expires.Format(http.TimeFormat))
+		claims                = jwt.MapClaims{
+			"email":          goodUsername,
+			"email_verified": true,
+		}
+	)
+
+	httpRequest, _ := http.NewRequestWithContext(context.WithValue(context.TODO(), ctx.ValueKey, &ctx.Context{Host: &resource.cfg.RootURL.URL}), http.MethodGet, "http://localhost", nil)
+
+	// Test happy path
+	mockDB.EXPECT().LookupUser(goodUsername).Return(goodUser, nil)
+	mockAuthenticator.EXPECT().CreateSession(goodUser, provider).Return(goodJWT, nil)
+
+	resource.createSessionFromClaims(httpRequest, response, provider, expires, claims)
+
+	require.Equal(t, expectedCookieContent, response.Header().Get(headers.SetCookie.String()))
+	require.Equal(t, "https://example.com/ui", response.Header().Get(headers.Location.String()))
+	require.Equal(t, http.StatusFound, response.Code)
+
+	// Change the principal claim to the bad username to assert we get a 403
+	claims["email"] = badUsername
+
+	mockDB.EXPECT().LookupUser(badUsername).Return(model.User{}, database.ErrNotFound)
+
+	response = httptest.NewRecorder()
+
+	resource.createSessionFromClaims(httpRequest, response, provider, expires, claims)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// Change the db return to a user that isn't associated with this OIDC provider
+	mockDB.EXPECT().LookupUser(badUsername).Return(model.User{OIDCProviderID: null.Int32From(2)}, nil)
+
+	response = httptest.NewRecorder()
+
+	resource.createSessionFromClaims(httpRequest, response, provider, expires, claims)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// An unverified email claim can not be used to look up the user
+	claims["email"] = goodUsername
+	claims["email_verified"] = false
+
+	response = httptest.NewRecorder()
+
+	resource.createSessionFromClaims(httpRequest, response, provider, expires, claims)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Remove the principal claim
+	delete(claims, "email")
+
+	response = httptest.NewRecorder()
+
+	resource.createSessionFromClaims(httpRequest, response, provider, expires, claims)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResource_createSessionFromClaims_RoleMappings(t *testing.T) {
+	var (
+		adminRole = model.Role{Name: "Administrator", Serial: model.Serial{ID: 1}}
+		readRole  = model.Role{Name: "Read-Only", Serial: model.Serial{ID: 3}}
+		provider  = model.OIDCProvider{
+			Name:           "corp",
+			PrincipalClaim: "preferred_username",
+			RoleClaim:      "groups",
+			RoleMappings: model.OIDCRoleMappings{
+				{OIDCProviderID: 1, ClaimValue: "sec-bh-admins", RoleID: adminRole.ID},
+			},
+			Serial: model.Serial{
+				ID: 1,
+			},
+		}
+
+		user = model.User{
+			PrincipalName:  "alice",
+			OIDCProviderID: null.Int32From(1),
+			Roles:          model.Roles{readRole},
+		}
+
+		mockCtrl          = gomock.NewController(t)
+		mockDB            = dbmocks.NewMockDatabase(mockCtrl)
+		mockAuthenticator = apimocks.NewMockAuthenticator(mockCtrl)
+		resource          = Resource{
+			db:            mockDB,
+			authenticator: mockAuthenticator,
+			provisioner:   bhsso.NewProvisioner(mockDB),
+			cfg: config.Configuration{
+				RootURL: serde.MustParseURL("https://example.com"),
+			},
+			writeAPIErrorResponse: func(request *http.Request, response http.ResponseWriter, statusCode int, message string) {
+				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(statusCode, message, request), response)
+			},
+		}
+	)
+
+	defer mockCtrl.Finish()
+
+	httpRequest, _ := http.NewRequestWithContext(context.WithValue(context.TODO(), ctx.ValueKey, &ctx.Context{Host: &resource.cfg.RootURL.URL}), http.MethodGet, "http://localhost", nil)
+
+	// A mapped claim value replaces the roles of the user
+	mockDB.EXPECT().LookupUser("alice").Return(user, nil)
+	mockDB.EXPECT().GetRoles([]int32{adminRole.ID}).Return(model.Roles{adminRole}, nil)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(updatedUser model.User) error {
+		require.Equal(t, model.Roles{adminRole}, updatedUser.Roles)
+		return nil
+	})
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "SyncSSOUserRoles", gomock.Any()).Return(nil)
+	mockAuthenticator.EXPECT().CreateSession(gomock.Any(), provider).Return("fake", nil)
+
+	response := httptest.NewRecorder()
+	resource.createSessionFromClaims(httpRequest, response, provider, time.Now().UTC().Add(auth.SessionTTL), jwt.MapClaims{
+		"preferred_username": "alice",
+		"groups":             []any{"users", "sec-bh-admins"},
+	})
+	require.Equal(t, http.StatusFound, response.Code)
+
+	// No mapped claim values deprovisions the user and denies access
+	activeSession := model.UserSession{BigSerial: model.BigSerial{ID: 5}}
+
+	mockDB.EXPECT().LookupUser("alice").Return(user, nil)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(updatedUser model.User) error {
+		require.Empty(t, updatedUser.Roles)
+		return nil
+	})
+	mockDB.EXPECT().LookupActiveSessionsByUser(gomock.Any()).Return([]model.UserSession{activeSession}, nil)
+	mockDB.EXPECT().EndUserSession(activeSession)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "DeprovisionSSOUser", gomock.Any()).Return(nil)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromClaims(httpRequest, response, provider, time.Now().UTC().Add(auth.SessionTTL), jwt.MapClaims{
+		"preferred_username": "alice",
+		"groups":             []any{"users"},
+	})
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// A user that was already deprovisioned is denied access without further changes
+	mockDB.EXPECT().LookupUser("alice").Return(model.User{PrincipalName: "alice", OIDCProviderID: null.Int32From(1)}, nil)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromClaims(httpRequest, response, provider, time.Now().UTC().Add(auth.SessionTTL), jwt.MapClaims{
+		"preferred_username": "alice",
+	})
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// Unknown users are denied access unless the provider provisions users
+	mockDB.EXPECT().LookupUser("bob").Return(model.User{}, database.ErrNotFound)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromClaims(httpRequest, response, provider, time.Now().UTC().Add(auth.SessionTTL), jwt.MapClaims{
+		"preferred_username": "bob",
+		"groups":             []any{"sec-bh-admins"},
+	})
+	require.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestResource_createSessionFromClaims_AutoProvision(t *testing.T) {
+	var (
+		adminRole = model.Role{Name: "Administrator", Serial: model.Serial{ID: 1}}
+		provider  = model.OIDCProvider{
+			Name:           "corp",
+			PrincipalClaim: "preferred_username",
+			RoleClaim:      "groups",
+			RoleMappings: model.OIDCRoleMappings{
+				{OIDCProviderID: 1, ClaimValue: "sec-bh-admins", RoleID: adminRole.ID},
+			},
+			AutoProvision: true,
+			Serial: model.Serial{
+				ID: 1,
+			},
+		}
+
+		mockCtrl          = gomock.NewController(t)
+		mockDB            = dbmocks.NewMockDatabase(mockCtrl)
+		mockAuthenticator = apimocks.NewMockAuthenticator(mockCtrl)
+		resource          = Resource{
+			db:            mockDB,
+			authenticator: mockAuthenticator,
+			provisioner:   bhsso.NewProvisioner(mockDB),
+			cfg: config.Configuration{
+				RootURL: serde.MustParseURL("https://example.com"),
+			},
+			writeAPIErrorResponse: func(request *http.Request, response http.ResponseWriter, statusCode int, message string) {
+				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(statusCode, message, request), response)
+			},
+		}
+	)
+
+	defer mockCtrl.Finish()
+
+	httpRequest, _ := http.NewRequestWithContext(context.WithValue(context.TODO(), ctx.ValueKey, &ctx.Context{Host: &resource.cfg.RootURL.URL}), http.MethodGet, "http://localhost", nil)
+
+	// Unknown users with a mapped claim value are created on first login
+	mockDB.EXPECT().LookupUser("bob").Return(model.User{}, database.ErrNotFound)
+	mockDB.EXPECT().GetRoles([]int32{adminRole.ID}).Return(model.Roles{adminRole}, nil)
+	mockDB.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(newUser model.User) (model.User, error) {
+		require.Equal(t, "bob", newUser.PrincipalName)
+		require.Equal(t, "bob@example.com", newUser.EmailAddress.String)
+		require.Equal(t, "Bob", newUser.FirstName.String)
+		require.Equal(t, null.Int32From(1), newUser.OIDCProviderID)
+		require.False(t, newUser.SAMLProviderID.Valid)
+		require.Equal(t, model.Roles{adminRole}, newUser.Roles)
+		return newUser, nil
+	})
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "ProvisionSSOUser", gomock.Any()).Return(nil)
+	mockAuthenticator.EXPECT().CreateSession(gomock.Any(), provider).Return("fake", nil)
+
+	response := httptest.NewRecorder()
+	resource.createSessionFromClaims(httpRequest, response, provider, time.Now().UTC().Add(auth.SessionTTL), jwt.MapClaims{
+		"preferred_username": "bob",
+		"email":              "bob@example.com",
+		"given_name":         "Bob",
+		"groups":             []any{"sec-bh-admins"},
+	})
+	require.Equal(t, http.StatusFound, response.Code)
+
+	// Unknown users without a mapped claim value are not created
+	mockDB.EXPECT().LookupUser("carol").Return(model.User{}, database.ErrNotFound)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromClaims(httpRequest, response, provider, time.Now().UTC().Add(auth.SessionTTL), jwt.MapClaims{
+		"preferred_username": "carol",
+		"groups":             []any{"users"},
+	})
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// Local users are never taken over by a provider
+	mockDB.EXPECT().LookupUser("admin").Return(model.User{PrincipalName: "admin"}, nil)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromClaims(httpRequest, response, provider, time.Now().UTC().Add(auth.SessionTTL), jwt.MapClaims{
+		"preferred_username": "admin",
+		"groups":             []any{"sec-bh-admins"},
+	})
+	require.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestResource_readAuthRequest(t *testing.T) {
+	var (
+		resource = Resource{}
+		expires  = time.Now().UTC().Add(authRequestTTL)
+	)
+
+	resource.cfg.Crypto.JWT.SetSigningKeyBytes([]byte("signing key"))
+
+	signedRequest, err := resource.signAuthRequest(authRequestClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   "corp",
+			ExpiresAt: expires.Unix(),
+		},
+		State:        "state",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+	})
+	require.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "https://example.com/api/v2/login/oidc/corp/callback", nil)
+	request.AddCookie(&http.Cookie{Name: authRequestCookieName, Value: signedRequest})
+
+	claims, err := resource.readAuthRequest(request, "corp")
+	require.Nil(t, err)
+	require.Equal(t, "verifier", claims.CodeVerifier)
+
+	// The request may only be redeemed at the provider it was issued for
+	_, err = resource.readAuthRequest(request, "other")
+	require.ErrorIs(t, err, ErrorAuthRequestInvalid)
+
+	// A tampered request must be rejected
+	request = httptest.NewRequest(http.MethodGet, "https://example.com/api/v2/login/oidc/corp/callback", nil)
+	request.AddCookie(&http.Cookie{Name: authRequestCookieName, Value: signedRequest + "x"})
+
+	_, err = resource.readAuthRequest(request, "corp")
+	require.ErrorIs(t, err, ErrorAuthRequestInvalid)
+}
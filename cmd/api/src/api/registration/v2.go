@@ -23,7 +23,9 @@ import (
 	"github.com/specterops/bloodhound/src/api"
 	"github.com/specterops/bloodhound/src/api/middleware"
 	"github.com/specterops/bloodhound/src/api/router"
+	"github.com/specterops/bloodhound/src/api/oidc"
 	"github.com/specterops/bloodhound/src/api/saml"
+	"github.com/specterops/bloodhound/src/api/scim"
 	v2 "github.com/specterops/bloodhound/src/api/v2"
 	authapi "github.com/specterops/bloodhound/src/api/v2/auth"
 	"github.com/specterops/bloodhound/src/auth"
@@ -41,6 +43,8 @@ func registerV2Auth(cfg config.Configuration, db database.Database, permissions
 		loginResource      = authapi.NewLoginResource(cfg, authenticator, db)
 		managementResource = authapi.NewManagementResource(cfg, db, auth.NewAuthorizer())
 		samlResource       = saml.NewSAMLRootResource(cfg, db, samlWriteAPIErrorResponse)
+		oidcResource       = oidc.NewOIDCResource(cfg, db, samlWriteAPIErrorResponse)
+		scimResource       = scim.NewSCIMResource(db)
 	)
 
 	router.With(middleware.DefaultRateLimitMiddleware,
@@ -58,6 +62,41 @@ func registerV2Auth(cfg config.Configuration, db database.Database, permissions
 		routerInst.POST("/api/v2/saml/providers", managementResource.CreateSAMLProviderMultipart).RequirePermissions(permissions.AuthManageProviders),
 		routerInst.GET(fmt.Sprintf("/api/v2/saml/providers/{%s}", api.URIPathVariableSAMLProviderID), managementResource.GetSAMLProvider).RequirePermissions(permissions.AuthManageProviders),
 		routerInst.DELETE(fmt.Sprintf("/api/v2/saml/providers/{%s}", api.URIPathVariableSAMLProviderID), managementResource.DeleteSAMLProvider).RequirePermissions(permissions.AuthManageProviders),
+		routerInst.PUT(fmt.Sprintf("/api/v2/saml/providers/{%s}/provisioning", api.URIPathVariableSAMLProviderID), managementResource.UpdateSAMLProviderProvisioning).RequirePermissions(permissions.AuthManageProviders),
+
+		// Login path prefix matcher for OIDC providers
+		routerInst.PathPrefix(fmt.Sprintf("/api/v2/login/oidc/{%s}", api.URIPathVariableOIDCProviderName), middleware.ContextMiddleware(oidcResource)),
+
+		// OIDC resources
+		routerInst.GET("/api/v2/oidc", managementResource.ListOIDCProviders).RequirePermissions(permissions.AuthManageProviders),
+		routerInst.GET("/api/v2/oidc/sso", managementResource.ListOIDCSignOnEndpoints),
+		routerInst.POST("/api/v2/oidc/providers", managementResource.CreateOIDCProvider).RequirePermissions(permissions.AuthManageProviders),
+		routerInst.GET(fmt.Sprintf("/api/v2/oidc/providers/{%s}", api.URIPathVariableOIDCProviderID), managementResource.GetOIDCProvider).RequirePermissions(permissions.AuthManageProviders),
+		routerInst.DELETE(fmt.Sprintf("/api/v2/oidc/providers/{%s}", api.URIPathVariableOIDCProviderID), managementResource.DeleteOIDCProvider).RequirePermissions(permissions.AuthManageProviders),
+		routerInst.PUT(fmt.Sprintf("/api/v2/oidc/providers/{%s}/provisioning", api.URIPathVariableOIDCProviderID), managementResource.UpdateOIDCProviderProvisioning).RequirePermissions(permissions.AuthManageProviders),
+
+		// SCIM token and group management
+		routerInst.GET("/api/v2/scim/tokens", managementResource.ListSCIMTokens).RequirePermissions(permissions.AuthManageUsers),
+		routerInst.POST("/api/v2/scim/tokens", managementResource.CreateSCIMToken).RequirePermissions(permissions.AuthManageUsers),
+		routerInst.DELETE(fmt.Sprintf("/api/v2/scim/tokens/{%s}", api.URIPathVariableSCIMTokenID), managementResource.DeleteSCIMToken).RequirePermissions(permissions.AuthManageUsers),
+		routerInst.GET("/api/v2/scim/groups", managementResource.ListSCIMGroups).RequirePermissions(permissions.AuthManageUsers),
+		routerInst.PUT(fmt.Sprintf("/api/v2/scim/groups/{%s}/role", api.URIPathVariableSCIMGroupID), managementResource.UpdateSCIMGroupRole).RequirePermissions(permissions.AuthManageUsers),
+
+		// SCIM provisioning resources, only accessible with a SCIM token
+		routerInst.GET(scim.BasePath+"/ServiceProviderConfig", scimResource.GetServiceProviderConfig).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.GET(scim.BasePath+"/ResourceTypes", scimResource.ListResourceTypes).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.GET(scim.BasePath+"/Users", scimResource.ListUsers).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.POST(scim.BasePath+"/Users", scimResource.CreateUser).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.GET(fmt.Sprintf("%s/Users/{%s}", scim.BasePath, api.URIPathVariableSCIMUserID), scimResource.GetUser).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.PUT(fmt.Sprintf("%s/Users/{%s}", scim.BasePath, api.URIPathVariableSCIMUserID), scimResource.ReplaceUser).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.PATCH(fmt.Sprintf("%s/Users/{%s}", scim.BasePath, api.URIPathVariableSCIMUserID), scimResource.PatchUser).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.DELETE(fmt.Sprintf("%s/Users/{%s}", scim.BasePath, api.URIPathVariableSCIMUserID), scimResource.DeleteUser).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.GET(scim.BasePath+"/Groups", scimResource.ListGroups).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.POST(scim.BasePath+"/Groups", scimResource.CreateGroup).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.GET(fmt.Sprintf("%s/Groups/{%s}", scim.BasePath, api.URIPathVariableSCIMGroupID), scimResource.GetGroup).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.PUT(fmt.Sprintf("%s/Groups/{%s}", scim.BasePath, api.URIPathVariableSCIMGroupID), scimResource.ReplaceGroup).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.PATCH(fmt.Sprintf("%s/Groups/{%s}", scim.BasePath, api.URIPathVariableSCIMGroupID), scimResource.PatchGroup).RequirePermissions(permissions.AuthSCIMProvisioning),
+		routerInst.DELETE(fmt.Sprintf("%s/Groups/{%s}", scim.BasePath, api.URIPathVariableSCIMGroupID), scimResource.DeleteGroup).RequirePermissions(permissions.AuthSCIMProvisioning),
 
 		// Permissions
 		routerInst.GET("/api/v2/permissions", managementResource.ListPermissions).RequirePermissions(permissions.AuthManageSelf),
@@ -66,6 +105,9 @@ func registerV2Auth(cfg config.Configuration, db database.Database, permissions
 		// Roles
 		routerInst.GET("/api/v2/roles", managementResource.ListRoles).RequirePermissions(permissions.AuthManageSelf),
 		routerInst.GET(fmt.Sprintf("/api/v2/roles/{%s}", api.URIPathVariableRoleID), managementResource.GetRole).RequirePermissions(permissions.AuthManageSelf),
+		routerInst.POST("/api/v2/roles", managementResource.CreateRole).RequirePermissions(permissions.AuthManageUsers),
+		routerInst.PUT(fmt.Sprintf("/api/v2/roles/{%s}", api.URIPathVariableRoleID), managementResource.UpdateRole).RequirePermissions(permissions.AuthManageUsers),
+		routerInst.DELETE(fmt.Sprintf("/api/v2/roles/{%s}", api.URIPathVariableRoleID), managementResource.DeleteRole).RequirePermissions(permissions.AuthManageUsers),
 
 		// User management for all BloodHound users
 		routerInst.GET("/api/v2/bloodhound-users", managementResource.ListUsers).RequirePermissions(permissions.AuthManageUsers),
@@ -107,6 +149,7 @@ func NewV2API(cfg config.Configuration, resources v2.Resources, routerInst *rout
 	routerInst.POST("/api/v2/file-upload/start", resources.StartFileUploadJob).RequirePermissions(permissions.GraphDBWrite)
 	routerInst.POST(fmt.Sprintf("/api/v2/file-upload/{%s}", v2.FileUploadJobIdPathParameterName), resources.ProcessFileUpload).RequirePermissions(permissions.GraphDBWrite)
 	routerInst.POST(fmt.Sprintf("/api/v2/file-upload/{%s}/end", v2.FileUploadJobIdPathParameterName), resources.EndFileUploadJob).RequirePermissions(permissions.GraphDBWrite)
+	routerInst.GET(fmt.Sprintf("/api/v2/file-upload/{%s}/files", v2.FileUploadJobIdPathParameterName), resources.ListFileUploadJobFiles).RequireAuth()
 
 	router.With(middleware.DefaultRateLimitMiddleware,
 		// Version API
@@ -137,15 +180,37 @@ func NewV2API(cfg config.Configuration, resources v2.Resources, routerInst *rout
 		routerInst.GET(fmt.Sprintf("/api/v2/asset-groups/{%s}/custom-selectors", api.URIPathVariableAssetGroupID), resources.GetAssetGroupCustomMemberCount).RequirePermissions(permissions.GraphDBRead),
 		routerInst.DELETE(fmt.Sprintf("/api/v2/asset-groups/{%s}", api.URIPathVariableAssetGroupID), resources.DeleteAssetGroup).RequirePermissions(permissions.GraphDBWrite),
 		routerInst.PUT(fmt.Sprintf("/api/v2/asset-groups/{%s}", api.URIPathVariableAssetGroupID), resources.UpdateAssetGroup).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.POST(fmt.Sprintf("/api/v2/asset-groups/{%s}/selectors", api.URIPathVariableAssetGroupID), resources.UpdateAssetGroupSelectors).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.PUT(fmt.Sprintf("/api/v2/asset-groups/{%s}/selectors", api.URIPathVariableAssetGroupID), resources.UpdateAssetGroupSelectors).RequirePermissions(permissions.GraphDBWrite),
 		routerInst.DELETE(fmt.Sprintf("/api/v2/asset-groups/{%s}/selectors/{%s}", api.URIPathVariableAssetGroupID, api.URIPathVariableAssetGroupSelectorID), resources.DeleteAssetGroupSelector).RequirePermissions(permissions.GraphDBWrite),
 		routerInst.GET(fmt.Sprintf("/api/v2/asset-groups/{%s}/collections", api.URIPathVariableAssetGroupID), resources.ListAssetGroupCollections).RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET(fmt.Sprintf("/api/v2/asset-groups/{%s}/members", api.URIPathVariableAssetGroupID), resources.ListAssetGroupMembers).RequirePermissions(permissions.GraphDBRead),
 
+		// Graph Snapshots API
+		routerInst.GET("/api/v2/snapshots", resources.ListGraphSnapshots).RequirePermissions(permissions.GraphDBRead),
+		routerInst.POST("/api/v2/snapshots", resources.CreateGraphSnapshot).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.GET(fmt.Sprintf("/api/v2/snapshots/{%s}", api.URIPathVariableSnapshotID), resources.GetGraphSnapshot).RequirePermissions(permissions.GraphDBRead),
+		routerInst.DELETE(fmt.Sprintf("/api/v2/snapshots/{%s}", api.URIPathVariableSnapshotID), resources.DeleteGraphSnapshot).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.GET(fmt.Sprintf("/api/v2/snapshots/{%s}/diff/{%s}", api.URIPathVariableSnapshotID, api.URIPathVariableCompareSnapshotID), resources.GetGraphSnapshotDiff).RequirePermissions(permissions.GraphDBRead),
+
+		// Post-processing Rules API
+		routerInst.GET("/api/v2/post-processing/rules", resources.ListPostProcessingRules).RequirePermissions(permissions.GraphDBRead),
+		routerInst.POST("/api/v2/post-processing/rules", resources.CreatePostProcessingRule).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.GET(fmt.Sprintf("/api/v2/post-processing/rules/{%s}", api.URIPathVariablePostProcessingRuleID), resources.GetPostProcessingRule).RequirePermissions(permissions.GraphDBRead),
+		routerInst.PUT(fmt.Sprintf("/api/v2/post-processing/rules/{%s}", api.URIPathVariablePostProcessingRuleID), resources.UpdatePostProcessingRule).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.DELETE(fmt.Sprintf("/api/v2/post-processing/rules/{%s}", api.URIPathVariablePostProcessingRuleID), resources.DeletePostProcessingRule).RequirePermissions(permissions.GraphDBWrite),
+
+		// Graph Archive API
+		routerInst.GET("/api/v2/graphs/archive", resources.ExportGraphArchive).RequirePermissions(permissions.GraphDBRead),
+		routerInst.POST("/api/v2/graphs/archive", resources.ImportGraphArchive).RequirePermissions(permissions.GraphDBWrite),
+
 		//QA API
 		routerInst.GET("/api/v2/completeness", resources.GetDatabaseCompleteness).RequirePermissions(permissions.GraphDBRead),
 
 		routerInst.GET("/api/v2/pathfinding", resources.GetPathfindingResult).Queries("start_node", "{start_node}", "end_node", "{end_node}").RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET("/api/v2/graphs/shortest-path", resources.GetShortestPath).Queries(params.StartNode.String(), params.StartNode.RouteMatcher(), params.EndNode.String(), params.EndNode.RouteMatcher()).RequirePermissions(permissions.GraphDBRead),
+		routerInst.POST("/api/v2/graphs/weighted-shortest-paths", resources.GetWeightedShortestPaths).RequirePermissions(permissions.GraphDBRead),
+		routerInst.POST("/api/v2/graphs/node-set-paths", resources.GetShortestPathsBetweenNodeSets).RequirePermissions(permissions.GraphDBRead),
 		// TODO discuss if this should be a post endpoint
 		routerInst.GET("/api/v2/graph-search", resources.GetSearchResult).RequirePermissions(permissions.GraphDBRead),
 		routerInst.POST("/api/v2/graphs/cypher", resources.CypherSearch).RequirePermissions(permissions.GraphDBRead),
@@ -193,6 +258,10 @@ func NewV2API(cfg config.Configuration, resources v2.Resources, routerInst *rout
 		routerInst.GET(fmt.Sprintf("/api/v2/domains/{%s}/controllers", api.URIPathVariableObjectID), resources.ListADEntityControllers).RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET(fmt.Sprintf("/api/v2/domains/{%s}/dc-syncers", api.URIPathVariableObjectID), resources.ListADDomainDCSyncers).RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET(fmt.Sprintf("/api/v2/domains/{%s}/linked-gpos", api.URIPathVariableObjectID), resources.ListADEntityLinkedGPOs).RequirePermissions(permissions.GraphDBRead),
+		routerInst.GET(fmt.Sprintf("/api/v2/domains/{%s}/choke-points", api.URIPathVariableObjectID), resources.ListChokePoints).RequirePermissions(permissions.GraphDBRead),
+		routerInst.GET(fmt.Sprintf("/api/v2/domains/{%s}/attack-path-findings", api.URIPathVariableObjectID), resources.ListAttackPathFindings).RequirePermissions(permissions.GraphDBRead),
+		routerInst.PUT(fmt.Sprintf("/api/v2/domains/{%s}/attack-path-findings", api.URIPathVariableObjectID), resources.UpdateAttackPathRisk).RequirePermissions(permissions.APsManageAPs),
+		routerInst.GET(fmt.Sprintf("/api/v2/domains/{%s}/attack-path-findings/details", api.URIPathVariableObjectID), resources.ListAttackPathFindingDetails).RequirePermissions(permissions.GraphDBRead),
 
 		// GPO Entity API
 		routerInst.GET(fmt.Sprintf("/api/v2/gpos/{%s}", api.URIPathVariableObjectID), resources.GetGPOEntityInfo).RequirePermissions(permissions.GraphDBRead),
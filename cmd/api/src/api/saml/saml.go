@@ -29,6 +29,7 @@ import (
 	"github.com/specterops/bloodhound/src/api"
 	"github.com/specterops/bloodhound/src/auth"
 	"github.com/specterops/bloodhound/src/auth/bhsaml"
+	"github.com/specterops/bloodhound/src/auth/bhsso"
 	"github.com/specterops/bloodhound/src/config"
 	"github.com/specterops/bloodhound/src/ctx"
 	"github.com/specterops/bloodhound/src/database"
@@ -57,10 +58,8 @@ const (
 )
 
 const (
-	ErrorUserDisabled                 = errors.Error("user disabled")
-	ErrorUserNotFound                 = errors.Error("User not found")
-	ErrorSAMLAssertion                = errors.Error("SAML assertion error")
-	ErrorUserNotAuthorizedForProvider = errors.Error("User not authorized for this provider")
+	ErrorUserDisabled  = errors.Error("user disabled")
+	ErrorSAMLAssertion = errors.Error("SAML assertion error")
 
 	APIVersion1 = 1
 	APIVersion2 = 2
@@ -124,7 +123,7 @@ func (s *RootResource) fetchInstance(organization string, ctx context.Context) (
 	if instance, hasInstance := s.getInstance(organization); !hasInstance {
 		// Create a new instance if we don't have one at the ready
 		return s.initInstance(organization, ctx)
-	} else if _, err := s.db.GetSAMLProvider(instance.serviceProvider.Config.ID); err != nil {
+	} else if provider, err := s.db.GetSAMLProvider(instance.serviceProvider.Config.ID); err != nil {
 		// In the case where the provider is no longer in the database we must clean up the existing ref and recreate it
 		if errors.Is(err, database.ErrNotFound) {
 			s.clearInstance(organization)
@@ -136,6 +135,10 @@ func (s *RootResource) fetchInstance(organization string, ctx context.Context) (
 		} else {
 			return ProviderResource{}, api.FormatDatabaseError(err)
 		}
+	} else if !provider.UpdatedAt.Equal(instance.serviceProvider.Config.UpdatedAt) {
+		// The provider was updated since the instance was cached, for example when its role mappings changed
+		s.clearInstance(organization)
+		return s.initInstance(organization, ctx)
 	} else {
 		// Instance is still valid, return it
 		return instance, nil
@@ -159,6 +162,7 @@ type ProviderResource struct {
 	cfg                   config.Configuration
 	authenticator         api.Authenticator
 	serviceProvider       bhsaml.ServiceProvider
+	provisioner           bhsso.Provisioner
 	requestTracker        samlsp.RequestTracker
 	bindingType           string
 	responseBindingType   string
@@ -171,6 +175,7 @@ func NewProviderResource(db database.Database, cfg config.Configuration, service
 		cfg:                   cfg,
 		authenticator:         api.NewAuthenticator(cfg, db, database.NewContextInitializer(db)),
 		serviceProvider:       serviceProvider,
+		provisioner:           bhsso.NewProvisioner(db),
 		requestTracker:        bhsaml.NewCookieRequestTracker(serviceProvider),
 		writeAPIErrorResponse: writeAPIErrorResponse,
 
@@ -215,6 +220,37 @@ func assertionFindString(assertion *saml.Assertion, names ...string) (string, er
 	return "", ErrAttributeNotFound
 }
 
+// assertionFindStrings returns every value of the first attribute in the assertion that matches one of the given names
+func assertionFindStrings(assertion *saml.Assertion, names ...string) []string {
+	for _, attributeStatement := range assertion.AttributeStatements {
+		for _, attribute := range attributeStatement.Attributes {
+			for _, validName := range names {
+				if attribute.Name == validName && len(attribute.Values) > 0 {
+					values := make([]string, len(attribute.Values))
+
+					for idx, value := range attribute.Values {
+						values[idx] = value.Value
+					}
+
+					return values
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// assertionFindOptionalString returns the value of the first attribute in the assertion that matches one of the given
+// names or an empty string if no such attribute exists
+func assertionFindOptionalString(assertion *saml.Assertion, names ...string) string {
+	if value, err := assertionFindString(assertion, names...); err != nil {
+		return ""
+	} else {
+		return value
+	}
+}
+
 // emailAttributeNames returns the service provider's configuration principal attribute mappings. If unset, this
 // function instead returns a default array of well-known values.
 func (s ProviderResource) emailAttributeNames() []string {
@@ -225,7 +261,7 @@ func (s ProviderResource) emailAttributeNames() []string {
 	return []string{bhsaml.ObjectIDEmail, bhsaml.XMLSOAPClaimsEmailAddress}
 }
 
-func (s ProviderResource) lookupSAMLUser(assertion *saml.Assertion) (model.User, error) {
+func (s ProviderResource) lookupSAMLUser(request *http.Request, assertion *saml.Assertion) (model.User, error) {
 	for _, attrStmt := range assertion.AttributeStatements {
 		for _, attr := range attrStmt.Attributes {
 			for _, value := range attr.Values {
@@ -239,17 +275,19 @@ func (s ProviderResource) lookupSAMLUser(assertion *saml.Assertion) (model.User,
 	if principalName, err := assertionFindString(assertion, s.emailAttributeNames()...); err != nil {
 		return model.User{}, ErrorSAMLAssertion
 	} else {
-		if user, err := s.db.LookupUser(principalName); err != nil {
-			if !errors.Is(err, database.ErrNotFound) {
-				return model.User{}, api.FormatDatabaseError(err)
-			} else {
-				return model.User{}, ErrorUserNotFound
-			}
-		} else if !user.SAMLProviderID.Valid || s.serviceProvider.Config.ID != user.SAMLProvider.ID {
-			return model.User{}, ErrorUserNotAuthorizedForProvider
-		} else {
-			return user, nil
+		var groups []string
+
+		if roleAttribute := s.serviceProvider.Config.RoleAttribute; roleAttribute != "" {
+			groups = assertionFindStrings(assertion, roleAttribute)
 		}
+
+		return s.provisioner.Provision(*ctx.FromRequest(request), s.serviceProvider.Config, bhsso.Identity{
+			PrincipalName: principalName,
+			EmailAddress:  assertionFindOptionalString(assertion, bhsaml.ObjectIDEmail, bhsaml.XMLSOAPClaimsEmailAddress),
+			FirstName:     assertionFindOptionalString(assertion, bhsaml.ObjectIDGivenName, bhsaml.XMLSOAPClaimsGivenName),
+			LastName:      assertionFindOptionalString(assertion, bhsaml.ObjectIDSurname, bhsaml.XMLSOAPClaimsSurname),
+			Groups:        groups,
+		})
 	}
 }
 
@@ -274,13 +312,13 @@ func domainValue(host url.URL) string {
 func (s ProviderResource) createSessionFromAssertion(request *http.Request, response http.ResponseWriter, expires time.Time, assertion *saml.Assertion) {
 	hostURL := *ctx.FromRequest(request).Host
 
-	if user, err := s.lookupSAMLUser(assertion); err != nil {
+	if user, err := s.lookupSAMLUser(request, assertion); err != nil {
 		log.Errorf("[SAML] Failed to lookup user for SAML provider %s: %v", s.serviceProvider.Config.Name, err)
 
 		switch err {
 		case ErrorSAMLAssertion:
 			s.writeAPIErrorResponse(request, response, http.StatusBadRequest, "session assertion does not meet the requirements for user lookup")
-		case ErrorUserNotFound, ErrorUserNotAuthorizedForProvider:
+		case bhsso.ErrUserNotFound, bhsso.ErrUserNotAuthorizedForProvider, bhsso.ErrNoMappedRoles:
 			// This is a tiny bit more descriptive for the end user without leaking any sensitive information
 			s.writeAPIErrorResponse(request, response, http.StatusForbidden, "user is not allowed")
 		default:
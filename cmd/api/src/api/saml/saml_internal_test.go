@@ -30,6 +30,7 @@ import (
 	apimocks "github.com/specterops/bloodhound/src/api/mocks"
 	"github.com/specterops/bloodhound/src/auth"
 	"github.com/specterops/bloodhound/src/auth/bhsaml"
+	"github.com/specterops/bloodhound/src/auth/bhsso"
 	"github.com/specterops/bloodhound/src/config"
 	"github.com/specterops/bloodhound/src/ctx"
 	"github.com/specterops/bloodhound/src/database"
@@ -68,6 +69,7 @@ func TestProviderResource_createSessionFromAssertion(t *testing.T) {
 		resource          = ProviderResource{
 			db:            mockDB,
 			authenticator: mockAuthenticator,
+			provisioner:   bhsso.NewProvisioner(mockDB),
 			serviceProvider: bhsaml.ServiceProvider{
 				Config: model.SAMLProvider{
 					Serial: model.Serial{
@@ -165,3 +167,129 @@ func TestProviderResource_createSessionFromAssertion(t *testing.T) {
 	resource.createSessionFromAssertion(httpRequest, response, expires, testAssertion)
 	require.Equal(t, http.StatusBadRequest, response.Code)
 }
+
+func TestProviderResource_createSessionFromAssertion_RoleMappings(t *testing.T) {
+	var (
+		adminRole = model.Role{Name: "Administrator", Serial: model.Serial{ID: 1}}
+		readRole  = model.Role{Name: "Read-Only", Serial: model.Serial{ID: 3}}
+		provider  = model.SAMLProvider{
+			Name:          "corp",
+			RoleAttribute: bhsaml.ObjectIDAffiliation,
+			RoleMappings: model.SAMLRoleMappings{
+				{SAMLProviderID: 1, AttributeValue: "sec-bh-admins", RoleID: adminRole.ID},
+			},
+			AutoProvision: true,
+			Serial: model.Serial{
+				ID: 1,
+			},
+		}
+
+		mockCtrl          = gomock.NewController(t)
+		mockDB            = dbmocks.NewMockDatabase(mockCtrl)
+		mockAuthenticator = apimocks.NewMockAuthenticator(mockCtrl)
+		resource          = ProviderResource{
+			db:            mockDB,
+			authenticator: mockAuthenticator,
+			provisioner:   bhsso.NewProvisioner(mockDB),
+			serviceProvider: bhsaml.ServiceProvider{
+				Config: provider,
+			},
+			cfg: config.Configuration{
+				RootURL: serde.MustParseURL("https://example.com"),
+			},
+			writeAPIErrorResponse: func(request *http.Request, response http.ResponseWriter, statusCode int, message string) {
+				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(statusCode, message, request), response)
+			},
+		}
+	)
+
+	defer mockCtrl.Finish()
+
+	newAssertion := func(principalName string, groups ...string) *saml.Assertion {
+		groupValues := make([]saml.AttributeValue, len(groups))
+
+		for idx, group := range groups {
+			groupValues[idx] = saml.AttributeValue{Type: bhsaml.XMLTypeString, Value: group}
+		}
+
+		return &saml.Assertion{
+			AttributeStatements: []saml.AttributeStatement{
+				{
+					Attributes: []saml.Attribute{
+						{
+							Name:   bhsaml.XMLSOAPClaimsEmailAddress,
+							Values: []saml.AttributeValue{{Type: bhsaml.XMLTypeString, Value: principalName}},
+						},
+						{
+							Name:   bhsaml.ObjectIDGivenName,
+							Values: []saml.AttributeValue{{Type: bhsaml.XMLTypeString, Value: "Alice"}},
+						},
+						{
+							Name:   bhsaml.ObjectIDAffiliation,
+							Values: groupValues,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	var (
+		expires        = time.Now().UTC().Add(auth.SessionTTL)
+		existingUser   = model.User{PrincipalName: "alice@example.com", SAMLProviderID: null.Int32From(1), Roles: model.Roles{readRole}}
+		activeSession  = model.UserSession{BigSerial: model.BigSerial{ID: 5}}
+		httpRequest, _ = http.NewRequestWithContext(context.WithValue(context.TODO(), ctx.ValueKey, &ctx.Context{Host: &resource.cfg.RootURL.URL}), http.MethodPost, "http://localhost", nil)
+	)
+
+	// Unknown users with a mapped group are created on first login
+	mockDB.EXPECT().LookupUser("alice@example.com").Return(model.User{}, database.ErrNotFound)
+	mockDB.EXPECT().GetRoles([]int32{adminRole.ID}).Return(model.Roles{adminRole}, nil)
+	mockDB.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(newUser model.User) (model.User, error) {
+		require.Equal(t, "alice@example.com", newUser.EmailAddress.String)
+		require.Equal(t, "Alice", newUser.FirstName.String)
+		require.Equal(t, null.Int32From(1), newUser.SAMLProviderID)
+		require.Equal(t, model.Roles{adminRole}, newUser.Roles)
+		return newUser, nil
+	})
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "ProvisionSSOUser", gomock.Any()).Return(nil)
+	mockAuthenticator.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return("fake", nil)
+
+	response := httptest.NewRecorder()
+	resource.createSessionFromAssertion(httpRequest, response, expires, newAssertion("alice@example.com", "users", "sec-bh-admins"))
+	require.Equal(t, http.StatusFound, response.Code)
+
+	// The roles of existing users are synchronized on every login
+	mockDB.EXPECT().LookupUser("alice@example.com").Return(existingUser, nil)
+	mockDB.EXPECT().GetRoles([]int32{adminRole.ID}).Return(model.Roles{adminRole}, nil)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(updatedUser model.User) error {
+		require.Equal(t, model.Roles{adminRole}, updatedUser.Roles)
+		return nil
+	})
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "SyncSSOUserRoles", gomock.Any()).Return(nil)
+	mockAuthenticator.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return("fake", nil)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromAssertion(httpRequest, response, expires, newAssertion("alice@example.com", "sec-bh-admins"))
+	require.Equal(t, http.StatusFound, response.Code)
+
+	// Users that are no longer members of a mapped group lose their roles and sessions
+	mockDB.EXPECT().LookupUser("alice@example.com").Return(existingUser, nil)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(updatedUser model.User) error {
+		require.Empty(t, updatedUser.Roles)
+		return nil
+	})
+	mockDB.EXPECT().LookupActiveSessionsByUser(gomock.Any()).Return([]model.UserSession{activeSession}, nil)
+	mockDB.EXPECT().EndUserSession(activeSession)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "DeprovisionSSOUser", gomock.Any()).Return(nil)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromAssertion(httpRequest, response, expires, newAssertion("alice@example.com", "users"))
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// Users of other providers are never taken over
+	mockDB.EXPECT().LookupUser("alice@example.com").Return(model.User{PrincipalName: "alice@example.com", OIDCProviderID: null.Int32From(1)}, nil)
+
+	response = httptest.NewRecorder()
+	resource.createSessionFromAssertion(httpRequest, response, expires, newAssertion("alice@example.com", "sec-bh-admins"))
+	require.Equal(t, http.StatusForbidden, response.Code)
+}
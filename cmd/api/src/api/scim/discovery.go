@@ -0,0 +1,125 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"net/http"
+
+	"github.com/specterops/bloodhound/src/api"
+)
+
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+type filterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type bulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+// ServiceProviderConfig describes the SCIM features that BloodHound supports as defined in RFC 7643 section 5
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	Patch                 supported              `json:"patch"`
+	Bulk                  bulkSupport            `json:"bulk"`
+	Filter                filterSupport          `json:"filter"`
+	ChangePassword        supported              `json:"changePassword"`
+	Sort                  supported              `json:"sort"`
+	ETag                  supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  *Meta                  `json:"meta"`
+}
+
+// ResourceType describes a resource type served by the SCIM API as defined in RFC 7643 section 6
+type ResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Endpoint    string   `json:"endpoint"`
+	Schema      string   `json:"schema"`
+	Meta        *Meta    `json:"meta"`
+}
+
+func (s Resource) GetServiceProviderConfig(response http.ResponseWriter, request *http.Request) {
+	location := api.URLJoinPath(baseURL(request), "ServiceProviderConfig")
+
+	writeResponse(response, http.StatusOK, ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProviderConfig},
+		Patch:   supported{Supported: true},
+		Filter: filterSupport{
+			Supported:  true,
+			MaxResults: MaxResults,
+		},
+		AuthenticationSchemes: []AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "SCIM Token",
+			Description: "Authentication with a BloodHound SCIM token presented as a bearer token",
+			Primary:     true,
+		}},
+		Meta: &Meta{
+			ResourceType: ResourceTypeServiceProviderConfig,
+			Location:     location.String(),
+		},
+	})
+}
+
+func (s Resource) ListResourceTypes(response http.ResponseWriter, request *http.Request) {
+	var (
+		resourceTypeURL = api.URLJoinPath(baseURL(request), "ResourceTypes")
+		userTypeURL     = api.URLJoinPath(resourceTypeURL, ResourceTypeUser)
+		groupTypeURL    = api.URLJoinPath(resourceTypeURL, ResourceTypeGroup)
+		resourceTypes   = []ResourceType{{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeUser,
+			Name:        ResourceTypeUser,
+			Description: "BloodHound user",
+			Endpoint:    "/Users",
+			Schema:      SchemaUser,
+			Meta: &Meta{
+				ResourceType: "ResourceType",
+				Location:     userTypeURL.String(),
+			},
+		}, {
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeGroup,
+			Name:        ResourceTypeGroup,
+			Description: "Group whose members are granted the role that the group is mapped to",
+			Endpoint:    "/Groups",
+			Schema:      SchemaGroup,
+			Meta: &Meta{
+				ResourceType: "ResourceType",
+				Location:     groupTypeURL.String(),
+			},
+		}}
+	)
+
+	writeResponse(response, http.StatusOK, newListResponse(resourceTypes, page{startIndex: 1, count: MaxResults}))
+}
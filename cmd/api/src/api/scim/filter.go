@@ -0,0 +1,384 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	operatorAnd = "and"
+	operatorOr  = "or"
+	operatorNot = "not"
+
+	operatorEqual          = "eq"
+	operatorNotEqual       = "ne"
+	operatorContains       = "co"
+	operatorStartsWith     = "sw"
+	operatorEndsWith       = "ew"
+	operatorPresent        = "pr"
+	operatorGreaterThan    = "gt"
+	operatorGreaterOrEqual = "ge"
+	operatorLessThan       = "lt"
+	operatorLessOrEqual    = "le"
+)
+
+// Filter is a SCIM filter expression as defined in RFC 7644 section 3.4.2.2. Attribute names and string values are
+// compared case-insensitively.
+type Filter interface {
+	matches(attributes attributes) bool
+}
+
+type logicalFilter struct {
+	operator    string
+	left, right Filter
+}
+
+func (s logicalFilter) matches(attributes attributes) bool {
+	if s.operator == operatorAnd {
+		return s.left.matches(attributes) && s.right.matches(attributes)
+	}
+
+	return s.left.matches(attributes) || s.right.matches(attributes)
+}
+
+type notFilter struct {
+	filter Filter
+}
+
+func (s notFilter) matches(attributes attributes) bool {
+	return !s.filter.matches(attributes)
+}
+
+type presentFilter struct {
+	attribute string
+}
+
+func (s presentFilter) matches(attributes attributes) bool {
+	return len(attributes[s.attribute]) > 0
+}
+
+type comparisonFilter struct {
+	attribute string
+	operator  string
+	value     string
+}
+
+func (s comparisonFilter) matches(attributes attributes) bool {
+	if s.operator == operatorNotEqual {
+		return !comparisonFilter{attribute: s.attribute, operator: operatorEqual, value: s.value}.matches(attributes)
+	}
+
+	for _, value := range attributes[s.attribute] {
+		if compare(strings.ToLower(value), s.operator, s.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compare(value, operator, operand string) bool {
+	switch operator {
+	case operatorEqual:
+		return value == operand
+	case operatorContains:
+		return strings.Contains(value, operand)
+	case operatorStartsWith:
+		return strings.HasPrefix(value, operand)
+	case operatorEndsWith:
+		return strings.HasSuffix(value, operand)
+	case operatorGreaterThan:
+		return value > operand
+	case operatorGreaterOrEqual:
+		return value >= operand
+	case operatorLessThan:
+		return value < operand
+	case operatorLessOrEqual:
+		return value <= operand
+	default:
+		return false
+	}
+}
+
+// equalityOperand returns the attribute and value of a filter that is a single equality comparison. PATCH operations
+// use this to create the value that a value filter targets when it does not exist yet.
+func equalityOperand(filter Filter) (string, string, bool) {
+	if comparison, isComparison := filter.(comparisonFilter); isComparison && comparison.operator == operatorEqual {
+		return comparison.attribute, comparison.value, true
+	}
+
+	return "", "", false
+}
+
+// normalizeAttributePath lower cases the attribute path and removes the schema URN that fully qualified attribute
+// paths are prefixed with
+func normalizeAttributePath(path string) string {
+	path = strings.ToLower(path)
+
+	if strings.HasPrefix(path, "urn:") {
+		path = path[strings.LastIndex(path, ":")+1:]
+	}
+
+	return path
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+
+	for cursor := 0; cursor < len(expression); {
+		switch next := expression[cursor]; {
+		case next == ' ' || next == '\t':
+			cursor++
+
+		case next == '(':
+			tokens = append(tokens, token{kind: tokenOpen, value: "("})
+			cursor++
+
+		case next == ')':
+			tokens = append(tokens, token{kind: tokenClose, value: ")"})
+			cursor++
+
+		case next == '"':
+			end := cursor + 1
+
+			for ; end < len(expression) && expression[end] != '"'; end++ {
+				if expression[end] == '\\' {
+					end++
+				}
+			}
+
+			var value string
+
+			if end >= len(expression) {
+				return nil, newError(ErrorTypeInvalidFilter, "unterminated string in filter")
+			} else if err := json.Unmarshal([]byte(expression[cursor:end+1]), &value); err != nil {
+				return nil, newError(ErrorTypeInvalidFilter, "invalid string in filter: %v", err)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: value})
+			cursor = end + 1
+
+		case next == '[' || next == ']':
+			return nil, newError(ErrorTypeInvalidFilter, "value filters are not supported in filter expressions")
+
+		default:
+			end := cursor
+
+			for end < len(expression) && !strings.ContainsRune(" \t()\"[]", rune(expression[end])) {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, value: expression[cursor:end]})
+			cursor = end
+		}
+	}
+
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []token
+	cursor int
+}
+
+// ParseFilter parses the SCIM filter expression
+func ParseFilter(expression string) (Filter, error) {
+	if tokens, err := tokenize(expression); err != nil {
+		return nil, err
+	} else if len(tokens) == 0 {
+		return nil, newError(ErrorTypeInvalidFilter, "filter is empty")
+	} else {
+		parser := filterParser{tokens: tokens}
+
+		if filter, err := parser.parseOr(); err != nil {
+			return nil, err
+		} else if parser.cursor < len(parser.tokens) {
+			return nil, newError(ErrorTypeInvalidFilter, "unexpected %q in filter", parser.tokens[parser.cursor].value)
+		} else {
+			return filter, nil
+		}
+	}
+}
+
+func (s *filterParser) peek() (token, bool) {
+	if s.cursor < len(s.tokens) {
+		return s.tokens[s.cursor], true
+	}
+
+	return token{}, false
+}
+
+func (s *filterParser) next() (token, error) {
+	if next, hasNext := s.peek(); !hasNext {
+		return token{}, newError(ErrorTypeInvalidFilter, "unexpected end of filter")
+	} else {
+		s.cursor++
+		return next, nil
+	}
+}
+
+func (s *filterParser) nextIsKeyword(keyword string) bool {
+	next, hasNext := s.peek()
+	return hasNext && next.kind == tokenWord && strings.EqualFold(next.value, keyword)
+}
+
+func (s *filterParser) parseOr() (Filter, error) {
+	if left, err := s.parseAnd(); err != nil {
+		return nil, err
+	} else {
+		for s.nextIsKeyword(operatorOr) {
+			s.cursor++
+
+			if right, err := s.parseAnd(); err != nil {
+				return nil, err
+			} else {
+				left = logicalFilter{operator: operatorOr, left: left, right: right}
+			}
+		}
+
+		return left, nil
+	}
+}
+
+func (s *filterParser) parseAnd() (Filter, error) {
+	if left, err := s.parseFactor(); err != nil {
+		return nil, err
+	} else {
+		for s.nextIsKeyword(operatorAnd) {
+			s.cursor++
+
+			if right, err := s.parseFactor(); err != nil {
+				return nil, err
+			} else {
+				left = logicalFilter{operator: operatorAnd, left: left, right: right}
+			}
+		}
+
+		return left, nil
+	}
+}
+
+func (s *filterParser) parseFactor() (Filter, error) {
+	if s.nextIsKeyword(operatorNot) {
+		s.cursor++
+
+		if filter, err := s.parseGroup(); err != nil {
+			return nil, err
+		} else {
+			return notFilter{filter: filter}, nil
+		}
+	} else if next, hasNext := s.peek(); hasNext && next.kind == tokenOpen {
+		return s.parseGroup()
+	} else {
+		return s.parseComparison()
+	}
+}
+
+func (s *filterParser) parseGroup() (Filter, error) {
+	if open, err := s.next(); err != nil {
+		return nil, err
+	} else if open.kind != tokenOpen {
+		return nil, newError(ErrorTypeInvalidFilter, "expected ( but found %q", open.value)
+	} else if filter, err := s.parseOr(); err != nil {
+		return nil, err
+	} else if closing, err := s.next(); err != nil {
+		return nil, err
+	} else if closing.kind != tokenClose {
+		return nil, newError(ErrorTypeInvalidFilter, "expected ) but found %q", closing.value)
+	} else {
+		return filter, nil
+	}
+}
+
+func (s *filterParser) parseComparison() (Filter, error) {
+	if attribute, err := s.next(); err != nil {
+		return nil, err
+	} else if attribute.kind != tokenWord {
+		return nil, newError(ErrorTypeInvalidFilter, "expected an attribute but found %q", attribute.value)
+	} else if operator, err := s.next(); err != nil {
+		return nil, err
+	} else if operator.kind != tokenWord {
+		return nil, newError(ErrorTypeInvalidFilter, "expected an operator but found %q", operator.value)
+	} else {
+		attributePath := normalizeAttributePath(attribute.value)
+
+		switch normalizedOperator := strings.ToLower(operator.value); normalizedOperator {
+		case operatorPresent:
+			return presentFilter{attribute: attributePath}, nil
+
+		case operatorEqual, operatorNotEqual, operatorContains, operatorStartsWith, operatorEndsWith,
+			operatorGreaterThan, operatorGreaterOrEqual, operatorLessThan, operatorLessOrEqual:
+			if value, err := s.next(); err != nil {
+				return nil, err
+			} else if value.kind != tokenString && value.kind != tokenWord {
+				return nil, newError(ErrorTypeInvalidFilter, "expected a value but found %q", value.value)
+			} else {
+				return comparisonFilter{
+					attribute: attributePath,
+					operator:  normalizedOperator,
+					value:     strings.ToLower(value.value),
+				}, nil
+			}
+
+		default:
+			return nil, newError(ErrorTypeInvalidFilter, "unsupported operator %q", operator.value)
+		}
+	}
+}
+
+// parseQueryFilter parses the filter query parameter of a list request. A nil filter matches all resources.
+func parseQueryFilter(request *http.Request) (Filter, error) {
+	if expression := request.URL.Query().Get("filter"); expression == "" {
+		return nil, nil
+	} else {
+		return ParseFilter(expression)
+	}
+}
+
+// filterResources returns the resources that match the filter
+func filterResources[T interface{ attributes() attributes }](resources []T, filter Filter) []T {
+	if filter == nil {
+		return resources
+	}
+
+	matched := make([]T, 0, len(resources))
+
+	for _, resource := range resources {
+		if filter.matches(resource.attributes()) {
+			matched = append(matched, resource)
+		}
+	}
+
+	return matched
+}
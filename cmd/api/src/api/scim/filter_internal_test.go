@@ -0,0 +1,245 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	var (
+		active = true
+		user   = User{
+			ID:          "8f7b4f2e-59a4-4a39-9d57-2f4a2c1f3f7a",
+			UserName:    "Bjensen@Example.com",
+			DisplayName: "Barbara Jensen",
+			Name: &Name{
+				GivenName:  "Barbara",
+				FamilyName: "Jensen",
+			},
+			Emails: []Email{{
+				Value:   "bjensen@example.com",
+				Type:    emailTypeWork,
+				Primary: true,
+			}},
+			Active: &active,
+		}
+
+		testCases = []struct {
+			filter  string
+			matches bool
+		}{
+			{filter: `userName eq "bjensen@example.com"`, matches: true},
+			{filter: `USERNAME EQ "BJENSEN@EXAMPLE.COM"`, matches: true},
+			{filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "bjensen@example.com"`, matches: true},
+			{filter: `userName ne "bjensen@example.com"`, matches: false},
+			{filter: `userName sw "bjensen"`, matches: true},
+			{filter: `userName ew "example.org"`, matches: false},
+			{filter: `displayName co "bar"`, matches: true},
+			{filter: `name.familyName eq "Jensen" and active eq true`, matches: true},
+			{filter: `name.familyName eq "Smith" or emails eq "bjensen@example.com"`, matches: true},
+			{filter: `emails.type eq "work" and not (active eq false)`, matches: true},
+			{filter: `not (userName pr)`, matches: false},
+			{filter: `externalId pr`, matches: false},
+			{filter: `userName gt "a" and userName lt "c"`, matches: true},
+		}
+	)
+
+	for _, testCase := range testCases {
+		filter, err := ParseFilter(testCase.filter)
+
+		require.Nil(t, err, testCase.filter)
+		require.Equal(t, testCase.matches, filter.matches(user.attributes()), testCase.filter)
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expression := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName eq "unterminated`,
+		`userName xx "value"`,
+		`(userName eq "value"`,
+		`userName eq "value" and`,
+		`emails[type eq "work"]`,
+	} {
+		_, err := ParseFilter(expression)
+
+		var scimError Error
+		require.ErrorAs(t, err, &scimError, expression)
+		require.Equal(t, ErrorTypeInvalidFilter, scimError.SCIMType, expression)
+	}
+}
+
+func TestFilterResources(t *testing.T) {
+	groups := []Group{{DisplayName: "Admins"}, {DisplayName: "Readers"}}
+
+	filter, err := ParseFilter(`displayName eq "readers"`)
+	require.Nil(t, err)
+
+	require.Equal(t, groups, filterResources(groups, nil))
+	require.Equal(t, []Group{{DisplayName: "Readers"}}, filterResources(groups, filter))
+}
+
+func TestUser_Patch(t *testing.T) {
+	var (
+		user = User{
+			UserName: "bjensen@example.com",
+			Emails: []Email{{
+				Value:   "bjensen@example.com",
+				Type:    emailTypeWork,
+				Primary: true,
+			}},
+		}
+
+		request = PatchRequest{
+			Operations: []PatchOperation{{
+				Op:    "Replace",
+				Value: json.RawMessage(`{"active": "False", "name": {"givenName": "Babs"}, "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department": "Sales"}`),
+			}, {
+				Op:    "replace",
+				Path:  `emails[type eq "work"].value`,
+				Value: json.RawMessage(`"babs@example.com"`),
+			}, {
+				Op:    "add",
+				Path:  `emails[type eq "home"].value`,
+				Value: json.RawMessage(`"babs@example.org"`),
+			}, {
+				Op:    "replace",
+				Path:  "name.familyName",
+				Value: json.RawMessage(`"Jensen"`),
+			}},
+		}
+	)
+
+	require.Nil(t, request.forEachOperation(user.patch))
+	require.NotNil(t, user.Active)
+	require.False(t, *user.Active)
+	require.Equal(t, &Name{GivenName: "Babs", FamilyName: "Jensen"}, user.Name)
+	require.Equal(t, []Email{{
+		Value:   "babs@example.com",
+		Type:    emailTypeWork,
+		Primary: true,
+	}, {
+		Value: "babs@example.org",
+		Type:  "home",
+	}}, user.Emails)
+
+	request = PatchRequest{
+		Operations: []PatchOperation{{
+			Op:   "remove",
+			Path: `emails[type eq "home"]`,
+		}},
+	}
+
+	require.Nil(t, request.forEachOperation(user.patch))
+	require.Len(t, user.Emails, 1)
+
+	var scimError Error
+
+	request = PatchRequest{
+		Operations: []PatchOperation{{
+			Op:   "remove",
+			Path: "userName",
+		}},
+	}
+
+	require.ErrorAs(t, request.forEachOperation(user.patch), &scimError)
+	require.Equal(t, ErrorTypeMutability, scimError.SCIMType)
+
+	request = PatchRequest{
+		Operations: []PatchOperation{{
+			Op: "remove",
+		}},
+	}
+
+	require.ErrorAs(t, request.forEachOperation(user.patch), &scimError)
+	require.Equal(t, ErrorTypeNoTarget, scimError.SCIMType)
+
+	request = PatchRequest{
+		Operations: []PatchOperation{{
+			Op:    "move",
+			Path:  "userName",
+			Value: json.RawMessage(`"babs@example.com"`),
+		}},
+	}
+
+	require.ErrorAs(t, request.forEachOperation(user.patch), &scimError)
+	require.Equal(t, ErrorTypeInvalidSyntax, scimError.SCIMType)
+}
+
+func TestGroup_Patch(t *testing.T) {
+	const (
+		firstMemberID  = "0b4a5b3e-5f1a-4b7b-8d39-1f4b55b3c9d1"
+		secondMemberID = "5d3f1e2a-7c44-4d5e-9a4f-6b2e8f0c1a7b"
+		thirdMemberID  = "c1e9d2f4-3b6a-4e8d-a7f5-9d0b2c4e6f81"
+	)
+
+	var (
+		group = Group{
+			DisplayName: "Admins",
+			Members:     []Reference{{Value: firstMemberID}, {Value: secondMemberID}},
+		}
+
+		request = PatchRequest{
+			Operations: []PatchOperation{{
+				Op:    "add",
+				Path:  "members",
+				Value: json.RawMessage(`[{"value": "` + secondMemberID + `"}, {"value": "` + thirdMemberID + `"}]`),
+			}, {
+				Op:   "remove",
+				Path: `members[value eq "` + firstMemberID + `"]`,
+			}, {
+				Op:    "replace",
+				Value: json.RawMessage(`{"displayName": "Administrators", "externalId": "admins"}`),
+			}},
+		}
+	)
+
+	require.Nil(t, request.forEachOperation(group.patch))
+	require.Equal(t, Group{
+		DisplayName: "Administrators",
+		ExternalID:  "admins",
+		Members:     []Reference{{Value: secondMemberID}, {Value: thirdMemberID}},
+	}, group)
+
+	// Some clients send the members to remove as the operation value
+	request = PatchRequest{
+		Operations: []PatchOperation{{
+			Op:    "remove",
+			Path:  "members",
+			Value: json.RawMessage(`[{"value": "` + thirdMemberID + `"}]`),
+		}},
+	}
+
+	require.Nil(t, request.forEachOperation(group.patch))
+	require.Equal(t, []Reference{{Value: secondMemberID}}, group.Members)
+
+	request = PatchRequest{
+		Operations: []PatchOperation{{
+			Op:   "remove",
+			Path: "members",
+		}},
+	}
+
+	require.Nil(t, request.forEachOperation(group.patch))
+	require.Empty(t, group.Members)
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/src/auth/bhscim"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// excludesMembers returns true if the request asks for groups without their members, which identity governance
+// platforms commonly do to avoid transferring the members of large groups
+func excludesMembers(request *http.Request) bool {
+	for _, attribute := range strings.Split(request.URL.Query().Get("excludedAttributes"), ",") {
+		if normalizeAttributePath(strings.TrimSpace(attribute)) == "members" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Resource) ListGroups(response http.ResponseWriter, request *http.Request) {
+	if filter, err := parseQueryFilter(request); err != nil {
+		writeError(response, err)
+	} else if requestPage, err := parsePage(request); err != nil {
+		writeError(response, err)
+	} else if groups, err := s.db.GetAllSCIMGroups(); err != nil {
+		writeError(response, err)
+	} else {
+		scimGroups := make([]Group, len(groups))
+
+		for idx, group := range groups {
+			scimGroups[idx] = newGroup(baseURL(request), group)
+		}
+
+		scimGroups = filterResources(scimGroups, filter)
+
+		if excludesMembers(request) {
+			for idx := range scimGroups {
+				scimGroups[idx].Members = nil
+			}
+		}
+
+		writeResponse(response, http.StatusOK, newListResponse(scimGroups, requestPage))
+	}
+}
+
+func (s Resource) GetGroup(response http.ResponseWriter, request *http.Request) {
+	if groupID, err := parseGroupID(request); err != nil {
+		writeError(response, err)
+	} else if group, err := s.db.GetSCIMGroup(groupID); err != nil {
+		writeError(response, err)
+	} else {
+		writeGroup(response, request, http.StatusOK, group)
+	}
+}
+
+func writeGroup(response http.ResponseWriter, request *http.Request, statusCode int, group model.SCIMGroup) {
+	scimGroup := newGroup(baseURL(request), group)
+
+	if excludesMembers(request) {
+		scimGroup.Members = nil
+	}
+
+	response.Header().Set(headers.Location.String(), scimGroup.Meta.Location)
+	writeResponse(response, statusCode, scimGroup)
+}
+
+// ensureUniqueDisplayName returns a uniqueness error if the display name of the group is taken by another group
+func ensureUniqueDisplayName(groups model.SCIMGroups, group model.SCIMGroup) error {
+	for _, existingGroup := range groups {
+		if existingGroup.ID != group.ID && strings.EqualFold(existingGroup.DisplayName, group.DisplayName) {
+			return newStatusError(http.StatusConflict, ErrorTypeUniqueness, "displayName is already taken")
+		}
+	}
+
+	return nil
+}
+
+// ensureMembersExist returns an invalid value error if a member of the group is not a BloodHound user
+func (s Resource) ensureMembersExist(group model.SCIMGroup) error {
+	if len(group.Members) == 0 {
+		return nil
+	} else if users, err := s.db.GetAllUsers("", model.SQLFilter{}); err != nil {
+		return err
+	} else {
+		userIDs := make(map[uuid.UUID]struct{}, len(users))
+
+		for _, user := range users {
+			userIDs[user.ID] = struct{}{}
+		}
+
+		for _, member := range group.Members {
+			if _, exists := userIDs[member.UserID]; !exists {
+				return newError(ErrorTypeInvalidValue, "member %s is not a user", member.UserID)
+			}
+		}
+
+		return nil
+	}
+}
+
+// CreateGroup creates a SCIM group. Groups that are named after a role are mapped to the role; other groups may be
+// mapped to a role through the SCIM group management API.
+func (s Resource) CreateGroup(response http.ResponseWriter, request *http.Request) {
+	var (
+		scimGroup     Group
+		groupTemplate model.SCIMGroup
+	)
+
+	if scimToken, err := s.requestToken(request); err != nil {
+		writeError(response, err)
+	} else if err := readPayload(request, &scimGroup); err != nil {
+		writeError(response, err)
+	} else if err := scimGroup.apply(&groupTemplate); err != nil {
+		writeError(response, err)
+	} else if groups, err := s.db.GetAllSCIMGroups(); err != nil {
+		writeError(response, err)
+	} else if err := ensureUniqueDisplayName(groups, groupTemplate); err != nil {
+		writeError(response, err)
+	} else if err := s.ensureMembersExist(groupTemplate); err != nil {
+		writeError(response, err)
+	} else if roles, err := s.db.GetRolesByName([]string{groupTemplate.DisplayName}); err != nil {
+		writeError(response, err)
+	} else {
+		if len(roles) > 0 {
+			groupTemplate.RoleID = null.Int32From(roles[0].ID)
+		}
+
+		if err := s.auditLog(request, scimToken, "CreateSCIMGroup", groupTemplate.AuditData().MergeLeft(model.AuditData{"members": groupTemplate.Members.UserIDs()})); err != nil {
+			writeError(response, err)
+		} else if newGroup, err := s.db.CreateSCIMGroup(groupTemplate); err != nil {
+			writeError(response, err)
+		} else if err := s.roleSynchronizer.SyncRoles(*ctx.FromRequest(request), bhscim.AffectedUserIDs(model.SCIMGroup{}, newGroup)); err != nil {
+			writeError(response, err)
+		} else {
+			writeGroup(response, request, http.StatusCreated, newGroup)
+		}
+	}
+}
+
+func (s Resource) ReplaceGroup(response http.ResponseWriter, request *http.Request) {
+	var scimGroup Group
+
+	if groupID, err := parseGroupID(request); err != nil {
+		writeError(response, err)
+	} else if group, err := s.db.GetSCIMGroup(groupID); err != nil {
+		writeError(response, err)
+	} else if err := readPayload(request, &scimGroup); err != nil {
+		writeError(response, err)
+	} else {
+		s.updateGroup(response, request, group, scimGroup)
+	}
+}
+
+func (s Resource) PatchGroup(response http.ResponseWriter, request *http.Request) {
+	var patchRequest PatchRequest
+
+	if groupID, err := parseGroupID(request); err != nil {
+		writeError(response, err)
+	} else if group, err := s.db.GetSCIMGroup(groupID); err != nil {
+		writeError(response, err)
+	} else if err := readPayload(request, &patchRequest); err != nil {
+		writeError(response, err)
+	} else {
+		scimGroup := newGroup(baseURL(request), group)
+
+		if err := patchRequest.forEachOperation(scimGroup.patch); err != nil {
+			writeError(response, err)
+		} else {
+			s.updateGroup(response, request, group, scimGroup)
+		}
+	}
+}
+
+// updateGroup writes the SCIM group to the group and synchronizes the roles of the users whose membership of a mapped
+// group changed
+func (s Resource) updateGroup(response http.ResponseWriter, request *http.Request, group model.SCIMGroup, scimGroup Group) {
+	updatedGroup := group
+
+	if scimToken, err := s.requestToken(request); err != nil {
+		writeError(response, err)
+	} else if err := scimGroup.apply(&updatedGroup); err != nil {
+		writeError(response, err)
+	} else if groups, err := s.db.GetAllSCIMGroups(); err != nil {
+		writeError(response, err)
+	} else if err := ensureUniqueDisplayName(groups, updatedGroup); err != nil {
+		writeError(response, err)
+	} else if err := s.ensureMembersExist(updatedGroup); err != nil {
+		writeError(response, err)
+	} else {
+		auditData := updatedGroup.AuditData().MergeLeft(model.AuditData{
+			"members_added":   updatedGroup.Members.Difference(group.Members),
+			"members_removed": group.Members.Difference(updatedGroup.Members),
+		})
+
+		if err := s.auditLog(request, scimToken, "UpdateSCIMGroup", auditData); err != nil {
+			writeError(response, err)
+		} else if err := s.db.UpdateSCIMGroup(updatedGroup); err != nil {
+			writeError(response, err)
+		} else if err := s.roleSynchronizer.SyncRoles(*ctx.FromRequest(request), bhscim.AffectedUserIDs(group, updatedGroup)); err != nil {
+			writeError(response, err)
+		} else {
+			writeGroup(response, request, http.StatusOK, updatedGroup)
+		}
+	}
+}
+
+func (s Resource) DeleteGroup(response http.ResponseWriter, request *http.Request) {
+	if scimToken, err := s.requestToken(request); err != nil {
+		writeError(response, err)
+	} else if groupID, err := parseGroupID(request); err != nil {
+		writeError(response, err)
+	} else if group, err := s.db.GetSCIMGroup(groupID); err != nil {
+		writeError(response, err)
+	} else if err := s.auditLog(request, scimToken, "DeleteSCIMGroup", group); err != nil {
+		writeError(response, err)
+	} else if err := s.db.DeleteSCIMGroup(group); err != nil {
+		writeError(response, err)
+	} else if err := s.roleSynchronizer.SyncRoles(*ctx.FromRequest(request), bhscim.AffectedUserIDs(group, model.SCIMGroup{})); err != nil {
+		writeError(response, err)
+	} else {
+		response.WriteHeader(http.StatusNoContent)
+	}
+}
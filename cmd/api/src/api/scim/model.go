@@ -0,0 +1,355 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	ResourceTypeUser                  = "User"
+	ResourceTypeGroup                 = "Group"
+	ResourceTypeServiceProviderConfig = "ServiceProviderConfig"
+
+	emailTypeWork = "work"
+)
+
+// attributes holds the values of the attributes of a resource, or of a value of a multi-valued complex attribute, by
+// their lower case attribute path. Filters are evaluated against these values.
+type attributes map[string][]string
+
+func (s attributes) add(attribute string, values ...string) {
+	for _, value := range values {
+		if value != "" {
+			s[attribute] = append(s[attribute], value)
+		}
+	}
+}
+
+// addComplex adds the values of a multi-valued complex attribute. Filtering on the attribute itself matches against
+// the value sub-attribute of its values.
+func (s attributes) addComplex(attribute string, values []attributes) {
+	for _, value := range values {
+		for subAttribute, subValues := range value {
+			s.add(attribute+"."+subAttribute, subValues...)
+		}
+
+		s.add(attribute, value["value"]...)
+	}
+}
+
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location"`
+}
+
+func newMeta(resourceType string, basic model.Basic, location url.URL) *Meta {
+	var (
+		created      = basic.CreatedAt.UTC()
+		lastModified = basic.UpdatedAt.UTC()
+	)
+
+	return &Meta{
+		ResourceType: resourceType,
+		Created:      &created,
+		LastModified: &lastModified,
+		Location:     location.String(),
+	}
+}
+
+func (s *Meta) attributes(values attributes) {
+	if s != nil {
+		values.add("meta.resourcetype", s.ResourceType)
+		values.add("meta.location", s.Location)
+
+		if s.Created != nil {
+			values.add("meta.created", s.Created.Format(time.RFC3339))
+		}
+
+		if s.LastModified != nil {
+			values.add("meta.lastmodified", s.LastModified.Format(time.RFC3339))
+		}
+	}
+}
+
+// Reference is a value of the members attribute of a group or of the groups attribute of a user
+type Reference struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+func (s Reference) attributes() attributes {
+	values := attributes{}
+	values.add("value", s.Value)
+	values.add("$ref", s.Ref)
+	values.add("display", s.Display)
+
+	return values
+}
+
+func referenceAttributes(references []Reference) []attributes {
+	values := make([]attributes, len(references))
+
+	for idx, reference := range references {
+		values[idx] = reference.attributes()
+	}
+
+	return values
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+func (s Email) attributes() attributes {
+	values := attributes{}
+	values.add("value", s.Value)
+	values.add("type", s.Type)
+	values.add("primary", strconv.FormatBool(s.Primary))
+
+	return values
+}
+
+// User is the SCIM representation of a BloodHound user as defined in RFC 7643 section 4.1. The userName of a SCIM
+// user is the principal name of the BloodHound user. BloodHound users hold a single email address.
+type User struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *Name       `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []Email     `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Groups      []Reference `json:"groups,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+func newUser(baseURL url.URL, user model.User, groups model.SCIMGroups) User {
+	var (
+		active   = !user.IsDisabled
+		scimUser = User{
+			Schemas:     []string{SchemaUser},
+			ID:          user.ID.String(),
+			UserName:    user.PrincipalName,
+			DisplayName: user.PrincipalName,
+			Active:      &active,
+			Meta:        newMeta(ResourceTypeUser, user.Basic, api.URLJoinPath(baseURL, "Users", user.ID.String())),
+		}
+	)
+
+	if user.FirstName.Valid || user.LastName.Valid {
+		scimUser.Name = &Name{
+			Formatted:  strings.TrimSpace(user.FirstName.ValueOrZero() + " " + user.LastName.ValueOrZero()),
+			GivenName:  user.FirstName.ValueOrZero(),
+			FamilyName: user.LastName.ValueOrZero(),
+		}
+
+		scimUser.DisplayName = scimUser.Name.Formatted
+	}
+
+	if user.EmailAddress.Valid && user.EmailAddress.String != "" {
+		scimUser.Emails = []Email{{
+			Value:   user.EmailAddress.String,
+			Type:    emailTypeWork,
+			Primary: true,
+		}}
+	}
+
+	for _, group := range groups.MemberOf(user.ID) {
+		var (
+			groupID  = strconv.FormatInt(int64(group.ID), 10)
+			groupURL = api.URLJoinPath(baseURL, "Groups", groupID)
+		)
+
+		scimUser.Groups = append(scimUser.Groups, Reference{
+			Value:   groupID,
+			Ref:     groupURL.String(),
+			Display: group.DisplayName,
+		})
+	}
+
+	return scimUser
+}
+
+// primaryEmail returns the primary email address of the user or its first email address if none is marked primary
+func (s User) primaryEmail() string {
+	for _, email := range s.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+
+	if len(s.Emails) > 0 {
+		return s.Emails[0].Value
+	}
+
+	return ""
+}
+
+func (s User) attributes() attributes {
+	values := attributes{}
+	values.add("id", s.ID)
+	values.add("username", s.UserName)
+	values.add("displayname", s.DisplayName)
+
+	if s.Name != nil {
+		values.add("name.formatted", s.Name.Formatted)
+		values.add("name.givenname", s.Name.GivenName)
+		values.add("name.familyname", s.Name.FamilyName)
+	}
+
+	if s.Active != nil {
+		values.add("active", strconv.FormatBool(*s.Active))
+	}
+
+	emails := make([]attributes, len(s.Emails))
+	for idx, email := range s.Emails {
+		emails[idx] = email.attributes()
+	}
+
+	values.addComplex("emails", emails)
+	values.addComplex("groups", referenceAttributes(s.Groups))
+	s.Meta.attributes(values)
+
+	return values
+}
+
+// apply writes the attributes of the SCIM user to the BloodHound user. The active attribute is left unchanged when it
+// is omitted.
+func (s User) apply(user *model.User) error {
+	if strings.TrimSpace(s.UserName) == "" {
+		return newError(ErrorTypeInvalidValue, "userName is required")
+	}
+
+	user.PrincipalName = s.UserName
+	user.FirstName = null.String{}
+	user.LastName = null.String{}
+	user.EmailAddress = null.String{}
+
+	if s.Name != nil {
+		user.FirstName = optionalString(s.Name.GivenName)
+		user.LastName = optionalString(s.Name.FamilyName)
+	}
+
+	if email := s.primaryEmail(); email != "" {
+		user.EmailAddress = null.StringFrom(email)
+	}
+
+	if s.Active != nil {
+		user.IsDisabled = !*s.Active
+	}
+
+	return nil
+}
+
+func optionalString(value string) null.String {
+	if value == "" {
+		return null.String{}
+	}
+
+	return null.StringFrom(value)
+}
+
+// Group is the SCIM representation of a SCIM group as defined in RFC 7643 section 4.2. Members are referenced by the
+// ID of their BloodHound user.
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+func newGroup(baseURL url.URL, group model.SCIMGroup) Group {
+	var (
+		groupID   = strconv.FormatInt(int64(group.ID), 10)
+		scimGroup = Group{
+			Schemas:     []string{SchemaGroup},
+			ID:          groupID,
+			ExternalID:  group.ExternalID,
+			DisplayName: group.DisplayName,
+			Meta:        newMeta(ResourceTypeGroup, group.Basic, api.URLJoinPath(baseURL, "Groups", groupID)),
+		}
+	)
+
+	for _, member := range group.Members {
+		memberURL := api.URLJoinPath(baseURL, "Users", member.UserID.String())
+
+		scimGroup.Members = append(scimGroup.Members, Reference{
+			Value: member.UserID.String(),
+			Ref:   memberURL.String(),
+		})
+	}
+
+	return scimGroup
+}
+
+func (s Group) attributes() attributes {
+	values := attributes{}
+	values.add("id", s.ID)
+	values.add("externalid", s.ExternalID)
+	values.add("displayname", s.DisplayName)
+	values.addComplex("members", referenceAttributes(s.Members))
+	s.Meta.attributes(values)
+
+	return values
+}
+
+// apply writes the attributes of the SCIM group to the BloodHound SCIM group. Whether the members exist is validated
+// separately.
+func (s Group) apply(group *model.SCIMGroup) error {
+	if strings.TrimSpace(s.DisplayName) == "" {
+		return newError(ErrorTypeInvalidValue, "displayName is required")
+	}
+
+	group.DisplayName = s.DisplayName
+	group.ExternalID = s.ExternalID
+	group.Members = model.SCIMGroupMembers{}
+
+	for _, member := range s.Members {
+		if userID, err := uuid.FromString(member.Value); err != nil {
+			return newError(ErrorTypeInvalidValue, "member %q is not a valid user ID", member.Value)
+		} else if !group.Members.Has(userID) {
+			group.Members = append(group.Members, model.SCIMGroupMember{
+				SCIMGroupID: group.ID,
+				UserID:      userID,
+			})
+		}
+	}
+
+	return nil
+}
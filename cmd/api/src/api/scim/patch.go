@@ -0,0 +1,452 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const (
+	patchOpAdd     = "add"
+	patchOpReplace = "replace"
+	patchOpRemove  = "remove"
+)
+
+// PatchRequest is a SCIM PATCH request as defined in RFC 7644 section 3.5.2
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// patchPath is the target of a PATCH operation: an attribute, optionally narrowed down to the values of a multi-valued
+// attribute that match a value filter, and an optional sub-attribute
+type patchPath struct {
+	attribute    string
+	valueFilter  Filter
+	subAttribute string
+}
+
+func parsePatchPath(path string) (patchPath, error) {
+	if openIdx := strings.Index(path, "["); openIdx >= 0 {
+		var (
+			closeIdx   = strings.LastIndex(path, "]")
+			parsedPath = patchPath{
+				attribute: normalizeAttributePath(path[:openIdx]),
+			}
+		)
+
+		if closeIdx < openIdx {
+			return parsedPath, newError(ErrorTypeInvalidPath, "unterminated value filter in path %q", path)
+		} else if valueFilter, err := ParseFilter(path[openIdx+1 : closeIdx]); err != nil {
+			return parsedPath, newError(ErrorTypeInvalidPath, "invalid value filter in path %q: %v", path, err)
+		} else if remainder := path[closeIdx+1:]; remainder != "" && !strings.HasPrefix(remainder, ".") {
+			return parsedPath, newError(ErrorTypeInvalidPath, "invalid path %q", path)
+		} else {
+			parsedPath.valueFilter = valueFilter
+			parsedPath.subAttribute = strings.ToLower(strings.TrimPrefix(remainder, "."))
+
+			return parsedPath, nil
+		}
+	} else if normalizedPath := normalizeAttributePath(path); normalizedPath == "" {
+		return patchPath{}, newError(ErrorTypeInvalidPath, "invalid path %q", path)
+	} else {
+		attribute, subAttribute, _ := strings.Cut(normalizedPath, ".")
+
+		return patchPath{
+			attribute:    attribute,
+			subAttribute: subAttribute,
+		}, nil
+	}
+}
+
+// forEachOperation validates the operations of the request and calls the given function for every attribute that they
+// target. Operations without a path target the attributes named by the keys of their value object.
+func (s PatchRequest) forEachOperation(delegate func(op string, path patchPath, value json.RawMessage) error) error {
+	if len(s.Operations) == 0 {
+		return newError(ErrorTypeInvalidSyntax, "the request does not contain any operations")
+	}
+
+	for _, operation := range s.Operations {
+		// Operation names are matched case-insensitively as some clients send capitalized operation names
+		switch op := strings.ToLower(operation.Op); op {
+		case patchOpAdd, patchOpReplace, patchOpRemove:
+			if operation.Path != "" {
+				if path, err := parsePatchPath(operation.Path); err != nil {
+					return err
+				} else if err := delegate(op, path, operation.Value); err != nil {
+					return err
+				}
+			} else if op == patchOpRemove {
+				return newError(ErrorTypeNoTarget, "remove operations require a path")
+			} else {
+				var values map[string]json.RawMessage
+
+				if err := json.Unmarshal(operation.Value, &values); err != nil {
+					return newError(ErrorTypeInvalidValue, "operations without a path require an object value")
+				}
+
+				keys := make([]string, 0, len(values))
+				for key := range values {
+					keys = append(keys, key)
+				}
+
+				sort.Strings(keys)
+
+				for _, key := range keys {
+					if path, err := parsePatchPath(key); err != nil {
+						return err
+					} else if err := delegate(op, path, values[key]); err != nil {
+						return err
+					}
+				}
+			}
+
+		default:
+			return newError(ErrorTypeInvalidSyntax, "unsupported operation %q", operation.Op)
+		}
+	}
+
+	return nil
+}
+
+func isNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
+
+func decodeString(value json.RawMessage, target *string) error {
+	if err := json.Unmarshal(value, target); err != nil {
+		return newError(ErrorTypeInvalidValue, "expected a string value")
+	}
+
+	return nil
+}
+
+// decodeBool decodes a boolean value. Some clients send boolean values as strings.
+func decodeBool(value json.RawMessage) (bool, error) {
+	var (
+		boolValue   bool
+		stringValue string
+	)
+
+	if err := json.Unmarshal(value, &boolValue); err == nil {
+		return boolValue, nil
+	} else if err := json.Unmarshal(value, &stringValue); err == nil && strings.EqualFold(stringValue, "true") {
+		return true, nil
+	} else if err == nil && strings.EqualFold(stringValue, "false") {
+		return false, nil
+	} else {
+		return false, newError(ErrorTypeInvalidValue, "expected a boolean value")
+	}
+}
+
+// decodeMultiValued decodes the value of a multi-valued attribute, which clients send either as an array or as a
+// single value
+func decodeMultiValued[T any](value json.RawMessage) ([]T, error) {
+	var (
+		values []T
+		single T
+	)
+
+	if err := json.Unmarshal(value, &values); err == nil {
+		return values, nil
+	} else if err := json.Unmarshal(value, &single); err != nil {
+		return nil, newError(ErrorTypeInvalidValue, "invalid multi-valued attribute value: %v", err)
+	} else {
+		return []T{single}, nil
+	}
+}
+
+// patch applies a PATCH operation to the user. Attributes that BloodHound does not store, such as the attributes of
+// the enterprise user extension, are ignored and the groups of a user are read-only.
+func (s *User) patch(op string, path patchPath, value json.RawMessage) error {
+	if path.valueFilter != nil && path.attribute != "emails" {
+		return newError(ErrorTypeInvalidPath, "attribute %q is not multi-valued", path.attribute)
+	}
+
+	switch path.attribute {
+	case "username":
+		if op == patchOpRemove {
+			return newError(ErrorTypeMutability, "userName can not be removed")
+		}
+
+		return decodeString(value, &s.UserName)
+
+	case "active":
+		if op == patchOpRemove {
+			return newError(ErrorTypeMutability, "active can not be removed")
+		} else if active, err := decodeBool(value); err != nil {
+			return err
+		} else {
+			s.Active = &active
+			return nil
+		}
+
+	case "name":
+		return s.patchName(op, path.subAttribute, value)
+
+	case "emails":
+		return s.patchEmails(op, path, value)
+
+	default:
+		return nil
+	}
+}
+
+func (s *User) patchName(op, subAttribute string, value json.RawMessage) error {
+	if s.Name == nil {
+		s.Name = &Name{}
+	}
+
+	var target *string
+
+	switch subAttribute {
+	case "":
+		var name Name
+
+		if op == patchOpRemove {
+			s.Name = nil
+		} else if err := json.Unmarshal(value, &name); err != nil {
+			return newError(ErrorTypeInvalidValue, "expected a name value")
+		} else {
+			// Replacing a complex attribute replaces the sub-attributes that are present in the value
+			if name.GivenName != "" {
+				s.Name.GivenName = name.GivenName
+			}
+
+			if name.FamilyName != "" {
+				s.Name.FamilyName = name.FamilyName
+			}
+		}
+
+		return nil
+
+	case "givenname":
+		target = &s.Name.GivenName
+
+	case "familyname":
+		target = &s.Name.FamilyName
+
+	case "formatted":
+		// The formatted name is derived from the given and family name
+		return nil
+
+	default:
+		return newError(ErrorTypeInvalidPath, "unsupported attribute name.%s", subAttribute)
+	}
+
+	if op == patchOpRemove {
+		*target = ""
+		return nil
+	}
+
+	return decodeString(value, target)
+}
+
+func (s *User) patchEmails(op string, path patchPath, value json.RawMessage) error {
+	if path.valueFilter == nil {
+		if path.subAttribute != "" {
+			return newError(ErrorTypeInvalidPath, "a value filter is required to modify emails.%s", path.subAttribute)
+		} else if op == patchOpRemove {
+			s.Emails = nil
+		} else if emails, err := decodeMultiValued[Email](value); err != nil {
+			return err
+		} else if op == patchOpReplace {
+			s.Emails = emails
+		} else {
+			s.Emails = append(s.Emails, emails...)
+		}
+
+		return nil
+	}
+
+	var matched []int
+
+	for idx, email := range s.Emails {
+		if path.valueFilter.matches(email.attributes()) {
+			matched = append(matched, idx)
+		}
+	}
+
+	if op == patchOpRemove {
+		if path.subAttribute == "" || path.subAttribute == "value" {
+			var remaining []Email
+
+			for idx, email := range s.Emails {
+				if !containsIndex(matched, idx) {
+					remaining = append(remaining, email)
+				}
+			}
+
+			s.Emails = remaining
+		}
+
+		return nil
+	}
+
+	if len(matched) == 0 {
+		// Create the value that the filter targets, e.g. for a replacement of emails[type eq "work"].value
+		email := Email{
+			Primary: len(s.Emails) == 0,
+		}
+
+		if attribute, operand, isEquality := equalityOperand(path.valueFilter); isEquality && attribute == "type" {
+			email.Type = operand
+		}
+
+		s.Emails = append(s.Emails, email)
+		matched = append(matched, len(s.Emails)-1)
+	}
+
+	for _, idx := range matched {
+		email := &s.Emails[idx]
+
+		switch path.subAttribute {
+		case "":
+			if err := json.Unmarshal(value, email); err != nil {
+				return newError(ErrorTypeInvalidValue, "expected an email value")
+			}
+
+		case "value":
+			if err := decodeString(value, &email.Value); err != nil {
+				return err
+			}
+
+		case "type":
+			if err := decodeString(value, &email.Type); err != nil {
+				return err
+			}
+
+		case "primary":
+			if primary, err := decodeBool(value); err != nil {
+				return err
+			} else {
+				email.Primary = primary
+			}
+
+		default:
+			return newError(ErrorTypeInvalidPath, "unsupported attribute emails.%s", path.subAttribute)
+		}
+	}
+
+	return nil
+}
+
+func containsIndex(indexes []int, idx int) bool {
+	for _, next := range indexes {
+		if next == idx {
+			return true
+		}
+	}
+
+	return false
+}
+
+// patch applies a PATCH operation to the group
+func (s *Group) patch(op string, path patchPath, value json.RawMessage) error {
+	if path.valueFilter != nil && path.attribute != "members" {
+		return newError(ErrorTypeInvalidPath, "attribute %q is not multi-valued", path.attribute)
+	}
+
+	switch path.attribute {
+	case "displayname":
+		if op == patchOpRemove {
+			return newError(ErrorTypeMutability, "displayName can not be removed")
+		}
+
+		return decodeString(value, &s.DisplayName)
+
+	case "externalid":
+		if op == patchOpRemove {
+			s.ExternalID = ""
+			return nil
+		}
+
+		return decodeString(value, &s.ExternalID)
+
+	case "members":
+		return s.patchMembers(op, path, value)
+
+	default:
+		return nil
+	}
+}
+
+func (s *Group) patchMembers(op string, path patchPath, value json.RawMessage) error {
+	if path.subAttribute != "" {
+		return newError(ErrorTypeInvalidPath, "members.%s can not be modified", path.subAttribute)
+	}
+
+	if op == patchOpRemove {
+		var remaining []Reference
+
+		if path.valueFilter != nil {
+			for _, member := range s.Members {
+				if !path.valueFilter.matches(member.attributes()) {
+					remaining = append(remaining, member)
+				}
+			}
+		} else if !isNull(value) {
+			// Some clients send the members to remove as the value of the operation instead of using a value filter
+			if removed, err := decodeMultiValued[Reference](value); err != nil {
+				return err
+			} else {
+				for _, member := range s.Members {
+					if !containsReference(removed, member) {
+						remaining = append(remaining, member)
+					}
+				}
+			}
+		}
+
+		s.Members = remaining
+		return nil
+	}
+
+	if path.valueFilter != nil {
+		return newError(ErrorTypeInvalidPath, "members can only be added without a value filter")
+	} else if members, err := decodeMultiValued[Reference](value); err != nil {
+		return err
+	} else if op == patchOpReplace {
+		s.Members = members
+	} else {
+		for _, member := range members {
+			if !containsReference(s.Members, member) {
+				s.Members = append(s.Members, member)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsReference(references []Reference, reference Reference) bool {
+	for _, next := range references {
+		if strings.EqualFold(next.Value, reference.Value) {
+			return true
+		}
+	}
+
+	return false
+}
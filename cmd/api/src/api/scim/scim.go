@@ -0,0 +1,268 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+// Package scim implements the SCIM 2.0 provisioning API (RFC 7643 and RFC 7644) that allows identity governance
+// platforms to create, update, suspend and delete BloodHound users and to grant roles through SCIM group memberships.
+//
+// Requests are authenticated with SCIM tokens that are presented as bearer tokens. See: `src/auth/scim.go`
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/mediatypes"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/api/stream"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/auth/bhscim"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	// BasePath is the root path of the SCIM API
+	BasePath = "/scim/v2"
+
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	ErrorTypeInvalidFilter = "invalidFilter"
+	ErrorTypeInvalidSyntax = "invalidSyntax"
+	ErrorTypeInvalidPath   = "invalidPath"
+	ErrorTypeInvalidValue  = "invalidValue"
+	ErrorTypeMutability    = "mutability"
+	ErrorTypeNoTarget      = "noTarget"
+	ErrorTypeUniqueness    = "uniqueness"
+
+	// MaxResults is the maximum number of resources returned in a single list response
+	MaxResults = 1000
+
+	ErrorResponseDetailsTokenOwnerImmutable = "the owner of the SCIM token can not be suspended or deleted"
+)
+
+// Error is a SCIM error as defined in RFC 7644 section 3.12
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	SCIMType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+
+	statusCode int
+}
+
+func (s Error) Error() string {
+	return s.Detail
+}
+
+// newError returns a bad request SCIM error of the given SCIM error type
+func newError(scimType, format string, args ...any) Error {
+	return newStatusError(http.StatusBadRequest, scimType, fmt.Sprintf(format, args...))
+}
+
+func newStatusError(statusCode int, scimType, detail string) Error {
+	return Error{
+		Schemas:    []string{SchemaError},
+		Status:     strconv.Itoa(statusCode),
+		SCIMType:   scimType,
+		Detail:     detail,
+		statusCode: statusCode,
+	}
+}
+
+// ListResponse is a SCIM list response as defined in RFC 7644 section 3.4.2
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// page is the 1-based page of a list request as defined in RFC 7644 section 3.4.2.4
+type page struct {
+	startIndex int
+	count      int
+}
+
+func parsePage(request *http.Request) (page, error) {
+	var (
+		queryParameters = request.URL.Query()
+		requestPage     = page{
+			startIndex: 1,
+			count:      MaxResults,
+		}
+	)
+
+	if rawStartIndex := queryParameters.Get("startIndex"); rawStartIndex != "" {
+		if startIndex, err := strconv.Atoi(rawStartIndex); err != nil {
+			return requestPage, newError(ErrorTypeInvalidValue, "startIndex must be a number")
+		} else if startIndex > 1 {
+			requestPage.startIndex = startIndex
+		}
+	}
+
+	if rawCount := queryParameters.Get("count"); rawCount != "" {
+		if count, err := strconv.Atoi(rawCount); err != nil {
+			return requestPage, newError(ErrorTypeInvalidValue, "count must be a number")
+		} else if count < 0 {
+			requestPage.count = 0
+		} else if count < MaxResults {
+			requestPage.count = count
+		}
+	}
+
+	return requestPage, nil
+}
+
+func newListResponse[T any](resources []T, requestPage page) ListResponse {
+	var (
+		start = requestPage.startIndex - 1
+		end   = start + requestPage.count
+	)
+
+	if start > len(resources) {
+		start = len(resources)
+	}
+
+	if end > len(resources) {
+		end = len(resources)
+	}
+
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   requestPage.startIndex,
+		ItemsPerPage: end - start,
+		Resources:    resources[start:end],
+	}
+}
+
+// Resource serves the SCIM provisioning API on top of the BloodHound user and role operations
+type Resource struct {
+	db               database.Database
+	roleSynchronizer bhscim.RoleSynchronizer
+}
+
+func NewSCIMResource(db database.Database) Resource {
+	return Resource{
+		db:               db,
+		roleSynchronizer: bhscim.NewRoleSynchronizer(db),
+	}
+}
+
+// baseURL returns the URL of the SCIM API root that resource locations are relative to
+func baseURL(request *http.Request) url.URL {
+	return api.URLJoinPath(*ctx.FromRequest(request).Host, BasePath)
+}
+
+// requestToken returns the SCIM token that authenticated the request
+func (s Resource) requestToken(request *http.Request) (model.SCIMToken, error) {
+	if authorization := strings.Fields(request.Header.Get(headers.Authorization.String())); len(authorization) != 2 || !auth.IsSCIMToken(authorization[1]) {
+		return model.SCIMToken{}, newStatusError(http.StatusUnauthorized, "", api.ErrorResponseDetailsAuthenticationInvalid)
+	} else {
+		return s.db.LookupSCIMToken(auth.SCIMTokenDigest(authorization[1]))
+	}
+}
+
+// auditLog appends an audit log entry on behalf of the owner of the SCIM token that authenticated the request
+func (s Resource) auditLog(request *http.Request, scimToken model.SCIMToken, action string, data model.Auditable) error {
+	return s.db.AppendAuditLog(*ctx.FromRequest(request), action, data.AuditData().MergeLeft(scimToken))
+}
+
+// endSessions ends the active sessions of the user so that a suspension takes effect immediately
+func (s Resource) endSessions(user model.User) error {
+	if sessions, err := s.db.LookupActiveSessionsByUser(user); err != nil {
+		return err
+	} else {
+		for _, session := range sessions {
+			s.db.EndUserSession(session)
+		}
+
+		return nil
+	}
+}
+
+func parseUserID(request *http.Request) (uuid.UUID, error) {
+	if userID, err := uuid.FromString(mux.Vars(request)[api.URIPathVariableSCIMUserID]); err != nil {
+		return uuid.UUID{}, newStatusError(http.StatusNotFound, "", api.ErrorResponseDetailsResourceNotFound)
+	} else {
+		return userID, nil
+	}
+}
+
+func parseGroupID(request *http.Request) (int32, error) {
+	if groupID, err := strconv.ParseInt(mux.Vars(request)[api.URIPathVariableSCIMGroupID], 10, 32); err != nil {
+		return 0, newStatusError(http.StatusNotFound, "", api.ErrorResponseDetailsResourceNotFound)
+	} else {
+		return int32(groupID), nil
+	}
+}
+
+// readPayload decodes the request body. SCIM clients send either application/scim+json or application/json.
+func readPayload(request *http.Request, value any) error {
+	if request.Body == nil {
+		return newError(ErrorTypeInvalidSyntax, "%v", api.ErrorNoRequestBody)
+	} else if err := json.NewDecoder(stream.NewLimitedReader(api.DefaultAPIPayloadReadLimitBytes, request.Body)).Decode(value); err != nil {
+		return newError(ErrorTypeInvalidSyntax, "could not decode request: %v", err)
+	} else {
+		return nil
+	}
+}
+
+func writeResponse(response http.ResponseWriter, statusCode int, body any) {
+	if content, err := json.Marshal(body); err != nil {
+		log.Errorf("[SCIM] Failed to marshal response: %v", err)
+		response.WriteHeader(http.StatusInternalServerError)
+	} else {
+		response.Header().Set(headers.ContentType.String(), mediatypes.ApplicationScimJson.String())
+		response.WriteHeader(statusCode)
+
+		if _, err := response.Write(content); err != nil {
+			log.Errorf("[SCIM] Failed to write response: %v", err)
+		}
+	}
+}
+
+// writeError writes the error as a SCIM error. Errors that are not SCIM errors are treated as database errors.
+func writeError(response http.ResponseWriter, err error) {
+	var scimError Error
+
+	if errors.As(err, &scimError) {
+		log.Warnf("[SCIM] Writing error. Status: %d. Detail: %s", scimError.statusCode, scimError.Detail)
+		writeResponse(response, scimError.statusCode, scimError)
+	} else if errors.Is(err, database.ErrNotFound) {
+		writeResponse(response, http.StatusNotFound, newStatusError(http.StatusNotFound, "", api.ErrorResponseDetailsResourceNotFound))
+	} else {
+		log.Errorf("[SCIM] Unexpected error: %v", err)
+		writeResponse(response, http.StatusInternalServerError, newStatusError(http.StatusInternalServerError, "", api.ErrorResponseDetailsInternalServerError))
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/mediatypes"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/serde"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testSCIMToken = auth.SCIMTokenPrefix + "test"
+
+func newTestRequest(t *testing.T, method, body string, urlVars map[string]string) *http.Request {
+	var (
+		rootURL          = serde.MustParseURL("https://example.com")
+		httpRequest, err = http.NewRequestWithContext(context.WithValue(context.TODO(), ctx.ValueKey, &ctx.Context{Host: &rootURL.URL}), method, "http://localhost", strings.NewReader(body))
+	)
+
+	require.Nil(t, err)
+
+	httpRequest.Header.Set(headers.Authorization.String(), "Bearer "+testSCIMToken)
+	httpRequest.Header.Set(headers.ContentType.String(), mediatypes.ApplicationScimJson.String())
+
+	return mux.SetURLVars(httpRequest, urlVars)
+}
+
+func TestResource_CreateUser(t *testing.T) {
+	var (
+		scimToken = model.SCIMToken{
+			UserID:         uuid.Must(uuid.NewV4()),
+			Name:           "governance",
+			OIDCProviderID: null.Int32From(1),
+		}
+
+		mockCtrl = gomock.NewController(t)
+		mockDB   = dbmocks.NewMockDatabase(mockCtrl)
+		resource = NewSCIMResource(mockDB)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().LookupSCIMToken(auth.SCIMTokenDigest(testSCIMToken)).Return(scimToken, nil).AnyTimes()
+
+	// New users are bound to the SSO provider of the token and are created without roles
+	createdUserID := uuid.Must(uuid.NewV4())
+
+	mockDB.EXPECT().LookupUser("bjensen@example.com").Return(model.User{}, database.ErrNotFound)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "CreateSCIMUser", gomock.Any()).Return(nil)
+	mockDB.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(newUser model.User) (model.User, error) {
+		require.Equal(t, "bjensen@example.com", newUser.PrincipalName)
+		require.Equal(t, null.StringFrom("Barbara"), newUser.FirstName)
+		require.Equal(t, null.StringFrom("bjensen@example.com"), newUser.EmailAddress)
+		require.Equal(t, null.Int32From(1), newUser.OIDCProviderID)
+		require.Empty(t, newUser.Roles)
+		require.False(t, newUser.IsDisabled)
+
+		newUser.ID = createdUserID
+		return newUser, nil
+	})
+	mockDB.EXPECT().GetAllSCIMGroups().Return(model.SCIMGroups{}, nil)
+
+	response := httptest.NewRecorder()
+	resource.CreateUser(response, newTestRequest(t, http.MethodPost, `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "bjensen@example.com",
+		"name": {"givenName": "Barbara"},
+		"emails": [{"value": "bjensen@example.com", "type": "work", "primary": true}],
+		"active": true
+	}`, nil))
+
+	require.Equal(t, http.StatusCreated, response.Code)
+	require.Equal(t, mediatypes.ApplicationScimJson.String(), response.Header().Get(headers.ContentType.String()))
+	require.Equal(t, "https://example.com/scim/v2/Users/"+createdUserID.String(), response.Header().Get(headers.Location.String()))
+
+	var scimUser User
+	require.Nil(t, json.Unmarshal(response.Body.Bytes(), &scimUser))
+	require.Equal(t, createdUserID.String(), scimUser.ID)
+	require.Equal(t, ResourceTypeUser, scimUser.Meta.ResourceType)
+
+	// User names are unique
+	mockDB.EXPECT().LookupUser("bjensen@example.com").Return(model.User{Unique: model.Unique{ID: createdUserID}}, nil)
+
+	response = httptest.NewRecorder()
+	resource.CreateUser(response, newTestRequest(t, http.MethodPost, `{"userName": "bjensen@example.com"}`, nil))
+
+	require.Equal(t, http.StatusConflict, response.Code)
+	require.Contains(t, response.Body.String(), ErrorTypeUniqueness)
+
+	// A user name is required
+	response = httptest.NewRecorder()
+	resource.CreateUser(response, newTestRequest(t, http.MethodPost, `{"name": {"givenName": "Barbara"}}`, nil))
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.Contains(t, response.Body.String(), ErrorTypeInvalidValue)
+}
+
+func TestResource_ListUsers(t *testing.T) {
+	var (
+		group = model.SCIMGroup{
+			DisplayName: "Administrators",
+			Serial:      model.Serial{ID: 3},
+		}
+
+		alice = model.User{PrincipalName: "alice@example.com", Unique: model.Unique{ID: uuid.Must(uuid.NewV4())}}
+		bob   = model.User{PrincipalName: "bob@example.com", IsDisabled: true, Unique: model.Unique{ID: uuid.Must(uuid.NewV4())}}
+
+		mockCtrl = gomock.NewController(t)
+		mockDB   = dbmocks.NewMockDatabase(mockCtrl)
+		resource = NewSCIMResource(mockDB)
+	)
+
+	defer mockCtrl.Finish()
+
+	group.Members = model.SCIMGroupMembers{{SCIMGroupID: group.ID, UserID: bob.ID}}
+
+	mockDB.EXPECT().GetAllUsers("principal_name", model.SQLFilter{}).Return(model.Users{alice, bob}, nil).Times(2)
+	mockDB.EXPECT().GetAllSCIMGroups().Return(model.SCIMGroups{group}, nil).Times(2)
+
+	response := httptest.NewRecorder()
+	httpRequest := newTestRequest(t, http.MethodGet, "", nil)
+	httpRequest.URL.RawQuery = "filter=" + `userName+eq+"BOB@example.com"+and+active+eq+false`
+
+	resource.ListUsers(response, httpRequest)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var listResponse struct {
+		TotalResults int    `json:"totalResults"`
+		Resources    []User `json:"Resources"`
+	}
+
+	require.Nil(t, json.Unmarshal(response.Body.Bytes(), &listResponse))
+	require.Equal(t, 1, listResponse.TotalResults)
+	require.Equal(t, bob.ID.String(), listResponse.Resources[0].ID)
+	require.Equal(t, []Reference{{
+		Value:   "3",
+		Ref:     "https://example.com/scim/v2/Groups/3",
+		Display: "Administrators",
+	}}, listResponse.Resources[0].Groups)
+
+	// Pages are 1-based
+	response = httptest.NewRecorder()
+	httpRequest = newTestRequest(t, http.MethodGet, "", nil)
+	httpRequest.URL.RawQuery = "startIndex=2&count=1"
+
+	resource.ListUsers(response, httpRequest)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Nil(t, json.Unmarshal(response.Body.Bytes(), &listResponse))
+	require.Equal(t, 2, listResponse.TotalResults)
+	require.Len(t, listResponse.Resources, 1)
+	require.Equal(t, bob.ID.String(), listResponse.Resources[0].ID)
+
+	// Invalid filters are rejected
+	response = httptest.NewRecorder()
+	httpRequest = newTestRequest(t, http.MethodGet, "", nil)
+	httpRequest.URL.RawQuery = "filter=userName+eq"
+
+	resource.ListUsers(response, httpRequest)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.Contains(t, response.Body.String(), ErrorTypeInvalidFilter)
+}
+
+func TestResource_PatchUser(t *testing.T) {
+	var (
+		owner = model.User{PrincipalName: "admin", Unique: model.Unique{ID: uuid.Must(uuid.NewV4())}}
+		user  = model.User{
+			PrincipalName: "bjensen@example.com",
+			Roles:         model.Roles{{Name: "Read-Only", Serial: model.Serial{ID: 3}}},
+			Unique:        model.Unique{ID: uuid.Must(uuid.NewV4())},
+		}
+
+		activeSession = model.UserSession{BigSerial: model.BigSerial{ID: 5}}
+		suspendUser   = `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "Replace", "path": "active", "value": false}]
+		}`
+
+		mockCtrl = gomock.NewController(t)
+		mockDB   = dbmocks.NewMockDatabase(mockCtrl)
+		resource = NewSCIMResource(mockDB)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().LookupSCIMToken(auth.SCIMTokenDigest(testSCIMToken)).Return(model.SCIMToken{UserID: owner.ID}, nil).AnyTimes()
+	mockDB.EXPECT().GetAllSCIMGroups().Return(model.SCIMGroups{}, nil).AnyTimes()
+
+	// Suspending a user ends its active sessions and leaves its roles unchanged
+	mockDB.EXPECT().GetUser(user.ID).Return(user, nil)
+	mockDB.EXPECT().LookupUser(user.PrincipalName).Return(user, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "UpdateSCIMUser", gomock.Any()).Return(nil)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(updatedUser model.User) error {
+		require.True(t, updatedUser.IsDisabled)
+		require.Equal(t, user.Roles, updatedUser.Roles)
+		return nil
+	})
+	mockDB.EXPECT().LookupActiveSessionsByUser(gomock.Any()).Return([]model.UserSession{activeSession}, nil)
+	mockDB.EXPECT().EndUserSession(activeSession)
+
+	response := httptest.NewRecorder()
+	resource.PatchUser(response, newTestRequest(t, http.MethodPatch, suspendUser, map[string]string{api.URIPathVariableSCIMUserID: user.ID.String()}))
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Contains(t, response.Body.String(), `"active":false`)
+
+	// The owner of the token can not suspend itself
+	mockDB.EXPECT().GetUser(owner.ID).Return(owner, nil)
+
+	response = httptest.NewRecorder()
+	resource.PatchUser(response, newTestRequest(t, http.MethodPatch, suspendUser, map[string]string{api.URIPathVariableSCIMUserID: owner.ID.String()}))
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.Contains(t, response.Body.String(), ErrorTypeMutability)
+
+	// Unknown users are not found
+	unknownUserID := uuid.Must(uuid.NewV4())
+	mockDB.EXPECT().GetUser(unknownUserID).Return(model.User{}, database.ErrNotFound)
+
+	response = httptest.NewRecorder()
+	resource.PatchUser(response, newTestRequest(t, http.MethodPatch, suspendUser, map[string]string{api.URIPathVariableSCIMUserID: unknownUserID.String()}))
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestResource_DeleteUser(t *testing.T) {
+	var (
+		owner = model.User{PrincipalName: "admin", Unique: model.Unique{ID: uuid.Must(uuid.NewV4())}}
+		user  = model.User{PrincipalName: "bjensen@example.com", Unique: model.Unique{ID: uuid.Must(uuid.NewV4())}}
+
+		mockCtrl = gomock.NewController(t)
+		mockDB   = dbmocks.NewMockDatabase(mockCtrl)
+		resource = NewSCIMResource(mockDB)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().LookupSCIMToken(auth.SCIMTokenDigest(testSCIMToken)).Return(model.SCIMToken{UserID: owner.ID}, nil).AnyTimes()
+
+	mockDB.EXPECT().GetUser(user.ID).Return(user, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "DeleteSCIMUser", gomock.Any()).Return(nil)
+	mockDB.EXPECT().DeleteUser(user).Return(nil)
+
+	response := httptest.NewRecorder()
+	resource.DeleteUser(response, newTestRequest(t, http.MethodDelete, "", map[string]string{api.URIPathVariableSCIMUserID: user.ID.String()}))
+
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	// The owner of the token can not delete itself
+	mockDB.EXPECT().GetUser(owner.ID).Return(owner, nil)
+
+	response = httptest.NewRecorder()
+	resource.DeleteUser(response, newTestRequest(t, http.MethodDelete, "", map[string]string{api.URIPathVariableSCIMUserID: owner.ID.String()}))
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResource_PatchGroup(t *testing.T) {
+	var (
+		adminRole = model.Role{Name: "Administrator", Serial: model.Serial{ID: 1}}
+		alice     = model.User{PrincipalName: "alice", Roles: model.Roles{adminRole}, Unique: model.Unique{ID: uuid.Must(uuid.NewV4())}}
+		bob       = model.User{PrincipalName: "bob", Roles: model.Roles{}, Unique: model.Unique{ID: uuid.Must(uuid.NewV4())}}
+		group     = model.SCIMGroup{
+			DisplayName: "BloodHound Admins",
+			RoleID:      null.Int32From(adminRole.ID),
+			Members:     model.SCIMGroupMembers{{SCIMGroupID: 2, UserID: alice.ID}},
+			Serial:      model.Serial{ID: 2},
+		}
+		updatedGroup = model.SCIMGroup{
+			DisplayName: group.DisplayName,
+			RoleID:      group.RoleID,
+			Members:     model.SCIMGroupMembers{{SCIMGroupID: 2, UserID: bob.ID}},
+			Serial:      group.Serial,
+		}
+
+		mockCtrl = gomock.NewController(t)
+		mockDB   = dbmocks.NewMockDatabase(mockCtrl)
+		resource = NewSCIMResource(mockDB)
+	)
+
+	defer mockCtrl.Finish()
+
+	// Replacing the members of a group that is mapped to a role grants the role to the new members and revokes it
+	// from the removed members
+	mockDB.EXPECT().LookupSCIMToken(auth.SCIMTokenDigest(testSCIMToken)).Return(model.SCIMToken{}, nil)
+	mockDB.EXPECT().GetSCIMGroup(group.ID).Return(group, nil)
+	mockDB.EXPECT().GetAllSCIMGroups().Return(model.SCIMGroups{group}, nil)
+	mockDB.EXPECT().GetAllUsers("", model.SQLFilter{}).Return(model.Users{alice, bob}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "UpdateSCIMGroup", gomock.Any()).Return(nil)
+	mockDB.EXPECT().UpdateSCIMGroup(updatedGroup).Return(nil)
+	mockDB.EXPECT().GetAllSCIMGroups().Return(model.SCIMGroups{updatedGroup}, nil)
+	mockDB.EXPECT().GetUser(alice.ID).Return(alice, nil)
+	mockDB.EXPECT().GetUser(bob.ID).Return(bob, nil)
+	mockDB.EXPECT().GetRoles([]int32{adminRole.ID}).Return(model.Roles{adminRole}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "SyncSCIMUserRoles", gomock.Any()).Return(nil).Times(2)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(updatedUser model.User) error {
+		if updatedUser.ID == alice.ID {
+			require.Empty(t, updatedUser.Roles)
+		} else {
+			require.Equal(t, model.Roles{adminRole}, updatedUser.Roles)
+		}
+
+		return nil
+	}).Times(2)
+
+	response := httptest.NewRecorder()
+	resource.PatchGroup(response, newTestRequest(t, http.MethodPatch, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "remove", "path": "members[value eq \"`+alice.ID.String()+`\"]"},
+			{"op": "add", "path": "members", "value": [{"value": "`+bob.ID.String()+`"}]}
+		]
+	}`, map[string]string{api.URIPathVariableSCIMGroupID: "2"}))
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var scimGroup Group
+	require.Nil(t, json.Unmarshal(response.Body.Bytes(), &scimGroup))
+	require.Equal(t, []Reference{{
+		Value: bob.ID.String(),
+		Ref:   "https://example.com/scim/v2/Users/" + bob.ID.String(),
+	}}, scimGroup.Members)
+
+	// Members must be existing users
+	mockDB.EXPECT().LookupSCIMToken(auth.SCIMTokenDigest(testSCIMToken)).Return(model.SCIMToken{}, nil)
+	mockDB.EXPECT().GetSCIMGroup(group.ID).Return(group, nil)
+	mockDB.EXPECT().GetAllSCIMGroups().Return(model.SCIMGroups{group}, nil)
+	mockDB.EXPECT().GetAllUsers("", model.SQLFilter{}).Return(model.Users{alice}, nil)
+
+	response = httptest.NewRecorder()
+	resource.PatchGroup(response, newTestRequest(t, http.MethodPatch, `{
+		"Operations": [{"op": "add", "path": "members", "value": [{"value": "`+bob.ID.String()+`"}]}]
+	}`, map[string]string{api.URIPathVariableSCIMGroupID: "2"}))
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.Contains(t, response.Body.String(), ErrorTypeInvalidValue)
+}
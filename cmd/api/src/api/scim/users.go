@@ -0,0 +1,200 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"net/http"
+
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+func (s Resource) ListUsers(response http.ResponseWriter, request *http.Request) {
+	if filter, err := parseQueryFilter(request); err != nil {
+		writeError(response, err)
+	} else if requestPage, err := parsePage(request); err != nil {
+		writeError(response, err)
+	} else if users, err := s.db.GetAllUsers("principal_name", model.SQLFilter{}); err != nil {
+		writeError(response, err)
+	} else if groups, err := s.db.GetAllSCIMGroups(); err != nil {
+		writeError(response, err)
+	} else {
+		scimUsers := make([]User, len(users))
+
+		for idx, user := range users {
+			scimUsers[idx] = newUser(baseURL(request), user, groups)
+		}
+
+		writeResponse(response, http.StatusOK, newListResponse(filterResources(scimUsers, filter), requestPage))
+	}
+}
+
+func (s Resource) GetUser(response http.ResponseWriter, request *http.Request) {
+	if userID, err := parseUserID(request); err != nil {
+		writeError(response, err)
+	} else if user, err := s.db.GetUser(userID); err != nil {
+		writeError(response, err)
+	} else {
+		s.writeUser(response, request, http.StatusOK, user)
+	}
+}
+
+func (s Resource) writeUser(response http.ResponseWriter, request *http.Request, statusCode int, user model.User) {
+	if groups, err := s.db.GetAllSCIMGroups(); err != nil {
+		writeError(response, err)
+	} else {
+		scimUser := newUser(baseURL(request), user, groups)
+
+		response.Header().Set(headers.Location.String(), scimUser.Meta.Location)
+		writeResponse(response, statusCode, scimUser)
+	}
+}
+
+// CreateUser creates a user without roles. Roles are granted through the membership of the user in SCIM groups that
+// are mapped to roles. Users are bound to the SSO provider of the SCIM token, if any.
+func (s Resource) CreateUser(response http.ResponseWriter, request *http.Request) {
+	var (
+		scimUser     User
+		userTemplate = model.User{
+			Roles: model.Roles{},
+
+			// EULA Acceptance does not pertain to Bloodhound Community Edition; this flag is used for Bloodhound Enterprise users.
+			EULAAccepted: true,
+		}
+	)
+
+	if scimToken, err := s.requestToken(request); err != nil {
+		writeError(response, err)
+	} else if err := readPayload(request, &scimUser); err != nil {
+		writeError(response, err)
+	} else if err := scimUser.apply(&userTemplate); err != nil {
+		writeError(response, err)
+	} else if err := s.ensureUniqueUserName(userTemplate); err != nil {
+		writeError(response, err)
+	} else {
+		scimToken.BindUser(&userTemplate)
+
+		if err := s.auditLog(request, scimToken, "CreateSCIMUser", userTemplate); err != nil {
+			writeError(response, err)
+		} else if newUser, err := s.db.CreateUser(userTemplate); err != nil {
+			writeError(response, err)
+		} else {
+			s.writeUser(response, request, http.StatusCreated, newUser)
+		}
+	}
+}
+
+// ensureUniqueUserName returns a uniqueness error if the user name of the user is taken by another user
+func (s Resource) ensureUniqueUserName(user model.User) error {
+	if existingUser, err := s.db.LookupUser(user.PrincipalName); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	} else if existingUser.ID != user.ID {
+		return newStatusError(http.StatusConflict, ErrorTypeUniqueness, "userName is already taken")
+	} else {
+		return nil
+	}
+}
+
+func (s Resource) ReplaceUser(response http.ResponseWriter, request *http.Request) {
+	var scimUser User
+
+	if userID, err := parseUserID(request); err != nil {
+		writeError(response, err)
+	} else if user, err := s.db.GetUser(userID); err != nil {
+		writeError(response, err)
+	} else if err := readPayload(request, &scimUser); err != nil {
+		writeError(response, err)
+	} else {
+		s.updateUser(response, request, user, scimUser)
+	}
+}
+
+func (s Resource) PatchUser(response http.ResponseWriter, request *http.Request) {
+	var patchRequest PatchRequest
+
+	if userID, err := parseUserID(request); err != nil {
+		writeError(response, err)
+	} else if user, err := s.db.GetUser(userID); err != nil {
+		writeError(response, err)
+	} else if groups, err := s.db.GetAllSCIMGroups(); err != nil {
+		writeError(response, err)
+	} else if err := readPayload(request, &patchRequest); err != nil {
+		writeError(response, err)
+	} else {
+		scimUser := newUser(baseURL(request), user, groups)
+
+		if err := patchRequest.forEachOperation(scimUser.patch); err != nil {
+			writeError(response, err)
+		} else {
+			s.updateUser(response, request, user, scimUser)
+		}
+	}
+}
+
+// updateUser writes the SCIM user to the user. Users that are suspended have their active sessions ended.
+func (s Resource) updateUser(response http.ResponseWriter, request *http.Request, user model.User, scimUser User) {
+	var (
+		updatedUser = user
+		suspended   = scimUser.Active != nil && !*scimUser.Active && !user.IsDisabled
+	)
+
+	if scimToken, err := s.requestToken(request); err != nil {
+		writeError(response, err)
+	} else if err := scimUser.apply(&updatedUser); err != nil {
+		writeError(response, err)
+	} else if suspended && scimToken.UserID == user.ID {
+		writeError(response, newError(ErrorTypeMutability, ErrorResponseDetailsTokenOwnerImmutable))
+	} else if err := s.ensureUniqueUserName(updatedUser); err != nil {
+		writeError(response, err)
+	} else if err := s.auditLog(request, scimToken, "UpdateSCIMUser", updatedUser.AuditData().MergeLeft(model.AuditData{"active": !updatedUser.IsDisabled})); err != nil {
+		writeError(response, err)
+	} else if err := s.db.UpdateUser(updatedUser); err != nil {
+		writeError(response, err)
+	} else if suspended {
+		if err := s.endSessions(updatedUser); err != nil {
+			writeError(response, err)
+		} else {
+			s.writeUser(response, request, http.StatusOK, updatedUser)
+		}
+	} else {
+		s.writeUser(response, request, http.StatusOK, updatedUser)
+	}
+}
+
+func (s Resource) DeleteUser(response http.ResponseWriter, request *http.Request) {
+	if scimToken, err := s.requestToken(request); err != nil {
+		writeError(response, err)
+	} else if userID, err := parseUserID(request); err != nil {
+		writeError(response, err)
+	} else if user, err := s.db.GetUser(userID); err != nil {
+		writeError(response, err)
+	} else if scimToken.UserID == user.ID {
+		writeError(response, newError(ErrorTypeMutability, ErrorResponseDetailsTokenOwnerImmutable))
+	} else if err := s.auditLog(request, scimToken, "DeleteSCIMUser", user); err != nil {
+		writeError(response, err)
+	} else if err := s.db.DeleteUser(user); err != nil {
+		writeError(response, err)
+	} else {
+		response.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 
 	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/api/graphexport"
 	"github.com/specterops/bloodhound/src/model/appcfg"
 	"github.com/specterops/bloodhound/src/queries"
 	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
@@ -30,6 +31,16 @@ import (
 	"github.com/specterops/bloodhound/graphschema/ad"
 )
 
+// writeEntityQueryResults writes the results of an entity query. Graph results are written in the export format
+// negotiated by queries.BuildEntityQueryParams when one was requested.
+func writeEntityQueryResults(response http.ResponseWriter, request *http.Request, params queries.EntityQueryParameters, results any) {
+	if exportGraph, isExport := results.(graphexport.Graph); isExport {
+		api.WriteGraphExportResponse(request.Context(), params.ExportFormat, exportGraph, http.StatusOK, response)
+	} else {
+		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+	}
+}
+
 func (s *Resources) ListADUserSessions(response http.ResponseWriter, request *http.Request) {
 	var (
 		queryName    = "ListADUserSessions"
@@ -50,7 +61,7 @@ func (s *Resources) ListADUserSessions(response http.ResponseWriter, request *ht
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -74,7 +85,7 @@ func (s *Resources) ListADUserSQLAdminRights(response http.ResponseWriter, reque
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -98,7 +109,7 @@ func (s *Resources) ListADGroupSessions(response http.ResponseWriter, request *h
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -122,7 +133,7 @@ func (s *Resources) ListADComputerSessions(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -146,7 +157,7 @@ func (s *Resources) ListADComputerAdmins(response http.ResponseWriter, request *
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -170,7 +181,7 @@ func (s *Resources) ListADComputerPSRemoteUsers(response http.ResponseWriter, re
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -194,7 +205,7 @@ func (s *Resources) ListADComputerRDPUsers(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -218,7 +229,7 @@ func (s *Resources) ListADComputerDCOMUsers(response http.ResponseWriter, reques
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -242,7 +253,7 @@ func (s *Resources) ListADGroupMembership(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -266,7 +277,7 @@ func (s *Resources) ListADGroupMembers(response http.ResponseWriter, request *ht
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -290,7 +301,7 @@ func (s *Resources) ListADComputerSQLAdmins(response http.ResponseWriter, reques
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -314,7 +325,7 @@ func (s *Resources) ListADComputerConstrainedDelegationUsers(response http.Respo
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -338,7 +349,7 @@ func (s *Resources) ListADEntityConstrainedDelegationRights(response http.Respon
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -362,7 +373,7 @@ func (s *Resources) ListADEntityAdminRights(response http.ResponseWriter, reques
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -386,7 +397,7 @@ func (s *Resources) ListADEntityRDPRights(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -410,7 +421,7 @@ func (s *Resources) ListADEntityPSRemoteRights(response http.ResponseWriter, req
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -434,7 +445,7 @@ func (s *Resources) ListADEntityDCOMRights(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -458,7 +469,7 @@ func (s *Resources) ListADEntityControllers(response http.ResponseWriter, reques
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -482,7 +493,7 @@ func (s *Resources) ListADEntityControllables(response http.ResponseWriter, requ
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -506,7 +517,7 @@ func (s *Resources) ListADEntityLinkedGPOs(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -529,7 +540,7 @@ func (s *Resources) ListADDomainContainedUsers(response http.ResponseWriter, req
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -552,7 +563,7 @@ func (s *Resources) ListADDomainContainedComputers(response http.ResponseWriter,
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -575,7 +586,7 @@ func (s *Resources) ListADDomainContainedGroups(response http.ResponseWriter, re
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -598,7 +609,7 @@ func (s *Resources) ListADDomainContainedOUs(response http.ResponseWriter, reque
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -621,7 +632,7 @@ func (s *Resources) ListADDomainContainedGPOs(response http.ResponseWriter, requ
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -645,7 +656,7 @@ func (s *Resources) ListADDomainForeignGroups(response http.ResponseWriter, requ
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -669,7 +680,7 @@ func (s *Resources) ListADDomainForeignUsers(response http.ResponseWriter, reque
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -693,7 +704,7 @@ func (s *Resources) ListADDomainForeignAdmins(response http.ResponseWriter, requ
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -717,7 +728,7 @@ func (s *Resources) ListADDomainForeignGPOControllers(response http.ResponseWrit
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -741,7 +752,7 @@ func (s *Resources) ListADDomainOutboundTrusts(response http.ResponseWriter, req
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -765,7 +776,7 @@ func (s *Resources) ListADDomainInboundTrusts(response http.ResponseWriter, requ
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -789,7 +800,7 @@ func (s *Resources) ListADDomainDCSyncers(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -813,7 +824,7 @@ func (s *Resources) ListADOUContainedUsers(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -837,7 +848,7 @@ func (s *Resources) ListADOUContainedGroups(response http.ResponseWriter, reques
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -861,7 +872,7 @@ func (s *Resources) ListADOUContainedComputers(response http.ResponseWriter, req
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -885,7 +896,7 @@ func (s *Resources) ListADGPOAffectedContainers(response http.ResponseWriter, re
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -909,7 +920,7 @@ func (s *Resources) ListADGPOAffectedUsers(response http.ResponseWriter, request
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -933,7 +944,7 @@ func (s *Resources) ListADGPOAffectedComputers(response http.ResponseWriter, req
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
 
@@ -957,6 +968,6 @@ func (s *Resources) ListADGPOAffectedTierZero(response http.ResponseWriter, requ
 			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "an unknown error occurred during the request", request), response)
 		}
 	} else {
-		api.WriteJSONResponse(request.Context(), results, http.StatusOK, response)
+		writeEntityQueryResults(response, request, params, results)
 	}
 }
@@ -23,6 +23,7 @@ import (
 	v2 "github.com/specterops/bloodhound/src/api/v2"
 	"github.com/specterops/bloodhound/src/api/v2/apitest"
 	dbMocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/model"
 	"github.com/specterops/bloodhound/src/model/appcfg"
 	"github.com/specterops/bloodhound/src/queries"
 	graphMocks "github.com/specterops/bloodhound/src/queries/mocks"
@@ -50,6 +51,17 @@ func setupCases(mockGraph *graphMocks.MockGraph, mockDB *dbMocks.MockDatabase) [
 				apitest.BodyContains(output, "no object ID found in request")
 			},
 		},
+		{
+			Name: "RepoGetEntityQueryParamsSortError",
+			Input: func(input *apitest.Input) {
+				apitest.SetURLVar(input, "object_id", "1")
+				apitest.AddQueryParam(input, "sort_by", "-description")
+			},
+			Test: func(output apitest.Output) {
+				apitest.StatusCode(output, http.StatusBadRequest)
+				apitest.BodyContains(output, model.ErrorResponseDetailsColumnNotSortable)
+			},
+		},
 		{
 			Name: "GraphDBGetADEntityQueryResultGraphUnsupportedError",
 			Input: func(input *apitest.Input) {
@@ -25,7 +25,9 @@ import (
 
 	"github.com/specterops/bloodhound/src/api"
 	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/daemons/datapipe"
 	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/services/agi"
 	"github.com/specterops/bloodhound/src/utils"
 	"github.com/gorilla/mux"
 	"github.com/specterops/bloodhound/analysis"
@@ -34,6 +36,7 @@ import (
 	"github.com/specterops/bloodhound/graphschema/azure"
 	"github.com/specterops/bloodhound/graphschema/common"
 	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/log"
 	"github.com/specterops/bloodhound/slices"
 )
 
@@ -234,6 +237,60 @@ func (s Resources) DeleteAssetGroup(response http.ResponseWriter, request *http.
 	}
 }
 
+// validateAssetGroupSelectorSpecs checks that every selector added by the given specs can be evaluated
+func validateAssetGroupSelectorSpecs(selectorSpecs []model.AssetGroupSelectorSpec) error {
+	for _, selectorSpec := range selectorSpecs {
+		if err := selectorSpec.Validate(); err != nil {
+			return err
+		} else if selectorSpec.Action == model.SelectorSpecActionAdd {
+			if err := agi.ValidateAssetGroupSelector(selectorSpec.AssetGroupSelector(0, false)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestAssetGroupIsolationTagging asks the datapipe to evaluate the asset group selectors again so that selector
+// changes are reflected in the graph without waiting for the next full analysis. Tier zero tagging is requested as well
+// since it relies on the system tags that the asset group tagging stage clears.
+func (s Resources) requestAssetGroupIsolationTagging() {
+	if err := s.TaskNotifier.RequestPostProcessingStages([]string{
+		datapipe.StageAssetGroupIsolationTagging,
+		datapipe.StageActiveDirectoryTierZeroTagging,
+		datapipe.StageAzureTierZeroTagging,
+		datapipe.StageAssetGroupIsolationCollections,
+	}); err != nil {
+		log.Errorf("Failed requesting asset group isolation tagging: %v", err)
+	}
+}
+
+// UpdateAssetGroupSelectors adds and removes the selectors of an asset group. Dynamic selectors are validated here and
+// resolved against the graph on every analysis run.
+func (s Resources) UpdateAssetGroupSelectors(response http.ResponseWriter, request *http.Request) {
+	var (
+		pathVars        = mux.Vars(request)
+		rawAssetGroupID = pathVars[api.URIPathVariableAssetGroupID]
+		selectorSpecs   []model.AssetGroupSelectorSpec
+	)
+
+	if assetGroupID, err := strconv.Atoi(rawAssetGroupID); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&selectorSpecs, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if err := validateAssetGroupSelectorSpecs(selectorSpecs); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if assetGroup, err := s.DB.GetAssetGroup(int32(assetGroupID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if updatedSelectors, err := s.DB.UpdateAssetGroupSelectors(*ctx.FromRequest(request), assetGroup, selectorSpecs, false); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		s.requestAssetGroupIsolationTagging()
+		api.WriteBasicResponse(request.Context(), updatedSelectors, http.StatusCreated, response)
+	}
+}
+
 func (s Resources) DeleteAssetGroupSelector(response http.ResponseWriter, request *http.Request) {
 	var (
 		pathVars                = mux.Vars(request)
@@ -401,9 +458,9 @@ func parseAGMembersFromNodes(nodes graph.NodeSet, selectors model.AssetGroupSele
 	agMembers := api.AssetGroupMembers{}
 	for _, node := range nodes {
 		isCustomMember := false
-		// a member is custom if at least one selector exists for that object ID
-		for _, agSelector := range selectors {
-			if agSelector.Selector == node.Properties.Map[common.ObjectID.String()].(string) {
+		// a member is custom if at least one object ID selector exists for that object ID
+		for _, objectID := range selectors.ObjectIDs() {
+			if objectID == node.Properties.Map[common.ObjectID.String()].(string) {
 				isCustomMember = true
 			}
 		}
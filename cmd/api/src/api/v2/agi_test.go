@@ -28,6 +28,7 @@ import (
 	v2 "github.com/specterops/bloodhound/src/api/v2"
 	"github.com/specterops/bloodhound/src/api/v2/apitest"
 	"github.com/specterops/bloodhound/src/ctx"
+	taskerMocks "github.com/specterops/bloodhound/src/daemons/datapipe/mocks"
 	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
 	"github.com/specterops/bloodhound/src/model"
 	queriesMocks "github.com/specterops/bloodhound/src/queries/mocks"
@@ -799,6 +800,123 @@ func TestResources_DeleteAssetGroupSelector(t *testing.T) {
 		ResponseStatusCode(http.StatusOK)
 }
 
+func TestResources_UpdateAssetGroupSelectors(t *testing.T) {
+	var (
+		mockCtrl   = gomock.NewController(t)
+		mockDB     = dbmocks.NewMockDatabase(mockCtrl)
+		mockTasker = taskerMocks.NewMockTasker(mockCtrl)
+		resources  = v2.Resources{DB: mockDB, TaskNotifier: mockTasker}
+		assetGroup = model.AssetGroup{Name: "test group", Tag: "test_group"}
+		specs      = []model.AssetGroupSelectorSpec{{
+			SelectorName:   "domain admins",
+			EntityObjectID: "S-1-5-21-570004220-2248230615-4072641716-512",
+			Action:         model.SelectorSpecActionAdd,
+		}, {
+			SelectorName: "tier one servers",
+			SelectorType: model.AssetGroupSelectorTypeDistinguishedName,
+			Selector:     "OU=TIER1 SERVERS,DC=CONTOSO,DC=LOCAL",
+			Action:       model.SelectorSpecActionAdd,
+		}, {
+			SelectorName: "admin count",
+			SelectorType: model.AssetGroupSelectorTypeProperty,
+			Selector:     "admincount=true",
+			Action:       model.SelectorSpecActionAdd,
+		}}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodPost).
+		WithURL("https://example.com/api/v2/asset-groups/{asset_group_id}/selectors")
+
+	// Error where AG ID is not a valid int
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"asset_group_id": "test",
+		}).
+		WithBody(specs).
+		OnHandlerFunc(resources.UpdateAssetGroupSelectors).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Unknown selector type
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"asset_group_id": "1234",
+		}).
+		WithBody([]model.AssetGroupSelectorSpec{{
+			SelectorName: "troll",
+			SelectorType: "troll",
+			Selector:     "troll",
+			Action:       model.SelectorSpecActionAdd,
+		}}).
+		OnHandlerFunc(resources.UpdateAssetGroupSelectors).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Malformed property selector
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"asset_group_id": "1234",
+		}).
+		WithBody([]model.AssetGroupSelectorSpec{{
+			SelectorName: "admin count",
+			SelectorType: model.AssetGroupSelectorTypeProperty,
+			Selector:     "admincount",
+			Action:       model.SelectorSpecActionAdd,
+		}}).
+		OnHandlerFunc(resources.UpdateAssetGroupSelectors).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// GetAssetGroup DB fails
+	mockDB.EXPECT().GetAssetGroup(int32(1234)).Return(model.AssetGroup{}, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"asset_group_id": "1234",
+		}).
+		WithBody(specs).
+		OnHandlerFunc(resources.UpdateAssetGroupSelectors).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// UpdateAssetGroupSelectors DB fails
+	mockDB.EXPECT().GetAssetGroup(int32(1234)).Return(assetGroup, nil)
+	mockDB.EXPECT().UpdateAssetGroupSelectors(gomock.Any(), assetGroup, specs, false).Return(nil, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"asset_group_id": "1234",
+		}).
+		WithBody(specs).
+		OnHandlerFunc(resources.UpdateAssetGroupSelectors).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Success
+	mockDB.EXPECT().GetAssetGroup(int32(1234)).Return(assetGroup, nil)
+	mockDB.EXPECT().UpdateAssetGroupSelectors(gomock.Any(), assetGroup, specs, false).Return(map[string]model.AssetGroupSelectors{
+		"added_selectors":   {specs[0].AssetGroupSelector(1234, false), specs[1].AssetGroupSelector(1234, false), specs[2].AssetGroupSelector(1234, false)},
+		"removed_selectors": {},
+	}, nil)
+	mockTasker.EXPECT().RequestPostProcessingStages([]string{
+		"asset-group-isolation-tagging",
+		"ad-tier-zero-tagging",
+		"azure-tier-zero-tagging",
+		"asset-group-isolation-collections",
+	}).Return(nil)
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"asset_group_id": "1234",
+		}).
+		WithBody(specs).
+		OnHandlerFunc(resources.UpdateAssetGroupSelectors).
+		Require().
+		ResponseStatusCode(http.StatusCreated)
+}
+
 func TestResources_ListAssetGroupMembers(t *testing.T) {
 	var (
 		mockCtrl   = gomock.NewController(t)
@@ -17,7 +17,17 @@
 package v2
 
 import (
+	"fmt"
+	"net/http"
 	"time"
+
+	"github.com/gorilla/mux"
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/utils"
 )
 
 const (
@@ -34,3 +44,158 @@ type RiskAcceptRequest struct {
 	AcceptUntil time.Time `json:"accept_until"`
 	Accepted    bool      `json:"accepted"` // DEPRECATED remove this field for V3
 }
+
+// RiskAcceptance returns the risk acceptance described by the request. The risk is snoozed until AcceptUntil when it is
+// set and accepted indefinitely when only the deprecated Accepted field is set. False is returned if the request
+// revokes the acceptance instead.
+func (s RiskAcceptRequest) RiskAcceptance(environmentID string, now time.Time) (model.RiskAcceptance, bool) {
+	acceptance := model.RiskAcceptance{
+		EnvironmentID: environmentID,
+		FindingType:   s.RiskType,
+	}
+
+	if !s.AcceptUntil.IsZero() {
+		acceptance.AcceptedUntil = null.TimeFrom(s.AcceptUntil.UTC())
+		return acceptance, s.AcceptUntil.After(now)
+	}
+
+	return acceptance, s.Accepted
+}
+
+// summarizeAttackPathFindings describes the state of every type of the finding catalogue in a domain
+func summarizeAttackPathFindings(findings model.AttackPathFindings, acceptances model.RiskAcceptances, now time.Time) model.AttackPathFindingSummaries {
+	summaries := make(model.AttackPathFindingSummaries, 0, len(adAnalysis.FindingDetections()))
+
+	for _, detection := range adAnalysis.FindingDetections() {
+		summary := model.AttackPathFindingSummary{
+			FindingType: detection.FindingType,
+			Description: detection.Description,
+		}
+
+		for _, finding := range findings {
+			if finding.FindingType != detection.FindingType {
+				continue
+			}
+
+			if finding.Resolved {
+				summary.Resolved++
+			} else {
+				summary.Principals++
+			}
+
+			if summary.FirstSeen.IsZero() || finding.FirstSeen.Before(summary.FirstSeen) {
+				summary.FirstSeen = finding.FirstSeen
+			}
+
+			if finding.LastSeen.After(summary.LastSeen) {
+				summary.LastSeen = finding.LastSeen
+			}
+		}
+
+		if acceptance, found := acceptances.Get(detection.FindingType); found && acceptance.IsAccepted(now) {
+			summary.Accepted = true
+			summary.AcceptedUntil = acceptance.AcceptedUntil
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
+func (s Resources) getAttackPathFindingSummaries(environmentID string) (model.AttackPathFindingSummaries, error) {
+	if findings, err := s.DB.GetAllAttackPathFindings(environmentID); err != nil {
+		return nil, err
+	} else if acceptances, err := s.DB.GetRiskAcceptances(environmentID); err != nil {
+		return nil, err
+	} else {
+		return summarizeAttackPathFindings(findings, acceptances, time.Now().UTC()), nil
+	}
+}
+
+// ListAttackPathFindings returns the state of every type of attack path finding in a domain
+func (s Resources) ListAttackPathFindings(response http.ResponseWriter, request *http.Request) {
+	if domainID, hasDomainID := mux.Vars(request)[api.URIPathVariableObjectID]; !hasDomainID {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNoDomainId, request), response)
+	} else if !requestEnvironmentScope(request).Allows(domainID) {
+		writeEnvironmentNotFound(response, request)
+	} else if summaries, err := s.getAttackPathFindingSummaries(domainID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), summaries, http.StatusOK, response)
+	}
+}
+
+// ListAttackPathFindingDetails returns the principals of a domain affected by one type of attack path finding. Resolved
+// findings are only included when requested.
+func (s Resources) ListAttackPathFindingDetails(response http.ResponseWriter, request *http.Request) {
+	var (
+		queryParams = request.URL.Query()
+		findingType = queryParams.Get(api.QueryParameterFinding)
+	)
+
+	if domainID, hasDomainID := mux.Vars(request)[api.URIPathVariableObjectID]; !hasDomainID {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNoDomainId, request), response)
+	} else if !requestEnvironmentScope(request).Allows(domainID) {
+		writeEnvironmentNotFound(response, request)
+	} else if findingType == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNoFindingType, request), response)
+	} else if _, isValidFindingType := adAnalysis.FindingDetectionByType(findingType); !isValidFindingType {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(ErrorInvalidFindingType, findingType), request), response)
+	} else if includeResolved, err := api.ParseOptionalBool(queryParams.Get(api.QueryParameterIncludeResolved), false); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsBadQueryParameterFilters, request), response)
+	} else if limit, err := ParseLimitQueryParameter(queryParams, 100); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(utils.ErrorInvalidLimit, queryParams["limit"]), request), response)
+	} else if skip, err := ParseSkipQueryParameter(queryParams, 0); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(utils.ErrorInvalidSkip, queryParams["skip"]), request), response)
+	} else if findings, count, err := s.DB.GetAttackPathFindings(domainID, findingType, includeResolved, limit, skip); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteResponseWrapperWithPagination(request.Context(), findings, limit, skip, count, http.StatusOK, response)
+	}
+}
+
+// applyRiskAcceptance records the risk acceptance described by the request in the audit log before storing or revoking
+// it
+func (s Resources) applyRiskAcceptance(request *http.Request, domainID string, riskAcceptRequest RiskAcceptRequest) error {
+	if acceptance, accepted := riskAcceptRequest.RiskAcceptance(domainID, time.Now().UTC()); !accepted {
+		if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "RevokeAttackPathRiskAcceptance", acceptance); err != nil {
+			return err
+		}
+
+		return s.DB.DeleteRiskAcceptance(domainID, acceptance.FindingType)
+	} else if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "AcceptAttackPathRisk", acceptance); err != nil {
+		return err
+	} else {
+		_, err := s.DB.SaveRiskAcceptance(acceptance)
+		return err
+	}
+}
+
+// UpdateAttackPathRisk accepts, snoozes or revokes the acceptance of the risk of one type of attack path finding in a
+// domain and returns the updated state of the finding type
+func (s Resources) UpdateAttackPathRisk(response http.ResponseWriter, request *http.Request) {
+	var riskAcceptRequest RiskAcceptRequest
+
+	if domainID, hasDomainID := mux.Vars(request)[api.URIPathVariableObjectID]; !hasDomainID {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNoDomainId, request), response)
+	} else if !requestEnvironmentScope(request).Allows(domainID) {
+		writeEnvironmentNotFound(response, request)
+	} else if err := api.ReadJSONRequestPayloadLimited(&riskAcceptRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorDecodeParams, request), response)
+	} else if riskAcceptRequest.RiskType == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNoFindingType, request), response)
+	} else if _, isValidFindingType := adAnalysis.FindingDetectionByType(riskAcceptRequest.RiskType); !isValidFindingType {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(ErrorInvalidFindingType, riskAcceptRequest.RiskType), request), response)
+	} else if err := s.applyRiskAcceptance(request, domainID, riskAcceptRequest); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if summaries, err := s.getAttackPathFindingSummaries(domainID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		for _, summary := range summaries {
+			if summary.FindingType == riskAcceptRequest.RiskType {
+				api.WriteBasicResponse(request.Context(), summary, http.StatusOK, response)
+			}
+		}
+	}
+}
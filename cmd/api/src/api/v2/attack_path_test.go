@@ -15,3 +15,242 @@
 // SPDX-License-Identifier: Apache-2.0
 
 package v2_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/utils/test"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRiskAcceptRequest_RiskAcceptance(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Snoozed until a date in the future
+	acceptance, accepted := v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins, AcceptUntil: now.Add(time.Hour)}.RiskAcceptance("S-1-5-21-1", now)
+	require.True(t, accepted)
+	require.Equal(t, "S-1-5-21-1", acceptance.EnvironmentID)
+	require.True(t, acceptance.AcceptedUntil.Valid)
+	require.True(t, acceptance.IsAccepted(now))
+	require.False(t, acceptance.IsAccepted(now.Add(2*time.Hour)))
+
+	// A date in the past revokes the acceptance
+	_, accepted = v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins, AcceptUntil: now.Add(-time.Hour)}.RiskAcceptance("S-1-5-21-1", now)
+	require.False(t, accepted)
+
+	// Accepted indefinitely
+	acceptance, accepted = v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins, Accepted: true}.RiskAcceptance("S-1-5-21-1", now)
+	require.True(t, accepted)
+	require.False(t, acceptance.AcceptedUntil.Valid)
+	require.True(t, acceptance.IsAccepted(now.AddDate(100, 0, 0)))
+
+	_, accepted = v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins}.RiskAcceptance("S-1-5-21-1", now)
+	require.False(t, accepted)
+}
+
+func TestResources_ListAttackPathFindings(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+		firstSeen = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/domains/{object_id}/attack-path-findings").
+		WithURLPathVars(map[string]string{
+			"object_id": "S-1-5-21-1",
+		})
+
+	// GetAllAttackPathFindings DB fails
+	mockDB.EXPECT().GetAllAttackPathFindings("S-1-5-21-1").Return(nil, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		OnHandlerFunc(resources.ListAttackPathFindings).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Success
+	mockDB.EXPECT().GetAllAttackPathFindings("S-1-5-21-1").Return(model.AttackPathFindings{{
+		EnvironmentID: "S-1-5-21-1",
+		FindingType:   adAnalysis.FindingNonTierZeroDCSync,
+		ObjectID:      "S-1-5-21-1-1104",
+		FirstSeen:     firstSeen,
+		LastSeen:      firstSeen.Add(time.Hour),
+	}, {
+		EnvironmentID: "S-1-5-21-1",
+		FindingType:   adAnalysis.FindingNonTierZeroDCSync,
+		ObjectID:      "S-1-5-21-1-1105",
+		FirstSeen:     firstSeen.Add(-time.Hour),
+		LastSeen:      firstSeen,
+		Resolved:      true,
+	}}, nil)
+	mockDB.EXPECT().GetRiskAcceptances("S-1-5-21-1").Return(model.RiskAcceptances{{
+		EnvironmentID: "S-1-5-21-1",
+		FindingType:   adAnalysis.FindingNonTierZeroDCSync,
+	}}, nil)
+
+	requestTemplate.
+		OnHandlerFunc(resources.ListAttackPathFindings).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Error where the domain is outside of the environments of the requester
+	requestTemplate.
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		OnHandlerFunc(resources.ListAttackPathFindings).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
+
+func TestResources_ListAttackPathFindingDetails(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/domains/{object_id}/attack-path-findings/details").
+		WithURLPathVars(map[string]string{
+			"object_id": "S-1-5-21-1",
+		})
+
+	// Error where no finding type is specified
+	requestTemplate.
+		WithURLQueryVars(url.Values{}).
+		OnHandlerFunc(resources.ListAttackPathFindingDetails).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where the finding type is not in the catalogue
+	requestTemplate.
+		WithURLQueryVars(url.Values{"finding": []string{"NotAFinding"}}).
+		OnHandlerFunc(resources.ListAttackPathFindingDetails).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where include_resolved is not a valid bool
+	requestTemplate.
+		WithURLQueryVars(url.Values{"finding": []string{adAnalysis.FindingForeignAdmins}, "include_resolved": []string{"test"}}).
+		OnHandlerFunc(resources.ListAttackPathFindingDetails).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// GetAttackPathFindings DB fails
+	mockDB.EXPECT().GetAttackPathFindings("S-1-5-21-1", adAnalysis.FindingForeignAdmins, false, 100, 0).Return(nil, 0, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithURLQueryVars(url.Values{"finding": []string{adAnalysis.FindingForeignAdmins}}).
+		OnHandlerFunc(resources.ListAttackPathFindingDetails).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Success
+	mockDB.EXPECT().GetAttackPathFindings("S-1-5-21-1", adAnalysis.FindingForeignAdmins, true, 10, 0).Return(model.AttackPathFindings{{
+		EnvironmentID: "S-1-5-21-1",
+		FindingType:   adAnalysis.FindingForeignAdmins,
+		ObjectID:      "S-1-5-21-2-1104",
+		Name:          "ALICE@CONTOSO.LOCAL",
+		Kind:          "User",
+	}}, 1, nil)
+
+	requestTemplate.
+		WithURLQueryVars(url.Values{"finding": []string{adAnalysis.FindingForeignAdmins}, "include_resolved": []string{"true"}, "limit": []string{"10"}}).
+		OnHandlerFunc(resources.ListAttackPathFindingDetails).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Error where the domain is outside of the environments of the requester
+	requestTemplate.
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		WithURLQueryVars(url.Values{"finding": []string{adAnalysis.FindingForeignAdmins}}).
+		OnHandlerFunc(resources.ListAttackPathFindingDetails).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
+
+func TestResources_UpdateAttackPathRisk(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodPut).
+		WithURL("http://example.com/api/v2/domains/{object_id}/attack-path-findings").
+		WithURLPathVars(map[string]string{
+			"object_id": "S-1-5-21-1",
+		})
+
+	// Error where no finding type is specified
+	requestTemplate.
+		WithBody(v2.RiskAcceptRequest{Accepted: true}).
+		OnHandlerFunc(resources.UpdateAttackPathRisk).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where the finding type is not in the catalogue
+	requestTemplate.
+		WithBody(v2.RiskAcceptRequest{RiskType: "NotAFinding", Accepted: true}).
+		OnHandlerFunc(resources.UpdateAttackPathRisk).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// SaveRiskAcceptance DB fails
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "AcceptAttackPathRisk", gomock.Any()).Return(nil)
+	mockDB.EXPECT().SaveRiskAcceptance(gomock.Any()).Return(model.RiskAcceptance{}, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithBody(v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins, AcceptUntil: time.Now().Add(time.Hour)}).
+		OnHandlerFunc(resources.UpdateAttackPathRisk).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Success snoozing the finding
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "AcceptAttackPathRisk", gomock.Any()).Return(nil)
+	mockDB.EXPECT().SaveRiskAcceptance(gomock.Any()).Return(model.RiskAcceptance{}, nil)
+	mockDB.EXPECT().GetAllAttackPathFindings("S-1-5-21-1").Return(model.AttackPathFindings{}, nil)
+	mockDB.EXPECT().GetRiskAcceptances("S-1-5-21-1").Return(model.RiskAcceptances{}, nil)
+
+	requestTemplate.
+		WithBody(v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins, AcceptUntil: time.Now().Add(time.Hour)}).
+		OnHandlerFunc(resources.UpdateAttackPathRisk).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Success revoking the acceptance
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "RevokeAttackPathRiskAcceptance", gomock.Any()).Return(nil)
+	mockDB.EXPECT().DeleteRiskAcceptance("S-1-5-21-1", adAnalysis.FindingForeignAdmins).Return(nil)
+	mockDB.EXPECT().GetAllAttackPathFindings("S-1-5-21-1").Return(model.AttackPathFindings{}, nil)
+	mockDB.EXPECT().GetRiskAcceptances("S-1-5-21-1").Return(model.RiskAcceptances{}, nil)
+
+	requestTemplate.
+		WithBody(v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins}).
+		OnHandlerFunc(resources.UpdateAttackPathRisk).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Error where the domain is outside of the environments of the requester
+	requestTemplate.
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		WithBody(v2.RiskAcceptRequest{RiskType: adAnalysis.FindingForeignAdmins, AcceptUntil: time.Now().Add(time.Hour)}).
+		OnHandlerFunc(resources.UpdateAttackPathRisk).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
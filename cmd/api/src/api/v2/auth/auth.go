@@ -54,6 +54,14 @@ const (
 	ErrorResponseDetailsInvalidCurrentPassword = "unable to verify current password"
 	ErrorResponseDetailsMFAActivated           = "multi-factor authentication already active"
 	ErrorResponseDetailsMFAEnrollmentRequired  = "multi-factor authentication enrollment is required before activation"
+	ErrorResponseDetailsRoleNameRequired       = "role name is required"
+	ErrorResponseDetailsRoleNameReserved       = "role name is reserved for a built-in role"
+	ErrorResponseDetailsRoleNameInUse          = "role name is already in use"
+	ErrorResponseDetailsRoleBuiltIn            = "built-in roles can not be modified"
+	ErrorResponseDetailsRoleInUse              = "role is assigned to one or more users"
+	ErrorResponseDetailsPermissionInvalid      = "unknown permission id"
+	ErrorResponseDetailsEnvironmentInvalid     = "environment ids can not be blank"
+	ErrorResponseDetailsMultipleSSOProviders   = "a user can only be bound to one SAML or OIDC provider"
 )
 
 type ManagementResource struct {
@@ -62,6 +70,7 @@ type ManagementResource struct {
 	db                         database.Database
 	QueryParameterFilterParser model.QueryParameterFilterParser
 	authorizer                 auth.Authorizer
+	httpClient                 api.HTTPClient
 }
 
 func NewManagementResource(authConfig config.Configuration, db database.Database, authorizer auth.Authorizer) ManagementResource {
@@ -71,6 +80,7 @@ func NewManagementResource(authConfig config.Configuration, db database.Database
 		db:                         db,
 		QueryParameterFilterParser: model.NewQueryParameterFilterParser(),
 		authorizer:                 authorizer,
+		httpClient:                 api.NewHTTPClient(),
 	}
 }
 
@@ -360,6 +370,135 @@ func (s ManagementResource) GetRole(response http.ResponseWriter, request *http.
 	}
 }
 
+// buildCustomRole applies the role request to the given role. The returned error details are suitable for a bad request
+// response.
+func (s ManagementResource) buildCustomRole(roleRequest v2.UpsertRoleRequest, role model.Role) (model.Role, string, error) {
+	role.Name = strings.TrimSpace(roleRequest.Name)
+	role.Description = roleRequest.Description
+	role.Custom = true
+	role.Permissions = model.Permissions{}
+	role.Environments = model.RoleEnvironments{}
+
+	if role.Name == "" {
+		return role, ErrorResponseDetailsRoleNameRequired, nil
+	} else if _, isTemplate := auth.Roles()[role.Name]; isTemplate {
+		return role, ErrorResponseDetailsRoleNameReserved, nil
+	}
+
+	for _, environmentID := range roleRequest.Environments {
+		if environmentID = strings.ToUpper(strings.TrimSpace(environmentID)); environmentID == "" {
+			return role, ErrorResponseDetailsEnvironmentInvalid, nil
+		} else if !slices.Contains(role.Environments.IDs(), environmentID) {
+			role.Environments = append(role.Environments, model.RoleEnvironment{
+				RoleID:        role.ID,
+				EnvironmentID: environmentID,
+			})
+		}
+	}
+
+	if permissions, err := s.db.GetAllPermissions("", model.SQLFilter{}); err != nil {
+		return role, "", err
+	} else {
+		for _, permissionID := range roleRequest.Permissions {
+			if permission, found := permissions.FindByID(permissionID); !found {
+				return role, ErrorResponseDetailsPermissionInvalid, nil
+			} else if !role.Permissions.Has(permission) {
+				role.Permissions = append(role.Permissions, permission)
+			}
+		}
+	}
+
+	return role, "", nil
+}
+
+// ensureRoleNameAvailable returns true if no other role uses the name of the given role
+func (s ManagementResource) ensureRoleNameAvailable(role model.Role) (bool, error) {
+	if existingRole, err := s.db.LookupRoleByName(role.Name); errors.Is(err, database.ErrNotFound) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	} else {
+		return existingRole.ID == role.ID, nil
+	}
+}
+
+func (s ManagementResource) CreateRole(response http.ResponseWriter, request *http.Request) {
+	var createRoleRequest v2.UpsertRoleRequest
+
+	if err := api.ReadJSONRequestPayloadLimited(&createRoleRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if role, details, err := s.buildCustomRole(createRoleRequest, model.Role{}); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if details != "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, details, request), response)
+	} else if available, err := s.ensureRoleNameAvailable(role); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !available {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorResponseDetailsRoleNameInUse, request), response)
+	} else if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "CreateRole", role); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if newRole, err := s.db.CreateRole(role); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), newRole, http.StatusCreated, response)
+	}
+}
+
+func (s ManagementResource) UpdateRole(response http.ResponseWriter, request *http.Request) {
+	var (
+		updateRoleRequest v2.UpsertRoleRequest
+		pathVars          = mux.Vars(request)
+		rawRoleID         = pathVars[api.URIPathVariableRoleID]
+	)
+
+	if roleID, err := strconv.ParseInt(rawRoleID, 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if existingRole, err := s.db.GetRole(int32(roleID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !existingRole.Custom {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorResponseDetailsRoleBuiltIn, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&updateRoleRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if role, details, err := s.buildCustomRole(updateRoleRequest, existingRole); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if details != "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, details, request), response)
+	} else if available, err := s.ensureRoleNameAvailable(role); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !available {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorResponseDetailsRoleNameInUse, request), response)
+	} else if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "UpdateRole", role); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.db.UpdateRole(role); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), role, http.StatusOK, response)
+	}
+}
+
+func (s ManagementResource) DeleteRole(response http.ResponseWriter, request *http.Request) {
+	var (
+		pathVars  = mux.Vars(request)
+		rawRoleID = pathVars[api.URIPathVariableRoleID]
+	)
+
+	if roleID, err := strconv.ParseInt(rawRoleID, 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if role, err := s.db.GetRole(int32(roleID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !role.Custom {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorResponseDetailsRoleBuiltIn, request), response)
+	} else if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "DeleteRole", role); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.db.DeleteRole(role); errors.Is(err, database.ErrRoleInUse) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorResponseDetailsRoleInUse, request), response)
+	} else if err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		response.WriteHeader(http.StatusOK)
+	}
+}
+
 func (s ManagementResource) ListUsers(response http.ResponseWriter, request *http.Request) {
 	var (
 		order         []string
@@ -468,6 +607,11 @@ func (s ManagementResource) CreateUser(response http.ResponseWriter, request *ht
 			}
 		}
 
+		if createUserRequest.SAMLProviderID != "" && createUserRequest.OIDCProviderID != "" {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsMultipleSSOProviders, request), response)
+			return
+		}
+
 		if createUserRequest.SAMLProviderID != "" {
 			if samlProviderID, err := serde.ParseInt32(createUserRequest.SAMLProviderID); err != nil {
 				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("SAML Provider ID must be a number: %v", err.Error()), request), response)
@@ -479,6 +623,18 @@ func (s ManagementResource) CreateUser(response http.ResponseWriter, request *ht
 			}
 		}
 
+		if createUserRequest.OIDCProviderID != "" {
+			if oidcProviderID, err := serde.ParseInt32(createUserRequest.OIDCProviderID); err != nil {
+				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("OIDC Provider ID must be a number: %v", err.Error()), request), response)
+				return
+			} else if oidcProvider, err := s.db.GetOIDCProvider(oidcProviderID); err != nil {
+				api.HandleDatabaseError(request, response, err)
+				return
+			} else {
+				userTemplate.OIDCProviderID = null.Int32From(oidcProvider.ID)
+			}
+		}
+
 		if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "CreateUser", userTemplate); err != nil {
 			api.HandleDatabaseError(request, response, err)
 		} else if newUser, err := s.db.CreateUser(userTemplate); err != nil {
@@ -555,7 +711,9 @@ func (s ManagementResource) UpdateUser(response http.ResponseWriter, request *ht
 			}
 		}
 
-		if updateUserRequest.SAMLProviderID != "" {
+		if updateUserRequest.SAMLProviderID != "" && updateUserRequest.OIDCProviderID != "" {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsMultipleSSOProviders, request), response)
+		} else if updateUserRequest.SAMLProviderID != "" {
 			// We're setting a SAML provider. If the user has an associated secret the secret will be removed.
 			if samlProviderID, err := serde.ParseInt32(updateUserRequest.SAMLProviderID); err != nil {
 				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("SAML Provider ID must be a number: %v", err.Error()), request), response)
@@ -570,13 +728,36 @@ func (s ManagementResource) UpdateUser(response http.ResponseWriter, request *ht
 				user.AuthSecret = nil
 				user.SAMLProvider = &provider
 				user.SAMLProviderID = null.Int32From(samlProviderID)
+				user.OIDCProvider = nil
+				user.OIDCProviderID = null.NewInt32(0, false)
+
+				s.updateUser(response, request, user)
+			}
+		} else if updateUserRequest.OIDCProviderID != "" {
+			// We're setting an OIDC provider. As with SAML, any secret associated with the user will be removed.
+			if oidcProviderID, err := serde.ParseInt32(updateUserRequest.OIDCProviderID); err != nil {
+				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("OIDC Provider ID must be a number: %v", err.Error()), request), response)
+			} else if err := s.ensureUserHasNoAuthSecret(context, user); err != nil {
+				api.HandleDatabaseError(request, response, err)
+			} else if provider, err := s.db.GetOIDCProvider(oidcProviderID); err != nil {
+				api.HandleDatabaseError(request, response, err)
+			} else if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "SetUserOIDCProvider", user); err != nil {
+				api.HandleDatabaseError(request, response, err)
+			} else {
+				user.AuthSecret = nil
+				user.OIDCProvider = &provider
+				user.OIDCProviderID = null.Int32From(oidcProviderID)
+				user.SAMLProvider = nil
+				user.SAMLProviderID = null.NewInt32(0, false)
 
 				s.updateUser(response, request, user)
 			}
 		} else {
-			// Default SAMLProviderID to null if the update request contains no SAMLProviderID
+			// Default the SSO provider IDs to null if the update request contains neither
 			user.SAMLProviderID = null.NewInt32(0, false)
 			user.SAMLProvider = nil
+			user.OIDCProviderID = null.NewInt32(0, false)
+			user.OIDCProvider = nil
 
 			s.updateUser(response, request, user)
 		}
@@ -57,6 +57,7 @@ import (
 
 const (
 	samlProviderPathFmt           = "/api/v2/saml/providers/%d"
+	oidcProviderPathFmt           = "/api/v2/oidc/providers/%d"
 	updateUserPathFmt             = "/api/v2/auth/users/%s"
 	updateUserSecretPathFmt       = "/api/v2/auth/users/%s/secret"
 	samlProviderID          int32 = 1234
@@ -236,6 +237,547 @@ func TestManagementResource_DeleteSAMLProvider(t *testing.T) {
 		ResponseStatusCode(http.StatusOK)
 }
 
+func TestManagementResource_CreateOIDCProvider(t *testing.T) {
+	var (
+		mockCtrl          = gomock.NewController(t)
+		resources, mockDB = apitest.NewAuthManagementResource(mockCtrl)
+		requestContext    = &ctx.Context{Host: &url.URL{Scheme: "https", Host: "example.com"}}
+		adminRole         = model.Role{Name: "Administrator", Serial: model.Serial{ID: 1}}
+	)
+
+	defer mockCtrl.Finish()
+
+	idp := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/.well-known/openid-configuration" {
+			response.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		issuer := "http://" + request.Host
+
+		response.Header().Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+		json.NewEncoder(response).Encode(map[string]any{
+			"issuer":                                issuer,
+			"authorization_endpoint":                issuer + "/authorize",
+			"token_endpoint":                        issuer + "/token",
+			"jwks_uri":                              issuer + "/jwks",
+			"response_types_supported":              []string{"code"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	}))
+	defer idp.Close()
+
+	goodRequest := v2.CreateOIDCProviderRequest{
+		Name:         "corp",
+		IssuerURI:    idp.URL,
+		ClientID:     "bloodhound",
+		ClientSecret: "secret",
+		RoleClaim:    "groups",
+		RoleMappings: []v2.OIDCRoleMappingRequest{{ClaimValue: "sec-bh-admins", RoleID: adminRole.ID}},
+	}
+
+	mockDB.EXPECT().LookupOIDCProviderByName("corp").Return(model.OIDCProvider{}, database.ErrNotFound).Times(3)
+	mockDB.EXPECT().LookupOIDCProviderByName("taken").Return(model.OIDCProvider{Name: "taken"}, nil)
+	mockDB.EXPECT().GetRoles([]int32{adminRole.ID}).Return(model.Roles{adminRole}, nil).Times(2)
+	mockDB.EXPECT().GetRoles([]int32{99}).Return(model.Roles{}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "CreateOIDCProvider", gomock.Any()).Return(nil)
+	mockDB.EXPECT().CreateOIDCProvider(gomock.Any()).DoAndReturn(func(oidcProvider model.OIDCProvider) (model.OIDCProvider, error) {
+		require.Equal(t, "corp", oidcProvider.DisplayName)
+		require.Equal(t, "email", oidcProvider.PrincipalClaim)
+		require.Equal(t, "openid email profile", oidcProvider.Scopes)
+		require.Equal(t, model.OIDCRoleMappings{{ClaimValue: "sec-bh-admins", RoleID: adminRole.ID}}, oidcProvider.RoleMappings)
+
+		oidcProvider.ID = 1
+		return oidcProvider, nil
+	})
+
+	// Happy path
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/oidc/providers").
+		WithBody(goodRequest).
+		OnHandlerFunc(resources.CreateOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Negative path where the client ID is missing
+	missingClientIDRequest := goodRequest
+	missingClientIDRequest.ClientID = ""
+
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/oidc/providers").
+		WithBody(missingClientIDRequest).
+		OnHandlerFunc(resources.CreateOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where the name is already taken
+	takenNameRequest := goodRequest
+	takenNameRequest.Name = "taken"
+
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/oidc/providers").
+		WithBody(takenNameRequest).
+		OnHandlerFunc(resources.CreateOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusConflict)
+
+	// Negative path where a role mapping references an unknown role
+	unknownRoleRequest := goodRequest
+	unknownRoleRequest.RoleMappings = []v2.OIDCRoleMappingRequest{{ClaimValue: "sec-bh-admins", RoleID: 99}}
+
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/oidc/providers").
+		WithBody(unknownRoleRequest).
+		OnHandlerFunc(resources.CreateOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where the issuer does not serve a discovery document
+	missingDiscoveryRequest := goodRequest
+	missingDiscoveryRequest.IssuerURI = idp.URL + "/missing"
+
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/oidc/providers").
+		WithBody(missingDiscoveryRequest).
+		OnHandlerFunc(resources.CreateOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+}
+
+func TestManagementResource_DeleteOIDCProvider(t *testing.T) {
+	var (
+		goodOIDCProvider = model.OIDCProvider{
+			Serial: model.Serial{
+				ID: 1,
+			},
+		}
+
+		oidcProviderWithUsers = model.OIDCProvider{
+			Serial: model.Serial{
+				ID: 2,
+			},
+		}
+
+		oidcEnabledUser = model.User{
+			Unique: model.Unique{
+				ID: must.NewUUIDv4(),
+			},
+			OIDCProviderID: null.Int32From(2),
+		}
+
+		mockCtrl          = gomock.NewController(t)
+		resources, mockDB = apitest.NewAuthManagementResource(mockCtrl)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().GetOIDCProvider(goodOIDCProvider.ID).Return(goodOIDCProvider, nil)
+	mockDB.EXPECT().GetOIDCProvider(oidcProviderWithUsers.ID).Return(oidcProviderWithUsers, nil)
+	mockDB.EXPECT().DeleteOIDCProvider(gomock.Eq(goodOIDCProvider)).Return(nil)
+	mockDB.EXPECT().DeleteOIDCProvider(gomock.Eq(oidcProviderWithUsers)).Return(nil)
+	mockDB.EXPECT().GetOIDCProviderUsers(goodOIDCProvider.ID).Return(nil, nil)
+	mockDB.EXPECT().GetOIDCProviderUsers(oidcProviderWithUsers.ID).Return(model.Users{oidcEnabledUser}, nil)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user model.User) error {
+		require.False(t, user.OIDCProviderID.Valid)
+		return nil
+	})
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	// Happy path
+	test.Request(t).
+		WithMethod(http.MethodDelete).
+		WithURL(fmt.Sprintf(oidcProviderPathFmt, goodOIDCProvider.ID)).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableOIDCProviderID: fmt.Sprintf("%d", goodOIDCProvider.ID),
+		}).
+		OnHandlerFunc(resources.DeleteOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Provider with bound users has them disassociated before deletion
+	test.Request(t).
+		WithMethod(http.MethodDelete).
+		WithURL(fmt.Sprintf(oidcProviderPathFmt, oidcProviderWithUsers.ID)).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableOIDCProviderID: fmt.Sprintf("%d", oidcProviderWithUsers.ID),
+		}).
+		OnHandlerFunc(resources.DeleteOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Negative path where the provider ID is malformed
+	test.Request(t).
+		WithMethod(http.MethodDelete).
+		WithURL("/api/v2/oidc/providers/abc").
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableOIDCProviderID: "abc",
+		}).
+		OnHandlerFunc(resources.DeleteOIDCProvider).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
+
+func TestManagementResource_UpdateOIDCProviderProvisioning(t *testing.T) {
+	var (
+		oidcProvider = model.OIDCProvider{
+			Name: "corp",
+			Serial: model.Serial{
+				ID: 1,
+			},
+		}
+
+		goodRequest = v2.UpdateOIDCProviderProvisioningRequest{
+			AutoProvision: true,
+			RoleClaim:     "groups",
+			RoleMappings: []v2.OIDCRoleMappingRequest{
+				{ClaimValue: "sec-bh-admins", RoleID: 1},
+			},
+		}
+
+		requestContext    = &ctx.Context{Host: &url.URL{Scheme: "https", Host: "example.com"}}
+		provisioningPath  = fmt.Sprintf(oidcProviderPathFmt+"/provisioning", oidcProvider.ID)
+		mockCtrl          = gomock.NewController(t)
+		resources, mockDB = apitest.NewAuthManagementResource(mockCtrl)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().GetOIDCProvider(oidcProvider.ID).Return(oidcProvider, nil).Times(3)
+	mockDB.EXPECT().GetRoles([]int32{1}).Return(model.Roles{{Name: "Administrator", Serial: model.Serial{ID: 1}}}, nil)
+	mockDB.EXPECT().GetRoles([]int32{7}).Return(model.Roles{}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "UpdateOIDCProviderProvisioning", gomock.Any()).Return(nil)
+	mockDB.EXPECT().UpdateOIDCProvider(gomock.Any()).DoAndReturn(func(provider model.OIDCProvider) error {
+		require.True(t, provider.AutoProvision)
+		require.Equal(t, "groups", provider.RoleClaim)
+		require.Equal(t, model.OIDCRoleMappings{{OIDCProviderID: 1, ClaimValue: "sec-bh-admins", RoleID: 1}}, provider.RoleMappings)
+		return nil
+	})
+
+	// Happy path
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL(provisioningPath).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableOIDCProviderID: fmt.Sprintf("%d", oidcProvider.ID),
+		}).
+		WithBody(goodRequest).
+		OnHandlerFunc(resources.UpdateOIDCProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Negative path where automatic provisioning is requested without role mappings
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL(provisioningPath).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableOIDCProviderID: fmt.Sprintf("%d", oidcProvider.ID),
+		}).
+		WithBody(v2.UpdateOIDCProviderProvisioningRequest{AutoProvision: true, RoleClaim: "groups"}).
+		OnHandlerFunc(resources.UpdateOIDCProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where role mappings are requested without a role claim
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL(provisioningPath).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableOIDCProviderID: fmt.Sprintf("%d", oidcProvider.ID),
+		}).
+		WithBody(v2.UpdateOIDCProviderProvisioningRequest{RoleMappings: goodRequest.RoleMappings}).
+		OnHandlerFunc(resources.UpdateOIDCProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where a role mapping refers to an unknown role
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL(provisioningPath).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableOIDCProviderID: fmt.Sprintf("%d", oidcProvider.ID),
+		}).
+		WithBody(v2.UpdateOIDCProviderProvisioningRequest{
+			RoleClaim:    "groups",
+			RoleMappings: []v2.OIDCRoleMappingRequest{{ClaimValue: "sec-bh-admins", RoleID: 7}},
+		}).
+		OnHandlerFunc(resources.UpdateOIDCProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+}
+
+func TestManagementResource_UpdateSAMLProviderProvisioning(t *testing.T) {
+	var (
+		samlProvider = model.SAMLProvider{
+			Name: "corp",
+			Serial: model.Serial{
+				ID: 1,
+			},
+		}
+
+		goodRequest = v2.UpdateSAMLProviderProvisioningRequest{
+			AutoProvision: true,
+			RoleAttribute: "urn:oid:1.3.6.1.4.1.5923.1.1.1.1",
+			RoleMappings: []v2.SAMLRoleMappingRequest{
+				{AttributeValue: "sec-bh-admins", RoleID: 1},
+			},
+		}
+
+		requestContext    = &ctx.Context{Host: &url.URL{Scheme: "https", Host: "example.com"}}
+		provisioningPath  = fmt.Sprintf(samlProviderPathFmt+"/provisioning", samlProvider.ID)
+		mockCtrl          = gomock.NewController(t)
+		resources, mockDB = apitest.NewAuthManagementResource(mockCtrl)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().GetSAMLProvider(samlProvider.ID).Return(samlProvider, nil).Times(3)
+	mockDB.EXPECT().GetRoles([]int32{1}).Return(model.Roles{{Name: "Administrator", Serial: model.Serial{ID: 1}}}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "UpdateSAMLProviderProvisioning", gomock.Any()).Return(nil)
+	mockDB.EXPECT().UpdateSAMLIdentityProvider(gomock.Any()).DoAndReturn(func(provider model.SAMLProvider) error {
+		require.True(t, provider.AutoProvision)
+		require.Equal(t, goodRequest.RoleAttribute, provider.RoleAttribute)
+		require.Equal(t, model.SAMLRoleMappings{{SAMLProviderID: 1, AttributeValue: "sec-bh-admins", RoleID: 1}}, provider.RoleMappings)
+		return nil
+	})
+
+	// Happy path
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL(provisioningPath).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableSAMLProviderID: fmt.Sprintf("%d", samlProvider.ID),
+		}).
+		WithBody(goodRequest).
+		OnHandlerFunc(resources.UpdateSAMLProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Negative path where automatic provisioning is requested without role mappings
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL(provisioningPath).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableSAMLProviderID: fmt.Sprintf("%d", samlProvider.ID),
+		}).
+		WithBody(v2.UpdateSAMLProviderProvisioningRequest{AutoProvision: true, RoleAttribute: goodRequest.RoleAttribute}).
+		OnHandlerFunc(resources.UpdateSAMLProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where a role mapping has no attribute value
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL(provisioningPath).
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableSAMLProviderID: fmt.Sprintf("%d", samlProvider.ID),
+		}).
+		WithBody(v2.UpdateSAMLProviderProvisioningRequest{
+			RoleAttribute: goodRequest.RoleAttribute,
+			RoleMappings:  []v2.SAMLRoleMappingRequest{{RoleID: 1}},
+		}).
+		OnHandlerFunc(resources.UpdateSAMLProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where the provider ID is malformed
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL("/api/v2/saml/providers/abc/provisioning").
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableSAMLProviderID: "abc",
+		}).
+		WithBody(goodRequest).
+		OnHandlerFunc(resources.UpdateSAMLProviderProvisioning).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
+
+func TestManagementResource_CreateSCIMToken(t *testing.T) {
+	var (
+		user = model.User{
+			PrincipalName: "admin",
+			Unique: model.Unique{
+				ID: must.NewUUIDv4(),
+			},
+		}
+
+		requestContext    = &ctx.Context{Host: &url.URL{Scheme: "https", Host: "example.com"}, AuthCtx: authz.Context{Owner: user}}
+		mockCtrl          = gomock.NewController(t)
+		resources, mockDB = apitest.NewAuthManagementResource(mockCtrl)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().GetOIDCProvider(int32(1)).Return(model.OIDCProvider{Serial: model.Serial{ID: 1}}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "CreateSCIMToken", gomock.Any()).Return(nil)
+	mockDB.EXPECT().CreateSCIMToken(gomock.Any()).DoAndReturn(func(scimToken model.SCIMToken) (model.SCIMToken, error) {
+		require.Equal(t, user.ID, scimToken.UserID)
+		require.Equal(t, "governance", scimToken.Name)
+		require.Equal(t, null.Int32From(1), scimToken.OIDCProviderID)
+		require.NotEmpty(t, scimToken.Digest)
+		return scimToken, nil
+	})
+
+	// Happy path
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/scim/tokens").
+		WithBody(v2.CreateSCIMTokenRequest{Name: "governance", OIDCProviderID: null.Int32From(1)}).
+		OnHandlerFunc(resources.CreateSCIMToken).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Negative path where the token name is missing
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/scim/tokens").
+		WithBody(v2.CreateSCIMTokenRequest{}).
+		OnHandlerFunc(resources.CreateSCIMToken).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where the token is bound to more than one SSO provider
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPost).
+		WithURL("/api/v2/scim/tokens").
+		WithBody(v2.CreateSCIMTokenRequest{Name: "governance", SAMLProviderID: null.Int32From(1), OIDCProviderID: null.Int32From(1)}).
+		OnHandlerFunc(resources.CreateSCIMToken).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+}
+
+func TestManagementResource_UpdateSCIMGroupRole(t *testing.T) {
+	var (
+		adminRole = model.Role{Name: "Administrator", Serial: model.Serial{ID: 1}}
+		member    = model.User{
+			PrincipalName: "bjensen@example.com",
+			Roles:         model.Roles{},
+			Unique: model.Unique{
+				ID: must.NewUUIDv4(),
+			},
+		}
+		scimGroup = model.SCIMGroup{
+			DisplayName: "sec-bh-admins",
+			Members:     model.SCIMGroupMembers{{SCIMGroupID: 2, UserID: member.ID}},
+			Serial: model.Serial{
+				ID: 2,
+			},
+		}
+
+		requestContext    = &ctx.Context{Host: &url.URL{Scheme: "https", Host: "example.com"}}
+		mockCtrl          = gomock.NewController(t)
+		resources, mockDB = apitest.NewAuthManagementResource(mockCtrl)
+	)
+
+	defer mockCtrl.Finish()
+
+	mappedSCIMGroup := scimGroup
+	mappedSCIMGroup.RoleID = null.Int32From(adminRole.ID)
+
+	mockDB.EXPECT().GetSCIMGroup(scimGroup.ID).Return(scimGroup, nil).Times(2)
+	mockDB.EXPECT().GetRoles([]int32{adminRole.ID}).Return(model.Roles{adminRole}, nil).Times(2)
+	mockDB.EXPECT().GetRoles([]int32{7}).Return(model.Roles{}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "UpdateSCIMGroupRole", gomock.Any()).Return(nil)
+	mockDB.EXPECT().UpdateSCIMGroup(mappedSCIMGroup).Return(nil)
+	mockDB.EXPECT().GetAllSCIMGroups().Return(model.SCIMGroups{mappedSCIMGroup}, nil)
+	mockDB.EXPECT().GetUser(member.ID).Return(member, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "SyncSCIMUserRoles", gomock.Any()).Return(nil)
+	mockDB.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user model.User) error {
+		require.Equal(t, model.Roles{adminRole}, user.Roles)
+		return nil
+	})
+
+	// Happy path where mapping the group to a role grants the role to its members
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL("/api/v2/scim/groups/2/role").
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableSCIMGroupID: "2",
+		}).
+		WithBody(v2.UpdateSCIMGroupRoleRequest{RoleID: null.Int32From(adminRole.ID)}).
+		OnHandlerFunc(resources.UpdateSCIMGroupRole).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Negative path where the role does not exist
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL("/api/v2/scim/groups/2/role").
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableSCIMGroupID: "2",
+		}).
+		WithBody(v2.UpdateSCIMGroupRoleRequest{RoleID: null.Int32From(7)}).
+		OnHandlerFunc(resources.UpdateSCIMGroupRole).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Negative path where the group ID is malformed
+	test.Request(t).
+		WithContext(requestContext).
+		WithMethod(http.MethodPut).
+		WithURL("/api/v2/scim/groups/abc/role").
+		WithURLPathVars(map[string]string{
+			api.URIPathVariableSCIMGroupID: "abc",
+		}).
+		WithBody(v2.UpdateSCIMGroupRoleRequest{RoleID: null.Int32From(adminRole.ID)}).
+		OnHandlerFunc(resources.UpdateSCIMGroupRole).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+}
+
+func TestManagementResource_UpdateUser_MultipleSSOProviders(t *testing.T) {
+	var (
+		goodRoles         = []int32{0}
+		userID            = must.NewUUIDv4()
+		mockCtrl          = gomock.NewController(t)
+		resources, mockDB = apitest.NewAuthManagementResource(mockCtrl)
+	)
+
+	defer mockCtrl.Finish()
+
+	mockDB.EXPECT().GetUser(userID).Return(model.User{}, nil)
+	mockDB.EXPECT().GetRoles(gomock.Eq(goodRoles)).Return(model.Roles{}, nil)
+
+	test.Request(t).
+		WithMethod(http.MethodPut).
+		WithURL(fmt.Sprintf(updateUserPathFmt, userID.String())).
+		WithURLPathVars(map[string]string{
+			"user_id": userID.String(),
+		}).
+		WithBody(v2.UpdateUserRequest{
+			Principal:      "tester",
+			Roles:          goodRoles,
+			SAMLProviderID: samlProviderIDStr,
+			OIDCProviderID: "1",
+		}).
+		OnHandlerFunc(resources.UpdateUser).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+}
+
 func TestManagementResource_ListPermissions_SortingError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -685,6 +1227,249 @@ func TestManagementResource_ListRoles_Filtered(t *testing.T) {
 	}
 }
 
+func TestManagementResource_CreateRole(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		endpoint    = "/api/v2/roles"
+		permissions = authz.Permissions()
+		graphRead   = permissions.GraphDBRead
+	)
+
+	graphRead.ID = 1
+
+	resources, mockDB := apitest.NewAuthManagementResource(mockCtrl)
+	mockDB.EXPECT().GetAllPermissions("", model.SQLFilter{}).Return(model.Permissions{graphRead}, nil).AnyTimes()
+	mockDB.EXPECT().LookupRoleByName("Taken").Return(model.Role{Name: "Taken", Serial: model.Serial{ID: 7}}, nil)
+	mockDB.EXPECT().LookupRoleByName("EMEA Analysts").Return(model.Role{}, database.ErrNotFound)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "CreateRole", gomock.Any()).Return(nil)
+	mockDB.EXPECT().CreateRole(model.Role{
+		Name:        "EMEA Analysts",
+		Permissions: model.Permissions{graphRead},
+		Environments: model.RoleEnvironments{
+			{EnvironmentID: "S-1-5-21-1"},
+		},
+		Custom: true,
+	}).Return(model.Role{Name: "EMEA Analysts", Custom: true}, nil)
+
+	cases := []struct {
+		Name           string
+		Input          v2.UpsertRoleRequest
+		ExpectedStatus int
+		ExpectedBody   string
+	}{
+		{
+			Name:           "missing name",
+			Input:          v2.UpsertRoleRequest{Name: " "},
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   auth.ErrorResponseDetailsRoleNameRequired,
+		},
+		{
+			Name:           "reserved name",
+			Input:          v2.UpsertRoleRequest{Name: authz.RoleAdministrator},
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   auth.ErrorResponseDetailsRoleNameReserved,
+		},
+		{
+			Name:           "unknown permission",
+			Input:          v2.UpsertRoleRequest{Name: "EMEA Analysts", Permissions: []int32{2}},
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   auth.ErrorResponseDetailsPermissionInvalid,
+		},
+		{
+			Name:           "blank environment",
+			Input:          v2.UpsertRoleRequest{Name: "EMEA Analysts", Environments: []string{""}},
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   auth.ErrorResponseDetailsEnvironmentInvalid,
+		},
+		{
+			Name:           "name in use",
+			Input:          v2.UpsertRoleRequest{Name: "Taken"},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedBody:   auth.ErrorResponseDetailsRoleNameInUse,
+		},
+		{
+			Name:           "success",
+			Input:          v2.UpsertRoleRequest{Name: "EMEA Analysts", Permissions: []int32{1, 1}, Environments: []string{"s-1-5-21-1", "S-1-5-21-1"}},
+			ExpectedStatus: http.StatusCreated,
+			ExpectedBody:   "EMEA Analysts",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), ctx.ValueKey, &ctx.Context{})
+			if payload, err := json.Marshal(tc.Input); err != nil {
+				t.Fatal(err)
+			} else if req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload)); err != nil {
+				t.Fatal(err)
+			} else {
+				req.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+				router := mux.NewRouter()
+				router.HandleFunc(endpoint, resources.CreateRole).Methods("POST")
+
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+
+				require.Equal(t, tc.ExpectedStatus, rr.Code)
+				require.Contains(t, rr.Body.String(), tc.ExpectedBody)
+			}
+		})
+	}
+}
+
+func TestManagementResource_UpdateRole(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		endpoint   = fmt.Sprintf("/api/v2/roles/{%s}", api.URIPathVariableRoleID)
+		customRole = model.Role{
+			Name:   "EMEA Analysts",
+			Custom: true,
+			Environments: model.RoleEnvironments{
+				{RoleID: 2, EnvironmentID: "S-1-5-21-1"},
+			},
+			Serial: model.Serial{ID: 2},
+		}
+	)
+
+	resources, mockDB := apitest.NewAuthManagementResource(mockCtrl)
+	mockDB.EXPECT().GetRole(int32(1)).Return(model.Role{Name: authz.RoleUser, Serial: model.Serial{ID: 1}}, nil)
+	mockDB.EXPECT().GetRole(int32(2)).Return(customRole, nil)
+	mockDB.EXPECT().GetAllPermissions("", model.SQLFilter{}).Return(model.Permissions{}, nil)
+	mockDB.EXPECT().LookupRoleByName("EMEA Analysts").Return(customRole, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "UpdateRole", gomock.Any()).Return(nil)
+	mockDB.EXPECT().UpdateRole(model.Role{
+		Name:        "EMEA Analysts",
+		Description: "Regional team",
+		Permissions: model.Permissions{},
+		Environments: model.RoleEnvironments{
+			{RoleID: 2, EnvironmentID: "TENANT-1"},
+		},
+		Custom: true,
+		Serial: model.Serial{ID: 2},
+	}).Return(nil)
+
+	cases := []struct {
+		Name           string
+		RoleID         string
+		ExpectedStatus int
+		ExpectedBody   string
+	}{
+		{
+			Name:           "malformed id",
+			RoleID:         "abc",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   api.ErrorResponseDetailsIDMalformed,
+		},
+		{
+			Name:           "built-in role",
+			RoleID:         "1",
+			ExpectedStatus: http.StatusConflict,
+			ExpectedBody:   auth.ErrorResponseDetailsRoleBuiltIn,
+		},
+		{
+			Name:           "success",
+			RoleID:         "2",
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   "TENANT-1",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), ctx.ValueKey, &ctx.Context{})
+			payload := v2.UpsertRoleRequest{
+				Name:         "EMEA Analysts",
+				Description:  "Regional team",
+				Environments: []string{"tenant-1"},
+			}
+
+			if body, err := json.Marshal(payload); err != nil {
+				t.Fatal(err)
+			} else if req, err := http.NewRequestWithContext(ctx, "PUT", "/api/v2/roles/"+tc.RoleID, bytes.NewReader(body)); err != nil {
+				t.Fatal(err)
+			} else {
+				req.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+				router := mux.NewRouter()
+				router.HandleFunc(endpoint, resources.UpdateRole).Methods("PUT")
+
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+
+				require.Equal(t, tc.ExpectedStatus, rr.Code)
+				require.Contains(t, rr.Body.String(), tc.ExpectedBody)
+			}
+		})
+	}
+}
+
+func TestManagementResource_DeleteRole(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		endpoint     = fmt.Sprintf("/api/v2/roles/{%s}", api.URIPathVariableRoleID)
+		assignedRole = model.Role{Name: "Assigned", Custom: true, Serial: model.Serial{ID: 2}}
+		unusedRole   = model.Role{Name: "Unused", Custom: true, Serial: model.Serial{ID: 3}}
+	)
+
+	resources, mockDB := apitest.NewAuthManagementResource(mockCtrl)
+	mockDB.EXPECT().GetRole(int32(1)).Return(model.Role{Name: authz.RoleAdministrator, Serial: model.Serial{ID: 1}}, nil)
+	mockDB.EXPECT().GetRole(int32(2)).Return(assignedRole, nil)
+	mockDB.EXPECT().GetRole(int32(3)).Return(unusedRole, nil)
+	mockDB.EXPECT().GetRole(int32(4)).Return(model.Role{}, database.ErrNotFound)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "DeleteRole", gomock.Any()).Return(nil).Times(2)
+	mockDB.EXPECT().DeleteRole(assignedRole).Return(database.ErrRoleInUse)
+	mockDB.EXPECT().DeleteRole(unusedRole).Return(nil)
+
+	cases := []struct {
+		Name           string
+		RoleID         string
+		ExpectedStatus int
+	}{
+		{
+			Name:           "built-in role",
+			RoleID:         "1",
+			ExpectedStatus: http.StatusConflict,
+		},
+		{
+			Name:           "assigned role",
+			RoleID:         "2",
+			ExpectedStatus: http.StatusConflict,
+		},
+		{
+			Name:           "success",
+			RoleID:         "3",
+			ExpectedStatus: http.StatusOK,
+		},
+		{
+			Name:           "not found",
+			RoleID:         "4",
+			ExpectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), ctx.ValueKey, &ctx.Context{})
+			if req, err := http.NewRequestWithContext(ctx, "DELETE", "/api/v2/roles/"+tc.RoleID, nil); err != nil {
+				t.Fatal(err)
+			} else {
+				router := mux.NewRouter()
+				router.HandleFunc(endpoint, resources.DeleteRole).Methods("DELETE")
+
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+
+				require.Equal(t, tc.ExpectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
 func TestExpireUserAuthSecret_Failure(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -0,0 +1,265 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/specterops/bloodhound/src/api"
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/auth/bhoidc"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/serde"
+)
+
+const (
+	ErrorResponseDetailsOIDCProviderNameInvalid = "provider name is required and may only contain URL path safe characters"
+	ErrorResponseDetailsOIDCProviderNameInUse   = "provider name is already in use"
+	ErrorResponseDetailsOIDCIssuerInvalid       = "issuer URI must be an absolute http or https URL"
+	ErrorResponseDetailsOIDCClientIDRequired    = "client ID is required"
+	ErrorResponseDetailsOIDCRoleClaimRequired   = "a role claim is required when role mappings are defined"
+	ErrorResponseDetailsOIDCRoleMappingInvalid  = "role mappings require a claim value and a valid role id"
+)
+
+func (s ManagementResource) ListOIDCSignOnEndpoints(response http.ResponseWriter, request *http.Request) {
+	if oidcProviders, err := bhoidc.GetAllOIDCProviders(s.db, request.Context()); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		oidcSignOnEndpoints := make([]v2.OIDCSignOnEndpoint, len(oidcProviders))
+
+		for idx, oidcProvider := range oidcProviders {
+			oidcSignOnEndpoints[idx].Name = oidcProvider.Name
+			oidcSignOnEndpoints[idx].DisplayName = oidcProvider.DisplayName
+			oidcSignOnEndpoints[idx].InitiationURL = oidcProvider.LoginURI
+		}
+
+		api.WriteBasicResponse(request.Context(), v2.ListOIDCSignOnEndpointsResponse{
+			Endpoints: oidcSignOnEndpoints,
+		}, http.StatusOK, response)
+	}
+}
+
+func (s ManagementResource) ListOIDCProviders(response http.ResponseWriter, request *http.Request) {
+	if oidcProviders, err := bhoidc.GetAllOIDCProviders(s.db, request.Context()); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), v2.ListOIDCProvidersResponse{OIDCProviders: oidcProviders}, http.StatusOK, response)
+	}
+}
+
+func (s ManagementResource) GetOIDCProvider(response http.ResponseWriter, request *http.Request) {
+	if providerID, err := strconv.ParseInt(mux.Vars(request)[api.URIPathVariableOIDCProviderID], 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound, request), response)
+	} else if provider, err := bhoidc.GetOIDCProviderByID(s.db, int32(providerID), request.Context()); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), provider, http.StatusOK, response)
+	}
+}
+
+func validOIDCIssuerURI(rawIssuerURI string) bool {
+	if issuerURI, err := url.Parse(rawIssuerURI); err != nil {
+		return false
+	} else {
+		return issuerURI.IsAbs() && issuerURI.Host != "" && (issuerURI.Scheme == "https" || issuerURI.Scheme == "http")
+	}
+}
+
+// buildOIDCRoleMappings validates the requested role mappings against the roles defined in the database
+func (s ManagementResource) buildOIDCRoleMappings(roleMappingRequests []v2.OIDCRoleMappingRequest) (model.OIDCRoleMappings, string, error) {
+	var (
+		roleMappings = make(model.OIDCRoleMappings, 0, len(roleMappingRequests))
+		roleIDs      []int32
+	)
+
+	for _, roleMappingRequest := range roleMappingRequests {
+		if strings.TrimSpace(roleMappingRequest.ClaimValue) == "" {
+			return nil, ErrorResponseDetailsOIDCRoleMappingInvalid, nil
+		}
+
+		roleMappings = append(roleMappings, model.OIDCRoleMapping{
+			ClaimValue: roleMappingRequest.ClaimValue,
+			RoleID:     roleMappingRequest.RoleID,
+		})
+
+		roleIDs = append(roleIDs, roleMappingRequest.RoleID)
+	}
+
+	if rolesExist, err := s.rolesExist(roleIDs); err != nil {
+		return nil, "", err
+	} else if !rolesExist {
+		return nil, ErrorResponseDetailsOIDCRoleMappingInvalid, nil
+	}
+
+	return roleMappings, "", nil
+}
+
+func (s ManagementResource) CreateOIDCProvider(response http.ResponseWriter, request *http.Request) {
+	var createRequest v2.CreateOIDCProviderRequest
+
+	if err := api.ReadJSONRequestPayloadLimited(&createRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if createRequest.Name == "" || url.PathEscape(createRequest.Name) != createRequest.Name {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsOIDCProviderNameInvalid, request), response)
+	} else if !validOIDCIssuerURI(createRequest.IssuerURI) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsOIDCIssuerInvalid, request), response)
+	} else if createRequest.ClientID == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsOIDCClientIDRequired, request), response)
+	} else if len(createRequest.RoleMappings) > 0 && createRequest.RoleClaim == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsOIDCRoleClaimRequired, request), response)
+	} else if createRequest.AutoProvision && len(createRequest.RoleMappings) == 0 {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsAutoProvisionRoleMappingsRequired, request), response)
+	} else if _, err := s.db.LookupOIDCProviderByName(createRequest.Name); err == nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorResponseDetailsOIDCProviderNameInUse, request), response)
+	} else if !errors.Is(err, database.ErrNotFound) {
+		api.HandleDatabaseError(request, response, err)
+	} else if roleMappings, errMsg, err := s.buildOIDCRoleMappings(createRequest.RoleMappings); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if errMsg != "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, errMsg, request), response)
+	} else if _, err := bhoidc.FetchDiscoveryDocument(s.httpClient, request.Context(), createRequest.IssuerURI); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("unable to validate OIDC provider discovery document: %v", err), request), response)
+	} else {
+		oidcProvider := model.OIDCProvider{
+			Name:           createRequest.Name,
+			DisplayName:    createRequest.DisplayName,
+			IssuerURI:      createRequest.IssuerURI,
+			ClientID:       createRequest.ClientID,
+			ClientSecret:   createRequest.ClientSecret,
+			Scopes:         strings.Join(bhoidc.Scopes(createRequest.Scopes), " "),
+			PrincipalClaim: createRequest.PrincipalClaim,
+			RoleClaim:      createRequest.RoleClaim,
+			RoleMappings:   roleMappings,
+			AutoProvision:  createRequest.AutoProvision,
+		}
+
+		if oidcProvider.DisplayName == "" {
+			oidcProvider.DisplayName = oidcProvider.Name
+		}
+
+		if createRequest.Scopes == "" {
+			oidcProvider.Scopes = bhoidc.DefaultScopes
+		}
+
+		if oidcProvider.PrincipalClaim == "" {
+			oidcProvider.PrincipalClaim = bhoidc.DefaultPrincipalClaim
+		}
+
+		if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "CreateOIDCProvider", oidcProvider); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if newOIDCProvider, err := s.db.CreateOIDCProvider(oidcProvider); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			providerURLs := bhoidc.FormatRelyingPartyURLs(*ctx.FromRequest(request).Host, newOIDCProvider.Name)
+
+			newOIDCProvider.LoginURI = serde.FromURL(providerURLs.Login)
+			newOIDCProvider.CallbackURI = serde.FromURL(providerURLs.Callback)
+
+			api.WriteBasicResponse(request.Context(), newOIDCProvider, http.StatusOK, response)
+		}
+	}
+}
+
+func (s ManagementResource) UpdateOIDCProviderProvisioning(response http.ResponseWriter, request *http.Request) {
+	var updateRequest v2.UpdateOIDCProviderProvisioningRequest
+
+	if providerID, err := strconv.ParseInt(mux.Vars(request)[api.URIPathVariableOIDCProviderID], 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&updateRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if len(updateRequest.RoleMappings) > 0 && updateRequest.RoleClaim == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsOIDCRoleClaimRequired, request), response)
+	} else if updateRequest.AutoProvision && len(updateRequest.RoleMappings) == 0 {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsAutoProvisionRoleMappingsRequired, request), response)
+	} else if oidcProvider, err := s.db.GetOIDCProvider(int32(providerID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if roleMappings, errMsg, err := s.buildOIDCRoleMappings(updateRequest.RoleMappings); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if errMsg != "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, errMsg, request), response)
+	} else {
+		for idx := range roleMappings {
+			roleMappings[idx].OIDCProviderID = oidcProvider.ID
+		}
+
+		oidcProvider.AutoProvision = updateRequest.AutoProvision
+		oidcProvider.RoleClaim = updateRequest.RoleClaim
+		oidcProvider.RoleMappings = roleMappings
+
+		if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "UpdateOIDCProviderProvisioning", oidcProvider); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if err := s.db.UpdateOIDCProvider(oidcProvider); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if updatedProvider, err := bhoidc.GetOIDCProviderByID(s.db, oidcProvider.ID, request.Context()); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			api.WriteBasicResponse(request.Context(), updatedProvider, http.StatusOK, response)
+		}
+	}
+}
+
+func (s ManagementResource) disassociateUsersFromOIDCProvider(request *http.Request, providerUsers model.Users) error {
+	for _, user := range providerUsers {
+		user.OIDCProvider = nil
+		user.OIDCProviderID = null.NewInt32(0, false)
+
+		if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "RemoveOIDCProvider", user); err != nil {
+			return api.FormatDatabaseError(err)
+		} else if err := s.db.UpdateUser(user); err != nil {
+			return api.FormatDatabaseError(err)
+		}
+	}
+
+	return nil
+}
+
+func (s ManagementResource) DeleteOIDCProvider(response http.ResponseWriter, request *http.Request) {
+	var (
+		rawProviderID  = mux.Vars(request)[api.URIPathVariableOIDCProviderID]
+		requestContext = ctx.FromRequest(request)
+	)
+
+	if providerID, err := strconv.ParseInt(rawProviderID, 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound, request), response)
+	} else if oidcProvider, err := s.db.GetOIDCProvider(int32(providerID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if user, isUser := auth.GetUserFromAuthCtx(requestContext.AuthCtx); isUser && user.OIDCProviderID.Valid && int64(user.OIDCProviderID.Int32) == providerID {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, "user may not delete their own OIDC auth provider", request), response)
+	} else if providerUsers, err := s.db.GetOIDCProviderUsers(oidcProvider.ID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.db.AppendAuditLog(*requestContext, "DeleteOIDCProvider", oidcProvider); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.disassociateUsersFromOIDCProvider(request, providerUsers); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.db.DeleteOIDCProvider(oidcProvider); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), v2.DeleteOIDCProviderResponse{
+			AffectedUsers: providerUsers,
+		}, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/src/api"
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	"github.com/specterops/bloodhound/src/auth/bhsaml"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	ErrorResponseDetailsAutoProvisionRoleMappingsRequired = "automatic user provisioning requires at least one role mapping"
+	ErrorResponseDetailsSAMLRoleAttributeRequired         = "a role attribute is required when role mappings are defined"
+	ErrorResponseDetailsSAMLRoleMappingInvalid            = "role mappings require an attribute value and a valid role id"
+)
+
+// rolesExist returns true if every given role ID refers to a role defined in the database
+func (s ManagementResource) rolesExist(roleIDs []int32) (bool, error) {
+	if len(roleIDs) == 0 {
+		return true, nil
+	} else if roles, err := s.db.GetRoles(roleIDs); err != nil {
+		return false, err
+	} else {
+		for _, roleID := range roleIDs {
+			if !containsRoleID(roles, roleID) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
+
+func containsRoleID(roles model.Roles, roleID int32) bool {
+	for _, role := range roles {
+		if role.ID == roleID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildSAMLRoleMappings validates the requested role mappings against the roles defined in the database
+func (s ManagementResource) buildSAMLRoleMappings(providerID int32, roleMappingRequests []v2.SAMLRoleMappingRequest) (model.SAMLRoleMappings, string, error) {
+	var (
+		roleMappings = make(model.SAMLRoleMappings, 0, len(roleMappingRequests))
+		roleIDs      []int32
+	)
+
+	for _, roleMappingRequest := range roleMappingRequests {
+		if strings.TrimSpace(roleMappingRequest.AttributeValue) == "" {
+			return nil, ErrorResponseDetailsSAMLRoleMappingInvalid, nil
+		}
+
+		roleMappings = append(roleMappings, model.SAMLRoleMapping{
+			SAMLProviderID: providerID,
+			AttributeValue: roleMappingRequest.AttributeValue,
+			RoleID:         roleMappingRequest.RoleID,
+		})
+
+		roleIDs = append(roleIDs, roleMappingRequest.RoleID)
+	}
+
+	if rolesExist, err := s.rolesExist(roleIDs); err != nil {
+		return nil, "", err
+	} else if !rolesExist {
+		return nil, ErrorResponseDetailsSAMLRoleMappingInvalid, nil
+	}
+
+	return roleMappings, "", nil
+}
+
+func (s ManagementResource) UpdateSAMLProviderProvisioning(response http.ResponseWriter, request *http.Request) {
+	var updateRequest v2.UpdateSAMLProviderProvisioningRequest
+
+	if providerID, err := strconv.ParseInt(mux.Vars(request)[api.URIPathVariableSAMLProviderID], 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&updateRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if len(updateRequest.RoleMappings) > 0 && updateRequest.RoleAttribute == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsSAMLRoleAttributeRequired, request), response)
+	} else if updateRequest.AutoProvision && len(updateRequest.RoleMappings) == 0 {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsAutoProvisionRoleMappingsRequired, request), response)
+	} else if samlProvider, err := s.db.GetSAMLProvider(int32(providerID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if roleMappings, errMsg, err := s.buildSAMLRoleMappings(samlProvider.ID, updateRequest.RoleMappings); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if errMsg != "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, errMsg, request), response)
+	} else {
+		samlProvider.AutoProvision = updateRequest.AutoProvision
+		samlProvider.RoleAttribute = updateRequest.RoleAttribute
+		samlProvider.RoleMappings = roleMappings
+
+		if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "UpdateSAMLProviderProvisioning", samlProvider); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if err := s.db.UpdateSAMLIdentityProvider(samlProvider); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if updatedProvider, err := bhsaml.GetSAMLProviderByID(s.db, samlProvider.ID, request.Context()); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			api.WriteBasicResponse(request.Context(), updatedProvider, http.StatusOK, response)
+		}
+	}
+}
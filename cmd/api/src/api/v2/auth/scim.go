@@ -0,0 +1,147 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/src/api"
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/auth/bhscim"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database/types/null"
+)
+
+const (
+	ErrorResponseDetailsSCIMTokenNameRequired = "a SCIM token name is required"
+	ErrorResponseDetailsSCIMGroupRoleInvalid  = "unknown role id"
+)
+
+func (s ManagementResource) ListSCIMTokens(response http.ResponseWriter, request *http.Request) {
+	if scimTokens, err := s.db.GetAllSCIMTokens(); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), v2.ListSCIMTokensResponse{SCIMTokens: scimTokens}, http.StatusOK, response)
+	}
+}
+
+// CreateSCIMToken creates a SCIM token that acts on behalf of the requesting user. The token itself is only returned
+// in the response of this request.
+func (s ManagementResource) CreateSCIMToken(response http.ResponseWriter, request *http.Request) {
+	var createRequest v2.CreateSCIMTokenRequest
+
+	if user, isUser := auth.GetUserFromAuthCtx(ctx.FromRequest(request).AuthCtx); !isUser {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&createRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if strings.TrimSpace(createRequest.Name) == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsSCIMTokenNameRequired, request), response)
+	} else if createRequest.SAMLProviderID.Valid && createRequest.OIDCProviderID.Valid {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsMultipleSSOProviders, request), response)
+	} else if err := s.ensureSCIMTokenProviderExists(createRequest); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if scimToken, token, err := auth.NewSCIMToken(user.ID, createRequest.Name); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else {
+		scimToken.SAMLProviderID = createRequest.SAMLProviderID
+		scimToken.OIDCProviderID = createRequest.OIDCProviderID
+
+		if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "CreateSCIMToken", scimToken); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if newSCIMToken, err := s.db.CreateSCIMToken(scimToken); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			api.WriteBasicResponse(request.Context(), v2.CreateSCIMTokenResponse{SCIMToken: newSCIMToken, Token: token}, http.StatusOK, response)
+		}
+	}
+}
+
+func (s ManagementResource) ensureSCIMTokenProviderExists(createRequest v2.CreateSCIMTokenRequest) error {
+	if createRequest.SAMLProviderID.Valid {
+		_, err := s.db.GetSAMLProvider(createRequest.SAMLProviderID.Int32)
+		return err
+	} else if createRequest.OIDCProviderID.Valid {
+		_, err := s.db.GetOIDCProvider(createRequest.OIDCProviderID.Int32)
+		return err
+	}
+
+	return nil
+}
+
+func (s ManagementResource) DeleteSCIMToken(response http.ResponseWriter, request *http.Request) {
+	if scimTokenID, err := strconv.ParseInt(mux.Vars(request)[api.URIPathVariableSCIMTokenID], 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if scimToken, err := s.db.GetSCIMToken(int32(scimTokenID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "DeleteSCIMToken", scimToken); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.db.DeleteSCIMToken(scimToken); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		response.WriteHeader(http.StatusOK)
+	}
+}
+
+func optionalRoleIDs(roleID null.Int32) []int32 {
+	if roleID.Valid {
+		return []int32{roleID.Int32}
+	}
+
+	return nil
+}
+
+func (s ManagementResource) ListSCIMGroups(response http.ResponseWriter, request *http.Request) {
+	if scimGroups, err := s.db.GetAllSCIMGroups(); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), v2.ListSCIMGroupsResponse{SCIMGroups: scimGroups}, http.StatusOK, response)
+	}
+}
+
+// UpdateSCIMGroupRole maps a SCIM group to a role and synchronizes the roles of the members of the group
+func (s ManagementResource) UpdateSCIMGroupRole(response http.ResponseWriter, request *http.Request) {
+	var updateRequest v2.UpdateSCIMGroupRoleRequest
+
+	if scimGroupID, err := strconv.ParseInt(mux.Vars(request)[api.URIPathVariableSCIMGroupID], 10, 32); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&updateRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if scimGroup, err := s.db.GetSCIMGroup(int32(scimGroupID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if rolesExist, err := s.rolesExist(optionalRoleIDs(updateRequest.RoleID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !rolesExist {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorResponseDetailsSCIMGroupRoleInvalid, request), response)
+	} else {
+		updatedSCIMGroup := scimGroup
+		updatedSCIMGroup.RoleID = updateRequest.RoleID
+
+		if err := s.db.AppendAuditLog(*ctx.FromRequest(request), "UpdateSCIMGroupRole", updatedSCIMGroup); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if err := s.db.UpdateSCIMGroup(updatedSCIMGroup); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if err := bhscim.NewRoleSynchronizer(s.db).SyncRoles(*ctx.FromRequest(request), bhscim.AffectedUserIDs(scimGroup, updatedSCIMGroup)); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			api.WriteBasicResponse(request.Context(), updatedSCIMGroup, http.StatusOK, response)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/utils"
+)
+
+// DefaultChokePointsLimit is the number of the highest ranked choke points returned when no limit is requested
+const DefaultChokePointsLimit = 20
+
+// ListChokePoints returns the ranked choke points of the paths into the tier zero assets of a domain or tenant. Each
+// choke point carries the number of principals whose paths into tier zero it cuts.
+func (s Resources) ListChokePoints(response http.ResponseWriter, request *http.Request) {
+	queryParams := request.URL.Query()
+
+	if objectID, hasObjectID := mux.Vars(request)[api.URIPathVariableObjectID]; !hasObjectID {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if !requestEnvironmentScope(request).Allows(objectID) {
+		writeEnvironmentNotFound(response, request)
+	} else if limit, err := ParseLimitQueryParameter(queryParams, DefaultChokePointsLimit); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(utils.ErrorInvalidLimit, queryParams["limit"]), request), response)
+	} else if skip, err := ParseSkipQueryParameter(queryParams, 0); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(utils.ErrorInvalidSkip, queryParams["skip"]), request), response)
+	} else if chokePoints, count, err := s.DB.GetChokePoints(objectID, limit, skip); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteResponseWrapperWithPagination(request.Context(), chokePoints, limit, skip, count, http.StatusOK, response)
+	}
+}
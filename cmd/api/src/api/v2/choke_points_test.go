@@ -0,0 +1,114 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/ctx"
+	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/utils/test"
+	"go.uber.org/mock/gomock"
+)
+
+// environmentScopedContext returns the context of a user whose only role is limited to the given environments
+func environmentScopedContext(environmentIDs ...string) *ctx.Context {
+	var environments model.RoleEnvironments
+
+	for _, environmentID := range environmentIDs {
+		environments = append(environments, model.RoleEnvironment{EnvironmentID: environmentID})
+	}
+
+	return &ctx.Context{
+		AuthCtx: auth.Context{
+			Owner: model.User{Roles: model.Roles{{Environments: environments}}},
+		},
+	}
+}
+
+func TestResources_ListChokePoints(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/domains/{object_id}/choke-points").
+		WithURLPathVars(map[string]string{
+			"object_id": "S-1-5-21-1",
+		})
+
+	// Error where the limit is not a valid int
+	requestTemplate.
+		WithURLQueryVars(url.Values{"limit": []string{"test"}}).
+		OnHandlerFunc(resources.ListChokePoints).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where the skip is not a valid int
+	requestTemplate.
+		WithURLQueryVars(url.Values{"skip": []string{"test"}}).
+		OnHandlerFunc(resources.ListChokePoints).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// GetChokePoints DB fails
+	mockDB.EXPECT().GetChokePoints("S-1-5-21-1", v2.DefaultChokePointsLimit, 0).Return(nil, 0, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithURLQueryVars(url.Values{}).
+		OnHandlerFunc(resources.ListChokePoints).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Success
+	mockDB.EXPECT().GetChokePoints("S-1-5-21-1", 5, 5).Return(model.ChokePoints{{
+		EnvironmentID:      "S-1-5-21-1",
+		EnvironmentType:    model.GraphSnapshotEnvironmentActiveDirectory,
+		Rank:               6,
+		FindingType:        model.ChokePointFindingTypeRelationship,
+		ObjectID:           "S-1-5-21-1-1104",
+		Kind:               "Group",
+		RelationshipKind:   "GenericAll",
+		TargetObjectID:     "S-1-5-21-1-512",
+		TargetKind:         "Group",
+		AffectedPrincipals: 42,
+	}}, 6, nil)
+
+	requestTemplate.
+		WithURLQueryVars(url.Values{"limit": []string{"5"}, "skip": []string{"5"}}).
+		OnHandlerFunc(resources.ListChokePoints).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Error where the domain is outside of the environments of the requester
+	requestTemplate.
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		WithURLQueryVars(url.Values{}).
+		OnHandlerFunc(resources.ListChokePoints).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
@@ -20,7 +20,9 @@ import (
 	"net/http"
 
 	"github.com/specterops/bloodhound/dawgs/util"
+	"github.com/specterops/bloodhound/headers"
 	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/api/graphexport"
 	"github.com/specterops/bloodhound/src/queries"
 )
 
@@ -28,6 +30,24 @@ type CypherSearch struct {
 	Query string `json:"query"`
 }
 
+func writeCypherSearchError(response http.ResponseWriter, request *http.Request, err error) {
+	if queries.IsQueryError(err) {
+		api.WriteErrorResponse(
+			request.Context(),
+			api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response,
+		)
+	} else if util.IsNeoTimeoutError(err) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "transaction timed out, reduce query complexity or try again later", request), response)
+	} else {
+		api.WriteErrorResponse(
+			request.Context(),
+			api.BuildErrorResponse(http.StatusInternalServerError, err.Error(), request), response,
+		)
+	}
+}
+
+// CypherSearch runs a user cypher query. Results are rendered in a standard graph interchange format when one is
+// requested through the Accept header.
 func (s Resources) CypherSearch(response http.ResponseWriter, request *http.Request) {
 	var payload CypherSearch
 
@@ -36,22 +56,15 @@ func (s Resources) CypherSearch(response http.ResponseWriter, request *http.Requ
 			request.Context(),
 			api.BuildErrorResponse(http.StatusBadRequest, "JSON malformed.", request), response,
 		)
-	} else if graphResponse, err := s.GraphQuery.RawCypherSearch(request.Context(), payload.Query); err != nil {
-		if queries.IsQueryError(err) {
-			api.WriteErrorResponse(
-				request.Context(),
-				api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response,
-			)
-		} else if util.IsNeoTimeoutError(err) {
-			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "transaction timed out, reduce query complexity or try again later", request), response)
+	} else if exportFormat, exportRequested := graphexport.NegotiateFormat(request.Header.Get(headers.Accept.String())); exportRequested {
+		if pathSet, err := s.GraphQuery.RawCypherPathSearch(request.Context(), payload.Query); err != nil {
+			writeCypherSearchError(response, request, err)
 		} else {
-			api.WriteErrorResponse(
-				request.Context(),
-				api.BuildErrorResponse(http.StatusInternalServerError, err.Error(), request), response,
-			)
+			api.WriteGraphExportResponse(request.Context(), exportFormat, graphexport.FromPathSet(pathSet), http.StatusOK, response)
 		}
+	} else if graphResponse, err := s.GraphQuery.RawCypherSearch(request.Context(), payload.Query); err != nil {
+		writeCypherSearchError(response, request, err)
 	} else {
 		api.WriteBasicResponse(request.Context(), graphResponse, http.StatusOK, response)
 	}
-
 }
@@ -1,36 +1,65 @@
 // Copyright 2023 Specter Ops, Inc.
-//
+// 
 // Licensed under the Apache License, Version 2.0
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
-//
+// 
 //     http://www.apache.org/licenses/LICENSE-2.0
-//
+// 
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
 // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 // See the License for the specific language governing permissions and
 // limitations under the License.
-//
+// 
 // SPDX-License-Identifier: Apache-2.0
 
 package v2
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
-	"github.com/specterops/bloodhound/src/api"
 	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/api"
 )
 
+// QueryParameterStages names the post-processing stages to re-run when requesting analysis
+const QueryParameterStages = "stages"
+
 func (s Resources) GetDatapipeStatus(response http.ResponseWriter, request *http.Request) {
 	api.WriteBasicResponse(request.Context(), s.TaskNotifier.GetStatus(), http.StatusOK, response)
 }
 
-func (s Resources) RequestAnalysis(response http.ResponseWriter, _ *http.Request) {
+// parseStages returns the stage names of the given stages query parameter values. Each value may name several stages
+// separated by commas.
+func parseStages(values []string) []string {
+	var stages []string
+
+	for _, value := range values {
+		for _, stage := range strings.Split(value, ",") {
+			if stage = strings.TrimSpace(stage); stage != "" {
+				stages = append(stages, stage)
+			}
+		}
+	}
+
+	return stages
+}
+
+func (s Resources) RequestAnalysis(response http.ResponseWriter, request *http.Request) {
 	defer log.Measure(log.LevelDebug, "Requesting analysis")()
 
-	s.TaskNotifier.RequestAnalysis()
+	if values, hasStages := request.URL.Query()[QueryParameterStages]; !hasStages {
+		s.TaskNotifier.RequestAnalysis()
+	} else if stages := parseStages(values); len(stages) == 0 {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(api.FmtErrorResponseDetailsBadQueryParameters, "no stages specified"), request), response)
+		return
+	} else if err := s.TaskNotifier.RequestPostProcessingStages(stages); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(api.FmtErrorResponseDetailsBadQueryParameters, err), request), response)
+		return
+	}
 
 	response.WriteHeader(http.StatusAccepted)
 }
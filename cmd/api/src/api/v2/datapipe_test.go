@@ -0,0 +1,79 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/specterops/bloodhound/analysis"
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	taskerMocks "github.com/specterops/bloodhound/src/daemons/datapipe/mocks"
+	"github.com/specterops/bloodhound/src/utils/test"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResources_RequestAnalysis(t *testing.T) {
+	var (
+		mockCtrl   = gomock.NewController(t)
+		mockTasker = taskerMocks.NewMockTasker(mockCtrl)
+		resources  = v2.Resources{TaskNotifier: mockTasker}
+	)
+	defer mockCtrl.Finish()
+
+	// Full analysis is requested when no stages are specified
+	mockTasker.EXPECT().RequestAnalysis()
+
+	test.Request(t).
+		WithMethod(http.MethodPut).
+		WithURL("http://example.com/api/v2/analysis").
+		OnHandlerFunc(resources.RequestAnalysis).
+		Require().
+		ResponseStatusCode(http.StatusAccepted)
+
+	// Stages may be comma separated or repeated
+	mockTasker.EXPECT().RequestPostProcessingStages([]string{"ad-post-processing", "exposure-scoring", "choke-point-analysis"}).Return(nil)
+
+	test.Request(t).
+		WithMethod(http.MethodPut).
+		WithURL("http://example.com/api/v2/analysis").
+		WithURLQueryVars(url.Values{
+			v2.QueryParameterStages: []string{"ad-post-processing, exposure-scoring", "choke-point-analysis"},
+		}).
+		OnHandlerFunc(resources.RequestAnalysis).
+		Require().
+		ResponseStatusCode(http.StatusAccepted)
+
+	// Error where no stage names are provided
+	test.Request(t).
+		WithMethod(http.MethodPut).
+		WithURL("http://example.com/api/v2/analysis?stages=,").
+		OnHandlerFunc(resources.RequestAnalysis).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where a stage can not be re-run
+	mockTasker.EXPECT().RequestPostProcessingStages([]string{"data-quality"}).Return(analysis.ErrPostProcessingStageNotIdempotent)
+
+	test.Request(t).
+		WithMethod(http.MethodPut).
+		WithURL("http://example.com/api/v2/analysis?stages=data-quality").
+		OnHandlerFunc(resources.RequestAnalysis).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+}
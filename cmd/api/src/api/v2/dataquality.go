@@ -91,6 +91,8 @@ func (s *Resources) GetADDataQualityStats(response http.ResponseWriter, request
 
 	if id, hasDomainID := mux.Vars(request)[api.URIPathVariableDomainID]; !hasDomainID {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNoDomainId, request), response)
+	} else if !requestEnvironmentScope(request).Allows(id) {
+		writeEnvironmentNotFound(response, request)
 	} else if start, err := ParseTimeQueryParameter(queryParams, "start", defaultStart); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(ErrorInvalidRFC3339, queryParams["start"]), request), response)
 	} else if end, err := ParseTimeQueryParameter(queryParams, "end", defaultEnd); err != nil {
@@ -135,6 +137,8 @@ func (s *Resources) GetAzureDataQualityStats(response http.ResponseWriter, reque
 
 	if id, hasTenantID := mux.Vars(request)[api.URIPathVariableTenantID]; !hasTenantID {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNoTenantId, request), response)
+	} else if !requestEnvironmentScope(request).Allows(id) {
+		writeEnvironmentNotFound(response, request)
 	} else if start, err := ParseTimeQueryParameter(queryParams, "start", defaultStart); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(ErrorInvalidRFC3339, queryParams["start"]), request), response)
 	} else if end, err := ParseTimeQueryParameter(queryParams, "end", defaultEnd); err != nil {
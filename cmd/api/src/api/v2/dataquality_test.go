@@ -30,6 +30,7 @@ import (
 	"github.com/specterops/bloodhound/src/api"
 	v2 "github.com/specterops/bloodhound/src/api/v2"
 	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/utils/test"
 	"github.com/stretchr/testify/require"
 
 	"github.com/specterops/bloodhound/src/database/mocks"
@@ -275,6 +276,21 @@ func TestGetADDataQualityStats_Success(t *testing.T) {
 	}
 }
 
+func TestGetADDataQualityStats_EnvironmentScoped(t *testing.T) {
+	resources := v2.Resources{}
+
+	test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/ad-domains/{domain_id}/data-quality-stats").
+		WithURLPathVars(map[string]string{
+			"domain_id": "S-1-5-21-1",
+		}).
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		OnHandlerFunc(resources.GetADDataQualityStats).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
+
 func TestGetAzureDataQualityStats_Failure(t *testing.T) {
 
 	mockCtrl := gomock.NewController(t)
@@ -513,6 +529,21 @@ func TestGetAzureDataQualityStats_Success(t *testing.T) {
 	}
 }
 
+func TestGetAzureDataQualityStats_EnvironmentScoped(t *testing.T) {
+	resources := v2.Resources{}
+
+	test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/azure-tenants/{tenant_id}/data-quality-stats").
+		WithURLPathVars(map[string]string{
+			"tenant_id": "TENANT-1",
+		}).
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		OnHandlerFunc(resources.GetAzureDataQualityStats).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
+
 func TestGetPlatformAggregateStats_Failure(t *testing.T) {
 
 	mockCtrl := gomock.NewController(t)
@@ -17,12 +17,14 @@
 package v2
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/headers"
 	"github.com/specterops/bloodhound/log"
 	"github.com/specterops/bloodhound/slices"
 	"github.com/specterops/bloodhound/src/api"
@@ -126,9 +128,13 @@ func (s Resources) ProcessFileUpload(response http.ResponseWriter, request *http
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
 	} else if fileUploadJob, err := fileupload.GetFileUploadJobByID(s.DB, int64(fileUploadJobID)); err != nil {
 		api.HandleDatabaseError(request, response, err)
-	} else if fileName, err := fileupload.SaveToTempFile(s.Config.TempDirectory(), request.Body); err != nil {
+	} else if fileType, err := fileupload.ParseFileType(request.Header.Get(headers.ContentType.String())); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusUnsupportedMediaType, err.Error(), request), response)
+	} else if fileName, err := fileupload.SaveIngestFile(s.Config.TempDirectory(), request.Body, fileType); errors.Is(err, fileupload.ErrInvalidZipFile) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, fmt.Sprintf("Error saving ingest file: %v", err), request), response)
-	} else if _, err = ingest.CreateIngestTask(s.DB, fileName, requestContext.RequestID, int64(fileUploadJobID)); err != nil {
+	} else if _, err = ingest.CreateIngestTask(s.DB, fileName, fileupload.ParseFileName(request.Header.Get(headers.ContentDisposition.String())), fileType, requestContext.RequestID, int64(fileUploadJobID)); err != nil {
 		api.HandleDatabaseError(request, response, err)
 	} else if err = fileupload.TouchFileUploadJobLastIngest(s.DB, fileUploadJob); err != nil {
 		api.HandleDatabaseError(request, response, err)
@@ -155,3 +161,17 @@ func (s Resources) EndFileUploadJob(response http.ResponseWriter, request *http.
 		response.WriteHeader(http.StatusOK)
 	}
 }
+
+func (s Resources) ListFileUploadJobFiles(response http.ResponseWriter, request *http.Request) {
+	var fileUploadJobIdString = mux.Vars(request)[FileUploadJobIdPathParameterName]
+
+	if fileUploadJobID, err := strconv.Atoi(fileUploadJobIdString); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if _, err := fileupload.GetFileUploadJobByID(s.DB, int64(fileUploadJobID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if reports, err := fileupload.GetIngestFileReports(s.DB, int64(fileUploadJobID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), reports, http.StatusOK, response)
+	}
+}
@@ -17,20 +17,29 @@
 package v2_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"database/sql"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/mediatypes"
 	v2 "github.com/specterops/bloodhound/src/api/v2"
 	"github.com/specterops/bloodhound/src/api/v2/apitest"
 	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/config"
 	"github.com/specterops/bloodhound/src/ctx"
 	taskerMocks "github.com/specterops/bloodhound/src/daemons/datapipe/mocks"
 	dbMocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/database/types"
 	"github.com/specterops/bloodhound/src/database/types/null"
 	"github.com/specterops/bloodhound/src/model"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -224,3 +233,183 @@ func TestResources_EndFileUploadJob(t *testing.T) {
 			},
 		})
 }
+
+func TestResources_ProcessFileUpload(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbMocks.NewMockDatabase(mockCtrl)
+		workDir   = t.TempDir()
+		resources = v2.Resources{DB: mockDB, Config: config.Configuration{WorkDir: workDir}}
+	)
+	defer mockCtrl.Finish()
+
+	if err := os.MkdirAll(filepath.Join(workDir, "tmp"), 0755); err != nil {
+		t.Fatalf("error creating temp directory: %v", err)
+	}
+
+	var archive bytes.Buffer
+	archiveWriter := zip.NewWriter(&archive)
+
+	if entryWriter, err := archiveWriter.Create("users.json"); err != nil {
+		t.Fatalf("error creating archive entry: %v", err)
+	} else if _, err := entryWriter.Write([]byte(`{"meta": {"type": "users", "version": 5}, "data": []}`)); err != nil {
+		t.Fatalf("error writing archive entry: %v", err)
+	} else if err := archiveWriter.Close(); err != nil {
+		t.Fatalf("error closing archive: %v", err)
+	}
+
+	apitest.
+		NewHarness(t, resources.ProcessFileUpload).
+		Run([]apitest.Case{
+			{
+				Name: "InvalidJobID",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "invalid")
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+				},
+			},
+			{
+				Name: "UnsupportedContentType",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "123")
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.TextCsv.String())
+					apitest.BodyString(input, "data")
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetFileUploadJob(gomock.Any()).Return(model.FileUploadJob{}, nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusUnsupportedMediaType)
+				},
+			},
+			{
+				Name: "InvalidZipFile",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "123")
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationZip.String())
+					apitest.BodyString(input, "not a zip archive")
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetFileUploadJob(gomock.Any()).Return(model.FileUploadJob{}, nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, "not a valid zip archive")
+				},
+			},
+			{
+				Name: "SuccessZip",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "123")
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationZip.String())
+					apitest.BodyBytes(input, archive.Bytes())
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetFileUploadJob(gomock.Any()).Return(model.FileUploadJob{}, nil)
+					mockDB.EXPECT().CreateIngestTask(gomock.Any()).DoAndReturn(func(task model.IngestTask) (model.IngestTask, error) {
+						if task.FileType != model.FileTypeZip {
+							t.Errorf("expected zip file type but got %d", task.FileType)
+						}
+
+						return task, nil
+					})
+					mockDB.EXPECT().UpdateFileUploadJob(gomock.Any()).Return(nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusAccepted)
+				},
+			},
+			{
+				Name: "SuccessJson",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "123")
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+					apitest.BodyString(input, `{"meta": {"type": "users", "version": 5}, "data": []}`)
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetFileUploadJob(gomock.Any()).Return(model.FileUploadJob{}, nil)
+					mockDB.EXPECT().CreateIngestTask(gomock.Any()).Return(model.IngestTask{}, nil)
+					mockDB.EXPECT().UpdateFileUploadJob(gomock.Any()).Return(nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusAccepted)
+				},
+			},
+		})
+}
+
+func TestResources_ListFileUploadJobFiles(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbMocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+	)
+	defer mockCtrl.Finish()
+
+	apitest.
+		NewHarness(t, resources.ListFileUploadJobFiles).
+		Run([]apitest.Case{
+			{
+				Name: "InvalidJobID",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "invalid")
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+				},
+			},
+			{
+				Name: "GetFileUploadJobDatabaseError",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "123")
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetFileUploadJob(gomock.Any()).Return(model.FileUploadJob{}, errors.New("db error"))
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusInternalServerError)
+				},
+			},
+			{
+				Name: "GetIngestFileReportsDatabaseError",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "123")
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetFileUploadJob(gomock.Any()).Return(model.FileUploadJob{}, nil)
+					mockDB.EXPECT().GetIngestFileReportsForJob(int64(123)).Return(nil, errors.New("db error"))
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusInternalServerError)
+				},
+			},
+			{
+				Name: "Success",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, v2.FileUploadJobIdPathParameterName, "123")
+				},
+				Setup: func() {
+					validationErrors, err := types.NewJSONBObject([]model.IngestValidationError{})
+					require.Nil(t, err)
+
+					mockDB.EXPECT().GetFileUploadJob(gomock.Any()).Return(model.FileUploadJob{}, nil)
+					mockDB.EXPECT().GetIngestFileReportsForJob(int64(123)).Return(model.IngestFileReports{
+						{
+							FileUploadJobID: 123,
+							FileName:        "collection.zip/users.json",
+							DataType:        "users",
+							Version:         6,
+							Accepted:        true,
+							Errors:          validationErrors,
+						},
+					}, nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusOK)
+					apitest.BodyContains(output, "collection.zip/users.json")
+				},
+			},
+		})
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/mediatypes"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/services/grapharchive"
+	"github.com/specterops/bloodhound/src/utils"
+)
+
+const (
+	ErrorGraphArchiveDatapipeBusy = "graph archives can only be imported or exported once ingest and analysis have completed"
+	ErrorGraphArchiveUnsupported  = "the request body is not a supported graph archive"
+	ErrorGraphArchiveTruncated    = "the graph archive is incomplete"
+	ErrorGraphArchiveScoped       = "graph archives contain every environment and can not be imported or exported by users whose roles are limited to environments"
+)
+
+// graphArchiveImport is the audit entry written for a graph archive import
+type graphArchiveImport grapharchive.ImportResult
+
+// AuditData returns an AuditData data structure corresponding to the graphArchiveImport
+func (s graphArchiveImport) AuditData() model.AuditData {
+	return model.AuditData{
+		"nodes":                 s.Nodes,
+		"relationships":         s.Relationships,
+		"skipped_relationships": s.SkippedRelationships,
+	}
+}
+
+// ExportGraphArchive streams every node and relationship of the graph to the response as a graph archive. Archives
+// span every environment so they are refused to users whose roles are limited to environments.
+func (s Resources) ExportGraphArchive(response http.ResponseWriter, request *http.Request) {
+	if requestEnvironmentScope(request).Restricted {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusForbidden, ErrorGraphArchiveScoped, request), response)
+	} else if s.TaskNotifier.GetStatus().Status != model.DatapipeStatusIdle {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorGraphArchiveDatapipeBusy, request), response)
+	} else {
+		filename := fmt.Sprintf("bloodhound-graph-%s%s", time.Now().UTC().Format("20060102T150405Z"), grapharchive.FileExtension)
+
+		response.Header().Set(headers.ContentType.String(), mediatypes.ApplicationGzip.String())
+		response.Header().Set(headers.ContentDisposition.String(), fmt.Sprintf(utils.ContentDispositionAttachmentTemplate, filename))
+		response.WriteHeader(http.StatusOK)
+
+		// The response status has already been written at this point so errors can only be logged. Clients detect a
+		// failed export by the missing archive footer.
+		if result, err := grapharchive.Export(request.Context(), s.Graph, response); err != nil {
+			log.Errorf("Error exporting graph archive: %v", err)
+		} else {
+			log.Infof("Exported graph archive with %d nodes and %d relationships", result.Nodes, result.Relationships)
+		}
+	}
+}
+
+// ImportGraphArchive imports the graph archive in the request body. Imports are refused while the datapipe is
+// ingesting or analyzing so that the imported graph is not modified by a concurrent analysis run, and to users whose
+// roles are limited to environments.
+func (s Resources) ImportGraphArchive(response http.ResponseWriter, request *http.Request) {
+	if requestEnvironmentScope(request).Restricted {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusForbidden, ErrorGraphArchiveScoped, request), response)
+	} else if s.TaskNotifier.GetStatus().Status != model.DatapipeStatusIdle {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorGraphArchiveDatapipeBusy, request), response)
+	} else if result, err := grapharchive.Import(request.Context(), s.Graph, request.Body); errors.Is(err, grapharchive.ErrUnsupportedArchive) || errors.Is(err, grapharchive.ErrMalformedArchive) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorGraphArchiveUnsupported, request), response)
+	} else if errors.Is(err, grapharchive.ErrTruncatedArchive) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorGraphArchiveTruncated, request), response)
+	} else if err != nil {
+		log.Errorf("Error importing graph archive: %v", err)
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "ImportGraphArchive", graphArchiveImport(result)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), result, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"net/http"
+	"testing"
+
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	"github.com/specterops/bloodhound/src/utils/test"
+)
+
+func TestResources_ExportGraphArchive(t *testing.T) {
+	resources := v2.Resources{}
+
+	// Error where the requester is limited to environments
+	test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/graphs/archive").
+		WithContext(environmentScopedContext("S-1-5-21-1")).
+		OnHandlerFunc(resources.ExportGraphArchive).
+		Require().
+		ResponseStatusCode(http.StatusForbidden)
+}
+
+func TestResources_ImportGraphArchive(t *testing.T) {
+	resources := v2.Resources{}
+
+	// Error where the requester is limited to environments
+	test.Request(t).
+		WithMethod(http.MethodPost).
+		WithURL("http://example.com/api/v2/graphs/archive").
+		WithContext(environmentScopedContext("S-1-5-21-1")).
+		OnHandlerFunc(resources.ImportGraphArchive).
+		Require().
+		ResponseStatusCode(http.StatusForbidden)
+}
@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/ctx"
 	"github.com/specterops/bloodhound/src/model"
 	"github.com/specterops/bloodhound/src/utils"
 	"github.com/gorilla/mux"
@@ -33,6 +35,16 @@ import (
 
 type DataType int
 
+// requestEnvironmentScope returns the environments whose graph data is visible to the requester. Handlers of data that
+// belongs to a single domain or tenant respond as if the data did not exist when its environment is not visible.
+func requestEnvironmentScope(request *http.Request) model.EnvironmentScope {
+	return auth.GetEnvironmentScopeFromAuthCtx(ctx.FromRequest(request).AuthCtx)
+}
+
+func writeEnvironmentNotFound(response http.ResponseWriter, request *http.Request) {
+	api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound, request), response)
+}
+
 func ErrBadQueryParameter(request *http.Request, key string, err error) *api.ErrorWrapper {
 	return api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("query parameter \"%s\" is malformed: %v", key, err), request)
 }
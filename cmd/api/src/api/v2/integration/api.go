@@ -140,7 +140,9 @@ func (s *Context) EnableAPI(startFunc APIStartFunc) {
 		s.TestCtrl.Fatalf("Failed ensuring database: %v", err)
 	} else if err := server.MigrateDB(cfg, db, migration.ListBHModels()); err != nil {
 		s.TestCtrl.Fatalf("Failed migrating database: %v", err)
-	} else if err := server.MigrateGraph(cfg, graphDB); err != nil {
+	} else if customKinds, err := db.GetCustomKinds(); err != nil {
+		s.TestCtrl.Fatalf("Failed fetching custom kinds: %v", err)
+	} else if err := server.MigrateGraph(cfg, graphDB, customKinds.NodeKinds()...); err != nil {
 		s.TestCtrl.Fatalf("Failed migrating Graph database: %v", err)
 	} else if apiCache, err := cache.NewCache(cache.Config{MaxSize: cfg.MaxAPICacheSize}); err != nil {
 		s.TestCtrl.Fatalf("Failed to create in-memory cache for API: %v", err)
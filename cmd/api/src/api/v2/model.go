@@ -20,6 +20,7 @@ import (
 	"github.com/specterops/bloodhound/src/config"
 	"github.com/specterops/bloodhound/src/daemons/datapipe"
 	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/database/types/null"
 	"github.com/specterops/bloodhound/src/model"
 	"github.com/specterops/bloodhound/src/queries"
 	"github.com/specterops/bloodhound/src/serde"
@@ -58,6 +59,20 @@ type ListSAMLProvidersResponse struct {
 	SAMLProviders model.SAMLProviders `json:"saml_providers"`
 }
 
+type OIDCSignOnEndpoint struct {
+	Name          string    `json:"name"`
+	DisplayName   string    `json:"display_name"`
+	InitiationURL serde.URL `json:"initiation_url"`
+}
+
+type ListOIDCSignOnEndpointsResponse struct {
+	Endpoints []OIDCSignOnEndpoint `json:"endpoints"`
+}
+
+type ListOIDCProvidersResponse struct {
+	OIDCProviders model.OIDCProviders `json:"oidc_providers"`
+}
+
 type UpdateUserRequest struct {
 	FirstName      string  `json:"first_name"`
 	LastName       string  `json:"last_name"`
@@ -65,9 +80,19 @@ type UpdateUserRequest struct {
 	Principal      string  `json:"principal"`
 	Roles          []int32 `json:"roles"`
 	SAMLProviderID string  `json:"saml_provider_id"`
+	OIDCProviderID string  `json:"oidc_provider_id"`
 	IsDisabled     bool    `json:"is_disabled"`
 }
 
+// UpsertRoleRequest defines a custom role. Permissions are permission IDs and environments are the domain SIDs and Azure
+// tenant IDs whose graph data is visible to the users of the role; a role without environments can see every environment.
+type UpsertRoleRequest struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Permissions  []int32  `json:"permissions"`
+	Environments []string `json:"environments"`
+}
+
 type CreateUserRequest struct {
 	UpdateUserRequest
 	SetUserSecretRequest
@@ -77,6 +102,79 @@ type DeleteSAMLProviderResponse struct {
 	AffectedUsers model.Users `json:"affected_users"`
 }
 
+type DeleteOIDCProviderResponse struct {
+	AffectedUsers model.Users `json:"affected_users"`
+}
+
+type OIDCRoleMappingRequest struct {
+	ClaimValue string `json:"claim_value"`
+	RoleID     int32  `json:"role_id"`
+}
+
+// CreateOIDCProviderRequest registers an OpenID Connect provider. The issuer URI must serve a discovery document and
+// role mappings are only applied when a role claim is set.
+type CreateOIDCProviderRequest struct {
+	Name           string                   `json:"name"`
+	DisplayName    string                   `json:"display_name"`
+	IssuerURI      string                   `json:"issuer_uri"`
+	ClientID       string                   `json:"client_id"`
+	ClientSecret   string                   `json:"client_secret"`
+	Scopes         string                   `json:"scopes"`
+	PrincipalClaim string                   `json:"principal_claim"`
+	RoleClaim      string                   `json:"role_claim"`
+	RoleMappings   []OIDCRoleMappingRequest `json:"role_mappings"`
+	AutoProvision  bool                     `json:"auto_provision"`
+}
+
+// UpdateOIDCProviderProvisioningRequest replaces the role mappings of an OIDC provider. Automatic user provisioning
+// requires at least one role mapping.
+type UpdateOIDCProviderProvisioningRequest struct {
+	AutoProvision bool                     `json:"auto_provision"`
+	RoleClaim     string                   `json:"role_claim"`
+	RoleMappings  []OIDCRoleMappingRequest `json:"role_mappings"`
+}
+
+type SAMLRoleMappingRequest struct {
+	AttributeValue string `json:"attribute_value"`
+	RoleID         int32  `json:"role_id"`
+}
+
+// UpdateSAMLProviderProvisioningRequest replaces the role mappings of a SAML provider. Automatic user provisioning
+// requires at least one role mapping.
+type UpdateSAMLProviderProvisioningRequest struct {
+	AutoProvision bool                     `json:"auto_provision"`
+	RoleAttribute string                   `json:"role_attribute"`
+	RoleMappings  []SAMLRoleMappingRequest `json:"role_mappings"`
+}
+
+// CreateSCIMTokenRequest creates a SCIM token for an identity governance platform. Users that are created through the
+// token are bound to the given SSO provider, if any.
+type CreateSCIMTokenRequest struct {
+	Name           string     `json:"name"`
+	SAMLProviderID null.Int32 `json:"saml_provider_id"`
+	OIDCProviderID null.Int32 `json:"oidc_provider_id"`
+}
+
+// CreateSCIMTokenResponse carries the SCIM token itself, which is only available when the token is created
+type CreateSCIMTokenResponse struct {
+	model.SCIMToken
+
+	Token string `json:"token"`
+}
+
+type ListSCIMTokensResponse struct {
+	SCIMTokens model.SCIMTokens `json:"scim_tokens"`
+}
+
+type ListSCIMGroupsResponse struct {
+	SCIMGroups model.SCIMGroups `json:"scim_groups"`
+}
+
+// UpdateSCIMGroupRoleRequest maps a SCIM group to a role. A null role ID removes the mapping.
+type UpdateSCIMGroupRoleRequest struct {
+	RoleID null.Int32 `json:"role_id"`
+}
+
 type SetUserSecretRequest struct {
 	Secret             string `json:"secret" validate:"password,length=12,lower=1,upper=1,special=1,numeric=1"`
 	NeedsPasswordReset bool   `json:"needs_password_reset"`
@@ -0,0 +1,142 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/api/graphexport"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	DefaultNodeSetPathsLimit    = 100
+	MaximumNodeSetPathsLimit    = 1000
+	DefaultNodeSetPathsMaxDepth = 8
+	MaximumNodeSetPathsMaxDepth = 16
+
+	ErrorNodeSetSelectorEmpty      = "sources and targets must each set at least one of object_ids, asset_group_id or cypher"
+	ErrorNodeSetPathsLimitInvalid  = "limit must be between 1 and 1000"
+	ErrorNodeSetPathsDepthInvalid  = "max_depth must be between 1 and 16"
+	ErrorNodeSetAssetGroupNotFound = "no asset group exists with the given asset_group_id"
+)
+
+// NodeSetSelector selects a set of nodes by object ID, by asset group membership or as the nodes returned by a cypher
+// query. The selected node set is the union of the nodes selected by each populated field.
+type NodeSetSelector struct {
+	ObjectIDs    []string `json:"object_ids"`
+	AssetGroupID int32    `json:"asset_group_id"`
+	Cypher       string   `json:"cypher"`
+}
+
+func (s NodeSetSelector) IsEmpty() bool {
+	return len(s.ObjectIDs) == 0 && s.AssetGroupID == 0 && s.Cypher == ""
+}
+
+// NodeSetShortestPathsRequest describes a search for the shortest paths from any node in a set of sources to any node
+// in a set of targets. AsOf restricts the search to the relationships that were valid at the given time.
+type NodeSetShortestPathsRequest struct {
+	Sources           NodeSetSelector `json:"sources"`
+	Targets           NodeSetSelector `json:"targets"`
+	RelationshipKinds string          `json:"relationship_kinds"`
+	MaxDepth          int             `json:"max_depth"`
+	Limit             int             `json:"limit"`
+	AsOf              time.Time       `json:"as_of"`
+}
+
+func (s Resources) selectNodeSet(ctx context.Context, selector NodeSetSelector) (graph.NodeSet, error) {
+	nodes := graph.NewNodeSet()
+
+	if len(selector.ObjectIDs) > 0 {
+		if objectIDNodes, err := s.GraphQuery.FetchNodesByObjectIDs(ctx, selector.ObjectIDs...); err != nil {
+			return nil, err
+		} else {
+			nodes.AddSet(objectIDNodes)
+		}
+	}
+
+	if selector.AssetGroupID != 0 {
+		if assetGroup, err := s.DB.GetAssetGroup(selector.AssetGroupID); err != nil {
+			return nil, err
+		} else if assetGroupNodes, err := s.GraphQuery.GetAssetGroupNodes(ctx, assetGroup.Tag); err != nil {
+			return nil, err
+		} else {
+			nodes.AddSet(assetGroupNodes)
+		}
+	}
+
+	if selector.Cypher != "" {
+		if cypherPaths, err := s.GraphQuery.RawCypherPathSearch(ctx, selector.Cypher); err != nil {
+			return nil, err
+		} else {
+			nodes.AddSet(cypherPaths.AllNodes())
+		}
+	}
+
+	return nodes, nil
+}
+
+func writeNodeSetSelectionError(response http.ResponseWriter, request *http.Request, err error) {
+	if errors.Is(err, database.ErrNotFound) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, ErrorNodeSetAssetGroupNotFound, request), response)
+	} else {
+		writeCypherSearchError(response, request, err)
+	}
+}
+
+// GetShortestPathsBetweenNodeSets returns the shortest path from each source to each target that it can reach, ranked
+// by path length and capped at the request's limit
+func (s Resources) GetShortestPathsBetweenNodeSets(response http.ResponseWriter, request *http.Request) {
+	pathsRequest := NodeSetShortestPathsRequest{
+		MaxDepth: DefaultNodeSetPathsMaxDepth,
+		Limit:    DefaultNodeSetPathsLimit,
+	}
+
+	if err := api.ReadJSONRequestPayloadLimited(&pathsRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if pathsRequest.Sources.IsEmpty() || pathsRequest.Targets.IsEmpty() {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNodeSetSelectorEmpty, request), response)
+	} else if pathsRequest.Limit < 1 || pathsRequest.Limit > MaximumNodeSetPathsLimit {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNodeSetPathsLimitInvalid, request), response)
+	} else if pathsRequest.MaxDepth < 1 || pathsRequest.MaxDepth > MaximumNodeSetPathsMaxDepth {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorNodeSetPathsDepthInvalid, request), response)
+	} else if kindFilter, err := parseRelationshipKindsParamFilter(pathsRequest.RelationshipKinds); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if sources, err := s.selectNodeSet(request.Context(), pathsRequest.Sources); err != nil {
+		writeNodeSetSelectionError(response, request, err)
+	} else if targets, err := s.selectNodeSet(request.Context(), pathsRequest.Targets); err != nil {
+		writeNodeSetSelectionError(response, request, err)
+	} else if paths, err := s.GraphQuery.GetShortestPathsBetweenNodeSets(request.Context(), sources, targets, withValidAsOf(kindFilter, pathsRequest.AsOf), pathsRequest.MaxDepth, pathsRequest.Limit); err != nil {
+		if errors.Is(err, ops.ErrTraversalMemoryLimit) {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "calculating the request results exceeded memory limitations due to the volume of objects involved", request), response)
+		} else {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, err.Error(), request), response)
+		}
+	} else if exportFormat, exportRequested := graphexport.NegotiateFormat(request.Header.Get(headers.Accept.String())); exportRequested {
+		api.WriteGraphExportResponse(request.Context(), exportFormat, graphexport.FromPathSet(paths), http.StatusOK, response)
+	} else {
+		api.WriteBasicResponse(request.Context(), model.NewNodeSetPathsResult(paths), http.StatusOK, response)
+	}
+}
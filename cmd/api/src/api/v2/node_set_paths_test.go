@@ -0,0 +1,130 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/mediatypes"
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	"github.com/specterops/bloodhound/src/api/v2/apitest"
+	"github.com/specterops/bloodhound/src/database"
+	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/model"
+	queriesMocks "github.com/specterops/bloodhound/src/queries/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResources_GetShortestPathsBetweenNodeSets(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockGraph = queriesMocks.NewMockGraph(mockCtrl)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{GraphQuery: mockGraph, DB: mockDB}
+
+		userNode     = graph.NewNode(1, graph.NewProperties().Set("objectid", "USER"), ad.Entity, ad.User)
+		groupNode    = graph.NewNode(2, graph.NewProperties().Set("objectid", "GROUP"), ad.Entity, ad.Group)
+		dcNode       = graph.NewNode(3, graph.NewProperties().Set("objectid", "DC"), ad.Entity, ad.Computer)
+		memberOf     = graph.NewRelationship(4, 1, 2, graph.NewProperties(), ad.MemberOf)
+		adminTo      = graph.NewRelationship(5, 2, 3, graph.NewProperties(), ad.AdminTo)
+		assetGroupID = int32(1)
+	)
+	defer mockCtrl.Finish()
+
+	apitest.NewHarness(t, resources.GetShortestPathsBetweenNodeSets).
+		WithCommonRequest(func(input *apitest.Input) {
+			apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+		}).
+		Run([]apitest.Case{
+			{
+				Name: "EmptySources",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"targets": {"object_ids": ["DC"]}}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, v2.ErrorNodeSetSelectorEmpty)
+				},
+			},
+			{
+				Name: "InvalidLimit",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"sources": {"object_ids": ["USER"]}, "targets": {"object_ids": ["DC"]}, "limit": 0}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, v2.ErrorNodeSetPathsLimitInvalid)
+				},
+			},
+			{
+				Name: "InvalidMaxDepth",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"sources": {"object_ids": ["USER"]}, "targets": {"object_ids": ["DC"]}, "max_depth": 100}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, v2.ErrorNodeSetPathsDepthInvalid)
+				},
+			},
+			{
+				Name: "AssetGroupNotFound",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"sources": {"asset_group_id": 1}, "targets": {"object_ids": ["DC"]}}`)
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetAssetGroup(assetGroupID).Return(model.AssetGroup{}, database.ErrNotFound)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusNotFound)
+					apitest.BodyContains(output, v2.ErrorNodeSetAssetGroupNotFound)
+				},
+			},
+			{
+				Name: "Success",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"sources": {"object_ids": ["USER"], "asset_group_id": 1}, "targets": {"cypher": "MATCH (n:Computer) RETURN n"}, "limit": 10}`)
+				},
+				Setup: func() {
+					mockGraph.EXPECT().FetchNodesByObjectIDs(gomock.Any(), "USER").Return(graph.NewNodeSet(userNode), nil)
+					mockDB.EXPECT().GetAssetGroup(assetGroupID).Return(model.AssetGroup{Tag: ad.AdminTierZero}, nil)
+					mockGraph.EXPECT().GetAssetGroupNodes(gomock.Any(), ad.AdminTierZero).Return(graph.NewNodeSet(groupNode), nil)
+					mockGraph.EXPECT().RawCypherPathSearch(gomock.Any(), "MATCH (n:Computer) RETURN n").Return(graph.NewPathSet(graph.Path{Nodes: []*graph.Node{dcNode}}), nil)
+					mockGraph.EXPECT().
+						GetShortestPathsBetweenNodeSets(gomock.Any(), graph.NewNodeSet(userNode, groupNode), graph.NewNodeSet(dcNode), gomock.Any(), v2.DefaultNodeSetPathsMaxDepth, 10).
+						Return(graph.NewPathSet(
+							graph.Path{Nodes: []*graph.Node{groupNode, dcNode}, Edges: []*graph.Relationship{adminTo}},
+							graph.Path{Nodes: []*graph.Node{userNode, groupNode, dcNode}, Edges: []*graph.Relationship{memberOf, adminTo}},
+						), nil)
+				},
+				Test: func(output apitest.Output) {
+					var result model.NodeSetPathsResult
+
+					apitest.StatusCode(output, http.StatusOK)
+					apitest.UnmarshalData(output, &result)
+					apitest.Equal(output, 2, len(result.Paths))
+					apitest.Equal(output, "GROUP", result.Paths[0].Source)
+					apitest.Equal(output, "DC", result.Paths[0].Target)
+					apitest.Equal(output, []string{"1", "2", "3"}, result.Paths[1].Nodes)
+					apitest.Equal(output, 3, len(result.Nodes))
+				},
+			},
+		})
+}
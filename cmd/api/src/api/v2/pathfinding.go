@@ -17,18 +17,24 @@
 package v2
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/specterops/bloodhound/analysis"
 	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
 	"github.com/specterops/bloodhound/dawgs/query"
 	"github.com/specterops/bloodhound/graphschema/ad"
 	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/headers"
 	"github.com/specterops/bloodhound/params"
 	"github.com/specterops/bloodhound/slices"
 	"github.com/specterops/bloodhound/src/api"
 	"github.com/specterops/bloodhound/src/api/bloodhoundgraph"
+	"github.com/specterops/bloodhound/src/api/graphexport"
 	"github.com/specterops/bloodhound/src/model"
 	"github.com/specterops/bloodhound/src/queries"
 )
@@ -47,6 +53,8 @@ func (s Resources) GetPathfindingResult(response http.ResponseWriter, request *h
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Missing query parameter: end_node", request), response)
 	} else if paths, err := s.GraphQuery.GetAllShortestPaths(request.Context(), startNodeObjectID, endNodeObjectID, nil); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, fmt.Sprintf("Error: %v", err), request), response)
+	} else if exportFormat, exportRequested := graphexport.NegotiateFormat(request.Header.Get(headers.Accept.String())); exportRequested {
+		api.WriteGraphExportResponse(request.Context(), exportFormat, graphexport.FromPathSet(paths), http.StatusOK, response)
 	} else {
 		api.WriteBasicResponse(request.Context(), bloodhoundgraph.PathSetToBloodHoundGraph(paths), http.StatusOK, response)
 	}
@@ -55,6 +63,8 @@ func (s Resources) GetPathfindingResult(response http.ResponseWriter, request *h
 func writeShortestPathsResult(paths graph.PathSet, response http.ResponseWriter, request *http.Request) {
 	if paths.Len() == 0 {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, "Path not found", request), response)
+	} else if exportFormat, exportRequested := graphexport.NegotiateFormat(request.Header.Get(headers.Accept.String())); exportRequested {
+		api.WriteGraphExportResponse(request.Context(), exportFormat, graphexport.FromPathSet(paths), http.StatusOK, response)
 	} else {
 		graphResponse := model.NewUnifiedGraph()
 
@@ -119,6 +129,16 @@ func parseRelationshipKindsParamFilter(relationshipKindsParam string) (graph.Cri
 	}
 }
 
+// withValidAsOf restricts a relationship filter to the relationships that were valid at the given time. The filter is
+// returned unchanged for a zero time.
+func withValidAsOf(filter graph.Criteria, asOf time.Time) graph.Criteria {
+	if asOf.IsZero() {
+		return filter
+	}
+
+	return query.And(filter, analysis.ValidAsOfCriteria(asOf))
+}
+
 func (s Resources) GetShortestPath(response http.ResponseWriter, request *http.Request) {
 	var (
 		queryParams            = request.URL.Query()
@@ -133,13 +153,100 @@ func (s Resources) GetShortestPath(response http.ResponseWriter, request *http.R
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Missing query parameter: end_node", request), response)
 	} else if kindFilter, err := parseRelationshipKindsParamFilter(relationshipKindsParam); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
-	} else if paths, err := s.GraphQuery.GetAllShortestPaths(request.Context(), startNode, endNode, kindFilter); err != nil {
+	} else if asOf, err := ParseTimeQueryParameter(queryParams, api.QueryParameterAsOf, time.Time{}); err != nil {
+		api.WriteErrorResponse(request.Context(), ErrBadQueryParameter(request, api.QueryParameterAsOf, err), response)
+	} else if paths, err := s.GraphQuery.GetAllShortestPaths(request.Context(), startNode, endNode, withValidAsOf(kindFilter, asOf)); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, err.Error(), request), response)
 	} else {
 		writeShortestPathsResult(paths, response, request)
 	}
 }
 
+const (
+	DefaultWeightedPathsLimit = 5
+	MaximumWeightedPathsLimit = 25
+
+	ErrorWeightedPathsLimitInvalid = "limit must be between 1 and 25"
+	ErrorWeightedPathsCostInvalid  = "path costs must not be negative"
+)
+
+// WeightedShortestPathsRequest describes a search for the cheapest paths between two nodes. RelationshipKinds accepts
+// the same in|nin:Kind1,Kind2 format as the relationship_kinds query parameter of the shortest path endpoint. AsOf
+// restricts the search to the relationships that were valid at the given time.
+type WeightedShortestPathsRequest struct {
+	StartNode         string              `json:"start_node"`
+	EndNode           string              `json:"end_node"`
+	RelationshipKinds string              `json:"relationship_kinds"`
+	Limit             int                 `json:"limit"`
+	CostModel         model.PathCostModel `json:"cost_model"`
+	AsOf              time.Time           `json:"as_of"`
+}
+
+func validatePathCostModel(costModel model.PathCostModel) error {
+	validKinds := graph.Kinds(ad.Relationships()).Concatenate(azure.Relationships())
+
+	if costModel.DefaultCost < 0 {
+		return errors.New(ErrorWeightedPathsCostInvalid)
+	}
+
+	for _, kindCosts := range []map[string]float64{costModel.KindCosts, costModel.TierZeroKindCosts} {
+		for kind, cost := range kindCosts {
+			if !validKinds.ContainsOneOf(graph.StringKind(kind)) {
+				return fmt.Errorf("invalid cost model relationship kind %s: acceptable relationship kinds are: %v", kind, validKinds.Strings())
+			} else if cost < 0 {
+				return errors.New(ErrorWeightedPathsCostInvalid)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetWeightedShortestPaths returns the cheapest paths between two nodes, ordered by ascending cost, where the cost of
+// a path is the sum of the costs that the request's cost model assigns to each of its relationships
+func (s Resources) GetWeightedShortestPaths(response http.ResponseWriter, request *http.Request) {
+	weightedPathsRequest := WeightedShortestPathsRequest{
+		Limit: DefaultWeightedPathsLimit,
+		CostModel: model.PathCostModel{
+			DefaultCost: 1,
+		},
+	}
+
+	if err := api.ReadJSONRequestPayloadLimited(&weightedPathsRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if weightedPathsRequest.StartNode == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Missing request field: start_node", request), response)
+	} else if weightedPathsRequest.EndNode == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Missing request field: end_node", request), response)
+	} else if weightedPathsRequest.Limit < 1 || weightedPathsRequest.Limit > MaximumWeightedPathsLimit {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorWeightedPathsLimitInvalid, request), response)
+	} else if err := validatePathCostModel(weightedPathsRequest.CostModel); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if kindFilter, err := parseRelationshipKindsParamFilter(weightedPathsRequest.RelationshipKinds); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if weightedPaths, err := s.GraphQuery.GetWeightedShortestPaths(request.Context(), weightedPathsRequest.StartNode, weightedPathsRequest.EndNode, withValidAsOf(kindFilter, weightedPathsRequest.AsOf), weightedPathsRequest.CostModel, weightedPathsRequest.Limit); err != nil {
+		if graph.IsErrNotFound(err) {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound, request), response)
+		} else if errors.Is(err, ops.ErrTraversalMemoryLimit) {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "calculating the request results exceeded memory limitations due to the volume of objects involved", request), response)
+		} else {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, err.Error(), request), response)
+		}
+	} else if len(weightedPaths) == 0 {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, "Path not found", request), response)
+	} else if exportFormat, exportRequested := graphexport.NegotiateFormat(request.Header.Get(headers.Accept.String())); exportRequested {
+		var paths graph.PathSet
+
+		for _, weightedPath := range weightedPaths {
+			paths.AddPath(weightedPath.Path)
+		}
+
+		api.WriteGraphExportResponse(request.Context(), exportFormat, graphexport.FromPathSet(paths), http.StatusOK, response)
+	} else {
+		api.WriteBasicResponse(request.Context(), model.NewWeightedPathsResult(weightedPaths), http.StatusOK, response)
+	}
+}
+
 const (
 	searchParameterQuery = "query"
 	searchParameterType  = "type"
@@ -23,11 +23,15 @@ import (
 	"github.com/specterops/bloodhound/src/api"
 	v2 "github.com/specterops/bloodhound/src/api/v2"
 	"github.com/specterops/bloodhound/src/api/v2/apitest"
+	"github.com/specterops/bloodhound/src/model"
 	mocks_graph "github.com/specterops/bloodhound/src/queries/mocks"
 	"go.uber.org/mock/gomock"
 	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/traversal"
 	"github.com/specterops/bloodhound/errors"
 	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/mediatypes"
 )
 
 func TestResources_GetPathfindingResult(t *testing.T) {
@@ -170,6 +174,19 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.UnmarshalBody(output, &api.ErrorWrapper{})
 				},
 			},
+			{
+				Name: "InvalidAsOfParam",
+				Input: func(input *apitest.Input) {
+					apitest.AddQueryParam(input, "start_node", "someID")
+					apitest.AddQueryParam(input, "end_node", "someOtherID")
+					apitest.AddQueryParam(input, "as_of", "yesterday")
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.UnmarshalBody(output, &api.ErrorWrapper{})
+					apitest.BodyContains(output, `query parameter \"as_of\" is malformed`)
+				},
+			},
 			{
 				Name: "GraphDBGetShortestPathsError",
 				Input: func(input *apitest.Input) {
@@ -187,6 +204,25 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.BodyContains(output, "graph error")
 				},
 			},
+			{
+				Name: "AsOfSuccess",
+				Input: func(input *apitest.Input) {
+					apitest.AddQueryParam(input, "start_node", "someID")
+					apitest.AddQueryParam(input, "end_node", "someOtherID")
+					apitest.AddQueryParam(input, "as_of", "2023-06-01T12:00:00Z")
+				},
+				Setup: func() {
+					mockGraph.EXPECT().
+						GetAllShortestPaths(gomock.Any(), "someID", "someOtherID", gomock.Any()).
+						Return(graph.NewPathSet(graph.Path{
+							Nodes: []*graph.Node{graph.NewNode(1, graph.NewProperties(), ad.Computer), graph.NewNode(2, graph.NewProperties(), ad.User)},
+							Edges: []*graph.Relationship{graph.NewRelationship(1, 1, 2, graph.NewProperties(), ad.HasSession)},
+						}), nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusOK)
+				},
+			},
 			{
 				Name: "Empty Result Set",
 				Input: func(input *apitest.Input) {
@@ -331,6 +367,132 @@ func TestResources_GetShortestPath(t *testing.T) {
 		})
 }
 
+func TestResources_GetWeightedShortestPaths(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockGraph = mocks_graph.NewMockGraph(mockCtrl)
+		resources = v2.Resources{GraphQuery: mockGraph}
+
+		startNode    = graph.NewNode(1, graph.NewProperties().Set("objectid", "A"), ad.Entity, ad.User)
+		endNode      = graph.NewNode(2, graph.NewProperties().Set("objectid", "B"), ad.Entity, ad.Group)
+		relationship = graph.NewRelationship(3, 1, 2, graph.NewProperties(), ad.MemberOf)
+	)
+	defer mockCtrl.Finish()
+
+	apitest.NewHarness(t, resources.GetWeightedShortestPaths).
+		WithCommonRequest(func(input *apitest.Input) {
+			apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+		}).
+		Run([]apitest.Case{
+			{
+				Name: "MissingStartNode",
+				Input: func(input *apitest.Input) {
+					apitest.BodyStruct(input, v2.WeightedShortestPathsRequest{EndNode: "B"})
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, "Missing request field: start_node")
+				},
+			},
+			{
+				Name: "MissingEndNode",
+				Input: func(input *apitest.Input) {
+					apitest.BodyStruct(input, v2.WeightedShortestPathsRequest{StartNode: "A"})
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, "Missing request field: end_node")
+				},
+			},
+			{
+				Name: "InvalidLimit",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"start_node": "A", "end_node": "B", "limit": 100}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, v2.ErrorWeightedPathsLimitInvalid)
+				},
+			},
+			{
+				Name: "NegativeCost",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"start_node": "A", "end_node": "B", "cost_model": {"kind_costs": {"HasSession": -1}}}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, v2.ErrorWeightedPathsCostInvalid)
+				},
+			},
+			{
+				Name: "InvalidCostModelKind",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"start_node": "A", "end_node": "B", "cost_model": {"tier_zero_kind_costs": {"NotAKind": 10}}}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, "invalid cost model relationship kind NotAKind")
+				},
+			},
+			{
+				Name: "NodeNotFound",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"start_node": "A", "end_node": "B"}`)
+				},
+				Setup: func() {
+					mockGraph.EXPECT().
+						GetWeightedShortestPaths(gomock.Any(), "A", "B", gomock.Any(), model.PathCostModel{DefaultCost: 1}, v2.DefaultWeightedPathsLimit).
+						Return(nil, graph.ErrNoResultsFound)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusNotFound)
+				},
+			},
+			{
+				Name: "PathNotFound",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"start_node": "A", "end_node": "B"}`)
+				},
+				Setup: func() {
+					mockGraph.EXPECT().
+						GetWeightedShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(nil, nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusNotFound)
+					apitest.BodyContains(output, "Path not found")
+				},
+			},
+			{
+				Name: "Success",
+				Input: func(input *apitest.Input) {
+					apitest.BodyString(input, `{"start_node": "A", "end_node": "B", "limit": 1, "cost_model": {"default_cost": 2, "kind_costs": {"MemberOf": 0}}}`)
+				},
+				Setup: func() {
+					mockGraph.EXPECT().
+						GetWeightedShortestPaths(gomock.Any(), "A", "B", gomock.Any(), model.PathCostModel{DefaultCost: 2, KindCosts: map[string]float64{"MemberOf": 0}}, 1).
+						Return([]traversal.WeightedPath{{
+							Path: graph.Path{
+								Nodes: []*graph.Node{startNode, endNode},
+								Edges: []*graph.Relationship{relationship},
+							},
+							Cost: 0,
+						}}, nil)
+				},
+				Test: func(output apitest.Output) {
+					var result model.WeightedPathsResult
+
+					apitest.StatusCode(output, http.StatusOK)
+					apitest.UnmarshalData(output, &result)
+					apitest.Equal(output, 1, len(result.Paths))
+					apitest.Equal(output, []string{"1", "2"}, result.Paths[0].Nodes)
+					apitest.Equal(output, "MemberOf", result.Paths[0].Edges[0].Kind)
+					apitest.Equal(output, "A", result.Nodes["1"].ObjectId)
+				},
+			},
+		})
+}
+
 func TestResources_GetSearchResult(t *testing.T) {
 	var (
 		mockCtrl  = gomock.NewController(t)
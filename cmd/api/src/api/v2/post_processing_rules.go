@@ -0,0 +1,200 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/daemons/datapipe"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/services/postprocessing"
+)
+
+const ErrorPostProcessingRuleNameConflict = "a post-processing rule with the given name already exists"
+
+// PostProcessingRuleRequest holds the data required to create or replace a post-processing rule. Rules are enabled
+// unless Enabled is set to false.
+type PostProcessingRuleRequest struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Query         string `json:"query"`
+	EdgeKind      string `json:"edge_kind"`
+	SourceBinding string `json:"source_binding"`
+	TargetBinding string `json:"target_binding"`
+	Enabled       *bool  `json:"enabled"`
+}
+
+// AuditData returns an AuditData data structure corresponding to the PostProcessingRuleRequest
+func (s PostProcessingRuleRequest) AuditData() model.AuditData {
+	return model.AuditData{
+		"rule_name":      s.Name,
+		"query":          s.Query,
+		"edge_kind":      s.EdgeKind,
+		"source_binding": s.SourceBinding,
+		"target_binding": s.TargetBinding,
+	}
+}
+
+// Apply copies the request onto the given rule
+func (s PostProcessingRuleRequest) Apply(rule model.PostProcessingRule) model.PostProcessingRule {
+	rule.Name = s.Name
+	rule.Description = s.Description
+	rule.Query = s.Query
+	rule.EdgeKind = s.EdgeKind
+	rule.SourceBinding = s.SourceBinding
+	rule.TargetBinding = s.TargetBinding
+
+	if s.Enabled != nil {
+		rule.Enabled = *s.Enabled
+	}
+
+	return rule
+}
+
+// postProcessingRuleNameInUse returns true if a rule other than the rule with the given ID is named name
+func (s Resources) postProcessingRuleNameInUse(name string, ruleID int32) (bool, error) {
+	if rules, err := s.DB.GetAllPostProcessingRules(); err != nil {
+		return false, err
+	} else {
+		for _, rule := range rules {
+			if rule.Name == name && rule.ID != ruleID {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+// requestPostProcessingRules asks the datapipe to evaluate the post-processing rules again so that rule changes are
+// reflected in the graph without waiting for the next full analysis
+func (s Resources) requestPostProcessingRules() {
+	if err := s.TaskNotifier.RequestPostProcessingStages([]string{datapipe.StagePostProcessingRules}); err != nil {
+		log.Errorf("Failed requesting evaluation of post-processing rules: %v", err)
+	}
+}
+
+func parsePostProcessingRuleID(request *http.Request) (int32, error) {
+	if ruleID, err := strconv.ParseInt(mux.Vars(request)[api.URIPathVariablePostProcessingRuleID], 10, 32); err != nil {
+		return 0, err
+	} else {
+		return int32(ruleID), nil
+	}
+}
+
+func (s Resources) ListPostProcessingRules(response http.ResponseWriter, request *http.Request) {
+	if rules, err := s.DB.GetAllPostProcessingRules(); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), rules, http.StatusOK, response)
+	}
+}
+
+func (s Resources) GetPostProcessingRule(response http.ResponseWriter, request *http.Request) {
+	if ruleID, err := parsePostProcessingRuleID(request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if rule, err := s.DB.GetPostProcessingRule(ruleID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), rule, http.StatusOK, response)
+	}
+}
+
+// CreatePostProcessingRule validates and stores a new rule. The rule query is parsed with the same cypher frontend that
+// user queries are parsed with so that only read-only match patterns are accepted.
+func (s Resources) CreatePostProcessingRule(response http.ResponseWriter, request *http.Request) {
+	var createRequest PostProcessingRuleRequest
+
+	if err := api.ReadJSONRequestPayloadLimited(&createRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else {
+		rule := createRequest.Apply(model.PostProcessingRule{Enabled: true})
+
+		if _, err := postprocessing.PrepareRuleQuery(rule); err != nil {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+		} else if nameInUse, err := s.postProcessingRuleNameInUse(rule.Name, 0); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if nameInUse {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorPostProcessingRuleNameConflict, request), response)
+		} else if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "CreatePostProcessingRule", createRequest); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if newRule, err := s.DB.CreatePostProcessingRule(rule); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			s.requestPostProcessingRules()
+
+			ruleURL := *ctx.Get(request.Context()).Host
+			ruleURL.Path = fmt.Sprintf("/api/v2/post-processing/rules/%d", newRule.ID)
+			response.Header().Set(headers.Location.String(), ruleURL.String())
+
+			api.WriteBasicResponse(request.Context(), newRule, http.StatusCreated, response)
+		}
+	}
+}
+
+// UpdatePostProcessingRule replaces the definition of a rule. The enabled state of the rule is kept unless the request
+// sets it.
+func (s Resources) UpdatePostProcessingRule(response http.ResponseWriter, request *http.Request) {
+	var updateRequest PostProcessingRuleRequest
+
+	if ruleID, err := parsePostProcessingRuleID(request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&updateRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if existingRule, err := s.DB.GetPostProcessingRule(ruleID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		rule := updateRequest.Apply(existingRule)
+
+		if _, err := postprocessing.PrepareRuleQuery(rule); err != nil {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+		} else if nameInUse, err := s.postProcessingRuleNameInUse(rule.Name, rule.ID); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if nameInUse {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorPostProcessingRuleNameConflict, request), response)
+		} else if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "UpdatePostProcessingRule", rule); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else if err := s.DB.UpdatePostProcessingRule(rule); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			s.requestPostProcessingRules()
+			api.WriteBasicResponse(request.Context(), rule, http.StatusOK, response)
+		}
+	}
+}
+
+func (s Resources) DeletePostProcessingRule(response http.ResponseWriter, request *http.Request) {
+	if ruleID, err := parsePostProcessingRuleID(request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if rule, err := s.DB.GetPostProcessingRule(ruleID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "DeletePostProcessingRule", rule); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.DB.DeletePostProcessingRule(rule); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		s.requestPostProcessingRules()
+		response.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	taskerMocks "github.com/specterops/bloodhound/src/daemons/datapipe/mocks"
+	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/utils/test"
+	"go.uber.org/mock/gomock"
+)
+
+func validPostProcessingRuleRequest() v2.PostProcessingRuleRequest {
+	return v2.PostProcessingRuleRequest{
+		Name:          "pam admins",
+		Query:         "MATCH (g:Group {objectid: 'S-1-5-21-1-1104'})<-[:MemberOf*1..]-(u:User) MATCH (c:Computer) WHERE c.name STARTS WITH 'SRV'",
+		EdgeKind:      "AdminTo",
+		SourceBinding: "u",
+		TargetBinding: "c",
+	}
+}
+
+func TestResources_CreatePostProcessingRule(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodPost).
+		WithURL("http://example.com/api/v2/post-processing/rules")
+
+	// Error where the bindings are the same
+	sameBindings := validPostProcessingRuleRequest()
+	sameBindings.TargetBinding = sameBindings.SourceBinding
+
+	requestTemplate.
+		WithBody(sameBindings).
+		OnHandlerFunc(resources.CreatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where the query contains an updating clause
+	updatingQuery := validPostProcessingRuleRequest()
+	updatingQuery.Query = "MATCH (u:User), (c:Computer) DETACH DELETE c"
+
+	requestTemplate.
+		WithBody(updatingQuery).
+		OnHandlerFunc(resources.CreatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where the query has its own return clause
+	returningQuery := validPostProcessingRuleRequest()
+	returningQuery.Query = "MATCH (u:User), (c:Computer) RETURN u"
+
+	requestTemplate.
+		WithBody(returningQuery).
+		OnHandlerFunc(resources.CreatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where the name is in use
+	mockDB.EXPECT().GetAllPostProcessingRules().Return(model.PostProcessingRules{{Name: "pam admins", Serial: model.Serial{ID: 1}}}, nil)
+
+	requestTemplate.
+		WithBody(validPostProcessingRuleRequest()).
+		OnHandlerFunc(resources.CreatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusConflict)
+
+	// CreatePostProcessingRule DB fails
+	mockDB.EXPECT().GetAllPostProcessingRules().Return(model.PostProcessingRules{}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "CreatePostProcessingRule", gomock.Any()).Return(nil)
+	mockDB.EXPECT().CreatePostProcessingRule(gomock.Any()).Return(model.PostProcessingRule{}, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithBody(validPostProcessingRuleRequest()).
+		OnHandlerFunc(resources.CreatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+}
+
+func TestResources_UpdatePostProcessingRule(t *testing.T) {
+	var (
+		mockCtrl   = gomock.NewController(t)
+		mockDB     = dbmocks.NewMockDatabase(mockCtrl)
+		mockTasker = taskerMocks.NewMockTasker(mockCtrl)
+		resources  = v2.Resources{DB: mockDB, TaskNotifier: mockTasker}
+		disabled   = false
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodPut).
+		WithURL("http://example.com/api/v2/post-processing/rules/{rule_id}")
+
+	// Error where the rule ID is not a valid int
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"rule_id": "test",
+		}).
+		WithBody(validPostProcessingRuleRequest()).
+		OnHandlerFunc(resources.UpdatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// GetPostProcessingRule DB fails
+	mockDB.EXPECT().GetPostProcessingRule(int32(1)).Return(model.PostProcessingRule{}, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"rule_id": "1",
+		}).
+		WithBody(validPostProcessingRuleRequest()).
+		OnHandlerFunc(resources.UpdatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Success where the rule is disabled and its name is kept
+	disableRequest := validPostProcessingRuleRequest()
+	disableRequest.Enabled = &disabled
+
+	existingRule := model.PostProcessingRule{Name: "pam admins", Enabled: true, Serial: model.Serial{ID: 1}}
+	updatedRule := disableRequest.Apply(existingRule)
+
+	mockDB.EXPECT().GetPostProcessingRule(int32(1)).Return(existingRule, nil)
+	mockDB.EXPECT().GetAllPostProcessingRules().Return(model.PostProcessingRules{existingRule}, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "UpdatePostProcessingRule", updatedRule).Return(nil)
+	mockDB.EXPECT().UpdatePostProcessingRule(updatedRule).Return(nil)
+	mockTasker.EXPECT().RequestPostProcessingStages([]string{"post-processing-rules"}).Return(nil)
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"rule_id": "1",
+		}).
+		WithBody(disableRequest).
+		OnHandlerFunc(resources.UpdatePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+}
+
+func TestResources_DeletePostProcessingRule(t *testing.T) {
+	var (
+		mockCtrl   = gomock.NewController(t)
+		mockDB     = dbmocks.NewMockDatabase(mockCtrl)
+		mockTasker = taskerMocks.NewMockTasker(mockCtrl)
+		resources  = v2.Resources{DB: mockDB, TaskNotifier: mockTasker}
+		rule       = model.PostProcessingRule{Name: "pam admins", Serial: model.Serial{ID: 1}}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodDelete).
+		WithURL("http://example.com/api/v2/post-processing/rules/{rule_id}").
+		WithURLPathVars(map[string]string{
+			"rule_id": "1",
+		})
+
+	// Audit Log fails
+	mockDB.EXPECT().GetPostProcessingRule(int32(1)).Return(rule, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "DeletePostProcessingRule", rule).Return(fmt.Errorf("exploded"))
+
+	requestTemplate.
+		OnHandlerFunc(resources.DeletePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Success
+	mockDB.EXPECT().GetPostProcessingRule(int32(1)).Return(rule, nil)
+	mockDB.EXPECT().AppendAuditLog(gomock.Any(), "DeletePostProcessingRule", rule).Return(nil)
+	mockDB.EXPECT().DeletePostProcessingRule(rule).Return(nil)
+	mockTasker.EXPECT().RequestPostProcessingStages([]string{"post-processing-rules"}).Return(nil)
+
+	requestTemplate.
+		OnHandlerFunc(resources.DeletePostProcessingRule).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+}
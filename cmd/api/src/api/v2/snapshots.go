@@ -0,0 +1,167 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/headers"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/services/snapshot"
+)
+
+const (
+	ErrorSnapshotNameRequired        = "a snapshot name is required"
+	ErrorSnapshotEnvironmentRequired = "an environment_id naming the domain SID or tenant ID to capture is required"
+	ErrorSnapshotDatapipeBusy        = "snapshots can only be captured once ingest and analysis have completed"
+	ErrorSnapshotEnvironmentMismatch = "snapshots of different domains or tenants cannot be compared"
+	ErrorSnapshotUnknownEnvironment  = "no domain or tenant exists with the given environment_id"
+)
+
+// CreateGraphSnapshotRequest holds the data required to capture a graph snapshot
+type CreateGraphSnapshotRequest struct {
+	Name          string `json:"name"`
+	EnvironmentID string `json:"environment_id"`
+}
+
+// AuditData returns an AuditData data structure corresponding to the CreateGraphSnapshotRequest
+func (s CreateGraphSnapshotRequest) AuditData() model.AuditData {
+	return model.AuditData{
+		"snapshot_name":  s.Name,
+		"environment_id": s.EnvironmentID,
+	}
+}
+
+func parseSnapshotID(request *http.Request, pathVariable string) (int32, error) {
+	if snapshotID, err := strconv.ParseInt(mux.Vars(request)[pathVariable], 10, 32); err != nil {
+		return 0, err
+	} else {
+		return int32(snapshotID), nil
+	}
+}
+
+func (s Resources) ListGraphSnapshots(response http.ResponseWriter, request *http.Request) {
+	if snapshots, err := s.DB.GetGraphSnapshots(); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), snapshots.InEnvironmentScope(requestEnvironmentScope(request)), http.StatusOK, response)
+	}
+}
+
+func (s Resources) GetGraphSnapshot(response http.ResponseWriter, request *http.Request) {
+	if snapshotID, err := parseSnapshotID(request, api.URIPathVariableSnapshotID); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if graphSnapshot, err := s.DB.GetGraphSnapshot(snapshotID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !requestEnvironmentScope(request).Allows(graphSnapshot.EnvironmentID) {
+		writeEnvironmentNotFound(response, request)
+	} else {
+		api.WriteBasicResponse(request.Context(), graphSnapshot, http.StatusOK, response)
+	}
+}
+
+// CreateGraphSnapshot captures the current state of a domain or tenant. Snapshots are refused while the datapipe is
+// ingesting or analyzing so that every snapshot reflects a fully analyzed graph.
+func (s Resources) CreateGraphSnapshot(response http.ResponseWriter, request *http.Request) {
+	var (
+		createRequest  CreateGraphSnapshotRequest
+		datapipeStatus = s.TaskNotifier.GetStatus()
+	)
+
+	if err := api.ReadJSONRequestPayloadLimited(&createRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if createRequest.Name == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorSnapshotNameRequired, request), response)
+	} else if createRequest.EnvironmentID == "" {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorSnapshotEnvironmentRequired, request), response)
+	} else if !requestEnvironmentScope(request).Allows(createRequest.EnvironmentID) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, ErrorSnapshotUnknownEnvironment, request), response)
+	} else if datapipeStatus.Status != model.DatapipeStatusIdle {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrorSnapshotDatapipeBusy, request), response)
+	} else if graphSnapshot, err := snapshot.Capture(request.Context(), s.Graph, createRequest.Name, createRequest.EnvironmentID); errors.Is(err, snapshot.ErrUnknownEnvironment) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, ErrorSnapshotUnknownEnvironment, request), response)
+	} else if err != nil {
+		log.Errorf("Error capturing graph snapshot of %s: %v", createRequest.EnvironmentID, err)
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "CreateGraphSnapshot", createRequest); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		graphSnapshot.AnalyzedAt = datapipeStatus.LastCompleteAnalysisAt
+
+		if newSnapshot, err := s.DB.CreateGraphSnapshot(graphSnapshot); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			snapshotURL := *ctx.Get(request.Context()).Host
+			snapshotURL.Path = fmt.Sprintf("/api/v2/snapshots/%d", newSnapshot.ID)
+			response.Header().Set(headers.Location.String(), snapshotURL.String())
+
+			api.WriteBasicResponse(request.Context(), newSnapshot, http.StatusCreated, response)
+		}
+	}
+}
+
+func (s Resources) DeleteGraphSnapshot(response http.ResponseWriter, request *http.Request) {
+	if snapshotID, err := parseSnapshotID(request, api.URIPathVariableSnapshotID); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if graphSnapshot, err := s.DB.GetGraphSnapshot(snapshotID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !requestEnvironmentScope(request).Allows(graphSnapshot.EnvironmentID) {
+		writeEnvironmentNotFound(response, request)
+	} else if err := s.DB.AppendAuditLog(*ctx.FromRequest(request), "DeleteGraphSnapshot", graphSnapshot); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.DB.DeleteGraphSnapshot(graphSnapshot); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		response.WriteHeader(http.StatusOK)
+	}
+}
+
+// GetGraphSnapshotDiff returns what changed between the snapshot in the path and the snapshot it is compared to
+func (s Resources) GetGraphSnapshotDiff(response http.ResponseWriter, request *http.Request) {
+	if fromID, err := parseSnapshotID(request, api.URIPathVariableSnapshotID); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if toID, err := parseSnapshotID(request, api.URIPathVariableCompareSnapshotID); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if fromSnapshot, err := s.DB.GetGraphSnapshot(fromID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if toSnapshot, err := s.DB.GetGraphSnapshot(toID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if scope := requestEnvironmentScope(request); !scope.Allows(fromSnapshot.EnvironmentID) || !scope.Allows(toSnapshot.EnvironmentID) {
+		writeEnvironmentNotFound(response, request)
+	} else if fromSnapshot.EnvironmentID != toSnapshot.EnvironmentID {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, ErrorSnapshotEnvironmentMismatch, request), response)
+	} else if fromContent, err := snapshot.Content(fromSnapshot); err != nil {
+		log.Errorf("Error reading content of graph snapshot %d: %v", fromSnapshot.ID, err)
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else if toContent, err := snapshot.Content(toSnapshot); err != nil {
+		log.Errorf("Error reading content of graph snapshot %d: %v", toSnapshot.ID, err)
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else {
+		diff := snapshot.Diff(fromContent, toContent)
+		diff.From = fromSnapshot
+		diff.To = toSnapshot
+
+		api.WriteBasicResponse(request.Context(), diff, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	v2 "github.com/specterops/bloodhound/src/api/v2"
+	"github.com/specterops/bloodhound/src/api/v2/apitest"
+	"github.com/specterops/bloodhound/src/ctx"
+	taskerMocks "github.com/specterops/bloodhound/src/daemons/datapipe/mocks"
+	dbmocks "github.com/specterops/bloodhound/src/database/mocks"
+	"github.com/specterops/bloodhound/src/database/types"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/utils/test"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResources_CreateGraphSnapshot(t *testing.T) {
+	var (
+		mockCtrl   = gomock.NewController(t)
+		mockDB     = dbmocks.NewMockDatabase(mockCtrl)
+		mockTasker = taskerMocks.NewMockTasker(mockCtrl)
+		resources  = v2.Resources{DB: mockDB, TaskNotifier: mockTasker}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodPost).
+		WithURL("http://example.com/api/v2/snapshots")
+
+	// Error where no name is provided
+	mockTasker.EXPECT().GetStatus().Return(model.DatapipeStatusWrapper{Status: model.DatapipeStatusIdle})
+
+	requestTemplate.
+		WithBody(v2.CreateGraphSnapshotRequest{EnvironmentID: "S-1-5-21-1"}).
+		OnHandlerFunc(resources.CreateGraphSnapshot).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where no environment is provided
+	mockTasker.EXPECT().GetStatus().Return(model.DatapipeStatusWrapper{Status: model.DatapipeStatusIdle})
+
+	requestTemplate.
+		WithBody(v2.CreateGraphSnapshotRequest{Name: "before remediation"}).
+		OnHandlerFunc(resources.CreateGraphSnapshot).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Error where analysis is running
+	mockTasker.EXPECT().GetStatus().Return(model.DatapipeStatusWrapper{Status: model.DatapipeStatusAnalyzing})
+
+	requestTemplate.
+		WithBody(v2.CreateGraphSnapshotRequest{Name: "before remediation", EnvironmentID: "S-1-5-21-1"}).
+		OnHandlerFunc(resources.CreateGraphSnapshot).
+		Require().
+		ResponseStatusCode(http.StatusConflict)
+}
+
+func TestResources_GetGraphSnapshotDiff(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/snapshots/{snapshot_id}/diff/{compare_snapshot_id}")
+
+	fromContent, err := types.NewJSONBObject(model.GraphSnapshotContent{
+		Nodes: []model.GraphSnapshotNode{{ObjectID: "S-1-5-21-1-1104", Kinds: []string{"Base", "User"}}},
+	})
+	require.Nil(t, err)
+
+	toContent, err := types.NewJSONBObject(model.GraphSnapshotContent{})
+	require.Nil(t, err)
+
+	// Error where a snapshot ID is not a valid int
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"snapshot_id":         "1",
+			"compare_snapshot_id": "test",
+		}).
+		OnHandlerFunc(resources.GetGraphSnapshotDiff).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// GetGraphSnapshot DB fails
+	mockDB.EXPECT().GetGraphSnapshot(int32(1)).Return(model.GraphSnapshot{}, fmt.Errorf("exploded"))
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"snapshot_id":         "1",
+			"compare_snapshot_id": "2",
+		}).
+		OnHandlerFunc(resources.GetGraphSnapshotDiff).
+		Require().
+		ResponseStatusCode(http.StatusInternalServerError)
+
+	// Error where the snapshots are of different domains
+	mockDB.EXPECT().GetGraphSnapshot(int32(1)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-1"}, nil)
+	mockDB.EXPECT().GetGraphSnapshot(int32(2)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-2"}, nil)
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"snapshot_id":         "1",
+			"compare_snapshot_id": "2",
+		}).
+		OnHandlerFunc(resources.GetGraphSnapshotDiff).
+		Require().
+		ResponseStatusCode(http.StatusBadRequest)
+
+	// Success
+	mockDB.EXPECT().GetGraphSnapshot(int32(1)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-1", Content: fromContent}, nil)
+	mockDB.EXPECT().GetGraphSnapshot(int32(2)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-1", Content: toContent}, nil)
+
+	requestTemplate.
+		WithURLPathVars(map[string]string{
+			"snapshot_id":         "1",
+			"compare_snapshot_id": "2",
+		}).
+		OnHandlerFunc(resources.GetGraphSnapshotDiff).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Error where the snapshots are outside of the environments of the requester
+	mockDB.EXPECT().GetGraphSnapshot(int32(1)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-1", Content: fromContent}, nil)
+	mockDB.EXPECT().GetGraphSnapshot(int32(2)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-1", Content: toContent}, nil)
+
+	requestTemplate.
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		WithURLPathVars(map[string]string{
+			"snapshot_id":         "1",
+			"compare_snapshot_id": "2",
+		}).
+		OnHandlerFunc(resources.GetGraphSnapshotDiff).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
+
+func TestResources_ListGraphSnapshots(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+		snapshots = model.GraphSnapshots{
+			{Name: "emea", EnvironmentID: "S-1-5-21-1"},
+			{Name: "amer", EnvironmentID: "S-1-5-21-2"},
+		}
+	)
+	defer mockCtrl.Finish()
+
+	apitest.NewHarness(t, resources.ListGraphSnapshots).
+		Run([]apitest.Case{
+			{
+				Name: "Success",
+				Setup: func() {
+					mockDB.EXPECT().GetGraphSnapshots().Return(snapshots, nil)
+				},
+				Test: func(output apitest.Output) {
+					var result model.GraphSnapshots
+
+					apitest.StatusCode(output, http.StatusOK)
+					apitest.UnmarshalData(output, &result)
+					apitest.Equal(output, snapshots, result)
+				},
+			},
+			{
+				Name: "EnvironmentScoped",
+				Input: func(input *apitest.Input) {
+					apitest.SetContext(input, ctx.Set(context.Background(), environmentScopedContext("S-1-5-21-2")))
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetGraphSnapshots().Return(snapshots, nil)
+				},
+				Test: func(output apitest.Output) {
+					var result model.GraphSnapshots
+
+					apitest.StatusCode(output, http.StatusOK)
+					apitest.UnmarshalData(output, &result)
+					apitest.Equal(output, snapshots[1:], result)
+				},
+			},
+		})
+}
+
+func TestResources_GetGraphSnapshot(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockDB    = dbmocks.NewMockDatabase(mockCtrl)
+		resources = v2.Resources{DB: mockDB}
+	)
+	defer mockCtrl.Finish()
+
+	requestTemplate := test.Request(t).
+		WithMethod(http.MethodGet).
+		WithURL("http://example.com/api/v2/snapshots/{snapshot_id}").
+		WithURLPathVars(map[string]string{
+			"snapshot_id": "1",
+		})
+
+	// Success
+	mockDB.EXPECT().GetGraphSnapshot(int32(1)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-1"}, nil)
+
+	requestTemplate.
+		OnHandlerFunc(resources.GetGraphSnapshot).
+		Require().
+		ResponseStatusCode(http.StatusOK)
+
+	// Error where the snapshot is outside of the environments of the requester
+	mockDB.EXPECT().GetGraphSnapshot(int32(1)).Return(model.GraphSnapshot{EnvironmentID: "S-1-5-21-1"}, nil)
+
+	requestTemplate.
+		WithContext(environmentScopedContext("S-1-5-21-2")).
+		OnHandlerFunc(resources.GetGraphSnapshot).
+		Require().
+		ResponseStatusCode(http.StatusNotFound)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package bhoidc
+
+import (
+	"context"
+
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/serde"
+)
+
+func formatOIDCProviderURLs(requestContext context.Context, oidcProviders ...model.OIDCProvider) model.OIDCProviders {
+	for idx := 0; idx < len(oidcProviders); idx++ {
+		providerURLs := FormatRelyingPartyURLs(*ctx.Get(requestContext).Host, oidcProviders[idx].Name)
+
+		oidcProviders[idx].LoginURI = serde.FromURL(providerURLs.Login)
+		oidcProviders[idx].CallbackURI = serde.FromURL(providerURLs.Callback)
+	}
+
+	return oidcProviders
+}
+
+func GetOIDCProviderByName(db database.Database, name string, requestContext context.Context) (model.OIDCProvider, error) {
+	if oidcProvider, err := db.LookupOIDCProviderByName(name); err != nil {
+		return model.OIDCProvider{}, err
+	} else {
+		return formatOIDCProviderURLs(requestContext, oidcProvider)[0], nil
+	}
+}
+
+func GetOIDCProviderByID(db database.Database, id int32, requestContext context.Context) (model.OIDCProvider, error) {
+	if oidcProvider, err := db.GetOIDCProvider(id); err != nil {
+		return model.OIDCProvider{}, err
+	} else {
+		return formatOIDCProviderURLs(requestContext, oidcProvider)[0], nil
+	}
+}
+
+func GetAllOIDCProviders(db database.Database, requestContext context.Context) (model.OIDCProviders, error) {
+	if oidcProviders, err := db.GetAllOIDCProviders(); err != nil {
+		return nil, err
+	} else {
+		return formatOIDCProviderURLs(requestContext, oidcProviders...), nil
+	}
+}
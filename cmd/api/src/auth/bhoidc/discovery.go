@@ -0,0 +1,121 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package bhoidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/api/stream"
+)
+
+const (
+	DiscoveryDocumentPath = "/.well-known/openid-configuration"
+
+	ErrDiscoveryDocumentInvalid = errors.Error("OIDC discovery document is invalid")
+	ErrIssuerMismatch           = errors.Error("OIDC discovery document issuer does not match the configured issuer")
+)
+
+// DiscoveryDocument contains the OpenID provider metadata that BloodHound requires to run the authorization code flow
+type DiscoveryDocument struct {
+	Issuer                           string   `json:"issuer"`
+	AuthorizationEndpoint            string   `json:"authorization_endpoint"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	UserInfoEndpoint                 string   `json:"userinfo_endpoint,omitempty"`
+	ScopesSupported                  []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	CodeChallengeMethodsSupported    []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// DiscoveryURL returns the URL of the discovery document for the given issuer
+func DiscoveryURL(issuerURI string) string {
+	return strings.TrimSuffix(issuerURI, "/") + DiscoveryDocumentPath
+}
+
+// ParseDiscoveryDocument parses and validates a discovery document. The issuer of the document must match the given
+// issuer URI exactly, ignoring a trailing slash.
+func ParseDiscoveryDocument(content []byte, issuerURI string) (DiscoveryDocument, error) {
+	var document DiscoveryDocument
+
+	if err := json.Unmarshal(content, &document); err != nil {
+		return document, fmt.Errorf("%w: %v", ErrDiscoveryDocumentInvalid, err)
+	} else if document.Issuer == "" || document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return document, fmt.Errorf("%w: issuer, authorization_endpoint, token_endpoint and jwks_uri are required", ErrDiscoveryDocumentInvalid)
+	} else if strings.TrimSuffix(document.Issuer, "/") != strings.TrimSuffix(issuerURI, "/") {
+		return document, fmt.Errorf("%w: expected %s but got %s", ErrIssuerMismatch, issuerURI, document.Issuer)
+	} else if len(document.CodeChallengeMethodsSupported) > 0 && !contains(document.CodeChallengeMethodsSupported, CodeChallengeMethodS256) {
+		return document, fmt.Errorf("%w: PKCE code challenge method %s is not supported", ErrDiscoveryDocumentInvalid, CodeChallengeMethodS256)
+	}
+
+	return document, nil
+}
+
+// FetchDiscoveryDocument fetches and validates the discovery document of the given issuer
+func FetchDiscoveryDocument(httpClient api.HTTPClient, ctx context.Context, issuerURI string) (DiscoveryDocument, error) {
+	if content, err := fetchJSON(httpClient, ctx, DiscoveryURL(issuerURI)); err != nil {
+		return DiscoveryDocument{}, err
+	} else {
+		return ParseDiscoveryDocument(content, issuerURI)
+	}
+}
+
+func fetchJSON(httpClient api.HTTPClient, ctx context.Context, url string) ([]byte, error) {
+	if req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil); err != nil {
+		return nil, err
+	} else {
+		req.Header.Set("Accept", "application/json")
+
+		if resp, err := httpClient.Do(req); err != nil {
+			return nil, err
+		} else {
+			defer resp.Body.Close()
+			return readResponse(resp, url)
+		}
+	}
+}
+
+func readResponse(resp *http.Response, url string) ([]byte, error) {
+	bodyReader := stream.NewLimitedReader(api.DefaultAPIPayloadReadLimitBytes, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		if content, err := io.ReadAll(bodyReader); err != nil {
+			return nil, fmt.Errorf("unexpected HTTP status code %d from %s: %s", resp.StatusCode, url, "unable to read response body")
+		} else {
+			return nil, fmt.Errorf("unexpected HTTP status code %d from %s: %s", resp.StatusCode, url, string(content))
+		}
+	}
+
+	return io.ReadAll(bodyReader)
+}
+
+func contains(values []string, value string) bool {
+	for _, next := range values {
+		if next == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package bhoidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/src/api"
+)
+
+const (
+	ErrKeyNotFound = errors.Error("signing key not found in the OIDC provider key set")
+	ErrKeyInvalid  = errors.Error("OIDC provider signing key is invalid")
+)
+
+// JSONWebKey is a single entry of a JSON Web Key Set. Only RSA signing keys are supported.
+type JSONWebKey struct {
+	KeyID     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+}
+
+// RSAPublicKey decodes the key material of the JSON Web Key
+func (s JSONWebKey) RSAPublicKey() (*rsa.PublicKey, error) {
+	if s.KeyType != "RSA" {
+		return nil, fmt.Errorf("%w: unsupported key type %s", ErrKeyInvalid, s.KeyType)
+	} else if modulus, err := base64.RawURLEncoding.DecodeString(s.Modulus); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyInvalid, err)
+	} else if exponent, err := base64.RawURLEncoding.DecodeString(s.Exponent); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyInvalid, err)
+	} else if len(modulus) == 0 || len(exponent) == 0 {
+		return nil, fmt.Errorf("%w: modulus and exponent are required", ErrKeyInvalid)
+	} else {
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}, nil
+	}
+}
+
+// NewJSONWebKey encodes an RSA public key as a JSON Web Key
+func NewJSONWebKey(keyID string, publicKey *rsa.PublicKey) JSONWebKey {
+	return JSONWebKey{
+		KeyID:     keyID,
+		KeyType:   "RSA",
+		Algorithm: SigningMethodRS256,
+		Use:       "sig",
+		Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+	}
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicKey returns the RSA signing key identified by the given key ID. When the key ID is empty and the set only
+// contains a single signing key, that key is returned.
+func (s JSONWebKeySet) PublicKey(keyID string) (*rsa.PublicKey, error) {
+	var signingKeys []JSONWebKey
+
+	for _, key := range s.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		if keyID != "" && key.KeyID == keyID {
+			return key.RSAPublicKey()
+		}
+
+		signingKeys = append(signingKeys, key)
+	}
+
+	if keyID == "" && len(signingKeys) == 1 {
+		return signingKeys[0].RSAPublicKey()
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// FetchJSONWebKeySet fetches the signing keys published at the given JWKS URI
+func FetchJSONWebKeySet(httpClient api.HTTPClient, ctx context.Context, jwksURI string) (JSONWebKeySet, error) {
+	var keySet JSONWebKeySet
+
+	if content, err := fetchJSON(httpClient, ctx, jwksURI); err != nil {
+		return keySet, err
+	} else if err := json.Unmarshal(content, &keySet); err != nil {
+		return keySet, fmt.Errorf("%w: %v", ErrKeyInvalid, err)
+	}
+
+	return keySet, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package bhoidc
+
+import (
+	"net/url"
+	"path"
+
+	"github.com/specterops/bloodhound/src/api"
+)
+
+const (
+	OIDCAPILoginRoot = "api/v2/login/oidc"
+
+	// DefaultPrincipalClaim is the ID token claim used to look up the BloodHound user when a provider does not
+	// configure one
+	DefaultPrincipalClaim = ClaimEmail
+
+	// DefaultScopes are requested when a provider does not configure its own scopes
+	DefaultScopes = "openid email profile"
+)
+
+type RelyingPartyURLs struct {
+	RelyingPartyRoot url.URL
+	Login            url.URL
+	Callback         url.URL
+}
+
+func FormatRelyingPartyURLs(hostURL url.URL, providerName string) RelyingPartyURLs {
+	root := hostURL
+	root.Path = path.Join("/", OIDCAPILoginRoot, providerName)
+
+	return RelyingPartyURLs{
+		RelyingPartyRoot: root,
+		Login:            api.URLJoinPath(root, "login"),
+		Callback:         api.URLJoinPath(root, "callback"),
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package bhoidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/src/api"
+)
+
+const (
+	CodeChallengeMethodS256 = "S256"
+	SigningMethodRS256      = "RS256"
+	ScopeOpenID             = "openid"
+
+	// Standard claims of the OpenID Connect profile and email scopes
+	ClaimEmail         = "email"
+	ClaimEmailVerified = "email_verified"
+	ClaimGivenName     = "given_name"
+	ClaimFamilyName    = "family_name"
+
+	ErrTokenResponseInvalid = errors.Error("OIDC token response is invalid")
+	ErrIDTokenInvalid       = errors.Error("OIDC ID token is invalid")
+	ErrNonceMismatch        = errors.Error("OIDC ID token nonce does not match the authorization request")
+
+	randomValueLength = 32
+	clockSkew         = time.Minute
+)
+
+// NewRandomValue returns a URL safe, high entropy value suitable for use as a PKCE verifier, state or nonce
+func NewRandomValue() (string, error) {
+	buffer := make([]byte, randomValueLength)
+
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// CodeChallenge derives the S256 PKCE code challenge for the given verifier
+func CodeChallenge(verifier string) string {
+	digest := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// Scopes returns the scopes to request from the provider. The openid scope is always requested.
+func Scopes(configured string) []string {
+	scopes := []string{ScopeOpenID}
+
+	for _, scope := range strings.Fields(configured) {
+		if !contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
+
+// AuthorizationRequest holds the parameters of a single authorization code flow
+type AuthorizationRequest struct {
+	ClientID     string
+	RedirectURI  string
+	Scopes       []string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// URL builds the authorization endpoint URL that the user agent is redirected to
+func (s AuthorizationRequest) URL(document DiscoveryDocument) (string, error) {
+	if authorizationURL, err := url.Parse(document.AuthorizationEndpoint); err != nil {
+		return "", err
+	} else {
+		query := authorizationURL.Query()
+		query.Set("response_type", "code")
+		query.Set("client_id", s.ClientID)
+		query.Set("redirect_uri", s.RedirectURI)
+		query.Set("scope", strings.Join(s.Scopes, " "))
+		query.Set("state", s.State)
+		query.Set("nonce", s.Nonce)
+		query.Set("code_challenge", CodeChallenge(s.CodeVerifier))
+		query.Set("code_challenge_method", CodeChallengeMethodS256)
+
+		authorizationURL.RawQuery = query.Encode()
+		return authorizationURL.String(), nil
+	}
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in,omitempty"`
+}
+
+// ExchangeCode redeems an authorization code at the token endpoint of the provider
+func ExchangeCode(httpClient api.HTTPClient, ctx context.Context, document DiscoveryDocument, clientID, clientSecret, redirectURI, code, codeVerifier string) (TokenResponse, error) {
+	var (
+		tokenResponse TokenResponse
+		form          = url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {redirectURI},
+			"client_id":     {clientID},
+			"code_verifier": {codeVerifier},
+		}
+	)
+
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+
+	if req, err := http.NewRequestWithContext(ctx, http.MethodPost, document.TokenEndpoint, strings.NewReader(form.Encode())); err != nil {
+		return tokenResponse, err
+	} else {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Accept", "application/json")
+
+		if resp, err := httpClient.Do(req); err != nil {
+			return tokenResponse, err
+		} else {
+			defer resp.Body.Close()
+
+			if content, err := readResponse(resp, document.TokenEndpoint); err != nil {
+				return tokenResponse, err
+			} else if err := json.Unmarshal(content, &tokenResponse); err != nil {
+				return tokenResponse, fmt.Errorf("%w: %v", ErrTokenResponseInvalid, err)
+			} else if tokenResponse.IDToken == "" {
+				return tokenResponse, fmt.Errorf("%w: id_token is missing", ErrTokenResponseInvalid)
+			}
+
+			return tokenResponse, nil
+		}
+	}
+}
+
+// ValidateIDToken verifies the signature and standard claims of an ID token and returns its claims. Only RS256 signed
+// tokens are accepted.
+func ValidateIDToken(rawIDToken string, keySet JSONWebKeySet, issuer, clientID, nonce string, now time.Time) (jwt.MapClaims, error) {
+	var (
+		claims = jwt.MapClaims{}
+		parser = jwt.NewParser(jwt.WithValidMethods([]string{SigningMethodRS256}), jwt.WithoutClaimsValidation())
+	)
+
+	if _, err := parser.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return keySet.PublicKey(keyID)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIDTokenInvalid, err)
+	}
+
+	if tokenIssuer, _ := claims["iss"].(string); strings.TrimSuffix(tokenIssuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, fmt.Errorf("%w: unexpected issuer %s", ErrIDTokenInvalid, tokenIssuer)
+	} else if !contains(ClaimValues(claims, "aud"), clientID) {
+		return nil, fmt.Errorf("%w: token audience does not include the client ID", ErrIDTokenInvalid)
+	} else if !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		return nil, fmt.Errorf("%w: token is expired", ErrIDTokenInvalid)
+	} else if !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), false) || !claims.VerifyNotBefore(now.Add(clockSkew).Unix(), false) {
+		return nil, fmt.Errorf("%w: token is not valid yet", ErrIDTokenInvalid)
+	} else if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return claims, nil
+}
+
+// ClaimValues returns the values of a claim that may be expressed either as a single string or as an array of strings
+func ClaimValues(claims jwt.MapClaims, name string) []string {
+	switch typedValue := claims[name].(type) {
+	case string:
+		if typedValue == "" {
+			return nil
+		}
+
+		return []string{typedValue}
+
+	case []string:
+		return typedValue
+
+	case []any:
+		values := make([]string, 0, len(typedValue))
+
+		for _, value := range typedValue {
+			if stringValue, ok := value.(string); ok {
+				values = append(values, stringValue)
+			}
+		}
+
+		return values
+
+	default:
+		return nil
+	}
+}
+
+// EmailVerified returns true if the claims assert that the provider verified the email address of the user. Some
+// providers express the claim as a string rather than a boolean.
+func EmailVerified(claims jwt.MapClaims) bool {
+	switch typedValue := claims[ClaimEmailVerified].(type) {
+	case bool:
+		return typedValue
+
+	case string:
+		return typedValue == "true"
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package bhoidc_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/specterops/bloodhound/src/auth/bhoidc"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testClientID = "bloodhound"
+	testNonce    = "nonce"
+	testKeyID    = "key-1"
+)
+
+func newTestKeySet(t *testing.T) (*rsa.PrivateKey, bhoidc.JSONWebKeySet) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey, bhoidc.JSONWebKeySet{
+		Keys: []bhoidc.JSONWebKey{bhoidc.NewJSONWebKey(testKeyID, &privateKey.PublicKey)},
+	}
+}
+
+func signTestIDToken(t *testing.T, privateKey *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+
+	if signedToken, err := token.SignedString(privateKey); err != nil {
+		t.Fatal(err)
+		return ""
+	} else {
+		return signedToken
+	}
+}
+
+func testClaims(now time.Time) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    testIssuer,
+		"aud":    []string{testClientID, "other"},
+		"sub":    "alice",
+		"email":  "alice@example.com",
+		"groups": []string{"sec-bh-admins", "users"},
+		"nonce":  testNonce,
+		"iat":    now.Unix(),
+		"exp":    now.Add(time.Minute).Unix(),
+	}
+}
+
+func TestValidateIDToken(t *testing.T) {
+	var (
+		now                 = time.Now()
+		privateKey, keySet  = newTestKeySet(t)
+		otherPrivateKey, _  = newTestKeySet(t)
+		expiredClaims       = testClaims(now)
+		wrongAudienceClaims = testClaims(now)
+		wrongIssuerClaims   = testClaims(now)
+	)
+
+	expiredClaims["exp"] = now.Add(-time.Hour).Unix()
+	wrongAudienceClaims["aud"] = "other"
+	wrongIssuerClaims["iss"] = "https://evil.example.com"
+
+	if claims, err := bhoidc.ValidateIDToken(signTestIDToken(t, privateKey, testClaims(now)), keySet, testIssuer, testClientID, testNonce, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if groups := bhoidc.ClaimValues(claims, "groups"); len(groups) != 2 || groups[0] != "sec-bh-admins" {
+		t.Errorf("got %v, want %v", groups, []string{"sec-bh-admins", "users"})
+	}
+
+	if _, err := bhoidc.ValidateIDToken(signTestIDToken(t, privateKey, testClaims(now)), keySet, testIssuer, testClientID, "replayed", now); !errors.Is(err, bhoidc.ErrNonceMismatch) {
+		t.Errorf("got %v, want %v", err, bhoidc.ErrNonceMismatch)
+	}
+
+	for name, rawIDToken := range map[string]string{
+		"expired":        signTestIDToken(t, privateKey, expiredClaims),
+		"wrong audience": signTestIDToken(t, privateKey, wrongAudienceClaims),
+		"wrong issuer":   signTestIDToken(t, privateKey, wrongIssuerClaims),
+		"wrong key":      signTestIDToken(t, otherPrivateKey, testClaims(now)),
+		"unsigned":       "eyJhbGciOiJub25lIn0.eyJpc3MiOiJodHRwczovL2lkcC5leGFtcGxlLmNvbSJ9.",
+	} {
+		if _, err := bhoidc.ValidateIDToken(rawIDToken, keySet, testIssuer, testClientID, testNonce, now); !errors.Is(err, bhoidc.ErrIDTokenInvalid) {
+			t.Errorf("%s: got %v, want %v", name, err, bhoidc.ErrIDTokenInvalid)
+		}
+	}
+}
+
+func TestParseDiscoveryDocument(t *testing.T) {
+	document := []byte(`{
+		"issuer": "https://idp.example.com/",
+		"authorization_endpoint": "https://idp.example.com/authorize",
+		"token_endpoint": "https://idp.example.com/token",
+		"jwks_uri": "https://idp.example.com/jwks",
+		"response_types_supported": ["code"],
+		"id_token_signing_alg_values_supported": ["RS256"],
+		"code_challenge_methods_supported": ["S256"]
+	}`)
+
+	if parsed, err := bhoidc.ParseDiscoveryDocument(document, testIssuer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if parsed.TokenEndpoint != "https://idp.example.com/token" {
+		t.Errorf("got %v, want %v", parsed.TokenEndpoint, "https://idp.example.com/token")
+	}
+
+	if _, err := bhoidc.ParseDiscoveryDocument(document, "https://other.example.com"); !errors.Is(err, bhoidc.ErrIssuerMismatch) {
+		t.Errorf("got %v, want %v", err, bhoidc.ErrIssuerMismatch)
+	}
+
+	if _, err := bhoidc.ParseDiscoveryDocument([]byte(`{"issuer": "https://idp.example.com"}`), testIssuer); !errors.Is(err, bhoidc.ErrDiscoveryDocumentInvalid) {
+		t.Errorf("got %v, want %v", err, bhoidc.ErrDiscoveryDocumentInvalid)
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// Test vector from RFC 7636 appendix B
+	if challenge := bhoidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("got %v, want %v", challenge, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")
+	}
+}
+
+func TestScopes(t *testing.T) {
+	if scopes := bhoidc.Scopes("email openid  groups"); len(scopes) != 3 || scopes[0] != bhoidc.ScopeOpenID || scopes[2] != "groups" {
+		t.Errorf("got %v, want %v", scopes, []string{"openid", "email", "groups"})
+	}
+}
+
+func TestEmailVerified(t *testing.T) {
+	for _, testCase := range []struct {
+		claims   jwt.MapClaims
+		expected bool
+	}{
+		{claims: jwt.MapClaims{"email_verified": true}, expected: true},
+		{claims: jwt.MapClaims{"email_verified": "true"}, expected: true},
+		{claims: jwt.MapClaims{"email_verified": false}, expected: false},
+		{claims: jwt.MapClaims{"email_verified": "false"}, expected: false},
+		{claims: jwt.MapClaims{}, expected: false},
+	} {
+		if verified := bhoidc.EmailVerified(testCase.claims); verified != testCase.expected {
+			t.Errorf("got %v, want %v for claims %v", verified, testCase.expected, testCase.claims)
+		}
+	}
+}
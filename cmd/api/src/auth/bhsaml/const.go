@@ -22,9 +22,12 @@ const (
 	ObjectIDGivenName     = "urn:oid:2.5.4.42"
 	ObjectIDUserID        = "urn:oid:0.9.2342.19200300.100.1.1"
 	ObjectIDEmail         = "urn:oid:0.9.2342.19200300.100.1.3"
+	ObjectIDAffiliation   = "urn:oid:1.3.6.1.4.1.5923.1.1.1.1"
 
 	ObjectIDAttributeNameFormat = "urn:oasis:names:tc:SAML:2.0:attrname-format:uri"
 
 	XMLSOAPClaimsEmailAddress = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"
+	XMLSOAPClaimsGivenName    = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname"
+	XMLSOAPClaimsSurname      = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname"
 	XMLTypeString             = "xs:string"
 )
@@ -0,0 +1,116 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bhscim contains the role synchronization of users that are provisioned through SCIM groups.
+package bhscim
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// RoleSynchronizer grants users the roles mapped to the SCIM groups that they are a member of
+type RoleSynchronizer struct {
+	db database.Database
+}
+
+func NewRoleSynchronizer(db database.Database) RoleSynchronizer {
+	return RoleSynchronizer{
+		db: db,
+	}
+}
+
+// AffectedUserIDs returns the IDs of the users whose roles may change when the group is replaced with the updated
+// group. Membership changes of unmapped groups do not affect the roles of their members.
+func AffectedUserIDs(group, updatedGroup model.SCIMGroup) []uuid.UUID {
+	var userIDs []uuid.UUID
+
+	if group.RoleID != updatedGroup.RoleID {
+		if group.RoleID.Valid {
+			userIDs = append(userIDs, group.Members.UserIDs()...)
+		}
+
+		if updatedGroup.RoleID.Valid {
+			userIDs = append(userIDs, updatedGroup.Members.UserIDs()...)
+		}
+	} else if group.RoleID.Valid {
+		userIDs = append(userIDs, group.Members.Difference(updatedGroup.Members)...)
+		userIDs = append(userIDs, updatedGroup.Members.Difference(group.Members)...)
+	}
+
+	return userIDs
+}
+
+// SyncRoles replaces the roles of the given users with the roles mapped to the SCIM groups that they are a member of.
+// Users that are no longer a member of any mapped group are left without roles. Users that no longer exist are skipped.
+func (s RoleSynchronizer) SyncRoles(requestContext ctx.Context, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	} else if groups, err := s.db.GetAllSCIMGroups(); err != nil {
+		return err
+	} else {
+		synchronized := make(map[uuid.UUID]struct{}, len(userIDs))
+
+		for _, userID := range userIDs {
+			if _, seen := synchronized[userID]; seen {
+				continue
+			}
+
+			synchronized[userID] = struct{}{}
+
+			if user, err := s.db.GetUser(userID); err != nil {
+				if !errors.Is(err, database.ErrNotFound) {
+					return err
+				}
+			} else if err := s.syncUserRoles(requestContext, user, groups.RoleIDs(userID)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func (s RoleSynchronizer) syncUserRoles(requestContext ctx.Context, user model.User, roleIDs []int32) error {
+	roles := model.Roles{}
+
+	if len(roleIDs) > 0 {
+		if mappedRoles, err := s.db.GetRoles(roleIDs); err != nil {
+			return err
+		} else {
+			roles = mappedRoles
+		}
+	}
+
+	if user.Roles.Equals(roles) {
+		return nil
+	}
+
+	user.Roles = roles
+
+	if err := s.db.AppendAuditLog(requestContext, "SyncSCIMUserRoles", user); err != nil {
+		return err
+	} else if err := s.db.UpdateUser(user); err != nil {
+		return err
+	} else {
+		log.Infof("[SCIM] Synchronized roles of user %s: %v", user.PrincipalName, roles.IDs())
+		return nil
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bhsso contains the user provisioning logic shared by the single sign-on providers that BloodHound supports.
+package bhsso
+
+import (
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/auth"
+	"github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/database"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	ErrUserNotFound                 = errors.Error("user not found")
+	ErrUserNotAuthorizedForProvider = errors.Error("user not authorized for this provider")
+	ErrNoMappedRoles                = errors.Error("no roles are mapped to the groups of the user")
+)
+
+// Provider is implemented by the single sign-on providers that can map the groups of an authenticated identity to
+// BloodHound roles
+type Provider interface {
+	model.Auditable
+
+	// HasUser returns true if the user is bound to the provider
+	HasUser(user model.User) bool
+
+	// BindUser binds the user to the provider
+	BindUser(user *model.User)
+
+	// MapsRoles returns true if the roles of the users of the provider are managed by its role mappings
+	MapsRoles() bool
+
+	// MappedRoleIDs returns the IDs of the roles mapped to any of the given groups
+	MappedRoleIDs(groups []string) []int32
+
+	// ProvisionsUsers returns true if unknown users are created on first login
+	ProvisionsUsers() bool
+}
+
+// Identity is the identity asserted by a single sign-on provider for an authenticated user
+type Identity struct {
+	PrincipalName string
+	EmailAddress  string
+	FirstName     string
+	LastName      string
+	Groups        []string
+}
+
+func (s Identity) user() model.User {
+	return model.User{
+		PrincipalName: s.PrincipalName,
+		EmailAddress:  optionalString(s.EmailAddress),
+		FirstName:     optionalString(s.FirstName),
+		LastName:      optionalString(s.LastName),
+
+		// EULA Acceptance does not pertain to Bloodhound Community Edition; this flag is used for Bloodhound Enterprise users.
+		EULAAccepted: true,
+	}
+}
+
+func optionalString(value string) null.String {
+	if value == "" {
+		return null.String{}
+	}
+
+	return null.StringFrom(value)
+}
+
+// auditContext returns a copy of the request context that records audit entries on behalf of the given user, as
+// there is no authenticated session while a login is being processed
+func auditContext(requestContext ctx.Context, user model.User) ctx.Context {
+	requestContext.AuthCtx = auth.Context{Owner: user}
+	return requestContext
+}
+
+// Provisioner resolves the identities asserted by single sign-on providers to BloodHound users. Users may be created
+// on first login and have their roles synchronized with the role mappings of their provider on every login.
+type Provisioner struct {
+	db database.Database
+}
+
+func NewProvisioner(db database.Database) Provisioner {
+	return Provisioner{
+		db: db,
+	}
+}
+
+// Provision returns the user for the given identity. Users that do not exist are created when the provider provisions
+// users. Existing users must be bound to the provider; local users and users of other providers are never taken over.
+func (s Provisioner) Provision(requestContext ctx.Context, provider Provider, identity Identity) (model.User, error) {
+	if user, err := s.db.LookupUser(identity.PrincipalName); err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			return model.User{}, api.FormatDatabaseError(err)
+		} else if !provider.ProvisionsUsers() {
+			return model.User{}, ErrUserNotFound
+		} else {
+			return s.createUser(requestContext, provider, identity)
+		}
+	} else if !provider.HasUser(user) {
+		return model.User{}, ErrUserNotAuthorizedForProvider
+	} else if !provider.MapsRoles() {
+		return user, nil
+	} else {
+		return s.syncRoles(requestContext, provider, user, provider.MappedRoleIDs(identity.Groups))
+	}
+}
+
+func (s Provisioner) createUser(requestContext ctx.Context, provider Provider, identity Identity) (model.User, error) {
+	if roleIDs := provider.MappedRoleIDs(identity.Groups); len(roleIDs) == 0 {
+		return model.User{}, ErrNoMappedRoles
+	} else if roles, err := s.db.GetRoles(roleIDs); err != nil {
+		return model.User{}, api.FormatDatabaseError(err)
+	} else {
+		userTemplate := identity.user()
+		userTemplate.Roles = roles
+		provider.BindUser(&userTemplate)
+
+		if newUser, err := s.db.CreateUser(userTemplate); err != nil {
+			return model.User{}, api.FormatDatabaseError(err)
+		} else {
+			auditData := newUser.AuditData().MergeLeft(provider)
+
+			if err := s.db.AppendAuditLog(auditContext(requestContext, newUser), "ProvisionSSOUser", auditData); err != nil {
+				log.Errorf("[SSO] Failed writing audit log for provisioning of user %s: %v", newUser.PrincipalName, err)
+			}
+
+			return newUser, nil
+		}
+	}
+}
+
+// syncRoles replaces the roles of the user with the roles mapped to the given role IDs. A user that no longer belongs
+// to any mapped group is deprovisioned: their roles are revoked and their active sessions are ended.
+func (s Provisioner) syncRoles(requestContext ctx.Context, provider Provider, user model.User, roleIDs []int32) (model.User, error) {
+	if len(roleIDs) == 0 {
+		if len(user.Roles) > 0 {
+			if err := s.deprovision(requestContext, provider, user); err != nil {
+				return user, err
+			}
+		}
+
+		return user, ErrNoMappedRoles
+	} else if roles, err := s.db.GetRoles(roleIDs); err != nil {
+		return user, api.FormatDatabaseError(err)
+	} else if user.Roles.Equals(roles) {
+		return user, nil
+	} else {
+		user.Roles = roles
+
+		if err := s.db.UpdateUser(user); err != nil {
+			return user, api.FormatDatabaseError(err)
+		} else {
+			auditData := user.AuditData().MergeLeft(provider)
+
+			if err := s.db.AppendAuditLog(auditContext(requestContext, user), "SyncSSOUserRoles", auditData); err != nil {
+				log.Errorf("[SSO] Failed writing audit log for role synchronization of user %s: %v", user.PrincipalName, err)
+			}
+		}
+
+		return user, nil
+	}
+}
+
+func (s Provisioner) deprovision(requestContext ctx.Context, provider Provider, user model.User) error {
+	user.Roles = model.Roles{}
+
+	if err := s.db.UpdateUser(user); err != nil {
+		return api.FormatDatabaseError(err)
+	} else if sessions, err := s.db.LookupActiveSessionsByUser(user); err != nil {
+		return api.FormatDatabaseError(err)
+	} else {
+		for _, session := range sessions {
+			s.db.EndUserSession(session)
+		}
+
+		auditData := user.AuditData().MergeLeft(provider)
+
+		if err := s.db.AppendAuditLog(auditContext(requestContext, user), "DeprovisionSSOUser", auditData); err != nil {
+			log.Errorf("[SSO] Failed writing audit log for deprovisioning of user %s: %v", user.PrincipalName, err)
+		}
+
+		return nil
+	}
+}
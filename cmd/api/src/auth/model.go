@@ -135,6 +135,16 @@ func GetUserFromAuthCtx(ctx Context) (model.User, bool) {
 	}
 }
 
+// GetEnvironmentScopeFromAuthCtx returns the environments whose graph data is visible to the owner of the auth context.
+// Only users are restricted by the environments of their roles.
+func GetEnvironmentScopeFromAuthCtx(ctx Context) model.EnvironmentScope {
+	if user, isUser := GetUserFromAuthCtx(ctx); isUser {
+		return user.Roles.EnvironmentScope()
+	}
+
+	return model.EnvironmentScope{}
+}
+
 // NewUserAuthToken creates a new User model.AuthToken using the details provided
 //
 // This isn't an ideal location for this function but it was determined to be the best place "for now".
@@ -39,6 +39,9 @@ type PermissionSet struct {
 	AuthManageProviders                 model.Permission
 	AuthManageApplicationConfigurations model.Permission
 
+	// AuthSCIMProvisioning is only granted to requests authenticated with a SCIM token and can not be assigned to roles
+	AuthSCIMProvisioning model.Permission
+
 	APsGenerateReport model.Permission
 	APsManageAPs      model.Permission
 }
@@ -81,6 +84,7 @@ func Permissions() PermissionSet {
 		AuthManageProviders:                 model.NewPermission("auth", "ManageProviders"),
 		AuthManageUsers:                     model.NewPermission("auth", "ManageUsers"),
 		AuthManageApplicationConfigurations: model.NewPermission("auth", "ManageAppConfig"),
+		AuthSCIMProvisioning:                model.NewPermission("auth", "SCIMProvisioning"),
 
 		APsGenerateReport: model.NewPermission("risks", "GenerateReport"),
 		APsManageAPs:      model.NewPermission("risks", "ManageRisks"),
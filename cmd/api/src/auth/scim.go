@@ -0,0 +1,63 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// SCIMTokenPrefix identifies SCIM tokens presented as bearer tokens so that they can be told apart from session JWTs
+const SCIMTokenPrefix = "bhscim_"
+
+// IsSCIMToken returns true if the bearer token is a SCIM token
+func IsSCIMToken(bearerToken string) bool {
+	return strings.HasPrefix(bearerToken, SCIMTokenPrefix)
+}
+
+// SCIMTokenDigest returns the digest that is stored in place of the SCIM token. SCIM tokens carry enough entropy that
+// a fast hash is sufficient.
+func SCIMTokenDigest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// NewSCIMToken creates a new model.SCIMToken owned by the given user. The returned token is only available at creation
+// time as the model only stores its digest.
+func NewSCIMToken(ownerID uuid.UUID, tokenName string) (model.SCIMToken, string, error) {
+	tokenBytes := make([]byte, 40)
+
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return model.SCIMToken{}, "", err
+	}
+
+	token := SCIMTokenPrefix + base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	return model.SCIMToken{
+		UserID:     ownerID,
+		Name:       tokenName,
+		Digest:     SCIMTokenDigest(token),
+		LastAccess: time.Now().UTC(),
+	}, token, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/specterops/bloodhound/src/auth"
+)
+
+func TestNewSCIMToken(t *testing.T) {
+	ownerID := uuid.Must(uuid.NewV4())
+
+	if scimToken, token, err := auth.NewSCIMToken(ownerID, "identity governance"); err != nil {
+		t.Fatal(err)
+	} else if !auth.IsSCIMToken(token) {
+		t.Errorf("token %s is missing the SCIM token prefix", token)
+	} else if scimToken.Digest != auth.SCIMTokenDigest(token) {
+		t.Errorf("got digest %v, want %v", scimToken.Digest, auth.SCIMTokenDigest(token))
+	} else if scimToken.UserID != ownerID {
+		t.Errorf("got owner %v, want %v", scimToken.UserID, ownerID)
+	} else if _, otherToken, err := auth.NewSCIMToken(ownerID, "identity governance"); err != nil {
+		t.Fatal(err)
+	} else if otherToken == token {
+		t.Error("expected tokens to be unique")
+	}
+}
@@ -43,8 +43,10 @@ func performMigrationsOnly(cfg config.Configuration) {
 		log.Fatalf("Failed connecting to databases: %v", err)
 	} else if err := db.MigrateModels(migration.ListBHModels()); err != nil {
 		log.Fatalf("Migrations failed: %v", err)
+	} else if customKinds, err := db.GetCustomKinds(); err != nil {
+		log.Fatalf("Failed fetching custom kinds: %v", err)
 	} else {
-		var migrator = migrations.NewGraphMigrator(graphDB)
+		var migrator = migrations.NewGraphMigrator(graphDB, customKinds.NodeKinds()...)
 		if err := migrator.Migrate(); err != nil {
 			log.Fatalf("Error running migrations for graph db: %v", err)
 		}
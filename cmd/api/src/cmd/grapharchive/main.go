@@ -0,0 +1,123 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/config"
+	"github.com/specterops/bloodhound/src/server"
+	"github.com/specterops/bloodhound/src/services/grapharchive"
+)
+
+func usageExit() {
+	flag.Usage()
+	os.Exit(1)
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Printf(format, args...)
+
+	if !strings.HasSuffix(format, "\n") {
+		fmt.Println()
+	}
+
+	os.Exit(1)
+}
+
+func exportGraph(ctx context.Context, graphDB graph.Database, path string) error {
+	if archiveFile, err := os.Create(path); err != nil {
+		return err
+	} else if result, err := grapharchive.Export(ctx, graphDB, archiveFile); err != nil {
+		archiveFile.Close()
+		os.Remove(path)
+
+		return err
+	} else if err := archiveFile.Close(); err != nil {
+		return err
+	} else {
+		fmt.Printf("Exported %d nodes and %d relationships to %s\n", result.Nodes, result.Relationships, path)
+		return nil
+	}
+}
+
+func importGraph(ctx context.Context, graphDB graph.Database, path string) error {
+	if archiveFile, err := os.Open(path); err != nil {
+		return err
+	} else {
+		defer archiveFile.Close()
+
+		if result, err := grapharchive.Import(ctx, graphDB, archiveFile); err != nil {
+			return err
+		} else {
+			fmt.Printf("Imported %d nodes and %d relationships from %s\n", result.Nodes, result.Relationships, path)
+
+			if result.SkippedRelationships > 0 {
+				fmt.Printf("Skipped %d relationships attached to nodes without an object ID\n", result.SkippedRelationships)
+			}
+
+			return nil
+		}
+	}
+}
+
+func main() {
+	var (
+		configFilePath string
+		exportPath     string
+		importPath     string
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "BloodHound Graph Archive Tool\n\nExports or imports the full graph of a BloodHound instance as a portable archive.\n\nUsage of %s\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.StringVar(&configFilePath, "configfile", server.DefaultConfigFilePath(), "Configuration file to load.")
+	flag.StringVar(&exportPath, "export", "", "Export the graph to the given archive file.")
+	flag.StringVar(&importPath, "import", "", "Import the graph from the given archive file.")
+	flag.Parse()
+
+	if (exportPath == "") == (importPath == "") {
+		usageExit()
+	}
+
+	// Initialize basic logging facilities while we start up
+	log.ConfigureDefaults()
+
+	if cfg, err := config.GetConfiguration(configFilePath); err != nil {
+		fatalf("Unable to read configuration %s: %v", configFilePath, err)
+	} else if graphDB, err := server.ConnectGraph(cfg); err != nil {
+		fatalf("Failed connecting to the graph database: %v", err)
+	} else {
+		defer graphDB.Close()
+
+		if exportPath != "" {
+			if err := exportGraph(context.Background(), graphDB, exportPath); err != nil {
+				fatalf("Graph export failed: %v", err)
+			}
+		} else if err := importGraph(context.Background(), graphDB, importPath); err != nil {
+			fatalf("Graph import failed: %v", err)
+		}
+	}
+}
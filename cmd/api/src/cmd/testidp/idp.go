@@ -30,6 +30,7 @@ import (
 	"os"
 
 	"github.com/specterops/bloodhound/crypto"
+	"github.com/specterops/bloodhound/src/cmd/testidp/oidcidp"
 	samlidp2 "github.com/specterops/bloodhound/src/cmd/testidp/samlidp"
 
 	"github.com/crewjam/saml/logger"
@@ -90,6 +91,11 @@ func startIDP(baseURL *url.URL, organization string, cert *x509.Certificate, key
 	} else if err := createDefaultUsers(organization, idpServer.Store); err != nil {
 		fmt.Printf("failed to create default users: %v\n", err)
 	} else {
+		// The OIDC provider shares the users of the SAML IDP and signs ID tokens with the IDP key
+		oidcProvider := oidcidp.New(*baseURL, key, idpServer.Store)
+		fmt.Printf("OIDC issuer: %s\n", oidcProvider.Issuer())
+
+		goji.Handle(oidcidp.Root+"/*", oidcProvider)
 		goji.Handle("/*", idpServer)
 		goji.Serve()
 
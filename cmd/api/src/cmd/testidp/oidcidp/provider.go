@@ -0,0 +1,275 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oidcidp implements a minimal OpenID Connect provider for exercising the BloodHound OIDC login flow locally.
+// It supports the authorization code flow with PKCE only and trusts every client that asks for a code. It must never
+// be used outside of testing.
+package oidcidp
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/specterops/bloodhound/src/auth/bhoidc"
+	"github.com/specterops/bloodhound/src/cmd/testidp/samlidp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// Root is the path prefix that the provider is served under. The issuer of the provider is the base URL of the
+	// test IDP joined with this path.
+	Root = "/oidc"
+
+	authorizationCodeTTL = time.Minute
+	idTokenTTL           = 5 * time.Minute
+)
+
+var loginForm = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<body>
+<p>{{.Message}}</p>
+<form method="post" action="{{.Action}}">
+<input type="text" name="user" placeholder="user" value="" />
+<input type="password" name="password" placeholder="password" value="" />
+{{range $name, $value := .Parameters}}<input type="hidden" name="{{$name}}" value="{{$value}}" />
+{{end}}<input type="submit" value="Log In" />
+</form>
+</body>
+</html>
+`))
+
+type authorizationCode struct {
+	ClientID      string
+	RedirectURI   string
+	CodeChallenge string
+	Nonce         string
+	UserName      string
+	ExpiresAt     time.Time
+}
+
+type Provider struct {
+	issuer string
+	key    *rsa.PrivateKey
+	keyID  string
+	store  samlidp.Store
+	lock   *sync.Mutex
+	codes  map[string]authorizationCode
+}
+
+// New creates a provider that authenticates the users held by the given SAML IDP store and signs ID tokens with key
+func New(baseURL url.URL, key *rsa.PrivateKey, store samlidp.Store) *Provider {
+	issuer := baseURL
+	issuer.Path = strings.TrimSuffix(issuer.Path, "/") + Root
+
+	keyDigest := sha256.Sum256(key.PublicKey.N.Bytes())
+
+	return &Provider{
+		issuer: issuer.String(),
+		key:    key,
+		keyID:  hex.EncodeToString(keyDigest[:8]),
+		store:  store,
+		lock:   &sync.Mutex{},
+		codes:  make(map[string]authorizationCode),
+	}
+}
+
+func (s *Provider) Issuer() string {
+	return s.issuer
+}
+
+func (s *Provider) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	switch strings.TrimPrefix(request.URL.Path, Root) {
+	case bhoidc.DiscoveryDocumentPath:
+		s.serveDiscoveryDocument(response)
+
+	case "/jwks":
+		writeJSON(response, http.StatusOK, bhoidc.JSONWebKeySet{
+			Keys: []bhoidc.JSONWebKey{bhoidc.NewJSONWebKey(s.keyID, &s.key.PublicKey)},
+		})
+
+	case "/authorize":
+		s.serveAuthorize(response, request)
+
+	case "/token":
+		s.serveToken(response, request)
+
+	default:
+		http.NotFound(response, request)
+	}
+}
+
+func writeJSON(response http.ResponseWriter, statusCode int, value any) {
+	response.Header().Set("Content-Type", "application/json")
+	response.Header().Set("Cache-Control", "no-store")
+	response.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(response).Encode(value); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+func writeTokenError(response http.ResponseWriter, statusCode int, errorCode, description string) {
+	writeJSON(response, statusCode, map[string]string{
+		"error":             errorCode,
+		"error_description": description,
+	})
+}
+
+func (s *Provider) serveDiscoveryDocument(response http.ResponseWriter) {
+	writeJSON(response, http.StatusOK, bhoidc.DiscoveryDocument{
+		Issuer:                           s.issuer,
+		AuthorizationEndpoint:            s.issuer + "/authorize",
+		TokenEndpoint:                    s.issuer + "/token",
+		JWKSURI:                          s.issuer + "/jwks",
+		ScopesSupported:                  []string{bhoidc.ScopeOpenID, "email", "profile", "groups"},
+		ResponseTypesSupported:           []string{"code"},
+		IDTokenSigningAlgValuesSupported: []string{bhoidc.SigningMethodRS256},
+		CodeChallengeMethodsSupported:    []string{bhoidc.CodeChallengeMethodS256},
+	})
+}
+
+func (s *Provider) renderLoginForm(response http.ResponseWriter, request *http.Request, message string) {
+	parameters := map[string]string{}
+
+	for _, name := range []string{"client_id", "redirect_uri", "state", "nonce", "code_challenge", "code_challenge_method", "response_type", "scope"} {
+		parameters[name] = request.Form.Get(name)
+	}
+
+	response.Header().Set("Content-Type", "text/html")
+
+	if err := loginForm.Execute(response, map[string]any{
+		"Message":    message,
+		"Action":     s.issuer + "/authorize",
+		"Parameters": parameters,
+	}); err != nil {
+		log.Printf("Failed to render login form: %v", err)
+	}
+}
+
+func (s *Provider) serveAuthorize(response http.ResponseWriter, request *http.Request) {
+	if err := request.ParseForm(); err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+	} else if request.Form.Get("response_type") != "code" {
+		http.Error(response, "only the authorization code flow is supported", http.StatusBadRequest)
+	} else if request.Form.Get("code_challenge") == "" || request.Form.Get("code_challenge_method") != bhoidc.CodeChallengeMethodS256 {
+		http.Error(response, "an S256 PKCE code challenge is required", http.StatusBadRequest)
+	} else if redirectURI, err := url.Parse(request.Form.Get("redirect_uri")); err != nil || !redirectURI.IsAbs() {
+		http.Error(response, "redirect_uri must be an absolute URL", http.StatusBadRequest)
+	} else if request.Method != http.MethodPost {
+		s.renderLoginForm(response, request, "")
+	} else {
+		var user samlidp.User
+
+		if err := s.store.Get("/users/"+request.Form.Get("user"), &user); err != nil {
+			s.renderLoginForm(response, request, "Invalid username or password")
+		} else if err := bcrypt.CompareHashAndPassword(user.HashedPassword, []byte(request.Form.Get("password"))); err != nil {
+			s.renderLoginForm(response, request, "Invalid username or password")
+		} else if code, err := bhoidc.NewRandomValue(); err != nil {
+			http.Error(response, err.Error(), http.StatusInternalServerError)
+		} else {
+			s.lock.Lock()
+			s.codes[code] = authorizationCode{
+				ClientID:      request.Form.Get("client_id"),
+				RedirectURI:   redirectURI.String(),
+				CodeChallenge: request.Form.Get("code_challenge"),
+				Nonce:         request.Form.Get("nonce"),
+				UserName:      user.Name,
+				ExpiresAt:     time.Now().Add(authorizationCodeTTL),
+			}
+			s.lock.Unlock()
+
+			query := redirectURI.Query()
+			query.Set("code", code)
+			query.Set("state", request.Form.Get("state"))
+			redirectURI.RawQuery = query.Encode()
+
+			http.Redirect(response, request, redirectURI.String(), http.StatusFound)
+		}
+	}
+}
+
+// redeemCode removes the code from the provider so that it may only be exchanged once
+func (s *Provider) redeemCode(code string) (authorizationCode, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	authCode, found := s.codes[code]
+	delete(s.codes, code)
+
+	return authCode, found && time.Now().Before(authCode.ExpiresAt)
+}
+
+func (s *Provider) serveToken(response http.ResponseWriter, request *http.Request) {
+	var user samlidp.User
+
+	if request.Method != http.MethodPost {
+		writeTokenError(response, http.StatusMethodNotAllowed, "invalid_request", "token requests must be POSTed")
+	} else if err := request.ParseForm(); err != nil {
+		writeTokenError(response, http.StatusBadRequest, "invalid_request", err.Error())
+	} else if request.PostForm.Get("grant_type") != "authorization_code" {
+		writeTokenError(response, http.StatusBadRequest, "unsupported_grant_type", "only the authorization_code grant is supported")
+	} else if authCode, valid := s.redeemCode(request.PostForm.Get("code")); !valid {
+		writeTokenError(response, http.StatusBadRequest, "invalid_grant", "authorization code is invalid or expired")
+	} else if authCode.ClientID != request.PostForm.Get("client_id") || authCode.RedirectURI != request.PostForm.Get("redirect_uri") {
+		writeTokenError(response, http.StatusBadRequest, "invalid_grant", "client_id or redirect_uri does not match the authorization request")
+	} else if bhoidc.CodeChallenge(request.PostForm.Get("code_verifier")) != authCode.CodeChallenge {
+		writeTokenError(response, http.StatusBadRequest, "invalid_grant", "PKCE verification failed")
+	} else if err := s.store.Get("/users/"+authCode.UserName, &user); err != nil {
+		writeTokenError(response, http.StatusBadRequest, "invalid_grant", "user no longer exists")
+	} else if idToken, err := s.signIDToken(authCode, user); err != nil {
+		writeTokenError(response, http.StatusInternalServerError, "server_error", err.Error())
+	} else {
+		writeJSON(response, http.StatusOK, bhoidc.TokenResponse{
+			AccessToken: idToken,
+			TokenType:   "Bearer",
+			IDToken:     idToken,
+			ExpiresIn:   int(idTokenTTL.Seconds()),
+		})
+	}
+}
+
+func (s *Provider) signIDToken(authCode authorizationCode, user samlidp.User) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                s.issuer,
+		"sub":                user.Name,
+		"aud":                authCode.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(idTokenTTL).Unix(),
+		"nonce":              authCode.Nonce,
+		"email":              user.Email,
+		"email_verified":     true,
+		"preferred_username": user.Name,
+		"name":               user.CommonName,
+		"given_name":         user.GivenName,
+		"family_name":        user.Surname,
+		"groups":             user.Groups,
+	})
+
+	token.Header["kid"] = s.keyID
+	return token.SignedString(s.key)
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package oidcidp_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/src/api"
+	"github.com/specterops/bloodhound/src/auth/bhoidc"
+	"github.com/specterops/bloodhound/src/cmd/testidp/oidcidp"
+	"github.com/specterops/bloodhound/src/cmd/testidp/samlidp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func login(t *testing.T, httpClient *http.Client, authorizationURL, password string) url.Values {
+	form, err := url.ParseQuery(strings.SplitN(authorizationURL, "?", 2)[1])
+	require.Nil(t, err)
+
+	// Submit the login form the same way a browser would
+	form.Set("user", "alice")
+	form.Set("password", password)
+
+	response, err := httpClient.PostForm(strings.SplitN(authorizationURL, "?", 2)[0], form)
+	require.Nil(t, err)
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return nil
+	}
+
+	callbackURL, err := url.Parse(response.Header.Get("Location"))
+	require.Nil(t, err)
+
+	return callbackURL.Query()
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	const (
+		clientID    = "bloodhound"
+		redirectURI = "https://bloodhound.localhost/api/v2/login/oidc/testidp/callback"
+	)
+
+	var (
+		store            = &samlidp.MemoryStore{}
+		noRedirectClient = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		httpClient = api.WrapHTTPClient(noRedirectClient)
+	)
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	require.Nil(t, err)
+	require.Nil(t, store.Put("/users/alice", samlidp.User{
+		Name:           "alice",
+		HashedPassword: hashedPassword,
+		Groups:         []string{"sec-bh-admins", "Users"},
+		Email:          "alice@example.com",
+	}))
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	server := httptest.NewUnstartedServer(nil)
+	baseURL, err := url.Parse("http://" + server.Listener.Addr().String())
+	require.Nil(t, err)
+
+	provider := oidcidp.New(*baseURL, key, store)
+	server.Config.Handler = provider
+	server.Start()
+	defer server.Close()
+
+	document, err := bhoidc.FetchDiscoveryDocument(httpClient, context.Background(), provider.Issuer())
+	require.Nil(t, err)
+
+	authRequest := bhoidc.AuthorizationRequest{
+		ClientID:     clientID,
+		RedirectURI:  redirectURI,
+		Scopes:       bhoidc.Scopes("email groups"),
+		State:        "state",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+	}
+
+	authorizationURL, err := authRequest.URL(document)
+	require.Nil(t, err)
+
+	// A bad password renders the login form again instead of redirecting
+	require.Nil(t, login(t, noRedirectClient, authorizationURL, "wrong"))
+
+	// A wrong PKCE verifier can not redeem the code
+	callbackQuery := login(t, noRedirectClient, authorizationURL, "hunter2")
+	require.Equal(t, "state", callbackQuery.Get("state"))
+
+	_, err = bhoidc.ExchangeCode(httpClient, context.Background(), document, clientID, "", redirectURI, callbackQuery.Get("code"), "wrong")
+	require.NotNil(t, err)
+
+	// Happy path
+	callbackQuery = login(t, noRedirectClient, authorizationURL, "hunter2")
+
+	tokenResponse, err := bhoidc.ExchangeCode(httpClient, context.Background(), document, clientID, "", redirectURI, callbackQuery.Get("code"), authRequest.CodeVerifier)
+	require.Nil(t, err)
+
+	keySet, err := bhoidc.FetchJSONWebKeySet(httpClient, context.Background(), document.JWKSURI)
+	require.Nil(t, err)
+
+	claims, err := bhoidc.ValidateIDToken(tokenResponse.IDToken, keySet, document.Issuer, clientID, authRequest.Nonce, time.Now())
+	require.Nil(t, err)
+	require.Equal(t, []string{"alice@example.com"}, bhoidc.ClaimValues(claims, "email"))
+	require.Equal(t, []string{"sec-bh-admins", "Users"}, bhoidc.ClaimValues(claims, "groups"))
+
+	// Codes are single use
+	_, err = bhoidc.ExchangeCode(httpClient, context.Background(), document, clientID, "", redirectURI, callbackQuery.Get("code"), authRequest.CodeVerifier)
+	require.NotNil(t, err)
+}
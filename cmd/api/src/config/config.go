@@ -152,6 +152,7 @@ type Configuration struct {
 	TLS                    TLSConfiguration          `json:"tls"`
 	Database               DatabaseConfiguration     `json:"database"`
 	Neo4J                  DatabaseConfiguration     `json:"neo4j"`
+	GraphDriver            string                    `json:"graph_driver"`
 	Crypto                 CryptoConfiguration       `json:"crypto"`
 	SAML                   SAMLConfiguration         `json:"saml"`
 	SpecterAuth            SpecterAuthConfiguration  `json:"specter_auth"`
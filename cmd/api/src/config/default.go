@@ -59,6 +59,7 @@ func NewDefaultConfiguration() (Configuration, error) {
 				Connection:            "",
 				MaxConcurrentSessions: 10,
 			},
+			GraphDriver: "neo4j",
 			Crypto: CryptoConfiguration{
 				JWT: JWTConfiguration{
 					SigningKey: jwtSigningKey,
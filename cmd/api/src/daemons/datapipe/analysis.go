@@ -20,66 +20,165 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/specterops/bloodhound/analysis"
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	azureAnalysis "github.com/specterops/bloodhound/analysis/azure"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/log"
 	"github.com/specterops/bloodhound/src/analysis/ad"
 	"github.com/specterops/bloodhound/src/analysis/azure"
-	"github.com/specterops/bloodhound/src/config"
 	"github.com/specterops/bloodhound/src/database"
 	"github.com/specterops/bloodhound/src/services/agi"
+	"github.com/specterops/bloodhound/src/services/chokepoint"
 	"github.com/specterops/bloodhound/src/services/dataquality"
-	"github.com/specterops/bloodhound/analysis"
-	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
-	"github.com/specterops/bloodhound/dawgs/graph"
-	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/src/services/exposure"
+	"github.com/specterops/bloodhound/src/services/findings"
+	"github.com/specterops/bloodhound/src/services/postprocessing"
 )
 
-func RunAnalysisOperations(ctx context.Context, db database.Database, graphDB graph.Database, cfg config.Configuration) error {
-	var (
-		collector = &errors.ErrorCollector{}
-	)
-
-	if err := adAnalysis.FixWellKnownNodeTypes(ctx, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("fix well known node types failed: %w", err))
-	}
-
-	if err := adAnalysis.RunDomainAssociations(ctx, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("domain association and pruning failed: %w", err))
-	}
-
-	if err := adAnalysis.LinkWellKnownGroups(ctx, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("well known group linking failed: %w", err))
-	}
+// Names of the built-in post-processing stages
+const (
+	StageFixWellKnownNodeTypes          = "fix-well-known-node-types"
+	StageDomainAssociations             = "domain-associations"
+	StageLinkWellKnownGroups            = "link-well-known-groups"
+	StageAssetGroupIsolationTagging     = "asset-group-isolation-tagging"
+	StageActiveDirectoryTierZeroTagging = "ad-tier-zero-tagging"
+	StageAzureTierZeroTagging           = "azure-tier-zero-tagging"
+	StageActiveDirectoryPost            = "ad-post-processing"
+	StageAzurePost                      = "azure-post-processing"
+	StagePostProcessingRules            = "post-processing-rules"
+	StageAssetGroupIsolationCollections = "asset-group-isolation-collections"
+	StageChokePointAnalysis             = "choke-point-analysis"
+	StageExposureScoring                = "exposure-scoring"
+	StageAttackPathFindings             = "attack-path-findings"
+	StageDataQuality                    = "data-quality"
+)
 
-	if err := updateAssetGroupIsolationTags(ctx, db, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("asset group isolation tagging failed: %w", err))
+// withoutStats adapts a post-processing function that does not create or delete relationships to a stage function
+func withoutStats(delegate func(ctx context.Context, db graph.Database) error) analysis.PostProcessingStageFunc {
+	return func(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+		return nil, delegate(ctx, db)
 	}
+}
 
-	if err := ParallelTagActiveDirectoryTierZero(ctx, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("active directory tier zero tagging failed: %w", err))
-	}
+func postProcessingStages(db database.Database) []analysis.PostProcessingStage {
+	return []analysis.PostProcessingStage{{
+		Name:       StageFixWellKnownNodeTypes,
+		Idempotent: true,
+		Run:        withoutStats(adAnalysis.FixWellKnownNodeTypes),
+	}, {
+		Name:       StageDomainAssociations,
+		DependsOn:  []string{StageFixWellKnownNodeTypes},
+		Idempotent: true,
+		Run:        withoutStats(adAnalysis.RunDomainAssociations),
+	}, {
+		Name:       StageLinkWellKnownGroups,
+		DependsOn:  []string{StageDomainAssociations},
+		Idempotent: true,
+		Run:        withoutStats(adAnalysis.LinkWellKnownGroups),
+	}, {
+		Name:       StageAssetGroupIsolationTagging,
+		DependsOn:  []string{StageDomainAssociations},
+		Idempotent: true,
+		Run: withoutStats(func(ctx context.Context, graphDB graph.Database) error {
+			return updateAssetGroupIsolationTags(ctx, db, graphDB)
+		}),
+	}, {
+		Name:       StageActiveDirectoryTierZeroTagging,
+		DependsOn:  []string{StageAssetGroupIsolationTagging},
+		Idempotent: true,
+		Run:        withoutStats(ParallelTagActiveDirectoryTierZero),
+	}, {
+		Name:       StageAzureTierZeroTagging,
+		DependsOn:  []string{StageAssetGroupIsolationTagging},
+		Idempotent: true,
+		Run:        withoutStats(ParallelTagAzureTierZero),
+	}, {
+		Name:       StageActiveDirectoryPost,
+		Produces:   adAnalysis.PostProcessedRelationships(),
+		DependsOn:  []string{StageLinkWellKnownGroups},
+		Idempotent: true,
+		Run:        ad.Post,
+	}, {
+		Name:       StageAzurePost,
+		Produces:   azureAnalysis.AzurePostProcessedRelationships(),
+		Idempotent: true,
+		Run:        azure.Post,
+	}, {
+		// Relationships created by the previous evaluation of the rules are deleted before the rules are evaluated
+		Name:       StagePostProcessingRules,
+		DependsOn:  []string{StageActiveDirectoryPost, StageAzurePost},
+		Idempotent: true,
+		Run: func(ctx context.Context, graphDB graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+			return postprocessing.RunPostProcessingRules(ctx, db, graphDB)
+		},
+	}, {
+		Name:       StageAssetGroupIsolationCollections,
+		DependsOn:  []string{StageActiveDirectoryTierZeroTagging, StageAzureTierZeroTagging},
+		Idempotent: true,
+		Run: withoutStats(func(ctx context.Context, graphDB graph.Database) error {
+			return agi.RunAssetGroupIsolationCollections(ctx, db, graphDB, analysis.GetNodeKindDisplayLabel)
+		}),
+	}, {
+		Name:       StageChokePointAnalysis,
+		DependsOn:  []string{StageActiveDirectoryTierZeroTagging, StageAzureTierZeroTagging, StageActiveDirectoryPost, StageAzurePost},
+		Idempotent: true,
+		Run: withoutStats(func(ctx context.Context, graphDB graph.Database) error {
+			return chokepoint.SaveChokePoints(ctx, db, graphDB)
+		}),
+	}, {
+		// Exposure history is only appended to for principals whose exposure changed
+		Name:       StageExposureScoring,
+		DependsOn:  []string{StageActiveDirectoryPost, StageAzurePost},
+		Idempotent: true,
+		Run: withoutStats(func(ctx context.Context, graphDB graph.Database) error {
+			return exposure.SaveExposure(ctx, db, graphDB)
+		}),
+	}, {
+		// Findings that are no longer detected are marked as resolved rather than deleted
+		Name:       StageAttackPathFindings,
+		DependsOn:  []string{StageActiveDirectoryTierZeroTagging, StageActiveDirectoryPost},
+		Idempotent: true,
+		Run: withoutStats(func(ctx context.Context, graphDB graph.Database) error {
+			return findings.SaveAttackPathFindings(ctx, db, graphDB)
+		}),
+	}, {
+		// Each run appends a new set of data quality stats
+		Name:      StageDataQuality,
+		DependsOn: []string{StageActiveDirectoryPost, StageAzurePost},
+		Run: withoutStats(func(ctx context.Context, graphDB graph.Database) error {
+			return dataquality.SaveDataQuality(ctx, db, graphDB)
+		}),
+	}}
+}
 
-	if err := ParallelTagAzureTierZero(ctx, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("azure tier zero tagging failed: %w", err))
-	}
+// NewPostProcessingRegistry returns a registry that contains the built-in post-processing stages
+func NewPostProcessingRegistry(db database.Database) *analysis.PostProcessingRegistry {
+	registry := analysis.NewPostProcessingRegistry()
 
-	if stats, err := ad.Post(ctx, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("error during ad post: %w", err))
-	} else {
-		stats.LogStats()
+	for _, stage := range postProcessingStages(db) {
+		if err := registry.Register(stage); err != nil {
+			log.Errorf("Failed registering post-processing stage %s: %v", stage.Name, err)
+		}
 	}
 
-	if stats, err := azure.Post(ctx, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("error during azure post: %w", err))
-	} else {
-		stats.LogStats()
-	}
+	return registry
+}
 
-	if err := agi.RunAssetGroupIsolationCollections(ctx, db, graphDB, analysis.GetNodeKindDisplayLabel); err != nil {
-		collector.Collect(fmt.Errorf("asset group isolation collection failed: %w", err))
-	}
+// RunAnalysisOperations runs the given post-processing stages and returns the result of each stage along with an
+// error that collects every stage failure
+func RunAnalysisOperations(ctx context.Context, graphDB graph.Database, stages []analysis.PostProcessingStage) ([]analysis.PostProcessingStageResult, error) {
+	var (
+		collector = &errors.ErrorCollector{}
+		results   = analysis.RunPostProcessingStages(ctx, graphDB, stages)
+	)
 
-	if err := dataquality.SaveDataQuality(ctx, db, graphDB); err != nil {
-		collector.Collect(fmt.Errorf("error saving data quality stat: %v", err))
+	for _, result := range results {
+		if result.Err != nil {
+			collector.Collect(fmt.Errorf("post-processing stage %s failed: %w", result.Stage.Name, result.Err))
+		}
 	}
 
-	return collector.Return()
+	return results, collector.Return()
 }
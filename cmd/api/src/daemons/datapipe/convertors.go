@@ -180,3 +180,100 @@ func convertContainerData(data []ein.Container) ConvertedData {
 
 	return converted
 }
+
+func convertAIACAData(data []ein.AIACA) ConvertedData {
+	converted := ConvertedData{}
+
+	for _, aiaca := range data {
+		converted.NodeProps = append(converted.NodeProps, ein.ConvertObjectToNode(ein.IngestBase(aiaca), ad.AIACA))
+		converted.RelProps = append(converted.RelProps, ein.ParseACEData(aiaca.Aces, aiaca.ObjectIdentifier, ad.AIACA)...)
+
+		if container := ein.ParseObjectContainer(ein.IngestBase(aiaca), ad.AIACA); container.IsValid() {
+			converted.RelProps = append(converted.RelProps, container)
+		}
+	}
+
+	return converted
+}
+
+func convertRootCAData(data []ein.RootCA) ConvertedData {
+	converted := ConvertedData{}
+
+	for _, rootCA := range data {
+		converted.NodeProps = append(converted.NodeProps, ein.ConvertObjectToNode(rootCA.IngestBase, ad.RootCA))
+		converted.RelProps = append(converted.RelProps, ein.ParseACEData(rootCA.Aces, rootCA.ObjectIdentifier, ad.RootCA)...)
+
+		if container := ein.ParseObjectContainer(rootCA.IngestBase, ad.RootCA); container.IsValid() {
+			converted.RelProps = append(converted.RelProps, container)
+		}
+
+		converted.RelProps = append(converted.RelProps, ein.ParseRootCAMiscData(rootCA)...)
+	}
+
+	return converted
+}
+
+func convertEnterpriseCAData(data []ein.EnterpriseCA) ConvertedData {
+	converted := ConvertedData{}
+
+	for _, enterpriseCA := range data {
+		converted.NodeProps = append(converted.NodeProps, ein.ConvertEnterpriseCAToNode(enterpriseCA))
+		converted.RelProps = append(converted.RelProps, ein.ParseACEData(enterpriseCA.Aces, enterpriseCA.ObjectIdentifier, ad.EnterpriseCA)...)
+
+		if container := ein.ParseObjectContainer(enterpriseCA.IngestBase, ad.EnterpriseCA); container.IsValid() {
+			converted.RelProps = append(converted.RelProps, container)
+		}
+
+		converted.RelProps = append(converted.RelProps, ein.ParseEnterpriseCAMiscData(enterpriseCA)...)
+	}
+
+	return converted
+}
+
+func convertNTAuthStoreData(data []ein.NTAuthStore) ConvertedData {
+	converted := ConvertedData{}
+
+	for _, ntAuthStore := range data {
+		converted.NodeProps = append(converted.NodeProps, ein.ConvertObjectToNode(ntAuthStore.IngestBase, ad.NTAuthStore))
+		converted.RelProps = append(converted.RelProps, ein.ParseACEData(ntAuthStore.Aces, ntAuthStore.ObjectIdentifier, ad.NTAuthStore)...)
+
+		if container := ein.ParseObjectContainer(ntAuthStore.IngestBase, ad.NTAuthStore); container.IsValid() {
+			converted.RelProps = append(converted.RelProps, container)
+		}
+
+		converted.RelProps = append(converted.RelProps, ein.ParseNTAuthStoreData(ntAuthStore)...)
+	}
+
+	return converted
+}
+
+func convertCertTemplateData(data []ein.CertTemplate) ConvertedData {
+	converted := ConvertedData{}
+
+	for _, certTemplate := range data {
+		converted.NodeProps = append(converted.NodeProps, ein.ConvertObjectToNode(ein.IngestBase(certTemplate), ad.CertTemplate))
+		converted.RelProps = append(converted.RelProps, ein.ParseACEData(certTemplate.Aces, certTemplate.ObjectIdentifier, ad.CertTemplate)...)
+
+		if container := ein.ParseObjectContainer(ein.IngestBase(certTemplate), ad.CertTemplate); container.IsValid() {
+			converted.RelProps = append(converted.RelProps, container)
+		}
+	}
+
+	return converted
+}
+
+func convertGenericData(data []ein.GenericObject) ConvertedGenericData {
+	converted := ConvertedGenericData{}
+
+	for _, object := range data {
+		switch object.Kind {
+		case ein.GenericObjectKindNode:
+			converted.NodeProps = append(converted.NodeProps, ein.ConvertGenericNode(object.Node))
+
+		case ein.GenericObjectKindEdge:
+			converted.RelProps = append(converted.RelProps, ein.ConvertGenericEdge(object.Edge))
+		}
+	}
+
+	return converted
+}
@@ -24,6 +24,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/specterops/bloodhound/analysis"
 	"github.com/specterops/bloodhound/cache"
 	"github.com/specterops/bloodhound/dawgs/graph"
 	"github.com/specterops/bloodhound/log"
@@ -41,6 +42,7 @@ const (
 type Tasker interface {
 	NotifyOfFileUploadJobStatus(task model.FileUploadJob)
 	RequestAnalysis()
+	RequestPostProcessingStages(names []string) error
 	GetStatus() model.DatapipeStatusWrapper
 }
 
@@ -51,11 +53,17 @@ type Daemon struct {
 	cache                         cache.Cache
 	cfg                           config.Configuration
 	analysisRequested             bool
+	requestedStages               []string
+	postProcessing                *analysis.PostProcessingRegistry
+	stageResults                  map[string]analysis.PostProcessingStageResult
 	tickInterval                  time.Duration
 	status                        model.DatapipeStatusWrapper
 	ctx                           context.Context
 	fileUploadJobIDsUnderAnalysis []int64
 	completedFileUploadJobIDs     []int64
+	customKinds                   *customKindCollector
+	collectionScopes              *collectionScopeCollector
+	sessionValidity               sessionValidity
 
 	lock                   *sync.Mutex
 	clearOrphanedFilesLock *sync.Mutex
@@ -75,6 +83,10 @@ func NewDaemon(cfg config.Configuration, db database.Database, graphdb graph.Dat
 		ctx:     context.Background(),
 
 		analysisRequested:      false,
+		postProcessing:         NewPostProcessingRegistry(db),
+		stageResults:           map[string]analysis.PostProcessingStageResult{},
+		customKinds:            newCustomKindCollector(),
+		collectionScopes:       newCollectionScopeCollector(),
 		lock:                   &sync.Mutex{},
 		clearOrphanedFilesLock: &sync.Mutex{},
 		tickInterval:           tickInterval,
@@ -89,8 +101,92 @@ func (s *Daemon) RequestAnalysis() {
 	s.setAnalysisRequested(true)
 }
 
+// RegisterPostProcessingStage adds a stage to the end of post-processing. Stages must be registered before the daemon
+// is started.
+func (s *Daemon) RegisterPostProcessingStage(stage analysis.PostProcessingStage) error {
+	return s.postProcessing.Register(stage)
+}
+
+// RequestPostProcessingStages requests that the named post-processing stages be re-run on their own. Requested stages
+// are merged with any stages already waiting to run and are dropped if a full analysis runs first.
+func (s *Daemon) RequestPostProcessingStages(names []string) error {
+	if _, err := s.postProcessing.Select(names...); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requestedStages = append(s.requestedStages, names...)
+	return nil
+}
+
 func (s *Daemon) GetStatus() model.DatapipeStatusWrapper {
-	return s.status
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	status := s.status
+	stages := s.postProcessing.Stages()
+	status.PostProcessingStages = make([]model.PostProcessingStageStatus, 0, len(stages))
+
+	for _, stage := range stages {
+		status.PostProcessingStages = append(status.PostProcessingStages, newPostProcessingStageStatus(stage, s.stageResults[stage.Name]))
+	}
+
+	return status
+}
+
+func statsCounts(counts map[graph.Kind]*int32) map[string]int32 {
+	formatted := make(map[string]int32, len(counts))
+
+	for kind, count := range counts {
+		formatted[kind.String()] = *count
+	}
+
+	return formatted
+}
+
+func newPostProcessingStageStatus(stage analysis.PostProcessingStage, result analysis.PostProcessingStageResult) model.PostProcessingStageStatus {
+	status := model.PostProcessingStageStatus{
+		Name:                 stage.Name,
+		Produces:             stage.Produces.Strings(),
+		DependsOn:            stage.DependsOn,
+		Idempotent:           stage.Idempotent,
+		LastRunAt:            result.StartedAt,
+		DurationMS:           result.Duration.Milliseconds(),
+		RelationshipsCreated: map[string]int32{},
+		RelationshipsDeleted: map[string]int32{},
+	}
+
+	if result.Stats != nil {
+		status.RelationshipsCreated = statsCounts(result.Stats.RelationshipsCreated)
+		status.RelationshipsDeleted = statsCounts(result.Stats.RelationshipsDeleted)
+	}
+
+	if result.Err != nil {
+		status.Error = result.Err.Error()
+	}
+
+	return status
+}
+
+func (s *Daemon) recordStageResults(results []analysis.PostProcessingStageResult) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, result := range results {
+		s.stageResults[result.Stage.Name] = result
+	}
+}
+
+func (s *Daemon) takeRequestedStages() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	requestedStages := s.requestedStages
+	s.requestedStages = nil
+
+	return requestedStages
 }
 
 func (s *Daemon) getAnalysisRequested() bool {
@@ -113,7 +209,13 @@ func (s *Daemon) analyze() {
 	s.status.Update(model.DatapipeStatusAnalyzing, false)
 	log.Measure(log.LevelInfo, "Starting analysis")()
 
-	if err := RunAnalysisOperations(s.ctx, s.db, s.graphdb, s.cfg); err != nil {
+	// A full analysis runs every stage so any individually requested stages are no longer needed
+	s.takeRequestedStages()
+
+	results, err := RunAnalysisOperations(s.ctx, s.graphdb, s.postProcessing.Stages())
+	s.recordStageResults(results)
+
+	if err != nil {
 		log.Errorf("Analysis failed: %v", err)
 		s.failJobsUnderAnalysis()
 
@@ -132,6 +234,36 @@ func (s *Daemon) analyze() {
 	s.setAnalysisRequested(false)
 }
 
+// runRequestedStages re-runs the post-processing stages requested since the last run. Jobs under analysis are left
+// untouched since only a full analysis completes them.
+func (s *Daemon) runRequestedStages(names []string) {
+	if s.cfg.DisableAnalysis {
+		return
+	}
+
+	if stages, err := s.postProcessing.Select(names...); err != nil {
+		log.Errorf("Failed selecting requested post-processing stages: %v", err)
+	} else {
+		s.status.Update(model.DatapipeStatusAnalyzing, false)
+		defer log.Measure(log.LevelInfo, "Requested post-processing stages finished")()
+
+		results, err := RunAnalysisOperations(s.ctx, s.graphdb, stages)
+		s.recordStageResults(results)
+
+		if err != nil {
+			log.Errorf("Requested post-processing stages failed: %v", err)
+		}
+
+		if entityPanelCachingFlag, err := s.db.GetFlagByKey(appcfg.FeatureEntityPanelCaching); err != nil {
+			log.Errorf("Error retrieving entity panel caching flag: %v", err)
+		} else {
+			resetCache(s.cache, entityPanelCachingFlag.Enabled)
+		}
+
+		s.status.Update(model.DatapipeStatusIdle, false)
+	}
+}
+
 func resetCache(cacher cache.Cache, cacheEnabled bool) {
 	if err := cacher.Reset(); err != nil {
 		log.Errorf("Error while resetting the cache: %v", err)
@@ -159,11 +291,13 @@ func (s *Daemon) Start() {
 	defer pruningTicker.Stop()
 
 	s.clearOrphanedData()
+	s.retireExpiredSessions()
 
 	for {
 		select {
 		case <-pruningTicker.C:
 			s.clearOrphanedData()
+			s.retireExpiredSessions()
 		case <-datapipeLoopTimer.C:
 			fileupload.ProcessStaleFileUploadJobs(s.db)
 
@@ -172,6 +306,8 @@ func (s *Daemon) Start() {
 				s.analyze()
 			} else if s.getAnalysisRequested() {
 				s.analyze()
+			} else if requestedStages := s.takeRequestedStages(); len(requestedStages) > 0 {
+				s.runRequestedStages(requestedStages)
 			} else {
 				s.ingestAvailableTasks()
 			}
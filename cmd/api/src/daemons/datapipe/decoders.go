@@ -0,0 +1,202 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/slices"
+)
+
+// IngestCountThreshold is the maximum number of data elements held in memory before they are converted and handed
+// to the graph batch
+const IngestCountThreshold = 500
+
+const (
+	ErrInvalidJSON     = errors.Error("ingest file is not a valid JSON object")
+	ErrMetaTagNotFound = errors.Error("no meta tag found in ingest file")
+	ErrDataTagNotFound = errors.Error("no data tag found in ingest file")
+	ErrInvalidDataType = errors.Error("meta tag contains an unknown data type")
+)
+
+// IngestSource opens a new reader over the contents of an ingest file. Collectors are free to write the meta and data
+// tags in any order so ingest files are read twice: once to validate the meta tag and once to stream the data tag.
+type IngestSource func() (io.ReadCloser, error)
+
+func expectDelimiter(decoder *json.Decoder, expected json.Delim) error {
+	if token, err := decoder.Token(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	} else if delimiter, typeOK := token.(json.Delim); !typeOK || delimiter != expected {
+		return fmt.Errorf("%w: expected %s but found %v", ErrInvalidJSON, expected, token)
+	}
+
+	return nil
+}
+
+func nextKey(decoder *json.Decoder) (string, error) {
+	if token, err := decoder.Token(); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	} else if key, typeOK := token.(string); !typeOK {
+		return "", fmt.Errorf("%w: expected an object key but found %v", ErrInvalidJSON, token)
+	} else {
+		return key, nil
+	}
+}
+
+// skipValue advances the decoder past the next value without retaining it. This allows the data tag of an ingest
+// file to be stepped over without holding it in memory.
+func skipValue(decoder *json.Decoder) error {
+	depth := 0
+
+	for {
+		if token, err := decoder.Token(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+		} else if delimiter, isDelimiter := token.(json.Delim); isDelimiter {
+			switch delimiter {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// ValidateMetaTag scans the top level object of an ingest file for its meta tag and validates it
+func ValidateMetaTag(reader io.Reader) (Metadata, error) {
+	var (
+		decoder = json.NewDecoder(reader)
+		meta    Metadata
+	)
+
+	if err := expectDelimiter(decoder, '{'); err != nil {
+		return meta, err
+	}
+
+	for decoder.More() {
+		if key, err := nextKey(decoder); err != nil {
+			return meta, err
+		} else if key != "meta" {
+			if err := skipValue(decoder); err != nil {
+				return meta, err
+			}
+		} else if err := decoder.Decode(&meta); err != nil {
+			return meta, fmt.Errorf("%w: %v", ErrMetaTagNotFound, err)
+		} else if !slices.Contains(AllIngestDataTypes(), meta.Type) {
+			return meta, fmt.Errorf("%w: %s", ErrInvalidDataType, meta.Type)
+		} else {
+			return meta, nil
+		}
+	}
+
+	return meta, ErrMetaTagNotFound
+}
+
+// SeekToDataTag advances the decoder to the first element of the data tag of an ingest file
+func SeekToDataTag(decoder *json.Decoder) error {
+	if err := expectDelimiter(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		if key, err := nextKey(decoder); err != nil {
+			return err
+		} else if key != "data" {
+			if err := skipValue(decoder); err != nil {
+				return err
+			}
+		} else {
+			return expectDelimiter(decoder, '[')
+		}
+	}
+
+	return ErrDataTagNotFound
+}
+
+// ElementValidator validates each element of the data tag before it is decoded for ingest
+type ElementValidator interface {
+	// ValidateElement returns false if the element failed validation and must not be ingested
+	ValidateElement(element json.RawMessage) bool
+
+	// AcceptElement records that the last validated element was decoded for ingest
+	AcceptElement()
+
+	// RejectElement records that the last validated element could not be decoded for ingest
+	RejectElement(err error)
+}
+
+// Validatable is implemented by ingest models that check their own content once decoded. Elements that fail
+// validation are rejected instead of being ingested.
+type Validatable interface {
+	Validate() error
+}
+
+// decodeBatches decodes the remaining elements of the data tag one at a time and hands them to the delegate in
+// batches of at most IngestCountThreshold elements. Elements that fail validation are skipped.
+func decodeBatches[T any](decoder *json.Decoder, validator ElementValidator, delegate func(batch []T)) error {
+	buffer := make([]T, 0, IngestCountThreshold)
+
+	for decoder.More() {
+		var (
+			element json.RawMessage
+			next    T
+		)
+
+		if err := decoder.Decode(&element); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+		} else if !validator.ValidateElement(element) {
+			continue
+		} else if err := json.Unmarshal(element, &next); err != nil {
+			validator.RejectElement(err)
+			continue
+		} else if validatable, isValidatable := any(next).(Validatable); isValidatable {
+			if err := validatable.Validate(); err != nil {
+				validator.RejectElement(err)
+				continue
+			}
+		}
+
+		validator.AcceptElement()
+
+		if buffer = append(buffer, next); len(buffer) >= IngestCountThreshold {
+			delegate(buffer)
+			buffer = make([]T, 0, IngestCountThreshold)
+		}
+	}
+
+	if len(buffer) > 0 {
+		delegate(buffer)
+	}
+
+	return nil
+}
+
+func readMetaTag(source IngestSource) (Metadata, error) {
+	if reader, err := source(); err != nil {
+		return Metadata{}, err
+	} else {
+		defer reader.Close()
+		return ValidateMetaTag(reader)
+	}
+}
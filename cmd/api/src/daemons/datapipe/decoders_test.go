@@ -0,0 +1,166 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/specterops/bloodhound/ein"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMetaTag(t *testing.T) {
+	meta, err := ValidateMetaTag(strings.NewReader(`{"data": [{"a": [1, {"b": 2}]}, {}], "meta": {"type": "users", "version": 5, "count": 2}}`))
+	require.Nil(t, err)
+	require.Equal(t, DataTypeUser, meta.Type)
+	require.Equal(t, 5, meta.Version)
+
+	meta, err = ValidateMetaTag(strings.NewReader(`{"meta": {"type": "computers", "version": 6}, "data": []}`))
+	require.Nil(t, err)
+	require.Equal(t, DataTypeComputer, meta.Type)
+
+	_, err = ValidateMetaTag(strings.NewReader(`{"data": []}`))
+	require.ErrorIs(t, err, ErrMetaTagNotFound)
+
+	_, err = ValidateMetaTag(strings.NewReader(`{"meta": {"type": "unknown"}, "data": []}`))
+	require.ErrorIs(t, err, ErrInvalidDataType)
+
+	_, err = ValidateMetaTag(strings.NewReader(`[]`))
+	require.ErrorIs(t, err, ErrInvalidJSON)
+
+	_, err = ValidateMetaTag(strings.NewReader(`{"data": [{"a": 1}`))
+	require.ErrorIs(t, err, ErrInvalidJSON)
+}
+
+func TestSeekToDataTag(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{"meta": {"type": "users", "version": 5}, "data": [{"ObjectIdentifier": "A"}]}`))
+	require.Nil(t, SeekToDataTag(decoder))
+	require.True(t, decoder.More())
+
+	decoder = json.NewDecoder(strings.NewReader(`{"meta": {"type": "users", "version": 5}}`))
+	require.ErrorIs(t, SeekToDataTag(decoder), ErrDataTagNotFound)
+
+	decoder = json.NewDecoder(strings.NewReader(`{"data": {}}`))
+	require.ErrorIs(t, SeekToDataTag(decoder), ErrInvalidJSON)
+}
+
+type countingValidator struct {
+	numAccepted int
+	numRejected int
+}
+
+func (s *countingValidator) ValidateElement(element json.RawMessage) bool {
+	return string(element) != "null"
+}
+
+func (s *countingValidator) AcceptElement() {
+	s.numAccepted++
+}
+
+func (s *countingValidator) RejectElement(err error) {
+	s.numRejected++
+}
+
+func TestDecodeBatches(t *testing.T) {
+	const numElements = IngestCountThreshold*2 + 1
+
+	var (
+		builder    strings.Builder
+		batchSizes []int
+		decoded    []int
+	)
+
+	builder.WriteString(`{"data": [`)
+
+	for idx := 0; idx < numElements; idx++ {
+		if idx > 0 {
+			builder.WriteString(",")
+		}
+
+		builder.WriteString(fmt.Sprintf("%d", idx))
+	}
+
+	builder.WriteString(`]}`)
+
+	var (
+		decoder   = json.NewDecoder(strings.NewReader(builder.String()))
+		validator = &countingValidator{}
+	)
+
+	require.Nil(t, SeekToDataTag(decoder))
+	require.Nil(t, decodeBatches(decoder, validator, func(batch []int) {
+		batchSizes = append(batchSizes, len(batch))
+		decoded = append(decoded, batch...)
+	}))
+
+	require.Equal(t, []int{IngestCountThreshold, IngestCountThreshold, 1}, batchSizes)
+	require.Len(t, decoded, numElements)
+	require.Equal(t, numElements-1, decoded[numElements-1])
+	require.Equal(t, numElements, validator.numAccepted)
+
+	validator = &countingValidator{}
+	decoded = decoded[:0]
+	decoder = json.NewDecoder(strings.NewReader(`{"data": [1, null, "two", 3]}`))
+	require.Nil(t, SeekToDataTag(decoder))
+	require.Nil(t, decodeBatches(decoder, validator, func(batch []int) {
+		decoded = append(decoded, batch...)
+	}))
+	require.Equal(t, []int{1, 3}, decoded)
+	require.Equal(t, 2, validator.numAccepted)
+	require.Equal(t, 1, validator.numRejected)
+
+	decoder = json.NewDecoder(strings.NewReader(`{"data": [1, {"a": 2]}`))
+	require.Nil(t, SeekToDataTag(decoder))
+	require.True(t, errors.Is(decodeBatches(decoder, validator, func(batch []int) {}), ErrInvalidJSON))
+}
+
+func TestDecodeBatches_Validatable(t *testing.T) {
+	var (
+		decoded   []ein.GenericObject
+		validator = &countingValidator{}
+		decoder   = json.NewDecoder(strings.NewReader(`{"data": [
+			{"kind": "node", "data": {"kinds": ["GHUser"], "identity_properties": ["login"], "properties": {"login": "octocat"}}},
+			{"kind": "node", "data": {"kinds": ["GH User"], "properties": {"objectid": "1"}}},
+			{"kind": "node", "data": {"kinds": ["GHUser"], "properties": {"name": "missing objectid"}}},
+			{"kind": "edge", "data": {"kind": "GHMemberOf", "start": {"kind": "GHUser", "identity": {"login": "octocat"}}, "end": {"kind": "GHTeam", "identity": {"objectid": "42"}}}},
+			{"kind": "edge", "data": {"kind": "GHMemberOf", "start": {"kind": "GHUser", "identity": {}}, "end": {"kind": "GHTeam", "identity": {"objectid": "42"}}}},
+			{"kind": "group", "data": {}}
+		]}`))
+	)
+
+	require.Nil(t, SeekToDataTag(decoder))
+	require.Nil(t, decodeBatches(decoder, validator, func(batch []ein.GenericObject) {
+		decoded = append(decoded, batch...)
+	}))
+
+	require.Len(t, decoded, 2)
+	require.Equal(t, 2, validator.numAccepted)
+	require.Equal(t, 4, validator.numRejected)
+
+	converted := convertGenericData(decoded)
+	require.Len(t, converted.NodeProps, 1)
+	require.Equal(t, "GHUser", converted.NodeProps[0].IdentityKind.String())
+	require.Equal(t, []string{"login"}, converted.NodeProps[0].IdentityProperties)
+
+	require.Len(t, converted.RelProps, 1)
+	require.Equal(t, []string{"login"}, converted.RelProps[0].StartIdentityProperties)
+	require.Equal(t, []string{"objectid"}, converted.RelProps[0].EndIdentityProperties)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"time"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/model/appcfg"
+)
+
+// RetireExpiredSessions deletes the session relationships that stopped being valid before the given time
+func RetireExpiredSessions(ctx context.Context, db graph.Database, expiredBefore time.Time) (int64, error) {
+	var numRetired int64
+
+	err := db.WriteTransaction(ctx, func(tx graph.Transaction) error {
+		criteria := query.And(
+			query.Kind(query.Relationship(), ad.HasSession),
+			analysis.ExpiredCriteria(expiredBefore),
+		)
+
+		if numExpired, err := tx.Relationships().Filter(criteria).Count(); err != nil || numExpired == 0 {
+			return err
+		} else {
+			numRetired = numExpired
+			return tx.Relationships().Filter(criteria).Delete()
+		}
+	})
+
+	return numRetired, err
+}
+
+// retireExpiredSessions deletes the sessions that expired longer ago than the configured retention. Expired sessions
+// are retained until then so that the paths that existed at an earlier time can still be queried.
+func (s *Daemon) retireExpiredSessions() {
+	window := appcfg.GetSessionValidity(s.db)
+
+	if numRetired, err := RetireExpiredSessions(s.ctx, s.graphdb, time.Now().UTC().Add(-window.Retention)); err != nil {
+		log.Errorf("Failed retiring expired sessions: %v", err)
+	} else if numRetired > 0 {
+		log.Infof("Retired %d sessions that expired more than %s ago", numRetired, window.Retention)
+	}
+}
@@ -18,7 +18,6 @@ package datapipe
 
 import (
 	"encoding/json"
-	"io"
 	"strings"
 	"time"
 
@@ -28,119 +27,166 @@ import (
 	"github.com/specterops/bloodhound/graphschema/azure"
 	"github.com/specterops/bloodhound/graphschema/common"
 	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/model/appcfg"
+	"github.com/specterops/bloodhound/src/services/ingest"
 )
 
-func (s *Daemon) ReadWrapper(batch graph.Batch, reader io.Reader) error {
-	var wrapper DataWrapper
+// sessionValidity bounds the validity of the sessions ingested from a single file
+type sessionValidity struct {
+	observedAt time.Time
+	validUntil time.Time
+}
 
-	if err := json.NewDecoder(reader).Decode(&wrapper); err != nil {
-		return err
+func newSessionValidity(observedAt time.Time, window appcfg.SessionValidityWindow) sessionValidity {
+	return sessionValidity{
+		observedAt: observedAt,
+		validUntil: observedAt.Add(window.Duration),
+	}
+}
+
+// bound sets the observed_at and valid_until properties of the session relationships in the given relationships
+func (s sessionValidity) bound(relationships []ein.IngestibleRelationship) {
+	for _, relationship := range relationships {
+		if relationship.RelType == ad.HasSession {
+			relationship.RelProps[common.ObservedAt.String()] = s.observedAt
+			relationship.RelProps[common.ValidUntil.String()] = s.validUntil
+		}
 	}
+}
+
+// ReadWrapper validates the meta tag of an ingest file and then streams the elements of its data tag into the batch.
+// Element validation results are recorded on the given validator.
+func (s *Daemon) ReadWrapper(batch graph.Batch, source IngestSource, validator *ingest.FileValidator) error {
+	if meta, err := readMetaTag(source); err != nil {
+		return err
+	} else if err := validator.SetMeta(string(meta.Type), meta.Version, meta.kindName()); err != nil {
+		return err
+	} else if reader, err := source(); err != nil {
+		return err
+	} else {
+		defer reader.Close()
+
+		decoder := json.NewDecoder(reader)
+		s.collectionScopes.SetMeta(meta)
+
+		if err := SeekToDataTag(decoder); err != nil {
+			return err
+		}
 
-	return s.IngestWrapper(batch, wrapper)
+		return s.IngestWrapper(batch, meta, decoder, validator)
+	}
 }
 
 func (s *Daemon) IngestBasicData(batch graph.Batch, converted ConvertedData) {
+	s.collectionScopes.CollectNodes(converted.NodeProps)
+	s.sessionValidity.bound(converted.RelProps)
+
 	IngestNodes(batch, ad.Entity, converted.NodeProps)
 	IngestRelationships(batch, ad.Entity, converted.RelProps)
 }
 
 func (s *Daemon) IngestGroupData(batch graph.Batch, converted ConvertedGroupData) {
+	s.collectionScopes.CollectNodes(converted.NodeProps)
+
 	IngestNodes(batch, ad.Entity, converted.NodeProps)
 	IngestRelationships(batch, ad.Entity, converted.RelProps)
 	IngestDNRelationships(batch, converted.DistinguishedNameProps)
 }
 
 func (s *Daemon) IngestAzureData(batch graph.Batch, converted ConvertedAzureData) {
+	s.collectionScopes.CollectNodes(converted.NodeProps)
+
 	IngestNodes(batch, azure.Entity, converted.NodeProps)
 	IngestNodes(batch, ad.Entity, converted.OnPremNodes)
 	IngestRelationships(batch, azure.Entity, converted.RelProps)
 }
 
-func (s *Daemon) IngestWrapper(batch graph.Batch, wrapper DataWrapper) error {
-	switch wrapper.Metadata.Type {
+func (s *Daemon) IngestGenericData(batch graph.Batch, converted ConvertedGenericData) {
+	s.customKinds.Collect(converted)
+
+	IngestGenericNodes(batch, converted.NodeProps)
+	IngestGenericRelationships(batch, converted.RelProps)
+}
+
+func (s *Daemon) IngestWrapper(batch graph.Batch, meta Metadata, decoder *json.Decoder, validator ElementValidator) error {
+	switch meta.Type {
 	case DataTypeComputer:
 		// We should not be getting anything with Version < 5 at this point, and we don't want to ingest it if we do as post-processing will blow it away anyways
-		if wrapper.Metadata.Version >= 5 {
-			var computerData []ein.Computer
-
-			if err := json.Unmarshal(wrapper.Payload, &computerData); err != nil {
-				return err
-			} else {
-				converted := convertComputerData(computerData)
-				s.IngestBasicData(batch, converted)
-			}
+		if meta.Version >= 5 {
+			return decodeBatches(decoder, validator, func(computerData []ein.Computer) {
+				s.IngestBasicData(batch, convertComputerData(computerData))
+			})
 		}
+
 	case DataTypeUser:
-		var userData []ein.User
-		if err := json.Unmarshal(wrapper.Payload, &userData); err != nil {
-			return err
-		} else {
-			converted := convertUserData(userData)
-			s.IngestBasicData(batch, converted)
-		}
+		return decodeBatches(decoder, validator, func(userData []ein.User) {
+			s.IngestBasicData(batch, convertUserData(userData))
+		})
 
 	case DataTypeGroup:
-		var groupData []ein.Group
-		if err := json.Unmarshal(wrapper.Payload, &groupData); err != nil {
-			return err
-		} else {
-			converted := convertGroupData(groupData)
-			s.IngestGroupData(batch, converted)
-		}
+		return decodeBatches(decoder, validator, func(groupData []ein.Group) {
+			s.IngestGroupData(batch, convertGroupData(groupData))
+		})
 
 	case DataTypeDomain:
-		var domainData []ein.Domain
-		if err := json.Unmarshal(wrapper.Payload, &domainData); err != nil {
-			return err
-		} else {
-			converted := convertDomainData(domainData)
-			s.IngestBasicData(batch, converted)
-		}
+		return decodeBatches(decoder, validator, func(domainData []ein.Domain) {
+			s.IngestBasicData(batch, convertDomainData(domainData))
+		})
 
 	case DataTypeGPO:
-		var gpoData []ein.GPO
-		if err := json.Unmarshal(wrapper.Payload, &gpoData); err != nil {
-			return err
-		} else {
-			converted := convertGPOData(gpoData)
-			s.IngestBasicData(batch, converted)
-		}
+		return decodeBatches(decoder, validator, func(gpoData []ein.GPO) {
+			s.IngestBasicData(batch, convertGPOData(gpoData))
+		})
 
 	case DataTypeOU:
-		var ouData []ein.OU
-		if err := json.Unmarshal(wrapper.Payload, &ouData); err != nil {
-			return err
-		} else {
-			converted := convertOUData(ouData)
-			s.IngestBasicData(batch, converted)
-		}
+		return decodeBatches(decoder, validator, func(ouData []ein.OU) {
+			s.IngestBasicData(batch, convertOUData(ouData))
+		})
 
 	case DataTypeSession:
-		var sessionData []ein.Session
-		if err := json.Unmarshal(wrapper.Payload, &sessionData); err != nil {
-			return err
-		} else {
-			IngestSessions(batch, convertSessionData(sessionData).SessionProps)
-		}
+		return decodeBatches(decoder, validator, func(sessionData []ein.Session) {
+			s.IngestSessions(batch, convertSessionData(sessionData).SessionProps)
+		})
 
 	case DataTypeContainer:
-		var containerData []ein.Container
-		if err := json.Unmarshal(wrapper.Payload, &containerData); err != nil {
-			return err
-		} else {
-			converted := convertContainerData(containerData)
-			s.IngestBasicData(batch, converted)
-		}
+		return decodeBatches(decoder, validator, func(containerData []ein.Container) {
+			s.IngestBasicData(batch, convertContainerData(containerData))
+		})
+
+	case DataTypeAIACA:
+		return decodeBatches(decoder, validator, func(aiaData []ein.AIACA) {
+			s.IngestBasicData(batch, convertAIACAData(aiaData))
+		})
+
+	case DataTypeRootCA:
+		return decodeBatches(decoder, validator, func(rootCAData []ein.RootCA) {
+			s.IngestBasicData(batch, convertRootCAData(rootCAData))
+		})
+
+	case DataTypeEnterpriseCA:
+		return decodeBatches(decoder, validator, func(enterpriseCAData []ein.EnterpriseCA) {
+			s.IngestBasicData(batch, convertEnterpriseCAData(enterpriseCAData))
+		})
+
+	case DataTypeNTAuthStore:
+		return decodeBatches(decoder, validator, func(ntAuthStoreData []ein.NTAuthStore) {
+			s.IngestBasicData(batch, convertNTAuthStoreData(ntAuthStoreData))
+		})
+
+	case DataTypeCertTemplate:
+		return decodeBatches(decoder, validator, func(certTemplateData []ein.CertTemplate) {
+			s.IngestBasicData(batch, convertCertTemplateData(certTemplateData))
+		})
 
 	case DataTypeAzure:
-		var azureData []json.RawMessage
-		if err := json.Unmarshal(wrapper.Payload, &azureData); err != nil {
-			return err
-		} else {
-			converted := convertAzureData(azureData)
-			s.IngestAzureData(batch, converted)
-		}
+		return decodeBatches(decoder, validator, func(azureData []json.RawMessage) {
+			s.IngestAzureData(batch, convertAzureData(azureData))
+		})
+
+	case DataTypeOpenGraph:
+		return decodeBatches(decoder, validator, func(genericData []ein.GenericObject) {
+			s.IngestGenericData(batch, convertGenericData(genericData))
+		})
 	}
 
 	return nil
@@ -199,6 +245,13 @@ func IngestNodes(batch graph.Batch, identityKind graph.Kind, nodes []ein.Ingesti
 
 func IngestRelationship(batch graph.Batch, nowUTC time.Time, nodeIDKind graph.Kind, nextRel ein.IngestibleRelationship) error {
 	nextRel.RelProps[common.LastSeen.String()] = nowUTC
+
+	// Time-bounded relationships record when they were observed unless their validity was already bounded on ingest
+	if _, isTimeBounded := nextRel.RelProps[common.ValidUntil.String()]; isTimeBounded {
+		if _, isObserved := nextRel.RelProps[common.ObservedAt.String()]; !isObserved {
+			nextRel.RelProps[common.ObservedAt.String()] = nowUTC
+		}
+	}
 	nextRel.Source = strings.ToUpper(nextRel.Source)
 	nextRel.Target = strings.ToUpper(nextRel.Target)
 
@@ -233,6 +286,56 @@ func IngestRelationships(batch graph.Batch, nodeIDKind graph.Kind, relationships
 	}
 }
 
+// IngestGenericNode merges a node of generic ingest on its declared identity kind and properties. Unlike IngestNode no
+// property values are normalized since identities of other systems may be case-sensitive.
+func IngestGenericNode(batch graph.Batch, nowUTC time.Time, nextNode ein.IngestibleGenericNode) error {
+	nextNode.PropertyMap[common.LastSeen.String()] = nowUTC
+
+	return batch.UpdateNodeBy(graph.NodeUpdate{
+		Node:               graph.PrepareNode(graph.AsProperties(nextNode.PropertyMap), nextNode.Kinds...),
+		IdentityKind:       nextNode.IdentityKind,
+		IdentityProperties: nextNode.IdentityProperties,
+	})
+}
+
+func IngestGenericNodes(batch graph.Batch, nodes []ein.IngestibleGenericNode) {
+	nowUTC := time.Now().UTC()
+
+	for _, next := range nodes {
+		if err := IngestGenericNode(batch, nowUTC, next); err != nil {
+			log.Errorf("Error ingesting generic node: %v", err)
+		}
+	}
+}
+
+// IngestGenericRelationship merges a relationship of generic ingest between the nodes matched by the declared identity
+// kinds and properties of its endpoints
+func IngestGenericRelationship(batch graph.Batch, nowUTC time.Time, nextRel ein.IngestibleGenericRelationship) error {
+	nextRel.PropertyMap[common.LastSeen.String()] = nowUTC
+
+	return batch.UpdateRelationshipBy(graph.RelationshipUpdate{
+		Relationship: graph.PrepareRelationship(graph.AsProperties(nextRel.PropertyMap), nextRel.Kind),
+
+		Start:                   graph.PrepareNode(graph.AsProperties(nextRel.StartIdentity), nextRel.StartKind),
+		StartIdentityKind:       nextRel.StartKind,
+		StartIdentityProperties: nextRel.StartIdentityProperties,
+
+		End:                   graph.PrepareNode(graph.AsProperties(nextRel.EndIdentity), nextRel.EndKind),
+		EndIdentityKind:       nextRel.EndKind,
+		EndIdentityProperties: nextRel.EndIdentityProperties,
+	})
+}
+
+func IngestGenericRelationships(batch graph.Batch, relationships []ein.IngestibleGenericRelationship) {
+	nowUTC := time.Now().UTC()
+
+	for _, next := range relationships {
+		if err := IngestGenericRelationship(batch, nowUTC, next); err != nil {
+			log.Errorf("Error ingesting generic relationship: %v", err)
+		}
+	}
+}
+
 func ingestDNRelationship(batch graph.Batch, nowUTC time.Time, nextRel ein.IngestibleRelationship) error {
 	nextRel.RelProps[common.LastSeen.String()] = nowUTC
 	nextRel.Source = strings.ToUpper(nextRel.Source)
@@ -271,14 +374,16 @@ func IngestDNRelationships(batch graph.Batch, relationships []ein.IngestibleRela
 	}
 }
 
-func ingestSession(batch graph.Batch, nowUTC time.Time, nextSession ein.IngestibleSession) error {
+func ingestSession(batch graph.Batch, nowUTC time.Time, validity sessionValidity, nextSession ein.IngestibleSession) error {
 	nextSession.Target = strings.ToUpper(nextSession.Target)
 	nextSession.Source = strings.ToUpper(nextSession.Source)
 
 	return batch.UpdateRelationshipBy(graph.RelationshipUpdate{
 		Relationship: graph.PrepareRelationship(graph.AsProperties(graph.PropertyMap{
-			common.LastSeen: nowUTC,
-			ad.LogonType:    nextSession.LogonType,
+			common.LastSeen:   nowUTC,
+			common.ObservedAt: validity.observedAt,
+			common.ValidUntil: validity.validUntil,
+			ad.LogonType:      nextSession.LogonType,
 		}), ad.HasSession),
 
 		Start: graph.PrepareNode(graph.AsProperties(graph.PropertyMap{
@@ -301,11 +406,11 @@ func ingestSession(batch graph.Batch, nowUTC time.Time, nextSession ein.Ingestib
 	})
 }
 
-func IngestSessions(batch graph.Batch, sessions []ein.IngestibleSession) {
+func (s *Daemon) IngestSessions(batch graph.Batch, sessions []ein.IngestibleSession) {
 	nowUTC := time.Now().UTC()
 
 	for _, next := range sessions {
-		if err := ingestSession(batch, nowUTC, next); err != nil {
+		if err := ingestSession(batch, nowUTC, s.sessionValidity, next); err != nil {
 			log.Errorf("Error ingesting sessions: %v", err)
 		}
 	}
@@ -0,0 +1,50 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/ein"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/src/model/appcfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionValidity_Bound(t *testing.T) {
+	var (
+		observedAt    = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		validity      = newSessionValidity(observedAt, appcfg.SessionValidityWindow{Duration: 72 * time.Hour})
+		relationships = []ein.IngestibleRelationship{{
+			RelType:  ad.HasSession,
+			RelProps: map[string]any{},
+		}, {
+			RelType:  ad.MemberOf,
+			RelProps: map[string]any{},
+		}}
+	)
+
+	validity.bound(relationships)
+
+	require.Equal(t, observedAt, relationships[0].RelProps[common.ObservedAt.String()])
+	require.Equal(t, observedAt.Add(72*time.Hour), relationships[0].RelProps[common.ValidUntil.String()])
+
+	// Only sessions are time-bounded
+	require.Empty(t, relationships[1].RelProps)
+}
@@ -17,12 +17,21 @@
 package datapipe
 
 import (
+	"archive/zip"
+	"fmt"
+	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/specterops/bloodhound/dawgs/graph"
 	"github.com/specterops/bloodhound/log"
 	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/model/appcfg"
 	"github.com/specterops/bloodhound/src/services/fileupload"
+	"github.com/specterops/bloodhound/src/services/ingest"
 )
 
 func (s *Daemon) numAvailableCompletedFileUploadJobs() int {
@@ -58,7 +67,19 @@ func (s *Daemon) processCompletedFileUploadJobs() {
 			s.processIngestTasks(ingestTasks)
 		}
 
-		if err := fileupload.UpdateFileUploadJobStatus(s.db, id, model.JobStatusComplete, "Complete"); err != nil {
+		if err := s.reconcileCollectionScopes(id); err != nil {
+			log.Errorf("Failed retiring stale objects for job %d: %v", id, err)
+		}
+
+		statusMessage := "Complete"
+
+		if reports, err := s.db.GetIngestFileReportsForJob(id); err != nil {
+			log.Errorf("Failed fetching ingest reports for job %d: %v", id, err)
+		} else if numRejected := reports.NumRejected(); numRejected > 0 {
+			statusMessage = fmt.Sprintf("Complete: %d of %d files rejected", numRejected, len(reports))
+		}
+
+		if err := fileupload.UpdateFileUploadJobStatus(s.db, id, model.JobStatusComplete, statusMessage); err != nil {
 			log.Errorf("Error updating fileupload job %d: %v", id, err)
 		}
 	}
@@ -75,28 +96,99 @@ func (s *Daemon) getAndTransitionCompletedJobIDs() []int64 {
 	return s.fileUploadJobIDsUnderAnalysis
 }
 
-func (s *Daemon) processIngestTasks(ingestTasks model.IngestTasks) {
-	s.status.Update(model.DatapipeStatusIngesting, false)
-	defer s.status.Update(model.DatapipeStatusIdle, false)
+// ingestFile ingests a single file in its own batch, registers any custom kinds it introduced and stores the
+// validation report for it. The domains and tenants of files ingested without errors are recorded so that objects no
+// longer present in them can be retired once the file upload job completes.
+func (s *Daemon) ingestFile(ingestTask model.IngestTask, fileName string, source IngestSource) {
+	var (
+		fileUploadJobID = ingestTask.TaskID.ValueOrZero()
+		validator       = ingest.NewFileValidator(fileUploadJobID, fileName)
+		observedAt      = time.Now().UTC()
+	)
+
+	s.customKinds.Reset()
+	s.collectionScopes.Reset()
+	s.sessionValidity = newSessionValidity(observedAt, appcfg.GetSessionValidity(s.db))
+
+	if err := s.graphdb.BatchOperation(s.ctx, func(batch graph.Batch) error {
+		return s.ReadWrapper(batch, source, validator)
+	}); err != nil {
+		log.Errorf("Error processing file %s for ingest task %d: %v", fileName, ingestTask.ID, err)
+		validator.Reject(err)
+	} else {
+		if err := s.registerCustomKinds(s.customKinds.CustomKinds()); err != nil {
+			log.Errorf("Error registering custom kinds from file %s: %v", fileName, err)
+		}
 
-	for _, ingestTask := range ingestTasks {
-		jsonFile, err := os.Open(ingestTask.FileName)
+		// Files with invalid elements are not a complete collection of their scope
+		if !validator.HasErrors() {
+			if err := s.db.CreateCollectionScopes(s.collectionScopes.Scopes(fileUploadJobID, observedAt)); err != nil {
+				log.Errorf("Error recording collection scopes of file %s: %v", fileName, err)
+			}
+		}
+	}
+
+	s.saveIngestFileReport(validator)
+}
+
+func (s *Daemon) saveIngestFileReport(validator *ingest.FileValidator) {
+	report := validator.Report()
+
+	if _, err := s.db.CreateIngestFileReport(report); err != nil {
+		log.Errorf("Error saving ingest report for file %s: %v", report.FileName, err)
+	}
+}
+
+func (s *Daemon) processIngestFile(ingestTask model.IngestTask) error {
+	fileName := ingestTask.OriginalFileName
+	if fileName == "" {
+		fileName = filepath.Base(ingestTask.FileName)
+	}
+
+	switch ingestTask.FileType {
+	case model.FileTypeZip:
+		archive, err := zip.OpenReader(ingestTask.FileName)
 		if err != nil {
-			log.Errorf("Error reading file for ingest task %v: %v", ingestTask.ID, err)
+			err = fmt.Errorf("error opening archive: %w", err)
+
+			validator := ingest.NewFileValidator(ingestTask.TaskID.ValueOrZero(), fileName)
+			validator.Reject(err)
+			s.saveIngestFileReport(validator)
+
+			return err
 		}
 
-		if err = s.graphdb.BatchOperation(s.ctx, func(batch graph.Batch) error {
-			if err := s.ReadWrapper(batch, jsonFile); err != nil {
-				return err
-			} else {
-				return nil
+		defer archive.Close()
+
+		// Each archive entry is ingested in its own batch so that a malformed entry does not stop the others from being
+		// ingested
+		for _, entry := range archive.File {
+			if entry.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(entry.Name), ".json") {
+				continue
 			}
-		}); err != nil {
+
+			s.ingestFile(ingestTask, path.Join(fileName, entry.Name), entry.Open)
+		}
+
+	default:
+		s.ingestFile(ingestTask, fileName, func() (io.ReadCloser, error) {
+			return os.Open(ingestTask.FileName)
+		})
+	}
+
+	return nil
+}
+
+func (s *Daemon) processIngestTasks(ingestTasks model.IngestTasks) {
+	s.status.Update(model.DatapipeStatusIngesting, false)
+	defer s.status.Update(model.DatapipeStatusIdle, false)
+
+	for _, ingestTask := range ingestTasks {
+		if err := s.processIngestFile(ingestTask); err != nil {
 			log.Errorf("Error processing ingest task %v: %v", ingestTask.ID, err)
 		}
 
 		s.clearTask(ingestTask)
-		jsonFile.Close()
 	}
 }
 
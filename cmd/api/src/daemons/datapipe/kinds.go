@@ -0,0 +1,126 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/src/migrations"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// customKindCollector collects the node and relationship kinds seen during generic ingest of a file so that kinds
+// that are not part of the built-in schema can be registered once the file has been ingested
+type customKindCollector struct {
+	nodeKinds         map[string]struct{}
+	relationshipKinds map[string]struct{}
+}
+
+func newCustomKindCollector() *customKindCollector {
+	collector := &customKindCollector{}
+	collector.Reset()
+
+	return collector
+}
+
+func (s *customKindCollector) Reset() {
+	s.nodeKinds = make(map[string]struct{})
+	s.relationshipKinds = make(map[string]struct{})
+}
+
+func (s *customKindCollector) Collect(converted ConvertedGenericData) {
+	for _, node := range converted.NodeProps {
+		for _, kind := range node.Kinds {
+			s.nodeKinds[kind.String()] = struct{}{}
+		}
+	}
+
+	for _, relationship := range converted.RelProps {
+		s.relationshipKinds[relationship.Kind.String()] = struct{}{}
+		s.nodeKinds[relationship.StartKind.String()] = struct{}{}
+		s.nodeKinds[relationship.EndKind.String()] = struct{}{}
+	}
+}
+
+func customKindsOf(kindNames map[string]struct{}, relationship bool) model.CustomKinds {
+	var customKinds model.CustomKinds
+
+	for kindName := range kindNames {
+		// Built-in kinds are already part of the schema
+		if _, err := analysis.ParseKind(kindName); err != nil {
+			customKinds = append(customKinds, model.CustomKind{
+				Name:         kindName,
+				Relationship: relationship,
+			})
+		}
+	}
+
+	return customKinds
+}
+
+// CustomKinds returns the collected kinds that are not built-in, sorted by name with node kinds first
+func (s *customKindCollector) CustomKinds() model.CustomKinds {
+	var (
+		nodeKinds         = customKindsOf(s.nodeKinds, false)
+		relationshipKinds = customKindsOf(s.relationshipKinds, true)
+	)
+
+	sort.Slice(nodeKinds, func(i, j int) bool {
+		return nodeKinds[i].Name < nodeKinds[j].Name
+	})
+
+	sort.Slice(relationshipKinds, func(i, j int) bool {
+		return relationshipKinds[i].Name < relationshipKinds[j].Name
+	})
+
+	return append(nodeKinds, relationshipKinds...)
+}
+
+// registerCustomKinds persists custom kinds that have not been registered before and asserts the graph schema again
+// when new node kinds were found
+func (s *Daemon) registerCustomKinds(customKinds model.CustomKinds) error {
+	if len(customKinds) == 0 {
+		return nil
+	}
+
+	registered, err := s.db.GetCustomKinds()
+	if err != nil {
+		return fmt.Errorf("failed fetching custom kinds: %w", err)
+	}
+
+	var unregistered model.CustomKinds
+
+	for _, customKind := range customKinds {
+		if !registered.Has(customKind.Name, customKind.Relationship) {
+			unregistered = append(unregistered, customKind)
+		}
+	}
+
+	if len(unregistered) == 0 {
+		return nil
+	} else if err := s.db.CreateCustomKinds(unregistered); err != nil {
+		return fmt.Errorf("failed registering custom kinds: %w", err)
+	} else if newNodeKinds := unregistered.NodeKinds(); len(newNodeKinds) == 0 {
+		return nil
+	} else if err := s.graphdb.AssertSchema(s.ctx, migrations.CurrentSchema(append(registered.NodeKinds(), newNodeKinds...)...)); err != nil {
+		return fmt.Errorf("failed asserting schema for custom kinds: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/ein"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomKindCollector(t *testing.T) {
+	collector := newCustomKindCollector()
+	collector.Collect(ConvertedGenericData{
+		NodeProps: []ein.IngestibleGenericNode{{
+			Kinds: graph.Kinds{graph.StringKind("GHUser"), graph.StringKind("GHAdmin")},
+		}},
+		RelProps: []ein.IngestibleGenericRelationship{{
+			Kind:      graph.StringKind("GHHasAccount"),
+			StartKind: ad.User,
+			EndKind:   graph.StringKind("GHUser"),
+		}, {
+			Kind:      ad.MemberOf,
+			StartKind: graph.StringKind("GHUser"),
+			EndKind:   graph.StringKind("GHTeam"),
+		}},
+	})
+
+	require.Equal(t, model.CustomKinds{
+		{Name: "GHAdmin"},
+		{Name: "GHTeam"},
+		{Name: "GHUser"},
+		{Name: "GHHasAccount", Relationship: true},
+	}, collector.CustomKinds())
+
+	require.Equal(t, graph.Kinds{graph.StringKind("GHAdmin"), graph.StringKind("GHTeam"), graph.StringKind("GHUser")}, collector.CustomKinds().NodeKinds())
+
+	collector.Reset()
+	require.Empty(t, collector.CustomKinds())
+}
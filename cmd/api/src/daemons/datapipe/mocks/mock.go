@@ -87,3 +87,17 @@ func (mr *MockTaskerMockRecorder) RequestAnalysis() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestAnalysis", reflect.TypeOf((*MockTasker)(nil).RequestAnalysis))
 }
+
+// RequestPostProcessingStages mocks base method.
+func (m *MockTasker) RequestPostProcessingStages(arg0 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPostProcessingStages", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPostProcessingStages indicates an expected call of RequestPostProcessingStages.
+func (mr *MockTaskerMockRecorder) RequestPostProcessingStages(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPostProcessingStages", reflect.TypeOf((*MockTasker)(nil).RequestPostProcessingStages), arg0)
+}
@@ -23,13 +23,9 @@ import (
 	"github.com/specterops/bloodhound/dawgs/graph"
 	"github.com/specterops/bloodhound/ein"
 	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
 )
 
-type DataWrapper struct {
-	Metadata Metadata        `json:"meta"`
-	Payload  json.RawMessage `json:"data"`
-}
-
 type Metadata struct {
 	Type    DataType         `json:"type"`
 	Methods CollectionMethod `json:"methods"`
@@ -58,25 +54,71 @@ func (s Metadata) MatchKind() (graph.Kind, bool) {
 
 	case DataTypeContainer:
 		return ad.Container, true
+
+	case DataTypeAIACA:
+		return ad.AIACA, true
+
+	case DataTypeRootCA:
+		return ad.RootCA, true
+
+	case DataTypeEnterpriseCA:
+		return ad.EnterpriseCA, true
+
+	case DataTypeNTAuthStore:
+		return ad.NTAuthStore, true
+
+	case DataTypeCertTemplate:
+		return ad.CertTemplate, true
 	}
 
 	return nil, false
 }
 
+// kindName returns the name of the kind that objects in the file are counted under in ingest reports. Files without a
+// single matching kind, such as Azure output, return an empty string.
+func (s Metadata) kindName() string {
+	if kind, matched := s.MatchKind(); matched {
+		return kind.String()
+	}
+
+	return ""
+}
+
+// Scope returns the kind and property of the objects whose lifetimes are tracked for files of this type. AD objects are
+// scoped to their domain and Azure objects to their tenant. Files that do not describe a complete collection of a
+// scope, such as sessions, are not scoped.
+func (s Metadata) Scope() (graph.Kind, string, bool) {
+	if s.Type == DataTypeAzure {
+		return azure.Entity, azure.TenantID.String(), true
+	}
+
+	if kind, matched := s.MatchKind(); matched {
+		return kind, ad.DomainSID.String(), true
+	}
+
+	return nil, "", false
+}
+
 type DataType string
 
 const (
-	DataTypeSession     DataType = "sessions"
-	DataTypeUser        DataType = "users"
-	DataTypeGroup       DataType = "groups"
-	DataTypeComputer    DataType = "computers"
-	DataTypeGPO         DataType = "gpos"
-	DataTypeOU          DataType = "ous"
-	DataTypeDomain      DataType = "domains"
-	DataTypeRemoved     DataType = "deleted"
-	DataTypeContainer   DataType = "containers"
-	DataTypeLocalGroups DataType = "localgroups"
-	DataTypeAzure       DataType = "azure"
+	DataTypeSession      DataType = "sessions"
+	DataTypeUser         DataType = "users"
+	DataTypeGroup        DataType = "groups"
+	DataTypeComputer     DataType = "computers"
+	DataTypeGPO          DataType = "gpos"
+	DataTypeOU           DataType = "ous"
+	DataTypeDomain       DataType = "domains"
+	DataTypeRemoved      DataType = "deleted"
+	DataTypeContainer    DataType = "containers"
+	DataTypeLocalGroups  DataType = "localgroups"
+	DataTypeAIACA        DataType = "aiacas"
+	DataTypeRootCA       DataType = "rootcas"
+	DataTypeEnterpriseCA DataType = "enterprisecas"
+	DataTypeNTAuthStore  DataType = "ntauthstores"
+	DataTypeCertTemplate DataType = "certtemplates"
+	DataTypeAzure        DataType = "azure"
+	DataTypeOpenGraph    DataType = "opengraph"
 )
 
 func AllIngestDataTypes() []DataType {
@@ -91,7 +133,13 @@ func AllIngestDataTypes() []DataType {
 		DataTypeRemoved,
 		DataTypeContainer,
 		DataTypeLocalGroups,
+		DataTypeAIACA,
+		DataTypeRootCA,
+		DataTypeEnterpriseCA,
+		DataTypeNTAuthStore,
+		DataTypeCertTemplate,
 		DataTypeAzure,
+		DataTypeOpenGraph,
 	}
 }
 
@@ -172,6 +220,11 @@ type ConvertedSessionData struct {
 	SessionProps []ein.IngestibleSession
 }
 
+type ConvertedGenericData struct {
+	NodeProps []ein.IngestibleGenericNode
+	RelProps  []ein.IngestibleGenericRelationship
+}
+
 type AzureBase struct {
 	Kind enums.Kind      `json:"kind"`
 	Data json.RawMessage `json:"data"`
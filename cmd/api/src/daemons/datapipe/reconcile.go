@@ -0,0 +1,417 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	azureAnalysis "github.com/specterops/bloodhound/analysis/azure"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/ein"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/model/appcfg"
+)
+
+const (
+	// LocalGroupCollectionMethods are the collection methods that together enumerate every local group of a computer.
+	// Local group membership is only reconciled when all of them were collected.
+	LocalGroupCollectionMethods = CollectionMethodLocalAdmin | CollectionMethodRDP | CollectionMethodDCOM | CollectionMethodPSRemote
+
+	// DomainEnumerationMethods are the collection methods that enumerate the objects of a domain over LDAP. Files
+	// collected without any of them, such as session loops, only contain the computers that were reached.
+	DomainEnumerationMethods = CollectionMethodGroup | CollectionMethodTrusts | CollectionMethodACL | CollectionMethodContainer | CollectionMethodObjectProps
+)
+
+// CollectedRelationships holds the relationship kinds that a collection enumerates completely for each object it
+// collects. Outbound kinds start at the collected object and inbound kinds end at it.
+type CollectedRelationships struct {
+	Outbound graph.Kinds
+	Inbound  graph.Kinds
+}
+
+// CollectedRelationshipKinds returns the relationships that files of this type enumerate completely. Post-processed
+// relationships are never included since analysis recreates them.
+func (s Metadata) CollectedRelationshipKinds() CollectedRelationships {
+	var collected CollectedRelationships
+
+	if s.Type == DataTypeAzure {
+		// Azure collections are complete for a tenant
+		kinds := graph.Kinds(azure.Relationships()).Exclude(azureAnalysis.AzurePostProcessedRelationships())
+
+		return CollectedRelationships{
+			Outbound: kinds,
+			Inbound:  kinds,
+		}
+	}
+
+	if s.Methods.Has(CollectionMethodGroup) {
+		collected.Inbound = append(collected.Inbound, ad.MemberOf)
+	}
+
+	if s.Methods.And(LocalGroupCollectionMethods) == LocalGroupCollectionMethods {
+		collected.Inbound = append(collected.Inbound, ad.LocalToComputer)
+	}
+
+	if s.Methods.Has(CollectionMethodSession | CollectionMethodLoggedOn | CollectionMethodSessionLoop | CollectionMethodLoggedOnLoop) {
+		collected.Outbound = append(collected.Outbound, ad.HasSession)
+	}
+
+	if s.Methods.Has(CollectionMethodTrusts) {
+		collected.Outbound = append(collected.Outbound, ad.TrustedBy)
+		collected.Inbound = append(collected.Inbound, ad.TrustedBy)
+	}
+
+	if s.Methods.Has(CollectionMethodACL) {
+		collected.Inbound = append(collected.Inbound, ad.ACLRelationships()...)
+	}
+
+	if s.Methods.Has(CollectionMethodContainer) {
+		collected.Outbound = append(collected.Outbound, ad.Contains)
+		collected.Inbound = append(collected.Inbound, ad.GPLink)
+	}
+
+	if s.Methods.Has(CollectionMethodObjectProps) {
+		collected.Outbound = append(collected.Outbound, ad.AllowedToDelegate, ad.HasSIDHistory, ad.DumpSMSAPassword)
+		collected.Inbound = append(collected.Inbound, ad.AllowedToAct)
+	}
+
+	return collected
+}
+
+// collectionScopeCollector collects the domains and tenants covered by an ingest file
+type collectionScopeCollector struct {
+	meta     Metadata
+	scopeIDs map[string]struct{}
+}
+
+func newCollectionScopeCollector() *collectionScopeCollector {
+	collector := &collectionScopeCollector{}
+	collector.Reset()
+
+	return collector
+}
+
+func (s *collectionScopeCollector) Reset() {
+	s.meta = Metadata{}
+	s.scopeIDs = make(map[string]struct{})
+}
+
+func (s *collectionScopeCollector) SetMeta(meta Metadata) {
+	s.meta = meta
+}
+
+func (s *collectionScopeCollector) CollectNodes(nodes []ein.IngestibleNode) {
+	if _, scopeProperty, isScoped := s.meta.Scope(); isScoped {
+		for _, node := range nodes {
+			if scopeID, typeOK := node.PropertyMap[scopeProperty].(string); typeOK && scopeID != "" {
+				s.scopeIDs[scopeID] = struct{}{}
+			}
+		}
+	}
+}
+
+// Scopes returns the collection scopes of the file for the given file upload job
+func (s *collectionScopeCollector) Scopes(fileUploadJobID int64, observedAt time.Time) model.CollectionScopes {
+	var scopes model.CollectionScopes
+
+	for scopeID := range s.scopeIDs {
+		scopes = append(scopes, model.CollectionScope{
+			FileUploadJobID:   fileUploadJobID,
+			ScopeID:           scopeID,
+			DataType:          string(s.meta.Type),
+			CollectionMethods: int64(s.meta.Methods),
+			ObservedAt:        observedAt,
+		})
+	}
+
+	return scopes
+}
+
+// scopeReconciliation describes a domain or tenant to reconcile along with the union of the collection methods of the
+// files that covered it. ObservedAt is the time the first of these files was ingested; every object they contained has
+// been seen since.
+type scopeReconciliation struct {
+	Meta       Metadata
+	ScopeID    string
+	ObservedAt time.Time
+}
+
+// mergeCollectionScopes merges the scopes recorded for each file of a job so that every domain or tenant is reconciled
+// once per data type
+func mergeCollectionScopes(scopes model.CollectionScopes) []scopeReconciliation {
+	var (
+		merged  []scopeReconciliation
+		indexOf = map[string]int{}
+	)
+
+	for _, scope := range scopes {
+		key := scope.DataType + "/" + scope.ScopeID
+
+		if idx, found := indexOf[key]; found {
+			merged[idx].Meta.Methods = merged[idx].Meta.Methods.Or(CollectionMethod(scope.CollectionMethods))
+
+			if scope.ObservedAt.Before(merged[idx].ObservedAt) {
+				merged[idx].ObservedAt = scope.ObservedAt
+			}
+		} else {
+			indexOf[key] = len(merged)
+			merged = append(merged, scopeReconciliation{
+				Meta: Metadata{
+					Type:    DataType(scope.DataType),
+					Methods: CollectionMethod(scope.CollectionMethods),
+				},
+				ScopeID:    scope.ScopeID,
+				ObservedAt: scope.ObservedAt,
+			})
+		}
+	}
+
+	return merged
+}
+
+// isCompleteCollection returns true if the merged scope is known to cover its whole domain or tenant. AzureHound always
+// collects a whole tenant. A domain is only complete when the same job collected the domain object itself, which
+// collections limited to an OU search base or a computer file do not contain, and when the objects of the scope were
+// enumerated over LDAP.
+func isCompleteCollection(scope scopeReconciliation, collectedDomains map[string]struct{}) bool {
+	if scope.Meta.Type == DataTypeAzure {
+		return true
+	} else if _, collected := collectedDomains[scope.ScopeID]; !collected {
+		return false
+	} else {
+		return scope.Meta.Methods.And(DomainEnumerationMethods) != 0
+	}
+}
+
+func staleCriteria(lastSeen graph.Criteria, observedAt time.Time) graph.Criteria {
+	return query.Before(lastSeen, observedAt)
+}
+
+func reobservedCriteria(lastSeen, stale graph.Criteria, observedAt time.Time) graph.Criteria {
+	return query.And(
+		query.Not(query.Before(lastSeen, observedAt)),
+		query.IsNotNull(stale),
+	)
+}
+
+func inScope(reference graph.Criteria, scopeProperty graph.Criteria, kind graph.Kind, scopeID string) graph.Criteria {
+	return query.And(
+		query.Kind(reference, kind),
+		query.Equals(scopeProperty, scopeID),
+	)
+}
+
+// StaleObjectStats counts the objects of a collection scope that were retired or observed again
+type StaleObjectStats struct {
+	NodesRetired          int64
+	RelationshipsRetired  int64
+	NodesRestored         int64
+	RelationshipsRestored int64
+}
+
+func retireNodes(tx graph.Transaction, criteria graph.Criteria, mode appcfg.StaleObjectRetirementMode) (int64, error) {
+	if numStale, err := tx.Nodes().Filter(criteria).Count(); err != nil || numStale == 0 {
+		return 0, err
+	} else if mode == appcfg.StaleObjectRetirementDelete {
+		return numStale, tx.Nodes().Filter(criteria).Delete()
+	} else {
+		return numStale, tx.Nodes().Filter(criteria).Update(graph.NewProperties().Set(common.Stale.String(), true))
+	}
+}
+
+func retireRelationships(tx graph.Transaction, criteria graph.Criteria, mode appcfg.StaleObjectRetirementMode) (int64, error) {
+	if numStale, err := tx.Relationships().Filter(criteria).Count(); err != nil || numStale == 0 {
+		return 0, err
+	} else if mode == appcfg.StaleObjectRetirementDelete {
+		return numStale, tx.Relationships().Filter(criteria).Delete()
+	} else {
+		return numStale, tx.Relationships().Filter(criteria).Update(graph.NewProperties().Set(common.Stale.String(), true))
+	}
+}
+
+func restoreNodes(tx graph.Transaction, criteria graph.Criteria) (int64, error) {
+	if numRestored, err := tx.Nodes().Filter(criteria).Count(); err != nil || numRestored == 0 {
+		return 0, err
+	} else {
+		return numRestored, tx.Nodes().Filter(criteria).Update(graph.NewProperties().Delete(common.Stale.String()))
+	}
+}
+
+func restoreRelationships(tx graph.Transaction, criteria graph.Criteria) (int64, error) {
+	if numRestored, err := tx.Relationships().Filter(criteria).Count(); err != nil || numRestored == 0 {
+		return 0, err
+	} else {
+		return numRestored, tx.Relationships().Filter(criteria).Update(graph.NewProperties().Delete(common.Stale.String()))
+	}
+}
+
+// scopedRelationshipCriteria matches the collected relationships of the objects in the scope
+func scopedRelationshipCriteria(meta Metadata, kind graph.Kind, scopeProperty, scopeID string) graph.Criteria {
+	var (
+		collected = meta.CollectedRelationshipKinds()
+		criteria  []graph.Criteria
+	)
+
+	if len(collected.Outbound) > 0 {
+		criteria = append(criteria, query.And(
+			query.KindIn(query.Relationship(), collected.Outbound...),
+			inScope(query.Start(), query.StartProperty(scopeProperty), kind, scopeID),
+		))
+	}
+
+	if len(collected.Inbound) > 0 {
+		criteria = append(criteria, query.And(
+			query.KindIn(query.Relationship(), collected.Inbound...),
+			inScope(query.End(), query.EndProperty(scopeProperty), kind, scopeID),
+		))
+	}
+
+	if len(criteria) == 0 {
+		return nil
+	}
+
+	return query.Or(criteria...)
+}
+
+// scopedLocalGroupIDs returns the IDs of the local groups of the computers in the scope. Local groups carry no domain
+// SID of their own so their membership is scoped through the computer they are local to.
+func scopedLocalGroupIDs(tx graph.Transaction, scopeID string) ([]graph.ID, error) {
+	if localGroups, err := ops.FetchStartNodes(tx.Relationships().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Relationship(), ad.LocalToComputer),
+			inScope(query.End(), query.EndProperty(ad.DomainSID.String()), ad.Computer, scopeID),
+		)
+	})); err != nil {
+		return nil, err
+	} else {
+		return localGroups.IDs(), nil
+	}
+}
+
+// ReconcileCollectionScope retires the objects of the given kind in the scope that were last seen before observedAt
+// together with their collected relationships. In mark mode objects that were observed again lose their stale mark.
+func ReconcileCollectionScope(ctx context.Context, db graph.Database, meta Metadata, scopeID string, observedAt time.Time, mode appcfg.StaleObjectRetirementMode) (StaleObjectStats, error) {
+	var stats StaleObjectStats
+
+	kind, scopeProperty, isScoped := meta.Scope()
+	if !isScoped || mode == appcfg.StaleObjectRetirementDisabled {
+		return stats, nil
+	}
+
+	err := db.WriteTransaction(ctx, func(tx graph.Transaction) error {
+		var (
+			nodeCriteria         = inScope(query.Node(), query.NodeProperty(scopeProperty), kind, scopeID)
+			relationshipCriteria = scopedRelationshipCriteria(meta, kind, scopeProperty, scopeID)
+			nodeLastSeen         = query.NodeProperty(common.LastSeen.String())
+			relationshipLastSeen = query.RelationshipProperty(common.LastSeen.String())
+			err                  error
+		)
+
+		if meta.Type == DataTypeComputer && meta.Methods.And(LocalGroupCollectionMethods) == LocalGroupCollectionMethods {
+			if localGroupIDs, err := scopedLocalGroupIDs(tx, scopeID); err != nil {
+				return fmt.Errorf("failed fetching local groups: %w", err)
+			} else if len(localGroupIDs) > 0 {
+				localGroupMembership := query.And(
+					query.Kind(query.Relationship(), ad.MemberOfLocalGroup),
+					query.InIDs(query.EndID(), localGroupIDs...),
+				)
+
+				if relationshipCriteria == nil {
+					relationshipCriteria = localGroupMembership
+				} else {
+					relationshipCriteria = query.Or(relationshipCriteria, localGroupMembership)
+				}
+			}
+		}
+
+		// Relationships are retired first since deleting stale nodes also removes their relationships
+		if relationshipCriteria != nil {
+			if stats.RelationshipsRetired, err = retireRelationships(tx, query.And(relationshipCriteria, staleCriteria(relationshipLastSeen, observedAt)), mode); err != nil {
+				return fmt.Errorf("failed retiring relationships: %w", err)
+			}
+
+			if mode == appcfg.StaleObjectRetirementMark {
+				if stats.RelationshipsRestored, err = restoreRelationships(tx, query.And(relationshipCriteria, reobservedCriteria(relationshipLastSeen, query.RelationshipProperty(common.Stale.String()), observedAt))); err != nil {
+					return fmt.Errorf("failed restoring relationships: %w", err)
+				}
+			}
+		}
+
+		if stats.NodesRetired, err = retireNodes(tx, query.And(nodeCriteria, staleCriteria(nodeLastSeen, observedAt)), mode); err != nil {
+			return fmt.Errorf("failed retiring nodes: %w", err)
+		}
+
+		if mode == appcfg.StaleObjectRetirementMark {
+			if stats.NodesRestored, err = restoreNodes(tx, query.And(nodeCriteria, reobservedCriteria(nodeLastSeen, query.NodeProperty(common.Stale.String()), observedAt))); err != nil {
+				return fmt.Errorf("failed restoring nodes: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return stats, err
+}
+
+// reconcileJobScopes retires the objects in the scopes of a file upload job that the job did not observe again. Scopes
+// that are not known to be a complete collection of their domain or tenant are left untouched.
+func reconcileJobScopes(ctx context.Context, db graph.Database, fileUploadJobID int64, scopes model.CollectionScopes, mode appcfg.StaleObjectRetirementMode) {
+	var (
+		merged           = mergeCollectionScopes(scopes)
+		collectedDomains = map[string]struct{}{}
+	)
+
+	for _, scope := range merged {
+		if scope.Meta.Type == DataTypeDomain {
+			collectedDomains[scope.ScopeID] = struct{}{}
+		}
+	}
+
+	for _, scope := range merged {
+		if !isCompleteCollection(scope, collectedDomains) {
+			log.Infof("Skipping reconciliation of %s of scope %s for file upload job %d: not a complete collection of the scope", scope.Meta.Type, scope.ScopeID, fileUploadJobID)
+		} else if stats, err := ReconcileCollectionScope(ctx, db, scope.Meta, scope.ScopeID, scope.ObservedAt, mode); err != nil {
+			log.Errorf("Failed reconciling %s of scope %s for file upload job %d: %v", scope.Meta.Type, scope.ScopeID, fileUploadJobID, err)
+		} else {
+			log.Infof("Reconciled %s of scope %s for file upload job %d: %d nodes and %d relationships retired (%s), %d nodes and %d relationships observed again", scope.Meta.Type, scope.ScopeID, fileUploadJobID, stats.NodesRetired, stats.RelationshipsRetired, mode, stats.NodesRestored, stats.RelationshipsRestored)
+		}
+	}
+}
+
+// reconcileCollectionScopes retires the objects in the scopes collected by the given file upload job that the job did
+// not observe again
+func (s *Daemon) reconcileCollectionScopes(fileUploadJobID int64) error {
+	mode := appcfg.GetStaleObjectRetirementMode(s.db)
+	if mode == appcfg.StaleObjectRetirementDisabled {
+		return nil
+	}
+
+	if scopes, err := s.db.GetCollectionScopesForJob(fileUploadJobID); err != nil {
+		return fmt.Errorf("failed fetching collection scopes: %w", err)
+	} else {
+		reconcileJobScopes(s.ctx, s.graphdb, fileUploadJobID, scopes, mode)
+		return nil
+	}
+}
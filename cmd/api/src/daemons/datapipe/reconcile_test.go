@@ -0,0 +1,213 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/ein"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/model/appcfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionScopeCollector(t *testing.T) {
+	var (
+		collector  = newCollectionScopeCollector()
+		observedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	collector.SetMeta(Metadata{Type: DataTypeUser, Methods: CollectionMethodGroup | CollectionMethodACL})
+	collector.CollectNodes([]ein.IngestibleNode{{
+		ObjectID:    "S-1-5-21-1-1104",
+		PropertyMap: map[string]any{ad.DomainSID.String(): "S-1-5-21-1"},
+	}, {
+		ObjectID:    "S-1-5-21-1-1105",
+		PropertyMap: map[string]any{ad.DomainSID.String(): "S-1-5-21-1"},
+	}, {
+		ObjectID:    "S-1-5-21-2-1104",
+		PropertyMap: map[string]any{},
+	}})
+
+	require.Equal(t, model.CollectionScopes{{
+		FileUploadJobID:   1,
+		ScopeID:           "S-1-5-21-1",
+		DataType:          string(DataTypeUser),
+		CollectionMethods: int64(CollectionMethodGroup | CollectionMethodACL),
+		ObservedAt:        observedAt,
+	}}, collector.Scopes(1, observedAt))
+
+	collector.Reset()
+	require.Empty(t, collector.Scopes(1, observedAt))
+
+	// Sessions are not a complete collection of a domain
+	collector.SetMeta(Metadata{Type: DataTypeSession})
+	collector.CollectNodes([]ein.IngestibleNode{{
+		ObjectID:    "S-1-5-21-1-1104",
+		PropertyMap: map[string]any{ad.DomainSID.String(): "S-1-5-21-1"},
+	}})
+
+	require.Empty(t, collector.Scopes(1, observedAt))
+}
+
+func TestMergeCollectionScopes(t *testing.T) {
+	var (
+		firstObservedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		lastObservedAt  = firstObservedAt.Add(time.Hour)
+	)
+
+	merged := mergeCollectionScopes(model.CollectionScopes{{
+		ScopeID:           "S-1-5-21-1",
+		DataType:          string(DataTypeComputer),
+		CollectionMethods: int64(CollectionMethodSession),
+		ObservedAt:        lastObservedAt,
+	}, {
+		ScopeID:           "S-1-5-21-1",
+		DataType:          string(DataTypeUser),
+		CollectionMethods: int64(CollectionMethodACL),
+		ObservedAt:        lastObservedAt,
+	}, {
+		ScopeID:           "S-1-5-21-1",
+		DataType:          string(DataTypeComputer),
+		CollectionMethods: int64(CollectionMethodLocalAdmin),
+		ObservedAt:        firstObservedAt,
+	}})
+
+	require.Equal(t, []scopeReconciliation{{
+		Meta:       Metadata{Type: DataTypeComputer, Methods: CollectionMethodSession | CollectionMethodLocalAdmin},
+		ScopeID:    "S-1-5-21-1",
+		ObservedAt: firstObservedAt,
+	}, {
+		Meta:       Metadata{Type: DataTypeUser, Methods: CollectionMethodACL},
+		ScopeID:    "S-1-5-21-1",
+		ObservedAt: lastObservedAt,
+	}}, merged)
+}
+
+func TestIsCompleteCollection(t *testing.T) {
+	collectedDomains := map[string]struct{}{"S-1-5-21-1": {}}
+
+	require.True(t, isCompleteCollection(scopeReconciliation{Meta: Metadata{Type: DataTypeAzure}, ScopeID: "tenant"}, collectedDomains))
+	require.True(t, isCompleteCollection(scopeReconciliation{Meta: Metadata{Type: DataTypeUser, Methods: CollectionMethodACL}, ScopeID: "S-1-5-21-1"}, collectedDomains))
+
+	// The domain object of the scope was not collected
+	require.False(t, isCompleteCollection(scopeReconciliation{Meta: Metadata{Type: DataTypeUser, Methods: CollectionMethodACL}, ScopeID: "S-1-5-21-2"}, collectedDomains))
+
+	// Session collection does not enumerate the computers of the domain
+	require.False(t, isCompleteCollection(scopeReconciliation{Meta: Metadata{Type: DataTypeComputer, Methods: CollectionMethodSession | CollectionMethodSessionLoop}, ScopeID: "S-1-5-21-1"}, collectedDomains))
+}
+
+func TestReconcileJobScopes_PartialCollection(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		graphDB    = memory.NewDatabase()
+		domainSID  = "S-1-5-21-1"
+		lastSeen   = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		observedAt = lastSeen.Add(24 * time.Hour)
+
+		userScope = model.CollectionScope{
+			ScopeID:           domainSID,
+			DataType:          string(DataTypeUser),
+			CollectionMethods: int64(CollectionMethodGroup | CollectionMethodACL),
+			ObservedAt:        observedAt,
+		}
+
+		sessionScope = model.CollectionScope{
+			ScopeID:           domainSID,
+			DataType:          string(DataTypeComputer),
+			CollectionMethods: int64(CollectionMethodSession | CollectionMethodSessionLoop),
+			ObservedAt:        observedAt,
+		}
+
+		domainScope = model.CollectionScope{
+			ScopeID:           domainSID,
+			DataType:          string(DataTypeDomain),
+			CollectionMethods: int64(CollectionMethodGroup | CollectionMethodACL),
+			ObservedAt:        observedAt,
+		}
+	)
+
+	require.Nil(t, graphDB.WriteTransaction(ctx, func(tx graph.Transaction) error {
+		if _, err := tx.CreateNode(graph.AsProperties(map[string]any{
+			common.ObjectID.String(): domainSID + "-1104",
+			common.LastSeen.String(): lastSeen,
+			ad.DomainSID.String():    domainSID,
+		}), ad.Entity, ad.User); err != nil {
+			return err
+		}
+
+		_, err := tx.CreateNode(graph.AsProperties(map[string]any{
+			common.ObjectID.String(): domainSID + "-1001",
+			common.LastSeen.String(): lastSeen,
+			ad.DomainSID.String():    domainSID,
+		}), ad.Entity, ad.Computer)
+		return err
+	}))
+
+	fetchStaleObjectIDs := func() []string {
+		var objectIDs []string
+
+		require.Nil(t, graphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+			return tx.Nodes().Filterf(func() graph.Criteria {
+				return query.Equals(query.NodeProperty(common.Stale.String()), true)
+			}).Fetch(func(cursor graph.Cursor[*graph.Node]) error {
+				for node := range cursor.Chan() {
+					objectID, _ := node.Properties.Get(common.ObjectID.String()).String()
+					objectIDs = append(objectIDs, objectID)
+				}
+
+				return cursor.Error()
+			})
+		}))
+
+		return objectIDs
+	}
+
+	// A users file without the domain object, such as a collection limited to an OU search base, leaves the users
+	// that it did not contain alone
+	reconcileJobScopes(ctx, graphDB, 1, model.CollectionScopes{userScope}, appcfg.StaleObjectRetirementMark)
+	require.Empty(t, fetchStaleObjectIDs())
+
+	// Session collection never retires the computers that it did not reach
+	reconcileJobScopes(ctx, graphDB, 2, model.CollectionScopes{sessionScope, domainScope}, appcfg.StaleObjectRetirementMark)
+	require.Empty(t, fetchStaleObjectIDs())
+
+	// A collection of the whole domain retires the user that it did not observe again
+	reconcileJobScopes(ctx, graphDB, 3, model.CollectionScopes{userScope, sessionScope, domainScope}, appcfg.StaleObjectRetirementMark)
+	require.Equal(t, []string{domainSID + "-1104"}, fetchStaleObjectIDs())
+}
+
+func TestMetadata_CollectedRelationshipKinds(t *testing.T) {
+	collected := Metadata{Type: DataTypeComputer, Methods: CollectionMethodSession | CollectionMethodLocalAdmin}.CollectedRelationshipKinds()
+	require.Equal(t, CollectedRelationships{Outbound: graph.Kinds{ad.HasSession}}, collected)
+
+	collected = Metadata{Type: DataTypeComputer, Methods: LocalGroupCollectionMethods | CollectionMethodGroup}.CollectedRelationshipKinds()
+	require.Equal(t, CollectedRelationships{Inbound: graph.Kinds{ad.MemberOf, ad.LocalToComputer}}, collected)
+
+	collected = Metadata{Type: DataTypeGroup, Methods: CollectionMethodObjectProps}.CollectedRelationshipKinds()
+	require.Equal(t, CollectedRelationships{
+		Outbound: graph.Kinds{ad.AllowedToDelegate, ad.HasSIDHistory, ad.DumpSMSAPassword},
+		Inbound:  graph.Kinds{ad.AllowedToAct},
+	}, collected)
+}
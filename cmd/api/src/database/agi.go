@@ -173,12 +173,7 @@ func (s *BloodhoundDB) CreateRawAssetGroupSelector(assetGroup model.AssetGroup,
 }
 
 func (s *BloodhoundDB) CreateAssetGroupSelector(assetGroup model.AssetGroup, spec model.AssetGroupSelectorSpec, systemSelector bool) (model.AssetGroupSelector, error) {
-	assetGroupSelector := model.AssetGroupSelector{
-		AssetGroupID:   assetGroup.ID,
-		Name:           spec.SelectorName,
-		Selector:       spec.EntityObjectID,
-		SystemSelector: systemSelector,
-	}
+	assetGroupSelector := spec.AssetGroupSelector(assetGroup.ID, systemSelector)
 
 	return assetGroupSelector, CheckError(s.db.Create(&assetGroupSelector))
 }
@@ -196,12 +191,7 @@ func (s *BloodhoundDB) UpdateAssetGroupSelectors(ctx ctx.Context, assetGroup mod
 			for _, selectorSpec := range selectorSpecs {
 				switch selectorSpec.Action {
 				case model.SelectorSpecActionAdd:
-					assetGroupSelector := model.AssetGroupSelector{
-						AssetGroupID:   assetGroup.ID,
-						Name:           selectorSpec.SelectorName,
-						Selector:       selectorSpec.EntityObjectID,
-						SystemSelector: systemSelector,
-					}
+					assetGroupSelector := selectorSpec.AssetGroupSelector(assetGroup.ID, systemSelector)
 
 					if result := tx.Create(&assetGroupSelector); result.Error != nil {
 						return CheckError(result)
@@ -213,11 +203,7 @@ func (s *BloodhoundDB) UpdateAssetGroupSelectors(ctx ctx.Context, assetGroup mod
 					if result := tx.Where("asset_group_id=? AND name=?", assetGroup.ID, selectorSpec.SelectorName).Delete(&model.AssetGroupSelector{}); result.Error != nil {
 						return CheckError(result)
 					} else {
-						removedSelectors = append(removedSelectors, model.AssetGroupSelector{
-							AssetGroupID: assetGroup.ID,
-							Name:         selectorSpec.SelectorName,
-							Selector:     selectorSpec.EntityObjectID,
-						})
+						removedSelectors = append(removedSelectors, selectorSpec.AssetGroupSelector(assetGroup.ID, false))
 					}
 				}
 
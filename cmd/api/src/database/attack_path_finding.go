@@ -0,0 +1,145 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"time"
+
+	"github.com/specterops/bloodhound/src/model"
+	"gorm.io/gorm"
+)
+
+func attackPathFindingKey(findingType, objectID string) string {
+	return findingType + "|" + objectID
+}
+
+// SaveAttackPathFindings records the attack path findings detected in a domain at the given time. Findings that were
+// detected before keep their first seen date, and previously detected findings that are missing from the given findings
+// are marked as resolved.
+func (s *BloodhoundDB) SaveAttackPathFindings(environmentID string, findings model.AttackPathFindings, observedAt time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var (
+			existingFindings model.AttackPathFindings
+			detected         = make(map[string]struct{}, len(findings))
+			existing         = map[string]model.AttackPathFinding{}
+		)
+
+		if result := tx.Where("environment_id = ?", environmentID).Find(&existingFindings); result.Error != nil {
+			return CheckError(result)
+		}
+
+		for _, finding := range existingFindings {
+			existing[attackPathFindingKey(finding.FindingType, finding.ObjectID)] = finding
+		}
+
+		for _, finding := range findings {
+			key := attackPathFindingKey(finding.FindingType, finding.ObjectID)
+			detected[key] = struct{}{}
+
+			if existingFinding, found := existing[key]; found {
+				finding.ID = existingFinding.ID
+				finding.CreatedAt = existingFinding.CreatedAt
+				finding.FirstSeen = existingFinding.FirstSeen
+			} else {
+				finding.FirstSeen = observedAt
+			}
+
+			finding.EnvironmentID = environmentID
+			finding.LastSeen = observedAt
+			finding.Resolved = false
+
+			if result := tx.Save(&finding); result.Error != nil {
+				return CheckError(result)
+			}
+		}
+
+		for key, finding := range existing {
+			if _, isDetected := detected[key]; !isDetected && !finding.Resolved {
+				if result := tx.Model(&finding).Update("resolved", true); result.Error != nil {
+					return CheckError(result)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetAllAttackPathFindings returns every attack path finding of a domain, including the resolved findings
+func (s *BloodhoundDB) GetAllAttackPathFindings(environmentID string) (model.AttackPathFindings, error) {
+	var (
+		findings model.AttackPathFindings
+		result   = s.db.Where("environment_id = ?", environmentID).Order("id").Find(&findings)
+	)
+
+	return findings, CheckError(result)
+}
+
+// GetAttackPathFindings returns the principals of a domain affected by the given type of attack path finding along with
+// the total number of matching findings
+func (s *BloodhoundDB) GetAttackPathFindings(environmentID, findingType string, includeResolved bool, limit, skip int) (model.AttackPathFindings, int, error) {
+	var (
+		findings    model.AttackPathFindings
+		count       int64
+		whereClause = "environment_id = ? AND finding_type = ?"
+		params      = []any{environmentID, findingType}
+	)
+
+	if !includeResolved {
+		whereClause += " AND resolved = ?"
+		params = append(params, false)
+	}
+
+	if result := s.Scope(Paginate(skip, limit)).Where(whereClause, params...).Order("name").Find(&findings); result.Error != nil {
+		return findings, 0, CheckError(result)
+	} else if result := s.db.Model(&model.AttackPathFinding{}).Where(whereClause, params...).Count(&count); result.Error != nil {
+		return findings, 0, CheckError(result)
+	}
+
+	return findings, int(count), nil
+}
+
+// GetRiskAcceptances returns the risk acceptances of a domain
+func (s *BloodhoundDB) GetRiskAcceptances(environmentID string) (model.RiskAcceptances, error) {
+	var (
+		acceptances model.RiskAcceptances
+		result      = s.db.Where("environment_id = ?", environmentID).Order("id").Find(&acceptances)
+	)
+
+	return acceptances, CheckError(result)
+}
+
+// SaveRiskAcceptance creates or replaces the risk acceptance of a type of attack path finding in a domain
+func (s *BloodhoundDB) SaveRiskAcceptance(acceptance model.RiskAcceptance) (model.RiskAcceptance, error) {
+	return acceptance, s.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.RiskAcceptance
+
+		if result := tx.Where("environment_id = ? AND finding_type = ?", acceptance.EnvironmentID, acceptance.FindingType).Find(&existing); result.Error != nil {
+			return CheckError(result)
+		} else if result.RowsAffected > 0 {
+			acceptance.ID = existing.ID
+			acceptance.CreatedAt = existing.CreatedAt
+		}
+
+		return CheckError(tx.Save(&acceptance))
+	})
+}
+
+// DeleteRiskAcceptance removes the risk acceptance of a type of attack path finding in a domain
+func (s *BloodhoundDB) DeleteRiskAcceptance(environmentID, findingType string) error {
+	return CheckError(s.db.Where("environment_id = ? AND finding_type = ?", environmentID, findingType).Delete(&model.RiskAcceptance{}))
+}
@@ -96,7 +96,7 @@ func (s *BloodhoundDB) CreateRole(role model.Role) (model.Role, error) {
 	return updatedRole, CheckError(result)
 }
 
-// UpdateRole updates permissions for the row matching the provided Role struct
+// UpdateRole updates permissions and environments for the row matching the provided Role struct
 // UPDATE roles SET permissions=.... WHERE role_id = ...
 func (s *BloodhoundDB) UpdateRole(role model.Role) error {
 	// Update permissions first
@@ -104,10 +104,35 @@ func (s *BloodhoundDB) UpdateRole(role model.Role) error {
 		return err
 	}
 
+	// Environments are replaced rather than merged; the role's current environments are saved along with the role
+	if result := s.db.Where("role_id = ?", role.ID).Delete(&model.RoleEnvironment{}); result.Error != nil {
+		return CheckError(result)
+	}
+
 	result := s.db.Save(&role)
 	return CheckError(result)
 }
 
+// DeleteRole deletes the provided role. Roles that are assigned to users can not be deleted.
+// DELETE FROM roles WHERE id = ...
+func (s *BloodhoundDB) DeleteRole(role model.Role) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var assignedUsers int64
+
+		if result := tx.Table("users_roles").Where("role_id = ?", role.ID).Count(&assignedUsers); result.Error != nil {
+			return CheckError(result)
+		} else if assignedUsers > 0 {
+			return ErrRoleInUse
+		} else if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		} else if result := tx.Where("role_id = ?", role.ID).Delete(&model.RoleEnvironment{}); result.Error != nil {
+			return CheckError(result)
+		}
+
+		return CheckError(tx.Delete(&role))
+	})
+}
+
 // GetAllRoles retrieves all available roles in the db
 // SELECT * FROM roles
 func (s *BloodhoundDB) GetAllRoles(order string, filter model.SQLFilter) (model.Roles, error) {
@@ -409,6 +434,13 @@ func (s *BloodhoundDB) DeleteUser(user model.User) error {
 			return err
 		}
 
+		// SCIM group memberships and tokens only reference the user by ID
+		if result := tx.Where("user_id = ?", user.ID).Delete(&model.SCIMGroupMember{}); result.Error != nil {
+			return CheckError(result)
+		} else if result := tx.Where("user_id = ?", user.ID).Delete(&model.SCIMToken{}); result.Error != nil {
+			return CheckError(result)
+		}
+
 		result := tx.Delete(&user)
 		return CheckError(result)
 	})
@@ -560,8 +592,14 @@ func (s *BloodhoundDB) CreateSAMLIdentityProvider(samlProvider model.SAMLProvide
 // CreateSAMLProvider updates a saml_providers row using the data in the input struct
 // UPDATE saml_identity_providers SET (...) VALUES (...) WHERE id = ...
 func (s *BloodhoundDB) UpdateSAMLIdentityProvider(provider model.SAMLProvider) error {
-	result := s.db.Save(&provider)
-	return CheckError(result)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Role mappings are replaced rather than merged; the provider's current mappings are saved along with it
+		if result := tx.Where("saml_provider_id = ?", provider.ID).Delete(&model.SAMLRoleMapping{}); result.Error != nil {
+			return CheckError(result)
+		}
+
+		return CheckError(tx.Save(&provider))
+	})
 }
 
 // LookupSAMLProviderByName returns a SAML provider corresponding to the name provided
@@ -569,7 +607,7 @@ func (s *BloodhoundDB) UpdateSAMLIdentityProvider(provider model.SAMLProvider) e
 func (s *BloodhoundDB) LookupSAMLProviderByName(name string) (model.SAMLProvider, error) {
 	var (
 		samlProvider model.SAMLProvider
-		result       = s.db.Where("name = ?", name).Find(&samlProvider)
+		result       = s.preload(model.SAMLProviderAssociations()).Where("name = ?", name).Find(&samlProvider)
 	)
 
 	return samlProvider, CheckError(result)
@@ -580,7 +618,7 @@ func (s *BloodhoundDB) LookupSAMLProviderByName(name string) (model.SAMLProvider
 func (s *BloodhoundDB) GetAllSAMLProviders() (model.SAMLProviders, error) {
 	var (
 		samlProviders model.SAMLProviders
-		result        = s.db.Find(&samlProviders)
+		result        = s.preload(model.SAMLProviderAssociations()).Find(&samlProviders)
 	)
 
 	return samlProviders, CheckError(result)
@@ -591,14 +629,20 @@ func (s *BloodhoundDB) GetAllSAMLProviders() (model.SAMLProviders, error) {
 func (s *BloodhoundDB) GetSAMLProvider(id int32) (model.SAMLProvider, error) {
 	var (
 		samlProvider model.SAMLProvider
-		result       = s.db.First(&samlProvider, id)
+		result       = s.preload(model.SAMLProviderAssociations()).First(&samlProvider, id)
 	)
 
 	return samlProvider, CheckError(result)
 }
 
 func (s *BloodhoundDB) DeleteSAMLProvider(provider model.SAMLProvider) error {
-	return CheckError(s.db.Delete(&provider))
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where("saml_provider_id = ?", provider.ID).Delete(&model.SAMLRoleMapping{}); result.Error != nil {
+			return CheckError(result)
+		}
+
+		return CheckError(tx.Delete(&provider))
+	})
 }
 
 // GetSAMLProviderUsers returns all users that are bound to the SAML provider ID provided
@@ -608,6 +652,79 @@ func (s *BloodhoundDB) GetSAMLProviderUsers(id int32) (model.Users, error) {
 	return users, CheckError(s.preload(model.UserAssociations()).Where("saml_provider_id = ?", id).Find(&users))
 }
 
+// CreateOIDCProvider creates a new oidc_providers row along with its role mappings
+// INSERT INTO oidc_providers (...) VALUES (...)
+func (s *BloodhoundDB) CreateOIDCProvider(oidcProvider model.OIDCProvider) (model.OIDCProvider, error) {
+	var (
+		updatedOIDCProvider = oidcProvider
+		result              = s.db.Create(&updatedOIDCProvider)
+	)
+
+	return updatedOIDCProvider, CheckError(result)
+}
+
+// LookupOIDCProviderByName returns an OIDC provider corresponding to the name provided
+// SELECT * FROM oidc_providers WHERE name = ....
+func (s *BloodhoundDB) LookupOIDCProviderByName(name string) (model.OIDCProvider, error) {
+	var (
+		oidcProvider model.OIDCProvider
+		result       = s.preload(model.OIDCProviderAssociations()).Where("name = ?", name).First(&oidcProvider)
+	)
+
+	return oidcProvider, CheckError(result)
+}
+
+// GetAllOIDCProviders returns all OIDC providers
+// SELECT * FROM oidc_providers
+func (s *BloodhoundDB) GetAllOIDCProviders() (model.OIDCProviders, error) {
+	var (
+		oidcProviders model.OIDCProviders
+		result        = s.preload(model.OIDCProviderAssociations()).Find(&oidcProviders)
+	)
+
+	return oidcProviders, CheckError(result)
+}
+
+// GetOIDCProvider returns an OIDC provider corresponding to the ID provided
+// SELECT * FROM oidc_providers WHERE id = ..
+func (s *BloodhoundDB) GetOIDCProvider(id int32) (model.OIDCProvider, error) {
+	var (
+		oidcProvider model.OIDCProvider
+		result       = s.preload(model.OIDCProviderAssociations()).First(&oidcProvider, id)
+	)
+
+	return oidcProvider, CheckError(result)
+}
+
+// GetOIDCProviderUsers returns all users that are bound to the OIDC provider ID provided
+// SELECT * FROM users WHERE oidc_provider_id = ..
+func (s *BloodhoundDB) GetOIDCProviderUsers(id int32) (model.Users, error) {
+	var users model.Users
+	return users, CheckError(s.preload(model.UserAssociations()).Where("oidc_provider_id = ?", id).Find(&users))
+}
+
+// UpdateOIDCProvider updates an oidc_providers row and replaces its role mappings
+// UPDATE oidc_providers SET (...) VALUES (...) WHERE id = ...
+func (s *BloodhoundDB) UpdateOIDCProvider(provider model.OIDCProvider) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where("oidc_provider_id = ?", provider.ID).Delete(&model.OIDCRoleMapping{}); result.Error != nil {
+			return CheckError(result)
+		}
+
+		return CheckError(tx.Save(&provider))
+	})
+}
+
+func (s *BloodhoundDB) DeleteOIDCProvider(provider model.OIDCProvider) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where("oidc_provider_id = ?", provider.ID).Delete(&model.OIDCRoleMapping{}); result.Error != nil {
+			return CheckError(result)
+		}
+
+		return CheckError(tx.Delete(&provider))
+	})
+}
+
 // CreateUserSession creates a new UserSession row
 // INSERT INTO user_sessions (...) VALUES (..)
 func (s *BloodhoundDB) CreateUserSession(userSession model.UserSession) (model.UserSession, error) {
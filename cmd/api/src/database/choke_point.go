@@ -0,0 +1,59 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"github.com/specterops/bloodhound/src/model"
+	"gorm.io/gorm"
+)
+
+// ReplaceChokePoints replaces every stored choke point with the given choke points
+func (s *BloodhoundDB) ReplaceChokePoints(chokePoints model.ChokePoints) error {
+	const deleteChokePointsQuery = `DELETE FROM choke_points;`
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Exec(deleteChokePointsQuery); result.Error != nil {
+			return CheckError(result)
+		}
+
+		// GORM will fail on an attempt to insert an empty slice
+		if len(chokePoints) > 0 {
+			if result := tx.Create(&chokePoints); result.Error != nil {
+				return CheckError(result)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetChokePoints returns the ranked choke points of the given domain or tenant along with the total number of choke
+// points stored for it
+func (s *BloodhoundDB) GetChokePoints(environmentID string, limit, skip int) (model.ChokePoints, int, error) {
+	var (
+		chokePoints model.ChokePoints
+		count       int64
+	)
+
+	if result := s.Scope(Paginate(skip, limit)).Where("environment_id = ?", environmentID).Order("rank").Find(&chokePoints); result.Error != nil {
+		return chokePoints, 0, CheckError(result)
+	} else if result := s.db.Model(&model.ChokePoint{}).Where("environment_id = ?", environmentID).Count(&count); result.Error != nil {
+		return chokePoints, 0, CheckError(result)
+	}
+
+	return chokePoints, int(count), nil
+}
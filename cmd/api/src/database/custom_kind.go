@@ -0,0 +1,38 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"github.com/specterops/bloodhound/src/model"
+	"gorm.io/gorm/clause"
+)
+
+func (s *BloodhoundDB) GetCustomKinds() (model.CustomKinds, error) {
+	var customKinds model.CustomKinds
+	result := s.db.Order("id").Find(&customKinds)
+
+	return customKinds, CheckError(result)
+}
+
+// CreateCustomKinds registers the given custom kinds, ignoring kinds that are already registered
+func (s *BloodhoundDB) CreateCustomKinds(customKinds model.CustomKinds) error {
+	if len(customKinds) == 0 {
+		return nil
+	}
+
+	return CheckError(s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&customKinds))
+}
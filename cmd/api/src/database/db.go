@@ -35,7 +35,8 @@ import (
 )
 
 const (
-	ErrNotFound = errors.Error("entity not found")
+	ErrNotFound  = errors.Error("entity not found")
+	ErrRoleInUse = errors.Error("role is assigned to one or more users")
 )
 
 func IsUnexpectedDatabaseError(err error) bool {
@@ -58,7 +59,31 @@ type Database interface {
 	GetAllIngestTasks() (model.IngestTasks, error)
 	DeleteIngestTask(ingestTask model.IngestTask) error
 	GetIngestTasksForJob(jobID int64) (model.IngestTasks, error)
+	CreateIngestFileReport(report model.IngestFileReport) (model.IngestFileReport, error)
+	GetIngestFileReportsForJob(jobID int64) (model.IngestFileReports, error)
+	CreateCollectionScopes(scopes model.CollectionScopes) error
+	GetCollectionScopesForJob(jobID int64) (model.CollectionScopes, error)
 	GetUnfinishedIngestIDs() ([]int64, error)
+	GetCustomKinds() (model.CustomKinds, error)
+	CreateCustomKinds(customKinds model.CustomKinds) error
+	CreateGraphSnapshot(snapshot model.GraphSnapshot) (model.GraphSnapshot, error)
+	GetGraphSnapshots() (model.GraphSnapshots, error)
+	GetGraphSnapshot(id int32) (model.GraphSnapshot, error)
+	DeleteGraphSnapshot(snapshot model.GraphSnapshot) error
+	ReplaceChokePoints(chokePoints model.ChokePoints) error
+	GetChokePoints(environmentID string, limit, skip int) (model.ChokePoints, int, error)
+	CreateExposureRecords(records model.ExposureRecords) error
+	SaveAttackPathFindings(environmentID string, findings model.AttackPathFindings, observedAt time.Time) error
+	GetAllAttackPathFindings(environmentID string) (model.AttackPathFindings, error)
+	GetAttackPathFindings(environmentID, findingType string, includeResolved bool, limit, skip int) (model.AttackPathFindings, int, error)
+	GetRiskAcceptances(environmentID string) (model.RiskAcceptances, error)
+	SaveRiskAcceptance(acceptance model.RiskAcceptance) (model.RiskAcceptance, error)
+	DeleteRiskAcceptance(environmentID, findingType string) error
+	CreatePostProcessingRule(rule model.PostProcessingRule) (model.PostProcessingRule, error)
+	GetAllPostProcessingRules() (model.PostProcessingRules, error)
+	GetPostProcessingRule(id int32) (model.PostProcessingRule, error)
+	UpdatePostProcessingRule(rule model.PostProcessingRule) error
+	DeletePostProcessingRule(rule model.PostProcessingRule) error
 	CreateAssetGroup(name, tag string, systemGroup bool) (model.AssetGroup, error)
 	UpdateAssetGroup(assetGroup model.AssetGroup) error
 	DeleteAssetGroup(assetGroup model.AssetGroup) error
@@ -87,6 +112,7 @@ type Database interface {
 	GetAuditLogsCount() (int, error)
 	CreateRole(role model.Role) (model.Role, error)
 	UpdateRole(role model.Role) error
+	DeleteRole(role model.Role) error
 	GetAllRoles(order string, filter model.SQLFilter) (model.Roles, error)
 	GetRoles(ids []int32) (model.Roles, error)
 	GetRolesByName(names []string) (model.Roles, error)
@@ -124,6 +150,24 @@ type Database interface {
 	GetSAMLProvider(id int32) (model.SAMLProvider, error)
 	GetSAMLProviderUsers(id int32) (model.Users, error)
 	DeleteSAMLProvider(samlProvider model.SAMLProvider) error
+	CreateOIDCProvider(oidcProvider model.OIDCProvider) (model.OIDCProvider, error)
+	LookupOIDCProviderByName(name string) (model.OIDCProvider, error)
+	GetAllOIDCProviders() (model.OIDCProviders, error)
+	GetOIDCProvider(id int32) (model.OIDCProvider, error)
+	GetOIDCProviderUsers(id int32) (model.Users, error)
+	UpdateOIDCProvider(oidcProvider model.OIDCProvider) error
+	DeleteOIDCProvider(oidcProvider model.OIDCProvider) error
+	CreateSCIMToken(scimToken model.SCIMToken) (model.SCIMToken, error)
+	GetAllSCIMTokens() (model.SCIMTokens, error)
+	GetSCIMToken(id int32) (model.SCIMToken, error)
+	LookupSCIMToken(digest string) (model.SCIMToken, error)
+	UpdateSCIMToken(scimToken model.SCIMToken) error
+	DeleteSCIMToken(scimToken model.SCIMToken) error
+	CreateSCIMGroup(scimGroup model.SCIMGroup) (model.SCIMGroup, error)
+	GetAllSCIMGroups() (model.SCIMGroups, error)
+	GetSCIMGroup(id int32) (model.SCIMGroup, error)
+	UpdateSCIMGroup(scimGroup model.SCIMGroup) error
+	DeleteSCIMGroup(scimGroup model.SCIMGroup) error
 	CreateUserSession(userSession model.UserSession) (model.UserSession, error)
 	LookupActiveSessionsByUser(user model.User) ([]model.UserSession, error)
 	EndUserSession(userSession model.UserSession)
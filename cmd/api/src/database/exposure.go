@@ -0,0 +1,34 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// exposureRecordBatchSize caps the number of rows written per insert statement
+const exposureRecordBatchSize = 1000
+
+// CreateExposureRecords appends the given exposure records to the exposure history of their principals
+func (s *BloodhoundDB) CreateExposureRecords(records model.ExposureRecords) error {
+	// GORM will fail on an attempt to insert an empty slice
+	if len(records) == 0 {
+		return nil
+	}
+
+	return CheckError(s.db.CreateInBatches(&records, exposureRecordBatchSize))
+}
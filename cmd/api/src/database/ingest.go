@@ -49,3 +49,31 @@ func (s *BloodhoundDB) GetUnfinishedIngestIDs() ([]int64, error) {
 
 	return ids, CheckError(result)
 }
+
+func (s *BloodhoundDB) CreateIngestFileReport(report model.IngestFileReport) (model.IngestFileReport, error) {
+	result := s.db.Create(&report)
+
+	return report, CheckError(result)
+}
+
+func (s *BloodhoundDB) GetIngestFileReportsForJob(jobID int64) (model.IngestFileReports, error) {
+	var reports model.IngestFileReports
+	result := s.db.Where("file_upload_job_id = ?", jobID).Order("id").Find(&reports)
+
+	return reports, CheckError(result)
+}
+
+func (s *BloodhoundDB) CreateCollectionScopes(scopes model.CollectionScopes) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	return CheckError(s.db.Create(&scopes))
+}
+
+func (s *BloodhoundDB) GetCollectionScopesForJob(jobID int64) (model.CollectionScopes, error) {
+	var scopes model.CollectionScopes
+	result := s.db.Where("file_upload_job_id = ?", jobID).Order("id").Find(&scopes)
+
+	return scopes, CheckError(result)
+}
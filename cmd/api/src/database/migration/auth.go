@@ -109,8 +109,8 @@ func (s *Migrator) updateRoles() error {
 					return err
 
 				} else if existingRole, found := existingRoles.FindByName(expectedRole.Name); !found {
-					// If cannot find by name, lookup by permissions set
-					if existingRole, ok := existingRoles.FindByPermissions(expectedRole.Permissions); !ok {
+					// If cannot find by name, lookup by permissions set. Custom roles are never treated as a renamed template.
+					if existingRole, ok := existingRoles.BuiltIn().FindByPermissions(expectedRole.Permissions); !ok {
 						// If no Role exists w/ expectedPermissions, create new Role
 						if result := tx.Create(&expectedRole); result.Error != nil {
 							return result.Error
@@ -46,14 +46,24 @@ func ListBHModels() []any {
 		&model.Installation{},
 		&model.User{},
 		&model.Role{},
+		&model.RoleEnvironment{},
 		&model.Permission{},
 		&model.AuthSecret{},
 		&model.AuthToken{},
 		&model.SAMLProvider{},
+		&model.OIDCProvider{},
+		&model.OIDCRoleMapping{},
+		&model.SAMLRoleMapping{},
+		&model.SCIMToken{},
+		&model.SCIMGroup{},
+		&model.SCIMGroupMember{},
 		&model.UserSession{},
 
 		// Ingest model
 		&model.IngestTask{},
+		&model.IngestFileReport{},
+		&model.CollectionScope{},
+		&model.CustomKind{},
 
 		// Database stats
 		&model.ADDataQualityStat{},
@@ -62,6 +72,22 @@ func ListBHModels() []any {
 		&model.AzureDataQualityAggregation{},
 		&model.DomainCollectionResult{},
 
+		// Graph snapshots
+		&model.GraphSnapshot{},
+
+		// Attack path choke points
+		&model.ChokePoint{},
+
+		// Principal exposure history
+		&model.ExposureRecord{},
+
+		// Rule based post-processing
+		&model.PostProcessingRule{},
+
+		// Attack path findings
+		&model.AttackPathFinding{},
+		&model.RiskAcceptance{},
+
 		&model.FileUploadJob{},
 	}
 }
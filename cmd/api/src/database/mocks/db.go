@@ -202,6 +202,48 @@ func (mr *MockDatabaseMockRecorder) CreateAzureDataQualityStats(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAzureDataQualityStats", reflect.TypeOf((*MockDatabase)(nil).CreateAzureDataQualityStats), arg0)
 }
 
+// CreateCollectionScopes mocks base method.
+func (m *MockDatabase) CreateCollectionScopes(arg0 model.CollectionScopes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollectionScopes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCollectionScopes indicates an expected call of CreateCollectionScopes.
+func (mr *MockDatabaseMockRecorder) CreateCollectionScopes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollectionScopes", reflect.TypeOf((*MockDatabase)(nil).CreateCollectionScopes), arg0)
+}
+
+// CreateCustomKinds mocks base method.
+func (m *MockDatabase) CreateCustomKinds(arg0 model.CustomKinds) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomKinds", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCustomKinds indicates an expected call of CreateCustomKinds.
+func (mr *MockDatabaseMockRecorder) CreateCustomKinds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomKinds", reflect.TypeOf((*MockDatabase)(nil).CreateCustomKinds), arg0)
+}
+
+// CreateExposureRecords mocks base method.
+func (m *MockDatabase) CreateExposureRecords(arg0 model.ExposureRecords) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExposureRecords", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExposureRecords indicates an expected call of CreateExposureRecords.
+func (mr *MockDatabaseMockRecorder) CreateExposureRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExposureRecords", reflect.TypeOf((*MockDatabase)(nil).CreateExposureRecords), arg0)
+}
+
 // CreateFileUploadJob mocks base method.
 func (m *MockDatabase) CreateFileUploadJob(arg0 model.FileUploadJob) (model.FileUploadJob, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +259,36 @@ func (mr *MockDatabaseMockRecorder) CreateFileUploadJob(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFileUploadJob", reflect.TypeOf((*MockDatabase)(nil).CreateFileUploadJob), arg0)
 }
 
+// CreateGraphSnapshot mocks base method.
+func (m *MockDatabase) CreateGraphSnapshot(arg0 model.GraphSnapshot) (model.GraphSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGraphSnapshot", arg0)
+	ret0, _ := ret[0].(model.GraphSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGraphSnapshot indicates an expected call of CreateGraphSnapshot.
+func (mr *MockDatabaseMockRecorder) CreateGraphSnapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGraphSnapshot", reflect.TypeOf((*MockDatabase)(nil).CreateGraphSnapshot), arg0)
+}
+
+// CreateIngestFileReport mocks base method.
+func (m *MockDatabase) CreateIngestFileReport(arg0 model.IngestFileReport) (model.IngestFileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngestFileReport", arg0)
+	ret0, _ := ret[0].(model.IngestFileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIngestFileReport indicates an expected call of CreateIngestFileReport.
+func (mr *MockDatabaseMockRecorder) CreateIngestFileReport(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngestFileReport", reflect.TypeOf((*MockDatabase)(nil).CreateIngestFileReport), arg0)
+}
+
 // CreateIngestTask mocks base method.
 func (m *MockDatabase) CreateIngestTask(arg0 model.IngestTask) (model.IngestTask, error) {
 	m.ctrl.T.Helper()
@@ -247,6 +319,21 @@ func (mr *MockDatabaseMockRecorder) CreateInstallation() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstallation", reflect.TypeOf((*MockDatabase)(nil).CreateInstallation))
 }
 
+// CreateOIDCProvider mocks base method.
+func (m *MockDatabase) CreateOIDCProvider(arg0 model.OIDCProvider) (model.OIDCProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCProvider", arg0)
+	ret0, _ := ret[0].(model.OIDCProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCProvider indicates an expected call of CreateOIDCProvider.
+func (mr *MockDatabaseMockRecorder) CreateOIDCProvider(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCProvider", reflect.TypeOf((*MockDatabase)(nil).CreateOIDCProvider), arg0)
+}
+
 // CreatePermission mocks base method.
 func (m *MockDatabase) CreatePermission(arg0 model.Permission) (model.Permission, error) {
 	m.ctrl.T.Helper()
@@ -262,6 +349,21 @@ func (mr *MockDatabaseMockRecorder) CreatePermission(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePermission", reflect.TypeOf((*MockDatabase)(nil).CreatePermission), arg0)
 }
 
+// CreatePostProcessingRule mocks base method.
+func (m *MockDatabase) CreatePostProcessingRule(arg0 model.PostProcessingRule) (model.PostProcessingRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePostProcessingRule", arg0)
+	ret0, _ := ret[0].(model.PostProcessingRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePostProcessingRule indicates an expected call of CreatePostProcessingRule.
+func (mr *MockDatabaseMockRecorder) CreatePostProcessingRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostProcessingRule", reflect.TypeOf((*MockDatabase)(nil).CreatePostProcessingRule), arg0)
+}
+
 // CreateRawAssetGroupSelector mocks base method.
 func (m *MockDatabase) CreateRawAssetGroupSelector(arg0 model.AssetGroup, arg1, arg2 string) (model.AssetGroupSelector, error) {
 	m.ctrl.T.Helper()
@@ -307,6 +409,36 @@ func (mr *MockDatabaseMockRecorder) CreateSAMLIdentityProvider(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSAMLIdentityProvider", reflect.TypeOf((*MockDatabase)(nil).CreateSAMLIdentityProvider), arg0)
 }
 
+// CreateSCIMGroup mocks base method.
+func (m *MockDatabase) CreateSCIMGroup(arg0 model.SCIMGroup) (model.SCIMGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSCIMGroup", arg0)
+	ret0, _ := ret[0].(model.SCIMGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSCIMGroup indicates an expected call of CreateSCIMGroup.
+func (mr *MockDatabaseMockRecorder) CreateSCIMGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSCIMGroup", reflect.TypeOf((*MockDatabase)(nil).CreateSCIMGroup), arg0)
+}
+
+// CreateSCIMToken mocks base method.
+func (m *MockDatabase) CreateSCIMToken(arg0 model.SCIMToken) (model.SCIMToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSCIMToken", arg0)
+	ret0, _ := ret[0].(model.SCIMToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSCIMToken indicates an expected call of CreateSCIMToken.
+func (mr *MockDatabaseMockRecorder) CreateSCIMToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSCIMToken", reflect.TypeOf((*MockDatabase)(nil).CreateSCIMToken), arg0)
+}
+
 // CreateUser mocks base method.
 func (m *MockDatabase) CreateUser(arg0 model.User) (model.User, error) {
 	m.ctrl.T.Helper()
@@ -393,6 +525,20 @@ func (mr *MockDatabaseMockRecorder) DeleteAuthToken(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthToken", reflect.TypeOf((*MockDatabase)(nil).DeleteAuthToken), arg0)
 }
 
+// DeleteGraphSnapshot mocks base method.
+func (m *MockDatabase) DeleteGraphSnapshot(arg0 model.GraphSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGraphSnapshot", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGraphSnapshot indicates an expected call of DeleteGraphSnapshot.
+func (mr *MockDatabaseMockRecorder) DeleteGraphSnapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGraphSnapshot", reflect.TypeOf((*MockDatabase)(nil).DeleteGraphSnapshot), arg0)
+}
+
 // DeleteIngestTask mocks base method.
 func (m *MockDatabase) DeleteIngestTask(arg0 model.IngestTask) error {
 	m.ctrl.T.Helper()
@@ -407,6 +553,62 @@ func (mr *MockDatabaseMockRecorder) DeleteIngestTask(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIngestTask", reflect.TypeOf((*MockDatabase)(nil).DeleteIngestTask), arg0)
 }
 
+// DeleteOIDCProvider mocks base method.
+func (m *MockDatabase) DeleteOIDCProvider(arg0 model.OIDCProvider) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOIDCProvider", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOIDCProvider indicates an expected call of DeleteOIDCProvider.
+func (mr *MockDatabaseMockRecorder) DeleteOIDCProvider(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOIDCProvider", reflect.TypeOf((*MockDatabase)(nil).DeleteOIDCProvider), arg0)
+}
+
+// DeletePostProcessingRule mocks base method.
+func (m *MockDatabase) DeletePostProcessingRule(arg0 model.PostProcessingRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePostProcessingRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostProcessingRule indicates an expected call of DeletePostProcessingRule.
+func (mr *MockDatabaseMockRecorder) DeletePostProcessingRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostProcessingRule", reflect.TypeOf((*MockDatabase)(nil).DeletePostProcessingRule), arg0)
+}
+
+// DeleteRiskAcceptance mocks base method.
+func (m *MockDatabase) DeleteRiskAcceptance(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRiskAcceptance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRiskAcceptance indicates an expected call of DeleteRiskAcceptance.
+func (mr *MockDatabaseMockRecorder) DeleteRiskAcceptance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRiskAcceptance", reflect.TypeOf((*MockDatabase)(nil).DeleteRiskAcceptance), arg0, arg1)
+}
+
+// DeleteRole mocks base method.
+func (m *MockDatabase) DeleteRole(arg0 model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockDatabaseMockRecorder) DeleteRole(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockDatabase)(nil).DeleteRole), arg0)
+}
+
 // DeleteSAMLProvider mocks base method.
 func (m *MockDatabase) DeleteSAMLProvider(arg0 model.SAMLProvider) error {
 	m.ctrl.T.Helper()
@@ -421,6 +623,34 @@ func (mr *MockDatabaseMockRecorder) DeleteSAMLProvider(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSAMLProvider", reflect.TypeOf((*MockDatabase)(nil).DeleteSAMLProvider), arg0)
 }
 
+// DeleteSCIMGroup mocks base method.
+func (m *MockDatabase) DeleteSCIMGroup(arg0 model.SCIMGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSCIMGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSCIMGroup indicates an expected call of DeleteSCIMGroup.
+func (mr *MockDatabaseMockRecorder) DeleteSCIMGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCIMGroup", reflect.TypeOf((*MockDatabase)(nil).DeleteSCIMGroup), arg0)
+}
+
+// DeleteSCIMToken mocks base method.
+func (m *MockDatabase) DeleteSCIMToken(arg0 model.SCIMToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSCIMToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSCIMToken indicates an expected call of DeleteSCIMToken.
+func (mr *MockDatabaseMockRecorder) DeleteSCIMToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCIMToken", reflect.TypeOf((*MockDatabase)(nil).DeleteSCIMToken), arg0)
+}
+
 // DeleteUser mocks base method.
 func (m *MockDatabase) DeleteUser(arg0 model.User) error {
 	m.ctrl.T.Helper()
@@ -524,6 +754,21 @@ func (mr *MockDatabaseMockRecorder) GetAllAssetGroups(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAssetGroups", reflect.TypeOf((*MockDatabase)(nil).GetAllAssetGroups), arg0, arg1)
 }
 
+// GetAllAttackPathFindings mocks base method.
+func (m *MockDatabase) GetAllAttackPathFindings(arg0 string) (model.AttackPathFindings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllAttackPathFindings", arg0)
+	ret0, _ := ret[0].(model.AttackPathFindings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllAttackPathFindings indicates an expected call of GetAllAttackPathFindings.
+func (mr *MockDatabaseMockRecorder) GetAllAttackPathFindings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAttackPathFindings", reflect.TypeOf((*MockDatabase)(nil).GetAllAttackPathFindings), arg0)
+}
+
 // GetAllAuthTokens mocks base method.
 func (m *MockDatabase) GetAllAuthTokens(arg0 string, arg1 model.SQLFilter) (model.AuthTokens, error) {
 	m.ctrl.T.Helper()
@@ -600,6 +845,21 @@ func (mr *MockDatabaseMockRecorder) GetAllIngestTasks() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllIngestTasks", reflect.TypeOf((*MockDatabase)(nil).GetAllIngestTasks))
 }
 
+// GetAllOIDCProviders mocks base method.
+func (m *MockDatabase) GetAllOIDCProviders() (model.OIDCProviders, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllOIDCProviders")
+	ret0, _ := ret[0].(model.OIDCProviders)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllOIDCProviders indicates an expected call of GetAllOIDCProviders.
+func (mr *MockDatabaseMockRecorder) GetAllOIDCProviders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllOIDCProviders", reflect.TypeOf((*MockDatabase)(nil).GetAllOIDCProviders))
+}
+
 // GetAllPermissions mocks base method.
 func (m *MockDatabase) GetAllPermissions(arg0 string, arg1 model.SQLFilter) (model.Permissions, error) {
 	m.ctrl.T.Helper()
@@ -615,6 +875,21 @@ func (mr *MockDatabaseMockRecorder) GetAllPermissions(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPermissions", reflect.TypeOf((*MockDatabase)(nil).GetAllPermissions), arg0, arg1)
 }
 
+// GetAllPostProcessingRules mocks base method.
+func (m *MockDatabase) GetAllPostProcessingRules() (model.PostProcessingRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllPostProcessingRules")
+	ret0, _ := ret[0].(model.PostProcessingRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllPostProcessingRules indicates an expected call of GetAllPostProcessingRules.
+func (mr *MockDatabaseMockRecorder) GetAllPostProcessingRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPostProcessingRules", reflect.TypeOf((*MockDatabase)(nil).GetAllPostProcessingRules))
+}
+
 // GetAllRoles mocks base method.
 func (m *MockDatabase) GetAllRoles(arg0 string, arg1 model.SQLFilter) (model.Roles, error) {
 	m.ctrl.T.Helper()
@@ -645,6 +920,36 @@ func (mr *MockDatabaseMockRecorder) GetAllSAMLProviders() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSAMLProviders", reflect.TypeOf((*MockDatabase)(nil).GetAllSAMLProviders))
 }
 
+// GetAllSCIMGroups mocks base method.
+func (m *MockDatabase) GetAllSCIMGroups() (model.SCIMGroups, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSCIMGroups")
+	ret0, _ := ret[0].(model.SCIMGroups)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllSCIMGroups indicates an expected call of GetAllSCIMGroups.
+func (mr *MockDatabaseMockRecorder) GetAllSCIMGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSCIMGroups", reflect.TypeOf((*MockDatabase)(nil).GetAllSCIMGroups))
+}
+
+// GetAllSCIMTokens mocks base method.
+func (m *MockDatabase) GetAllSCIMTokens() (model.SCIMTokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSCIMTokens")
+	ret0, _ := ret[0].(model.SCIMTokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllSCIMTokens indicates an expected call of GetAllSCIMTokens.
+func (mr *MockDatabaseMockRecorder) GetAllSCIMTokens() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSCIMTokens", reflect.TypeOf((*MockDatabase)(nil).GetAllSCIMTokens))
+}
+
 // GetAllUsers mocks base method.
 func (m *MockDatabase) GetAllUsers(arg0 string, arg1 model.SQLFilter) (model.Users, error) {
 	m.ctrl.T.Helper()
@@ -705,6 +1010,22 @@ func (mr *MockDatabaseMockRecorder) GetAssetGroupSelector(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssetGroupSelector", reflect.TypeOf((*MockDatabase)(nil).GetAssetGroupSelector), arg0)
 }
 
+// GetAttackPathFindings mocks base method.
+func (m *MockDatabase) GetAttackPathFindings(arg0, arg1 string, arg2 bool, arg3, arg4 int) (model.AttackPathFindings, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttackPathFindings", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(model.AttackPathFindings)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAttackPathFindings indicates an expected call of GetAttackPathFindings.
+func (mr *MockDatabaseMockRecorder) GetAttackPathFindings(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttackPathFindings", reflect.TypeOf((*MockDatabase)(nil).GetAttackPathFindings), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetAuditLogs mocks base method.
 func (m *MockDatabase) GetAuditLogs(arg0, arg1 int) (model.AuditLogs, error) {
 	m.ctrl.T.Helper()
@@ -812,6 +1133,37 @@ func (mr *MockDatabaseMockRecorder) GetAzureDataQualityStats(arg0, arg1, arg2, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAzureDataQualityStats", reflect.TypeOf((*MockDatabase)(nil).GetAzureDataQualityStats), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// GetChokePoints mocks base method.
+func (m *MockDatabase) GetChokePoints(arg0 string, arg1, arg2 int) (model.ChokePoints, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChokePoints", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.ChokePoints)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChokePoints indicates an expected call of GetChokePoints.
+func (mr *MockDatabaseMockRecorder) GetChokePoints(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChokePoints", reflect.TypeOf((*MockDatabase)(nil).GetChokePoints), arg0, arg1, arg2)
+}
+
+// GetCollectionScopesForJob mocks base method.
+func (m *MockDatabase) GetCollectionScopesForJob(arg0 int64) (model.CollectionScopes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionScopesForJob", arg0)
+	ret0, _ := ret[0].(model.CollectionScopes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionScopesForJob indicates an expected call of GetCollectionScopesForJob.
+func (mr *MockDatabaseMockRecorder) GetCollectionScopesForJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionScopesForJob", reflect.TypeOf((*MockDatabase)(nil).GetCollectionScopesForJob), arg0)
+}
+
 // GetConfigurationParameter mocks base method.
 func (m *MockDatabase) GetConfigurationParameter(arg0 string) (appcfg.Parameter, error) {
 	m.ctrl.T.Helper()
@@ -842,6 +1194,21 @@ func (mr *MockDatabaseMockRecorder) GetConfigurationParametersByPrefix(arg0 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigurationParametersByPrefix", reflect.TypeOf((*MockDatabase)(nil).GetConfigurationParametersByPrefix), arg0)
 }
 
+// GetCustomKinds mocks base method.
+func (m *MockDatabase) GetCustomKinds() (model.CustomKinds, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomKinds")
+	ret0, _ := ret[0].(model.CustomKinds)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomKinds indicates an expected call of GetCustomKinds.
+func (mr *MockDatabaseMockRecorder) GetCustomKinds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomKinds", reflect.TypeOf((*MockDatabase)(nil).GetCustomKinds))
+}
+
 // GetFileUploadJob mocks base method.
 func (m *MockDatabase) GetFileUploadJob(arg0 int64) (model.FileUploadJob, error) {
 	m.ctrl.T.Helper()
@@ -902,6 +1269,51 @@ func (mr *MockDatabaseMockRecorder) GetFlagByKey(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlagByKey", reflect.TypeOf((*MockDatabase)(nil).GetFlagByKey), arg0)
 }
 
+// GetGraphSnapshot mocks base method.
+func (m *MockDatabase) GetGraphSnapshot(arg0 int32) (model.GraphSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGraphSnapshot", arg0)
+	ret0, _ := ret[0].(model.GraphSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGraphSnapshot indicates an expected call of GetGraphSnapshot.
+func (mr *MockDatabaseMockRecorder) GetGraphSnapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGraphSnapshot", reflect.TypeOf((*MockDatabase)(nil).GetGraphSnapshot), arg0)
+}
+
+// GetGraphSnapshots mocks base method.
+func (m *MockDatabase) GetGraphSnapshots() (model.GraphSnapshots, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGraphSnapshots")
+	ret0, _ := ret[0].(model.GraphSnapshots)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGraphSnapshots indicates an expected call of GetGraphSnapshots.
+func (mr *MockDatabaseMockRecorder) GetGraphSnapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGraphSnapshots", reflect.TypeOf((*MockDatabase)(nil).GetGraphSnapshots))
+}
+
+// GetIngestFileReportsForJob mocks base method.
+func (m *MockDatabase) GetIngestFileReportsForJob(arg0 int64) (model.IngestFileReports, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestFileReportsForJob", arg0)
+	ret0, _ := ret[0].(model.IngestFileReports)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestFileReportsForJob indicates an expected call of GetIngestFileReportsForJob.
+func (mr *MockDatabaseMockRecorder) GetIngestFileReportsForJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestFileReportsForJob", reflect.TypeOf((*MockDatabase)(nil).GetIngestFileReportsForJob), arg0)
+}
+
 // GetIngestTasksForJob mocks base method.
 func (m *MockDatabase) GetIngestTasksForJob(arg0 int64) (model.IngestTasks, error) {
 	m.ctrl.T.Helper()
@@ -947,6 +1359,36 @@ func (mr *MockDatabaseMockRecorder) GetLatestAssetGroupCollection(arg0 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestAssetGroupCollection", reflect.TypeOf((*MockDatabase)(nil).GetLatestAssetGroupCollection), arg0)
 }
 
+// GetOIDCProvider mocks base method.
+func (m *MockDatabase) GetOIDCProvider(arg0 int32) (model.OIDCProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOIDCProvider", arg0)
+	ret0, _ := ret[0].(model.OIDCProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOIDCProvider indicates an expected call of GetOIDCProvider.
+func (mr *MockDatabaseMockRecorder) GetOIDCProvider(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOIDCProvider", reflect.TypeOf((*MockDatabase)(nil).GetOIDCProvider), arg0)
+}
+
+// GetOIDCProviderUsers mocks base method.
+func (m *MockDatabase) GetOIDCProviderUsers(arg0 int32) (model.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOIDCProviderUsers", arg0)
+	ret0, _ := ret[0].(model.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOIDCProviderUsers indicates an expected call of GetOIDCProviderUsers.
+func (mr *MockDatabaseMockRecorder) GetOIDCProviderUsers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOIDCProviderUsers", reflect.TypeOf((*MockDatabase)(nil).GetOIDCProviderUsers), arg0)
+}
+
 // GetPermission mocks base method.
 func (m *MockDatabase) GetPermission(arg0 int) (model.Permission, error) {
 	m.ctrl.T.Helper()
@@ -962,6 +1404,36 @@ func (mr *MockDatabaseMockRecorder) GetPermission(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermission", reflect.TypeOf((*MockDatabase)(nil).GetPermission), arg0)
 }
 
+// GetPostProcessingRule mocks base method.
+func (m *MockDatabase) GetPostProcessingRule(arg0 int32) (model.PostProcessingRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostProcessingRule", arg0)
+	ret0, _ := ret[0].(model.PostProcessingRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostProcessingRule indicates an expected call of GetPostProcessingRule.
+func (mr *MockDatabaseMockRecorder) GetPostProcessingRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostProcessingRule", reflect.TypeOf((*MockDatabase)(nil).GetPostProcessingRule), arg0)
+}
+
+// GetRiskAcceptances mocks base method.
+func (m *MockDatabase) GetRiskAcceptances(arg0 string) (model.RiskAcceptances, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRiskAcceptances", arg0)
+	ret0, _ := ret[0].(model.RiskAcceptances)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRiskAcceptances indicates an expected call of GetRiskAcceptances.
+func (mr *MockDatabaseMockRecorder) GetRiskAcceptances(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRiskAcceptances", reflect.TypeOf((*MockDatabase)(nil).GetRiskAcceptances), arg0)
+}
+
 // GetRole mocks base method.
 func (m *MockDatabase) GetRole(arg0 int32) (model.Role, error) {
 	m.ctrl.T.Helper()
@@ -1037,6 +1509,36 @@ func (mr *MockDatabaseMockRecorder) GetSAMLProviderUsers(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSAMLProviderUsers", reflect.TypeOf((*MockDatabase)(nil).GetSAMLProviderUsers), arg0)
 }
 
+// GetSCIMGroup mocks base method.
+func (m *MockDatabase) GetSCIMGroup(arg0 int32) (model.SCIMGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMGroup", arg0)
+	ret0, _ := ret[0].(model.SCIMGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMGroup indicates an expected call of GetSCIMGroup.
+func (mr *MockDatabaseMockRecorder) GetSCIMGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMGroup", reflect.TypeOf((*MockDatabase)(nil).GetSCIMGroup), arg0)
+}
+
+// GetSCIMToken mocks base method.
+func (m *MockDatabase) GetSCIMToken(arg0 int32) (model.SCIMToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMToken", arg0)
+	ret0, _ := ret[0].(model.SCIMToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMToken indicates an expected call of GetSCIMToken.
+func (mr *MockDatabaseMockRecorder) GetSCIMToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMToken", reflect.TypeOf((*MockDatabase)(nil).GetSCIMToken), arg0)
+}
+
 // GetTimeRangedAssetGroupCollections mocks base method.
 func (m *MockDatabase) GetTimeRangedAssetGroupCollections(arg0 int32, arg1, arg2 int64, arg3 string) (model.AssetGroupCollections, error) {
 	m.ctrl.T.Helper()
@@ -1188,6 +1690,21 @@ func (mr *MockDatabaseMockRecorder) LookupActiveSessionsByUser(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupActiveSessionsByUser", reflect.TypeOf((*MockDatabase)(nil).LookupActiveSessionsByUser), arg0)
 }
 
+// LookupOIDCProviderByName mocks base method.
+func (m *MockDatabase) LookupOIDCProviderByName(arg0 string) (model.OIDCProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupOIDCProviderByName", arg0)
+	ret0, _ := ret[0].(model.OIDCProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupOIDCProviderByName indicates an expected call of LookupOIDCProviderByName.
+func (mr *MockDatabaseMockRecorder) LookupOIDCProviderByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupOIDCProviderByName", reflect.TypeOf((*MockDatabase)(nil).LookupOIDCProviderByName), arg0)
+}
+
 // LookupRoleByName mocks base method.
 func (m *MockDatabase) LookupRoleByName(arg0 string) (model.Role, error) {
 	m.ctrl.T.Helper()
@@ -1218,6 +1735,21 @@ func (mr *MockDatabaseMockRecorder) LookupSAMLProviderByName(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupSAMLProviderByName", reflect.TypeOf((*MockDatabase)(nil).LookupSAMLProviderByName), arg0)
 }
 
+// LookupSCIMToken mocks base method.
+func (m *MockDatabase) LookupSCIMToken(arg0 string) (model.SCIMToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupSCIMToken", arg0)
+	ret0, _ := ret[0].(model.SCIMToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupSCIMToken indicates an expected call of LookupSCIMToken.
+func (mr *MockDatabaseMockRecorder) LookupSCIMToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupSCIMToken", reflect.TypeOf((*MockDatabase)(nil).LookupSCIMToken), arg0)
+}
+
 // LookupUser mocks base method.
 func (m *MockDatabase) LookupUser(arg0 string) (model.User, error) {
 	m.ctrl.T.Helper()
@@ -1275,6 +1807,49 @@ func (mr *MockDatabaseMockRecorder) RemoveAssetGroupSelector(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAssetGroupSelector", reflect.TypeOf((*MockDatabase)(nil).RemoveAssetGroupSelector), arg0)
 }
 
+// ReplaceChokePoints mocks base method.
+func (m *MockDatabase) ReplaceChokePoints(arg0 model.ChokePoints) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceChokePoints", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceChokePoints indicates an expected call of ReplaceChokePoints.
+func (mr *MockDatabaseMockRecorder) ReplaceChokePoints(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceChokePoints", reflect.TypeOf((*MockDatabase)(nil).ReplaceChokePoints), arg0)
+}
+
+// SaveAttackPathFindings mocks base method.
+func (m *MockDatabase) SaveAttackPathFindings(arg0 string, arg1 model.AttackPathFindings, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttackPathFindings", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttackPathFindings indicates an expected call of SaveAttackPathFindings.
+func (mr *MockDatabaseMockRecorder) SaveAttackPathFindings(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttackPathFindings", reflect.TypeOf((*MockDatabase)(nil).SaveAttackPathFindings), arg0, arg1, arg2)
+}
+
+// SaveRiskAcceptance mocks base method.
+func (m *MockDatabase) SaveRiskAcceptance(arg0 model.RiskAcceptance) (model.RiskAcceptance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRiskAcceptance", arg0)
+	ret0, _ := ret[0].(model.RiskAcceptance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRiskAcceptance indicates an expected call of SaveRiskAcceptance.
+func (mr *MockDatabaseMockRecorder) SaveRiskAcceptance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRiskAcceptance", reflect.TypeOf((*MockDatabase)(nil).SaveRiskAcceptance), arg0)
+}
+
 // SetConfigurationParameter mocks base method.
 func (m *MockDatabase) SetConfigurationParameter(arg0 appcfg.Parameter) error {
 	m.ctrl.T.Helper()
@@ -1412,6 +1987,34 @@ func (mr *MockDatabaseMockRecorder) UpdateFileUploadJob(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileUploadJob", reflect.TypeOf((*MockDatabase)(nil).UpdateFileUploadJob), arg0)
 }
 
+// UpdateOIDCProvider mocks base method.
+func (m *MockDatabase) UpdateOIDCProvider(arg0 model.OIDCProvider) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOIDCProvider", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOIDCProvider indicates an expected call of UpdateOIDCProvider.
+func (mr *MockDatabaseMockRecorder) UpdateOIDCProvider(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOIDCProvider", reflect.TypeOf((*MockDatabase)(nil).UpdateOIDCProvider), arg0)
+}
+
+// UpdatePostProcessingRule mocks base method.
+func (m *MockDatabase) UpdatePostProcessingRule(arg0 model.PostProcessingRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostProcessingRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePostProcessingRule indicates an expected call of UpdatePostProcessingRule.
+func (mr *MockDatabaseMockRecorder) UpdatePostProcessingRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostProcessingRule", reflect.TypeOf((*MockDatabase)(nil).UpdatePostProcessingRule), arg0)
+}
+
 // UpdateRole mocks base method.
 func (m *MockDatabase) UpdateRole(arg0 model.Role) error {
 	m.ctrl.T.Helper()
@@ -1440,6 +2043,34 @@ func (mr *MockDatabaseMockRecorder) UpdateSAMLIdentityProvider(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSAMLIdentityProvider", reflect.TypeOf((*MockDatabase)(nil).UpdateSAMLIdentityProvider), arg0)
 }
 
+// UpdateSCIMGroup mocks base method.
+func (m *MockDatabase) UpdateSCIMGroup(arg0 model.SCIMGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSCIMGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSCIMGroup indicates an expected call of UpdateSCIMGroup.
+func (mr *MockDatabaseMockRecorder) UpdateSCIMGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSCIMGroup", reflect.TypeOf((*MockDatabase)(nil).UpdateSCIMGroup), arg0)
+}
+
+// UpdateSCIMToken mocks base method.
+func (m *MockDatabase) UpdateSCIMToken(arg0 model.SCIMToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSCIMToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSCIMToken indicates an expected call of UpdateSCIMToken.
+func (mr *MockDatabaseMockRecorder) UpdateSCIMToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSCIMToken", reflect.TypeOf((*MockDatabase)(nil).UpdateSCIMToken), arg0)
+}
+
 // UpdateUser mocks base method.
 func (m *MockDatabase) UpdateUser(arg0 model.User) error {
 	m.ctrl.T.Helper()
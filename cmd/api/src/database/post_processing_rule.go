@@ -0,0 +1,52 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"github.com/specterops/bloodhound/src/model"
+)
+
+func (s *BloodhoundDB) CreatePostProcessingRule(rule model.PostProcessingRule) (model.PostProcessingRule, error) {
+	result := s.db.Create(&rule)
+	return rule, CheckError(result)
+}
+
+func (s *BloodhoundDB) GetAllPostProcessingRules() (model.PostProcessingRules, error) {
+	var (
+		rules  model.PostProcessingRules
+		result = s.db.Order("id").Find(&rules)
+	)
+
+	return rules, CheckError(result)
+}
+
+func (s *BloodhoundDB) GetPostProcessingRule(id int32) (model.PostProcessingRule, error) {
+	var (
+		rule   model.PostProcessingRule
+		result = s.db.First(&rule, id)
+	)
+
+	return rule, CheckError(result)
+}
+
+func (s *BloodhoundDB) UpdatePostProcessingRule(rule model.PostProcessingRule) error {
+	return CheckError(s.db.Save(&rule))
+}
+
+func (s *BloodhoundDB) DeletePostProcessingRule(rule model.PostProcessingRule) error {
+	return CheckError(s.db.Delete(&rule))
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"github.com/specterops/bloodhound/src/model"
+	"gorm.io/gorm"
+)
+
+// CreateSCIMToken creates a new SCIMToken row
+// INSERT INTO scim_tokens (...) VALUES (...)
+func (s *BloodhoundDB) CreateSCIMToken(scimToken model.SCIMToken) (model.SCIMToken, error) {
+	var (
+		updatedSCIMToken = scimToken
+		result           = s.db.Create(&updatedSCIMToken)
+	)
+
+	return updatedSCIMToken, CheckError(result)
+}
+
+// GetAllSCIMTokens returns all SCIM tokens
+// SELECT * FROM scim_tokens
+func (s *BloodhoundDB) GetAllSCIMTokens() (model.SCIMTokens, error) {
+	var (
+		scimTokens model.SCIMTokens
+		result     = s.db.Order("id").Find(&scimTokens)
+	)
+
+	return scimTokens, CheckError(result)
+}
+
+// GetSCIMToken returns the SCIM token associated with the provided ID
+// SELECT * FROM scim_tokens WHERE id = ...
+func (s *BloodhoundDB) GetSCIMToken(id int32) (model.SCIMToken, error) {
+	var (
+		scimToken model.SCIMToken
+		result    = s.db.First(&scimToken, id)
+	)
+
+	return scimToken, CheckError(result)
+}
+
+// LookupSCIMToken returns the SCIM token associated with the provided token digest
+// SELECT * FROM scim_tokens WHERE digest = ...
+func (s *BloodhoundDB) LookupSCIMToken(digest string) (model.SCIMToken, error) {
+	var (
+		scimToken model.SCIMToken
+		result    = s.db.Where("digest = ?", digest).First(&scimToken)
+	)
+
+	return scimToken, CheckError(result)
+}
+
+// UpdateSCIMToken updates all fields in the SCIMToken row as specified in the provided struct
+// UPDATE scim_tokens SET name = ..., last_access = ... WHERE id = ...
+func (s *BloodhoundDB) UpdateSCIMToken(scimToken model.SCIMToken) error {
+	return CheckError(s.db.Save(&scimToken))
+}
+
+// DeleteSCIMToken deletes the provided SCIMToken row
+// DELETE FROM scim_tokens WHERE id = ...
+func (s *BloodhoundDB) DeleteSCIMToken(scimToken model.SCIMToken) error {
+	return CheckError(s.db.Delete(&scimToken))
+}
+
+// CreateSCIMGroup creates a new scim_groups row along with its members
+// INSERT INTO scim_groups (...) VALUES (...)
+func (s *BloodhoundDB) CreateSCIMGroup(scimGroup model.SCIMGroup) (model.SCIMGroup, error) {
+	var (
+		updatedSCIMGroup = scimGroup
+		result           = s.db.Create(&updatedSCIMGroup)
+	)
+
+	return updatedSCIMGroup, CheckError(result)
+}
+
+// GetAllSCIMGroups returns all SCIM groups along with their members
+// SELECT * FROM scim_groups
+func (s *BloodhoundDB) GetAllSCIMGroups() (model.SCIMGroups, error) {
+	var (
+		scimGroups model.SCIMGroups
+		result     = s.preload(model.SCIMGroupAssociations()).Order("id").Find(&scimGroups)
+	)
+
+	return scimGroups, CheckError(result)
+}
+
+// GetSCIMGroup returns the SCIM group associated with the provided ID along with its members
+// SELECT * FROM scim_groups WHERE id = ...
+func (s *BloodhoundDB) GetSCIMGroup(id int32) (model.SCIMGroup, error) {
+	var (
+		scimGroup model.SCIMGroup
+		result    = s.preload(model.SCIMGroupAssociations()).First(&scimGroup, id)
+	)
+
+	return scimGroup, CheckError(result)
+}
+
+// UpdateSCIMGroup updates a scim_groups row and replaces its members
+// UPDATE scim_groups SET (...) VALUES (...) WHERE id = ...
+func (s *BloodhoundDB) UpdateSCIMGroup(scimGroup model.SCIMGroup) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where("scim_group_id = ?", scimGroup.ID).Delete(&model.SCIMGroupMember{}); result.Error != nil {
+			return CheckError(result)
+		}
+
+		return CheckError(tx.Save(&scimGroup))
+	})
+}
+
+// DeleteSCIMGroup deletes the provided scim_groups row along with its members
+// DELETE FROM scim_groups WHERE id = ...
+func (s *BloodhoundDB) DeleteSCIMGroup(scimGroup model.SCIMGroup) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where("scim_group_id = ?", scimGroup.ID).Delete(&model.SCIMGroupMember{}); result.Error != nil {
+			return CheckError(result)
+		}
+
+		return CheckError(tx.Delete(&scimGroup))
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"github.com/specterops/bloodhound/src/model"
+)
+
+func (s *BloodhoundDB) CreateGraphSnapshot(snapshot model.GraphSnapshot) (model.GraphSnapshot, error) {
+	result := s.db.Create(&snapshot)
+	return snapshot, CheckError(result)
+}
+
+// GetGraphSnapshots returns all snapshots, newest first, without their captured content
+func (s *BloodhoundDB) GetGraphSnapshots() (model.GraphSnapshots, error) {
+	var (
+		snapshots model.GraphSnapshots
+		result    = s.db.Omit("content").Order("created_at desc").Find(&snapshots)
+	)
+
+	return snapshots, CheckError(result)
+}
+
+func (s *BloodhoundDB) GetGraphSnapshot(id int32) (model.GraphSnapshot, error) {
+	var (
+		snapshot model.GraphSnapshot
+		result   = s.db.First(&snapshot, id)
+	)
+
+	return snapshot, CheckError(result)
+}
+
+func (s *BloodhoundDB) DeleteGraphSnapshot(snapshot model.GraphSnapshot) error {
+	return CheckError(s.db.Delete(&snapshot))
+}
@@ -33,11 +33,15 @@ type Migration struct {
 }
 
 type GraphMigrator struct {
-	db graph.Database
+	db              graph.Database
+	customNodeKinds graph.Kinds
 }
 
-func NewGraphMigrator(db graph.Database) *GraphMigrator {
-	return &GraphMigrator{db: db}
+func NewGraphMigrator(db graph.Database, customNodeKinds ...graph.Kind) *GraphMigrator {
+	return &GraphMigrator{
+		db:              db,
+		customNodeKinds: customNodeKinds,
+	}
 }
 
 func (s *GraphMigrator) Migrate() error {
@@ -140,7 +144,7 @@ func (s *GraphMigrator) executeMigrations(target version.Version) error {
 }
 
 func (s *GraphMigrator) executeStepwiseMigrations() error {
-	if err := s.db.AssertSchema(context.Background(), CurrentSchema()); err != nil {
+	if err := s.db.AssertSchema(context.Background(), CurrentSchema(s.customNodeKinds...)); err != nil {
 		return fmt.Errorf("error asserting current schema: %w", err)
 	}
 
@@ -23,11 +23,14 @@ import (
 	"github.com/specterops/bloodhound/graphschema/common"
 )
 
-func CurrentSchema() *graph.Schema {
+// CurrentSchema returns the graph schema for the built-in kinds and the given custom node kinds registered by generic
+// ingest. Custom node kinds share the objectid constraint and the common property indices of the built-in kinds.
+func CurrentSchema(customNodeKinds ...graph.Kind) *graph.Schema {
 	bhSchema := graph.NewSchema()
 
 	bhSchema.DefineKinds(ad.NodeKinds()...)
 	bhSchema.DefineKinds(azure.NodeKinds()...)
+	bhSchema.DefineKinds(customNodeKinds...)
 
 	bhSchema.ConstrainProperty(common.ObjectID.String(), graph.FullTextSearchIndex)
 
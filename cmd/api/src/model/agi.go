@@ -19,13 +19,38 @@ package model
 import (
 	"fmt"
 
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/slices"
 	"github.com/specterops/bloodhound/src/database/types"
 )
 
+// Types of asset group selectors. Selectors other than object ID selectors are dynamic and are evaluated against the
+// graph on every analysis run.
+const (
+	AssetGroupSelectorTypeObjectID          = "object_id"
+	AssetGroupSelectorTypeCypher            = "cypher"
+	AssetGroupSelectorTypeDistinguishedName = "distinguished_name"
+	AssetGroupSelectorTypeGroupMembers      = "group_members"
+	AssetGroupSelectorTypeProperty          = "property"
+)
+
+// AssetGroupSelectorTypes returns every supported type of asset group selector
+func AssetGroupSelectorTypes() []string {
+	return []string{
+		AssetGroupSelectorTypeObjectID,
+		AssetGroupSelectorTypeCypher,
+		AssetGroupSelectorTypeDistinguishedName,
+		AssetGroupSelectorTypeGroupMembers,
+		AssetGroupSelectorTypeProperty,
+	}
+}
+
+// AssetGroupSelector selects the members of an asset group. Selectors without a type are object ID selectors.
 type AssetGroupSelector struct {
 	AssetGroupID   int32  `json:"asset_group_id"`
 	Name           string `json:"name"`
 	Selector       string `json:"selector"`
+	SelectorType   string `json:"selector_type"`
 	SystemSelector bool   `json:"system_selector"`
 
 	Serial
@@ -33,9 +58,24 @@ type AssetGroupSelector struct {
 
 func (s AssetGroupSelector) AuditData() AuditData {
 	return AuditData{
-		"name":     s.Name,
-		"selector": s.Selector,
+		"name":          s.Name,
+		"selector":      s.Selector,
+		"selector_type": s.Type(),
+	}
+}
+
+// Type returns the type of the selector
+func (s AssetGroupSelector) Type() string {
+	if s.SelectorType == "" {
+		return AssetGroupSelectorTypeObjectID
 	}
+
+	return s.SelectorType
+}
+
+// IsDynamic returns true if the members selected by the selector are resolved from the graph
+func (s AssetGroupSelector) IsDynamic() bool {
+	return s.Type() != AssetGroupSelectorTypeObjectID
 }
 
 type AssetGroupSelectors []AssetGroupSelector
@@ -50,6 +90,32 @@ func (s AssetGroupSelectors) Strings() []string {
 	return selectorStrings
 }
 
+// ObjectIDs returns the object IDs selected by the object ID selectors
+func (s AssetGroupSelectors) ObjectIDs() []string {
+	objectIDs := make([]string, 0, len(s))
+
+	for _, selector := range s {
+		if !selector.IsDynamic() {
+			objectIDs = append(objectIDs, selector.Selector)
+		}
+	}
+
+	return objectIDs
+}
+
+// Dynamic returns the selectors that are resolved from the graph
+func (s AssetGroupSelectors) Dynamic() AssetGroupSelectors {
+	var dynamic AssetGroupSelectors
+
+	for _, selector := range s {
+		if selector.IsDynamic() {
+			dynamic = append(dynamic, selector)
+		}
+	}
+
+	return dynamic
+}
+
 // AssetGroupAssociations returns a list of AssetGroup model associations to load eagerly by default with GORM
 // Preload(...). Note: this does not include the "Collections" association on-purpose since this collection grows
 // over time and may require additional parameters for fetching.
@@ -212,9 +278,13 @@ type AssetGroupCollectionEntry struct {
 
 type AssetGroupCollectionEntries []AssetGroupCollectionEntry
 
+// AssetGroupSelectorSpec describes a change to the selectors of an asset group. Object ID selectors select the node
+// identified by EntityObjectID while dynamic selectors are described by Selector.
 type AssetGroupSelectorSpec struct {
 	SelectorName   string `json:"selector_name"`
 	EntityObjectID string `json:"sid"`
+	SelectorType   string `json:"selector_type"`
+	Selector       string `json:"selector"`
 	Action         string `json:"action"`
 }
 
@@ -223,7 +293,47 @@ const (
 	SelectorSpecActionRemove = "remove"
 )
 
+var (
+	ErrSelectorNameRequired = errors.Error("a selector name is required")
+	ErrActionInvalid        = errors.Error("the selector spec action must be add or remove")
+	ErrSelectorTypeInvalid  = errors.Error("invalid selector type")
+	ErrSelectorRequired     = errors.Error("a selector is required")
+)
+
+// AssetGroupSelector returns the selector described by the spec
+func (s AssetGroupSelectorSpec) AssetGroupSelector(assetGroupID int32, systemSelector bool) AssetGroupSelector {
+	selector := AssetGroupSelector{
+		AssetGroupID:   assetGroupID,
+		Name:           s.SelectorName,
+		Selector:       s.EntityObjectID,
+		SelectorType:   s.SelectorType,
+		SystemSelector: systemSelector,
+	}
+
+	if selector.IsDynamic() {
+		selector.Selector = s.Selector
+	}
+
+	return selector
+}
+
+// Validate checks that the spec describes a selector of a supported type. Selectors are only referenced by name when
+// they are removed.
 func (s AssetGroupSelectorSpec) Validate() error {
+	selector := s.AssetGroupSelector(0, false)
+
+	if s.SelectorName == "" {
+		return ErrSelectorNameRequired
+	} else if s.Action == SelectorSpecActionRemove {
+		return nil
+	} else if s.Action != SelectorSpecActionAdd {
+		return ErrActionInvalid
+	} else if !slices.Contains(AssetGroupSelectorTypes(), selector.Type()) {
+		return ErrSelectorTypeInvalid
+	} else if selector.Selector == "" {
+		return ErrSelectorRequired
+	}
+
 	return nil
 }
 
@@ -231,5 +341,7 @@ func (s AssetGroupSelectorSpec) AuditData() AuditData {
 	return AuditData{
 		"selector_name":             s.SelectorName,
 		"selector_entity_object_id": s.EntityObjectID,
+		"selector_type":             s.SelectorType,
+		"selector":                  s.Selector,
 	}
 }
@@ -18,30 +18,78 @@ package model
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAssetGroupSelectorSpec_Validate(t *testing.T) {
-	//happyPath := AssetGroupSelectorSpec{
-	//	SelectorName:   "test",
-	//	EntityObjectID: "S-1-5-21-570004220-2248230615-4072641716-544",
-	//	Action:         SelectorSpecActionAdd,
-	//}
-	//
-	//assert.Nil(t, happyPath.Validate(), "Expected valid AG selector spec to validate")
-	//
-	//bustedEntityObjectID := AssetGroupSelectorSpec{
-	//	SelectorName:   "troll",
-	//	EntityObjectID: "I CONTROL YOU",
-	//	Action:         SelectorSpecActionRemove,
-	//}
-	//
-	//assert.Equalf(t, bustedEntityObjectID.Validate(), ErrEntityObjectIDInvalid, "Expected bad object ID to fail validation")
-	//
-	//bustedAction := AssetGroupSelectorSpec{
-	//	SelectorName:   "dayman",
-	//	EntityObjectID: "S-1-5-21-570004220-2248230615-4072641716-544",
-	//	Action:         "foobar",
-	//}
-	//
-	//assert.Equalf(t, bustedAction.Validate(), ErrActionInvalid, "Expected bad node label to fail validation")
+	happyPath := AssetGroupSelectorSpec{
+		SelectorName:   "test",
+		EntityObjectID: "S-1-5-21-570004220-2248230615-4072641716-544",
+		Action:         SelectorSpecActionAdd,
+	}
+
+	assert.Nil(t, happyPath.Validate(), "Expected valid AG selector spec to validate")
+
+	dynamicSelector := AssetGroupSelectorSpec{
+		SelectorName: "tier one servers",
+		SelectorType: AssetGroupSelectorTypeDistinguishedName,
+		Selector:     "OU=TIER1 SERVERS,DC=CONTOSO,DC=LOCAL",
+		Action:       SelectorSpecActionAdd,
+	}
+
+	assert.Nil(t, dynamicSelector.Validate(), "Expected valid dynamic AG selector spec to validate")
+	assert.Equal(t, AssetGroupSelector{
+		AssetGroupID: 1,
+		Name:         "tier one servers",
+		Selector:     "OU=TIER1 SERVERS,DC=CONTOSO,DC=LOCAL",
+		SelectorType: AssetGroupSelectorTypeDistinguishedName,
+	}, dynamicSelector.AssetGroupSelector(1, false))
+
+	removal := AssetGroupSelectorSpec{
+		SelectorName: "troll",
+		Action:       SelectorSpecActionRemove,
+	}
+
+	assert.Nil(t, removal.Validate(), "Expected removal by name to validate")
+
+	bustedSelector := AssetGroupSelectorSpec{
+		SelectorName: "troll",
+		SelectorType: AssetGroupSelectorTypeCypher,
+		Action:       SelectorSpecActionAdd,
+	}
+
+	assert.Equalf(t, ErrSelectorRequired, bustedSelector.Validate(), "Expected missing selector to fail validation")
+
+	bustedSelectorType := AssetGroupSelectorSpec{
+		SelectorName: "troll",
+		SelectorType: "I CONTROL YOU",
+		Selector:     "I CONTROL YOU",
+		Action:       SelectorSpecActionAdd,
+	}
+
+	assert.Equalf(t, ErrSelectorTypeInvalid, bustedSelectorType.Validate(), "Expected bad selector type to fail validation")
+
+	bustedAction := AssetGroupSelectorSpec{
+		SelectorName:   "dayman",
+		EntityObjectID: "S-1-5-21-570004220-2248230615-4072641716-544",
+		Action:         "foobar",
+	}
+
+	assert.Equalf(t, ErrActionInvalid, bustedAction.Validate(), "Expected bad action to fail validation")
+}
+
+func TestAssetGroupSelectors_ObjectIDs(t *testing.T) {
+	selectors := AssetGroupSelectors{{
+		Selector: "S-1-5-21-1-512",
+	}, {
+		Selector:     "S-1-5-21-1-1104",
+		SelectorType: AssetGroupSelectorTypeObjectID,
+	}, {
+		Selector:     "admincount=true",
+		SelectorType: AssetGroupSelectorTypeProperty,
+	}}
+
+	assert.Equal(t, []string{"S-1-5-21-1-512", "S-1-5-21-1-1104"}, selectors.ObjectIDs())
+	assert.Equal(t, AssetGroupSelectors{selectors[2]}, selectors.Dynamic())
 }
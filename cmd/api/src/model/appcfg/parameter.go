@@ -35,6 +35,14 @@ const (
 	Neo4jConfigsName                    = "Neo4j Configuration Parameters"
 	PasswordExpirationWindowDescription = "This configuration parameter sets the local auth password expiry window for users that have valid auth secrets. Values for this configuration must follow the duration specification of ISO-8601."
 	Neo4jConfigsDescription             = "This configuration parameter sets the BatchWriteSize and the BatchFlushSize for Neo4J."
+	StaleObjectRetirement               = "ingest.stale_object_retirement"
+	StaleObjectRetirementName           = "Stale Object Retirement"
+	StaleObjectRetirementDescription    = "This configuration parameter sets how objects that were not observed again by the latest collection of their domain or tenant are retired. Valid modes are disabled, mark and delete."
+	SessionValidity                     = "ingest.session_validity"
+	SessionValidityName                 = "Session Validity"
+	SessionValidityDescription          = "This configuration parameter sets how long a session remains valid after it was last observed and how long expired sessions are retained for historical queries. Values for this configuration must follow the duration specification of ISO-8601."
+	DefaultSessionValidityDuration      = "P3D"
+	DefaultSessionValidityRetention     = "P30D"
 )
 
 // Parameter is a runtime configuration parameter that can be fetched from the appcfg.ParameterService interface. The
@@ -98,6 +106,15 @@ func AvailableParameters() (ParameterSet, error) {
 		WriteFlushSize: neo4j.DefaultWriteFlushSize,
 	}); err != nil {
 		return ParameterSet{}, fmt.Errorf("error creating neo4jExpirationValue parameter: %w", err)
+	} else if staleObjectRetirementValue, err := types.NewJSONBObject(StaleObjectRetirementParameters{
+		Mode: DefaultStaleObjectRetirementMode,
+	}); err != nil {
+		return ParameterSet{}, fmt.Errorf("error creating StaleObjectRetirement parameter: %w", err)
+	} else if sessionValidityValue, err := types.NewJSONBObject(SessionValidityParameters{
+		Duration:  DefaultSessionValidityDuration,
+		Retention: DefaultSessionValidityRetention,
+	}); err != nil {
+		return ParameterSet{}, fmt.Errorf("error creating SessionValidity parameter: %w", err)
 	} else {
 		return ParameterSet{
 			PasswordExpirationWindow: {
@@ -113,6 +130,18 @@ func AvailableParameters() (ParameterSet, error) {
 				Description: Neo4jConfigsDescription,
 				Value:       neo4jExpirationValue,
 			},
+			StaleObjectRetirement: {
+				Key:         StaleObjectRetirement,
+				Name:        StaleObjectRetirementName,
+				Description: StaleObjectRetirementDescription,
+				Value:       staleObjectRetirementValue,
+			},
+			SessionValidity: {
+				Key:         SessionValidity,
+				Name:        SessionValidityName,
+				Description: SessionValidityDescription,
+				Value:       sessionValidityValue,
+			},
 		}, nil
 	}
 }
@@ -165,3 +194,94 @@ func GetNeo4jParameters(service ParameterService) Neo4jParameters {
 
 	return result
 }
+
+type StaleObjectRetirementMode string
+
+const (
+	// StaleObjectRetirementDisabled leaves objects that were not observed again untouched
+	StaleObjectRetirementDisabled StaleObjectRetirementMode = "disabled"
+
+	// StaleObjectRetirementMark sets the stale property on objects that were not observed again and removes it once
+	// they are observed again
+	StaleObjectRetirementMark StaleObjectRetirementMode = "mark"
+
+	// StaleObjectRetirementDelete deletes objects that were not observed again
+	StaleObjectRetirementDelete StaleObjectRetirementMode = "delete"
+
+	DefaultStaleObjectRetirementMode = StaleObjectRetirementDisabled
+)
+
+func (s StaleObjectRetirementMode) IsValid() bool {
+	switch s {
+	case StaleObjectRetirementDisabled, StaleObjectRetirementMark, StaleObjectRetirementDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+type StaleObjectRetirementParameters struct {
+	Mode StaleObjectRetirementMode `json:"mode"`
+}
+
+func GetStaleObjectRetirementMode(service ParameterService) StaleObjectRetirementMode {
+	var result StaleObjectRetirementParameters
+
+	if staleObjectRetirementCfg, err := service.GetConfigurationParameter(StaleObjectRetirement); err != nil {
+		log.Errorf("Failed to fetch stale object retirement configuration; returning default mode %s", DefaultStaleObjectRetirementMode)
+		return DefaultStaleObjectRetirementMode
+	} else if err := staleObjectRetirementCfg.Map(&result); err != nil || !result.Mode.IsValid() {
+		log.Errorf("Invalid stale object retirement configuration supplied; returning default mode %s", DefaultStaleObjectRetirementMode)
+		return DefaultStaleObjectRetirementMode
+	}
+
+	return result.Mode
+}
+
+type SessionValidityParameters struct {
+	Duration  string `json:"duration"`
+	Retention string `json:"retention"`
+}
+
+// SessionValidityWindow bounds the validity of session relationships. A session is valid for Duration after it was last
+// observed and is retained for Retention after it expired.
+type SessionValidityWindow struct {
+	Duration  time.Duration
+	Retention time.Duration
+}
+
+func (s SessionValidityParameters) Parse() (SessionValidityWindow, error) {
+	if duration, err := iso8601.FromString(s.Duration); err != nil {
+		return SessionValidityWindow{}, err
+	} else if retention, err := iso8601.FromString(s.Retention); err != nil {
+		return SessionValidityWindow{}, err
+	} else {
+		return SessionValidityWindow{
+			Duration:  duration.ToDuration(),
+			Retention: retention.ToDuration(),
+		}, nil
+	}
+}
+
+func GetSessionValidity(service ParameterService) SessionValidityWindow {
+	var (
+		result           SessionValidityParameters
+		defaultWindow, _ = SessionValidityParameters{
+			Duration:  DefaultSessionValidityDuration,
+			Retention: DefaultSessionValidityRetention,
+		}.Parse()
+	)
+
+	if sessionValidityCfg, err := service.GetConfigurationParameter(SessionValidity); err != nil {
+		log.Errorf("Failed to fetch session validity configuration; returning default values")
+		return defaultWindow
+	} else if err := sessionValidityCfg.Map(&result); err != nil {
+		log.Errorf("Invalid session validity configuration supplied; returning default values")
+		return defaultWindow
+	} else if window, err := result.Parse(); err != nil {
+		log.Errorf("Invalid session validity configuration supplied; returning default values")
+		return defaultWindow
+	} else {
+		return window
+	}
+}
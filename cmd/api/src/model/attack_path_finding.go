@@ -0,0 +1,90 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"time"
+
+	"github.com/specterops/bloodhound/src/database/types/null"
+)
+
+// AttackPathFinding is a principal of a domain that is affected by a type of attack path finding. Findings that are no
+// longer detected are kept as resolved so that their remediation can be tracked over time.
+type AttackPathFinding struct {
+	EnvironmentID string    `json:"environment_id" gorm:"index"`
+	FindingType   string    `json:"finding_type"`
+	ObjectID      string    `json:"object_id"`
+	Name          string    `json:"name"`
+	Kind          string    `json:"kind"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+	Resolved      bool      `json:"resolved"`
+
+	Serial
+}
+
+type AttackPathFindings []AttackPathFinding
+
+// AttackPathFindingSummary describes the state of one type of attack path finding in a domain
+type AttackPathFindingSummary struct {
+	FindingType   string    `json:"finding_type"`
+	Description   string    `json:"description"`
+	Principals    int       `json:"principals"`
+	Resolved      int       `json:"resolved"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+	Accepted      bool      `json:"accepted"`
+	AcceptedUntil null.Time `json:"accepted_until"`
+}
+
+type AttackPathFindingSummaries []AttackPathFindingSummary
+
+// RiskAcceptance records that the risk of a type of attack path finding in a domain was accepted. The risk is accepted
+// indefinitely unless AcceptedUntil is set, in which case the finding is snoozed until then.
+type RiskAcceptance struct {
+	EnvironmentID string    `json:"environment_id" gorm:"index"`
+	FindingType   string    `json:"finding_type"`
+	AcceptedUntil null.Time `json:"accepted_until"`
+
+	Serial
+}
+
+// IsAccepted returns true if the risk is accepted at the given time
+func (s RiskAcceptance) IsAccepted(now time.Time) bool {
+	return !s.AcceptedUntil.Valid || s.AcceptedUntil.Time.After(now)
+}
+
+func (s RiskAcceptance) AuditData() AuditData {
+	return AuditData{
+		"environment_id": s.EnvironmentID,
+		"finding_type":   s.FindingType,
+		"accepted_until": s.AcceptedUntil,
+	}
+}
+
+type RiskAcceptances []RiskAcceptance
+
+// Get returns the acceptance of the given finding type
+func (s RiskAcceptances) Get(findingType string) (RiskAcceptance, bool) {
+	for _, acceptance := range s {
+		if acceptance.FindingType == findingType {
+			return acceptance, true
+		}
+	}
+
+	return RiskAcceptance{}, false
+}
@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/specterops/bloodhound/slices"
 	"github.com/specterops/bloodhound/src/database/types/null"
 	"github.com/specterops/bloodhound/src/serde"
 	"github.com/gofrs/uuid"
@@ -144,6 +145,16 @@ func (s Permissions) Has(other Permission) bool {
 	return false
 }
 
+func (s Permissions) FindByID(id int32) (Permission, bool) {
+	for _, permission := range s {
+		if permission.ID == id {
+			return permission, true
+		}
+	}
+
+	return Permission{}, false
+}
+
 type AuthToken struct {
 	UserID     uuid.NullUUID `json:"user_id" gorm:"type:text"`
 	ClientID   uuid.NullUUID `json:"-"  gorm:"type:text"`
@@ -279,6 +290,15 @@ type SAMLProvider struct {
 	// For example: ["http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress", "urn:oid:0.9.2342.19200300.100.1.3"]
 	PrincipalAttributeMappings []string `json:"principal_attribute_mappings" gorm:"type:text[];column:ous"`
 
+	// RoleAttribute is the OID or XML Namespace name of the assertion attribute that lists the groups of the
+	// authenticated identity. When role mappings are defined, the roles of a user are synchronized with the mapped roles
+	// of the attribute on every login.
+	RoleAttribute string           `json:"role_attribute"`
+	RoleMappings  SAMLRoleMappings `json:"role_mappings" gorm:"foreignKey:SAMLProviderID;constraint:OnDelete:CASCADE"`
+
+	// AutoProvision enables just-in-time creation of users that authenticate with the provider for the first time
+	AutoProvision bool `json:"auto_provision"`
+
 	// The below values generated values that point a client to SAML related resources hosted on the BloodHound instance
 	// and should not be persisted to the database due to the fact that the URLs rely on the Host header that the user is
 	// using to communicate to the API
@@ -296,15 +316,190 @@ func (s SAMLProvider) AuditData() AuditData {
 		"saml_name":                    s.Name,
 		"principal_attribute_mappings": s.PrincipalAttributeMappings,
 		"idp_url":                      s.IssuerURI,
+		"role_attribute":               s.RoleAttribute,
+		"auto_provision":               s.AutoProvision,
 	}
 }
 
+// HasUser returns true if the user is bound to the provider
+func (s SAMLProvider) HasUser(user User) bool {
+	return user.SAMLProviderID.Valid && user.SAMLProviderID.Int32 == s.ID
+}
+
+// BindUser binds the user to the provider
+func (s SAMLProvider) BindUser(user *User) {
+	user.SAMLProviderID = null.Int32From(s.ID)
+	user.OIDCProviderID = null.Int32{}
+}
+
+// MapsRoles returns true if the roles of the users of the provider are managed by its role mappings
+func (s SAMLProvider) MapsRoles() bool {
+	return s.RoleAttribute != "" && len(s.RoleMappings) > 0
+}
+
+// MappedRoleIDs returns the IDs of the roles mapped to any of the given role attribute values
+func (s SAMLProvider) MappedRoleIDs(attributeValues []string) []int32 {
+	return s.RoleMappings.RoleIDs(attributeValues)
+}
+
+// ProvisionsUsers returns true if unknown users that authenticate with the provider are created on first login
+func (s SAMLProvider) ProvisionsUsers() bool {
+	return s.AutoProvision && s.MapsRoles()
+}
+
 type SAMLProviders []SAMLProvider
 
+func SAMLProviderAssociations() []string {
+	return []string{
+		"RoleMappings",
+	}
+}
+
+// SAMLRoleMapping grants the role to users of a SAML provider whose assertion role attribute contains the attribute
+// value
+type SAMLRoleMapping struct {
+	SAMLProviderID int32  `json:"-" gorm:"primaryKey"`
+	AttributeValue string `json:"attribute_value" gorm:"primaryKey"`
+	RoleID         int32  `json:"role_id" gorm:"primaryKey"`
+}
+
+type SAMLRoleMappings []SAMLRoleMapping
+
+// RoleIDs returns the IDs of the roles mapped to any of the given attribute values
+func (s SAMLRoleMappings) RoleIDs(attributeValues []string) []int32 {
+	var roleIDs []int32
+
+	for _, mapping := range s {
+		if slices.Contains(attributeValues, mapping.AttributeValue) && !slices.Contains(roleIDs, mapping.RoleID) {
+			roleIDs = append(roleIDs, mapping.RoleID)
+		}
+	}
+
+	return roleIDs
+}
+
+// OIDCRoleMapping grants the role to users of an OIDC provider whose ID token role claim contains the claim value
+type OIDCRoleMapping struct {
+	OIDCProviderID int32  `json:"-" gorm:"primaryKey"`
+	ClaimValue     string `json:"claim_value" gorm:"primaryKey"`
+	RoleID         int32  `json:"role_id" gorm:"primaryKey"`
+}
+
+type OIDCRoleMappings []OIDCRoleMapping
+
+// RoleIDs returns the IDs of the roles mapped to any of the given claim values
+func (s OIDCRoleMappings) RoleIDs(claimValues []string) []int32 {
+	var roleIDs []int32
+
+	for _, mapping := range s {
+		if slices.Contains(claimValues, mapping.ClaimValue) && !slices.Contains(roleIDs, mapping.RoleID) {
+			roleIDs = append(roleIDs, mapping.RoleID)
+		}
+	}
+
+	return roleIDs
+}
+
+type OIDCProvider struct {
+	Name         string `json:"name" gorm:"unique;index"`
+	DisplayName  string `json:"display_name"`
+	IssuerURI    string `json:"issuer_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"-"`
+
+	// Scopes is the space separated list of scopes requested during authorization. The openid scope is always requested.
+	Scopes string `json:"scopes"`
+
+	// PrincipalClaim is the ID token claim that is used to map an authenticated identity to a user in the database. The
+	// email claim is used when unset. The email claim is only trusted when the ID token asserts that it was verified.
+	PrincipalClaim string `json:"principal_claim"`
+
+	// RoleClaim is the ID token claim that lists the groups or roles of the authenticated identity. When role mappings
+	// are defined, the roles of a user are synchronized with the mapped roles of the claim on every login.
+	RoleClaim    string           `json:"role_claim"`
+	RoleMappings OIDCRoleMappings `json:"role_mappings" gorm:"foreignKey:OIDCProviderID;constraint:OnDelete:CASCADE"`
+
+	// AutoProvision enables just-in-time creation of users that authenticate with the provider for the first time
+	AutoProvision bool `json:"auto_provision"`
+
+	// The below values are generated values that point a client to OIDC related resources hosted on the BloodHound
+	// instance and are not persisted for the same reasons as the SAML service provider URLs
+	LoginURI    serde.URL `json:"login_uri" gorm:"-"`
+	CallbackURI serde.URL `json:"callback_uri" gorm:"-"`
+
+	Serial
+}
+
+func (s OIDCProvider) AuditData() AuditData {
+	return AuditData{
+		"oidc_id":         s.ID,
+		"oidc_name":       s.Name,
+		"issuer_uri":      s.IssuerURI,
+		"client_id":       s.ClientID,
+		"principal_claim": s.PrincipalClaim,
+		"role_claim":      s.RoleClaim,
+		"auto_provision":  s.AutoProvision,
+	}
+}
+
+// HasUser returns true if the user is bound to the provider
+func (s OIDCProvider) HasUser(user User) bool {
+	return user.OIDCProviderID.Valid && user.OIDCProviderID.Int32 == s.ID
+}
+
+// BindUser binds the user to the provider
+func (s OIDCProvider) BindUser(user *User) {
+	user.OIDCProviderID = null.Int32From(s.ID)
+	user.SAMLProviderID = null.Int32{}
+}
+
+// MapsRoles returns true if the roles of the users of the provider are managed by its role mappings
+func (s OIDCProvider) MapsRoles() bool {
+	return s.RoleClaim != "" && len(s.RoleMappings) > 0
+}
+
+// MappedRoleIDs returns the IDs of the roles mapped to any of the given role claim values
+func (s OIDCProvider) MappedRoleIDs(claimValues []string) []int32 {
+	return s.RoleMappings.RoleIDs(claimValues)
+}
+
+// ProvisionsUsers returns true if unknown users that authenticate with the provider are created on first login
+func (s OIDCProvider) ProvisionsUsers() bool {
+	return s.AutoProvision && s.MapsRoles()
+}
+
+type OIDCProviders []OIDCProvider
+
+func OIDCProviderAssociations() []string {
+	return []string{
+		"RoleMappings",
+	}
+}
+
 func RoleAssociations() []string {
 	return []string{
 		"Permissions",
+		"Environments",
+	}
+}
+
+// RoleEnvironment grants the users of a role visibility of the graph data of an environment, identified by its domain
+// SID or tenant ID
+type RoleEnvironment struct {
+	RoleID        int32  `json:"-" gorm:"primaryKey"`
+	EnvironmentID string `json:"environment_id" gorm:"primaryKey"`
+}
+
+type RoleEnvironments []RoleEnvironment
+
+func (s RoleEnvironments) IDs() []string {
+	ids := make([]string, len(s))
+
+	for idx, environment := range s {
+		ids[idx] = environment.EnvironmentID
 	}
+
+	return ids
 }
 
 type Role struct {
@@ -312,16 +507,36 @@ type Role struct {
 	Description string      `json:"description"`
 	Permissions Permissions `json:"permissions" gorm:"many2many:roles_permissions"`
 
+	// Environments limits the graph data visible to the users of the role. The users of a role without environments
+	// can see the graph data of every environment.
+	Environments RoleEnvironments `json:"environments" gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"`
+
+	// Custom is set for roles that were defined through the API rather than built from a role template
+	Custom bool `json:"custom"`
+
 	Serial
 }
 
 func (s Role) AuditData() AuditData {
 	return AuditData{
-		"role_id":   s.ID,
-		"role_name": s.Name,
+		"role_id":      s.ID,
+		"role_name":    s.Name,
+		"environments": s.Environments.IDs(),
 	}
 }
 
+// EnvironmentScope describes the environments, identified by domain SID or tenant ID, whose graph data is visible. A
+// scope that is not restricted allows every environment.
+type EnvironmentScope struct {
+	Restricted     bool
+	EnvironmentIDs []string
+}
+
+// Allows returns true if the graph data of the given environment is visible
+func (s EnvironmentScope) Allows(environmentID string) bool {
+	return !s.Restricted || slices.Contains(s.EnvironmentIDs, environmentID)
+}
+
 type Roles []Role
 
 func (s Roles) IsSortable(column string) bool {
@@ -341,6 +556,7 @@ func (s Roles) IsSortable(column string) bool {
 func (s Roles) ValidFilters() map[string][]FilterOperator {
 	return map[string][]FilterOperator{
 		"name":       {Equals, NotEquals},
+		"custom":     {Equals, NotEquals},
 		"id":         {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
 		"created_at": {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
 		"updated_at": {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
@@ -402,6 +618,21 @@ func (s Roles) Has(other Role) bool {
 	return false
 }
 
+// Equals returns true if both sets contain the same roles, regardless of their order
+func (s Roles) Equals(other Roles) bool {
+	if len(s) != len(other) {
+		return false
+	}
+
+	for _, role := range other {
+		if !s.Has(role) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (s Roles) RemoveByName(name string) Roles {
 	for idx, role := range s {
 		if role.Name == name {
@@ -422,6 +653,36 @@ func (s Roles) FindByName(name string) (Role, bool) {
 	return Role{}, false
 }
 
+// BuiltIn returns the roles that were built from a role template
+func (s Roles) BuiltIn() Roles {
+	var builtIn Roles
+
+	for _, role := range s {
+		if !role.Custom {
+			builtIn = append(builtIn, role)
+		}
+	}
+
+	return builtIn
+}
+
+// EnvironmentScope returns the environments whose graph data is visible to a user with the given roles. A role without
+// environments makes every environment visible.
+func (s Roles) EnvironmentScope() EnvironmentScope {
+	var scope EnvironmentScope
+
+	for _, role := range s {
+		if len(role.Environments) == 0 {
+			return EnvironmentScope{}
+		}
+
+		scope.Restricted = true
+		scope.EnvironmentIDs = append(scope.EnvironmentIDs, role.Environments.IDs()...)
+	}
+
+	return scope
+}
+
 func (s Roles) FindByPermissions(permissions Permissions) (Role, bool) {
 	for _, role := range s {
 		if role.Permissions.Equals(permissions) {
@@ -435,15 +696,19 @@ func (s Roles) FindByPermissions(permissions Permissions) (Role, bool) {
 func UserAssociations() []string {
 	return []string{
 		"SAMLProvider",
+		"OIDCProvider",
 		"AuthSecret",
 		"AuthTokens",
 		"Roles.Permissions",
+		"Roles.Environments",
 	}
 }
 
 type User struct {
 	SAMLProviderID null.Int32    `json:"saml_provider_id,omitempty"`
 	SAMLProvider   *SAMLProvider `json:"-" `
+	OIDCProviderID null.Int32    `json:"oidc_provider_id,omitempty"`
+	OIDCProvider   *OIDCProvider `json:"-"`
 	AuthSecret     *AuthSecret   `gorm:"constraint:OnDelete:CASCADE;"`
 	AuthTokens     AuthTokens    `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
 	Roles          Roles         `json:"roles" gorm:"many2many:users_roles"`
@@ -471,6 +736,10 @@ func (s User) AuditData() AuditData {
 		data["saml_provider_id"] = s.SAMLProviderID
 	}
 
+	if s.OIDCProviderID.Valid {
+		data["oidc_provider_id"] = s.OIDCProviderID
+	}
+
 	return data
 }
 
@@ -545,9 +814,11 @@ func (s Users) GetValidFilterPredicatesAsStrings(column string) ([]string, error
 func UserSessionAssociations() []string {
 	return []string{
 		"User.SAMLProvider",
+		"User.OIDCProvider",
 		"User.AuthSecret",
 		"User.AuthTokens",
 		"User.Roles.Permissions",
+		"User.Roles.Environments",
 	}
 }
 
@@ -556,6 +827,7 @@ type SessionAuthProvider int
 const (
 	SessionAuthProviderSecret SessionAuthProvider = 0
 	SessionAuthProviderSAML   SessionAuthProvider = 1
+	SessionAuthProviderOIDC   SessionAuthProvider = 2
 )
 
 type UserSession struct {
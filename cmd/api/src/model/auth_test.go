@@ -0,0 +1,64 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles_EnvironmentScope(t *testing.T) {
+	var (
+		emea = Role{
+			Name:         "EMEA Analysts",
+			Environments: RoleEnvironments{{EnvironmentID: "S-1-5-21-1"}},
+			Custom:       true,
+		}
+		apac = Role{
+			Name:         "APAC Analysts",
+			Environments: RoleEnvironments{{EnvironmentID: "TENANT-1"}},
+			Custom:       true,
+		}
+		user = Role{Name: "User"}
+	)
+
+	scope := Roles{emea, apac}.EnvironmentScope()
+	assert.True(t, scope.Restricted)
+	assert.Equal(t, []string{"S-1-5-21-1", "TENANT-1"}, scope.EnvironmentIDs)
+	assert.True(t, scope.Allows("S-1-5-21-1"))
+	assert.True(t, scope.Allows("TENANT-1"))
+	assert.False(t, scope.Allows("S-1-5-21-2"))
+
+	scope = Roles{emea, user}.EnvironmentScope()
+	assert.False(t, scope.Restricted)
+	assert.True(t, scope.Allows("S-1-5-21-2"))
+
+	assert.Equal(t, Roles{user}, Roles{emea, user, apac}.BuiltIn())
+}
+
+func TestOIDCRoleMappings_RoleIDs(t *testing.T) {
+	mappings := OIDCRoleMappings{
+		{ClaimValue: "sec-bh-admins", RoleID: 1},
+		{ClaimValue: "sec-bh-analysts", RoleID: 3},
+		{ClaimValue: "sec-bh-leads", RoleID: 1},
+	}
+
+	assert.Equal(t, []int32{1, 3}, mappings.RoleIDs([]string{"sec-bh-admins", "sec-bh-analysts", "sec-bh-leads"}))
+	assert.Equal(t, []int32{3}, mappings.RoleIDs([]string{"users", "sec-bh-analysts"}))
+	assert.Empty(t, mappings.RoleIDs(nil))
+}
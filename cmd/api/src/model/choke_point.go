@@ -0,0 +1,45 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+const (
+	ChokePointFindingTypeNode         = "node"
+	ChokePointFindingTypeRelationship = "relationship"
+)
+
+// ChokePoint is a node or relationship whose removal cuts every path into the tier zero assets of a domain or tenant for
+// AffectedPrincipals principals. Choke points are ranked per environment starting at 1 with the most impactful finding.
+// Relationship findings describe the relationship from the node identified by ObjectID to the node identified by
+// TargetObjectID.
+type ChokePoint struct {
+	EnvironmentID      string `json:"environment_id" gorm:"index"`
+	EnvironmentType    string `json:"environment_type"`
+	Rank               int    `json:"rank"`
+	FindingType        string `json:"finding_type"`
+	ObjectID           string `json:"object_id"`
+	Name               string `json:"name"`
+	Kind               string `json:"kind"`
+	RelationshipKind   string `json:"relationship_kind,omitempty"`
+	TargetObjectID     string `json:"target_object_id,omitempty"`
+	TargetName         string `json:"target_name,omitempty"`
+	TargetKind         string `json:"target_kind,omitempty"`
+	AffectedPrincipals int64  `json:"affected_principals"`
+
+	Serial
+}
+
+type ChokePoints []ChokePoint
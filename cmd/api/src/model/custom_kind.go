@@ -0,0 +1,54 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import "github.com/specterops/bloodhound/dawgs/graph"
+
+// CustomKind is a node or relationship kind introduced by generic ingest that is not part of the built-in graph
+// schema. Custom node kinds are added to the graph schema so that their indices survive schema assertion.
+type CustomKind struct {
+	Name         string `json:"name" gorm:"uniqueIndex:idx_custom_kinds_name_relationship"`
+	Relationship bool   `json:"relationship" gorm:"uniqueIndex:idx_custom_kinds_name_relationship"`
+
+	Serial
+}
+
+type CustomKinds []CustomKind
+
+// NodeKinds returns the custom kinds that are node kinds
+func (s CustomKinds) NodeKinds() graph.Kinds {
+	var kinds graph.Kinds
+
+	for _, customKind := range s {
+		if !customKind.Relationship {
+			kinds = append(kinds, graph.StringKind(customKind.Name))
+		}
+	}
+
+	return kinds
+}
+
+// Has returns true if the given kind has already been registered
+func (s CustomKinds) Has(name string, relationship bool) bool {
+	for _, customKind := range s {
+		if customKind.Name == name && customKind.Relationship == relationship {
+			return true
+		}
+	}
+
+	return false
+}
@@ -26,10 +26,25 @@ const (
 	DatapipeStatusAnalyzing DatapipeStatus = "analyzing"
 )
 
+// PostProcessingStageStatus describes a registered post-processing stage along with the outcome of its last run.
+// LastRunAt is zero if the stage has not run since the datapipe started.
+type PostProcessingStageStatus struct {
+	Name                 string           `json:"name"`
+	Produces             []string         `json:"produces"`
+	DependsOn            []string         `json:"depends_on"`
+	Idempotent           bool             `json:"idempotent"`
+	LastRunAt            time.Time        `json:"last_run_at"`
+	DurationMS           int64            `json:"duration_ms"`
+	RelationshipsCreated map[string]int32 `json:"relationships_created"`
+	RelationshipsDeleted map[string]int32 `json:"relationships_deleted"`
+	Error                string           `json:"error,omitempty"`
+}
+
 type DatapipeStatusWrapper struct {
-	Status                 DatapipeStatus `json:"status"`
-	UpdatedAt              time.Time      `json:"updated_at"`
-	LastCompleteAnalysisAt time.Time      `json:"last_complete_analysis_at"`
+	Status                 DatapipeStatus              `json:"status"`
+	UpdatedAt              time.Time                   `json:"updated_at"`
+	LastCompleteAnalysisAt time.Time                   `json:"last_complete_analysis_at"`
+	PostProcessingStages   []PostProcessingStageStatus `json:"post_processing_stages"`
 }
 
 func (s *DatapipeStatusWrapper) Update(status DatapipeStatus, updateAnalysisTime bool) {
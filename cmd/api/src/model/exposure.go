@@ -0,0 +1,31 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+// ExposureRecord is a point-in-time snapshot of the exposure of a principal. InboundExposure is the number of
+// principals in the same domain or tenant that can reach the principal and OutboundExposure is the number of objects in
+// the same domain or tenant that the principal can reach.
+type ExposureRecord struct {
+	ObjectID         string `json:"object_id" gorm:"index"`
+	EnvironmentID    string `json:"environment_id"`
+	InboundExposure  int64  `json:"inbound_exposure"`
+	OutboundExposure int64  `json:"outbound_exposure"`
+
+	Serial
+}
+
+type ExposureRecords []ExposureRecord
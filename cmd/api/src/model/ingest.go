@@ -18,16 +18,27 @@ package model
 
 import (
 	"os"
+	"time"
 
+	"github.com/specterops/bloodhound/src/database/types"
 	"github.com/specterops/bloodhound/src/database/types/null"
 	"gorm.io/gorm"
 	"github.com/specterops/bloodhound/log"
 )
 
+type FileType int
+
+const (
+	FileTypeJson FileType = iota
+	FileTypeZip
+)
+
 type IngestTask struct {
-	FileName    string     `json:"file_name"`
-	RequestGUID string     `json:"request_guid"`
-	TaskID      null.Int64 `json:"task_id"`
+	FileName         string     `json:"file_name"`
+	RequestGUID      string     `json:"request_guid"`
+	TaskID           null.Int64 `json:"task_id"`
+	FileType         FileType   `json:"file_type"`
+	OriginalFileName string     `json:"original_file_name"`
 
 	BigSerial
 }
@@ -41,3 +52,55 @@ func (s *IngestTask) AfterDelete(tx *gorm.DB) (err error) {
 
 	return nil
 }
+
+// IngestValidationError describes a single validation failure within an ingest file. Path is a JSON pointer to the
+// offending value.
+type IngestValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// IngestFileReport records the outcome of validating and ingesting a single file of a file upload job. Zip uploads
+// produce one report per archive entry.
+type IngestFileReport struct {
+	FileUploadJobID int64                   `json:"file_upload_job_id"`
+	FileName        string                  `json:"file_name"`
+	DataType        string                  `json:"data_type"`
+	Version         int                     `json:"version"`
+	Accepted        bool                    `json:"accepted"`
+	ObjectCounts    types.JSONUntypedObject `json:"object_counts"`
+	ErrorCount      int                     `json:"error_count"`
+	Errors          types.JSONBObject       `json:"errors"`
+
+	BigSerial
+}
+
+type IngestFileReports []IngestFileReport
+
+// NumRejected returns the number of files that were rejected during ingest
+func (s IngestFileReports) NumRejected() int {
+	numRejected := 0
+
+	for _, report := range s {
+		if !report.Accepted {
+			numRejected++
+		}
+	}
+
+	return numRejected
+}
+
+// CollectionScope records that a file of a file upload job was a collection of one data type within a domain or a
+// tenant. The scope ID is the domain SID or tenant ID. Objects in the scope that were last seen before the file was
+// ingested were not observed again by the collection and may be retired once the job completes.
+type CollectionScope struct {
+	FileUploadJobID   int64     `json:"file_upload_job_id" gorm:"index"`
+	ScopeID           string    `json:"scope_id"`
+	DataType          string    `json:"data_type"`
+	CollectionMethods int64     `json:"collection_methods"`
+	ObservedAt        time.Time `json:"observed_at"`
+
+	BigSerial
+}
+
+type CollectionScopes []CollectionScope
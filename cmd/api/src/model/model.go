@@ -79,9 +79,11 @@ type LegacyADOU struct {
 }
 
 type PagedNodeListEntry struct {
-	ObjectID string `json:"objectID"`
-	Name     string `json:"name"`
-	Label    string `json:"label"`
+	ObjectID         string `json:"objectID"`
+	Name             string `json:"name"`
+	Label            string `json:"label"`
+	InboundExposure  *int64 `json:"inboundExposure,omitempty"`
+	OutboundExposure *int64 `json:"outboundExposure,omitempty"`
 }
 
 type DataType int
@@ -0,0 +1,67 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/common"
+)
+
+// NodeSetPath is a single path of a node set pathfinding result. Source and Target are the object IDs of the first and
+// last nodes of the path. Nodes lists the graph IDs of each node in the path in traversal order.
+type NodeSetPath struct {
+	Source string        `json:"source"`
+	Target string        `json:"target"`
+	Nodes  []string      `json:"nodes"`
+	Edges  []UnifiedEdge `json:"edges"`
+}
+
+// NodeSetPathsResult contains the ranked paths of a node set pathfinding result along with every node referenced by
+// them
+type NodeSetPathsResult struct {
+	Nodes map[string]UnifiedNode `json:"nodes"`
+	Paths []NodeSetPath          `json:"paths"`
+}
+
+func NewNodeSetPathsResult(paths graph.PathSet) NodeSetPathsResult {
+	result := NodeSetPathsResult{
+		Nodes: map[string]UnifiedNode{},
+		Paths: make([]NodeSetPath, 0, len(paths)),
+	}
+
+	for _, path := range paths {
+		nodeSetPath := NodeSetPath{
+			Source: getTypedPropertyOrDefault(path.Root().Properties, common.ObjectID.String(), ""),
+			Target: getTypedPropertyOrDefault(path.Terminal().Properties, common.ObjectID.String(), ""),
+			Nodes:  make([]string, 0, len(path.Nodes)),
+			Edges:  make([]UnifiedEdge, 0, len(path.Edges)),
+		}
+
+		for _, node := range path.Nodes {
+			result.Nodes[node.ID.String()] = FromDAWGSNode(node)
+			nodeSetPath.Nodes = append(nodeSetPath.Nodes, node.ID.String())
+		}
+
+		for _, edge := range path.Edges {
+			nodeSetPath.Edges = append(nodeSetPath.Edges, FromDAWGSRelationship(edge))
+		}
+
+		result.Paths = append(result.Paths, nodeSetPath)
+	}
+
+	return result
+}
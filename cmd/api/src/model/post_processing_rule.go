@@ -0,0 +1,59 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+// PostProcessingRule is an administrator defined post-processing step. Every pair of source and target nodes bound by
+// the rule's match pattern is joined by a relationship of the rule's edge kind after the built-in post-processing
+// stages have run.
+type PostProcessingRule struct {
+	Name          string `json:"name" gorm:"unique"`
+	Description   string `json:"description"`
+	Query         string `json:"query"`
+	EdgeKind      string `json:"edge_kind"`
+	SourceBinding string `json:"source_binding"`
+	TargetBinding string `json:"target_binding"`
+	Enabled       bool   `json:"enabled"`
+
+	Serial
+}
+
+func (s PostProcessingRule) AuditData() AuditData {
+	return AuditData{
+		"rule_id":        s.ID,
+		"rule_name":      s.Name,
+		"query":          s.Query,
+		"edge_kind":      s.EdgeKind,
+		"source_binding": s.SourceBinding,
+		"target_binding": s.TargetBinding,
+		"enabled":        s.Enabled,
+	}
+}
+
+type PostProcessingRules []PostProcessingRule
+
+// Enabled returns the rules that are enabled
+func (s PostProcessingRules) Enabled() PostProcessingRules {
+	var enabled PostProcessingRules
+
+	for _, rule := range s {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+		}
+	}
+
+	return enabled
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/specterops/bloodhound/slices"
+	"github.com/specterops/bloodhound/src/database/types/null"
+)
+
+// SCIMToken authenticates an identity governance platform against the SCIM provisioning API. Requests authenticated
+// with the token act on behalf of the user that created it. Only the digest of the token is stored; the token itself
+// is returned once when it is created.
+type SCIMToken struct {
+	UserID uuid.UUID `json:"user_id" gorm:"type:text"`
+	Name   string    `json:"name"`
+	Digest string    `json:"-" gorm:"unique;index"`
+
+	// Users created through the token are bound to the SSO provider set below, if any, so that they are able to log in
+	SAMLProviderID null.Int32 `json:"saml_provider_id"`
+	OIDCProviderID null.Int32 `json:"oidc_provider_id"`
+
+	LastAccess time.Time `json:"last_access"`
+
+	Serial
+}
+
+func (s SCIMToken) AuditData() AuditData {
+	return AuditData{
+		"scim_token_id":   s.ID,
+		"scim_token_name": s.Name,
+	}
+}
+
+// BindUser binds the user to the SSO provider of the token, if any
+func (s SCIMToken) BindUser(user *User) {
+	if s.SAMLProviderID.Valid {
+		user.SAMLProviderID = s.SAMLProviderID
+		user.OIDCProviderID = null.Int32{}
+	} else if s.OIDCProviderID.Valid {
+		user.OIDCProviderID = s.OIDCProviderID
+		user.SAMLProviderID = null.Int32{}
+	}
+}
+
+type SCIMTokens []SCIMToken
+
+// SCIMGroupMember is the membership of a user in a SCIM group
+type SCIMGroupMember struct {
+	SCIMGroupID int32     `json:"-" gorm:"primaryKey"`
+	UserID      uuid.UUID `json:"user_id" gorm:"primaryKey"`
+}
+
+type SCIMGroupMembers []SCIMGroupMember
+
+// UserIDs returns the IDs of the member users
+func (s SCIMGroupMembers) UserIDs() []uuid.UUID {
+	userIDs := make([]uuid.UUID, len(s))
+
+	for idx, member := range s {
+		userIDs[idx] = member.UserID
+	}
+
+	return userIDs
+}
+
+// Difference returns the IDs of the member users that are not a member of the other members
+func (s SCIMGroupMembers) Difference(other SCIMGroupMembers) []uuid.UUID {
+	var userIDs []uuid.UUID
+
+	for _, member := range s {
+		if !other.Has(member.UserID) {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	return userIDs
+}
+
+// Has returns true if the user is a member
+func (s SCIMGroupMembers) Has(userID uuid.UUID) bool {
+	for _, member := range s {
+		if member.UserID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SCIMGroup is a group pushed by an identity governance platform through the SCIM provisioning API. Members of a group
+// that is mapped to a role are granted the role.
+type SCIMGroup struct {
+	DisplayName string           `json:"display_name" gorm:"unique;index"`
+	ExternalID  string           `json:"external_id"`
+	RoleID      null.Int32       `json:"role_id"`
+	Members     SCIMGroupMembers `json:"members" gorm:"foreignKey:SCIMGroupID;constraint:OnDelete:CASCADE"`
+
+	Serial
+}
+
+func (s SCIMGroup) AuditData() AuditData {
+	return AuditData{
+		"scim_group_id":           s.ID,
+		"scim_group_display_name": s.DisplayName,
+		"role_id":                 s.RoleID,
+	}
+}
+
+type SCIMGroups []SCIMGroup
+
+func SCIMGroupAssociations() []string {
+	return []string{
+		"Members",
+	}
+}
+
+// MemberOf returns the groups that the user is a member of
+func (s SCIMGroups) MemberOf(userID uuid.UUID) SCIMGroups {
+	var groups SCIMGroups
+
+	for _, group := range s {
+		if group.Members.Has(userID) {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// RoleIDs returns the IDs of the roles mapped to the groups that the user is a member of
+func (s SCIMGroups) RoleIDs(userID uuid.UUID) []int32 {
+	var roleIDs []int32
+
+	for _, group := range s.MemberOf(userID) {
+		if group.RoleID.Valid && !slices.Contains(roleIDs, group.RoleID.Int32) {
+			roleIDs = append(roleIDs, group.RoleID.Int32)
+		}
+	}
+
+	return roleIDs
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/specterops/bloodhound/src/database/types/null"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSCIMGroups_RoleIDs(t *testing.T) {
+	var (
+		alice = uuid.Must(uuid.NewV4())
+		bob   = uuid.Must(uuid.NewV4())
+		carol = uuid.Must(uuid.NewV4())
+
+		groups = SCIMGroups{
+			{DisplayName: "BloodHound Admins", RoleID: null.Int32From(1), Members: SCIMGroupMembers{{UserID: alice}}},
+			{DisplayName: "BloodHound Users", RoleID: null.Int32From(3), Members: SCIMGroupMembers{{UserID: alice}, {UserID: bob}}},
+			{DisplayName: "Tier Zero Owners", RoleID: null.Int32From(1), Members: SCIMGroupMembers{{UserID: alice}, {UserID: carol}}},
+			{DisplayName: "Unmapped", Members: SCIMGroupMembers{{UserID: carol}}},
+		}
+	)
+
+	assert.Equal(t, []int32{1, 3}, groups.RoleIDs(alice))
+	assert.Equal(t, []int32{3}, groups.RoleIDs(bob))
+	assert.Equal(t, []int32{1}, groups.RoleIDs(carol))
+	assert.Len(t, groups.MemberOf(carol), 2)
+	assert.Empty(t, groups.RoleIDs(uuid.Must(uuid.NewV4())))
+}
+
+func TestSCIMToken_BindUser(t *testing.T) {
+	user := User{SAMLProviderID: null.Int32From(4)}
+
+	SCIMToken{}.BindUser(&user)
+	assert.Equal(t, null.Int32From(4), user.SAMLProviderID)
+
+	SCIMToken{OIDCProviderID: null.Int32From(2)}.BindUser(&user)
+	assert.False(t, user.SAMLProviderID.Valid)
+	assert.Equal(t, null.Int32From(2), user.OIDCProviderID)
+}
+
+func TestRoles_Equals(t *testing.T) {
+	var (
+		admin = Role{Name: "Administrator"}
+		user  = Role{Name: "User"}
+	)
+
+	assert.True(t, Roles{admin, user}.Equals(Roles{user, admin}))
+	assert.True(t, Roles{}.Equals(nil))
+	assert.False(t, Roles{admin}.Equals(Roles{user}))
+	assert.False(t, Roles{admin, user}.Equals(Roles{admin}))
+}
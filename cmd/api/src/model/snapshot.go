@@ -0,0 +1,112 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"time"
+
+	"github.com/specterops/bloodhound/src/database/types"
+)
+
+const (
+	GraphSnapshotEnvironmentActiveDirectory = "active-directory"
+	GraphSnapshotEnvironmentAzure           = "azure"
+)
+
+// GraphSnapshot is a named, point-in-time capture of the identity keys and kinds of the nodes and relationships of a
+// single domain or tenant. The captured graph is kept in Content and is only loaded when a snapshot is fetched by ID.
+type GraphSnapshot struct {
+	Name              string            `json:"name"`
+	EnvironmentType   string            `json:"environment_type"`
+	EnvironmentID     string            `json:"environment_id" gorm:"index"`
+	NodeCount         int               `json:"node_count"`
+	RelationshipCount int               `json:"relationship_count"`
+	AnalyzedAt        time.Time         `json:"analyzed_at"`
+	Content           types.JSONBObject `json:"-"`
+
+	Serial
+}
+
+func (s GraphSnapshot) AuditData() AuditData {
+	return AuditData{
+		"snapshot_id":      s.ID,
+		"snapshot_name":    s.Name,
+		"environment_type": s.EnvironmentType,
+		"environment_id":   s.EnvironmentID,
+	}
+}
+
+type GraphSnapshots []GraphSnapshot
+
+// InEnvironmentScope returns the snapshots of the environments that are visible in the given scope
+func (s GraphSnapshots) InEnvironmentScope(scope EnvironmentScope) GraphSnapshots {
+	if !scope.Restricted {
+		return s
+	}
+
+	scopedSnapshots := GraphSnapshots{}
+
+	for _, graphSnapshot := range s {
+		if scope.Allows(graphSnapshot.EnvironmentID) {
+			scopedSnapshots = append(scopedSnapshots, graphSnapshot)
+		}
+	}
+
+	return scopedSnapshots
+}
+
+// GraphSnapshotNode is the identity of a node at the time of a snapshot
+type GraphSnapshotNode struct {
+	ObjectID       string   `json:"objectid"`
+	Name           string   `json:"name,omitempty"`
+	Kinds          []string `json:"kinds"`
+	TierZero       bool     `json:"tier_zero,omitempty"`
+	AttackPathRoot bool     `json:"attack_path_root,omitempty"`
+}
+
+// GraphSnapshotRelationship is the identity of a relationship at the time of a snapshot. Start and end nodes are
+// referenced by object ID so that relationships can be compared across recreated graphs.
+type GraphSnapshotRelationship struct {
+	Kind          string `json:"kind"`
+	StartObjectID string `json:"start_objectid"`
+	EndObjectID   string `json:"end_objectid"`
+}
+
+// Key returns the identity key of the relationship
+func (s GraphSnapshotRelationship) Key() string {
+	return s.StartObjectID + "-[" + s.Kind + "]->" + s.EndObjectID
+}
+
+type GraphSnapshotContent struct {
+	Nodes         []GraphSnapshotNode         `json:"nodes"`
+	Relationships []GraphSnapshotRelationship `json:"relationships"`
+}
+
+type GraphSnapshotChanges[T any] struct {
+	Added   []T `json:"added"`
+	Removed []T `json:"removed"`
+}
+
+// GraphSnapshotDiff lists what changed between two snapshots
+type GraphSnapshotDiff struct {
+	From            GraphSnapshot                                   `json:"from"`
+	To              GraphSnapshot                                   `json:"to"`
+	Principals      GraphSnapshotChanges[GraphSnapshotNode]         `json:"principals"`
+	Relationships   GraphSnapshotChanges[GraphSnapshotRelationship] `json:"relationships"`
+	TierZero        GraphSnapshotChanges[GraphSnapshotNode]         `json:"tier_zero"`
+	AttackPathRoots GraphSnapshotChanges[GraphSnapshotNode]         `json:"attack_path_roots"`
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import "github.com/specterops/bloodhound/dawgs/traversal"
+
+// PathCostModel assigns a traversal cost to relationships by kind. Relationships that end at a tier zero node are
+// costed by TierZeroKindCosts first, then by KindCosts. Any relationship kind not otherwise costed is assigned the
+// DefaultCost.
+type PathCostModel struct {
+	DefaultCost       float64            `json:"default_cost"`
+	KindCosts         map[string]float64 `json:"kind_costs"`
+	TierZeroKindCosts map[string]float64 `json:"tier_zero_kind_costs"`
+}
+
+// WeightedPath is a single path of a weighted pathfinding result. Nodes lists the graph IDs of each node in the path
+// in traversal order.
+type WeightedPath struct {
+	Cost  float64       `json:"cost"`
+	Nodes []string      `json:"nodes"`
+	Edges []UnifiedEdge `json:"edges"`
+}
+
+// WeightedPathsResult contains the paths of a weighted pathfinding result ordered by ascending cost along with every
+// node referenced by them
+type WeightedPathsResult struct {
+	Nodes map[string]UnifiedNode `json:"nodes"`
+	Paths []WeightedPath         `json:"paths"`
+}
+
+func NewWeightedPathsResult(weightedPaths []traversal.WeightedPath) WeightedPathsResult {
+	result := WeightedPathsResult{
+		Nodes: map[string]UnifiedNode{},
+		Paths: make([]WeightedPath, 0, len(weightedPaths)),
+	}
+
+	for _, weightedPath := range weightedPaths {
+		path := WeightedPath{
+			Cost:  weightedPath.Cost,
+			Nodes: make([]string, 0, len(weightedPath.Path.Nodes)),
+			Edges: make([]UnifiedEdge, 0, len(weightedPath.Path.Edges)),
+		}
+
+		for _, node := range weightedPath.Path.Nodes {
+			result.Nodes[node.ID.String()] = FromDAWGSNode(node)
+			path.Nodes = append(path.Nodes, node.ID.String())
+		}
+
+		for _, edge := range weightedPath.Path.Edges {
+			path.Edges = append(path.Edges, FromDAWGSRelationship(edge))
+		}
+
+		result.Paths = append(result.Paths, path)
+	}
+
+	return result
+}
@@ -33,19 +33,23 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/analysis/exposure"
 	"github.com/specterops/bloodhound/cache"
 	"github.com/specterops/bloodhound/cypher/analyzer"
 	"github.com/specterops/bloodhound/cypher/frontend"
 	"github.com/specterops/bloodhound/dawgs/graph"
 	"github.com/specterops/bloodhound/dawgs/ops"
 	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/traversal"
 	"github.com/specterops/bloodhound/errors"
 	"github.com/specterops/bloodhound/graphschema/ad"
 	"github.com/specterops/bloodhound/graphschema/azure"
 	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/headers"
 	"github.com/specterops/bloodhound/log"
 	"github.com/specterops/bloodhound/src/api"
 	"github.com/specterops/bloodhound/src/api/bloodhoundgraph"
+	"github.com/specterops/bloodhound/src/api/graphexport"
 	"github.com/specterops/bloodhound/src/model"
 	"github.com/specterops/bloodhound/src/utils"
 )
@@ -73,6 +77,17 @@ type EntityQueryParameters struct {
 	Limit         int
 	PathDelegate  any
 	ListDelegate  any
+
+	// OrderCriteria is the order of list results requested with the sort_by query parameter. List results are ordered
+	// by descending node ID when no order is requested.
+	OrderCriteria model.OrderCriteria
+
+	// ExportFormat is set when a graph result is to be exported in a standard graph interchange format
+	ExportFormat graphexport.Format
+
+	// AsOf restricts results to the relationships that were valid at the given time when set with the as_of query
+	// parameter
+	AsOf time.Time
 }
 
 func GetEntityObjectIDFromRequestPath(request *http.Request) (string, error) {
@@ -97,6 +112,54 @@ func GetRequestedType(params url.Values) model.DataType {
 	}
 }
 
+// IsSortableEntityColumn returns true if entity list results can be sorted by the given column
+func IsSortableEntityColumn(column string) bool {
+	switch column {
+	case common.ObjectID.String(),
+		common.Name.String(),
+		common.InboundExposure.String(),
+		common.OutboundExposure.String():
+		return true
+	default:
+		return false
+	}
+}
+
+// GetEntityOrderCriteria parses the sort_by query parameters of an entity list request. Columns prefixed with a
+// hyphen are sorted in descending order.
+func GetEntityOrderCriteria(params url.Values) (model.OrderCriteria, error) {
+	var orderCriteria model.OrderCriteria
+
+	for _, column := range params["sort_by"] {
+		criterion := model.OrderCriterion{
+			Order: query.Ascending(),
+		}
+
+		if strings.HasPrefix(column, "-") {
+			column = column[1:]
+			criterion.Order = query.Descending()
+		}
+
+		if !IsSortableEntityColumn(column) {
+			return nil, errors.New(model.ErrorResponseDetailsColumnNotSortable)
+		}
+
+		criterion.Property = column
+		orderCriteria = append(orderCriteria, criterion)
+	}
+
+	return orderCriteria, nil
+}
+
+// GetAsOf parses the as_of query parameter. A zero time is returned if the parameter is not set.
+func GetAsOf(params url.Values) (time.Time, error) {
+	if rawAsOf := params.Get(api.QueryParameterAsOf); rawAsOf == "" {
+		return time.Time{}, nil
+	} else {
+		return time.Parse(time.RFC3339Nano, rawAsOf)
+	}
+}
+
 func BuildEntityQueryParams(request *http.Request, queryName string, pathDelegate any, listDelegate any) (EntityQueryParameters, error) {
 	var (
 		requestQueryParams = request.URL.Query()
@@ -107,11 +170,20 @@ func BuildEntityQueryParams(request *http.Request, queryName string, pathDelegat
 		return EntityQueryParameters{}, fmt.Errorf("error getting objectid: %w", err)
 	} else if skip, limit, _, err := utils.GetPageParamsForGraphQuery(request.Context(), requestQueryParams); err != nil {
 		return EntityQueryParameters{}, fmt.Errorf("error getting paging parameters: %w", err)
+	} else if orderCriteria, err := GetEntityOrderCriteria(requestQueryParams); err != nil {
+		return EntityQueryParameters{}, fmt.Errorf("error getting sort parameters: %w", err)
+	} else if asOf, err := GetAsOf(requestQueryParams); err != nil {
+		return EntityQueryParameters{}, fmt.Errorf("error getting as_of parameter: %w", err)
 	} else {
+		var exportFormat graphexport.Format
+
 		if dataType == model.DataTypeCount {
 			skip = 0
 			limit = 0
+		} else if dataType == model.DataTypeGraph {
+			exportFormat, _ = graphexport.NegotiateFormat(request.Header.Get(headers.Accept.String()))
 		}
+
 		return EntityQueryParameters{
 			QueryName:     queryName,
 			ObjectID:      objectId,
@@ -120,6 +192,9 @@ func BuildEntityQueryParams(request *http.Request, queryName string, pathDelegat
 			Limit:         limit,
 			PathDelegate:  pathDelegate,
 			ListDelegate:  listDelegate,
+			OrderCriteria: orderCriteria,
+			ExportFormat:  exportFormat,
+			AsOf:          asOf,
 		}, nil
 	}
 }
@@ -128,17 +203,20 @@ type Graph interface {
 	GetAssetGroupComboNode(ctx context.Context, owningObjectID string, assetGroupTag string) (map[string]any, error)
 	GetAssetGroupNodes(ctx context.Context, assetGroupTag string) (graph.NodeSet, error)
 	GetAllShortestPaths(ctx context.Context, startNodeID string, endNodeID string, filter graph.Criteria) (graph.PathSet, error)
+	GetWeightedShortestPaths(ctx context.Context, startNodeID string, endNodeID string, filter graph.Criteria, costModel model.PathCostModel, limit int) ([]traversal.WeightedPath, error)
+	GetShortestPathsBetweenNodeSets(ctx context.Context, sources graph.NodeSet, targets graph.NodeSet, filter graph.Criteria, maxDepth int, limit int) (graph.PathSet, error)
 	SearchNodesByName(ctx context.Context, nodeKinds graph.Kinds, nameQuery string, skip int, limit int) ([]model.SearchResult, error)
 	SearchByNameOrObjectID(ctx context.Context, searchValue string, searchType string) (graph.NodeSet, error)
 	GetADEntityQueryResult(ctx context.Context, params EntityQueryParameters, cacheEnabled bool) (any, error)
 	GetEntityByObjectId(ctx context.Context, objectID string, kinds ...graph.Kind) (*graph.Node, error)
 	GetEntityCountResults(ctx context.Context, node *graph.Node, delegates map[string]any) map[string]any
 	GetNodesByKind(ctx context.Context, kinds ...graph.Kind) (graph.NodeSet, error)
-	GetFilteredAndSortedNodes(orderCriteria model.OrderCriteria, filterCriteria graph.Criteria) (graph.NodeSet, error)
+	GetFilteredAndSortedNodes(ctx context.Context, orderCriteria model.OrderCriteria, filterCriteria graph.Criteria) (graph.NodeSet, error)
 	FetchNodesByObjectIDs(ctx context.Context, objectIDs ...string) (graph.NodeSet, error)
 	ValidateOUs(ctx context.Context, ous []string) ([]string, error)
 	BatchNodeUpdate(ctx context.Context, nodeUpdate graph.NodeUpdate) error
 	RawCypherSearch(ctx context.Context, rawCypher string) (model.UnifiedGraph, error)
+	RawCypherPathSearch(ctx context.Context, rawCypher string) (graph.PathSet, error)
 }
 
 type GraphQuery struct {
@@ -162,7 +240,10 @@ func NewGraphQuery(graphDB graph.Database, cache cache.Cache, slowQueryThreshold
 }
 
 func (s *GraphQuery) GetAssetGroupComboNode(ctx context.Context, owningObjectID string, assetGroupTag string) (map[string]any, error) {
-	var graphData = map[string]any{}
+	var (
+		graphData = map[string]any{}
+		scope     = requestEnvironmentScope(ctx)
+	)
 
 	return graphData, s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		if assetGroupNodes, err := ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
@@ -178,7 +259,7 @@ func (s *GraphQuery) GetAssetGroupComboNode(ctx context.Context, owningObjectID
 				))
 			}
 
-			return query.And(filters...)
+			return withScopeCriteria(scope, query.And(filters...))
 		})); err != nil {
 			return err
 		} else {
@@ -186,6 +267,7 @@ func (s *GraphQuery) GetAssetGroupComboNode(ctx context.Context, owningObjectID
 				if groupMembershipPaths, err := analysis.ExpandGroupMembershipPaths(tx, groups); err != nil {
 					return err
 				} else {
+					groupMembershipPaths = filterPathSetByScope(scope, groupMembershipPaths)
 					graphData = bloodhoundgraph.PathSetToBloodHoundGraph(groupMembershipPaths)
 
 					for key := range graphData {
@@ -216,6 +298,7 @@ func (s *GraphQuery) GetAssetGroupComboNode(ctx context.Context, owningObjectID
 func (s *GraphQuery) GetAssetGroupNodes(ctx context.Context, assetGroupTag string) (graph.NodeSet, error) {
 	var (
 		assetGroupNodes graph.NodeSet
+		scope           = requestEnvironmentScope(ctx)
 		err             error
 	)
 	return assetGroupNodes, s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
@@ -225,7 +308,7 @@ func (s *GraphQuery) GetAssetGroupNodes(ctx context.Context, assetGroupTag strin
 				query.StringContains(query.NodeProperty(common.SystemTags.String()), assetGroupTag),
 			}
 
-			return query.And(filters...)
+			return withScopeCriteria(scope, query.And(filters...))
 		})); err != nil {
 			return err
 		} else {
@@ -240,7 +323,10 @@ func (s *GraphQuery) GetAssetGroupNodes(ctx context.Context, assetGroupTag strin
 func (s *GraphQuery) GetAllShortestPaths(ctx context.Context, startNodeID string, endNodeID string, filter graph.Criteria) (graph.PathSet, error) {
 	defer log.Measure(log.LevelInfo, "GetAllShortestPaths")()
 
-	var paths graph.PathSet
+	var (
+		paths graph.PathSet
+		scope = requestEnvironmentScope(ctx)
+	)
 
 	return paths, s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		if startNode, err := analysis.FetchNodeByObjectID(tx, startNodeID); err != nil {
@@ -259,7 +345,9 @@ func (s *GraphQuery) GetAllShortestPaths(ctx context.Context, startNodeID string
 
 			return tx.Relationships().Filter(query.And(criteria...)).FetchAllShortestPaths(func(cursor graph.Cursor[graph.Path]) error {
 				for path := range cursor.Chan() {
-					paths.AddPath(path)
+					if pathInScope(scope, path) {
+						paths.AddPath(path)
+					}
 				}
 
 				return cursor.Error()
@@ -268,6 +356,80 @@ func (s *GraphQuery) GetAllShortestPaths(ctx context.Context, startNodeID string
 	})
 }
 
+// pathCostFunction returns a traversal cost function for the given cost model. Weighted pathfinding traverses
+// outbound so the node of each segment is the end node of the segment's relationship.
+func pathCostFunction(costModel model.PathCostModel) traversal.CostFunction {
+	kindCostFunction := traversal.KindCostFunction(costModel.KindCosts, costModel.DefaultCost)
+
+	return func(next *graph.PathSegment) (float64, bool) {
+		if cost, hasCost := costModel.TierZeroKindCosts[next.Edge.Kind.String()]; hasCost {
+			if systemTags, err := next.Node.Properties.Get(common.SystemTags.String()).String(); err == nil && strings.Contains(systemTags, ad.AdminTierZero) {
+				return cost, true
+			}
+		}
+
+		return kindCostFunction(next)
+	}
+}
+
+func (s *GraphQuery) GetWeightedShortestPaths(ctx context.Context, startNodeID string, endNodeID string, filter graph.Criteria, costModel model.PathCostModel, limit int) ([]traversal.WeightedPath, error) {
+	defer log.Measure(log.LevelInfo, "GetWeightedShortestPaths")()
+
+	var (
+		startNode *graph.Node
+		endNode   *graph.Node
+	)
+
+	if err := s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		if node, err := analysis.FetchNodeByObjectID(tx, startNodeID); err != nil {
+			return err
+		} else {
+			startNode = node
+		}
+
+		if node, err := analysis.FetchNodeByObjectID(tx, endNodeID); err != nil {
+			return err
+		} else {
+			endNode = node
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if paths, err := traversal.KShortestPaths(ctx, s.Graph, traversal.WeightedPlan{
+		Root:      startNode,
+		Direction: graph.DirectionOutbound,
+		Criteria:  filter,
+		Cost:      pathCostFunction(costModel),
+		Target: func(node *graph.Node) bool {
+			return node.ID == endNode.ID
+		},
+		K: limit,
+	}); err != nil {
+		return nil, err
+	} else {
+		return filterWeightedPathsByScope(requestEnvironmentScope(ctx), paths), nil
+	}
+}
+
+func (s *GraphQuery) GetShortestPathsBetweenNodeSets(ctx context.Context, sources graph.NodeSet, targets graph.NodeSet, filter graph.Criteria, maxDepth int, limit int) (graph.PathSet, error) {
+	defer log.Measure(log.LevelInfo, "GetShortestPathsBetweenNodeSets")()
+
+	if paths, err := traversal.ShortestPathsBetween(ctx, s.Graph, traversal.BidirectionalPlan{
+		Sources:  sources,
+		Targets:  targets,
+		Criteria: filter,
+		MaxDepth: maxDepth,
+		Limit:    limit,
+	}); err != nil {
+		return nil, err
+	} else {
+		return filterPathSetByScope(requestEnvironmentScope(ctx), paths), nil
+	}
+}
+
 func searchNodeByKindAndEqualsName(kind graph.Kind, name string) graph.Criteria {
 	return query.And(
 		query.Kind(query.Node(), kind),
@@ -323,18 +485,19 @@ func (s *GraphQuery) SearchNodesByName(ctx context.Context, nodeKinds graph.Kind
 		exactResults  []model.SearchResult
 		fuzzyResults  []model.SearchResult
 		formattedName = strings.ToUpper(name)
+		scope         = requestEnvironmentScope(ctx)
 	)
 
 	for _, kind := range nodeKinds {
 		if err := s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
-			if exactMatchNodes, err := ops.FetchNodes(tx.Nodes().Filter(searchNodeByKindAndEqualsName(kind, formattedName))); err != nil {
+			if exactMatchNodes, err := ops.FetchNodes(tx.Nodes().Filter(withScopeCriteria(scope, searchNodeByKindAndEqualsName(kind, formattedName)))); err != nil {
 				return err
 
 			} else {
 				exactResults = append(exactResults, nodesToSearchResult(exactMatchNodes...)...)
 			}
 
-			if fuzzyMatchNodes, err := ops.FetchNodes(tx.Nodes().Filter(searchNodeByKindAndContainsName(kind, formattedName))); err != nil {
+			if fuzzyMatchNodes, err := ops.FetchNodes(tx.Nodes().Filter(withScopeCriteria(scope, searchNodeByKindAndContainsName(kind, formattedName)))); err != nil {
 				return err
 			} else {
 				fuzzyResults = append(fuzzyResults, nodesToSearchResult(fuzzyMatchNodes...)...)
@@ -390,23 +553,36 @@ func (s *GraphQuery) prepareGraphQuery(rawCypher string, disableCypherQC bool) (
 }
 
 func (s *GraphQuery) RawCypherSearch(ctx context.Context, rawCypher string) (model.UnifiedGraph, error) {
+	graphResponse := model.NewUnifiedGraph()
+
+	if pathSet, err := s.RawCypherPathSearch(ctx, rawCypher); err != nil {
+		return graphResponse, err
+	} else {
+		graphResponse.AddPathSet(pathSet)
+		return graphResponse, nil
+	}
+}
+
+// RawCypherPathSearch runs the given user cypher query and returns every path, node and relationship it matched. Paths
+// that cross a node outside of the environments visible to the requester are removed from the result.
+func (s *GraphQuery) RawCypherPathSearch(ctx context.Context, rawCypher string) (graph.PathSet, error) {
 	var (
-		graphResponse = model.NewUnifiedGraph()
-		bhCtxInst     = bhCtx.Get(ctx)
+		pathSet   graph.PathSet
+		bhCtxInst = bhCtx.Get(ctx)
 	)
 
 	if preparedQuery, err := s.prepareGraphQuery(rawCypher, s.DisableCypherQC); err != nil {
-		return graphResponse, err
+		return pathSet, err
 	} else {
 		logEvent := log.WithLevel(log.LevelInfo)
 		logEvent.Str("query", preparedQuery.strippedCypher)
 		logEvent.Msg("Executing user cypher query")
 
-		return graphResponse, s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
-			if pathSet, err := ops.FetchPathSetByQuery(tx, preparedQuery.cypher); err != nil {
+		err := s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
+			if fetchedPathSet, err := ops.FetchPathSetByQuery(tx, preparedQuery.cypher); err != nil {
 				return err
 			} else {
-				graphResponse.AddPathSet(pathSet)
+				pathSet = filterPathSetByScope(requestEnvironmentScope(ctx), fetchedPathSet)
 			}
 
 			return nil
@@ -429,6 +605,8 @@ func (s *GraphQuery) RawCypherSearch(ctx context.Context, rawCypher string) (mod
 			// Set a sane timeout for this DB interaction
 			config.Timeout = availableRuntime
 		})
+
+		return pathSet, err
 	}
 }
 
@@ -458,27 +636,30 @@ func nodesToSearchResult(nodes ...*graph.Node) []model.SearchResult {
 }
 
 func (s *GraphQuery) SearchByNameOrObjectID(ctx context.Context, searchValue string, searchType SearchType) (graph.NodeSet, error) {
-	var nodes = graph.NewNodeSet()
+	var (
+		nodes = graph.NewNodeSet()
+		scope = requestEnvironmentScope(ctx)
+	)
 
 	for _, kind := range []graph.Kind{ad.Entity, azure.Entity} {
 		if err := s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 			if fetchedNodes, err := ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
 				if searchType == SearchTypeExact {
-					return query.And(
+					return withScopeCriteria(scope, query.And(
 						query.Kind(query.Node(), kind),
 						query.Or(
 							query.Equals(query.NodeProperty(common.Name.String()), strings.ToUpper(searchValue)),
 							query.Equals(query.NodeProperty(common.ObjectID.String()), strings.ToUpper(searchValue)),
 						),
-					)
+					))
 				} else {
-					return query.And(
+					return withScopeCriteria(scope, query.And(
 						query.Kind(query.Node(), kind),
 						query.Or(
 							query.StringStartsWith(query.NodeProperty(common.Name.String()), strings.ToUpper(searchValue)),
 							query.StringStartsWith(query.NodeProperty(common.ObjectID.String()), strings.ToUpper(searchValue)),
 						),
-					)
+					))
 				}
 			})); err != nil {
 				return err
@@ -510,17 +691,20 @@ func (s *GraphQuery) GetADEntityQueryResult(ctx context.Context, params EntityQu
 	}
 }
 
+// GetEntityByObjectId returns the entity node with the given object ID. Entities outside of the environments visible to
+// the requester are not found.
 func (s *GraphQuery) GetEntityByObjectId(ctx context.Context, objectID string, kinds ...graph.Kind) (*graph.Node, error) {
 	var (
-		node *graph.Node
-		err  error
+		node  *graph.Node
+		scope = requestEnvironmentScope(ctx)
+		err   error
 	)
 	if err := s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		if node, err = tx.Nodes().Filterf(func() graph.Criteria {
-			return query.And(
+			return withScopeCriteria(scope, query.And(
 				query.Equals(query.NodeProperty(common.ObjectID.String()), objectID),
 				query.KindIn(query.Node(), kinds...),
-			)
+			))
 		}).First(); err != nil {
 			return err
 		}
@@ -536,6 +720,7 @@ func (s *GraphQuery) GetEntityByObjectId(ctx context.Context, objectID string, k
 func (s *GraphQuery) GetEntityCountResults(ctx context.Context, node *graph.Node, delegates map[string]any) map[string]any {
 	var (
 		results   = make(map[string]any)
+		scope     = requestEnvironmentScope(ctx)
 		data      sync.Map
 		waitGroup sync.WaitGroup
 	)
@@ -552,7 +737,7 @@ func (s *GraphQuery) GetEntityCountResults(ctx context.Context, node *graph.Node
 				log.Errorf("error running entity query for key %s: %v", delegateKey, err)
 				data.Store(delegateKey, 0)
 			} else {
-				data.Store(delegateKey, result.Len())
+				data.Store(delegateKey, filterNodeSetByScope(scope, result).Len())
 			}
 		}(delegateKey, delegate)
 	}
@@ -569,11 +754,14 @@ func (s *GraphQuery) GetEntityCountResults(ctx context.Context, node *graph.Node
 }
 
 func (s *GraphQuery) GetNodesByKind(ctx context.Context, kinds ...graph.Kind) (graph.NodeSet, error) {
-	var nodes graph.NodeSet
+	var (
+		nodes graph.NodeSet
+		scope = requestEnvironmentScope(ctx)
+	)
 
 	return nodes, s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		if fetchedNodes, err := ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
-			return query.KindIn(query.Node(), kinds...)
+			return withScopeCriteria(scope, query.KindIn(query.Node(), kinds...))
 		})); err != nil {
 			return err
 		} else {
@@ -583,12 +771,15 @@ func (s *GraphQuery) GetNodesByKind(ctx context.Context, kinds ...graph.Kind) (g
 	})
 }
 
-func (s *GraphQuery) GetFilteredAndSortedNodes(orderCriteria model.OrderCriteria, filterCriteria graph.Criteria) (graph.NodeSet, error) {
-	var nodes graph.NodeSet
+func (s *GraphQuery) GetFilteredAndSortedNodes(ctx context.Context, orderCriteria model.OrderCriteria, filterCriteria graph.Criteria) (graph.NodeSet, error) {
+	var (
+		nodes graph.NodeSet
+		scope = requestEnvironmentScope(ctx)
+	)
 
-	if err := s.Graph.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+	if err := s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		nodeQuery := tx.Nodes().Filterf(func() graph.Criteria {
-			return filterCriteria
+			return withScopeCriteria(scope, filterCriteria)
 		})
 
 		if len(orderCriteria) > 0 {
@@ -613,15 +804,18 @@ func (s *GraphQuery) GetFilteredAndSortedNodes(orderCriteria model.OrderCriteria
 // FetchNodesByObjectIDs takes a list of objectIDs. Returns a graph.NodeSet for found results
 // and an error for graph database errors.
 func (s *GraphQuery) FetchNodesByObjectIDs(ctx context.Context, objectIDs ...string) (graph.NodeSet, error) {
-	var nodes graph.NodeSet
+	var (
+		nodes graph.NodeSet
+		scope = requestEnvironmentScope(ctx)
+	)
 
 	return nodes, s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		if fetchedNodes, err := ops.FetchNodeSet(tx.Nodes().Filterf(
 			func() graph.Criteria {
-				return query.And(
+				return withScopeCriteria(scope, query.And(
 					query.KindIn(query.Node(), ad.Entity, azure.Entity),
 					query.In(query.NodeProperty(common.ObjectID.String()), objectIDs),
-				)
+				))
 			}),
 		); err != nil {
 			return err
@@ -689,6 +883,62 @@ func nodeSetToOrderedSlice(nodeSet graph.NodeSet) []*graph.Node {
 	return nodes
 }
 
+// compareEntityColumn compares the values of the given sortable column of two nodes. Numeric columns that have not been
+// set on a node sort before every set value.
+func compareEntityColumn(column string, left, right *graph.Node) int {
+	switch column {
+	case common.InboundExposure.String(), common.OutboundExposure.String():
+		var (
+			leftValue, leftHasValue   = exposure.NumericProperty(left, column)
+			rightValue, rightHasValue = exposure.NumericProperty(right, column)
+		)
+
+		if leftHasValue != rightHasValue {
+			if leftHasValue {
+				return 1
+			}
+
+			return -1
+		}
+
+		if leftValue < rightValue {
+			return -1
+		} else if leftValue > rightValue {
+			return 1
+		}
+
+		return 0
+
+	default:
+		leftValue, _ := left.Properties.GetOrDefault(column, "").String()
+		rightValue, _ := right.Properties.GetOrDefault(column, "").String()
+
+		return strings.Compare(leftValue, rightValue)
+	}
+}
+
+// sortEntityNodes sorts the given nodes by the given order criteria. Nodes that are equal under every criterion keep
+// their relative order.
+func sortEntityNodes(nodes []*graph.Node, orderCriteria model.OrderCriteria) {
+	if len(orderCriteria) == 0 {
+		return
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		for _, criterion := range orderCriteria {
+			if comparison := compareEntityColumn(criterion.Property, nodes[i], nodes[j]); comparison != 0 {
+				if criterion.Order == query.Descending() {
+					return comparison > 0
+				}
+
+				return comparison < 0
+			}
+		}
+
+		return false
+	})
+}
+
 func (s *GraphQuery) cacheQueryResult(queryStart time.Time, cacheKey string, result graph.NodeSet) {
 	queryTime := time.Since(queryStart).Milliseconds()
 
@@ -743,12 +993,13 @@ func (s *GraphQuery) runListEntityQuery(ctx context.Context, node *graph.Node, p
 		queryStart = time.Now()
 		skip       = params.Skip
 		limit      = params.Limit
-		mustFetch  = !cacheEnabled
+		asOf       = !params.AsOf.IsZero()
+		mustFetch  = !cacheEnabled || asOf
 
 		result graph.NodeSet
 	)
 
-	if cacheEnabled {
+	if cacheEnabled && !asOf {
 		if hasResult, err := s.Cache.Get(cacheKey, &result); err != nil {
 			return nil, fmt.Errorf("error getting cache entry for %s: %w", cacheKey, err)
 		} else {
@@ -765,6 +1016,21 @@ func (s *GraphQuery) runListEntityQuery(ctx context.Context, node *graph.Node, p
 		}
 	}
 
+	if asOf {
+		if validResult, err := nodesValidAsOf(ctx, s.Graph, node, params.PathDelegate, result, params.AsOf); err != nil {
+			return nil, err
+		} else {
+			result = validResult
+		}
+	}
+
+	if params.QueryName != "" && cacheEnabled && mustFetch && !asOf {
+		s.cacheQueryResult(queryStart, cacheKey, result)
+	}
+
+	// Cached results hold every node matched by the query and are scoped to the requester afterwards
+	result = filterNodeSetByScope(requestEnvironmentScope(ctx), result)
+
 	// Return early if this is just a count request
 	if params.RequestedType == model.DataTypeCount {
 		return result.Len(), nil
@@ -778,9 +1044,8 @@ func (s *GraphQuery) runListEntityQuery(ctx context.Context, node *graph.Node, p
 		limit = result.Len() - skip
 	}
 
-	if params.QueryName != "" && cacheEnabled && mustFetch {
-		s.cacheQueryResult(queryStart, cacheKey, result)
-	}
+	orderedResult := nodeSetToOrderedSlice(result)
+	sortEntityNodes(orderedResult, params.OrderCriteria)
 
 	return api.ResponseWrapper{
 		Count: result.Len(),
@@ -788,7 +1053,7 @@ func (s *GraphQuery) runListEntityQuery(ctx context.Context, node *graph.Node, p
 		Skip:  params.Skip,
 
 		// Slice the result set to match skip/limit and return the packaged response
-		Data: fromGraphNodes(graph.NewNodeSet(nodeSetToOrderedSlice(result)[skip : skip+limit]...)),
+		Data: fromGraphNodes(orderedResult[skip : skip+limit]),
 	}, nil
 }
 
@@ -815,21 +1080,54 @@ func RunPathQuery(ctx context.Context, db graph.Database, node *graph.Node, path
 	}
 }
 
+// nodesValidAsOf returns the nodes of a list result that lie on a path of the entity query whose relationships were all
+// valid at the given time
+func nodesValidAsOf(ctx context.Context, db graph.Database, node *graph.Node, pathDelegate any, result graph.NodeSet, asOf time.Time) (graph.NodeSet, error) {
+	if pathDelegate == nil {
+		return nil, fmt.Errorf("%w: as_of requires a graph query", ErrUnsupportedDataType)
+	} else if paths, err := RunPathQuery(ctx, db, node, pathDelegate); err != nil {
+		return nil, err
+	} else {
+		var (
+			validNodes  = analysis.FilterPathsValidAsOf(paths, asOf).AllNodes()
+			validResult = graph.NewNodeSet()
+		)
+
+		for _, resultNode := range result {
+			if validNodes.Contains(resultNode) {
+				validResult.Add(resultNode)
+			}
+		}
+
+		return validResult, nil
+	}
+}
+
 func (s *GraphQuery) GetEntityResults(ctx context.Context, node *graph.Node, params EntityQueryParameters, cacheEnabled bool) (any, error) {
 	// Graph type isn't currently under a caching model and is handled separately from other supported RequestedTypes
 	if params.RequestedType == model.DataTypeGraph {
 		if result, err := RunPathQuery(ctx, s.Graph, node, params.PathDelegate); err != nil {
 			return nil, err
 		} else {
-			return bloodhoundgraph.PathSetToBloodHoundGraph(result), nil
+			if !params.AsOf.IsZero() {
+				result = analysis.FilterPathsValidAsOf(result, params.AsOf)
+			}
+
+			result = filterPathSetByScope(requestEnvironmentScope(ctx), result)
+
+			if params.ExportFormat != "" {
+				return graphexport.FromPathSet(result), nil
+			} else {
+				return bloodhoundgraph.PathSetToBloodHoundGraph(result), nil
+			}
 		}
 	}
 
 	return s.runListEntityQuery(ctx, node, params, cacheEnabled)
 }
 
-func fromGraphNodes(nodes graph.NodeSet) []model.PagedNodeListEntry {
-	renderedNodes := make([]model.PagedNodeListEntry, 0, nodes.Len())
+func fromGraphNodes(nodes []*graph.Node) []model.PagedNodeListEntry {
+	renderedNodes := make([]model.PagedNodeListEntry, 0, len(nodes))
 
 	for _, node := range nodes {
 		var (
@@ -853,6 +1151,11 @@ func fromGraphNodes(nodes graph.NodeSet) []model.PagedNodeListEntry {
 
 		nodeEntry.Label = analysis.GetNodeKindDisplayLabel(node)
 
+		if nodeExposure, scored := exposure.NodeExposure(node); scored {
+			nodeEntry.InboundExposure = &nodeExposure.Inbound
+			nodeEntry.OutboundExposure = &nodeExposure.Outbound
+		}
+
 		renderedNodes = append(renderedNodes, nodeEntry)
 	}
 
@@ -29,7 +29,9 @@ import (
 	"go.uber.org/mock/gomock"
 	"github.com/specterops/bloodhound/dawgs/graph"
 	graph_mocks "github.com/specterops/bloodhound/dawgs/graph/mocks"
+	"github.com/specterops/bloodhound/dawgs/query"
 	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/graphschema/common"
 )
 
 const cacheKey = "ad-entity-query_queryName_objectID_1"
@@ -126,6 +128,90 @@ func Test_getOrRunListQuery(t *testing.T) {
 	})
 }
 
+func Test_runListEntityQuery_sorting(t *testing.T) {
+	var (
+		mockCtrl = gomock.NewController(t)
+		mockDB   = graph_mocks.NewMockDatabase(mockCtrl)
+		node     = graph.NewNode(0, graph.NewProperties(), graph.StringKind("kind"))
+		ctx      = context.Background()
+
+		newPrincipal = func(id graph.ID, name string, outboundExposure int64) *graph.Node {
+			return graph.NewNode(id, graph.AsProperties(map[string]any{
+				common.Name.String():             name,
+				common.InboundExposure.String():  int64(0),
+				common.OutboundExposure.String(): outboundExposure,
+			}), graph.StringKind("kind"))
+		}
+
+		listDelegate = func(tx graph.Transaction, node *graph.Node, skip, limit int) (graph.NodeSet, error) {
+			return graph.NewNodeSet(
+				newPrincipal(1, "a", 5),
+				newPrincipal(2, "b", 10),
+				newPrincipal(3, "c", 5),
+				graph.NewNode(4, graph.AsProperties(map[string]any{common.Name.String(): "d"}), graph.StringKind("kind")),
+			), nil
+		}
+
+		resultNames = func(result any) []string {
+			var names []string
+
+			for _, entry := range result.(api.ResponseWrapper).Data.([]model.PagedNodeListEntry) {
+				names = append(names, entry.Name)
+			}
+
+			return names
+		}
+	)
+	defer mockCtrl.Finish()
+
+	graphQueryInst := &GraphQuery{
+		Graph: mockDB,
+	}
+
+	mockDB.EXPECT().ReadTransaction(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, txDelegate graph.TransactionDelegate, options ...graph.TransactionOption) error {
+		return txDelegate(nil)
+	}).Times(3)
+
+	// Results default to descending node ID order
+	result, err := graphQueryInst.runListEntityQuery(ctx, node, EntityQueryParameters{
+		RequestedType: model.DataTypeList,
+		Limit:         10,
+		ListDelegate:  listDelegate,
+	}, false)
+	require.Nil(t, err)
+	require.Equal(t, []string{"d", "c", "b", "a"}, resultNames(result))
+
+	// Ties keep the default order and principals that were not scored sort last when descending
+	result, err = graphQueryInst.runListEntityQuery(ctx, node, EntityQueryParameters{
+		RequestedType: model.DataTypeList,
+		Limit:         10,
+		ListDelegate:  listDelegate,
+		OrderCriteria: model.OrderCriteria{{
+			Property: common.OutboundExposure.String(),
+			Order:    query.Descending(),
+		}},
+	}, false)
+	require.Nil(t, err)
+	require.Equal(t, []string{"b", "c", "a", "d"}, resultNames(result))
+
+	// Sorting is applied before the page is sliced
+	result, err = graphQueryInst.runListEntityQuery(ctx, node, EntityQueryParameters{
+		RequestedType: model.DataTypeList,
+		Skip:          1,
+		Limit:         2,
+		ListDelegate:  listDelegate,
+		OrderCriteria: model.OrderCriteria{{
+			Property: common.OutboundExposure.String(),
+			Order:    query.Ascending(),
+		}, {
+			Property: common.Name.String(),
+			Order:    query.Ascending(),
+		}},
+	}, false)
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "c"}, resultNames(result))
+}
+
 func Test_cacheQueryResult(t *testing.T) {
 	var (
 		mockCtrl = gomock.NewController(t)
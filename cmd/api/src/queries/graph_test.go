@@ -31,6 +31,7 @@ import (
 	"github.com/specterops/bloodhound/cache"
 	"github.com/specterops/bloodhound/dawgs/graph"
 	graphMocks "github.com/specterops/bloodhound/dawgs/graph/mocks"
+	"github.com/specterops/bloodhound/dawgs/query"
 	"github.com/specterops/bloodhound/graphschema/ad"
 	"github.com/specterops/bloodhound/graphschema/common"
 	"github.com/specterops/bloodhound/src/api"
@@ -196,6 +197,42 @@ func TestQueries_BuildEntityQueryParams(t *testing.T) {
 	require.Equal(t, objectID, params.ObjectID)
 }
 
+func TestQueries_BuildEntityQueryParams_SortBy(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/v2/domains/S-1-5-21-570004220-2248230615-4072641716/users", nil)
+	require.Nil(t, err)
+
+	req = mux.SetURLVars(req, map[string]string{"object_id": "1"})
+
+	q := url.Values{}
+	q.Add("sort_by", "-outboundexposure")
+	q.Add("sort_by", "name")
+	req.URL.RawQuery = q.Encode()
+
+	params, err := queries.BuildEntityQueryParams(req, "", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, model.OrderCriteria{{
+		Property: "outboundexposure",
+		Order:    query.Descending(),
+	}, {
+		Property: "name",
+		Order:    query.Ascending(),
+	}}, params.OrderCriteria)
+}
+
+func TestQueries_BuildEntityQueryParams_InvalidSortBy(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/v2/domains/S-1-5-21-570004220-2248230615-4072641716/users", nil)
+	require.Nil(t, err)
+
+	req = mux.SetURLVars(req, map[string]string{"object_id": "1"})
+
+	q := url.Values{}
+	q.Add("sort_by", "-description")
+	req.URL.RawQuery = q.Encode()
+
+	_, err = queries.BuildEntityQueryParams(req, "", nil, nil)
+	require.Contains(t, err.Error(), model.ErrorResponseDetailsColumnNotSortable)
+}
+
 func TestQueries_BuildEntityQueryParams_DataTypeCount(t *testing.T) {
 	req, err := http.NewRequest("GET", "/api/v2/users/S-1-5-21-570004220-2248230615-4072641716-4001/admin-rights", nil)
 	require.Nil(t, err)
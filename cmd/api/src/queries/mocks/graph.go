@@ -25,6 +25,7 @@ import (
 	reflect "reflect"
 
 	graph "github.com/specterops/bloodhound/dawgs/graph"
+	traversal "github.com/specterops/bloodhound/dawgs/traversal"
 	model "github.com/specterops/bloodhound/src/model"
 	queries "github.com/specterops/bloodhound/src/queries"
 	gomock "go.uber.org/mock/gomock"
@@ -182,18 +183,18 @@ func (mr *MockGraphMockRecorder) GetEntityCountResults(arg0, arg1, arg2 interfac
 }
 
 // GetFilteredAndSortedNodes mocks base method.
-func (m *MockGraph) GetFilteredAndSortedNodes(arg0 model.OrderCriteria, arg1 graph.Criteria) (graph.NodeSet, error) {
+func (m *MockGraph) GetFilteredAndSortedNodes(arg0 context.Context, arg1 model.OrderCriteria, arg2 graph.Criteria) (graph.NodeSet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilteredAndSortedNodes", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetFilteredAndSortedNodes", arg0, arg1, arg2)
 	ret0, _ := ret[0].(graph.NodeSet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilteredAndSortedNodes indicates an expected call of GetFilteredAndSortedNodes.
-func (mr *MockGraphMockRecorder) GetFilteredAndSortedNodes(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockGraphMockRecorder) GetFilteredAndSortedNodes(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredAndSortedNodes", reflect.TypeOf((*MockGraph)(nil).GetFilteredAndSortedNodes), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredAndSortedNodes", reflect.TypeOf((*MockGraph)(nil).GetFilteredAndSortedNodes), arg0, arg1, arg2)
 }
 
 // GetNodesByKind mocks base method.
@@ -216,6 +217,51 @@ func (mr *MockGraphMockRecorder) GetNodesByKind(arg0 interface{}, arg1 ...interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodesByKind", reflect.TypeOf((*MockGraph)(nil).GetNodesByKind), varargs...)
 }
 
+// GetShortestPathsBetweenNodeSets mocks base method.
+func (m *MockGraph) GetShortestPathsBetweenNodeSets(arg0 context.Context, arg1, arg2 graph.NodeSet, arg3 graph.Criteria, arg4, arg5 int) (graph.PathSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortestPathsBetweenNodeSets", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(graph.PathSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortestPathsBetweenNodeSets indicates an expected call of GetShortestPathsBetweenNodeSets.
+func (mr *MockGraphMockRecorder) GetShortestPathsBetweenNodeSets(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortestPathsBetweenNodeSets", reflect.TypeOf((*MockGraph)(nil).GetShortestPathsBetweenNodeSets), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetWeightedShortestPaths mocks base method.
+func (m *MockGraph) GetWeightedShortestPaths(arg0 context.Context, arg1, arg2 string, arg3 graph.Criteria, arg4 model.PathCostModel, arg5 int) ([]traversal.WeightedPath, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWeightedShortestPaths", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]traversal.WeightedPath)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWeightedShortestPaths indicates an expected call of GetWeightedShortestPaths.
+func (mr *MockGraphMockRecorder) GetWeightedShortestPaths(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWeightedShortestPaths", reflect.TypeOf((*MockGraph)(nil).GetWeightedShortestPaths), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// RawCypherPathSearch mocks base method.
+func (m *MockGraph) RawCypherPathSearch(arg0 context.Context, arg1 string) (graph.PathSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RawCypherPathSearch", arg0, arg1)
+	ret0, _ := ret[0].(graph.PathSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RawCypherPathSearch indicates an expected call of RawCypherPathSearch.
+func (mr *MockGraphMockRecorder) RawCypherPathSearch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RawCypherPathSearch", reflect.TypeOf((*MockGraph)(nil).RawCypherPathSearch), arg0, arg1)
+}
+
 // RawCypherSearch mocks base method.
 func (m *MockGraph) RawCypherSearch(arg0 context.Context, arg1 string) (model.UnifiedGraph, error) {
 	m.ctrl.T.Helper()
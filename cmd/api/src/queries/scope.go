@@ -0,0 +1,129 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package queries
+
+import (
+	"context"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/traversal"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/src/auth"
+	bhCtx "github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// requestEnvironmentScope returns the environments whose graph data is visible to the requester of the given context
+func requestEnvironmentScope(ctx context.Context) model.EnvironmentScope {
+	return auth.GetEnvironmentScopeFromAuthCtx(bhCtx.Get(ctx).AuthCtx)
+}
+
+// nodeInScope returns true if the node belongs to an environment of the scope. Domain and tenant nodes belong to the
+// environment they identify. Nodes that belong to no environment are only visible to unrestricted scopes.
+func nodeInScope(scope model.EnvironmentScope, node *graph.Node) bool {
+	if !scope.Restricted {
+		return true
+	}
+
+	for _, property := range []string{ad.DomainSID.String(), azure.TenantID.String(), common.ObjectID.String()} {
+		if environmentID, err := node.Properties.Get(property).String(); err == nil && scope.Allows(environmentID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scopeCriteria returns the criteria that match the nodes of the environments of a restricted scope
+func scopeCriteria(scope model.EnvironmentScope) graph.Criteria {
+	return query.Or(
+		query.In(query.NodeProperty(ad.DomainSID.String()), scope.EnvironmentIDs),
+		query.In(query.NodeProperty(azure.TenantID.String()), scope.EnvironmentIDs),
+		query.In(query.NodeProperty(common.ObjectID.String()), scope.EnvironmentIDs),
+	)
+}
+
+// withScopeCriteria adds the criteria of a restricted scope to the given node criteria
+func withScopeCriteria(scope model.EnvironmentScope, criteria graph.Criteria) graph.Criteria {
+	if !scope.Restricted {
+		return criteria
+	}
+
+	return query.And(criteria, scopeCriteria(scope))
+}
+
+func filterNodeSetByScope(scope model.EnvironmentScope, nodes graph.NodeSet) graph.NodeSet {
+	if !scope.Restricted {
+		return nodes
+	}
+
+	scopedNodes := graph.NewNodeSet()
+
+	for _, node := range nodes {
+		if nodeInScope(scope, node) {
+			scopedNodes.Add(node)
+		}
+	}
+
+	return scopedNodes
+}
+
+func pathInScope(scope model.EnvironmentScope, path graph.Path) bool {
+	for _, node := range path.Nodes {
+		if !nodeInScope(scope, node) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterPathSetByScope removes every path that crosses a node outside of the scope
+func filterPathSetByScope(scope model.EnvironmentScope, paths graph.PathSet) graph.PathSet {
+	if !scope.Restricted {
+		return paths
+	}
+
+	var scopedPaths graph.PathSet
+
+	for _, path := range paths {
+		if pathInScope(scope, path) {
+			scopedPaths.AddPath(path)
+		}
+	}
+
+	return scopedPaths
+}
+
+func filterWeightedPathsByScope(scope model.EnvironmentScope, paths []traversal.WeightedPath) []traversal.WeightedPath {
+	if !scope.Restricted {
+		return paths
+	}
+
+	var scopedPaths []traversal.WeightedPath
+
+	for _, path := range paths {
+		if pathInScope(scope, path.Path) {
+			scopedPaths = append(scopedPaths, path)
+		}
+	}
+
+	return scopedPaths
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/src/auth"
+	bhCtx "github.com/specterops/bloodhound/src/ctx"
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requestEnvironmentScope(t *testing.T) {
+	require.False(t, requestEnvironmentScope(context.Background()).Restricted)
+
+	ctx := bhCtx.Set(context.Background(), &bhCtx.Context{
+		AuthCtx: auth.Context{
+			Owner: model.User{
+				Roles: model.Roles{{
+					Name:         "EMEA Analysts",
+					Environments: model.RoleEnvironments{{EnvironmentID: "S-1-5-21-1"}},
+					Custom:       true,
+				}},
+			},
+		},
+	})
+
+	scope := requestEnvironmentScope(ctx)
+	require.True(t, scope.Restricted)
+	require.Equal(t, []string{"S-1-5-21-1"}, scope.EnvironmentIDs)
+}
+
+func Test_filterByScope(t *testing.T) {
+	var (
+		scope = model.EnvironmentScope{
+			Restricted:     true,
+			EnvironmentIDs: []string{"S-1-5-21-1", "TENANT-1"},
+		}
+
+		domain      = graph.NewNode(1, graph.NewProperties().Set(common.ObjectID.String(), "S-1-5-21-1"), ad.Entity, ad.Domain)
+		user        = graph.NewNode(2, graph.NewProperties().Set(common.ObjectID.String(), "S-1-5-21-1-1105").Set(ad.DomainSID.String(), "S-1-5-21-1"), ad.Entity, ad.User)
+		azureUser   = graph.NewNode(3, graph.NewProperties().Set(common.ObjectID.String(), "AZUSER").Set(azure.TenantID.String(), "TENANT-1"), azure.Entity, azure.User)
+		foreignUser = graph.NewNode(4, graph.NewProperties().Set(common.ObjectID.String(), "S-1-5-21-2-1105").Set(ad.DomainSID.String(), "S-1-5-21-2"), ad.Entity, ad.User)
+
+		containsUser    = graph.NewRelationship(5, domain.ID, user.ID, graph.NewProperties(), ad.Contains)
+		containsForeign = graph.NewRelationship(6, domain.ID, foreignUser.ID, graph.NewProperties(), ad.Contains)
+	)
+
+	require.True(t, nodeInScope(scope, domain))
+	require.True(t, nodeInScope(scope, user))
+	require.True(t, nodeInScope(scope, azureUser))
+	require.False(t, nodeInScope(scope, foreignUser))
+	require.True(t, nodeInScope(model.EnvironmentScope{}, foreignUser))
+
+	nodes := filterNodeSetByScope(scope, graph.NewNodeSet(domain, user, azureUser, foreignUser))
+	require.Equal(t, 3, nodes.Len())
+	require.False(t, nodes.Contains(foreignUser))
+
+	paths := filterPathSetByScope(scope, graph.PathSet{
+		{Nodes: []*graph.Node{domain, user}, Edges: []*graph.Relationship{containsUser}},
+		{Nodes: []*graph.Node{domain, foreignUser}, Edges: []*graph.Relationship{containsForeign}},
+	})
+	require.Len(t, paths, 1)
+	require.Equal(t, user.ID, paths[0].Terminal().ID)
+}
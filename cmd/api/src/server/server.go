@@ -69,13 +69,14 @@ func SystemSignalExitChannel() chan struct{} {
 	return exitC
 }
 
-// MigrateGraph runs migrations for the graph database
-func MigrateGraph(cfg config.Configuration, db graph.Database) error {
+// MigrateGraph runs migrations for the graph database. Custom node kinds registered by generic ingest are kept in the
+// asserted schema.
+func MigrateGraph(cfg config.Configuration, db graph.Database, customNodeKinds ...graph.Kind) error {
 	if cfg.DisableMigrations {
 		log.Infof("Graph migrations are disabled per configuration")
 		return nil
 	}
-	return migrations.NewGraphMigrator(db).Migrate()
+	return migrations.NewGraphMigrator(db, customNodeKinds...).Migrate()
 }
 
 // MigrateDB runs database migrations on PG
@@ -157,7 +158,9 @@ func StartServer(cfg config.Configuration, exitC chan struct{}) error {
 		return fmt.Errorf("db connection error: %w", err)
 	} else if err := MigrateDB(cfg, db, migration.ListBHModels()); err != nil {
 		return fmt.Errorf("db migration error: %w", err)
-	} else if err := MigrateGraph(cfg, graphDB); err != nil {
+	} else if customKinds, err := db.GetCustomKinds(); err != nil {
+		return fmt.Errorf("custom kinds error: %w", err)
+	} else if err := MigrateGraph(cfg, graphDB, customKinds.NodeKinds()...); err != nil {
 		return fmt.Errorf("graph db migration error: %w", err)
 	} else if apiCache, err := cache.NewCache(cache.Config{MaxSize: cfg.MaxAPICacheSize}); err != nil {
 		return fmt.Errorf("failed to create in-memory cache for API: %w", err)
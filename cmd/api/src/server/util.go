@@ -25,7 +25,9 @@ import (
 	"github.com/specterops/bloodhound/src/config"
 	"github.com/specterops/bloodhound/src/database"
 	"github.com/specterops/bloodhound/dawgs"
-	_ "github.com/specterops/bloodhound/dawgs/drivers/neo4j"
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/drivers/neo4j"
+	"github.com/specterops/bloodhound/dawgs/drivers/pg"
 	"github.com/specterops/bloodhound/dawgs/graph"
 	"github.com/specterops/bloodhound/log"
 )
@@ -96,11 +98,30 @@ func ConnectPostgres(cfg config.Configuration) (*database.BloodhoundDB, error) {
 	}
 }
 
+// ConnectGraph opens the graph database selected by the graph driver configuration. Only the Neo4j graph driver can
+// back the API: graph searches, post-processing rules and asset group selectors run raw Cypher, which the PostgreSQL
+// graph driver can not translate yet. The in-memory graph driver is only meant for tests.
+func ConnectGraph(cfg config.Configuration) (graph.Database, error) {
+	switch cfg.GraphDriver {
+	case neo4j.DriverName, "":
+		return dawgs.Open(neo4j.DriverName, cfg.Neo4J.Neo4jConnectionString())
+
+	case pg.DriverName:
+		return nil, fmt.Errorf("graph driver %s does not support raw Cypher queries; use the %s graph driver", pg.DriverName, neo4j.DriverName)
+
+	case memory.DriverName:
+		return nil, fmt.Errorf("graph driver %s is only available to tests", memory.DriverName)
+
+	default:
+		return nil, fmt.Errorf("unknown graph driver %s", cfg.GraphDriver)
+	}
+}
+
 // ConnectDatabases initializes connections to PG and connection, and returns errors if any
 func ConnectDatabases(cfg config.Configuration) (*database.BloodhoundDB, graph.Database, error) {
 	if db, err := ConnectPostgres(cfg); err != nil {
 		return nil, nil, err
-	} else if graphDatabase, err := dawgs.Open("neo4j", cfg.Neo4J.Neo4jConnectionString()); err != nil {
+	} else if graphDatabase, err := ConnectGraph(cfg); err != nil {
 		return nil, nil, err
 	} else {
 		return db, graphDatabase, nil
@@ -88,34 +88,59 @@ func RunAssetGroupIsolationCollections(ctx context.Context, db AgiData, graphDB
 	}
 }
 
+// resolveDynamicSelectors returns the IDs of the nodes selected by the given dynamic selectors. A selector that can not
+// be evaluated is logged and skipped, in which case the returned bool is false.
+func resolveDynamicSelectors(tx graph.Transaction, assetGroup model.AssetGroup) ([]graph.ID, bool) {
+	var (
+		selectedIDs []graph.ID
+		complete    = true
+	)
+
+	for _, selector := range assetGroup.Selectors.Dynamic() {
+		if ids, err := resolveSelector(tx, selector); err != nil {
+			log.Errorf("Failed evaluating selector %s of asset group %s: %v", selector.Name, assetGroup.Tag, err)
+			complete = false
+		} else {
+			selectedIDs = append(selectedIDs, ids...)
+		}
+	}
+
+	return selectedIDs, complete
+}
+
+// UpdateAssetGroupIsolationTags tags the nodes selected by the selectors of every asset group. Dynamic selectors are
+// re-evaluated on every call and the tag is removed from any node that is no longer selected.
 func UpdateAssetGroupIsolationTags(ctx context.Context, db AgiData, graphDb graph.Database) error {
 	if assetGroups, err := db.GetAllAssetGroups("", model.SQLFilter{}); err != nil {
 		return err
 	} else {
 		return graphDb.WriteTransaction(ctx, func(tx graph.Transaction) error {
 			for _, assetGroup := range assetGroups {
-				if assetGroupNodes, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
-					tagPropertyStr := common.SystemTags.String()
-
-					if !assetGroup.SystemGroup {
-						tagPropertyStr = common.UserTags.String()
+				var (
+					tagPropertyStr           = common.SystemTags.String()
+					selectedIDs, allResolved = resolveDynamicSelectors(tx, assetGroup)
+					selectedCriteria         = func() graph.Criteria {
+						return query.Or(
+							query.In(query.NodeProperty(common.ObjectID.String()), assetGroup.Selectors.ObjectIDs()),
+							query.InIDs(query.NodeID(), selectedIDs...),
+						)
 					}
+				)
+
+				if !assetGroup.SystemGroup {
+					tagPropertyStr = common.UserTags.String()
+				}
 
+				if assetGroupNodes, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
 					return query.And(
 						query.KindIn(query.Node(), ad.Entity, azure.Entity),
-						query.In(query.NodeProperty(common.ObjectID.String()), assetGroup.Selectors.Strings()),
+						selectedCriteria(),
 						query.Not(query.StringContains(query.NodeProperty(tagPropertyStr), assetGroup.Tag)),
 					)
 				})); err != nil {
 					return err
 				} else {
 					for _, node := range assetGroupNodes {
-						tagPropertyStr := common.SystemTags.String()
-
-						if !assetGroup.SystemGroup {
-							tagPropertyStr = common.UserTags.String()
-						}
-
 						if tags, err := node.Properties.Get(tagPropertyStr).String(); err != nil {
 							if graph.IsErrPropertyNotFound(err) {
 								node.Properties.Set(tagPropertyStr, assetGroup.Tag)
@@ -131,6 +156,32 @@ func UpdateAssetGroupIsolationTags(ctx context.Context, db AgiData, graphDb grap
 						}
 					}
 				}
+
+				// Leave the existing members in place if a selector could not be evaluated
+				if !allResolved {
+					continue
+				}
+
+				if staleNodes, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+					return query.And(
+						query.StringContains(query.NodeProperty(tagPropertyStr), assetGroup.Tag),
+						query.Not(selectedCriteria()),
+					)
+				})); err != nil {
+					return err
+				} else {
+					for _, node := range staleNodes {
+						if tags, err := node.Properties.Get(tagPropertyStr).String(); err != nil {
+							return err
+						} else if remainingTags, removed := removeTag(tags, assetGroup.Tag); removed {
+							node.Properties.Set(tagPropertyStr, remainingTags)
+
+							if err := tx.UpdateNode(node); err != nil {
+								return err
+							}
+						}
+					}
+				}
 			}
 
 			return nil
@@ -0,0 +1,207 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package agi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/cypher/analyzer"
+	"github.com/specterops/bloodhound/cypher/frontend"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	// SelectorBinding is the binding that cypher selectors must match the selected nodes to
+	SelectorBinding = "n"
+
+	// MaxSelectorQueryComplexityWeight is the highest query complexity weight allowed for a cypher selector
+	MaxSelectorQueryComplexityWeight = 50
+)
+
+var (
+	ErrSelectorQueryTooComplex   = errors.Error("the selector query is too complex")
+	ErrSelectorQueryInvalidInput = errors.Error("the selector query must be a match pattern that binds n without a return clause")
+	ErrPropertySelectorInvalid   = errors.Error("property selectors must be of the form name=value")
+
+	propertyNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// PrepareSelectorQuery returns the cypher query that returns every node selected by the given cypher selector. The
+// selector is a match pattern, with an optional where clause, that binds the selected nodes to n.
+func PrepareSelectorQuery(selector string) (string, error) {
+	buffer := &bytes.Buffer{}
+
+	if queryModel, err := frontend.ParseCypher(frontend.DefaultCypherContext(), fmt.Sprintf("%s RETURN %s", strings.TrimSpace(selector), SelectorBinding)); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSelectorQueryInvalidInput, err)
+	} else if complexityMeasure, err := analyzer.QueryComplexity(queryModel); err != nil {
+		return "", err
+	} else if complexityMeasure.Weight > MaxSelectorQueryComplexityWeight {
+		return "", ErrSelectorQueryTooComplex
+	} else if err := frontend.NewCypherEmitter(false).Write(queryModel, buffer); err != nil {
+		return "", err
+	} else {
+		return buffer.String(), nil
+	}
+}
+
+// ParsePropertySelector returns the property name and value of a property selector such as admincount=true. Values
+// of true or false are matched as booleans and values that parse as integers are matched as integers, all other values
+// are matched as strings.
+func ParsePropertySelector(selector string) (string, any, error) {
+	if name, rawValue, found := strings.Cut(selector, "="); !found {
+		return "", nil, ErrPropertySelectorInvalid
+	} else if name = strings.TrimSpace(name); !propertyNamePattern.MatchString(name) {
+		return "", nil, ErrPropertySelectorInvalid
+	} else if rawValue = strings.TrimSpace(rawValue); rawValue == "" {
+		return "", nil, ErrPropertySelectorInvalid
+	} else if rawValue == "true" || rawValue == "false" {
+		return name, rawValue == "true", nil
+	} else if intValue, err := strconv.ParseInt(rawValue, 10, 64); err == nil {
+		return name, intValue, nil
+	} else {
+		return name, strings.Trim(rawValue, `"'`), nil
+	}
+}
+
+// ValidateAssetGroupSelector checks that a dynamic selector can be evaluated against the graph
+func ValidateAssetGroupSelector(selector model.AssetGroupSelector) error {
+	switch selector.Type() {
+	case model.AssetGroupSelectorTypeCypher:
+		_, err := PrepareSelectorQuery(selector.Selector)
+		return err
+
+	case model.AssetGroupSelectorTypeProperty:
+		_, _, err := ParsePropertySelector(selector.Selector)
+		return err
+
+	default:
+		return nil
+	}
+}
+
+func fetchSelectedEntityIDs(tx graph.Transaction, criteria graph.Criteria) ([]graph.ID, error) {
+	return ops.FetchNodeIDs(tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.KindIn(query.Node(), ad.Entity, azure.Entity),
+			criteria,
+		)
+	}))
+}
+
+func fetchCypherSelectorIDs(tx graph.Transaction, selector string) ([]graph.ID, error) {
+	var ids []graph.ID
+
+	if cypher, err := PrepareSelectorQuery(selector); err != nil {
+		return nil, err
+	} else if result := tx.Run(cypher, map[string]any{}); result.Error() != nil {
+		return nil, result.Error()
+	} else {
+		defer result.Close()
+
+		for result.Next() {
+			var node graph.Node
+
+			if err := result.Scan(&node); err != nil {
+				return nil, fmt.Errorf("binding %s must be a node: %w", SelectorBinding, err)
+			}
+
+			ids = append(ids, node.ID)
+		}
+
+		return ids, result.Error()
+	}
+}
+
+func fetchGroupMemberIDs(tx graph.Transaction, groupObjectID string) ([]graph.ID, error) {
+	if groups, err := ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Node(), ad.Group),
+			query.Equals(query.NodeProperty(common.ObjectID.String()), groupObjectID),
+		)
+	})); err != nil {
+		return nil, err
+	} else if members, err := analysis.ExpandGroupMembership(tx, groups); err != nil {
+		return nil, err
+	} else {
+		// The expansion includes the group itself which is not one of its members
+		for _, group := range groups {
+			members.Remove(group.ID)
+		}
+
+		return members.IDs(), nil
+	}
+}
+
+// resolveSelector returns the IDs of the nodes currently selected by the given dynamic selector. Distinguished names
+// are matched in upper case as they are ingested.
+func resolveSelector(tx graph.Transaction, selector model.AssetGroupSelector) ([]graph.ID, error) {
+	switch selector.Type() {
+	case model.AssetGroupSelectorTypeCypher:
+		return fetchCypherSelectorIDs(tx, selector.Selector)
+
+	case model.AssetGroupSelectorTypeDistinguishedName:
+		distinguishedName := strings.ToUpper(strings.TrimSpace(selector.Selector))
+
+		return fetchSelectedEntityIDs(tx, query.Or(
+			query.Equals(query.NodeProperty(ad.DistinguishedName.String()), distinguishedName),
+			query.StringEndsWith(query.NodeProperty(ad.DistinguishedName.String()), ","+distinguishedName),
+		))
+
+	case model.AssetGroupSelectorTypeGroupMembers:
+		return fetchGroupMemberIDs(tx, strings.TrimSpace(selector.Selector))
+
+	case model.AssetGroupSelectorTypeProperty:
+		if name, value, err := ParsePropertySelector(selector.Selector); err != nil {
+			return nil, err
+		} else {
+			return fetchSelectedEntityIDs(tx, query.Equals(query.NodeProperty(name), value))
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: %s", model.ErrSelectorTypeInvalid, selector.Type())
+	}
+}
+
+// removeTag removes the given tag from a space separated list of tags and returns true if the tag was present
+func removeTag(tags, tag string) (string, bool) {
+	var (
+		remainingTags []string
+		removed       = false
+	)
+
+	for _, existingTag := range strings.Fields(tags) {
+		if existingTag == tag {
+			removed = true
+		} else {
+			remainingTags = append(remainingTags, existingTag)
+		}
+	}
+
+	return strings.Join(remainingTags, " "), removed
+}
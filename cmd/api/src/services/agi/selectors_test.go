@@ -0,0 +1,54 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package agi_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/src/services/agi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePropertySelector(t *testing.T) {
+	cases := []struct {
+		Selector string
+		Name     string
+		Value    any
+	}{
+		{Selector: "admincount=true", Name: "admincount", Value: true},
+		{Selector: "enabled = false", Name: "enabled", Value: false},
+		{Selector: "admincount=1", Name: "admincount", Value: int64(1)},
+		{Selector: "admincount=0", Name: "admincount", Value: int64(0)},
+		{Selector: "enabled=T", Name: "enabled", Value: "T"},
+		{Selector: `name="ADMIN@TESTLAB.LOCAL"`, Name: "name", Value: "ADMIN@TESTLAB.LOCAL"},
+	}
+
+	for _, testCase := range cases {
+		name, value, err := agi.ParsePropertySelector(testCase.Selector)
+
+		require.Nil(t, err, testCase.Selector)
+		require.Equal(t, testCase.Name, name, testCase.Selector)
+		require.Equal(t, testCase.Value, value, testCase.Selector)
+	}
+}
+
+func TestParsePropertySelector_Invalid(t *testing.T) {
+	for _, selector := range []string{"admincount", "=true", "admincount="} {
+		_, _, err := agi.ParsePropertySelector(selector)
+		require.ErrorIs(t, err, agi.ErrPropertySelectorInvalid, selector)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate go run go.uber.org/mock/mockgen -copyright_file=../../../../../LICENSE.header -destination=./mocks/mock.go -package=mocks . ChokePointData
+package chokepoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/analysis/chokepoint"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// MaximumEnvironmentChokePoints is the number of the highest ranked choke points stored for each domain or tenant
+const MaximumEnvironmentChokePoints = 100
+
+type ChokePointData interface {
+	ReplaceChokePoints(chokePoints model.ChokePoints) error
+}
+
+// environment describes how the tier zero assets and the principals of a domain or tenant are found in the graph
+type environment struct {
+	Type              string
+	Kind              graph.Kind
+	EntityKind        graph.Kind
+	ScopeProperty     string
+	RelationshipKinds graph.Kinds
+	PrincipalKinds    graph.Kinds
+}
+
+func environments() []environment {
+	return []environment{{
+		Type:              model.GraphSnapshotEnvironmentActiveDirectory,
+		Kind:              ad.Domain,
+		EntityKind:        ad.Entity,
+		ScopeProperty:     ad.DomainSID.String(),
+		RelationshipKinds: ad.PathfindingRelationships(),
+		PrincipalKinds:    graph.Kinds{ad.User, ad.Computer},
+	}, {
+		Type:              model.GraphSnapshotEnvironmentAzure,
+		Kind:              azure.Tenant,
+		EntityKind:        azure.Entity,
+		ScopeProperty:     azure.TenantID.String(),
+		RelationshipKinds: azure.PathfindingRelationships(),
+		PrincipalKinds:    graph.Kinds{azure.User, azure.ServicePrincipal},
+	}}
+}
+
+// fetchTierZero returns the domain or tenant node along with every tier zero asset that belongs to it
+func (s environment) fetchTierZero(tx graph.Transaction, root *graph.Node, environmentID string) (graph.NodeSet, error) {
+	if tierZero, err := ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Node(), s.EntityKind),
+			query.Equals(query.NodeProperty(s.ScopeProperty), environmentID),
+			query.StringContains(query.NodeProperty(common.SystemTags.String()), ad.AdminTierZero),
+		)
+	})); err != nil {
+		return nil, err
+	} else {
+		tierZero.Add(root)
+		return tierZero, nil
+	}
+}
+
+func nodeObjectID(node *graph.Node) string {
+	objectID, _ := node.Properties.GetOrDefault(common.ObjectID.String(), "").String()
+	return objectID
+}
+
+func nodeName(node *graph.Node) string {
+	name, _ := node.Properties.GetOrDefault(common.Name.String(), "").String()
+	return name
+}
+
+func newChokePoint(environment environment, environmentID string, rank int, finding chokepoint.Finding) model.ChokePoint {
+	chokePoint := model.ChokePoint{
+		EnvironmentID:      environmentID,
+		EnvironmentType:    environment.Type,
+		Rank:               rank,
+		FindingType:        model.ChokePointFindingTypeNode,
+		ObjectID:           nodeObjectID(finding.Node),
+		Name:               nodeName(finding.Node),
+		Kind:               analysis.GetNodeKindDisplayLabel(finding.Node),
+		AffectedPrincipals: finding.AffectedPrincipals,
+	}
+
+	if finding.IsRelationship() {
+		chokePoint.FindingType = model.ChokePointFindingTypeRelationship
+		chokePoint.RelationshipKind = finding.Relationship.Kind.String()
+		chokePoint.TargetObjectID = nodeObjectID(finding.End)
+		chokePoint.TargetName = nodeName(finding.End)
+		chokePoint.TargetKind = analysis.GetNodeKindDisplayLabel(finding.End)
+	}
+
+	return chokePoint
+}
+
+func analyzeEnvironments(ctx context.Context, graphDB graph.Database, environment environment) (model.ChokePoints, error) {
+	var (
+		chokePoints model.ChokePoints
+		plans       = map[string]chokepoint.Plan{}
+	)
+
+	if err := graphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		if roots, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+			return query.Kind(query.Node(), environment.Kind)
+		})); err != nil {
+			return err
+		} else {
+			for _, root := range roots {
+				if environmentID := nodeObjectID(root); environmentID == "" {
+					continue
+				} else if tierZero, err := environment.fetchTierZero(tx, root, environmentID); err != nil {
+					return err
+				} else {
+					plans[environmentID] = chokepoint.Plan{
+						Roots:             tierZero,
+						RelationshipKinds: environment.RelationshipKinds,
+						PrincipalKinds:    environment.PrincipalKinds,
+						Limit:             MaximumEnvironmentChokePoints,
+					}
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for environmentID, plan := range plans {
+		if findings, err := chokepoint.Analyze(ctx, graphDB, plan); err != nil {
+			return nil, fmt.Errorf("choke point analysis of %s failed: %w", environmentID, err)
+		} else {
+			for idx, finding := range findings {
+				chokePoints = append(chokePoints, newChokePoint(environment, environmentID, idx+1, finding))
+			}
+		}
+	}
+
+	return chokePoints, nil
+}
+
+// SaveChokePoints analyzes the paths into the tier zero assets of every domain and tenant and replaces the stored choke
+// points with the highest ranked findings of each
+func SaveChokePoints(ctx context.Context, db ChokePointData, graphDB graph.Database) error {
+	log.Infof("Started Choke Point Analysis")
+	defer log.Measure(log.LevelInfo, "Successfully Completed Choke Point Analysis")()
+
+	var chokePoints model.ChokePoints
+
+	for _, environment := range environments() {
+		if environmentChokePoints, err := analyzeEnvironments(ctx, graphDB, environment); err != nil {
+			return fmt.Errorf("could not analyze %s choke points: %w", environment.Type, err)
+		} else {
+			chokePoints = append(chokePoints, environmentChokePoints...)
+		}
+	}
+
+	if err := db.ReplaceChokePoints(chokePoints); err != nil {
+		return fmt.Errorf("could not save choke points: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate go run go.uber.org/mock/mockgen -copyright_file=../../../../../LICENSE.header -destination=./mocks/mock.go -package=mocks . ExposureData
+package exposure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/specterops/bloodhound/analysis/exposure"
+	"github.com/specterops/bloodhound/dawgs/cardinality"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+type ExposureData interface {
+	CreateExposureRecords(records model.ExposureRecords) error
+}
+
+// environmentType describes how the members and the scored principals of a domain or tenant are found in the graph
+type environmentType struct {
+	Name              string
+	Kind              graph.Kind
+	EntityKind        graph.Kind
+	ScopeProperty     string
+	RelationshipKinds graph.Kinds
+	PrincipalKinds    graph.Kinds
+}
+
+func environmentTypes() []environmentType {
+	return []environmentType{{
+		Name:              model.GraphSnapshotEnvironmentActiveDirectory,
+		Kind:              ad.Domain,
+		EntityKind:        ad.Entity,
+		ScopeProperty:     ad.DomainSID.String(),
+		RelationshipKinds: ad.PathfindingRelationships(),
+		PrincipalKinds:    graph.Kinds{ad.User, ad.Computer, ad.Group},
+	}, {
+		Name:              model.GraphSnapshotEnvironmentAzure,
+		Kind:              azure.Tenant,
+		EntityKind:        azure.Entity,
+		ScopeProperty:     azure.TenantID.String(),
+		RelationshipKinds: azure.PathfindingRelationships(),
+		PrincipalKinds:    graph.Kinds{azure.User, azure.Device, azure.Group},
+	}}
+}
+
+// scoredEnvironment is a domain or tenant along with the principal nodes that are scored within it
+type scoredEnvironment struct {
+	ID         string
+	Principals graph.NodeSet
+	Scope      exposure.Environment
+}
+
+func nodeObjectID(node *graph.Node) string {
+	objectID, _ := node.Properties.GetOrDefault(common.ObjectID.String(), "").String()
+	return objectID
+}
+
+func newBitmap(ids []graph.ID) cardinality.Duplex[uint32] {
+	bitmap := cardinality.NewBitmap32()
+
+	for _, id := range ids {
+		bitmap.Add(id.Uint32())
+	}
+
+	return bitmap
+}
+
+// fetchEnvironment returns the members and the principals of the domain or tenant with the given root node
+func (s environmentType) fetchEnvironment(tx graph.Transaction, root *graph.Node, environmentID string) (scoredEnvironment, error) {
+	if memberIDs, err := ops.FetchNodeIDs(tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Node(), s.EntityKind),
+			query.Equals(query.NodeProperty(s.ScopeProperty), environmentID),
+		)
+	})); err != nil {
+		return scoredEnvironment{}, err
+	} else if principals, err := ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.KindIn(query.Node(), s.PrincipalKinds...),
+			query.Equals(query.NodeProperty(s.ScopeProperty), environmentID),
+		)
+	})); err != nil {
+		return scoredEnvironment{}, err
+	} else {
+		members := newBitmap(memberIDs)
+		members.Add(root.ID.Uint32())
+
+		return scoredEnvironment{
+			ID:         environmentID,
+			Principals: principals,
+			Scope: exposure.Environment{
+				Members:    members,
+				Principals: newBitmap(principals.IDs()),
+			},
+		}, nil
+	}
+}
+
+func (s environmentType) fetchEnvironments(ctx context.Context, graphDB graph.Database) ([]scoredEnvironment, error) {
+	var environments []scoredEnvironment
+
+	return environments, graphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		if roots, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+			return query.Kind(query.Node(), s.Kind)
+		})); err != nil {
+			return err
+		} else {
+			for _, root := range roots {
+				if environmentID := nodeObjectID(root); environmentID == "" {
+					continue
+				} else if environment, err := s.fetchEnvironment(tx, root, environmentID); err != nil {
+					return err
+				} else {
+					environments = append(environments, environment)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// scoreEnvironments calculates the exposure of every principal of the given environment type and stores it on the
+// principal's node. A history record is returned for each principal whose exposure changed.
+func scoreEnvironments(ctx context.Context, graphDB graph.Database, environmentType environmentType) (model.ExposureRecords, error) {
+	var records model.ExposureRecords
+
+	if environments, err := environmentType.fetchEnvironments(ctx, graphDB); err != nil {
+		return nil, err
+	} else if len(environments) == 0 {
+		return nil, nil
+	} else {
+		scopes := make([]exposure.Environment, len(environments))
+
+		for idx, environment := range environments {
+			scopes[idx] = environment.Scope
+		}
+
+		if exposures, err := exposure.Calculate(ctx, graphDB, environmentType.RelationshipKinds, scopes); err != nil {
+			return nil, err
+		} else if err := graphDB.WriteTransaction(ctx, func(tx graph.Transaction) error {
+			for _, environment := range environments {
+				for _, principal := range environment.Principals {
+					nextExposure := exposures[principal.ID]
+
+					// Only principals whose exposure changed are written to keep the history free of duplicates
+					if previousExposure, scored := exposure.NodeExposure(principal); scored && previousExposure == nextExposure {
+						continue
+					}
+
+					nextExposure.SetProperties(principal.Properties)
+
+					if err := tx.UpdateNode(principal); err != nil {
+						return err
+					}
+
+					records = append(records, model.ExposureRecord{
+						ObjectID:         nodeObjectID(principal),
+						EnvironmentID:    environment.ID,
+						InboundExposure:  nextExposure.Inbound,
+						OutboundExposure: nextExposure.Outbound,
+					})
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// SaveExposure scores the exposure of the principals of every domain and tenant, stores the scores as node properties
+// and appends every changed score to the exposure history
+func SaveExposure(ctx context.Context, db ExposureData, graphDB graph.Database) error {
+	log.Infof("Started Exposure Scoring")
+	defer log.Measure(log.LevelInfo, "Successfully Completed Exposure Scoring")()
+
+	var records model.ExposureRecords
+
+	for _, environmentType := range environmentTypes() {
+		if environmentRecords, err := scoreEnvironments(ctx, graphDB, environmentType); err != nil {
+			return fmt.Errorf("could not score %s exposure: %w", environmentType.Name, err)
+		} else {
+			records = append(records, environmentRecords...)
+		}
+	}
+
+	if err := db.CreateExposureRecords(records); err != nil {
+		return fmt.Errorf("could not save exposure history: %w", err)
+	}
+
+	return nil
+}
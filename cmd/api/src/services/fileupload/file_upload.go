@@ -18,12 +18,17 @@
 package fileupload
 
 import (
+	"archive/zip"
 	"fmt"
 	"io"
+	"mime"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/specterops/bloodhound/errors"
 	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/mediatypes"
 	"github.com/specterops/bloodhound/src/model"
 )
 
@@ -31,12 +36,18 @@ const (
 	jobActivityTimeout = time.Minute * 20
 )
 
+const (
+	ErrInvalidContentType = errors.Error("content type must be application/json or application/zip")
+	ErrInvalidZipFile     = errors.Error("file is not a valid zip archive")
+)
+
 type FileUploadData interface {
 	CreateFileUploadJob(job model.FileUploadJob) (model.FileUploadJob, error)
 	UpdateFileUploadJob(job model.FileUploadJob) error
 	GetFileUploadJob(id int64) (model.FileUploadJob, error)
 	GetAllFileUploadJobs(skip int, limit int, order string, filter model.SQLFilter) ([]model.FileUploadJob, int, error)
 	GetFileUploadJobsWithStatus(status model.JobStatus) ([]model.FileUploadJob, error)
+	GetIngestFileReportsForJob(jobID int64) (model.IngestFileReports, error)
 }
 
 func ProcessStaleFileUploadJobs(db FileUploadData) {
@@ -82,6 +93,19 @@ func GetFileUploadJobByID(db FileUploadData, jobID int64) (model.FileUploadJob,
 	return db.GetFileUploadJob(jobID)
 }
 
+func GetIngestFileReports(db FileUploadData, jobID int64) (model.IngestFileReports, error) {
+	return db.GetIngestFileReportsForJob(jobID)
+}
+
+// ParseFileName returns the file name given in the Content-Disposition header of a file upload request, if any
+func ParseFileName(contentDisposition string) string {
+	if _, params, err := mime.ParseMediaType(contentDisposition); err != nil || params["filename"] == "" {
+		return ""
+	} else {
+		return filepath.Base(params["filename"])
+	}
+}
+
 func SaveToTempFile(location string, fileData io.ReadCloser) (string, error) {
 	if tempFile, err := os.CreateTemp(location, "bh"); err != nil {
 		return "", fmt.Errorf("error creating temp file: %w", err)
@@ -93,6 +117,46 @@ func SaveToTempFile(location string, fileData io.ReadCloser) (string, error) {
 	}
 }
 
+// ParseFileType maps the content type of a file upload request to the type of ingest file it carries. Requests without
+// a content type are treated as JSON for compatibility with older collectors.
+func ParseFileType(contentType string) (model.FileType, error) {
+	if contentType == "" {
+		return model.FileTypeJson, nil
+	} else if mediaType, _, err := mime.ParseMediaType(contentType); err != nil {
+		return model.FileTypeJson, ErrInvalidContentType
+	} else {
+		switch mediaType {
+		case mediatypes.ApplicationJson.String():
+			return model.FileTypeJson, nil
+
+		case mediatypes.ApplicationZip.String(), "application/x-zip-compressed", "application/zip-compressed":
+			return model.FileTypeZip, nil
+
+		default:
+			return model.FileTypeJson, ErrInvalidContentType
+		}
+	}
+}
+
+// SaveIngestFile saves the request body to a temp file. Zip archives are checked for a readable central directory
+// before being accepted so that corrupt uploads are rejected up front instead of failing later during ingest.
+func SaveIngestFile(location string, fileData io.ReadCloser, fileType model.FileType) (string, error) {
+	if fileName, err := SaveToTempFile(location, fileData); err != nil {
+		return "", err
+	} else if fileType != model.FileTypeZip {
+		return fileName, nil
+	} else if archive, err := zip.OpenReader(fileName); err != nil {
+		if err := os.Remove(fileName); err != nil {
+			log.Errorf("Error removing invalid zip file %s: %v", fileName, err)
+		}
+
+		return "", fmt.Errorf("%w: %v", ErrInvalidZipFile, err)
+	} else {
+		archive.Close()
+		return fileName, nil
+	}
+}
+
 func TouchFileUploadJobLastIngest(db FileUploadData, fileUploadJob model.FileUploadJob) error {
 	fileUploadJob.LastIngest = time.Now().UTC()
 	return db.UpdateFileUploadJob(fileUploadJob)
@@ -111,6 +111,21 @@ func (mr *MockFileUploadDataMockRecorder) GetFileUploadJobsWithStatus(arg0 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUploadJobsWithStatus", reflect.TypeOf((*MockFileUploadData)(nil).GetFileUploadJobsWithStatus), arg0)
 }
 
+// GetIngestFileReportsForJob mocks base method.
+func (m *MockFileUploadData) GetIngestFileReportsForJob(arg0 int64) (model.IngestFileReports, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestFileReportsForJob", arg0)
+	ret0, _ := ret[0].(model.IngestFileReports)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestFileReportsForJob indicates an expected call of GetIngestFileReportsForJob.
+func (mr *MockFileUploadDataMockRecorder) GetIngestFileReportsForJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestFileReportsForJob", reflect.TypeOf((*MockFileUploadData)(nil).GetIngestFileReportsForJob), arg0)
+}
+
 // UpdateFileUploadJob mocks base method.
 func (m *MockFileUploadData) UpdateFileUploadJob(arg0 model.FileUploadJob) error {
 	m.ctrl.T.Helper()
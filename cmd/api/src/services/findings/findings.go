@@ -0,0 +1,111 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate go run go.uber.org/mock/mockgen -copyright_file=../../../../../LICENSE.header -destination=./mocks/mock.go -package=mocks . FindingData
+package findings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/specterops/bloodhound/analysis"
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+type FindingData interface {
+	SaveAttackPathFindings(environmentID string, findings model.AttackPathFindings, observedAt time.Time) error
+}
+
+func nodeObjectID(node *graph.Node) string {
+	objectID, _ := node.Properties.GetOrDefault(common.ObjectID.String(), "").String()
+	return objectID
+}
+
+func nodeName(node *graph.Node) string {
+	name, _ := node.Properties.GetOrDefault(common.Name.String(), "").String()
+	return name
+}
+
+// detectDomainFindings runs every detection of the finding catalogue against the given domain
+func detectDomainFindings(tx graph.Transaction, domain *graph.Node) (model.AttackPathFindings, error) {
+	var findings model.AttackPathFindings
+
+	for _, detection := range adAnalysis.FindingDetections() {
+		if principals, err := detection.Fetch(tx, domain); err != nil {
+			return nil, fmt.Errorf("detection of %s failed: %w", detection.FindingType, err)
+		} else {
+			for _, principal := range principals {
+				findings = append(findings, model.AttackPathFinding{
+					FindingType: detection.FindingType,
+					ObjectID:    nodeObjectID(principal),
+					Name:        nodeName(principal),
+					Kind:        analysis.GetNodeKindDisplayLabel(principal),
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// SaveAttackPathFindings runs the finding catalogue against every domain and records the detected findings of each
+func SaveAttackPathFindings(ctx context.Context, db FindingData, graphDB graph.Database) error {
+	log.Infof("Started Attack Path Findings Detection")
+	defer log.Measure(log.LevelInfo, "Successfully Completed Attack Path Findings Detection")()
+
+	var (
+		observedAt     = time.Now().UTC()
+		domainFindings = map[string]model.AttackPathFindings{}
+	)
+
+	if err := graphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		if domains, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+			return query.Kind(query.Node(), ad.Domain)
+		})); err != nil {
+			return err
+		} else {
+			for _, domain := range domains {
+				if domainSID, err := domain.Properties.Get(ad.DomainSID.String()).String(); err != nil || domainSID == "" {
+					continue
+				} else if findings, err := detectDomainFindings(tx, domain); err != nil {
+					return fmt.Errorf("could not detect attack path findings of %s: %w", domainSID, err)
+				} else {
+					domainFindings[domainSID] = findings
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for domainSID, findings := range domainFindings {
+		if err := db.SaveAttackPathFindings(domainSID, findings, observedAt); err != nil {
+			return fmt.Errorf("could not save attack path findings of %s: %w", domainSID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,435 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package grapharchive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/errors"
+)
+
+// A graph archive is a gzip compressed stream of JSON records, one per line. The first record is the header, which
+// names the format and its version, followed by the schema record, every node record, every relationship record and
+// finally the footer record, which carries the number of nodes and relationships written. Relationship records always
+// follow all node records so that an archive can be imported in a single pass.
+const (
+	FormatName    = "bloodhound-graph-archive"
+	FormatVersion = 1
+
+	// FileExtension is the file extension used for graph archives
+	FileExtension = ".bhgraph.gz"
+
+	// dateTimeKey tags property values that are timestamps. JSON has no timestamp type and timestamps would otherwise
+	// be imported as strings.
+	dateTimeKey = "$datetime"
+)
+
+const (
+	ErrUnsupportedArchive = errors.Error("not a supported graph archive")
+	ErrTruncatedArchive   = errors.Error("graph archive ended before its footer")
+	ErrMalformedArchive   = errors.Error("graph archive records are out of order")
+)
+
+type RecordType string
+
+const (
+	RecordTypeHeader       RecordType = "header"
+	RecordTypeSchema       RecordType = "schema"
+	RecordTypeNode         RecordType = "node"
+	RecordTypeRelationship RecordType = "relationship"
+	RecordTypeFooter       RecordType = "footer"
+)
+
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SchemaIndex is a single property index or constraint of the schema returned by graph.Database.FetchSchema
+type SchemaIndex struct {
+	Kind      string `json:"kind"`
+	Property  string `json:"property"`
+	Name      string `json:"name"`
+	IndexType string `json:"index_type"`
+	Unique    bool   `json:"unique"`
+}
+
+type Node struct {
+	ID         graph.ID       `json:"id"`
+	Kinds      []string       `json:"kinds"`
+	Properties map[string]any `json:"properties"`
+}
+
+type Relationship struct {
+	ID         graph.ID       `json:"id"`
+	StartID    graph.ID       `json:"start_id"`
+	EndID      graph.ID       `json:"end_id"`
+	Kind       string         `json:"kind"`
+	Properties map[string]any `json:"properties"`
+}
+
+type Footer struct {
+	Nodes         int64 `json:"nodes"`
+	Relationships int64 `json:"relationships"`
+}
+
+type Record struct {
+	Type         RecordType    `json:"type"`
+	Header       *Header       `json:"header,omitempty"`
+	Schema       []SchemaIndex `json:"schema,omitempty"`
+	Node         *Node         `json:"node,omitempty"`
+	Relationship *Relationship `json:"relationship,omitempty"`
+	Footer       *Footer       `json:"footer,omitempty"`
+}
+
+func indexTypeFromString(value string) graph.IndexType {
+	switch value {
+	case graph.BTreeIndex.String():
+		return graph.BTreeIndex
+	case graph.FullTextSearchIndex.String():
+		return graph.FullTextSearchIndex
+	default:
+		return graph.UnsupportedIndex
+	}
+}
+
+// NewSchemaIndexes flattens the given schema into a list of schema indexes
+func NewSchemaIndexes(schema *graph.Schema) []SchemaIndex {
+	var indexes []SchemaIndex
+
+	for kind, kindSchema := range schema.Kinds {
+		for property, index := range kindSchema.PropertyIndices {
+			indexes = append(indexes, SchemaIndex{
+				Kind:      kind.String(),
+				Property:  property,
+				Name:      index.Name,
+				IndexType: index.IndexType.String(),
+			})
+		}
+
+		for property, constraint := range kindSchema.PropertyConstraints {
+			indexes = append(indexes, SchemaIndex{
+				Kind:      kind.String(),
+				Property:  property,
+				Name:      constraint.Name,
+				IndexType: constraint.IndexType.String(),
+				Unique:    true,
+			})
+		}
+	}
+
+	return indexes
+}
+
+// MergeSchemaIndexes adds the given schema indexes to the schema. Existing indexes and constraints take precedence.
+func MergeSchemaIndexes(schema *graph.Schema, indexes []SchemaIndex) {
+	for _, index := range indexes {
+		kindSchema := schema.EnsureKind(graph.StringKind(index.Kind))
+
+		if index.Unique {
+			if _, found := kindSchema.PropertyConstraints[index.Property]; !found {
+				kindSchema.Constraint(index.Property, index.Name, indexTypeFromString(index.IndexType))
+			}
+		} else if _, found := kindSchema.PropertyIndices[index.Property]; !found {
+			kindSchema.Index(index.Property, index.Name, indexTypeFromString(index.IndexType))
+		}
+	}
+}
+
+// encodeValue tags timestamps so that they survive the round trip through JSON
+func encodeValue(value any) any {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return map[string]any{
+			dateTimeKey: typedValue.UTC().Format(time.RFC3339Nano),
+		}
+
+	case []time.Time:
+		encoded := make([]any, len(typedValue))
+
+		for idx, nextValue := range typedValue {
+			encoded[idx] = encodeValue(nextValue)
+		}
+
+		return encoded
+
+	case []any:
+		encoded := make([]any, len(typedValue))
+
+		for idx, nextValue := range typedValue {
+			encoded[idx] = encodeValue(nextValue)
+		}
+
+		return encoded
+
+	default:
+		return value
+	}
+}
+
+func encodeProperties(properties *graph.Properties) map[string]any {
+	encoded := map[string]any{}
+
+	if properties != nil {
+		for key, value := range properties.Map {
+			encoded[key] = encodeValue(value)
+		}
+	}
+
+	return encoded
+}
+
+// decodeValue reverses encodeValue. Numbers are decoded as int64 values when they are integral and as float64 values
+// otherwise.
+func decodeValue(value any) (any, error) {
+	switch typedValue := value.(type) {
+	case json.Number:
+		if intValue, err := typedValue.Int64(); err == nil {
+			return intValue, nil
+		}
+
+		return typedValue.Float64()
+
+	case map[string]any:
+		if rawDateTime, found := typedValue[dateTimeKey]; found && len(typedValue) == 1 {
+			if dateTimeStr, typeOK := rawDateTime.(string); !typeOK {
+				return nil, fmt.Errorf("expected a string timestamp but got %T", rawDateTime)
+			} else {
+				return time.Parse(time.RFC3339Nano, dateTimeStr)
+			}
+		}
+
+		decoded := make(map[string]any, len(typedValue))
+
+		for key, nextValue := range typedValue {
+			if decodedValue, err := decodeValue(nextValue); err != nil {
+				return nil, err
+			} else {
+				decoded[key] = decodedValue
+			}
+		}
+
+		return decoded, nil
+
+	case []any:
+		decoded := make([]any, len(typedValue))
+
+		for idx, nextValue := range typedValue {
+			if decodedValue, err := decodeValue(nextValue); err != nil {
+				return nil, err
+			} else {
+				decoded[idx] = decodedValue
+			}
+		}
+
+		return decoded, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func decodeProperties(properties map[string]any) (*graph.Properties, error) {
+	decoded := make(map[string]any, len(properties))
+
+	for key, value := range properties {
+		if decodedValue, err := decodeValue(value); err != nil {
+			return nil, fmt.Errorf("invalid value for property %s: %w", key, err)
+		} else {
+			decoded[key] = decodedValue
+		}
+	}
+
+	return graph.AsProperties(decoded), nil
+}
+
+// Writer writes the records of a graph archive
+type Writer struct {
+	compressor *gzip.Writer
+	encoder    *json.Encoder
+	footer     Footer
+}
+
+// NewWriter writes the archive header and schema to the given writer and returns a Writer for the graph content
+func NewWriter(writer io.Writer, schema *graph.Schema) (*Writer, error) {
+	compressor := gzip.NewWriter(writer)
+
+	archiveWriter := &Writer{
+		compressor: compressor,
+		encoder:    json.NewEncoder(compressor),
+	}
+
+	if err := archiveWriter.encoder.Encode(Record{
+		Type: RecordTypeHeader,
+		Header: &Header{
+			Format:    FormatName,
+			Version:   FormatVersion,
+			CreatedAt: time.Now().UTC(),
+		},
+	}); err != nil {
+		return nil, err
+	} else if err := archiveWriter.encoder.Encode(Record{
+		Type:   RecordTypeSchema,
+		Schema: NewSchemaIndexes(schema),
+	}); err != nil {
+		return nil, err
+	}
+
+	return archiveWriter, nil
+}
+
+func (s *Writer) WriteNode(node *graph.Node) error {
+	s.footer.Nodes++
+
+	return s.encoder.Encode(Record{
+		Type: RecordTypeNode,
+		Node: &Node{
+			ID:         node.ID,
+			Kinds:      node.Kinds.Strings(),
+			Properties: encodeProperties(node.Properties),
+		},
+	})
+}
+
+func (s *Writer) WriteRelationship(relationship *graph.Relationship) error {
+	s.footer.Relationships++
+
+	return s.encoder.Encode(Record{
+		Type: RecordTypeRelationship,
+		Relationship: &Relationship{
+			ID:         relationship.ID,
+			StartID:    relationship.StartID,
+			EndID:      relationship.EndID,
+			Kind:       relationship.Kind.String(),
+			Properties: encodeProperties(relationship.Properties),
+		},
+	})
+}
+
+// Close writes the footer and flushes the compressed stream. It does not close the underlying writer.
+func (s *Writer) Close() (Footer, error) {
+	if err := s.encoder.Encode(Record{
+		Type:   RecordTypeFooter,
+		Footer: &s.footer,
+	}); err != nil {
+		return s.footer, err
+	}
+
+	return s.footer, s.compressor.Close()
+}
+
+// Reader reads the records of a graph archive
+type Reader struct {
+	decompressor *gzip.Reader
+	decoder      *json.Decoder
+	header       Header
+	schema       []SchemaIndex
+}
+
+// NewReader reads and validates the header and schema of the graph archive in the given reader
+func NewReader(reader io.Reader) (*Reader, error) {
+	if decompressor, err := gzip.NewReader(bufio.NewReader(reader)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+	} else {
+		archiveReader := &Reader{
+			decompressor: decompressor,
+			decoder:      json.NewDecoder(decompressor),
+		}
+
+		archiveReader.decoder.UseNumber()
+
+		if header, err := archiveReader.next(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+		} else if header.Type != RecordTypeHeader || header.Header == nil || header.Header.Format != FormatName {
+			return nil, ErrUnsupportedArchive
+		} else if header.Header.Version > FormatVersion {
+			return nil, fmt.Errorf("%w: version %d is newer than the supported version %d", ErrUnsupportedArchive, header.Header.Version, FormatVersion)
+		} else if schema, err := archiveReader.next(); err != nil {
+			return nil, err
+		} else if schema.Type != RecordTypeSchema {
+			return nil, ErrMalformedArchive
+		} else {
+			archiveReader.header = *header.Header
+			archiveReader.schema = schema.Schema
+
+			return archiveReader, nil
+		}
+	}
+}
+
+func (s *Reader) next() (Record, error) {
+	var record Record
+
+	if err := s.decoder.Decode(&record); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return record, ErrTruncatedArchive
+		}
+
+		return record, err
+	}
+
+	return record, nil
+}
+
+func (s *Reader) Header() Header {
+	return s.header
+}
+
+func (s *Reader) Schema() []SchemaIndex {
+	return s.schema
+}
+
+// Next returns the next node, relationship or footer record of the archive. Property values of node and relationship
+// records are decoded into their graph representation.
+func (s *Reader) Next() (Record, error) {
+	if record, err := s.next(); err != nil {
+		return record, err
+	} else {
+		switch record.Type {
+		case RecordTypeNode:
+			if record.Node == nil {
+				return record, ErrMalformedArchive
+			}
+
+		case RecordTypeRelationship:
+			if record.Relationship == nil {
+				return record, ErrMalformedArchive
+			}
+
+		case RecordTypeFooter:
+			if record.Footer == nil {
+				return record, ErrMalformedArchive
+			}
+
+		default:
+			return record, ErrMalformedArchive
+		}
+
+		return record, nil
+	}
+}
+
+func (s *Reader) Close() error {
+	return s.decompressor.Close()
+}
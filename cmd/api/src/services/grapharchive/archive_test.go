@@ -0,0 +1,175 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package grapharchive_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/src/services/grapharchive"
+	"github.com/stretchr/testify/require"
+)
+
+func fetchNodeByObjectID(t *testing.T, graphDB graph.Database, objectID string) *graph.Node {
+	var node *graph.Node
+
+	require.Nil(t, graphDB.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		fetchedNode, err := tx.Nodes().Filterf(func() graph.Criteria {
+			return query.Equals(query.NodeProperty(common.ObjectID.String()), objectID)
+		}).First()
+
+		node = fetchedNode
+		return err
+	}))
+
+	return node
+}
+
+func TestExportImport(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		source        = memory.NewDatabase()
+		target        = memory.NewDatabase()
+		lastLogon     = time.Date(2023, 10, 2, 14, 30, 0, 0, time.UTC)
+		archiveBuffer = &bytes.Buffer{}
+		schema        = graph.NewSchema()
+	)
+
+	schema.EnsureKind(ad.Entity).ConstrainProperty(common.ObjectID.String(), graph.BTreeIndex)
+	schema.EnsureKind(ad.User).IndexProperty(common.Name.String(), graph.FullTextSearchIndex)
+	require.Nil(t, source.AssertSchema(ctx, schema))
+
+	require.Nil(t, source.WriteTransaction(ctx, func(tx graph.Transaction) error {
+		if user, err := tx.CreateNode(graph.AsProperties(map[string]any{
+			common.ObjectID.String(): "S-1-5-21-1-1104",
+			common.Name.String():     "USER@TESTLAB.LOCAL",
+			ad.LastLogon.String():    lastLogon,
+			ad.AdminCount.String():   true,
+			"pwdlastset":             1696257000.5,
+			"serviceprincipalnames":  []string{"HTTP/web.testlab.local"},
+		}), ad.Entity, ad.User); err != nil {
+			return err
+		} else if group, err := tx.CreateNode(graph.AsProperties(graph.PropertyMap{
+			common.ObjectID: "S-1-5-21-1-512",
+			common.Name:     "DOMAIN ADMINS@TESTLAB.LOCAL",
+		}), ad.Entity, ad.Group); err != nil {
+			return err
+		} else if orphan, err := tx.CreateNode(graph.NewProperties(), ad.Entity); err != nil {
+			return err
+		} else if _, err := tx.CreateRelationshipByIDs(user.ID, group.ID, ad.MemberOf, graph.AsProperties(graph.PropertyMap{
+			common.LastSeen: lastLogon,
+		})); err != nil {
+			return err
+		} else {
+			_, err := tx.CreateRelationshipByIDs(orphan.ID, group.ID, ad.MemberOf, graph.NewProperties())
+			return err
+		}
+	}))
+
+	exportResult, err := grapharchive.Export(ctx, source, archiveBuffer)
+	require.Nil(t, err)
+	require.Equal(t, grapharchive.ExportResult{Nodes: 3, Relationships: 2}, exportResult)
+
+	// Import twice to make sure that identities are preserved instead of duplicated
+	archive := archiveBuffer.Bytes()
+
+	for iteration := 0; iteration < 2; iteration++ {
+		importResult, err := grapharchive.Import(ctx, target, bytes.NewReader(archive))
+		require.Nil(t, err)
+		require.Equal(t, grapharchive.ImportResult{Nodes: 3, Relationships: 2, SkippedRelationships: 1}, importResult)
+	}
+
+	user := fetchNodeByObjectID(t, target, "S-1-5-21-1-1104")
+	require.True(t, user.Kinds.ContainsOneOf(ad.User))
+	require.True(t, user.Kinds.ContainsOneOf(ad.Entity))
+
+	actualLastLogon, err := user.Properties.Get(ad.LastLogon.String()).Time()
+	require.Nil(t, err)
+	require.True(t, lastLogon.Equal(actualLastLogon))
+
+	adminCount, err := user.Properties.Get(ad.AdminCount.String()).Bool()
+	require.Nil(t, err)
+	require.True(t, adminCount)
+
+	pwdLastSetValue, err := user.Properties.Get("pwdlastset").Float64()
+	require.Nil(t, err)
+	require.Equal(t, 1696257000.5, pwdLastSetValue)
+
+	// Lists are decoded the same way the neo4j driver returns them
+	require.Equal(t, []any{"HTTP/web.testlab.local"}, user.Properties.Get("serviceprincipalnames").Any())
+
+	require.Nil(t, target.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		if numNodes, err := tx.Nodes().Count(); err != nil {
+			return err
+		} else if numRelationships, err := tx.Relationships().Filterf(func() graph.Criteria {
+			return query.And(
+				query.Equals(query.StartProperty(common.ObjectID.String()), "S-1-5-21-1-1104"),
+				query.Kind(query.Relationship(), ad.MemberOf),
+			)
+		}).Count(); err != nil {
+			return err
+		} else {
+			// The orphan node has no object ID and is created again on every import
+			require.Equal(t, int64(4), numNodes)
+			require.Equal(t, int64(1), numRelationships)
+		}
+
+		return nil
+	}))
+
+	targetSchema, err := target.FetchSchema(ctx)
+	require.Nil(t, err)
+	require.Contains(t, targetSchema.Kinds, ad.User)
+	require.Equal(t, graph.FullTextSearchIndex, targetSchema.Kinds[ad.User].PropertyIndices[common.Name.String()].IndexType)
+}
+
+func TestImportRejectsUnsupportedArchives(t *testing.T) {
+	_, err := grapharchive.Import(context.Background(), memory.NewDatabase(), bytes.NewReader([]byte("not an archive")))
+	require.ErrorIs(t, err, grapharchive.ErrUnsupportedArchive)
+}
+
+func TestImportRejectsTruncatedArchives(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		source        = memory.NewDatabase()
+		archiveBuffer = &bytes.Buffer{}
+	)
+
+	require.Nil(t, source.WriteTransaction(ctx, func(tx graph.Transaction) error {
+		_, err := tx.CreateNode(graph.AsProperties(graph.PropertyMap{
+			common.ObjectID: "S-1-5-21-1",
+		}), ad.Entity, ad.Domain)
+
+		return err
+	}))
+
+	_, err := grapharchive.Export(ctx, source, archiveBuffer)
+	require.Nil(t, err)
+
+	// Cut the archive off before the gzip trailer
+	archive := archiveBuffer.Bytes()
+
+	_, err = grapharchive.Import(ctx, memory.NewDatabase(), bytes.NewReader(archive[:len(archive)-16]))
+	require.ErrorIs(t, err, grapharchive.ErrTruncatedArchive)
+}
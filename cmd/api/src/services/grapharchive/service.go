@@ -0,0 +1,227 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package grapharchive
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+)
+
+// ExportResult describes the content written to a graph archive
+type ExportResult struct {
+	Nodes         int64 `json:"nodes"`
+	Relationships int64 `json:"relationships"`
+}
+
+// ImportResult describes the content read from a graph archive. Relationships are skipped when either of their
+// nodes lacks an object ID, since there is then no way to identify the node once it has been imported.
+type ImportResult struct {
+	Nodes                int64 `json:"nodes"`
+	Relationships        int64 `json:"relationships"`
+	SkippedRelationships int64 `json:"skipped_relationships"`
+}
+
+// Export writes the schema, every node and every relationship of the given graph database to a graph archive
+func Export(ctx context.Context, graphDB graph.Database, writer io.Writer) (ExportResult, error) {
+	if schema, err := graphDB.FetchSchema(ctx); err != nil {
+		return ExportResult{}, fmt.Errorf("failed fetching graph schema: %w", err)
+	} else if archiveWriter, err := NewWriter(writer, schema); err != nil {
+		return ExportResult{}, err
+	} else if err := graphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		if err := tx.Nodes().Fetch(func(cursor graph.Cursor[*graph.Node]) error {
+			for node := range cursor.Chan() {
+				if err := archiveWriter.WriteNode(node); err != nil {
+					return err
+				}
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return fmt.Errorf("failed exporting nodes: %w", err)
+		}
+
+		if err := tx.Relationships().Fetch(func(cursor graph.Cursor[*graph.Relationship]) error {
+			for relationship := range cursor.Chan() {
+				if err := archiveWriter.WriteRelationship(relationship); err != nil {
+					return err
+				}
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return fmt.Errorf("failed exporting relationships: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return ExportResult{}, err
+	} else if footer, err := archiveWriter.Close(); err != nil {
+		return ExportResult{}, err
+	} else {
+		return ExportResult{
+			Nodes:         footer.Nodes,
+			Relationships: footer.Relationships,
+		}, nil
+	}
+}
+
+// nodeIdentity is the identity an archived node is merged on
+type nodeIdentity struct {
+	kind     graph.Kind
+	objectID string
+}
+
+func (s nodeIdentity) node() *graph.Node {
+	return graph.PrepareNode(graph.AsProperties(graph.PropertyMap{
+		common.ObjectID: s.objectID,
+	}), s.kind)
+}
+
+// identityKind selects the kind a node is merged on. Base entity kinds are preferred since ingest merges on them.
+func identityKind(kinds graph.Kinds) graph.Kind {
+	if kinds.ContainsOneOf(ad.Entity) {
+		return ad.Entity
+	} else if kinds.ContainsOneOf(azure.Entity) {
+		return azure.Entity
+	} else if len(kinds) > 0 {
+		return kinds[0]
+	}
+
+	return nil
+}
+
+// Import reads the graph archive from the given reader into the given graph database. Nodes are merged on their
+// object ID the same way ingest merges them, which preserves the identity of every node and relationship across
+// instances and makes importing the same archive more than once safe.
+func Import(ctx context.Context, graphDB graph.Database, reader io.Reader) (ImportResult, error) {
+	var (
+		result     ImportResult
+		identities = map[graph.ID]nodeIdentity{}
+		nextRecord Record
+	)
+
+	archiveReader, err := NewReader(reader)
+	if err != nil {
+		return result, err
+	}
+
+	defer archiveReader.Close()
+
+	if schema, err := graphDB.FetchSchema(ctx); err != nil {
+		return result, fmt.Errorf("failed fetching graph schema: %w", err)
+	} else {
+		MergeSchemaIndexes(schema, archiveReader.Schema())
+
+		if err := graphDB.AssertSchema(ctx, schema); err != nil {
+			return result, fmt.Errorf("failed asserting graph schema: %w", err)
+		}
+	}
+
+	// Node records always precede relationship records so nodes are imported first
+	if err := graphDB.BatchOperation(ctx, func(batch graph.Batch) error {
+		for {
+			if nextRecord, err = archiveReader.Next(); err != nil {
+				return err
+			} else if nextRecord.Type != RecordTypeNode {
+				return nil
+			} else if properties, err := decodeProperties(nextRecord.Node.Properties); err != nil {
+				return fmt.Errorf("invalid node %d: %w", nextRecord.Node.ID, err)
+			} else {
+				var (
+					kinds       = graph.StringsToKinds(nextRecord.Node.Kinds)
+					objectID, _ = properties.GetOrDefault(common.ObjectID.String(), "").String()
+				)
+
+				result.Nodes++
+
+				if kind := identityKind(kinds); kind == nil || objectID == "" {
+					if err := batch.CreateNode(properties, kinds...); err != nil {
+						return err
+					}
+				} else {
+					identities[nextRecord.Node.ID] = nodeIdentity{
+						kind:     kind,
+						objectID: objectID,
+					}
+
+					if err := batch.UpdateNodeBy(graph.NodeUpdate{
+						Node:               graph.PrepareNode(properties, kinds...),
+						IdentityKind:       kind,
+						IdentityProperties: []string{common.ObjectID.String()},
+					}); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}); err != nil {
+		return result, fmt.Errorf("failed importing nodes: %w", err)
+	}
+
+	if err := graphDB.BatchOperation(ctx, func(batch graph.Batch) error {
+		for nextRecord.Type == RecordTypeRelationship {
+			archivedRelationship := nextRecord.Relationship
+			result.Relationships++
+
+			if properties, err := decodeProperties(archivedRelationship.Properties); err != nil {
+				return fmt.Errorf("invalid relationship %d: %w", archivedRelationship.ID, err)
+			} else if start, found := identities[archivedRelationship.StartID]; !found {
+				result.SkippedRelationships++
+			} else if end, found := identities[archivedRelationship.EndID]; !found {
+				result.SkippedRelationships++
+			} else if err := batch.UpdateRelationshipBy(graph.RelationshipUpdate{
+				Relationship:            graph.PrepareRelationship(properties, graph.StringKind(archivedRelationship.Kind)),
+				Start:                   start.node(),
+				StartIdentityKind:       start.kind,
+				StartIdentityProperties: []string{common.ObjectID.String()},
+				End:                     end.node(),
+				EndIdentityKind:         end.kind,
+				EndIdentityProperties:   []string{common.ObjectID.String()},
+			}); err != nil {
+				return err
+			}
+
+			if nextRecord, err = archiveReader.Next(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return result, fmt.Errorf("failed importing relationships: %w", err)
+	}
+
+	if nextRecord.Type != RecordTypeFooter {
+		return result, ErrMalformedArchive
+	} else if nextRecord.Footer.Nodes != result.Nodes || nextRecord.Footer.Relationships != result.Relationships {
+		return result, fmt.Errorf("%w: expected %d nodes and %d relationships but read %d nodes and %d relationships",
+			ErrTruncatedArchive, nextRecord.Footer.Nodes, nextRecord.Footer.Relationships, result.Nodes, result.Relationships)
+	}
+
+	if result.SkippedRelationships > 0 {
+		log.Warnf("Skipped %d archived relationships attached to nodes without an object ID", result.SkippedRelationships)
+	}
+
+	return result, nil
+}
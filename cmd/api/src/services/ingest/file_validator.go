@@ -0,0 +1,173 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/database/types"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// FileValidator validates the elements of a single ingest file against the schema of its data type and collects the
+// outcome into a model.IngestFileReport
+type FileValidator struct {
+	report       model.IngestFileReport
+	schema       *Schema
+	kind         string
+	rejected     bool
+	elementIndex int
+	lastElement  any
+	objectCounts map[string]int
+	errors       []model.IngestValidationError
+}
+
+func NewFileValidator(fileUploadJobID int64, fileName string) *FileValidator {
+	return &FileValidator{
+		report: model.IngestFileReport{
+			FileUploadJobID: fileUploadJobID,
+			FileName:        fileName,
+		},
+		elementIndex: -1,
+		objectCounts: make(map[string]int),
+	}
+}
+
+func (s *FileValidator) recordError(validationError model.IngestValidationError) {
+	s.report.ErrorCount++
+
+	if len(s.errors) < MaxReportedValidationErrors {
+		s.errors = append(s.errors, validationError)
+	}
+}
+
+func (s *FileValidator) elementPath() string {
+	return JSONPointer("/data", strconv.Itoa(s.elementIndex))
+}
+
+// SetMeta loads the schema for the data type and version found in the meta tag of the file. Elements are counted
+// under the given kind; when kind is empty the kind property of each element is used instead.
+func (s *FileValidator) SetMeta(dataType string, version int, kind string) error {
+	s.report.DataType = dataType
+	s.report.Version = version
+	s.kind = kind
+
+	if schema, err := LoadSchema(dataType, version); err != nil {
+		return err
+	} else {
+		s.schema = schema
+		return nil
+	}
+}
+
+// Reject marks the whole file as rejected. Batches are flushed as they fill up, so objects that were ingested from the
+// file before the error remain in the graph. They are overwritten once a corrected file is ingested.
+func (s *FileValidator) Reject(err error) {
+	s.rejected = true
+	s.objectCounts = make(map[string]int)
+	s.recordError(model.IngestValidationError{
+		Path:    "",
+		Message: err.Error(),
+	})
+}
+
+// ValidateElement validates the next element of the data tag. Elements that fail validation are recorded on the
+// report and must not be ingested.
+func (s *FileValidator) ValidateElement(element json.RawMessage) bool {
+	var (
+		decoder = json.NewDecoder(bytes.NewReader(element))
+		value   any
+	)
+
+	s.elementIndex++
+	s.lastElement = nil
+
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&value); err != nil {
+		s.RejectElement(err)
+		return false
+	} else if s.schema == nil {
+		return true
+	} else if validationErrors := s.schema.Validate(value, s.elementPath()); len(validationErrors) > 0 {
+		for _, validationError := range validationErrors {
+			s.recordError(validationError)
+		}
+
+		return false
+	}
+
+	s.lastElement = value
+	return true
+}
+
+// AcceptElement counts the last validated element as ingested
+func (s *FileValidator) AcceptElement() {
+	kind := s.kind
+
+	if kind == "" {
+		if element, typeOK := s.lastElement.(map[string]any); typeOK {
+			kind, _ = element["kind"].(string)
+		}
+	}
+
+	if kind == "" {
+		kind = s.report.DataType
+	}
+
+	s.objectCounts[kind]++
+}
+
+// RejectElement records an error for the last validated element when it could not be decoded for ingest
+func (s *FileValidator) RejectElement(err error) {
+	s.recordError(model.IngestValidationError{
+		Path:    s.elementPath(),
+		Message: err.Error(),
+	})
+}
+
+// HasErrors returns true if the file was rejected or any of its elements failed validation
+func (s *FileValidator) HasErrors() bool {
+	return s.report.ErrorCount > 0
+}
+
+// Report returns the validation report for the file
+func (s *FileValidator) Report() model.IngestFileReport {
+	report := s.report
+	report.Accepted = !s.rejected
+	report.ObjectCounts = make(types.JSONUntypedObject, len(s.objectCounts))
+
+	for kind, count := range s.objectCounts {
+		report.ObjectCounts[kind] = count
+	}
+
+	errorList := s.errors
+	if errorList == nil {
+		errorList = []model.IngestValidationError{}
+	}
+
+	if errors, err := types.NewJSONBObject(errorList); err != nil {
+		log.Errorf("Error encoding validation errors for file %s: %v", report.FileName, err)
+	} else {
+		report.Errors = errors
+	}
+
+	return report
+}
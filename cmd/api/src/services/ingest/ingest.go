@@ -25,11 +25,13 @@ type IngestData interface {
 	CreateIngestTask(task model.IngestTask) (model.IngestTask, error)
 }
 
-func CreateIngestTask(db IngestData, filename string, requestID string, jobID int64) (model.IngestTask, error) {
+func CreateIngestTask(db IngestData, filename string, originalFileName string, fileType model.FileType, requestID string, jobID int64) (model.IngestTask, error) {
 	newIngestTask := model.IngestTask{
-		FileName:    filename,
-		RequestGUID: requestID,
-		TaskID:      null.Int64From(jobID),
+		FileName:         filename,
+		OriginalFileName: originalFileName,
+		FileType:         fileType,
+		RequestGUID:      requestID,
+		TaskID:           null.Int64From(jobID),
 	}
 
 	return db.CreateIngestTask(newIngestTask)
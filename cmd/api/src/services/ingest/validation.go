@@ -0,0 +1,228 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ingest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+//go:embed schemas
+var schemaFiles embed.FS
+
+// MaxReportedValidationErrors is the number of validation errors stored on a file report. Further errors are only
+// counted.
+const MaxReportedValidationErrors = 10
+
+// minimumVersions holds the oldest collector output version accepted for a data type. Computer output older than v5
+// is missing data that post-processing depends on.
+var minimumVersions = map[string]int{
+	"computers": 5,
+}
+
+const (
+	ErrUnsupportedVersion = errors.Error("unsupported collector output version")
+	ErrSchemaNotFound     = errors.Error("no schema found for data type")
+)
+
+// SchemaTypes holds the value of a JSON schema type keyword which may be either a single type name or a list of them
+type SchemaTypes []string
+
+func (s *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = SchemaTypes{single}
+		return nil
+	}
+
+	var multiple []string
+
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	} else {
+		*s = multiple
+		return nil
+	}
+}
+
+// Schema is the subset of JSON schema (draft 7) used to describe collector output: type, required, properties,
+// items, oneOf and local $ref pointers into definitions. Unlike JSON schema, property names are matched without
+// regard to case.
+type Schema struct {
+	Type        SchemaTypes        `json:"type"`
+	Required    []string           `json:"required"`
+	Properties  map[string]*Schema `json:"properties"`
+	Items       *Schema            `json:"items"`
+	OneOf       []*Schema          `json:"oneOf"`
+	Ref         string             `json:"$ref"`
+	Definitions map[string]*Schema `json:"definitions"`
+}
+
+// LoadSchema returns the schema for the given data type and version. Version specific schemas named
+// <type>.v<version>.json take precedence over the general <type>.json schema.
+func LoadSchema(dataType string, version int) (*Schema, error) {
+	if minimumVersion, hasMinimum := minimumVersions[dataType]; hasMinimum && version < minimumVersion {
+		return nil, fmt.Errorf("%w: %s requires version %d or later but found %d", ErrUnsupportedVersion, dataType, minimumVersion, version)
+	}
+
+	for _, fileName := range []string{
+		fmt.Sprintf("schemas/%s.v%d.json", dataType, version),
+		fmt.Sprintf("schemas/%s.json", dataType),
+	} {
+		if content, err := schemaFiles.ReadFile(fileName); err == nil {
+			var schema Schema
+
+			if err := json.Unmarshal(content, &schema); err != nil {
+				return nil, fmt.Errorf("failed to parse schema %s: %w", fileName, err)
+			}
+
+			return &schema, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, dataType)
+}
+
+func jsonTypeOf(value any) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if _, err := typedValue.Int64(); err == nil {
+			return "integer"
+		}
+
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func (s *Schema) matchesType(valueType string) bool {
+	if len(s.Type) == 0 {
+		return true
+	}
+
+	for _, schemaType := range s.Type {
+		if schemaType == valueType || (schemaType == "number" && valueType == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JSONPointer appends the given reference token to a JSON pointer, escaping it as described in RFC 6901
+func JSONPointer(path string, token string) string {
+	return path + "/" + strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// Validate validates the value against this schema and returns every validation error found. Values are expected to
+// be decoded with json.Decoder.UseNumber so that integers can be told apart from other numbers.
+func (s *Schema) Validate(value any, path string) []model.IngestValidationError {
+	return s.validate(s, value, path)
+}
+
+func (s *Schema) resolve(root *Schema) (*Schema, error) {
+	if s.Ref == "" {
+		return s, nil
+	} else if name, isLocal := strings.CutPrefix(s.Ref, "#/definitions/"); !isLocal {
+		return nil, fmt.Errorf("unsupported schema reference %s", s.Ref)
+	} else if definition, found := root.Definitions[name]; !found {
+		return nil, fmt.Errorf("schema reference %s not found", s.Ref)
+	} else {
+		return definition, nil
+	}
+}
+
+func (s *Schema) validate(root *Schema, value any, path string) []model.IngestValidationError {
+	schema, err := s.resolve(root)
+	if err != nil {
+		return []model.IngestValidationError{{Path: path, Message: err.Error()}}
+	}
+
+	if len(schema.OneOf) > 0 {
+		numMatched := 0
+
+		for _, candidate := range schema.OneOf {
+			if len(candidate.validate(root, value, path)) == 0 {
+				numMatched++
+			}
+		}
+
+		if numMatched != 1 {
+			return []model.IngestValidationError{{Path: path, Message: fmt.Sprintf("value matches %d of %d allowed schemas", numMatched, len(schema.OneOf))}}
+		}
+	}
+
+	valueType := jsonTypeOf(value)
+
+	if !schema.matchesType(valueType) {
+		return []model.IngestValidationError{{Path: path, Message: fmt.Sprintf("expected %s but found %s", strings.Join(schema.Type, " or "), valueType)}}
+	}
+
+	var validationErrors []model.IngestValidationError
+
+	switch typedValue := value.(type) {
+	case map[string]any:
+		// Property names are matched case-insensitively to mirror how encoding/json decodes the element for ingest
+		foldedNames := make(map[string]string, len(typedValue))
+
+		for name := range typedValue {
+			foldedNames[strings.ToLower(name)] = name
+		}
+
+		for _, required := range schema.Required {
+			if _, found := foldedNames[strings.ToLower(required)]; !found {
+				validationErrors = append(validationErrors, model.IngestValidationError{
+					Path:    JSONPointer(path, required),
+					Message: "required property is missing",
+				})
+			}
+		}
+
+		for name, propertySchema := range schema.Properties {
+			if actualName, found := foldedNames[strings.ToLower(name)]; found {
+				validationErrors = append(validationErrors, propertySchema.validate(root, typedValue[actualName], JSONPointer(path, actualName))...)
+			}
+		}
+
+	case []any:
+		if schema.Items != nil {
+			for idx, item := range typedValue {
+				validationErrors = append(validationErrors, schema.Items.validate(root, item, JSONPointer(path, strconv.Itoa(idx)))...)
+			}
+		}
+	}
+
+	return validationErrors
+}
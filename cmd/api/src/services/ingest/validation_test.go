@@ -0,0 +1,145 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ingest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/services/ingest"
+	"github.com/specterops/bloodhound/src/test/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeWithNumbers(t *testing.T, content []byte, target any) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	require.Nil(t, decoder.Decode(target))
+}
+
+func TestLoadSchema(t *testing.T) {
+	_, err := ingest.LoadSchema("computers", 4)
+	require.ErrorIs(t, err, ingest.ErrUnsupportedVersion)
+
+	_, err = ingest.LoadSchema("unknown", 5)
+	require.ErrorIs(t, err, ingest.ErrSchemaNotFound)
+
+	schema, err := ingest.LoadSchema("users", 0)
+	require.Nil(t, err)
+	require.Contains(t, schema.Required, "ObjectIdentifier")
+}
+
+func TestSchema_ValidateFixtures(t *testing.T) {
+	loader := fixtures.NewLoader(fixtures.NewTestErrorHandler(t))
+
+	for _, version := range []string{"v5", "v6"} {
+		for _, dataType := range []string{"computers", "containers", "deleted", "domains", "gpos", "groups", "ous", "sessions", "users"} {
+			var (
+				path    = fmt.Sprintf("%s/ingest/%s.json", version, dataType)
+				content struct {
+					Meta struct {
+						Type    string `json:"type"`
+						Version int    `json:"version"`
+					} `json:"meta"`
+					Data []any `json:"data"`
+				}
+			)
+
+			decodeWithNumbers(t, loader.Get(path), &content)
+
+			schema, err := ingest.LoadSchema(content.Meta.Type, content.Meta.Version)
+			require.Nil(t, err, path)
+
+			for idx, element := range content.Data {
+				require.Empty(t, schema.Validate(element, fmt.Sprintf("/data/%d", idx)), path)
+			}
+		}
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	var element any
+
+	schema, err := ingest.LoadSchema("groups", 5)
+	require.Nil(t, err)
+
+	decodeWithNumbers(t, []byte(`{"Properties": [], "Members": [{"ObjectIdentifier": 1, "ObjectType": "User"}]}`), &element)
+
+	require.Equal(t, []model.IngestValidationError{
+		{Path: "/data/0/ObjectIdentifier", Message: "required property is missing"},
+	}, filterPath(schema.Validate(element, "/data/0"), "/data/0/ObjectIdentifier"))
+
+	require.Equal(t, []model.IngestValidationError{
+		{Path: "/data/0/Properties", Message: "expected object or null but found array"},
+	}, filterPath(schema.Validate(element, "/data/0"), "/data/0/Properties"))
+
+	require.Equal(t, []model.IngestValidationError{
+		{Path: "/data/0/Members/0/ObjectIdentifier", Message: "expected string but found integer"},
+	}, filterPath(schema.Validate(element, "/data/0"), "/data/0/Members/0/ObjectIdentifier"))
+
+	require.Equal(t, "/a~1b/c~0d", ingest.JSONPointer(ingest.JSONPointer("", "a/b"), "c~d"))
+}
+
+func filterPath(validationErrors []model.IngestValidationError, path string) []model.IngestValidationError {
+	var filtered []model.IngestValidationError
+
+	for _, validationError := range validationErrors {
+		if validationError.Path == path {
+			filtered = append(filtered, validationError)
+		}
+	}
+
+	return filtered
+}
+
+func TestFileValidator(t *testing.T) {
+	validator := ingest.NewFileValidator(1, "users.json")
+	require.Nil(t, validator.SetMeta("users", 5, "User"))
+
+	require.True(t, validator.ValidateElement(json.RawMessage(`{"ObjectIdentifier": "S-1-5-21-1"}`)))
+	validator.AcceptElement()
+
+	for idx := 0; idx < ingest.MaxReportedValidationErrors+5; idx++ {
+		require.False(t, validator.ValidateElement(json.RawMessage(`{}`)))
+	}
+
+	report := validator.Report()
+	require.True(t, report.Accepted)
+	require.Equal(t, "users", report.DataType)
+	require.Equal(t, 1, report.ObjectCounts["User"])
+	require.Equal(t, ingest.MaxReportedValidationErrors+5, report.ErrorCount)
+
+	var reportedErrors []model.IngestValidationError
+	require.Nil(t, report.Errors.Map(&reportedErrors))
+	require.Len(t, reportedErrors, ingest.MaxReportedValidationErrors)
+	require.Equal(t, "/data/1/ObjectIdentifier", reportedErrors[0].Path)
+
+	azureValidator := ingest.NewFileValidator(1, "azure.json")
+	require.Nil(t, azureValidator.SetMeta("azure", 5, ""))
+	require.True(t, azureValidator.ValidateElement(json.RawMessage(`{"kind": "AZUser", "data": {}}`)))
+	azureValidator.AcceptElement()
+	require.Equal(t, 1, azureValidator.Report().ObjectCounts["AZUser"])
+
+	rejectedValidator := ingest.NewFileValidator(1, "computers.json")
+	require.ErrorIs(t, rejectedValidator.SetMeta("computers", 4, "Computer"), ingest.ErrUnsupportedVersion)
+	rejectedValidator.Reject(ingest.ErrUnsupportedVersion)
+	require.False(t, rejectedValidator.Report().Accepted)
+}
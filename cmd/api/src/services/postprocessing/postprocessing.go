@@ -0,0 +1,213 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate go run go.uber.org/mock/mockgen -copyright_file=../../../../../LICENSE.header -destination=./mocks/mock.go -package=mocks . RuleData
+package postprocessing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/cypher/analyzer"
+	"github.com/specterops/bloodhound/cypher/frontend"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/util/channels"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/log"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// MaxRuleQueryComplexityWeight is the highest query complexity weight allowed for the match pattern of a rule
+const MaxRuleQueryComplexityWeight = 50
+
+var (
+	ErrRuleNameRequired      = errors.Error("a rule name is required")
+	ErrRuleQueryRequired     = errors.Error("a rule query is required")
+	ErrInvalidEdgeKind       = errors.Error("the edge kind must be a valid cypher identifier")
+	ErrInvalidBinding        = errors.Error("the source and target bindings must be valid cypher identifiers")
+	ErrBindingsNotDistinct   = errors.Error("the source and target bindings must differ")
+	ErrRuleQueryTooComplex   = errors.Error("the rule query is too complex")
+	ErrRuleQueryInvalidInput = errors.Error("the rule query must be a match pattern without a return clause")
+
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+type RuleData interface {
+	GetAllPostProcessingRules() (model.PostProcessingRules, error)
+}
+
+// PrepareRuleQuery validates the given rule and returns the cypher query that returns the source and target node of
+// every relationship the rule creates. The rule query is a match pattern, with an optional where clause, that the
+// source and target bindings are returned from.
+func PrepareRuleQuery(rule model.PostProcessingRule) (string, error) {
+	var (
+		rawQuery = strings.TrimSpace(rule.Query)
+		buffer   = &bytes.Buffer{}
+	)
+
+	if rule.Name == "" {
+		return "", ErrRuleNameRequired
+	} else if rawQuery == "" {
+		return "", ErrRuleQueryRequired
+	} else if !identifierPattern.MatchString(rule.EdgeKind) {
+		return "", ErrInvalidEdgeKind
+	} else if !identifierPattern.MatchString(rule.SourceBinding) || !identifierPattern.MatchString(rule.TargetBinding) {
+		return "", ErrInvalidBinding
+	} else if rule.SourceBinding == rule.TargetBinding {
+		return "", ErrBindingsNotDistinct
+	} else if queryModel, err := frontend.ParseCypher(frontend.DefaultCypherContext(), fmt.Sprintf("%s RETURN %s, %s", rawQuery, rule.SourceBinding, rule.TargetBinding)); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrRuleQueryInvalidInput, err)
+	} else if complexityMeasure, err := analyzer.QueryComplexity(queryModel); err != nil {
+		return "", err
+	} else if complexityMeasure.Weight > MaxRuleQueryComplexityWeight {
+		return "", ErrRuleQueryTooComplex
+	} else if err := frontend.NewCypherEmitter(false).Write(queryModel, buffer); err != nil {
+		return "", err
+	} else {
+		return buffer.String(), nil
+	}
+}
+
+// ruleRelationships tracks the relationships submitted by every rule so that rules that overlap do not create the
+// same relationship twice
+type ruleRelationships struct {
+	mutex         *sync.Mutex
+	relationships map[analysis.CreatePostRelationshipJob]struct{}
+}
+
+func newRuleRelationships() ruleRelationships {
+	return ruleRelationships{
+		mutex:         &sync.Mutex{},
+		relationships: map[analysis.CreatePostRelationshipJob]struct{}{},
+	}
+}
+
+// Add returns true if the given relationship has not been added before
+func (s ruleRelationships) Add(job analysis.CreatePostRelationshipJob) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, seen := s.relationships[job]; seen {
+		return false
+	}
+
+	s.relationships[job] = struct{}{}
+	return true
+}
+
+func relationshipExists(tx graph.Transaction, job analysis.CreatePostRelationshipJob) (bool, error) {
+	count, err := tx.Relationships().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Equals(query.StartID(), job.FromID),
+			query.Equals(query.EndID(), job.ToID),
+			query.Kind(query.Relationship(), job.Kind),
+		)
+	}).Count()
+
+	return count > 0, err
+}
+
+func evaluateRule(ctx context.Context, tx graph.Transaction, rule model.PostProcessingRule, cypher string, submitted ruleRelationships, outC chan<- analysis.CreatePostRelationshipJob) error {
+	var jobs []analysis.CreatePostRelationshipJob
+
+	if result := tx.Run(cypher, map[string]any{}); result.Error() != nil {
+		return result.Error()
+	} else {
+		defer result.Close()
+
+		for result.Next() {
+			var source, target graph.Node
+
+			if err := result.Scan(&source, &target); err != nil {
+				return fmt.Errorf("bindings %s and %s must both be nodes: %w", rule.SourceBinding, rule.TargetBinding, err)
+			} else if source.ID != target.ID {
+				jobs = append(jobs, analysis.CreatePostRelationshipJob{
+					FromID: source.ID,
+					ToID:   target.ID,
+					Kind:   graph.StringKind(rule.EdgeKind),
+				})
+			}
+		}
+
+		if err := result.Error(); err != nil {
+			return err
+		}
+	}
+
+	// Relationships that were ingested or created by the built-in post-processing stages are left as they are
+	for _, job := range jobs {
+		if exists, err := relationshipExists(tx, job); err != nil {
+			return err
+		} else if !exists && submitted.Add(job) {
+			if !channels.Submit(ctx, outC, job) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// RunPostProcessingRules deletes the relationships created by the previous evaluation of the post-processing rules and
+// then evaluates every enabled rule. A rule that can not be evaluated is logged and skipped so that it does not prevent
+// the remaining rules from creating their relationships.
+func RunPostProcessingRules(ctx context.Context, db RuleData, graphDB graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	defer log.Measure(log.LevelInfo, "Finished evaluating post-processing rules")()
+
+	stats := analysis.NewAtomicPostProcessingStats()
+
+	if deleteStats, err := analysis.DeletePostProcessedEdges(ctx, graphDB); err != nil {
+		return &stats, fmt.Errorf("failed deleting post-processed edges: %w", err)
+	} else if rules, err := db.GetAllPostProcessingRules(); err != nil {
+		return &stats, fmt.Errorf("failed fetching post-processing rules: %w", err)
+	} else {
+		stats.Merge(deleteStats)
+
+		var (
+			operation = analysis.NewPostProcessedRelationshipOperation(ctx, graphDB, "Post-processing rules")
+			submitted = newRuleRelationships()
+		)
+
+		for _, rule := range rules.Enabled() {
+			innerRule := rule
+
+			if cypher, err := PrepareRuleQuery(innerRule); err != nil {
+				log.Errorf("Skipping invalid post-processing rule %s: %v", innerRule.Name, err)
+			} else {
+				operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+					if err := evaluateRule(ctx, tx, innerRule, cypher, submitted, outC); err != nil {
+						log.Errorf("Failed evaluating post-processing rule %s: %v", innerRule.Name, err)
+					}
+
+					return nil
+				})
+			}
+		}
+
+		if err := operation.Done(); err != nil {
+			return &stats, err
+		}
+
+		stats.Merge(&operation.Stats)
+		return &stats, nil
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"sort"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+// PrincipalKinds are the kinds of nodes reported as principals when diffing snapshots
+var PrincipalKinds = graph.Kinds{
+	ad.User,
+	ad.Group,
+	ad.Computer,
+	azure.User,
+	azure.Group,
+	azure.ServicePrincipal,
+	azure.App,
+	azure.Device,
+}
+
+func isPrincipal(node model.GraphSnapshotNode) bool {
+	return PrincipalKinds.ContainsOneOf(graph.StringsToKinds(node.Kinds)...)
+}
+
+func isTierZero(node model.GraphSnapshotNode) bool {
+	return node.TierZero
+}
+
+func isAttackPathRoot(node model.GraphSnapshotNode) bool {
+	return node.AttackPathRoot
+}
+
+func indexNodes(nodes []model.GraphSnapshotNode, filter func(node model.GraphSnapshotNode) bool) map[string]model.GraphSnapshotNode {
+	index := make(map[string]model.GraphSnapshotNode, len(nodes))
+
+	for _, node := range nodes {
+		if filter(node) {
+			index[node.ObjectID] = node
+		}
+	}
+
+	return index
+}
+
+// diffNodes compares the nodes of both snapshots that pass the filter. Nodes are matched by object ID.
+func diffNodes(from, to []model.GraphSnapshotNode, filter func(node model.GraphSnapshotNode) bool) model.GraphSnapshotChanges[model.GraphSnapshotNode] {
+	var (
+		changes = model.GraphSnapshotChanges[model.GraphSnapshotNode]{
+			Added:   []model.GraphSnapshotNode{},
+			Removed: []model.GraphSnapshotNode{},
+		}
+		fromIndex = indexNodes(from, filter)
+		toIndex   = indexNodes(to, filter)
+	)
+
+	for objectID, node := range toIndex {
+		if _, found := fromIndex[objectID]; !found {
+			changes.Added = append(changes.Added, node)
+		}
+	}
+
+	for objectID, node := range fromIndex {
+		if _, found := toIndex[objectID]; !found {
+			changes.Removed = append(changes.Removed, node)
+		}
+	}
+
+	sortNodes(changes.Added)
+	sortNodes(changes.Removed)
+
+	return changes
+}
+
+func sortNodes(nodes []model.GraphSnapshotNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ObjectID < nodes[j].ObjectID
+	})
+}
+
+func diffRelationships(from, to []model.GraphSnapshotRelationship) model.GraphSnapshotChanges[model.GraphSnapshotRelationship] {
+	var (
+		changes = model.GraphSnapshotChanges[model.GraphSnapshotRelationship]{
+			Added:   []model.GraphSnapshotRelationship{},
+			Removed: []model.GraphSnapshotRelationship{},
+		}
+		fromKeys = make(map[string]struct{}, len(from))
+		toKeys   = make(map[string]struct{}, len(to))
+	)
+
+	for _, relationship := range from {
+		fromKeys[relationship.Key()] = struct{}{}
+	}
+
+	for _, relationship := range to {
+		toKeys[relationship.Key()] = struct{}{}
+
+		if _, found := fromKeys[relationship.Key()]; !found {
+			changes.Added = append(changes.Added, relationship)
+		}
+	}
+
+	for _, relationship := range from {
+		if _, found := toKeys[relationship.Key()]; !found {
+			changes.Removed = append(changes.Removed, relationship)
+		}
+	}
+
+	return changes
+}
+
+// Diff returns the principals, relationships, tier zero members and attack path roots that were added or removed
+// between the content of two snapshots
+func Diff(from, to model.GraphSnapshotContent) model.GraphSnapshotDiff {
+	return model.GraphSnapshotDiff{
+		Principals:      diffNodes(from.Nodes, to.Nodes, isPrincipal),
+		Relationships:   diffRelationships(from.Relationships, to.Relationships),
+		TierZero:        diffNodes(from.Nodes, to.Nodes, isTierZero),
+		AttackPathRoots: diffNodes(from.Nodes, to.Nodes, isAttackPathRoot),
+	}
+}
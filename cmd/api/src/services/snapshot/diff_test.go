@@ -0,0 +1,60 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/src/model"
+	"github.com/specterops/bloodhound/src/services/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	var (
+		user      = model.GraphSnapshotNode{ObjectID: "S-1-5-21-1-1104", Kinds: []string{"Base", "User"}}
+		admin     = model.GraphSnapshotNode{ObjectID: "S-1-5-21-1-1105", Kinds: []string{"Base", "User"}, AttackPathRoot: true}
+		ou        = model.GraphSnapshotNode{ObjectID: "OU-1", Kinds: []string{"Base", "OU"}}
+		domain    = model.GraphSnapshotNode{ObjectID: "S-1-5-21-1", Kinds: []string{"Base", "Domain"}, TierZero: true}
+		newUser   = model.GraphSnapshotNode{ObjectID: "S-1-5-21-1-1106", Kinds: []string{"Base", "User"}, TierZero: true}
+		adminEdge = model.GraphSnapshotRelationship{Kind: "GenericAll", StartObjectID: admin.ObjectID, EndObjectID: domain.ObjectID}
+		ouEdge    = model.GraphSnapshotRelationship{Kind: "Contains", StartObjectID: ou.ObjectID, EndObjectID: user.ObjectID}
+
+		remediatedAdmin = model.GraphSnapshotNode{ObjectID: admin.ObjectID, Kinds: admin.Kinds}
+	)
+
+	diff := snapshot.Diff(model.GraphSnapshotContent{
+		Nodes:         []model.GraphSnapshotNode{user, admin, ou, domain},
+		Relationships: []model.GraphSnapshotRelationship{adminEdge, ouEdge},
+	}, model.GraphSnapshotContent{
+		Nodes:         []model.GraphSnapshotNode{remediatedAdmin, domain, newUser},
+		Relationships: []model.GraphSnapshotRelationship{ouEdge},
+	})
+
+	// Only principals are reported, so the removed OU is not listed
+	require.Equal(t, []model.GraphSnapshotNode{newUser}, diff.Principals.Added)
+	require.Equal(t, []model.GraphSnapshotNode{user}, diff.Principals.Removed)
+
+	require.Empty(t, diff.Relationships.Added)
+	require.Equal(t, []model.GraphSnapshotRelationship{adminEdge}, diff.Relationships.Removed)
+
+	require.Equal(t, []model.GraphSnapshotNode{newUser}, diff.TierZero.Added)
+	require.Empty(t, diff.TierZero.Removed)
+
+	require.Empty(t, diff.AttackPathRoots.Added)
+	require.Equal(t, []model.GraphSnapshotNode{admin}, diff.AttackPathRoots.Removed)
+}
@@ -0,0 +1,236 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/src/database/types"
+	"github.com/specterops/bloodhound/src/model"
+)
+
+const (
+	ErrUnknownEnvironment = errors.Error("no domain or tenant exists with the given id")
+)
+
+// environment describes how the objects of a domain or tenant are found in the graph
+type environment struct {
+	Type             string
+	Kind             graph.Kind
+	ScopeProperty    string
+	PathfindingKinds graph.Kinds
+}
+
+func (s environment) contains(reference graph.Criteria, scopeProperty graph.Criteria, environmentID string) graph.Criteria {
+	return query.And(
+		query.Kind(reference, s.Kind),
+		query.Equals(scopeProperty, environmentID),
+	)
+}
+
+func fetchEnvironment(tx graph.Transaction, environmentID string) (environment, error) {
+	if node, err := tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.KindIn(query.Node(), ad.Domain, azure.Tenant),
+			query.Equals(query.NodeProperty(common.ObjectID.String()), environmentID),
+		)
+	}).First(); err != nil {
+		if graph.IsErrNotFound(err) {
+			return environment{}, ErrUnknownEnvironment
+		}
+
+		return environment{}, err
+	} else if node.Kinds.ContainsOneOf(azure.Tenant) {
+		return environment{
+			Type:             model.GraphSnapshotEnvironmentAzure,
+			Kind:             azure.Entity,
+			ScopeProperty:    azure.TenantID.String(),
+			PathfindingKinds: azure.PathfindingRelationships(),
+		}, nil
+	} else {
+		return environment{
+			Type:             model.GraphSnapshotEnvironmentActiveDirectory,
+			Kind:             ad.Entity,
+			ScopeProperty:    ad.DomainSID.String(),
+			PathfindingKinds: ad.PathfindingRelationships(),
+		}, nil
+	}
+}
+
+func newSnapshotNode(node *graph.Node) model.GraphSnapshotNode {
+	var (
+		objectID, _   = node.Properties.GetOrDefault(common.ObjectID.String(), "").String()
+		name, _       = node.Properties.GetOrDefault(common.Name.String(), "").String()
+		systemTags, _ = node.Properties.GetOrDefault(common.SystemTags.String(), "").String()
+		kinds         = node.Kinds.Strings()
+	)
+
+	sort.Strings(kinds)
+
+	return model.GraphSnapshotNode{
+		ObjectID: objectID,
+		Name:     name,
+		Kinds:    kinds,
+		TierZero: strings.Contains(systemTags, ad.AdminTierZero),
+	}
+}
+
+func fetchSnapshotNodes(nodeQuery graph.NodeQuery, nodes map[graph.ID]model.GraphSnapshotNode) error {
+	return nodeQuery.Fetch(func(cursor graph.Cursor[*graph.Node]) error {
+		for node := range cursor.Chan() {
+			if snapshotNode := newSnapshotNode(node); snapshotNode.ObjectID != "" {
+				nodes[node.ID] = snapshotNode
+			}
+		}
+
+		return cursor.Error()
+	})
+}
+
+// capture reads the nodes of the environment and the relationships that start at them. Relationships are stored with
+// the object IDs of their start and end nodes, which for relationships that leave the environment requires fetching
+// the nodes they end at. Nodes outside of the environment are not part of the snapshot.
+func capture(tx graph.Transaction, environmentID string) (environment, model.GraphSnapshotContent, error) {
+	var (
+		content       model.GraphSnapshotContent
+		nodes         = map[graph.ID]model.GraphSnapshotNode{}
+		externalNodes = map[graph.ID]model.GraphSnapshotNode{}
+		relationships []graph.RelationshipKindsResult
+		externalIDs   []graph.ID
+	)
+
+	env, err := fetchEnvironment(tx, environmentID)
+	if err != nil {
+		return env, content, err
+	}
+
+	if err := fetchSnapshotNodes(tx.Nodes().Filterf(func() graph.Criteria {
+		return env.contains(query.Node(), query.NodeProperty(env.ScopeProperty), environmentID)
+	}), nodes); err != nil {
+		return env, content, err
+	}
+
+	if err := tx.Relationships().Filterf(func() graph.Criteria {
+		return env.contains(query.Start(), query.StartProperty(env.ScopeProperty), environmentID)
+	}).FetchKinds(func(cursor graph.Cursor[graph.RelationshipKindsResult]) error {
+		for next := range cursor.Chan() {
+			relationships = append(relationships, next)
+
+			if _, found := nodes[next.EndID]; !found {
+				externalIDs = append(externalIDs, next.EndID)
+			}
+		}
+
+		return cursor.Error()
+	}); err != nil {
+		return env, content, err
+	}
+
+	if len(externalIDs) > 0 {
+		if err := fetchSnapshotNodes(tx.Nodes().Filterf(func() graph.Criteria {
+			return query.InIDs(query.NodeID(), externalIDs...)
+		}), externalNodes); err != nil {
+			return env, content, err
+		}
+	}
+
+	for _, relationship := range relationships {
+		start, startFound := nodes[relationship.StartID]
+		end, endFound := nodes[relationship.EndID]
+
+		if !endFound {
+			end, endFound = externalNodes[relationship.EndID]
+		}
+
+		if !startFound || !endFound {
+			continue
+		}
+
+		content.Relationships = append(content.Relationships, model.GraphSnapshotRelationship{
+			Kind:          relationship.Kind.String(),
+			StartObjectID: start.ObjectID,
+			EndObjectID:   end.ObjectID,
+		})
+
+		// Attack path roots are objects outside of tier zero with a traversable relationship into it
+		if !start.TierZero && end.TierZero && env.PathfindingKinds.ContainsOneOf(relationship.Kind) {
+			start.AttackPathRoot = true
+			nodes[relationship.StartID] = start
+		}
+	}
+
+	for _, node := range nodes {
+		content.Nodes = append(content.Nodes, node)
+	}
+
+	sort.Slice(content.Nodes, func(i, j int) bool {
+		return content.Nodes[i].ObjectID < content.Nodes[j].ObjectID
+	})
+
+	sort.Slice(content.Relationships, func(i, j int) bool {
+		return content.Relationships[i].Key() < content.Relationships[j].Key()
+	})
+
+	return env, content, nil
+}
+
+// Capture takes a snapshot of the domain or tenant with the given ID as it currently exists in the graph
+func Capture(ctx context.Context, graphDB graph.Database, name, environmentID string) (model.GraphSnapshot, error) {
+	var (
+		env     environment
+		content model.GraphSnapshotContent
+	)
+
+	if err := graphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		var err error
+
+		env, content, err = capture(tx, environmentID)
+		return err
+	}); err != nil {
+		return model.GraphSnapshot{}, err
+	} else if serializedContent, err := types.NewJSONBObject(content); err != nil {
+		return model.GraphSnapshot{}, err
+	} else {
+		return model.GraphSnapshot{
+			Name:              name,
+			EnvironmentType:   env.Type,
+			EnvironmentID:     environmentID,
+			NodeCount:         len(content.Nodes),
+			RelationshipCount: len(content.Relationships),
+			Content:           serializedContent,
+		}, nil
+	}
+}
+
+// Content returns the captured content of the given snapshot
+func Content(snapshot model.GraphSnapshot) (model.GraphSnapshotContent, error) {
+	var content model.GraphSnapshotContent
+
+	if err := snapshot.Content.Map(&content); err != nil {
+		return content, err
+	}
+
+	return content, nil
+}
@@ -166,6 +166,104 @@ HasURA: types.#StringEnum & {
 	representation: "hasura"
 }
 
+CertThumbprint: types.#StringEnum & {
+	symbol:         "CertThumbprint"
+	schema:         "ad"
+	name:           "Certificate Thumbprint"
+	representation: "certthumbprint"
+}
+
+CertThumbprints: types.#StringEnum & {
+	symbol:         "CertThumbprints"
+	schema:         "ad"
+	name:           "Certificate Thumbprints"
+	representation: "certthumbprints"
+}
+
+CertChain: types.#StringEnum & {
+	symbol:         "CertChain"
+	schema:         "ad"
+	name:           "Certificate Chain"
+	representation: "certchain"
+}
+
+CAName: types.#StringEnum & {
+	symbol:         "CAName"
+	schema:         "ad"
+	name:           "CA Name"
+	representation: "caname"
+}
+
+EnrolleeSuppliesSubject: types.#StringEnum & {
+	symbol:         "EnrolleeSuppliesSubject"
+	schema:         "ad"
+	name:           "Enrollee Supplies Subject"
+	representation: "enrolleesuppliessubject"
+}
+
+RequiresManagerApproval: types.#StringEnum & {
+	symbol:         "RequiresManagerApproval"
+	schema:         "ad"
+	name:           "Requires Manager Approval"
+	representation: "requiresmanagerapproval"
+}
+
+AuthenticationEnabled: types.#StringEnum & {
+	symbol:         "AuthenticationEnabled"
+	schema:         "ad"
+	name:           "Authentication Enabled"
+	representation: "authenticationenabled"
+}
+
+EKUs: types.#StringEnum & {
+	symbol:         "EKUs"
+	schema:         "ad"
+	name:           "Enhanced Key Usage"
+	representation: "ekus"
+}
+
+CertificateApplicationPolicy: types.#StringEnum & {
+	symbol:         "CertificateApplicationPolicy"
+	schema:         "ad"
+	name:           "Certificate Application Policies"
+	representation: "certificateapplicationpolicy"
+}
+
+AuthorizedSignatures: types.#StringEnum & {
+	symbol:         "AuthorizedSignatures"
+	schema:         "ad"
+	name:           "Authorized Signatures Required"
+	representation: "authorizedsignatures"
+}
+
+SchemaVersion: types.#StringEnum & {
+	symbol:         "SchemaVersion"
+	schema:         "ad"
+	name:           "Schema Version"
+	representation: "schemaversion"
+}
+
+IsUserSpecifiesSanEnabled: types.#StringEnum & {
+	symbol:         "IsUserSpecifiesSanEnabled"
+	schema:         "ad"
+	name:           "User Specified SAN Enabled"
+	representation: "isuserspecifiessanenabled"
+}
+
+HasEnrollmentAgentRestrictions: types.#StringEnum & {
+	symbol:         "HasEnrollmentAgentRestrictions"
+	schema:         "ad"
+	name:           "Has Enrollment Agent Restrictions"
+	representation: "hasenrollmentagentrestrictions"
+}
+
+HasVulnerableEndpoint: types.#StringEnum & {
+	symbol:         "HasVulnerableEndpoint"
+	schema:         "ad"
+	name:           "Has Vulnerable Web Enrollment Endpoint"
+	representation: "hasvulnerableendpoint"
+}
+
 Properties: [
 	AdminCount,
 	DistinguishedName,
@@ -186,7 +284,21 @@ Properties: [
 	HasLAPS,
 	DontRequirePreAuth,
 	LogonType,
-	HasURA
+	HasURA,
+	CertThumbprint,
+	CertThumbprints,
+	CertChain,
+	CAName,
+	EnrolleeSuppliesSubject,
+	RequiresManagerApproval,
+	AuthenticationEnabled,
+	EKUs,
+	CertificateApplicationPolicy,
+	AuthorizedSignatures,
+	SchemaVersion,
+	IsUserSpecifiesSanEnabled,
+	HasEnrollmentAgentRestrictions,
+	HasVulnerableEndpoint
 ]
 
 // Kinds
@@ -243,6 +355,31 @@ Domain: types.#Kind & {
 	schema: "active_directory"
 }
 
+AIACA: types.#Kind & {
+	symbol: "AIACA"
+	schema: "active_directory"
+}
+
+RootCA: types.#Kind & {
+	symbol: "RootCA"
+	schema: "active_directory"
+}
+
+EnterpriseCA: types.#Kind & {
+	symbol: "EnterpriseCA"
+	schema: "active_directory"
+}
+
+NTAuthStore: types.#Kind & {
+	symbol: "NTAuthStore"
+	schema: "active_directory"
+}
+
+CertTemplate: types.#Kind & {
+	symbol: "CertTemplate"
+	schema: "active_directory"
+}
+
 NodeKinds: [
 	Entity,
 	User,
@@ -254,6 +391,11 @@ NodeKinds: [
 	Domain,
 	LocalGroup,
 	LocalUser,
+	AIACA,
+	RootCA,
+	EnterpriseCA,
+	NTAuthStore,
+	CertTemplate,
 ]
 
 Owns: types.#Kind & {
@@ -442,6 +584,106 @@ GetChangesInFilteredSet: types.#Kind & {
 	schema: "active_directory"
 }
 
+Enroll: types.#Kind & {
+	symbol: "Enroll"
+	schema: "active_directory"
+}
+
+ManageCA: types.#Kind & {
+	symbol: "ManageCA"
+	schema: "active_directory"
+}
+
+ManageCertificates: types.#Kind & {
+	symbol: "ManageCertificates"
+	schema: "active_directory"
+}
+
+PublishedTo: types.#Kind & {
+	symbol: "PublishedTo"
+	schema: "active_directory"
+}
+
+HostsCAService: types.#Kind & {
+	symbol: "HostsCAService"
+	schema: "active_directory"
+}
+
+RootCAFor: types.#Kind & {
+	symbol: "RootCAFor"
+	schema: "active_directory"
+}
+
+NTAuthStoreFor: types.#Kind & {
+	symbol: "NTAuthStoreFor"
+	schema: "active_directory"
+}
+
+TrustedForNTAuth: types.#Kind & {
+	symbol: "TrustedForNTAuth"
+	schema: "active_directory"
+}
+
+IssuedSignedBy: types.#Kind & {
+	symbol: "IssuedSignedBy"
+	schema: "active_directory"
+}
+
+EnterpriseCAFor: types.#Kind & {
+	symbol: "EnterpriseCAFor"
+	schema: "active_directory"
+}
+
+GoldenCert: types.#Kind & {
+	symbol: "GoldenCert"
+	schema: "active_directory"
+}
+
+ADCSESC1: types.#Kind & {
+	symbol: "ADCSESC1"
+	schema: "active_directory"
+}
+
+ADCSESC3: types.#Kind & {
+	symbol: "ADCSESC3"
+	schema: "active_directory"
+}
+
+ADCSESC4: types.#Kind & {
+	symbol: "ADCSESC4"
+	schema: "active_directory"
+}
+
+ADCSESC6: types.#Kind & {
+	symbol: "ADCSESC6"
+	schema: "active_directory"
+}
+
+ADCSESC8: types.#Kind & {
+	symbol: "ADCSESC8"
+	schema: "active_directory"
+}
+
+CoerceToTGT: types.#Kind & {
+	symbol: "CoerceToTGT"
+	schema: "active_directory"
+}
+
+AdminToViaRBCD: types.#Kind & {
+	symbol: "AdminToViaRBCD"
+	schema: "active_directory"
+}
+
+SyncedToEntraUser: types.#Kind & {
+	symbol: "SyncedToEntraUser"
+	schema: "active_directory"
+}
+
+HostsEntraSyncAccount: types.#Kind & {
+	symbol: "HostsEntraSyncAccount"
+	schema: "active_directory"
+}
+
 // Relationship Kinds
 RelationshipKinds: [
 	Owns,
@@ -480,7 +722,27 @@ RelationshipKinds: [
 	MemberOfLocalGroup,
 	RemoteInteractiveLogonPrivilege,
 	SyncLAPSPassword,
-	WriteAccountRestrictions
+	WriteAccountRestrictions,
+	Enroll,
+	ManageCA,
+	ManageCertificates,
+	PublishedTo,
+	HostsCAService,
+	RootCAFor,
+	NTAuthStoreFor,
+	TrustedForNTAuth,
+	IssuedSignedBy,
+	EnterpriseCAFor,
+	GoldenCert,
+	ADCSESC1,
+	ADCSESC3,
+	ADCSESC4,
+	ADCSESC6,
+	ADCSESC8,
+	CoerceToTGT,
+	AdminToViaRBCD,
+	SyncedToEntraUser,
+	HostsEntraSyncAccount
 ]
 
 // ACL Relationships
@@ -505,6 +767,9 @@ ACLRelationships: [
 	WriteAccountRestrictions,
 	SyncLAPSPassword,
 	DCSync,
+	Enroll,
+	ManageCA,
+	ManageCertificates,
 ]
 
 // Edges that are used in pathfinding
@@ -539,5 +804,15 @@ PathfindingRelationships: [
 	WriteSPN,
 	AddKeyCredentialLink,
 	SyncLAPSPassword,
-	WriteAccountRestrictions
-]
+	WriteAccountRestrictions,
+	GoldenCert,
+	ADCSESC1,
+	ADCSESC3,
+	ADCSESC4,
+	ADCSESC6,
+	ADCSESC8,
+	CoerceToTGT,
+	AdminToViaRBCD,
+	SyncedToEntraUser,
+	HostsEntraSyncAccount
+]
\ No newline at end of file
@@ -521,6 +521,12 @@ AZMGGrantRole: types.#Kind & {
 	representation: "AZMGGrantRole"
 }
 
+SyncedToADUser: types.#Kind & {
+	symbol:         "SyncedToADUser"
+	schema:         "azure"
+	representation: "SyncedToADUser"
+}
+
 Contains: types.#Kind & {
 	symbol:         "Contains"
 	schema:         "azure"
@@ -762,6 +768,7 @@ RelationshipKinds: [
 	AZMGAddSecret,
 	AZMGGrantAppRoles,
 	AZMGGrantRole,
+	SyncedToADUser,
 ]
 
 AppRoleTransitRelationshipKinds: [
@@ -863,4 +870,5 @@ PathfindingRelationships: [
 	AZMGAddSecret,
 	AZMGGrantAppRoles,
 	AZMGGrantRole,
+	SyncedToADUser,
 ]
@@ -129,6 +129,48 @@ Email: types.#StringEnum & {
 	representation: "email"
 }
 
+Stale: types.#StringEnum & {
+	symbol:         "Stale"
+	schema:         "common"
+	name:           "Stale"
+	representation: "stale"
+}
+
+InboundExposure: types.#StringEnum & {
+	symbol:         "InboundExposure"
+	schema:         "common"
+	name:           "Inbound Exposure"
+	representation: "inboundexposure"
+}
+
+OutboundExposure: types.#StringEnum & {
+	symbol:         "OutboundExposure"
+	schema:         "common"
+	name:           "Outbound Exposure"
+	representation: "outboundexposure"
+}
+
+PostProcessed: types.#StringEnum & {
+	symbol:         "PostProcessed"
+	schema:         "common"
+	name:           "Post Processed"
+	representation: "post_processed"
+}
+
+ObservedAt: types.#StringEnum & {
+	symbol:         "ObservedAt"
+	schema:         "common"
+	name:           "Observed At"
+	representation: "observed_at"
+}
+
+ValidUntil: types.#StringEnum & {
+	symbol:         "ValidUntil"
+	schema:         "common"
+	name:           "Valid Until"
+	representation: "valid_until"
+}
+
 Properties: [
 	ObjectID,
 	Name,
@@ -145,6 +187,12 @@ Properties: [
 	PasswordLastSet,
 	Title,
 	Email,
+	Stale,
+	InboundExposure,
+	OutboundExposure,
+	PostProcessed,
+	ObservedAt,
+	ValidUntil,
 ]
 
 // Kinds
@@ -0,0 +1,674 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/util/channels"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+)
+
+const (
+	EKUCertRequestAgent = "1.3.6.1.4.1.311.20.2.1"
+	EKUAnyPurpose       = "2.5.29.37.0"
+)
+
+// EnrollmentRelationships are the relationship kinds that grant a principal the right to enroll in a certificate
+// template or with an enterprise CA
+func EnrollmentRelationships() []graph.Kind {
+	return []graph.Kind{
+		ad.Enroll,
+		ad.GenericAll,
+		ad.AllExtendedRights,
+	}
+}
+
+// TemplateWriteRelationships are the relationship kinds that allow a principal to rewrite the configuration of a
+// certificate template
+func TemplateWriteRelationships() []graph.Kind {
+	return []graph.Kind{
+		ad.GenericAll,
+		ad.GenericWrite,
+		ad.WriteOwner,
+		ad.WriteDACL,
+		ad.Owns,
+	}
+}
+
+func nodeBoolProperty(node *graph.Node, property ad.Property) bool {
+	if value, err := node.Properties.Get(property.String()).Bool(); err != nil {
+		return false
+	} else {
+		return value
+	}
+}
+
+func nodeIntProperty(node *graph.Node, property ad.Property) (int, bool) {
+	switch typed := node.Properties.Get(property.String()).Any().(type) {
+	case int:
+		return typed, true
+	case int64:
+		return int(typed), true
+	case float64:
+		return int(typed), true
+	default:
+		return 0, false
+	}
+}
+
+func nodeStringSliceProperty(node *graph.Node, property ad.Property) []string {
+	switch typed := node.Properties.Get(property.String()).Any().(type) {
+	case []string:
+		return typed
+	case []any:
+		values := make([]string, 0, len(typed))
+
+		for _, rawValue := range typed {
+			if value, typeOK := rawValue.(string); typeOK {
+				values = append(values, value)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func templateRequiresNoSignatures(template *graph.Node) bool {
+	if schemaVersion, hasSchemaVersion := nodeIntProperty(template, ad.SchemaVersion); hasSchemaVersion && schemaVersion == 1 {
+		return true
+	} else if authorizedSignatures, hasSignatures := nodeIntProperty(template, ad.AuthorizedSignatures); !hasSignatures || authorizedSignatures == 0 {
+		return true
+	}
+
+	return false
+}
+
+// IsTemplateIssuable returns true if a certificate for the template is issued without manager approval or any
+// authorized signatures
+func IsTemplateIssuable(template *graph.Node) bool {
+	return !nodeBoolProperty(template, ad.RequiresManagerApproval) && templateRequiresNoSignatures(template)
+}
+
+// IsESC1Template returns true if the template can be used for domain authentication and allows the enrollee to
+// supply an arbitrary subject
+func IsESC1Template(template *graph.Node) bool {
+	return nodeBoolProperty(template, ad.AuthenticationEnabled) && nodeBoolProperty(template, ad.EnrolleeSuppliesSubject) && IsTemplateIssuable(template)
+}
+
+// IsESC6Template returns true if the template can be used for domain authentication. Paired with an enterprise CA that
+// has EDITF_ATTRIBUTESUBJECTALTNAME2 set the enrollee may supply an arbitrary SAN regardless of the template flags.
+func IsESC6Template(template *graph.Node) bool {
+	return nodeBoolProperty(template, ad.AuthenticationEnabled) && IsTemplateIssuable(template)
+}
+
+// IsEnrollmentAgentTemplate returns true if the template issues certificates that may be used to request certificates
+// on behalf of other principals
+func IsEnrollmentAgentTemplate(template *graph.Node) bool {
+	ekus := nodeStringSliceProperty(template, ad.EKUs)
+
+	if len(ekus) > 0 && !containsFold(ekus, EKUCertRequestAgent) && !containsFold(ekus, EKUAnyPurpose) {
+		return false
+	}
+
+	return IsTemplateIssuable(template)
+}
+
+// IsESC3TargetTemplate returns true if a certificate for the template may be requested on behalf of another principal
+// with an enrollment agent certificate and then used for domain authentication
+func IsESC3TargetTemplate(template *graph.Node) bool {
+	if !nodeBoolProperty(template, ad.AuthenticationEnabled) || nodeBoolProperty(template, ad.RequiresManagerApproval) {
+		return false
+	}
+
+	if schemaVersion, hasSchemaVersion := nodeIntProperty(template, ad.SchemaVersion); hasSchemaVersion && schemaVersion == 1 {
+		return true
+	} else if authorizedSignatures, hasSignatures := nodeIntProperty(template, ad.AuthorizedSignatures); hasSignatures && authorizedSignatures == 1 {
+		return containsFold(nodeStringSliceProperty(template, ad.CertificateApplicationPolicy), EKUCertRequestAgent)
+	}
+
+	return false
+}
+
+// ADCSCache holds the certificate services objects of the graph along with the structural relationships between
+// them so that post-processing can evaluate the trust of each enterprise CA without further round trips
+type ADCSCache struct {
+	EnterpriseCAs graph.NodeSet
+	RootCAs       graph.NodeSet
+	AIACAs        graph.NodeSet
+	NTAuthStores  graph.NodeSet
+	CertTemplates graph.NodeSet
+	Domains       graph.NodeSet
+
+	RootCAsByDomain      map[graph.ID][]graph.ID
+	NTAuthStoresByDomain map[graph.ID][]graph.ID
+	PublishedTemplates   map[graph.ID][]graph.ID
+	CAHosts              map[graph.ID][]graph.ID
+}
+
+func NewADCSCache() ADCSCache {
+	return ADCSCache{
+		EnterpriseCAs:        graph.NewNodeSet(),
+		RootCAs:              graph.NewNodeSet(),
+		AIACAs:               graph.NewNodeSet(),
+		NTAuthStores:         graph.NewNodeSet(),
+		CertTemplates:        graph.NewNodeSet(),
+		Domains:              graph.NewNodeSet(),
+		RootCAsByDomain:      make(map[graph.ID][]graph.ID),
+		NTAuthStoresByDomain: make(map[graph.ID][]graph.ID),
+		PublishedTemplates:   make(map[graph.ID][]graph.ID),
+		CAHosts:              make(map[graph.ID][]graph.ID),
+	}
+}
+
+// IsTrustedForNTAuth returns true if the certificate of the enterprise CA is present in one of the NTAuth stores of
+// the given domain
+func (s ADCSCache) IsTrustedForNTAuth(enterpriseCA *graph.Node, domainID graph.ID) bool {
+	if thumbprint, err := enterpriseCA.Properties.Get(ad.CertThumbprint.String()).String(); err != nil || thumbprint == "" {
+		return false
+	} else {
+		for _, storeID := range s.NTAuthStoresByDomain[domainID] {
+			if store := s.NTAuthStores.Get(storeID); store != nil && containsFold(nodeStringSliceProperty(store, ad.CertThumbprints), thumbprint) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ChainsToRootCA returns true if the certificate chain of the enterprise CA terminates in a root CA that is trusted
+// by the given domain
+func (s ADCSCache) ChainsToRootCA(enterpriseCA *graph.Node, domainID graph.ID) bool {
+	chain := nodeStringSliceProperty(enterpriseCA, ad.CertChain)
+
+	for _, rootCAID := range s.RootCAsByDomain[domainID] {
+		if rootCA := s.RootCAs.Get(rootCAID); rootCA != nil {
+			if thumbprint, err := rootCA.Properties.Get(ad.CertThumbprint.String()).String(); err == nil && containsFold(chain, thumbprint) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ValidDomains returns the IDs of all domains for which certificates issued by the enterprise CA are accepted for
+// domain authentication
+func (s ADCSCache) ValidDomains(enterpriseCA *graph.Node) []graph.ID {
+	var domainIDs []graph.ID
+
+	for domainID := range s.Domains {
+		if s.IsTrustedForNTAuth(enterpriseCA, domainID) && s.ChainsToRootCA(enterpriseCA, domainID) {
+			domainIDs = append(domainIDs, domainID)
+		}
+	}
+
+	return domainIDs
+}
+
+// PublishedCertTemplates returns the certificate templates published to the enterprise CA
+func (s ADCSCache) PublishedCertTemplates(enterpriseCAID graph.ID) []*graph.Node {
+	var templates []*graph.Node
+
+	for _, templateID := range s.PublishedTemplates[enterpriseCAID] {
+		if template := s.CertTemplates.Get(templateID); template != nil {
+			templates = append(templates, template)
+		}
+	}
+
+	return templates
+}
+
+func fetchRelationshipIDPairs(tx graph.Transaction, kind graph.Kind, target map[graph.ID][]graph.ID, byEnd bool) error {
+	return tx.Relationships().Filterf(func() graph.Criteria {
+		return query.Kind(query.Relationship(), kind)
+	}).FetchTriples(func(cursor graph.Cursor[graph.RelationshipTripleResult]) error {
+		for next := range cursor.Chan() {
+			if byEnd {
+				target[next.EndID] = append(target[next.EndID], next.StartID)
+			} else {
+				target[next.StartID] = append(target[next.StartID], next.EndID)
+			}
+		}
+
+		return cursor.Error()
+	})
+}
+
+func FetchADCSCache(ctx context.Context, db graph.Database) (ADCSCache, error) {
+	cache := NewADCSCache()
+
+	return cache, db.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		for kind, nodeSet := range map[graph.Kind]graph.NodeSet{
+			ad.EnterpriseCA: cache.EnterpriseCAs,
+			ad.RootCA:       cache.RootCAs,
+			ad.AIACA:        cache.AIACAs,
+			ad.NTAuthStore:  cache.NTAuthStores,
+			ad.CertTemplate: cache.CertTemplates,
+			ad.Domain:       cache.Domains,
+		} {
+			innerKind := kind
+
+			if nodes, err := ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
+				return query.Kind(query.Node(), innerKind)
+			})); err != nil {
+				return err
+			} else {
+				nodeSet.AddSet(nodes)
+			}
+		}
+
+		if err := fetchRelationshipIDPairs(tx, ad.RootCAFor, cache.RootCAsByDomain, true); err != nil {
+			return err
+		} else if err := fetchRelationshipIDPairs(tx, ad.NTAuthStoreFor, cache.NTAuthStoresByDomain, true); err != nil {
+			return err
+		} else if err := fetchRelationshipIDPairs(tx, ad.PublishedTo, cache.PublishedTemplates, true); err != nil {
+			return err
+		} else {
+			return fetchRelationshipIDPairs(tx, ad.HostsCAService, cache.CAHosts, true)
+		}
+	})
+}
+
+// PostADCSTrust creates the relationships that describe how enterprise CAs are trusted: TrustedForNTAuth for CAs
+// present in an NTAuth store, IssuedSignedBy for the issuer of each CA certificate, EnterpriseCAFor for the AIA
+// object published for a CA and GoldenCert for the computers hosting a CA that is trusted by a domain.
+func PostADCSTrust(ctx context.Context, db graph.Database, cache ADCSCache) (*analysis.AtomicPostProcessingStats, error) {
+	operation := analysis.NewPostRelationshipOperation(ctx, db, "ADCS Trust Post Processing")
+
+	for _, enterpriseCA := range cache.EnterpriseCAs {
+		innerEnterpriseCA := enterpriseCA
+
+		operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+			var (
+				thumbprint, _ = innerEnterpriseCA.Properties.Get(ad.CertThumbprint.String()).String()
+				chain         = nodeStringSliceProperty(innerEnterpriseCA, ad.CertChain)
+				jobs          []analysis.CreatePostRelationshipJob
+			)
+
+			for _, store := range cache.NTAuthStores {
+				if thumbprint != "" && containsFold(nodeStringSliceProperty(store, ad.CertThumbprints), thumbprint) {
+					jobs = append(jobs, analysis.CreatePostRelationshipJob{
+						FromID: innerEnterpriseCA.ID,
+						ToID:   store.ID,
+						Kind:   ad.TrustedForNTAuth,
+					})
+				}
+			}
+
+			for _, aiaCA := range cache.AIACAs {
+				if aiaThumbprint, err := aiaCA.Properties.Get(ad.CertThumbprint.String()).String(); err == nil && thumbprint != "" && strings.EqualFold(aiaThumbprint, thumbprint) {
+					jobs = append(jobs, analysis.CreatePostRelationshipJob{
+						FromID: innerEnterpriseCA.ID,
+						ToID:   aiaCA.ID,
+						Kind:   ad.EnterpriseCAFor,
+					})
+				}
+			}
+
+			// The first entry of the chain is the certificate of the CA itself, the second is the certificate of its
+			// issuer
+			if len(chain) > 1 {
+				for _, issuer := range append(cache.RootCAs.Slice(), cache.EnterpriseCAs.Slice()...) {
+					if issuer.ID == innerEnterpriseCA.ID {
+						continue
+					}
+
+					if issuerThumbprint, err := issuer.Properties.Get(ad.CertThumbprint.String()).String(); err == nil && strings.EqualFold(issuerThumbprint, chain[1]) {
+						jobs = append(jobs, analysis.CreatePostRelationshipJob{
+							FromID: innerEnterpriseCA.ID,
+							ToID:   issuer.ID,
+							Kind:   ad.IssuedSignedBy,
+						})
+					}
+				}
+			}
+
+			for _, domainID := range cache.ValidDomains(innerEnterpriseCA) {
+				for _, hostID := range cache.CAHosts[innerEnterpriseCA.ID] {
+					jobs = append(jobs, analysis.CreatePostRelationshipJob{
+						FromID: hostID,
+						ToID:   domainID,
+						Kind:   ad.GoldenCert,
+					})
+				}
+			}
+
+			for _, job := range jobs {
+				if !channels.Submit(ctx, outC, job) {
+					return nil
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return &operation.Stats, operation.Done()
+}
+
+func fetchFirstDegreePrincipals(tx graph.Transaction, targetID graph.ID, relationshipKinds ...graph.Kind) (graph.NodeSet, error) {
+	return ops.FetchStartNodes(tx.Relationships().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Start(), ad.Entity),
+			query.KindIn(query.Relationship(), relationshipKinds...),
+			query.Equals(query.EndID(), targetID),
+		)
+	}))
+}
+
+func expandPrincipals(tx graph.Transaction, principals graph.NodeSet) (graph.NodeSet, error) {
+	if members, err := analysis.ExpandGroupMembership(tx, principals); err != nil {
+		return nil, err
+	} else {
+		expanded := principals.Copy()
+		expanded.AddSet(members)
+
+		return expanded, nil
+	}
+}
+
+func intersectNodeSets(left, right graph.NodeSet) graph.NodeSet {
+	intersection := graph.NewNodeSet()
+
+	for _, node := range left {
+		if right.Contains(node) {
+			intersection.Add(node)
+		}
+	}
+
+	return intersection
+}
+
+// reduceToEdgeSources reduces a set of principals that hold an ESC primitive down to the set of principals that
+// relationships should be created from. Principals that hold one of the required rights directly are kept and
+// anything reachable from them through group membership is dropped since the MemberOf path already describes it.
+func reduceToEdgeSources(tx graph.Transaction, effective, firstDegree graph.NodeSet) (graph.NodeSet, error) {
+	roots := intersectNodeSets(firstDegree, effective)
+
+	if covered, err := expandPrincipals(tx, roots); err != nil {
+		return nil, err
+	} else {
+		sources := roots.Copy()
+
+		for _, principal := range effective {
+			if !covered.Contains(principal) && !principal.Kinds.ContainsOneOf(ad.Group) {
+				sources.Add(principal)
+			}
+		}
+
+		return sources, nil
+	}
+}
+
+type enrollmentPrimitive struct {
+	effective   graph.NodeSet
+	firstDegree graph.NodeSet
+}
+
+func newEnrollmentPrimitive() enrollmentPrimitive {
+	return enrollmentPrimitive{
+		effective:   graph.NewNodeSet(),
+		firstDegree: graph.NewNodeSet(),
+	}
+}
+
+func (s enrollmentPrimitive) add(other enrollmentPrimitive) {
+	s.effective.AddSet(other.effective)
+	s.firstDegree.AddSet(other.firstDegree)
+}
+
+func fetchEnrollmentPrimitive(tx graph.Transaction, templateID graph.ID, templateRelationships []graph.Kind, caEnrollers, expandedCAEnrollers graph.NodeSet) (enrollmentPrimitive, error) {
+	primitive := newEnrollmentPrimitive()
+
+	if templatePrincipals, err := fetchFirstDegreePrincipals(tx, templateID, templateRelationships...); err != nil {
+		return primitive, err
+	} else if expandedTemplatePrincipals, err := expandPrincipals(tx, templatePrincipals); err != nil {
+		return primitive, err
+	} else {
+		primitive.effective.AddSet(intersectNodeSets(expandedTemplatePrincipals, expandedCAEnrollers))
+		primitive.firstDegree.AddSet(templatePrincipals)
+		primitive.firstDegree.AddSet(caEnrollers)
+
+		return primitive, nil
+	}
+}
+
+func fetchDomainControllers(tx graph.Transaction, domain *graph.Node) (graph.NodeSet, error) {
+	if domainSID, err := domain.Properties.Get(ad.DomainSID.String()).String(); err != nil {
+		return nil, err
+	} else if dcGroup, err := tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Node(), ad.Group),
+			query.Equals(query.NodeProperty(common.ObjectID.String()), domainSID+DomainControllersGroupSIDSuffix),
+		)
+	}).First(); err != nil {
+		if graph.IsErrNotFound(err) {
+			return graph.NewNodeSet(), nil
+		}
+
+		return nil, err
+	} else if members, err := analysis.ExpandGroupMembership(tx, graph.NewNodeSet(dcGroup)); err != nil {
+		return nil, err
+	} else {
+		return members.ByKind(ad.Computer), nil
+	}
+}
+
+func fetchAuthenticatedUsersGroup(tx graph.Transaction, domain *graph.Node) (*graph.Node, error) {
+	if domainName, err := domain.Properties.Get(common.Name.String()).String(); err != nil {
+		return nil, err
+	} else {
+		return tx.Nodes().Filterf(func() graph.Criteria {
+			return query.And(
+				query.Kind(query.Node(), ad.Group),
+				query.Equals(query.NodeProperty(common.ObjectID.String()), domainName+AuthenticatedUsersSuffix),
+			)
+		}).First()
+	}
+}
+
+func submitEdgeSources(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob, primitive enrollmentPrimitive, domainID graph.ID, kind graph.Kind) error {
+	if sources, err := reduceToEdgeSources(tx, primitive.effective, primitive.firstDegree); err != nil {
+		return err
+	} else {
+		for _, source := range sources {
+			if !channels.Submit(ctx, outC, analysis.CreatePostRelationshipJob{
+				FromID: source.ID,
+				ToID:   domainID,
+				Kind:   kind,
+			}) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// PostADCSESC creates composite relationships from principals to the domains they can compromise by abusing
+// certificate services. The relationships are named after the escalation technique they represent.
+func PostADCSESC(ctx context.Context, db graph.Database, cache ADCSCache) (*analysis.AtomicPostProcessingStats, error) {
+	var (
+		operation     = analysis.NewPostRelationshipOperation(ctx, db, "ADCS ESC Post Processing")
+		caByDomainIDs = make(map[graph.ID][]*graph.Node)
+	)
+
+	for _, enterpriseCA := range cache.EnterpriseCAs {
+		for _, domainID := range cache.ValidDomains(enterpriseCA) {
+			caByDomainIDs[domainID] = append(caByDomainIDs[domainID], enterpriseCA)
+		}
+	}
+
+	for domainID, enterpriseCAs := range caByDomainIDs {
+		var (
+			innerDomain        = cache.Domains.Get(domainID)
+			innerEnterpriseCAs = enterpriseCAs
+		)
+
+		operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+			var (
+				esc1        = newEnrollmentPrimitive()
+				esc3Agent   = newEnrollmentPrimitive()
+				esc3Target  = newEnrollmentPrimitive()
+				esc4        = newEnrollmentPrimitive()
+				esc6        = newEnrollmentPrimitive()
+				esc8Relayed = false
+			)
+
+			domainControllers, err := fetchDomainControllers(tx, innerDomain)
+			if err != nil {
+				return fmt.Errorf("failed fetching domain controllers for domain %d: %w", innerDomain.ID, err)
+			}
+
+			for _, enterpriseCA := range innerEnterpriseCAs {
+				caEnrollers, err := fetchFirstDegreePrincipals(tx, enterpriseCA.ID, ad.Enroll)
+				if err != nil {
+					return err
+				}
+
+				expandedCAEnrollers, err := expandPrincipals(tx, caEnrollers)
+				if err != nil {
+					return err
+				}
+
+				var (
+					userSpecifiedSAN      = nodeBoolProperty(enterpriseCA, ad.IsUserSpecifiesSanEnabled)
+					agentRestrictions     = nodeBoolProperty(enterpriseCA, ad.HasEnrollmentAgentRestrictions)
+					vulnerableWebEndpoint = nodeBoolProperty(enterpriseCA, ad.HasVulnerableEndpoint)
+				)
+
+				for _, template := range cache.PublishedCertTemplates(enterpriseCA.ID) {
+					enrollment, err := fetchEnrollmentPrimitive(tx, template.ID, EnrollmentRelationships(), caEnrollers, expandedCAEnrollers)
+					if err != nil {
+						return err
+					}
+
+					if IsESC1Template(template) {
+						esc1.add(enrollment)
+					}
+
+					if userSpecifiedSAN && IsESC6Template(template) {
+						esc6.add(enrollment)
+					}
+
+					if IsEnrollmentAgentTemplate(template) {
+						esc3Agent.add(enrollment)
+					}
+
+					// Enrollment agent restrictions are evaluated by the CA at request time. Rather than guess at their
+					// effect, CAs that define any restrictions are not considered for the on-behalf-of request.
+					if !agentRestrictions && IsESC3TargetTemplate(template) {
+						esc3Target.add(enrollment)
+					}
+
+					if vulnerableWebEndpoint && !esc8Relayed && IsESC6Template(template) {
+						for _, domainController := range domainControllers {
+							if enrollment.effective.Contains(domainController) {
+								esc8Relayed = true
+								break
+							}
+						}
+					}
+
+					if writers, err := fetchEnrollmentPrimitive(tx, template.ID, TemplateWriteRelationships(), caEnrollers, expandedCAEnrollers); err != nil {
+						return err
+					} else {
+						esc4.add(writers)
+					}
+				}
+			}
+
+			esc3 := newEnrollmentPrimitive()
+			esc3.effective.AddSet(intersectNodeSets(esc3Agent.effective, esc3Target.effective))
+			esc3.firstDegree.AddSet(esc3Agent.firstDegree)
+			esc3.firstDegree.AddSet(esc3Target.firstDegree)
+
+			for kind, primitive := range map[graph.Kind]enrollmentPrimitive{
+				ad.ADCSESC1: esc1,
+				ad.ADCSESC3: esc3,
+				ad.ADCSESC4: esc4,
+				ad.ADCSESC6: esc6,
+			} {
+				if err := submitEdgeSources(ctx, tx, outC, primitive, innerDomain.ID, kind); err != nil {
+					return err
+				}
+			}
+
+			if esc8Relayed {
+				// Any authenticated principal can coerce a domain controller into authenticating to a host of their
+				// choosing and relay that authentication to the web enrollment endpoint
+				if authenticatedUsers, err := fetchAuthenticatedUsersGroup(tx, innerDomain); err != nil {
+					if !graph.IsErrNotFound(err) {
+						return err
+					}
+				} else {
+					channels.Submit(ctx, outC, analysis.CreatePostRelationshipJob{
+						FromID: authenticatedUsers.ID,
+						ToID:   innerDomain.ID,
+						Kind:   ad.ADCSESC8,
+					})
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return &operation.Stats, operation.Done()
+}
+
+func PostADCS(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	defer log.Measure(log.LevelInfo, "ADCS Post Processing")()
+
+	aggregateStats := analysis.NewAtomicPostProcessingStats()
+
+	if cache, err := FetchADCSCache(ctx, db); err != nil {
+		return &aggregateStats, err
+	} else if cache.EnterpriseCAs.Len() == 0 {
+		return &aggregateStats, nil
+	} else if trustStats, err := PostADCSTrust(ctx, db, cache); err != nil {
+		return &aggregateStats, err
+	} else if escStats, err := PostADCSESC(ctx, db, cache); err != nil {
+		return &aggregateStats, err
+	} else {
+		aggregateStats.Merge(trustStats)
+		aggregateStats.Merge(escStats)
+		return &aggregateStats, nil
+	}
+}
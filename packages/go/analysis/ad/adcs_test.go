@@ -0,0 +1,127 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad_test
+
+import (
+	"testing"
+
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/stretchr/testify/require"
+)
+
+func newCertTemplate(properties map[string]any) *graph.Node {
+	return graph.NewNode(1, graph.AsProperties(properties), ad.Entity, ad.CertTemplate)
+}
+
+func TestIsESC1Template(t *testing.T) {
+	require.True(t, adAnalysis.IsESC1Template(newCertTemplate(map[string]any{
+		ad.AuthenticationEnabled.String():   true,
+		ad.EnrolleeSuppliesSubject.String(): true,
+		ad.SchemaVersion.String():           float64(1),
+	})))
+
+	require.True(t, adAnalysis.IsESC1Template(newCertTemplate(map[string]any{
+		ad.AuthenticationEnabled.String():   true,
+		ad.EnrolleeSuppliesSubject.String(): true,
+		ad.SchemaVersion.String():           int64(2),
+		ad.AuthorizedSignatures.String():    int64(0),
+	})))
+
+	require.False(t, adAnalysis.IsESC1Template(newCertTemplate(map[string]any{
+		ad.AuthenticationEnabled.String():   true,
+		ad.EnrolleeSuppliesSubject.String(): true,
+		ad.RequiresManagerApproval.String(): true,
+	})))
+
+	require.False(t, adAnalysis.IsESC1Template(newCertTemplate(map[string]any{
+		ad.AuthenticationEnabled.String():   true,
+		ad.EnrolleeSuppliesSubject.String(): true,
+		ad.SchemaVersion.String():           int64(2),
+		ad.AuthorizedSignatures.String():    int64(1),
+	})))
+
+	require.False(t, adAnalysis.IsESC1Template(newCertTemplate(map[string]any{
+		ad.EnrolleeSuppliesSubject.String(): true,
+	})))
+}
+
+func TestIsEnrollmentAgentTemplate(t *testing.T) {
+	require.True(t, adAnalysis.IsEnrollmentAgentTemplate(newCertTemplate(map[string]any{
+		ad.EKUs.String(): []any{adAnalysis.EKUCertRequestAgent},
+	})))
+
+	require.True(t, adAnalysis.IsEnrollmentAgentTemplate(newCertTemplate(map[string]any{
+		ad.EKUs.String(): []string{adAnalysis.EKUAnyPurpose},
+	})))
+
+	require.True(t, adAnalysis.IsEnrollmentAgentTemplate(newCertTemplate(map[string]any{})))
+
+	require.False(t, adAnalysis.IsEnrollmentAgentTemplate(newCertTemplate(map[string]any{
+		ad.EKUs.String(): []string{"1.3.6.1.5.5.7.3.2"},
+	})))
+}
+
+func TestIsESC3TargetTemplate(t *testing.T) {
+	require.True(t, adAnalysis.IsESC3TargetTemplate(newCertTemplate(map[string]any{
+		ad.AuthenticationEnabled.String(): true,
+		ad.SchemaVersion.String():         int64(1),
+	})))
+
+	require.True(t, adAnalysis.IsESC3TargetTemplate(newCertTemplate(map[string]any{
+		ad.AuthenticationEnabled.String():        true,
+		ad.SchemaVersion.String():                int64(2),
+		ad.AuthorizedSignatures.String():         int64(1),
+		ad.CertificateApplicationPolicy.String(): []string{adAnalysis.EKUCertRequestAgent},
+	})))
+
+	require.False(t, adAnalysis.IsESC3TargetTemplate(newCertTemplate(map[string]any{
+		ad.AuthenticationEnabled.String(): true,
+		ad.SchemaVersion.String():         int64(2),
+		ad.AuthorizedSignatures.String():  int64(1),
+	})))
+}
+
+func TestADCSCache_ValidDomains(t *testing.T) {
+	var (
+		cache        = adAnalysis.NewADCSCache()
+		domain       = graph.NewNode(1, graph.NewProperties(), ad.Entity, ad.Domain)
+		otherDomain  = graph.NewNode(2, graph.NewProperties(), ad.Entity, ad.Domain)
+		rootCA       = graph.NewNode(3, graph.AsProperties(map[string]any{ad.CertThumbprint.String(): "ROOT"}), ad.Entity, ad.RootCA)
+		ntAuthStore  = graph.NewNode(4, graph.AsProperties(map[string]any{ad.CertThumbprints.String(): []any{"ECA"}}), ad.Entity, ad.NTAuthStore)
+		enterpriseCA = graph.NewNode(5, graph.AsProperties(map[string]any{
+			ad.CertThumbprint.String(): "eca",
+			ad.CertChain.String():      []any{"ECA", "ROOT"},
+		}), ad.Entity, ad.EnterpriseCA)
+	)
+
+	cache.Domains.Add(domain)
+	cache.Domains.Add(otherDomain)
+	cache.RootCAs.Add(rootCA)
+	cache.NTAuthStores.Add(ntAuthStore)
+	cache.EnterpriseCAs.Add(enterpriseCA)
+
+	require.Empty(t, cache.ValidDomains(enterpriseCA))
+
+	cache.RootCAsByDomain[domain.ID] = []graph.ID{rootCA.ID}
+	cache.RootCAsByDomain[otherDomain.ID] = []graph.ID{rootCA.ID}
+	require.Empty(t, cache.ValidDomains(enterpriseCA))
+
+	cache.NTAuthStoresByDomain[domain.ID] = []graph.ID{ntAuthStore.ID}
+	require.Equal(t, []graph.ID{domain.ID}, cache.ValidDomains(enterpriseCA))
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/util/channels"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+)
+
+// RBCDWriteRelationships are the relationship kinds that allow a principal to write the
+// msDS-AllowedToActOnBehalfOfOtherIdentity attribute of a computer
+func RBCDWriteRelationships() []graph.Kind {
+	return []graph.Kind{ad.AddAllowedToAct, ad.WriteAccountRestrictions, ad.GenericAll, ad.GenericWrite}
+}
+
+// IsCoerceToTGTPrincipal returns true if a DC coerced into authenticating to the given principal would leave a TGT of
+// the DC behind. Domain controllers are trusted for unconstrained delegation by default and are not considered.
+func IsCoerceToTGTPrincipal(principal *graph.Node, domainControllers graph.NodeSet) bool {
+	if !nodeBoolProperty(principal, ad.UnconstrainedDelegation) {
+		return false
+	} else if enabled, err := principal.Properties.Get(common.Enabled.String()).Bool(); err == nil && !enabled {
+		return false
+	}
+
+	return !domainControllers.Contains(principal)
+}
+
+// PostCoerceToTGT creates CoerceToTGT relationships from the enabled computers and users of a domain that are trusted
+// for unconstrained delegation to the domain. Compromising such a principal and coercing a DC to authenticate to it
+// yields the TGT of the DC and with it the domain.
+func PostCoerceToTGT(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	if domainNodes, err := fetchCollectedDomainNodes(ctx, db); err != nil {
+		return &analysis.AtomicPostProcessingStats{}, err
+	} else {
+		operation := analysis.NewPostRelationshipOperation(ctx, db, "CoerceToTGT Post Processing")
+
+		for _, domain := range domainNodes {
+			innerDomain := domain
+
+			operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+				if domainSID, err := innerDomain.Properties.Get(ad.DomainSID.String()).String(); err != nil {
+					return err
+				} else if domainControllers, err := fetchDomainControllers(tx, innerDomain); err != nil {
+					return fmt.Errorf("failed fetching domain controllers for domain %d: %w", innerDomain.ID, err)
+				} else if principals, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+					return query.And(
+						query.KindIn(query.Node(), ad.Computer, ad.User),
+						query.Equals(query.NodeProperty(ad.DomainSID.String()), domainSID),
+						query.Equals(query.NodeProperty(ad.UnconstrainedDelegation.String()), true),
+					)
+				})); err != nil {
+					return err
+				} else {
+					for _, principal := range principals {
+						if !IsCoerceToTGTPrincipal(principal, domainControllers) {
+							continue
+						}
+
+						nextJob := analysis.CreatePostRelationshipJob{
+							FromID: principal.ID,
+							ToID:   innerDomain.ID,
+							Kind:   ad.CoerceToTGT,
+						}
+
+						if !channels.Submit(ctx, outC, nextJob) {
+							return nil
+						}
+					}
+
+					return nil
+				}
+			})
+		}
+
+		return &operation.Stats, operation.Done()
+	}
+}
+
+// PostAdminToViaRBCD creates AdminToViaRBCD relationships to the computers of a domain from every principal that is
+// allowed to act on behalf of other identities to the computer or that can write the computer's
+// msDS-AllowedToActOnBehalfOfOtherIdentity attribute. Either lets the principal impersonate an administrator of the
+// computer with S4U2Proxy. Writers additionally need control of an account with an SPN, which the default machine
+// account quota grants to any domain user.
+func PostAdminToViaRBCD(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	if domainNodes, err := fetchCollectedDomainNodes(ctx, db); err != nil {
+		return &analysis.AtomicPostProcessingStats{}, err
+	} else {
+		var (
+			operation = analysis.NewPostRelationshipOperation(ctx, db, "AdminToViaRBCD Post Processing")
+			rbcdKinds = append(RBCDWriteRelationships(), ad.AllowedToAct)
+		)
+
+		for _, domain := range domainNodes {
+			innerDomain := domain
+
+			operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+				if domainSID, err := innerDomain.Properties.Get(ad.DomainSID.String()).String(); err != nil {
+					return err
+				} else if relationships, err := ops.FetchRelationships(tx.Relationships().Filterf(func() graph.Criteria {
+					return query.And(
+						query.KindIn(query.Relationship(), rbcdKinds...),
+						query.Kind(query.End(), ad.Computer),
+						query.Equals(query.EndProperty(ad.DomainSID.String()), domainSID),
+					)
+				})); err != nil {
+					return err
+				} else {
+					submitted := make(map[analysis.CreatePostRelationshipJob]struct{})
+
+					for _, relationship := range relationships {
+						nextJob := analysis.CreatePostRelationshipJob{
+							FromID: relationship.StartID,
+							ToID:   relationship.EndID,
+							Kind:   ad.AdminToViaRBCD,
+						}
+
+						if _, seen := submitted[nextJob]; seen || nextJob.FromID == nextJob.ToID {
+							continue
+						}
+
+						submitted[nextJob] = struct{}{}
+
+						if !channels.Submit(ctx, outC, nextJob) {
+							return nil
+						}
+					}
+
+					return nil
+				}
+			})
+		}
+
+		return &operation.Stats, operation.Done()
+	}
+}
+
+// PostDelegation creates the relationships that represent the abuse of Kerberos delegation
+func PostDelegation(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	defer log.Measure(log.LevelInfo, "Delegation Post Processing")()
+
+	aggregateStats := analysis.NewAtomicPostProcessingStats()
+
+	if coerceStats, err := PostCoerceToTGT(ctx, db); err != nil {
+		return &aggregateStats, err
+	} else if rbcdStats, err := PostAdminToViaRBCD(ctx, db); err != nil {
+		return &aggregateStats, err
+	} else {
+		aggregateStats.Merge(coerceStats)
+		aggregateStats.Merge(rbcdStats)
+		return &aggregateStats, nil
+	}
+}
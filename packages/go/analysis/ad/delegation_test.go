@@ -0,0 +1,55 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad_test
+
+import (
+	"testing"
+
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCoerceToTGTPrincipal(t *testing.T) {
+	var (
+		domainController = graph.NewNode(1, graph.AsProperties(map[string]any{
+			ad.UnconstrainedDelegation.String(): true,
+		}), ad.Entity, ad.Computer)
+
+		delegationHost = graph.NewNode(2, graph.AsProperties(map[string]any{
+			ad.UnconstrainedDelegation.String(): true,
+		}), ad.Entity, ad.Computer)
+
+		disabledDelegationHost = graph.NewNode(3, graph.AsProperties(map[string]any{
+			ad.UnconstrainedDelegation.String(): true,
+			common.Enabled.String():             false,
+		}), ad.Entity, ad.Computer)
+
+		workstation = graph.NewNode(4, graph.AsProperties(map[string]any{
+			ad.UnconstrainedDelegation.String(): false,
+		}), ad.Entity, ad.Computer)
+
+		domainControllers = graph.NewNodeSet(domainController)
+	)
+
+	require.True(t, adAnalysis.IsCoerceToTGTPrincipal(delegationHost, domainControllers))
+	require.False(t, adAnalysis.IsCoerceToTGTPrincipal(domainController, domainControllers))
+	require.False(t, adAnalysis.IsCoerceToTGTPrincipal(disabledDelegationHost, domainControllers))
+	require.False(t, adAnalysis.IsCoerceToTGTPrincipal(workstation, domainControllers))
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad
+
+import (
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+)
+
+// Types of the attack path findings detected in each domain
+const (
+	FindingTierZeroKerberoastable    = "TierZeroKerberoastable"
+	FindingNonTierZeroDCSync         = "NonTierZeroDCSync"
+	FindingTierZeroNonTierZeroLogons = "TierZeroNonTierZeroLogons"
+	FindingForeignAdmins             = "ForeignAdmins"
+)
+
+// KRBTGTSIDSuffix is the relative identifier of the krbtgt account of a domain
+const KRBTGTSIDSuffix = "-502"
+
+// FindingDetection fetches the principals of a domain that are affected by one type of attack path finding
+type FindingDetection struct {
+	FindingType string
+	Description string
+	Fetch       func(tx graph.Transaction, domain *graph.Node) (graph.NodeSet, error)
+}
+
+// FindingDetections returns the catalogue of attack path findings detected in each domain after analysis
+func FindingDetections() []FindingDetection {
+	return []FindingDetection{{
+		FindingType: FindingTierZeroKerberoastable,
+		Description: "Enabled tier zero users with a service principal name whose password can be cracked offline",
+		Fetch:       FetchTierZeroKerberoastableUsers,
+	}, {
+		FindingType: FindingNonTierZeroDCSync,
+		Description: "Principals outside of tier zero that can replicate the secrets of the domain",
+		Fetch:       FetchNonTierZeroDCSyncers,
+	}, {
+		FindingType: FindingTierZeroNonTierZeroLogons,
+		Description: "Tier zero users with sessions on computers outside of tier zero",
+		Fetch:       FetchTierZeroNonTierZeroLogons,
+	}, {
+		FindingType: FindingForeignAdmins,
+		Description: "Principals of other domains with administrative rights on computers of the domain",
+		Fetch: func(tx graph.Transaction, domain *graph.Node) (graph.NodeSet, error) {
+			return FetchForeignAdmins(tx, domain, 0, 0)
+		},
+	}}
+}
+
+// FindingDetectionByType returns the detection of the given finding type
+func FindingDetectionByType(findingType string) (FindingDetection, bool) {
+	for _, detection := range FindingDetections() {
+		if detection.FindingType == findingType {
+			return detection, true
+		}
+	}
+
+	return FindingDetection{}, false
+}
+
+// FetchTierZeroKerberoastableUsers returns the enabled tier zero users of the domain that have a service principal
+// name. The krbtgt account is excluded as its password is not chosen by a person.
+func FetchTierZeroKerberoastableUsers(tx graph.Transaction, domain *graph.Node) (graph.NodeSet, error) {
+	if domainSID, err := domain.Properties.Get(ad.DomainSID.String()).String(); err != nil {
+		return nil, err
+	} else {
+		return ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
+			return query.And(
+				query.Kind(query.Node(), ad.User),
+				query.Equals(query.NodeProperty(ad.DomainSID.String()), domainSID),
+				query.Equals(query.NodeProperty(ad.HasSPN.String()), true),
+				query.Equals(query.NodeProperty(common.Enabled.String()), true),
+				query.StringContains(query.NodeProperty(common.SystemTags.String()), ad.AdminTierZero),
+				query.Not(query.StringEndsWith(query.NodeProperty(common.ObjectID.String()), KRBTGTSIDSuffix)),
+			)
+		}))
+	}
+}
+
+// FetchNonTierZeroDCSyncers returns the principals that hold DCSync on the domain without being tagged as tier zero
+func FetchNonTierZeroDCSyncers(tx graph.Transaction, domain *graph.Node) (graph.NodeSet, error) {
+	return ops.FetchStartNodes(tx.Relationships().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Equals(query.EndID(), domain.ID),
+			query.Kind(query.Relationship(), ad.DCSync),
+			query.Not(query.StringContains(query.StartProperty(common.SystemTags.String()), ad.AdminTierZero)),
+		)
+	}))
+}
+
+// FetchTierZeroNonTierZeroLogons returns the tier zero users that have sessions on computers of the domain that are not
+// tagged as tier zero
+func FetchTierZeroNonTierZeroLogons(tx graph.Transaction, domain *graph.Node) (graph.NodeSet, error) {
+	if domainSID, err := domain.Properties.Get(ad.DomainSID.String()).String(); err != nil {
+		return nil, err
+	} else {
+		return ops.FetchEndNodes(tx.Relationships().Filterf(func() graph.Criteria {
+			return query.And(
+				query.Kind(query.Start(), ad.Computer),
+				query.Kind(query.Relationship(), ad.HasSession),
+				query.Kind(query.End(), ad.User),
+				query.Equals(query.StartProperty(ad.DomainSID.String()), domainSID),
+				query.Not(query.StringContains(query.StartProperty(common.SystemTags.String()), ad.AdminTierZero)),
+				query.StringContains(query.EndProperty(common.SystemTags.String()), ad.AdminTierZero),
+			)
+		}))
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad_test
+
+import (
+	"context"
+	"testing"
+
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/stretchr/testify/require"
+)
+
+const findingsDomainSID = "S-1-5-21-1"
+
+type findingsGraph struct {
+	Domain              *graph.Node
+	TierZeroServiceUser *graph.Node
+	TierZeroAdmin       *graph.Node
+	KRBTGT              *graph.Node
+	ServiceUser         *graph.Node
+	DCSyncUser          *graph.Node
+	TierZeroGroup       *graph.Node
+	Workstation         *graph.Node
+	DomainController    *graph.Node
+}
+
+func newFindingsGraph(t *testing.T, db graph.Database) findingsGraph {
+	var harness findingsGraph
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(objectID string, properties map[string]any, kinds ...graph.Kind) *graph.Node {
+			properties[common.ObjectID.String()] = objectID
+			properties[ad.DomainSID.String()] = findingsDomainSID
+
+			node, err := tx.CreateNode(graph.AsProperties(properties), kinds...)
+			require.Nil(t, err)
+
+			return node
+		}
+
+		newRelationship := func(start, end *graph.Node, kind graph.Kind) {
+			_, err := tx.CreateRelationshipByIDs(start.ID, end.ID, kind, graph.NewProperties())
+			require.Nil(t, err)
+		}
+
+		harness.Domain = newNode(findingsDomainSID, map[string]any{
+			common.SystemTags.String(): ad.AdminTierZero,
+		}, ad.Entity, ad.Domain)
+
+		harness.TierZeroServiceUser = newNode(findingsDomainSID+"-1101", map[string]any{
+			ad.HasSPN.String():         true,
+			common.Enabled.String():    true,
+			common.SystemTags.String(): ad.AdminTierZero,
+		}, ad.Entity, ad.User)
+
+		harness.TierZeroAdmin = newNode(findingsDomainSID+"-500", map[string]any{
+			ad.HasSPN.String():         false,
+			common.Enabled.String():    true,
+			common.SystemTags.String(): ad.AdminTierZero,
+		}, ad.Entity, ad.User)
+
+		harness.KRBTGT = newNode(findingsDomainSID+adAnalysis.KRBTGTSIDSuffix, map[string]any{
+			ad.HasSPN.String():         true,
+			common.Enabled.String():    true,
+			common.SystemTags.String(): ad.AdminTierZero,
+		}, ad.Entity, ad.User)
+
+		harness.ServiceUser = newNode(findingsDomainSID+"-1102", map[string]any{
+			ad.HasSPN.String():      true,
+			common.Enabled.String(): true,
+		}, ad.Entity, ad.User)
+
+		harness.DCSyncUser = newNode(findingsDomainSID+"-1103", map[string]any{}, ad.Entity, ad.User)
+
+		harness.TierZeroGroup = newNode(findingsDomainSID+"-512", map[string]any{
+			common.SystemTags.String(): ad.AdminTierZero,
+		}, ad.Entity, ad.Group)
+
+		harness.Workstation = newNode(findingsDomainSID+"-1104", map[string]any{}, ad.Entity, ad.Computer)
+
+		harness.DomainController = newNode(findingsDomainSID+"-1000", map[string]any{
+			common.SystemTags.String(): ad.AdminTierZero,
+		}, ad.Entity, ad.Computer)
+
+		newRelationship(harness.DCSyncUser, harness.Domain, ad.DCSync)
+		newRelationship(harness.TierZeroGroup, harness.Domain, ad.DCSync)
+		newRelationship(harness.Workstation, harness.TierZeroAdmin, ad.HasSession)
+		newRelationship(harness.Workstation, harness.ServiceUser, ad.HasSession)
+		newRelationship(harness.DomainController, harness.TierZeroServiceUser, ad.HasSession)
+
+		return nil
+	}))
+
+	return harness
+}
+
+func TestFindingDetections(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newFindingsGraph(t, db)
+	)
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		if kerberoastable, err := adAnalysis.FetchTierZeroKerberoastableUsers(tx, harness.Domain); err != nil {
+			return err
+		} else {
+			require.Equal(t, []graph.ID{harness.TierZeroServiceUser.ID}, kerberoastable.IDs())
+		}
+
+		if dcSyncers, err := adAnalysis.FetchNonTierZeroDCSyncers(tx, harness.Domain); err != nil {
+			return err
+		} else {
+			require.Equal(t, []graph.ID{harness.DCSyncUser.ID}, dcSyncers.IDs())
+		}
+
+		if logons, err := adAnalysis.FetchTierZeroNonTierZeroLogons(tx, harness.Domain); err != nil {
+			return err
+		} else {
+			require.Equal(t, []graph.ID{harness.TierZeroAdmin.ID}, logons.IDs())
+		}
+
+		return nil
+	}))
+}
+
+func TestFindingDetectionByType(t *testing.T) {
+	for _, detection := range adAnalysis.FindingDetections() {
+		found, ok := adAnalysis.FindingDetectionByType(detection.FindingType)
+		require.True(t, ok)
+		require.Equal(t, detection.Description, found.Description)
+	}
+
+	_, ok := adAnalysis.FindingDetectionByType("NotAFinding")
+	require.False(t, ok)
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad
+
+import (
+	"context"
+	"strings"
+
+	"github.com/specterops/bloodhound/analysis"
+	azureAnalysis "github.com/specterops/bloodhound/analysis/azure"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/util/channels"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+)
+
+// EntraSyncAccountPrefix is the name prefix of the Entra ID accounts created by Entra Connect. Each account is named
+// Sync_<computer>_<installation id> after the host that Entra Connect was installed on.
+const EntraSyncAccountPrefix = "SYNC_"
+
+// HybridPostProcessedRelationships are the post-processed relationships that start in Active Directory and end in
+// Entra ID
+func HybridPostProcessedRelationships() []graph.Kind {
+	return []graph.Kind{
+		ad.SyncedToEntraUser,
+		ad.HostsEntraSyncAccount,
+	}
+}
+
+// EntraSyncAccountHost returns the upper case name of the computer that hosts Entra Connect for the given Entra ID sync
+// account name or user principal name
+func EntraSyncAccountHost(name string) (string, bool) {
+	accountName, _, _ := strings.Cut(strings.ToUpper(name), "@")
+
+	if !strings.HasPrefix(accountName, EntraSyncAccountPrefix) {
+		return "", false
+	} else if separatorIdx := strings.LastIndex(accountName, "_"); separatorIdx <= len(EntraSyncAccountPrefix) {
+		return "", false
+	} else {
+		return accountName[len(EntraSyncAccountPrefix):separatorIdx], true
+	}
+}
+
+// PostSyncedToEntraUser creates SyncedToEntraUser relationships from Active Directory users to the Entra ID users that
+// are synchronized from them. With password hash sync or pass-through authentication the password of the Active
+// Directory user authenticates the Entra ID user.
+func PostSyncedToEntraUser(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	operation := analysis.NewPostRelationshipOperation(ctx, db, "SyncedToEntraUser Post Processing")
+
+	operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+		if hybridUsers, err := azureAnalysis.FetchHybridUsers(tx); err != nil {
+			return err
+		} else {
+			for _, hybridUser := range hybridUsers {
+				nextJob := analysis.CreatePostRelationshipJob{
+					FromID: hybridUser.OnPremUserID,
+					ToID:   hybridUser.EntraUserID,
+					Kind:   ad.SyncedToEntraUser,
+				}
+
+				if !channels.Submit(ctx, outC, nextJob) {
+					return nil
+				}
+			}
+
+			return nil
+		}
+	})
+
+	return &operation.Stats, operation.Done()
+}
+
+// fetchEntraSyncedDomainSIDs returns the SIDs of the Active Directory domains that have users synchronized to Entra ID
+// users of the given tenant. SyncedToEntraUser relationships must be post-processed first.
+func fetchEntraSyncedDomainSIDs(tx graph.Transaction, tenantID string) ([]string, error) {
+	if onPremUsers, err := ops.FetchStartNodes(tx.Relationships().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Relationship(), ad.SyncedToEntraUser),
+			query.Equals(query.EndProperty(azure.TenantID.String()), tenantID),
+		)
+	})); err != nil {
+		return nil, err
+	} else {
+		var (
+			domainSIDs []string
+			seen       = map[string]struct{}{}
+		)
+
+		for _, onPremUser := range onPremUsers {
+			if domainSID, err := onPremUser.Properties.Get(ad.DomainSID.String()).String(); err != nil || domainSID == "" {
+				continue
+			} else if _, isSeen := seen[domainSID]; !isSeen {
+				seen[domainSID] = struct{}{}
+				domainSIDs = append(domainSIDs, domainSID)
+			}
+		}
+
+		return domainSIDs, nil
+	}
+}
+
+// PostHostsEntraSyncAccount creates HostsEntraSyncAccount relationships from the computers that host Entra Connect to
+// the Entra ID sync accounts that Entra Connect authenticates as. The credentials of the sync account can be recovered by
+// an administrator of the computer and the account is allowed to reset the passwords of synchronized users.
+//
+// Sync accounts only name the host by its short name, so the search for the host is limited to the domains that have
+// users synchronized to the tenant of the sync account. This keeps same-named computers of unrelated forests from being
+// linked to the sync account.
+func PostHostsEntraSyncAccount(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	operation := analysis.NewPostRelationshipOperation(ctx, db, "HostsEntraSyncAccount Post Processing")
+
+	operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+		if syncAccounts, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+			return query.And(
+				query.Kind(query.Node(), azure.User),
+				query.StringStartsWith(query.NodeProperty(common.Name.String()), EntraSyncAccountPrefix),
+			)
+		})); err != nil {
+			return err
+		} else {
+			for _, syncAccount := range syncAccounts {
+				if name, err := syncAccount.Properties.Get(common.Name.String()).String(); err != nil {
+					continue
+				} else if hostName, isSyncAccount := EntraSyncAccountHost(name); !isSyncAccount {
+					continue
+				} else if tenantID, err := syncAccount.Properties.Get(azure.TenantID.String()).String(); err != nil || tenantID == "" {
+					continue
+				} else if domainSIDs, err := fetchEntraSyncedDomainSIDs(tx, tenantID); err != nil {
+					return err
+				} else if len(domainSIDs) == 0 {
+					continue
+				} else if computers, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+					return query.And(
+						query.Kind(query.Node(), ad.Computer),
+						query.StringStartsWith(query.NodeProperty(common.Name.String()), hostName+"."),
+						query.In(query.NodeProperty(ad.DomainSID.String()), domainSIDs),
+					)
+				})); err != nil {
+					return err
+				} else {
+					for _, computer := range computers {
+						nextJob := analysis.CreatePostRelationshipJob{
+							FromID: computer.ID,
+							ToID:   syncAccount.ID,
+							Kind:   ad.HostsEntraSyncAccount,
+						}
+
+						if !channels.Submit(ctx, outC, nextJob) {
+							return nil
+						}
+					}
+				}
+			}
+
+			return nil
+		}
+	})
+
+	return &operation.Stats, operation.Done()
+}
+
+// PostHybrid creates the relationships that link Active Directory principals to Entra ID principals
+func PostHybrid(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	defer log.Measure(log.LevelInfo, "Hybrid Post Processing")()
+
+	aggregateStats := analysis.NewAtomicPostProcessingStats()
+
+	if syncedUserStats, err := PostSyncedToEntraUser(ctx, db); err != nil {
+		return &aggregateStats, err
+	} else if syncAccountStats, err := PostHostsEntraSyncAccount(ctx, db); err != nil {
+		return &aggregateStats, err
+	} else {
+		aggregateStats.Merge(syncedUserStats)
+		aggregateStats.Merge(syncAccountStats)
+		return &aggregateStats, nil
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ad_test
+
+import (
+	"context"
+	"testing"
+
+	adAnalysis "github.com/specterops/bloodhound/analysis/ad"
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntraSyncAccountHost(t *testing.T) {
+	hostName, isSyncAccount := adAnalysis.EntraSyncAccountHost("Sync_AADCONNECT01_1a2b3c4d5e6f@contoso.onmicrosoft.com")
+	require.True(t, isSyncAccount)
+	require.Equal(t, "AADCONNECT01", hostName)
+
+	hostName, isSyncAccount = adAnalysis.EntraSyncAccountHost("SYNC_AAD_CONNECT_1A2B3C4D5E6F@CONTOSO.ONMICROSOFT.COM")
+	require.True(t, isSyncAccount)
+	require.Equal(t, "AAD_CONNECT", hostName)
+
+	_, isSyncAccount = adAnalysis.EntraSyncAccountHost("SYNC_1A2B3C4D5E6F@CONTOSO.ONMICROSOFT.COM")
+	require.False(t, isSyncAccount)
+
+	_, isSyncAccount = adAnalysis.EntraSyncAccountHost("ALICE@CONTOSO.COM")
+	require.False(t, isSyncAccount)
+}
+
+func TestPostHostsEntraSyncAccount(t *testing.T) {
+	var (
+		db                      = memory.NewDatabase()
+		syncAccount, syncedHost *graph.Node
+	)
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(properties map[string]any, kinds ...graph.Kind) *graph.Node {
+			node, err := tx.CreateNode(graph.AsProperties(properties), kinds...)
+			require.Nil(t, err)
+
+			return node
+		}
+
+		syncAccount = newNode(map[string]any{
+			common.Name.String():    "SYNC_AADCONNECT01_1A2B3C4D5E6F@CONTOSO.ONMICROSOFT.COM",
+			azure.TenantID.String(): "tenant-1",
+		}, azure.Entity, azure.User)
+
+		entraUser := newNode(map[string]any{
+			common.Name.String():    "ALICE@CONTOSO.COM",
+			azure.TenantID.String(): "tenant-1",
+		}, azure.Entity, azure.User)
+
+		onPremUser := newNode(map[string]any{
+			common.Name.String():  "ALICE@CONTOSO.LOCAL",
+			ad.DomainSID.String(): "S-1-5-21-1",
+		}, ad.Entity, ad.User)
+
+		// The host of Entra Connect in the synchronized domain and a computer of the same name in an unrelated forest
+		syncedHost = newNode(map[string]any{
+			common.Name.String():  "AADCONNECT01.CONTOSO.LOCAL",
+			ad.DomainSID.String(): "S-1-5-21-1",
+		}, ad.Entity, ad.Computer)
+
+		newNode(map[string]any{
+			common.Name.String():  "AADCONNECT01.FABRIKAM.LOCAL",
+			ad.DomainSID.String(): "S-1-5-21-2",
+		}, ad.Entity, ad.Computer)
+
+		_, err := tx.CreateRelationshipByIDs(onPremUser.ID, entraUser.ID, ad.SyncedToEntraUser, graph.NewProperties())
+		return err
+	}))
+
+	_, err := adAnalysis.PostHostsEntraSyncAccount(context.Background(), db)
+	require.Nil(t, err)
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		if hosts, err := ops.FetchStartNodes(tx.Relationships().Filterf(func() graph.Criteria {
+			return query.And(
+				query.Kind(query.Relationship(), ad.HostsEntraSyncAccount),
+				query.Equals(query.EndID(), syncAccount.ID),
+			)
+		})); err != nil {
+			return err
+		} else {
+			require.Equal(t, []graph.ID{syncedHost.ID}, hosts.IDs())
+		}
+
+		return nil
+	}))
+}
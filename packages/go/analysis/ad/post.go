@@ -40,6 +40,19 @@ func PostProcessedRelationships() []graph.Kind {
 		ad.AdminTo,
 		ad.CanPSRemote,
 		ad.ExecuteDCOM,
+		ad.TrustedForNTAuth,
+		ad.IssuedSignedBy,
+		ad.EnterpriseCAFor,
+		ad.GoldenCert,
+		ad.ADCSESC1,
+		ad.ADCSESC3,
+		ad.ADCSESC4,
+		ad.ADCSESC6,
+		ad.ADCSESC8,
+		ad.CoerceToTGT,
+		ad.AdminToViaRBCD,
+		ad.SyncedToEntraUser,
+		ad.HostsEntraSyncAccount,
 	}
 }
 
@@ -0,0 +1,141 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package azure
+
+import (
+	"context"
+	"strings"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/util/channels"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+)
+
+// HybridUser links an Active Directory user to an Entra ID user that is synchronized from it
+type HybridUser struct {
+	OnPremUserID graph.ID
+	EntraUserID  graph.ID
+}
+
+// SyncedOnPremSID returns the SID of the Active Directory user that the given Entra ID user is synchronized from. Users
+// that are not synchronized or that are missing an on-prem identifier are not considered hybrid.
+func SyncedOnPremSID(entraUser *graph.Node) (string, bool) {
+	if syncEnabled, err := entraUser.Properties.Get(azure.OnPremSyncEnabled.String()).Bool(); err != nil || !syncEnabled {
+		return "", false
+	} else if onPremID, err := entraUser.Properties.Get(azure.OnPremID.String()).String(); err != nil || onPremID == "" {
+		return "", false
+	} else {
+		return strings.ToUpper(onPremID), true
+	}
+}
+
+// MatchHybridUsers pairs Entra ID users with the Active Directory users they are synchronized from by comparing the
+// on-prem SID of the Entra ID user with the object ID of the Active Directory user
+func MatchHybridUsers(entraUsers, onPremUsers []*graph.Node) []HybridUser {
+	var (
+		hybridUsers          []HybridUser
+		entraUsersByOnPremID = make(map[string][]graph.ID, len(entraUsers))
+	)
+
+	for _, entraUser := range entraUsers {
+		if onPremSID, isSynced := SyncedOnPremSID(entraUser); isSynced {
+			entraUsersByOnPremID[onPremSID] = append(entraUsersByOnPremID[onPremSID], entraUser.ID)
+		}
+	}
+
+	for _, onPremUser := range onPremUsers {
+		if objectID, err := onPremUser.Properties.Get(common.ObjectID.String()).String(); err != nil {
+			continue
+		} else {
+			for _, entraUserID := range entraUsersByOnPremID[strings.ToUpper(objectID)] {
+				hybridUsers = append(hybridUsers, HybridUser{
+					OnPremUserID: onPremUser.ID,
+					EntraUserID:  entraUserID,
+				})
+			}
+		}
+	}
+
+	return hybridUsers
+}
+
+// FetchHybridUsers returns every Active Directory user in the graph that has a synchronized Entra ID user
+func FetchHybridUsers(tx graph.Transaction) ([]HybridUser, error) {
+	if entraUsers, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+		return query.And(
+			query.Kind(query.Node(), azure.User),
+			query.Equals(query.NodeProperty(azure.OnPremSyncEnabled.String()), true),
+		)
+	})); err != nil {
+		return nil, err
+	} else if len(entraUsers) == 0 {
+		return nil, nil
+	} else {
+		onPremSIDs := make([]string, 0, len(entraUsers))
+
+		for _, entraUser := range entraUsers {
+			if onPremSID, isSynced := SyncedOnPremSID(entraUser); isSynced {
+				onPremSIDs = append(onPremSIDs, onPremSID)
+			}
+		}
+
+		if onPremUsers, err := ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
+			return query.And(
+				query.Kind(query.Node(), ad.User),
+				query.In(query.NodeProperty(common.ObjectID.String()), onPremSIDs),
+			)
+		})); err != nil {
+			return nil, err
+		} else {
+			return MatchHybridUsers(entraUsers, onPremUsers), nil
+		}
+	}
+}
+
+// PostSyncedToADUser creates SyncedToADUser relationships from synchronized Entra ID users to the Active Directory users
+// they are synchronized from. Password writeback lets a principal that can reset the password of the Entra ID user set
+// the password of the Active Directory user as well.
+func PostSyncedToADUser(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+	operation := analysis.NewPostRelationshipOperation(ctx, db, "SyncedToADUser Post Processing")
+
+	operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
+		if hybridUsers, err := FetchHybridUsers(tx); err != nil {
+			return err
+		} else {
+			for _, hybridUser := range hybridUsers {
+				nextJob := analysis.CreatePostRelationshipJob{
+					FromID: hybridUser.EntraUserID,
+					ToID:   hybridUser.OnPremUserID,
+					Kind:   azure.SyncedToADUser,
+				}
+
+				if !channels.Submit(ctx, outC, nextJob) {
+					return nil
+				}
+			}
+
+			return nil
+		}
+	})
+
+	return &operation.Stats, operation.Done()
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package azure_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/analysis/azure"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	azschema "github.com/specterops/bloodhound/graphschema/azure"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncedOnPremSID(t *testing.T) {
+	onPremSID, isSynced := azure.SyncedOnPremSID(graph.NewNode(1, graph.AsProperties(map[string]any{
+		azschema.OnPremSyncEnabled.String(): true,
+		azschema.OnPremID.String():          "s-1-5-21-1000-1105",
+	}), azschema.Entity, azschema.User))
+
+	require.True(t, isSynced)
+	require.Equal(t, "S-1-5-21-1000-1105", onPremSID)
+
+	_, isSynced = azure.SyncedOnPremSID(graph.NewNode(2, graph.AsProperties(map[string]any{
+		azschema.OnPremSyncEnabled.String(): false,
+		azschema.OnPremID.String():          "S-1-5-21-1000-1105",
+	}), azschema.Entity, azschema.User))
+
+	require.False(t, isSynced)
+
+	_, isSynced = azure.SyncedOnPremSID(graph.NewNode(3, graph.AsProperties(map[string]any{
+		azschema.OnPremSyncEnabled.String(): true,
+	}), azschema.Entity, azschema.User))
+
+	require.False(t, isSynced)
+}
+
+func TestMatchHybridUsers(t *testing.T) {
+	var (
+		syncedEntraUser = graph.NewNode(1, graph.AsProperties(map[string]any{
+			azschema.OnPremSyncEnabled.String(): true,
+			azschema.OnPremID.String():          "S-1-5-21-1000-1105",
+		}), azschema.Entity, azschema.User)
+
+		cloudOnlyEntraUser = graph.NewNode(2, graph.AsProperties(map[string]any{
+			azschema.OnPremSyncEnabled.String(): false,
+		}), azschema.Entity, azschema.User)
+
+		syncedOnPremUser = graph.NewNode(3, graph.AsProperties(map[string]any{
+			common.ObjectID.String(): "S-1-5-21-1000-1105",
+		}), ad.Entity, ad.User)
+
+		unsyncedOnPremUser = graph.NewNode(4, graph.AsProperties(map[string]any{
+			common.ObjectID.String(): "S-1-5-21-1000-1106",
+		}), ad.Entity, ad.User)
+	)
+
+	hybridUsers := azure.MatchHybridUsers(
+		[]*graph.Node{syncedEntraUser, cloudOnlyEntraUser},
+		[]*graph.Node{syncedOnPremUser, unsyncedOnPremUser},
+	)
+
+	require.Equal(t, []azure.HybridUser{{
+		OnPremUserID: syncedOnPremUser.ID,
+		EntraUserID:  syncedEntraUser.ID,
+	}}, hybridUsers)
+}
@@ -110,6 +110,15 @@ func AzurePostProcessedRelationships() []graph.Kind {
 		azure.AZMGAddSecret,
 		azure.AZMGGrantAppRoles,
 		azure.AZMGGrantRole,
+		azure.SyncedToADUser,
+	}
+}
+
+// AzureHybridPostProcessedRelationships are the post-processed relationships that start in Entra ID and end in Active
+// Directory
+func AzureHybridPostProcessedRelationships() []graph.Kind {
+	return []graph.Kind{
+		azure.SyncedToADUser,
 	}
 }
 
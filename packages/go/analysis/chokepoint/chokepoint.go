@@ -0,0 +1,324 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package chokepoint
+
+import (
+	"context"
+	"sort"
+
+	"github.com/specterops/bloodhound/analysis/impact"
+	"github.com/specterops/bloodhound/dawgs/cardinality"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/log"
+)
+
+// Finding is a node or relationship whose removal cuts every path from one or more principals into the analyzed
+// roots. Relationship findings set Relationship and End, the node the relationship points to. AffectedPrincipals
+// counts the principals that would no longer have a path into the roots.
+type Finding struct {
+	Node               *graph.Node
+	Relationship       *graph.Relationship
+	End                *graph.Node
+	AffectedPrincipals int64
+}
+
+func (s Finding) IsRelationship() bool {
+	return s.Relationship != nil
+}
+
+// Plan describes a choke point analysis of every path into the given roots over relationships of the given kinds.
+// Principals are the nodes that match one of PrincipalKinds. Limit caps the number of findings returned and is
+// unbounded when zero.
+type Plan struct {
+	Roots             graph.NodeSet
+	RelationshipKinds graph.Kinds
+	PrincipalKinds    graph.Kinds
+	Limit             int
+}
+
+// pathGraph is the subgraph of every node with a path into the roots of a plan
+type pathGraph struct {
+	roots    graph.NodeSet
+	nodes    graph.NodeSet
+	outbound map[graph.ID][]*graph.Relationship
+	inbound  map[graph.ID][]graph.ID
+}
+
+// fetchPathGraph walks every path into the given roots with an inbound breadth-first search. Each depth of the search
+// fetches the relationships of its entire frontier with a single query.
+func fetchPathGraph(ctx context.Context, db graph.Database, plan Plan) (pathGraph, error) {
+	var (
+		paths = pathGraph{
+			roots:    plan.Roots,
+			nodes:    graph.NewNodeSet(),
+			outbound: map[graph.ID][]*graph.Relationship{},
+			inbound:  map[graph.ID][]graph.ID{},
+		}
+		frontier = plan.Roots.IDs()
+	)
+
+	paths.nodes.AddSet(plan.Roots)
+
+	return paths, db.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		for len(frontier) > 0 {
+			var nextFrontier []graph.ID
+
+			if err := tx.Relationships().Filter(query.And(
+				query.KindIn(query.Relationship(), plan.RelationshipKinds...),
+				query.InIDs(query.EndID(), frontier...),
+			)).FetchDirection(graph.DirectionOutbound, func(cursor graph.Cursor[graph.DirectionalResult]) error {
+				for result := range cursor.Chan() {
+					var (
+						relationship = result.Relationship
+						start        = result.Node
+					)
+
+					// Relationships that start at a root or loop back to their start do not contribute to any path into
+					// the roots
+					if relationship.StartID == relationship.EndID || plan.Roots.Contains(start) {
+						continue
+					}
+
+					if !paths.nodes.Contains(start) {
+						paths.nodes.Add(start)
+						nextFrontier = append(nextFrontier, start.ID)
+					}
+
+					paths.outbound[start.ID] = append(paths.outbound[start.ID], relationship)
+					paths.inbound[relationship.EndID] = append(paths.inbound[relationship.EndID], start.ID)
+				}
+
+				return cursor.Error()
+			}); err != nil {
+				return err
+			}
+
+			frontier = nextFrontier
+		}
+
+		return nil
+	})
+}
+
+// dominators computes the immediate dominator of every node in the path graph. A node dominates another when every path
+// from the other node into the roots passes through it. Nodes that are dominated only by the roots as a whole are
+// mapped to themselves.
+//
+// Dominators are computed with the iterative algorithm described in "A Simple, Fast Dominance Algorithm" by Cooper,
+// Harvey and Kennedy over the reverse of the path graph, rooted at a virtual node that precedes every root.
+func (s pathGraph) dominators() map[graph.ID]graph.ID {
+	const undefined = -1
+
+	var (
+		postOrder      = map[graph.ID]int{}
+		order          []graph.ID
+		visited        = map[graph.ID]struct{}{}
+		immediateOrder = map[int]int{}
+	)
+
+	// Number each node in post order with an iterative depth-first search from every root
+	for _, root := range s.roots.Slice() {
+		type frame struct {
+			id   graph.ID
+			next int
+		}
+
+		if _, seen := visited[root.ID]; seen {
+			continue
+		}
+
+		visited[root.ID] = struct{}{}
+		stack := []*frame{{id: root.ID}}
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+
+			if predecessors := s.inbound[top.id]; top.next < len(predecessors) {
+				predecessor := predecessors[top.next]
+				top.next++
+
+				if _, seen := visited[predecessor]; !seen {
+					visited[predecessor] = struct{}{}
+					stack = append(stack, &frame{id: predecessor})
+				}
+			} else {
+				stack = stack[:len(stack)-1]
+				postOrder[top.id] = len(order)
+				order = append(order, top.id)
+			}
+		}
+	}
+
+	// The virtual root follows every node in post order
+	virtualRootOrder := len(order)
+	immediateOrder[virtualRootOrder] = virtualRootOrder
+
+	intersect := func(left, right int) int {
+		for left != right {
+			for left < right {
+				left = immediateOrder[left]
+			}
+
+			for right < left {
+				right = immediateOrder[right]
+			}
+		}
+
+		return left
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		// Walk nodes in reverse post order
+		for idx := len(order) - 1; idx >= 0; idx-- {
+			var (
+				node         = order[idx]
+				newImmediate = undefined
+			)
+
+			if s.roots.ContainsID(node) {
+				newImmediate = virtualRootOrder
+			}
+
+			// The successors of a node in the path graph are its predecessors in the reverse of the path graph
+			for _, relationship := range s.outbound[node] {
+				if successorOrder := postOrder[relationship.EndID]; hasOrder(immediateOrder, successorOrder) {
+					if newImmediate == undefined {
+						newImmediate = successorOrder
+					} else {
+						newImmediate = intersect(successorOrder, newImmediate)
+					}
+				}
+			}
+
+			if current, hasCurrent := immediateOrder[idx]; !hasCurrent || current != newImmediate {
+				immediateOrder[idx] = newImmediate
+				changed = true
+			}
+		}
+	}
+
+	immediateDominators := make(map[graph.ID]graph.ID, len(order))
+
+	for idx, node := range order {
+		if immediate := immediateOrder[idx]; immediate == virtualRootOrder {
+			immediateDominators[node] = node
+		} else {
+			immediateDominators[node] = order[immediate]
+		}
+	}
+
+	return immediateDominators
+}
+
+func hasOrder(orders map[int]int, order int) bool {
+	_, hasOrder := orders[order]
+	return hasOrder
+}
+
+// affectedPrincipals aggregates the principals dominated by each node of the path graph. Each edge of the dominator
+// tree is encoded as a shortcut so that the impact aggregator rolls the principals of every subtree up to its
+// dominators when resolved. Segments of the dominator tree do not correspond to relationships in the graph and have no
+// edge.
+func (s pathGraph) affectedPrincipals(immediateDominators map[graph.ID]graph.ID, principalKinds graph.Kinds) impact.Aggregator {
+	aggregator := impact.NewAggregator(func() cardinality.Provider[uint32] {
+		return cardinality.NewBitmap32()
+	})
+
+	for node, immediateDominator := range immediateDominators {
+		if node != immediateDominator {
+			dominatorSegment := graph.NewRootPathSegment(s.nodes.Get(immediateDominator))
+			aggregator.AddShortcut(dominatorSegment.Descend(s.nodes.Get(node), nil), principalKinds)
+		}
+	}
+
+	return aggregator
+}
+
+// Analyze finds the nodes and relationships whose removal cuts every path into the roots of the plan for the largest
+// number of principals. Findings are ranked by the number of principals affected.
+//
+// A node is a choke point for every principal that it dominates in the graph of paths into the roots. A relationship
+// is a choke point when it is the only relationship that continues a path from its start node, in which case it cuts
+// the paths of its start node and of every principal that its start node dominates.
+func Analyze(ctx context.Context, db graph.Database, plan Plan) ([]Finding, error) {
+	defer log.Measure(log.LevelInfo, "Choke point analysis of %d roots", plan.Roots.Len())()
+
+	if plan.Roots.Len() == 0 {
+		return nil, nil
+	}
+
+	paths, err := fetchPathGraph(ctx, db, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		findings            []Finding
+		immediateDominators = paths.dominators()
+		aggregator          = paths.affectedPrincipals(immediateDominators, plan.PrincipalKinds)
+	)
+
+	for _, node := range paths.nodes {
+		if paths.roots.Contains(node) {
+			continue
+		}
+
+		affectedPrincipals := int64(aggregator.Cardinality(node.ID.Uint32()).Cardinality())
+
+		if affectedPrincipals > 0 {
+			findings = append(findings, Finding{
+				Node:               node,
+				AffectedPrincipals: affectedPrincipals,
+			})
+		}
+
+		if relationships := paths.outbound[node.ID]; len(relationships) == 1 {
+			if node.Kinds.ContainsOneOf(plan.PrincipalKinds...) {
+				affectedPrincipals++
+			}
+
+			if affectedPrincipals > 0 {
+				findings = append(findings, Finding{
+					Node:               node,
+					Relationship:       relationships[0],
+					End:                paths.nodes.Get(relationships[0].EndID),
+					AffectedPrincipals: affectedPrincipals,
+				})
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].AffectedPrincipals != findings[j].AffectedPrincipals {
+			return findings[i].AffectedPrincipals > findings[j].AffectedPrincipals
+		} else if findings[i].Node.ID != findings[j].Node.ID {
+			return findings[i].Node.ID < findings[j].Node.ID
+		}
+
+		// Node findings are ranked before relationship findings of the same node
+		return !findings[i].IsRelationship() && findings[j].IsRelationship()
+	})
+
+	if plan.Limit > 0 && len(findings) > plan.Limit {
+		findings = findings[:plan.Limit]
+	}
+
+	return findings, nil
+}
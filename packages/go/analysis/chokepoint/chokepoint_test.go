@@ -0,0 +1,190 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package chokepoint_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specterops/bloodhound/analysis/chokepoint"
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	kindPrincipal = graph.StringKind("Principal")
+	kindGroup     = graph.StringKind("Group")
+	kindTierZero  = graph.StringKind("TierZero")
+	kindEdge      = graph.StringKind("Edge")
+	kindExcluded  = graph.StringKind("Excluded")
+)
+
+type chokePointGraph struct {
+	UserA    *graph.Node
+	UserB    *graph.Node
+	UserC    *graph.Node
+	UserD    *graph.Node
+	UserE    *graph.Node
+	GroupA   *graph.Node
+	GroupB   *graph.Node
+	Computer *graph.Node
+	DC       *graph.Node
+}
+
+// newChokePointGraph creates the following graph:
+//
+//	(UserA) -[Edge]-> (GroupA) -[Edge]-> (Computer) -[Edge]-> (DC)
+//	(UserB) -[Edge]-> (GroupA)
+//	(UserC) -[Edge]-> (GroupB) -[Edge]-> (Computer)
+//	(GroupB) -[Edge]-> (DC)
+//	(UserD) -[Edge]-> (DC)
+//	(UserE) -[Excluded]-> (DC)
+func newChokePointGraph(t *testing.T, db graph.Database) chokePointGraph {
+	var harness chokePointGraph
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(name string, kind graph.Kind) *graph.Node {
+			node, err := tx.CreateNode(graph.AsProperties(map[string]any{"name": name}), kind)
+			require.Nil(t, err)
+
+			return node
+		}
+
+		newRelationship := func(start, end *graph.Node, kind graph.Kind) {
+			_, err := tx.CreateRelationshipByIDs(start.ID, end.ID, kind, graph.NewProperties())
+			require.Nil(t, err)
+		}
+
+		harness.UserA = newNode("user a", kindPrincipal)
+		harness.UserB = newNode("user b", kindPrincipal)
+		harness.UserC = newNode("user c", kindPrincipal)
+		harness.UserD = newNode("user d", kindPrincipal)
+		harness.UserE = newNode("user e", kindPrincipal)
+		harness.GroupA = newNode("group a", kindGroup)
+		harness.GroupB = newNode("group b", kindGroup)
+		harness.Computer = newNode("computer", kindPrincipal)
+		harness.DC = newNode("dc", kindTierZero)
+
+		newRelationship(harness.UserA, harness.GroupA, kindEdge)
+		newRelationship(harness.UserB, harness.GroupA, kindEdge)
+		newRelationship(harness.GroupA, harness.Computer, kindEdge)
+		newRelationship(harness.Computer, harness.DC, kindEdge)
+		newRelationship(harness.UserC, harness.GroupB, kindEdge)
+		newRelationship(harness.GroupB, harness.Computer, kindEdge)
+		newRelationship(harness.GroupB, harness.DC, kindEdge)
+		newRelationship(harness.UserD, harness.DC, kindEdge)
+		newRelationship(harness.UserE, harness.DC, kindExcluded)
+
+		return nil
+	}))
+
+	return harness
+}
+
+func findingFor(findings []chokepoint.Finding, node *graph.Node, relationship bool) (chokepoint.Finding, bool) {
+	for _, finding := range findings {
+		if finding.Node.ID == node.ID && finding.IsRelationship() == relationship {
+			return finding, true
+		}
+	}
+
+	return chokepoint.Finding{}, false
+}
+
+func TestAnalyze(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newChokePointGraph(t, db)
+		plan    = chokepoint.Plan{
+			Roots:             graph.NewNodeSet(harness.DC),
+			RelationshipKinds: graph.Kinds{kindEdge},
+			PrincipalKinds:    graph.Kinds{kindPrincipal},
+		}
+	)
+
+	findings, err := chokepoint.Analyze(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, findings, 9)
+
+	// The computer's relationship to the DC is the only way in for users a and b as well as for the computer itself
+	require.Equal(t, harness.Computer.ID, findings[0].Node.ID)
+	require.True(t, findings[0].IsRelationship())
+	require.Equal(t, harness.DC.ID, findings[0].End.ID)
+	require.Equal(t, int64(3), findings[0].AffectedPrincipals)
+
+	require.Equal(t, harness.GroupA.ID, findings[1].Node.ID)
+	require.False(t, findings[1].IsRelationship())
+	require.Equal(t, int64(2), findings[1].AffectedPrincipals)
+
+	require.Equal(t, harness.GroupA.ID, findings[2].Node.ID)
+	require.True(t, findings[2].IsRelationship())
+	require.Equal(t, harness.Computer.ID, findings[2].End.ID)
+	require.Equal(t, int64(2), findings[2].AffectedPrincipals)
+
+	require.Equal(t, harness.Computer.ID, findings[3].Node.ID)
+	require.False(t, findings[3].IsRelationship())
+	require.Equal(t, int64(2), findings[3].AffectedPrincipals)
+
+	// Group b reaches the DC both directly and through the computer
+	groupBFinding, found := findingFor(findings, harness.GroupB, false)
+	require.True(t, found)
+	require.Equal(t, int64(1), groupBFinding.AffectedPrincipals)
+
+	_, found = findingFor(findings, harness.GroupB, true)
+	require.False(t, found)
+
+	// Principals with a single relationship into the paths are cut by removing it
+	userDFinding, found := findingFor(findings, harness.UserD, true)
+	require.True(t, found)
+	require.Equal(t, int64(1), userDFinding.AffectedPrincipals)
+
+	// Excluded relationships and roots are never findings
+	_, found = findingFor(findings, harness.UserE, true)
+	require.False(t, found)
+
+	_, found = findingFor(findings, harness.DC, false)
+	require.False(t, found)
+}
+
+func TestAnalyze_Limit(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newChokePointGraph(t, db)
+		plan    = chokepoint.Plan{
+			Roots:             graph.NewNodeSet(harness.DC),
+			RelationshipKinds: graph.Kinds{kindEdge},
+			PrincipalKinds:    graph.Kinds{kindPrincipal},
+			Limit:             1,
+		}
+	)
+
+	findings, err := chokepoint.Analyze(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, findings, 1)
+	require.Equal(t, harness.Computer.ID, findings[0].Node.ID)
+	require.True(t, findings[0].IsRelationship())
+}
+
+func TestAnalyze_NoRoots(t *testing.T) {
+	findings, err := chokepoint.Analyze(context.Background(), memory.NewDatabase(), chokepoint.Plan{
+		Roots: graph.NewNodeSet(),
+	})
+
+	require.Nil(t, err)
+	require.Empty(t, findings)
+}
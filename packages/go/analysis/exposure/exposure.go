@@ -0,0 +1,315 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package exposure
+
+import (
+	"context"
+
+	"github.com/specterops/bloodhound/dawgs/cardinality"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/specterops/bloodhound/log"
+)
+
+// Environment is a domain or tenant whose principals are scored. Members are the IDs of every object that belongs to
+// the environment and Principals are the IDs of the members that are scored.
+type Environment struct {
+	Members    cardinality.Duplex[uint32]
+	Principals cardinality.Duplex[uint32]
+}
+
+// Exposure is the blast radius of a principal within its environment. Outbound exposure counts the members of the
+// environment that the principal has a path to. Inbound exposure counts the principals of the environment that have a
+// path to the principal.
+type Exposure struct {
+	Inbound  int64
+	Outbound int64
+}
+
+// SetProperties stores the exposure in the given node properties
+func (s Exposure) SetProperties(properties *graph.Properties) {
+	properties.Set(common.InboundExposure.String(), s.Inbound)
+	properties.Set(common.OutboundExposure.String(), s.Outbound)
+}
+
+// NumericProperty returns the value of a numeric node property regardless of the numeric type that the graph driver
+// decoded it as. False is returned if the node has no numeric value for the property.
+func NumericProperty(node *graph.Node, propertyName string) (int64, bool) {
+	switch typedValue := node.Properties.Get(propertyName).Any().(type) {
+	case int:
+		return int64(typedValue), true
+	case int64:
+		return typedValue, true
+	case float64:
+		return int64(typedValue), true
+	default:
+		return 0, false
+	}
+}
+
+// NodeExposure returns the exposure stored on the given node. False is returned if the node has not been scored.
+func NodeExposure(node *graph.Node) (Exposure, bool) {
+	if inbound, hasInbound := NumericProperty(node, common.InboundExposure.String()); !hasInbound {
+		return Exposure{}, false
+	} else if outbound, hasOutbound := NumericProperty(node, common.OutboundExposure.String()); !hasOutbound {
+		return Exposure{}, false
+	} else {
+		return Exposure{
+			Inbound:  inbound,
+			Outbound: outbound,
+		}, true
+	}
+}
+
+// reachabilityGraph is the graph of every relationship that may be traversed, condensed into its strongly connected
+// components. Every node of a component reaches every other node of the same component.
+type reachabilityGraph struct {
+	outbound    map[uint32][]uint32
+	components  [][]uint32
+	componentOf map[uint32]int
+}
+
+func fetchReachabilityGraph(ctx context.Context, db graph.Database, relationshipKinds graph.Kinds) (*reachabilityGraph, error) {
+	reachability := &reachabilityGraph{
+		outbound:    map[uint32][]uint32{},
+		componentOf: map[uint32]int{},
+	}
+
+	if err := db.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		return tx.Relationships().Filter(
+			query.KindIn(query.Relationship(), relationshipKinds...),
+		).FetchTriples(func(cursor graph.Cursor[graph.RelationshipTripleResult]) error {
+			for triple := range cursor.Chan() {
+				if triple.StartID != triple.EndID {
+					start := triple.StartID.Uint32()
+					reachability.outbound[start] = append(reachability.outbound[start], triple.EndID.Uint32())
+				}
+			}
+
+			return cursor.Error()
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	reachability.condense()
+	return reachability, nil
+}
+
+// condense finds the strongly connected components of the graph with an iterative form of Tarjan's algorithm. Components
+// are emitted in reverse topological order: every component is emitted after all of the components it has a path to.
+func (s *reachabilityGraph) condense() {
+	type frame struct {
+		node uint32
+		next int
+	}
+
+	var (
+		nextIndex = 0
+		indices   = map[uint32]int{}
+		lowLinks  = map[uint32]int{}
+		onStack   = map[uint32]struct{}{}
+		stack     []uint32
+	)
+
+	visit := func(node uint32) *frame {
+		indices[node] = nextIndex
+		lowLinks[node] = nextIndex
+		nextIndex++
+
+		stack = append(stack, node)
+		onStack[node] = struct{}{}
+
+		return &frame{node: node}
+	}
+
+	for root := range s.outbound {
+		if _, visited := indices[root]; visited {
+			continue
+		}
+
+		callStack := []*frame{visit(root)}
+
+		for len(callStack) > 0 {
+			top := callStack[len(callStack)-1]
+
+			if successors := s.outbound[top.node]; top.next < len(successors) {
+				successor := successors[top.next]
+				top.next++
+
+				if _, visited := indices[successor]; !visited {
+					callStack = append(callStack, visit(successor))
+				} else if _, isOnStack := onStack[successor]; isOnStack && indices[successor] < lowLinks[top.node] {
+					lowLinks[top.node] = indices[successor]
+				}
+
+				continue
+			}
+
+			callStack = callStack[:len(callStack)-1]
+
+			if len(callStack) > 0 {
+				if parent := callStack[len(callStack)-1]; lowLinks[top.node] < lowLinks[parent.node] {
+					lowLinks[parent.node] = lowLinks[top.node]
+				}
+			}
+
+			// The node is the root of a component when no node on the stack above it has a path back to an earlier node
+			if lowLinks[top.node] == indices[top.node] {
+				var (
+					componentIndex = len(s.components)
+					component      []uint32
+				)
+
+				for {
+					member := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					delete(onStack, member)
+
+					s.componentOf[member] = componentIndex
+					component = append(component, member)
+
+					if member == top.node {
+						break
+					}
+				}
+
+				s.components = append(s.components, component)
+			}
+		}
+	}
+}
+
+// resolveOutbound sets the outbound exposure of every principal. The bitmap of every node that a component has a path
+// to, including the members of the component itself, is resolved in reverse topological order so that every component
+// reachable from the current one has already been resolved.
+func (s *reachabilityGraph) resolveOutbound(environments []Environment, exposures map[graph.ID]Exposure) {
+	reachable := make([]cardinality.Duplex[uint32], len(s.components))
+
+	for componentIndex, component := range s.components {
+		componentReachable := cardinality.NewBitmap32()
+		componentReachable.Add(component...)
+
+		for _, member := range component {
+			for _, successor := range s.outbound[member] {
+				if successorComponent := s.componentOf[successor]; successorComponent != componentIndex {
+					componentReachable.Or(reachable[successorComponent])
+				}
+			}
+		}
+
+		reachable[componentIndex] = componentReachable
+	}
+
+	for _, environment := range environments {
+		environment.Principals.Each(func(principal uint32) (bool, error) {
+			if component, hasComponent := s.componentOf[principal]; hasComponent {
+				exposure := exposures[graph.ID(principal)]
+				exposure.Outbound = scopedCardinality(reachable[component], environment.Members, principal)
+				exposures[graph.ID(principal)] = exposure
+			}
+
+			return true, nil
+		})
+	}
+}
+
+// resolveInbound sets the inbound exposure of every principal. The bitmap of the principals that have a path to a
+// component, including the principals that are members of the component itself, is pushed to its successors in
+// topological order so that every component with a path to the current one has already been resolved.
+func (s *reachabilityGraph) resolveInbound(environments []Environment, principals cardinality.Duplex[uint32], exposures map[graph.ID]Exposure) {
+	reachedBy := make([]cardinality.Duplex[uint32], len(s.components))
+
+	for componentIndex, component := range s.components {
+		componentReachedBy := cardinality.NewBitmap32()
+
+		for _, member := range component {
+			if principals.Contains(member) {
+				componentReachedBy.Add(member)
+			}
+		}
+
+		reachedBy[componentIndex] = componentReachedBy
+	}
+
+	for componentIndex := len(s.components) - 1; componentIndex >= 0; componentIndex-- {
+		for _, member := range s.components[componentIndex] {
+			for _, successor := range s.outbound[member] {
+				if successorComponent := s.componentOf[successor]; successorComponent != componentIndex {
+					reachedBy[successorComponent].Or(reachedBy[componentIndex])
+				}
+			}
+		}
+	}
+
+	for _, environment := range environments {
+		environment.Principals.Each(func(principal uint32) (bool, error) {
+			if component, hasComponent := s.componentOf[principal]; hasComponent {
+				exposure := exposures[graph.ID(principal)]
+				exposure.Inbound = scopedCardinality(reachedBy[component], environment.Principals, principal)
+				exposures[graph.ID(principal)] = exposure
+			}
+
+			return true, nil
+		})
+	}
+}
+
+// scopedCardinality counts the values of the given bitmap that are also in scope, not counting the excluded value
+func scopedCardinality(values, scope cardinality.Duplex[uint32], excluded uint32) int64 {
+	scoped := values.Clone()
+	scoped.And(scope)
+	scoped.Remove(excluded)
+
+	return int64(scoped.Cardinality())
+}
+
+// Calculate resolves the exposure of every principal of the given environments over relationships of the given kinds.
+// Paths may leave an environment, but only the members and principals of the principal's own environment are counted.
+//
+// The traversable graph is condensed into its strongly connected components so that reachability is resolved once per
+// component by merging the bitmaps of the components that it has a path to.
+func Calculate(ctx context.Context, db graph.Database, relationshipKinds graph.Kinds, environments []Environment) (map[graph.ID]Exposure, error) {
+	defer log.Measure(log.LevelInfo, "Calculated exposure for %d environments", len(environments))()
+
+	reachability, err := fetchReachabilityGraph(ctx, db, relationshipKinds)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		exposures     = map[graph.ID]Exposure{}
+		allPrincipals = cardinality.NewBitmap32()
+	)
+
+	for _, environment := range environments {
+		allPrincipals.Or(environment.Principals)
+
+		environment.Principals.Each(func(principal uint32) (bool, error) {
+			exposures[graph.ID(principal)] = Exposure{}
+			return true, nil
+		})
+	}
+
+	// Outbound and inbound exposure are resolved one after the other so that only one set of component bitmaps is held
+	// at a time
+	reachability.resolveOutbound(environments, exposures)
+	reachability.resolveInbound(environments, allPrincipals, exposures)
+
+	return exposures, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package exposure_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specterops/bloodhound/analysis/exposure"
+	"github.com/specterops/bloodhound/dawgs/cardinality"
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	kindNode     = graph.StringKind("Node")
+	kindEdge     = graph.StringKind("Edge")
+	kindExcluded = graph.StringKind("Excluded")
+)
+
+func bitmap(nodes ...*graph.Node) cardinality.Duplex[uint32] {
+	ids := cardinality.NewBitmap32()
+
+	for _, node := range nodes {
+		ids.Add(node.ID.Uint32())
+	}
+
+	return ids
+}
+
+// TestCalculate scores the following graph where every node belongs to environment A except for UserC which belongs to
+// environment B:
+//
+//	(UserA) -[Edge]-> (Group) -[Edge]-> (Computer) -[Edge]-> (Admin)
+//	(UserB) -[Edge]-> (Group) <-[Edge]- (Computer)
+//	(UserC) -[Edge]-> (UserA) -[Excluded]-> (Admin)
+func TestCalculate(t *testing.T) {
+	var (
+		db                                          = memory.NewDatabase()
+		userA, userB, userC, group, computer, admin *graph.Node
+	)
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(name string) *graph.Node {
+			node, err := tx.CreateNode(graph.AsProperties(map[string]any{"name": name}), kindNode)
+			require.Nil(t, err)
+
+			return node
+		}
+
+		newRelationship := func(start, end *graph.Node, kind graph.Kind) {
+			_, err := tx.CreateRelationshipByIDs(start.ID, end.ID, kind, graph.NewProperties())
+			require.Nil(t, err)
+		}
+
+		userA = newNode("user a")
+		userB = newNode("user b")
+		userC = newNode("user c")
+		group = newNode("group")
+		computer = newNode("computer")
+		admin = newNode("admin")
+
+		newRelationship(userA, group, kindEdge)
+		newRelationship(userB, group, kindEdge)
+		newRelationship(group, computer, kindEdge)
+		newRelationship(computer, group, kindEdge)
+		newRelationship(computer, admin, kindEdge)
+		newRelationship(userC, userA, kindEdge)
+		newRelationship(userA, admin, kindExcluded)
+
+		return nil
+	}))
+
+	exposures, err := exposure.Calculate(context.Background(), db, graph.Kinds{kindEdge}, []exposure.Environment{{
+		Members:    bitmap(userA, userB, group, computer, admin),
+		Principals: bitmap(userA, userB, group, computer),
+	}, {
+		Members:    bitmap(userC),
+		Principals: bitmap(userC),
+	}})
+
+	require.Nil(t, err)
+	require.Len(t, exposures, 5)
+
+	require.Equal(t, exposure.Exposure{Outbound: 3}, exposures[userA.ID])
+	require.Equal(t, exposure.Exposure{Outbound: 3}, exposures[userB.ID])
+
+	// The group and computer reach each other but do not count themselves
+	require.Equal(t, exposure.Exposure{Outbound: 2, Inbound: 3}, exposures[group.ID])
+	require.Equal(t, exposure.Exposure{Outbound: 2, Inbound: 3}, exposures[computer.ID])
+
+	// Paths into and out of another environment are not counted
+	require.Equal(t, exposure.Exposure{}, exposures[userC.ID])
+}
+
+func TestCalculate_NoRelationships(t *testing.T) {
+	var (
+		db   = memory.NewDatabase()
+		user *graph.Node
+	)
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		var err error
+
+		user, err = tx.CreateNode(graph.NewProperties(), kindNode)
+		return err
+	}))
+
+	exposures, err := exposure.Calculate(context.Background(), db, graph.Kinds{kindEdge}, []exposure.Environment{{
+		Members:    bitmap(user),
+		Principals: bitmap(user),
+	}})
+
+	require.Nil(t, err)
+	require.Equal(t, map[graph.ID]exposure.Exposure{user.ID: {}}, exposures)
+}
+
+func TestNodeExposure(t *testing.T) {
+	node := graph.NewNode(1, graph.NewProperties(), kindNode)
+
+	_, scored := exposure.NodeExposure(node)
+	require.False(t, scored)
+
+	exposure.Exposure{Inbound: 4, Outbound: 2}.SetProperties(node.Properties)
+
+	stored, scored := exposure.NodeExposure(node)
+	require.True(t, scored)
+	require.Equal(t, exposure.Exposure{Inbound: 4, Outbound: 2}, stored)
+
+	// Drivers that decode numbers from JSON return them as floats
+	node.Properties.Set("inboundexposure", float64(5))
+
+	stored, scored = exposure.NodeExposure(node)
+	require.True(t, scored)
+	require.Equal(t, exposure.Exposure{Inbound: 5, Outbound: 2}, stored)
+}
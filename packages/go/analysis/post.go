@@ -160,6 +160,42 @@ func DeleteTransitEdges(ctx context.Context, db graph.Database, fromKind, toKind
 	})
 }
 
+// DeletePostProcessedEdges deletes every relationship tagged with the post_processed property. Relationships created by
+// rule based post-processing are tagged so that they can be removed before the rules are evaluated again.
+func DeletePostProcessedEdges(ctx context.Context, db graph.Database) (*AtomicPostProcessingStats, error) {
+	defer log.Measure(log.LevelInfo, "Finished deleting post-processed edges")()
+
+	var (
+		relationshipIDs []graph.ID
+		stats           = NewAtomicPostProcessingStats()
+	)
+
+	if err := db.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		return tx.Relationships().Filterf(func() graph.Criteria {
+			return query.Equals(query.RelationshipProperty(common.PostProcessed.String()), true)
+		}).Fetch(func(cursor graph.Cursor[*graph.Relationship]) error {
+			for relationship := range cursor.Chan() {
+				stats.AddRelationshipsDeleted(relationship.Kind, 1)
+				relationshipIDs = append(relationshipIDs, relationship.ID)
+			}
+
+			return cursor.Error()
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return &stats, db.BatchOperation(ctx, func(batch graph.Batch) error {
+		for _, relationshipID := range relationshipIDs {
+			if err := batch.DeleteRelationship(relationshipID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func NodesWithoutRelationshipsFilter() graph.Criteria {
 	return query.And(
 		// Nodes without relationships
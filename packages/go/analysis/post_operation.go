@@ -34,15 +34,24 @@ type StatTrackedOperation[T any] struct {
 }
 
 func NewPostRelationshipOperation(ctx context.Context, db graph.Database, operationName string) StatTrackedOperation[CreatePostRelationshipJob] {
+	return newPostRelationshipOperation(ctx, db, operationName, NewPropertiesWithLastSeen())
+}
+
+// NewPostProcessedRelationshipOperation behaves like NewPostRelationshipOperation but tags every created relationship
+// with the post_processed property so that it can be removed with DeletePostProcessedEdges
+func NewPostProcessedRelationshipOperation(ctx context.Context, db graph.Database, operationName string) StatTrackedOperation[CreatePostRelationshipJob] {
+	relProp := NewPropertiesWithLastSeen()
+	relProp.Set(common.PostProcessed.String(), true)
+
+	return newPostRelationshipOperation(ctx, db, operationName, relProp)
+}
+
+func newPostRelationshipOperation(ctx context.Context, db graph.Database, operationName string, relProp *graph.Properties) StatTrackedOperation[CreatePostRelationshipJob] {
 	operation := StatTrackedOperation[CreatePostRelationshipJob]{}
 	operation.NewOperation(ctx, db)
 	operation.Operation.SubmitWriter(func(ctx context.Context, batch graph.Batch, inC <-chan CreatePostRelationshipJob) error {
 		defer log.Measure(log.LevelInfo, operationName)()
 
-		var (
-			relProp = NewPropertiesWithLastSeen()
-		)
-
 		for nextJob := range inC {
 			if err := batch.CreateRelationshipByIDs(nextJob.FromID, nextJob.ToID, nextJob.Kind, relProp); err != nil {
 				return err
@@ -0,0 +1,73 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletePostProcessedEdges(t *testing.T) {
+	var (
+		db               = memory.NewDatabase()
+		ingestedAdminTo  *graph.Relationship
+		processedAdminTo *graph.Relationship
+		processedCanRDP  *graph.Relationship
+	)
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		var (
+			user, _     = tx.CreateNode(graph.NewProperties(), ad.Entity, ad.User)
+			computer, _ = tx.CreateNode(graph.NewProperties(), ad.Entity, ad.Computer)
+			tagged      = graph.AsProperties(map[string]any{common.PostProcessed.String(): true})
+			err         error
+		)
+
+		if ingestedAdminTo, err = tx.CreateRelationshipByIDs(user.ID, computer.ID, ad.AdminTo, graph.NewProperties()); err != nil {
+			return err
+		} else if processedAdminTo, err = tx.CreateRelationshipByIDs(user.ID, computer.ID, ad.AdminTo, tagged); err != nil {
+			return err
+		} else if processedCanRDP, err = tx.CreateRelationshipByIDs(user.ID, computer.ID, ad.CanRDP, tagged); err != nil {
+			return err
+		}
+
+		return nil
+	}))
+
+	stats, err := analysis.DeletePostProcessedEdges(context.Background(), db)
+	require.Nil(t, err)
+	require.Equal(t, int32(1), *stats.RelationshipsDeleted[ad.AdminTo])
+	require.Equal(t, int32(1), *stats.RelationshipsDeleted[ad.CanRDP])
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		remaining, err := ops.FetchRelationshipIDs(tx.Relationships())
+		require.Nil(t, err)
+		require.Equal(t, []graph.ID{ingestedAdminTo.ID}, remaining)
+		require.NotContains(t, remaining, processedAdminTo.ID)
+		require.NotContains(t, remaining, processedCanRDP.ID)
+
+		return nil
+	}))
+}
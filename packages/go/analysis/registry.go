@@ -0,0 +1,182 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/log"
+)
+
+const (
+	ErrPostProcessingStageInvalid          = errors.Error("post-processing stage is invalid")
+	ErrPostProcessingStageExists           = errors.Error("post-processing stage is already registered")
+	ErrPostProcessingStageNotFound         = errors.Error("post-processing stage not found")
+	ErrPostProcessingStageNotIdempotent    = errors.Error("post-processing stage is not idempotent and can not be run on its own")
+	ErrPostProcessingStageDependencyFailed = errors.Error("post-processing stage dependency failed")
+)
+
+// PostProcessingStageFunc runs a post-processing stage against the graph. Stages that do not create or delete
+// relationships may return nil stats.
+type PostProcessingStageFunc func(ctx context.Context, db graph.Database) (*AtomicPostProcessingStats, error)
+
+// PostProcessingStage is a named step of post-processing. Produces lists the relationship kinds that the stage creates
+// and DependsOn names the stages that must complete before the stage may run. Stages that are Idempotent may be re-run
+// on their own without corrupting the graph.
+type PostProcessingStage struct {
+	Name       string
+	Produces   graph.Kinds
+	DependsOn  []string
+	Idempotent bool
+	Run        PostProcessingStageFunc
+}
+
+// PostProcessingStageResult records the outcome of a single run of a post-processing stage
+type PostProcessingStageResult struct {
+	Stage     PostProcessingStage
+	StartedAt time.Time
+	Duration  time.Duration
+	Stats     *AtomicPostProcessingStats
+	Err       error
+}
+
+// PostProcessingRegistry is an ordered collection of post-processing stages. Stages may only depend on stages that
+// were registered before them which keeps the registration order a valid execution order.
+type PostProcessingRegistry struct {
+	stages []PostProcessingStage
+	index  map[string]int
+	lock   *sync.RWMutex
+}
+
+func NewPostProcessingRegistry() *PostProcessingRegistry {
+	return &PostProcessingRegistry{
+		index: map[string]int{},
+		lock:  &sync.RWMutex{},
+	}
+}
+
+// Register appends the given stage to the registry
+func (s *PostProcessingRegistry) Register(stage PostProcessingStage) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if stage.Name == "" || stage.Run == nil {
+		return ErrPostProcessingStageInvalid
+	} else if _, exists := s.index[stage.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrPostProcessingStageExists, stage.Name)
+	}
+
+	for _, dependency := range stage.DependsOn {
+		if _, exists := s.index[dependency]; !exists {
+			return fmt.Errorf("%w: stage %s depends on unregistered stage %s", ErrPostProcessingStageNotFound, stage.Name, dependency)
+		}
+	}
+
+	s.index[stage.Name] = len(s.stages)
+	s.stages = append(s.stages, stage)
+
+	return nil
+}
+
+// Stages returns every registered stage in execution order
+func (s *PostProcessingRegistry) Stages() []PostProcessingStage {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	stages := make([]PostProcessingStage, len(s.stages))
+	copy(stages, s.stages)
+
+	return stages
+}
+
+// Select returns the named stages in execution order. Only idempotent stages may be selected since the stages are run
+// without the rest of post-processing.
+func (s *PostProcessingRegistry) Select(names ...string) ([]PostProcessingStage, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	selected := make([]bool, len(s.stages))
+
+	for _, name := range names {
+		if stageIdx, exists := s.index[name]; !exists {
+			return nil, fmt.Errorf("%w: %s", ErrPostProcessingStageNotFound, name)
+		} else if !s.stages[stageIdx].Idempotent {
+			return nil, fmt.Errorf("%w: %s", ErrPostProcessingStageNotIdempotent, name)
+		} else {
+			selected[stageIdx] = true
+		}
+	}
+
+	var stages []PostProcessingStage
+
+	for stageIdx, stage := range s.stages {
+		if selected[stageIdx] {
+			stages = append(stages, stage)
+		}
+	}
+
+	return stages, nil
+}
+
+// RunPostProcessingStages runs the given stages in order and returns the result of each. A stage is skipped if any
+// stage that it depends on failed or was skipped during this run. Dependencies that are not part of this run are
+// assumed to be satisfied.
+func RunPostProcessingStages(ctx context.Context, db graph.Database, stages []PostProcessingStage) []PostProcessingStageResult {
+	var (
+		results = make([]PostProcessingStageResult, 0, len(stages))
+		failed  = map[string]struct{}{}
+	)
+
+	for _, stage := range stages {
+		result := PostProcessingStageResult{
+			Stage:     stage,
+			StartedAt: time.Now().UTC(),
+		}
+
+		for _, dependency := range stage.DependsOn {
+			if _, dependencyFailed := failed[dependency]; dependencyFailed {
+				result.Err = fmt.Errorf("%w: %s", ErrPostProcessingStageDependencyFailed, dependency)
+				break
+			}
+		}
+
+		if result.Err == nil {
+			log.Infof("Running post-processing stage %s", stage.Name)
+
+			result.Stats, result.Err = stage.Run(ctx, db)
+			result.Duration = time.Since(result.StartedAt)
+
+			if result.Stats != nil {
+				result.Stats.LogStats()
+			}
+		}
+
+		if result.Err != nil {
+			log.Errorf("Post-processing stage %s failed: %v", stage.Name, result.Err)
+			failed[stage.Name] = struct{}{}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStage(name string, idempotent bool, runs *[]string, err error, dependsOn ...string) analysis.PostProcessingStage {
+	return analysis.PostProcessingStage{
+		Name:       name,
+		DependsOn:  dependsOn,
+		Idempotent: idempotent,
+		Run: func(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
+			*runs = append(*runs, name)
+
+			stats := analysis.NewAtomicPostProcessingStats()
+			stats.AddRelationshipsCreated(ad.AdminTo, 1)
+
+			return &stats, err
+		},
+	}
+}
+
+func TestPostProcessingRegistry_Register(t *testing.T) {
+	var (
+		runs     []string
+		registry = analysis.NewPostProcessingRegistry()
+	)
+
+	require.Nil(t, registry.Register(newTestStage("a", true, &runs, nil)))
+	require.Nil(t, registry.Register(newTestStage("b", true, &runs, nil, "a")))
+
+	// Stages must be named and runnable
+	require.ErrorIs(t, registry.Register(analysis.PostProcessingStage{Name: "c"}), analysis.ErrPostProcessingStageInvalid)
+
+	// Stage names are unique
+	require.ErrorIs(t, registry.Register(newTestStage("a", true, &runs, nil)), analysis.ErrPostProcessingStageExists)
+
+	// Dependencies must be registered first
+	require.ErrorIs(t, registry.Register(newTestStage("c", true, &runs, nil, "d")), analysis.ErrPostProcessingStageNotFound)
+
+	stages := registry.Stages()
+	require.Len(t, stages, 2)
+	require.Equal(t, "a", stages[0].Name)
+	require.Equal(t, "b", stages[1].Name)
+}
+
+func TestPostProcessingRegistry_Select(t *testing.T) {
+	var (
+		runs     []string
+		registry = analysis.NewPostProcessingRegistry()
+	)
+
+	require.Nil(t, registry.Register(newTestStage("a", true, &runs, nil)))
+	require.Nil(t, registry.Register(newTestStage("b", false, &runs, nil)))
+	require.Nil(t, registry.Register(newTestStage("c", true, &runs, nil, "a")))
+
+	// Selected stages are returned in execution order
+	stages, err := registry.Select("c", "a")
+	require.Nil(t, err)
+	require.Len(t, stages, 2)
+	require.Equal(t, "a", stages[0].Name)
+	require.Equal(t, "c", stages[1].Name)
+
+	_, err = registry.Select("b")
+	require.ErrorIs(t, err, analysis.ErrPostProcessingStageNotIdempotent)
+
+	_, err = registry.Select("d")
+	require.ErrorIs(t, err, analysis.ErrPostProcessingStageNotFound)
+}
+
+func TestRunPostProcessingStages(t *testing.T) {
+	var (
+		runs     []string
+		failure  = errors.New("failure")
+		registry = analysis.NewPostProcessingRegistry()
+	)
+
+	require.Nil(t, registry.Register(newTestStage("a", true, &runs, nil)))
+	require.Nil(t, registry.Register(newTestStage("b", true, &runs, failure, "a")))
+	require.Nil(t, registry.Register(newTestStage("c", true, &runs, nil, "b")))
+	require.Nil(t, registry.Register(newTestStage("d", true, &runs, nil, "c")))
+	require.Nil(t, registry.Register(newTestStage("e", true, &runs, nil, "a")))
+
+	results := analysis.RunPostProcessingStages(context.Background(), nil, registry.Stages())
+	require.Equal(t, []string{"a", "b", "e"}, runs)
+	require.Len(t, results, 5)
+
+	require.Nil(t, results[0].Err)
+	require.Equal(t, int32(1), *results[0].Stats.RelationshipsCreated[ad.AdminTo])
+	require.ErrorIs(t, results[1].Err, failure)
+
+	// Failures cascade to every stage that depends on the failed stage
+	require.ErrorIs(t, results[2].Err, analysis.ErrPostProcessingStageDependencyFailed)
+	require.ErrorIs(t, results[3].Err, analysis.ErrPostProcessingStageDependencyFailed)
+	require.Nil(t, results[3].Stats)
+	require.Nil(t, results[4].Err)
+
+	// Dependencies outside of the run are assumed to be satisfied
+	runs = nil
+	stages, err := registry.Select("c")
+	require.Nil(t, err)
+
+	results = analysis.RunPostProcessingStages(context.Background(), nil, stages)
+	require.Equal(t, []string{"c"}, runs)
+	require.Nil(t, results[0].Err)
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis
+
+import (
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/graphschema/common"
+)
+
+// RelationshipValidAsOf returns true if the given relationship was valid at the given time. Relationships observed
+// after the given time were not yet valid. Relationships without observed_at and valid_until properties are not
+// time-bounded and are always valid.
+func RelationshipValidAsOf(relationship *graph.Relationship, asOf time.Time) bool {
+	if observedAt, err := relationship.Properties.Get(common.ObservedAt.String()).Time(); err == nil && observedAt.After(asOf) {
+		return false
+	} else if validUntil, err := relationship.Properties.Get(common.ValidUntil.String()).Time(); err != nil {
+		return true
+	} else {
+		return !validUntil.Before(asOf)
+	}
+}
+
+// ValidAsOfCriteria returns criteria matching the relationships that were valid at the given time
+func ValidAsOfCriteria(asOf time.Time) graph.Criteria {
+	var (
+		observedAt = query.RelationshipProperty(common.ObservedAt.String())
+		validUntil = query.RelationshipProperty(common.ValidUntil.String())
+	)
+
+	return query.And(
+		query.Or(
+			query.IsNull(observedAt),
+			query.Not(query.After(observedAt, asOf)),
+		),
+		query.Or(
+			query.IsNull(validUntil),
+			query.Not(query.Before(validUntil, asOf)),
+		),
+	)
+}
+
+// ExpiredCriteria returns criteria matching the relationships that stopped being valid before the given time
+func ExpiredCriteria(before time.Time) graph.Criteria {
+	return query.Before(query.RelationshipProperty(common.ValidUntil.String()), before)
+}
+
+// FilterPathsValidAsOf returns the paths of the given path set whose relationships were all valid at the given time
+func FilterPathsValidAsOf(paths graph.PathSet, asOf time.Time) graph.PathSet {
+	return paths.FilterByEdge(func(edge *graph.Relationship) bool {
+		return RelationshipValidAsOf(edge, asOf)
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/analysis"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/graphschema/ad"
+	"github.com/specterops/bloodhound/graphschema/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationshipValidAsOf(t *testing.T) {
+	var (
+		observedAt = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+		validUntil = observedAt.Add(72 * time.Hour)
+
+		session = graph.NewRelationship(1, 1, 2, graph.AsProperties(map[string]any{
+			common.ObservedAt.String(): observedAt,
+			common.ValidUntil.String(): validUntil,
+		}), ad.HasSession)
+
+		memberOf = graph.NewRelationship(2, 2, 3, graph.NewProperties(), ad.MemberOf)
+	)
+
+	require.True(t, analysis.RelationshipValidAsOf(session, observedAt))
+	require.True(t, analysis.RelationshipValidAsOf(session, validUntil))
+	require.False(t, analysis.RelationshipValidAsOf(session, validUntil.Add(time.Second)))
+	require.True(t, analysis.RelationshipValidAsOf(memberOf, validUntil.Add(time.Hour)))
+}
+
+func TestRelationshipValidAsOf_ObservedAfter(t *testing.T) {
+	var (
+		observedAt = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		session = graph.NewRelationship(1, 1, 2, graph.AsProperties(map[string]any{
+			common.ObservedAt.String(): observedAt,
+			common.ValidUntil.String(): observedAt.Add(72 * time.Hour),
+		}), ad.HasSession)
+
+		unboundedSession = graph.NewRelationship(2, 1, 2, graph.AsProperties(map[string]any{
+			common.ObservedAt.String(): observedAt,
+		}), ad.HasSession)
+	)
+
+	require.False(t, analysis.RelationshipValidAsOf(session, observedAt.Add(-time.Second)))
+	require.False(t, analysis.RelationshipValidAsOf(unboundedSession, observedAt.Add(-time.Hour)))
+	require.True(t, analysis.RelationshipValidAsOf(unboundedSession, observedAt))
+}
+
+func TestFilterPathsValidAsOf(t *testing.T) {
+	var (
+		validUntil = time.Date(2023, 6, 4, 12, 0, 0, 0, time.UTC)
+
+		computer = graph.NewNode(1, graph.NewProperties(), ad.Entity, ad.Computer)
+		user     = graph.NewNode(2, graph.NewProperties(), ad.Entity, ad.User)
+		group    = graph.NewNode(3, graph.NewProperties(), ad.Entity, ad.Group)
+
+		session = graph.NewRelationship(1, computer.ID, user.ID, graph.AsProperties(map[string]any{
+			common.ValidUntil.String(): validUntil,
+		}), ad.HasSession)
+
+		memberOf = graph.NewRelationship(2, user.ID, group.ID, graph.NewProperties(), ad.MemberOf)
+
+		sessionPath = graph.Path{
+			Nodes: []*graph.Node{computer, user, group},
+			Edges: []*graph.Relationship{session, memberOf},
+		}
+
+		memberOfPath = graph.Path{
+			Nodes: []*graph.Node{user, group},
+			Edges: []*graph.Relationship{memberOf},
+		}
+
+		paths = graph.NewPathSet(sessionPath, memberOfPath)
+	)
+
+	require.Equal(t, 2, analysis.FilterPathsValidAsOf(paths, validUntil).Len())
+
+	expired := analysis.FilterPathsValidAsOf(paths, validUntil.Add(time.Hour))
+	require.Equal(t, 1, expired.Len())
+	require.Equal(t, memberOfPath, expired[0])
+}
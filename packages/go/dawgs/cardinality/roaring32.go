@@ -83,7 +83,7 @@ func (s bitmap32) Remove(value uint32) {
 func (s bitmap32) And(provider Provider[uint32]) {
 	switch typedProvider := provider.(type) {
 	case bitmap32:
-		s.bitmap.Or(typedProvider.bitmap)
+		s.bitmap.And(typedProvider.bitmap)
 
 	case Duplex[uint32]:
 		s.Each(func(nextValue uint32) (bool, error) {
@@ -83,7 +83,7 @@ func (s bitmap64) Remove(value uint64) {
 func (s bitmap64) And(provider Provider[uint64]) {
 	switch typedProvider := provider.(type) {
 	case bitmap64:
-		s.bitmap.Or(typedProvider.bitmap)
+		s.bitmap.And(typedProvider.bitmap)
 
 	case Duplex[uint64]:
 		s.Each(func(nextValue uint64) (bool, error) {
@@ -0,0 +1,51 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package cardinality_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/cardinality"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitmap64_And(t *testing.T) {
+	var (
+		bitmap = cardinality.NewBitmap64()
+		other  = cardinality.NewBitmap64()
+	)
+
+	bitmap.Add(1, 2, 3, 4)
+	other.Add(3, 4, 5, 6)
+	bitmap.And(other)
+
+	require.Equal(t, []uint64{3, 4}, bitmap.Slice())
+	require.Equal(t, uint64(4), other.Cardinality())
+}
+
+func TestBitmap64_Or(t *testing.T) {
+	var (
+		bitmap = cardinality.NewBitmap64()
+		other  = cardinality.NewBitmap64()
+	)
+
+	bitmap.Add(1, 2)
+	other.Add(2, 3)
+	bitmap.Or(other)
+
+	require.Equal(t, []uint64{1, 2, 3}, bitmap.Slice())
+}
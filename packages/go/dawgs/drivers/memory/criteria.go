@@ -0,0 +1,686 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/specterops/bloodhound/cypher/model"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+)
+
+var (
+	ErrUnboundReference = errors.New("unbound variable reference")
+)
+
+// bindings holds the graph entities that the query symbols of a single query candidate refer to.
+type bindings struct {
+	node         *graph.Node
+	start        *graph.Node
+	relationship *graph.Relationship
+	end          *graph.Node
+}
+
+func (s bindings) lookup(symbol string) (any, error) {
+	switch symbol {
+	case query.NodeSymbol:
+		if s.node != nil {
+			return s.node, nil
+		}
+
+	case query.RelationshipStartSymbol:
+		if s.start != nil {
+			return s.start, nil
+		}
+
+	case query.RelationshipSymbol:
+		if s.relationship != nil {
+			return s.relationship, nil
+		}
+
+	case query.RelationshipEndSymbol:
+		if s.end != nil {
+			return s.end, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnboundReference, symbol)
+}
+
+// truth converts an evaluated value into a ternary truth value. Valid return values are true, false and nil where nil
+// represents a null result as it would in Cypher.
+func truth(value any) (any, error) {
+	switch typedValue := value.(type) {
+	case nil:
+		return nil, nil
+
+	case bool:
+		return typedValue, nil
+
+	default:
+		return nil, fmt.Errorf("expected a boolean expression but got %T", value)
+	}
+}
+
+func isStringComparison(comparison *model.Comparison) bool {
+	if firstPartial := comparison.FirstPartial(); firstPartial != nil {
+		switch firstPartial.Operator {
+		case model.OperatorStartsWith, model.OperatorEndsWith, model.OperatorContains:
+			return true
+		}
+	}
+
+	return false
+}
+
+// evaluator interprets the criteria model of the query package directly against the entities of a store.
+type evaluator struct {
+	store *store
+}
+
+func (s evaluator) matches(expression graph.Criteria, scope bindings) (bool, error) {
+	if expression == nil {
+		return true, nil
+	}
+
+	if value, err := s.evaluate(expression, scope); err != nil {
+		return false, err
+	} else if truthValue, err := truth(value); err != nil {
+		return false, err
+	} else {
+		matched, _ := truthValue.(bool)
+		return matched, nil
+	}
+}
+
+func (s evaluator) conjunction(expressions []model.Expression, scope bindings) (any, error) {
+	var result any = true
+
+	for _, expression := range expressions {
+		if value, err := s.evaluate(expression, scope); err != nil {
+			return nil, err
+		} else if truthValue, err := truth(value); err != nil {
+			return nil, err
+		} else if truthValue == nil {
+			result = nil
+		} else if !truthValue.(bool) {
+			return false, nil
+		}
+	}
+
+	return result, nil
+}
+
+func (s evaluator) disjunction(expressions []model.Expression, scope bindings) (any, error) {
+	var result any = false
+
+	for _, expression := range expressions {
+		if value, err := s.evaluate(expression, scope); err != nil {
+			return nil, err
+		} else if truthValue, err := truth(value); err != nil {
+			return nil, err
+		} else if truthValue == nil {
+			result = nil
+		} else if truthValue.(bool) {
+			return true, nil
+		}
+	}
+
+	return result, nil
+}
+
+func (s evaluator) exclusiveDisjunction(expressions []model.Expression, scope bindings) (any, error) {
+	result := false
+
+	for _, expression := range expressions {
+		if value, err := s.evaluate(expression, scope); err != nil {
+			return nil, err
+		} else if truthValue, err := truth(value); err != nil {
+			return nil, err
+		} else if truthValue == nil {
+			return nil, nil
+		} else if truthValue.(bool) {
+			result = !result
+		}
+	}
+
+	return result, nil
+}
+
+func (s evaluator) negation(negation *model.Negation, scope bindings) (any, error) {
+	// Negated string comparisons against a null value evaluate to true. This mirrors the rewrite that the Neo4j query
+	// builder applies to the same criteria.
+	if comparison, isComparison := negation.Expression.(*model.Comparison); isComparison && isStringComparison(comparison) {
+		if left, err := s.evaluate(comparison.Left, scope); err != nil {
+			return nil, err
+		} else if left == nil {
+			return true, nil
+		}
+	}
+
+	if value, err := s.evaluate(negation.Expression, scope); err != nil {
+		return nil, err
+	} else if truthValue, err := truth(value); err != nil {
+		return nil, err
+	} else if truthValue == nil {
+		return nil, nil
+	} else {
+		return !truthValue.(bool), nil
+	}
+}
+
+func (s evaluator) kindMatcher(matcher *model.KindMatcher, scope bindings) (any, error) {
+	if reference, err := s.evaluate(matcher.Reference, scope); err != nil {
+		return nil, err
+	} else {
+		switch typedReference := reference.(type) {
+		case nil:
+			return nil, nil
+
+		case *graph.Node:
+			// Node kind matchers require that all kinds are present on the node
+			for _, kind := range matcher.Kinds {
+				if !typedReference.Kinds.ContainsOneOf(kind) {
+					return false, nil
+				}
+			}
+
+			return true, nil
+
+		case *graph.Relationship:
+			// Relationships have exactly one kind so the matcher is satisfied by any one of the kinds
+			return typedReference.Kind != nil && typedReference.Kind.Is(matcher.Kinds...), nil
+
+		default:
+			return nil, fmt.Errorf("invalid kind matcher reference type: %T", reference)
+		}
+	}
+}
+
+func (s evaluator) comparison(comparison *model.Comparison, scope bindings) (any, error) {
+	var result any = true
+
+	if left, err := s.evaluate(comparison.Left, scope); err != nil {
+		return nil, err
+	} else {
+		// Chained comparisons such as a < b < c are evaluated pairwise and joined as a conjunction
+		for _, partial := range comparison.Partials {
+			if right, err := s.evaluate(partial.Right, scope); err != nil {
+				return nil, err
+			} else if outcome, err := compare(partial.Operator, left, right); err != nil {
+				return nil, err
+			} else if outcome == nil {
+				result = nil
+			} else if !outcome.(bool) {
+				return false, nil
+			} else {
+				left = right
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func compare(operator model.Operator, left, right any) (any, error) {
+	switch operator {
+	case model.OperatorIs:
+		return left == nil, nil
+
+	case model.OperatorIsNot:
+		return left != nil, nil
+	}
+
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	switch operator {
+	case model.OperatorEquals:
+		return equalValues(left, right), nil
+
+	case model.OperatorNotEquals:
+		return !equalValues(left, right), nil
+
+	case model.OperatorGreaterThan, model.OperatorGreaterThanOrEqualTo, model.OperatorLessThan, model.OperatorLessThanOrEqualTo:
+		if ordering, comparable := compareValues(left, right); !comparable {
+			return nil, nil
+		} else {
+			switch operator {
+			case model.OperatorGreaterThan:
+				return ordering > 0, nil
+			case model.OperatorGreaterThanOrEqualTo:
+				return ordering >= 0, nil
+			case model.OperatorLessThan:
+				return ordering < 0, nil
+			default:
+				return ordering <= 0, nil
+			}
+		}
+
+	case model.OperatorStartsWith, model.OperatorEndsWith, model.OperatorContains:
+		if leftStr, isString := asString(left); !isString {
+			return nil, nil
+		} else if rightStr, isString := asString(right); !isString {
+			return nil, nil
+		} else {
+			switch operator {
+			case model.OperatorStartsWith:
+				return strings.HasPrefix(leftStr, rightStr), nil
+			case model.OperatorEndsWith:
+				return strings.HasSuffix(leftStr, rightStr), nil
+			default:
+				return strings.Contains(leftStr, rightStr), nil
+			}
+		}
+
+	case model.OperatorRegexMatch:
+		if leftStr, isString := asString(left); !isString {
+			return nil, nil
+		} else if rightStr, isString := asString(right); !isString {
+			return nil, nil
+		} else if expression, err := regexp.Compile("^(?:" + rightStr + ")$"); err != nil {
+			return nil, err
+		} else {
+			return expression.MatchString(leftStr), nil
+		}
+
+	case model.OperatorIn:
+		if values, isList := listValues(right); !isList {
+			return nil, fmt.Errorf("expected a list for the right operand of %s but got %T", operator, right)
+		} else {
+			var result any = false
+
+			for _, value := range values {
+				if value == nil {
+					result = nil
+				} else if equalValues(left, value) {
+					return true, nil
+				}
+			}
+
+			return result, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported comparison operator: %s", operator)
+	}
+}
+
+func (s evaluator) propertyLookup(lookup *model.PropertyLookup, scope bindings) (any, error) {
+	if atom, err := s.evaluate(lookup.Atom, scope); err != nil {
+		return nil, err
+	} else {
+		var value any
+
+		switch typedAtom := atom.(type) {
+		case nil:
+			return nil, nil
+
+		case *graph.Node:
+			if typedAtom.Properties != nil {
+				value = typedAtom.Properties.Map
+			}
+
+		case *graph.Relationship:
+			if typedAtom.Properties != nil {
+				value = typedAtom.Properties.Map
+			}
+
+		case map[string]any:
+			value = typedAtom
+
+		default:
+			return nil, fmt.Errorf("invalid property lookup reference type: %T", atom)
+		}
+
+		for _, symbol := range lookup.Symbols {
+			if properties, isMap := value.(map[string]any); !isMap {
+				return nil, nil
+			} else {
+				value = properties[symbol]
+			}
+		}
+
+		return value, nil
+	}
+}
+
+func (s evaluator) functionArgument(invocation *model.FunctionInvocation, scope bindings) (any, error) {
+	if len(invocation.Arguments) != 1 {
+		return nil, fmt.Errorf("function %s expects exactly one argument but got %d", invocation.Name, len(invocation.Arguments))
+	}
+
+	return s.evaluate(invocation.Arguments[0], scope)
+}
+
+func (s evaluator) functionInvocation(invocation *model.FunctionInvocation, scope bindings) (any, error) {
+	if len(invocation.Errors()) > 0 {
+		return nil, errors.Join(invocation.Errors()...)
+	}
+
+	switch strings.ToLower(invocation.Name) {
+	case "count":
+		return nil, fmt.Errorf("aggregate function %s is only supported as a projection", invocation.Name)
+	}
+
+	if argument, err := s.functionArgument(invocation, scope); err != nil {
+		return nil, err
+	} else if argument == nil {
+		switch strings.ToLower(invocation.Name) {
+		case "exists":
+			return false, nil
+
+		default:
+			return nil, nil
+		}
+	} else {
+		switch strings.ToLower(invocation.Name) {
+		case "id":
+			switch typedArgument := argument.(type) {
+			case *graph.Node:
+				return typedArgument.ID, nil
+
+			case *graph.Relationship:
+				return typedArgument.ID, nil
+			}
+
+		case "labels":
+			if node, isNode := argument.(*graph.Node); isNode {
+				return node.Kinds.Copy(), nil
+			}
+
+		case "type":
+			if relationship, isRelationship := argument.(*graph.Relationship); isRelationship {
+				return relationship.Kind, nil
+			}
+
+		case "tolower":
+			if str, isString := asString(argument); isString {
+				return strings.ToLower(str), nil
+			}
+
+		case "toupper":
+			if str, isString := asString(argument); isString {
+				return strings.ToUpper(str), nil
+			}
+
+		case "exists":
+			return true, nil
+
+		case "distinct":
+			// Distinct is applied to the projected rows of a result and evaluates to its argument
+			return argument, nil
+
+		default:
+			return nil, fmt.Errorf("unsupported function: %s", invocation.Name)
+		}
+
+		return nil, fmt.Errorf("invalid argument type for function %s: %T", invocation.Name, argument)
+	}
+}
+
+// patternExists evaluates a pattern predicate such as the one created by query.HasRelationships. Only single hop
+// patterns that start at a bound node are supported.
+func (s evaluator) patternExists(patternParts []*model.PatternPart, scope bindings) (any, error) {
+	if len(patternParts) != 1 || len(patternParts[0].PatternElements) != 3 {
+		return nil, fmt.Errorf("only single hop pattern predicates are supported")
+	}
+
+	var (
+		elements                 = patternParts[0].PatternElements
+		startPattern, startOK    = elements[0].AsNodePattern()
+		relationshipPattern, rOK = elements[1].AsRelationshipPattern()
+		endPattern, endOK        = elements[2].AsNodePattern()
+	)
+
+	if !startOK || !rOK || !endOK || startPattern.Binding == "" {
+		return nil, fmt.Errorf("only single hop pattern predicates that start at a bound node are supported")
+	}
+
+	if reference, err := scope.lookup(startPattern.Binding); err != nil {
+		return nil, err
+	} else if node, isNode := reference.(*graph.Node); !isNode {
+		return nil, fmt.Errorf("pattern predicate binding %s does not refer to a node", startPattern.Binding)
+	} else {
+		for _, relationshipID := range s.store.adjacent(node.ID, relationshipPattern.Direction) {
+			relationship := s.store.relationships[relationshipID]
+
+			if len(relationshipPattern.Kinds) > 0 && !relationship.Kind.Is(relationshipPattern.Kinds...) {
+				continue
+			}
+
+			otherID := relationship.EndID
+			if otherID == node.ID {
+				otherID = relationship.StartID
+			}
+
+			if otherNode := s.store.nodes[otherID]; len(endPattern.Kinds) == 0 || otherNode.Kinds.ContainsOneOf(endPattern.Kinds...) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+func (s evaluator) evaluate(expression graph.Criteria, scope bindings) (any, error) {
+	switch typedExpression := expression.(type) {
+	case nil:
+		return nil, nil
+
+	case *model.Where:
+		return s.conjunction(typedExpression.Expressions, scope)
+
+	case *model.Conjunction:
+		return s.conjunction(typedExpression.Expressions, scope)
+
+	case *model.Disjunction:
+		return s.disjunction(typedExpression.Expressions, scope)
+
+	case *model.ExclusiveDisjunction:
+		return s.exclusiveDisjunction(typedExpression.Expressions, scope)
+
+	case *model.Parenthetical:
+		return s.evaluate(typedExpression.Expression, scope)
+
+	case *model.Negation:
+		return s.negation(typedExpression, scope)
+
+	case *model.KindMatcher:
+		return s.kindMatcher(typedExpression, scope)
+
+	case *model.Comparison:
+		return s.comparison(typedExpression, scope)
+
+	case *model.PropertyLookup:
+		return s.propertyLookup(typedExpression, scope)
+
+	case *model.FunctionInvocation:
+		return s.functionInvocation(typedExpression, scope)
+
+	case []*model.PatternPart:
+		return s.patternExists(typedExpression, scope)
+
+	case *model.Variable:
+		return scope.lookup(typedExpression.Symbol)
+
+	case *model.Parameter:
+		return typedExpression.Value, nil
+
+	case *model.Literal:
+		if typedExpression.Null {
+			return nil, nil
+		}
+
+		return typedExpression.Value, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported criteria type: %T", expression)
+	}
+}
+
+// terms flattens the top level conjunctions of the given expression into a list of terms that must all hold.
+func terms(expression graph.Criteria) []graph.Criteria {
+	switch typedExpression := expression.(type) {
+	case nil:
+		return nil
+
+	case *model.Where:
+		var flattened []graph.Criteria
+
+		for _, subExpression := range typedExpression.Expressions {
+			flattened = append(flattened, terms(subExpression)...)
+		}
+
+		return flattened
+
+	case *model.Conjunction:
+		var flattened []graph.Criteria
+
+		for _, subExpression := range typedExpression.Expressions {
+			flattened = append(flattened, terms(subExpression)...)
+		}
+
+		return flattened
+
+	case *model.Parenthetical:
+		switch typedExpression.Expression.(type) {
+		case *model.Conjunction, *model.Parenthetical:
+			return terms(typedExpression.Expression)
+		}
+	}
+
+	return []graph.Criteria{expression}
+}
+
+// referencedSymbols returns the set of query symbols referenced by the given expression.
+func referencedSymbols(expression graph.Criteria) (map[string]struct{}, error) {
+	symbols := map[string]struct{}{}
+
+	return symbols, model.Walk(expression, func(parent, element any) error {
+		switch typedElement := element.(type) {
+		case *model.Variable:
+			symbols[typedElement.Symbol] = struct{}{}
+
+		case *model.NodePattern:
+			if typedElement.Binding != "" {
+				symbols[typedElement.Binding] = struct{}{}
+			}
+
+		case *model.RelationshipPattern:
+			if typedElement.Binding != "" {
+				symbols[typedElement.Binding] = struct{}{}
+			}
+		}
+
+		return nil
+	}, nil)
+}
+
+func toIDs(value any) ([]graph.ID, bool) {
+	if values, isList := listValues(value); isList {
+		ids := make([]graph.ID, 0, len(values))
+
+		for _, value := range values {
+			if id, isID := toID(value); !isID {
+				return nil, false
+			} else {
+				ids = append(ids, id)
+			}
+		}
+
+		return ids, true
+	} else if id, isID := toID(value); isID {
+		return []graph.ID{id}, true
+	}
+
+	return nil, false
+}
+
+func toID(value any) (graph.ID, bool) {
+	if intValue, isInt := normalize(value).(int64); isInt && intValue >= 0 {
+		return graph.ID(intValue), true
+	}
+
+	return 0, false
+}
+
+// identityConstraints inspects the top level terms of the given expression for identity comparisons, such as those
+// created by query.Equals(query.NodeID(), id) or query.InIDs(query.StartID(), ids...), and returns the set of IDs each
+// constrained symbol may take. This allows queries to narrow their candidates before evaluating the full expression.
+func identityConstraints(expression graph.Criteria) map[string]idSet {
+	constraints := map[string]idSet{}
+
+	for _, term := range terms(expression) {
+		comparison, isComparison := term.(*model.Comparison)
+
+		if !isComparison || len(comparison.Partials) != 1 {
+			continue
+		}
+
+		var (
+			partial                 = comparison.FirstPartial()
+			invocation, isInvocable = comparison.Left.(*model.FunctionInvocation)
+		)
+
+		if !isInvocable || invocation.Name != "id" || len(invocation.Arguments) != 1 {
+			continue
+		}
+
+		if partial.Operator != model.OperatorEquals && partial.Operator != model.OperatorIn {
+			continue
+		}
+
+		variable, isVariable := invocation.Arguments[0].(*model.Variable)
+		if !isVariable {
+			continue
+		}
+
+		var value any
+		switch typedRight := partial.Right.(type) {
+		case *model.Parameter:
+			value = typedRight.Value
+
+		case *model.Literal:
+			value = typedRight.Value
+
+		default:
+			continue
+		}
+
+		if ids, isIDs := toIDs(value); isIDs {
+			constrained := idSet{}
+
+			for _, id := range ids {
+				if existing, hasExisting := constraints[variable.Symbol]; !hasExisting {
+					constrained[id] = struct{}{}
+				} else if _, found := existing[id]; found {
+					constrained[id] = struct{}{}
+				}
+			}
+
+			constraints[variable.Symbol] = constrained
+		}
+	}
+
+	return constraints
+}
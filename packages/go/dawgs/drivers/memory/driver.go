@@ -0,0 +1,161 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+const (
+	DriverName = "memory"
+
+	DefaultBatchWriteSize = 20_000
+	DefaultWriteFlushSize = DefaultBatchWriteSize * 5
+)
+
+// driver is an in-memory graph.Database. Read transactions operate on the snapshot of the graph that was committed
+// when they began and never block. Write transactions and batch operations are serialized; nesting a write inside of
+// another write on the same database will deadlock.
+type driver struct {
+	storeLock      *sync.RWMutex
+	writeLock      *sync.Mutex
+	store          *store
+	schema         *graph.Schema
+	batchWriteSize int
+	writeFlushSize int
+}
+
+// NewDatabase returns a new, empty in-memory graph.Database.
+func NewDatabase() graph.Database {
+	return &driver{
+		storeLock:      &sync.RWMutex{},
+		writeLock:      &sync.Mutex{},
+		store:          newStore(),
+		schema:         graph.NewSchema(),
+		batchWriteSize: DefaultBatchWriteSize,
+		writeFlushSize: DefaultWriteFlushSize,
+	}
+}
+
+func (s *driver) current() *store {
+	s.storeLock.RLock()
+	defer s.storeLock.RUnlock()
+
+	return s.store
+}
+
+func (s *driver) publish(committed *store) {
+	s.storeLock.Lock()
+	defer s.storeLock.Unlock()
+
+	s.store = committed
+}
+
+func (s *driver) SetBatchWriteSize(size int) {
+	s.batchWriteSize = size
+}
+
+func (s *driver) SetWriteFlushSize(size int) {
+	s.writeFlushSize = size
+}
+
+func (s *driver) BatchOperation(ctx context.Context, batchDelegate graph.BatchDelegate) error {
+	if ctx.Err() != nil {
+		return graph.ErrContextTimedOut
+	}
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	batch := newBatchOperation(ctx, s)
+
+	if err := batchDelegate(batch); err != nil {
+		return err
+	}
+
+	return batch.Commit()
+}
+
+func (s *driver) transaction(ctx context.Context, txDelegate graph.TransactionDelegate, writable bool) error {
+	if ctx.Err() != nil {
+		return graph.ErrContextTimedOut
+	}
+
+	tx := newTransaction(ctx, s, writable)
+
+	if err := txDelegate(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *driver) ReadTransaction(ctx context.Context, txDelegate graph.TransactionDelegate, options ...graph.TransactionOption) error {
+	return s.transaction(ctx, txDelegate, false)
+}
+
+func (s *driver) WriteTransaction(ctx context.Context, txDelegate graph.TransactionDelegate, options ...graph.TransactionOption) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	return s.transaction(ctx, txDelegate, true)
+}
+
+func copySchema(schema *graph.Schema) *graph.Schema {
+	copied := graph.NewSchema()
+
+	for kind, kindSchema := range schema.Kinds {
+		copiedKindSchema := copied.EnsureKind(kind)
+
+		for property, index := range kindSchema.PropertyIndices {
+			copiedKindSchema.PropertyIndices[property] = index
+		}
+
+		for property, constraint := range kindSchema.PropertyConstraints {
+			copiedKindSchema.PropertyConstraints[property] = constraint
+		}
+	}
+
+	return copied
+}
+
+func (s *driver) FetchSchema(ctx context.Context) (*graph.Schema, error) {
+	s.storeLock.RLock()
+	defer s.storeLock.RUnlock()
+
+	return copySchema(s.schema), nil
+}
+
+// AssertSchema records the given schema. Indexes and constraints are not enforced by the in-memory driver.
+func (s *driver) AssertSchema(ctx context.Context, schema *graph.Schema) error {
+	s.storeLock.Lock()
+	defer s.storeLock.Unlock()
+
+	s.schema = copySchema(schema)
+	return nil
+}
+
+func (s *driver) Run(ctx context.Context, query string, parameters map[string]any) error {
+	return graph.NewError(query, graph.ErrUnsupportedDatabaseOperation)
+}
+
+func (s *driver) Close() error {
+	return nil
+}
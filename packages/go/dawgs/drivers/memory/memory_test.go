@@ -0,0 +1,489 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	Entity     = graph.StringKind("Base")
+	Group      = graph.StringKind("Group")
+	User       = graph.StringKind("User")
+	Computer   = graph.StringKind("Computer")
+	MemberOf   = graph.StringKind("MemberOf")
+	AdminTo    = graph.StringKind("AdminTo")
+	HasSession = graph.StringKind("HasSession")
+)
+
+type testGraph struct {
+	UserA     *graph.Node
+	UserB     *graph.Node
+	GroupA    *graph.Node
+	GroupB    *graph.Node
+	ComputerA *graph.Node
+}
+
+func newTestGraph(t *testing.T, db graph.Database) testGraph {
+	var harness testGraph
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(name string, kinds ...graph.Kind) *graph.Node {
+			node, err := tx.CreateNode(graph.AsProperties(map[string]any{
+				"name":     name,
+				"objectid": name + "-ID",
+			}), kinds...)
+
+			require.Nil(t, err)
+			return node
+		}
+
+		newRelationship := func(start, end *graph.Node, kind graph.Kind) {
+			_, err := tx.CreateRelationship(start, end, kind, graph.NewProperties())
+			require.Nil(t, err)
+		}
+
+		harness.UserA = newNode("USER A", Entity, User)
+		harness.UserB = newNode("USER B", Entity, User)
+		harness.GroupA = newNode("GROUP A", Entity, Group)
+		harness.GroupB = newNode("GROUP B", Entity, Group)
+		harness.ComputerA = newNode("COMPUTER A", Entity, Computer)
+
+		newRelationship(harness.UserA, harness.GroupA, MemberOf)
+		newRelationship(harness.UserB, harness.GroupB, MemberOf)
+		newRelationship(harness.GroupA, harness.GroupB, MemberOf)
+		newRelationship(harness.GroupB, harness.ComputerA, AdminTo)
+		newRelationship(harness.UserA, harness.ComputerA, AdminTo)
+		newRelationship(harness.ComputerA, harness.UserB, HasSession)
+
+		return nil
+	}))
+
+	return harness
+}
+
+func TestNodeQuery_Filter(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newTestGraph(t, db)
+	)
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		if count, err := tx.Nodes().Filter(query.Kind(query.Node(), User)).Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(2), count)
+		}
+
+		if node, err := tx.Nodes().Filter(query.Equals(query.NodeProperty("name"), "GROUP B")).First(); err != nil {
+			return err
+		} else {
+			require.Equal(t, harness.GroupB.ID, node.ID)
+			require.Equal(t, graph.Kinds{Entity, Group}, node.Kinds)
+		}
+
+		if count, err := tx.Nodes().Filter(query.And(
+			query.Kind(query.Node(), Entity),
+			query.Or(
+				query.StringStartsWith(query.NodeProperty("name"), "GROUP"),
+				query.CaseInsensitiveStringContains(query.NodeProperty("name"), "computer"),
+			),
+			query.Not(query.Equals(query.NodeProperty("name"), "GROUP A")),
+		)).Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(2), count)
+		}
+
+		if count, err := tx.Nodes().Filter(query.InIDs(query.NodeID(), harness.UserA.ID, harness.ComputerA.ID)).Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(2), count)
+		}
+
+		// Null semantics: comparisons against missing properties never match, even when negated
+		if count, err := tx.Nodes().Filter(query.Not(query.Equals(query.NodeProperty("missing"), "value"))).Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(0), count)
+		}
+
+		// Negated string comparisons against missing properties match
+		if count, err := tx.Nodes().Filter(query.Not(query.StringContains(query.NodeProperty("missing"), "value"))).Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(5), count)
+		}
+
+		if count, err := tx.Nodes().Filter(query.IsNull(query.NodeProperty("missing"))).Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(5), count)
+		}
+
+		_, err := tx.Nodes().Filter(query.Equals(query.NodeProperty("name"), "NOT A NODE")).First()
+		require.True(t, graph.IsErrNotFound(err))
+
+		return nil
+	}))
+}
+
+func TestNodeQuery_OrderAndPaging(t *testing.T) {
+	db := memory.NewDatabase()
+	newTestGraph(t, db)
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		var names []string
+
+		if err := tx.Nodes().Filter(
+			query.Kind(query.Node(), Entity),
+		).OrderBy(
+			query.Order(query.NodeProperty("name"), query.Descending()),
+		).Offset(1).Limit(2).Fetch(func(cursor graph.Cursor[*graph.Node]) error {
+			for node := range cursor.Chan() {
+				name, _ := node.Properties.Get("name").String()
+				names = append(names, name)
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return err
+		}
+
+		require.Equal(t, []string{"USER A", "GROUP B"}, names)
+		return nil
+	}))
+}
+
+func TestNodeQuery_Execute(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newTestGraph(t, db)
+	)
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		return tx.Nodes().Filter(
+			query.Equals(query.NodeID(), harness.UserA.ID),
+		).Execute(func(results graph.Result) error {
+			var (
+				nodeID   graph.ID
+				kinds    graph.Kinds
+				objectID string
+			)
+
+			require.True(t, results.Next())
+			require.Nil(t, results.Scan(&nodeID, &kinds, &objectID))
+			require.False(t, results.Next())
+
+			require.Equal(t, harness.UserA.ID, nodeID)
+			require.Equal(t, graph.Kinds{Entity, User}, kinds)
+			require.Equal(t, "USER A-ID", objectID)
+
+			return results.Error()
+		}, query.Returning(
+			query.NodeID(),
+			query.KindsOf(query.Node()),
+			query.NodeProperty("objectid"),
+		))
+	}))
+}
+
+func TestNodeQuery_UpdateAndDelete(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newTestGraph(t, db)
+	)
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		if err := tx.Nodes().Filter(query.Kind(query.Node(), Group)).Update(graph.NewProperties().Set("tier", 0).Delete("objectid")); err != nil {
+			return err
+		}
+
+		return tx.Nodes().Filter(query.Equals(query.NodeID(), harness.ComputerA.ID)).Delete()
+	}))
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		if node, err := tx.Nodes().Filter(query.Equals(query.NodeID(), harness.GroupA.ID)).First(); err != nil {
+			return err
+		} else {
+			tier, _ := node.Properties.Get("tier").Int()
+
+			require.Equal(t, 0, tier)
+			require.True(t, node.Properties.Exists("tier"))
+			require.False(t, node.Properties.Exists("objectid"))
+		}
+
+		// Deleting a node detaches all of its relationships
+		if count, err := tx.Relationships().Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(3), count)
+		}
+
+		return nil
+	}))
+}
+
+func TestTransaction_Rollback(t *testing.T) {
+	var (
+		db          = memory.NewDatabase()
+		expectedErr = errors.New("rollback")
+	)
+
+	newTestGraph(t, db)
+
+	require.Equal(t, expectedErr, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		if _, err := tx.CreateNode(graph.NewProperties(), User); err != nil {
+			return err
+		}
+
+		// Writes are visible within the transaction
+		count, err := tx.Nodes().Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(6), count)
+
+		return expectedErr
+	}))
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		count, err := tx.Nodes().Count()
+		require.Nil(t, err)
+		require.Equal(t, int64(5), count)
+
+		_, err = tx.CreateNode(graph.NewProperties(), User)
+		require.ErrorIs(t, err, memory.ErrReadOnlyTransaction)
+
+		return nil
+	}))
+}
+
+func TestTransaction_ReturnedEntitiesAreDetached(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newTestGraph(t, db)
+	)
+
+	harness.UserA.Properties.Set("name", "CHANGED")
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		node, err := tx.Nodes().Filter(query.Equals(query.NodeID(), harness.UserA.ID)).First()
+		require.Nil(t, err)
+
+		name, _ := node.Properties.Get("name").String()
+		require.Equal(t, "USER A", name)
+
+		return nil
+	}))
+}
+
+func TestTransaction_UpdateNode(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newTestGraph(t, db)
+	)
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		node, err := tx.Nodes().Filter(query.Equals(query.NodeID(), harness.UserA.ID)).First()
+		require.Nil(t, err)
+
+		node.AddKinds(Computer)
+		node.DeleteKinds(User)
+		node.Properties.Set("enabled", true)
+
+		return tx.UpdateNode(node)
+	}))
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		node, err := tx.Nodes().Filter(query.Equals(query.NodeID(), harness.UserA.ID)).First()
+		require.Nil(t, err)
+
+		enabled, _ := node.Properties.Get("enabled").Bool()
+
+		require.Equal(t, graph.Kinds{Entity, Computer}, node.Kinds)
+		require.True(t, enabled)
+
+		return nil
+	}))
+}
+
+func TestBatch_UpdateBy(t *testing.T) {
+	db := memory.NewDatabase()
+
+	require.Nil(t, db.BatchOperation(context.Background(), func(batch graph.Batch) error {
+		for i := 0; i < 2; i++ {
+			if err := batch.UpdateNodeBy(graph.NodeUpdate{
+				Node: graph.PrepareNode(graph.AsProperties(map[string]any{
+					"objectid": "1-2-3",
+					"name":     "USER",
+				}), User),
+				IdentityKind:       Entity,
+				IdentityProperties: []string{"objectid"},
+			}); err != nil {
+				return err
+			}
+
+			if err := batch.UpdateRelationshipBy(graph.RelationshipUpdate{
+				Relationship:            graph.PrepareRelationship(graph.AsProperties(map[string]any{"isacl": false}), MemberOf),
+				Start:                   graph.PrepareNode(graph.AsProperties(map[string]any{"objectid": "1-2-3"}), User),
+				StartIdentityKind:       Entity,
+				StartIdentityProperties: []string{"objectid"},
+				End:                     graph.PrepareNode(graph.AsProperties(map[string]any{"objectid": "4-5-6"}), Group),
+				EndIdentityKind:         Entity,
+				EndIdentityProperties:   []string{"objectid"},
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		if node, err := tx.Nodes().Filter(query.Equals(query.NodeProperty("objectid"), "1-2-3")).First(); err != nil {
+			return err
+		} else {
+			require.Equal(t, graph.Kinds{Entity, User}, node.Kinds)
+			require.True(t, node.Properties.Exists("lastseen"))
+		}
+
+		if count, err := tx.Nodes().Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(2), count)
+		}
+
+		if count, err := tx.Relationships().Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(1), count)
+		}
+
+		return nil
+	}))
+}
+
+func TestRelationshipQuery_Traversal(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newTestGraph(t, db)
+	)
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		var groupIDs []graph.ID
+
+		// Expand the outbound memberships of user A
+		if err := tx.Relationships().Filter(query.And(
+			query.Equals(query.StartID(), harness.UserA.ID),
+			query.Kind(query.Relationship(), MemberOf),
+		)).FetchDirection(graph.DirectionInbound, func(cursor graph.Cursor[graph.DirectionalResult]) error {
+			for next := range cursor.Chan() {
+				groupIDs = append(groupIDs, next.Node.ID)
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return err
+		}
+
+		require.Equal(t, []graph.ID{harness.GroupA.ID}, groupIDs)
+
+		var triples []graph.RelationshipTripleResult
+
+		if err := tx.Relationships().Filter(query.And(
+			query.Kind(query.Start(), Group),
+			query.KindIn(query.Relationship(), MemberOf, AdminTo),
+		)).FetchTriples(func(cursor graph.Cursor[graph.RelationshipTripleResult]) error {
+			for next := range cursor.Chan() {
+				triples = append(triples, next)
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return err
+		}
+
+		require.Len(t, triples, 2)
+		require.Equal(t, harness.GroupA.ID, triples[0].StartID)
+		require.Equal(t, harness.GroupB.ID, triples[0].EndID)
+		require.Equal(t, harness.GroupB.ID, triples[1].StartID)
+		require.Equal(t, harness.ComputerA.ID, triples[1].EndID)
+
+		if count, err := tx.Nodes().Filter(query.Not(query.HasRelationships(query.Node()))).Count(); err != nil {
+			return err
+		} else {
+			require.Equal(t, int64(0), count)
+		}
+
+		return nil
+	}))
+}
+
+func TestRelationshipQuery_FetchAllShortestPaths(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newTestGraph(t, db)
+	)
+
+	require.Nil(t, db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
+		var paths graph.PathSet
+
+		if err := tx.Relationships().Filter(query.And(
+			query.Equals(query.StartID(), harness.UserA.ID),
+			query.Equals(query.EndID(), harness.UserB.ID),
+		)).FetchAllShortestPaths(func(cursor graph.Cursor[graph.Path]) error {
+			for path := range cursor.Chan() {
+				paths = append(paths, path)
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return err
+		}
+
+		require.Len(t, paths, 1)
+		require.Equal(t, harness.UserA.ID, paths[0].Root().ID)
+		require.Equal(t, harness.UserB.ID, paths[0].Terminal().ID)
+		require.Len(t, paths[0].Edges, 2)
+
+		// Excluding admin edges leaves no path to the computer
+		paths = nil
+
+		if err := tx.Relationships().Filter(query.And(
+			query.Equals(query.StartID(), harness.UserA.ID),
+			query.Equals(query.EndID(), harness.ComputerA.ID),
+			query.Not(query.Kind(query.Relationship(), AdminTo)),
+		)).FetchAllShortestPaths(func(cursor graph.Cursor[graph.Path]) error {
+			for path := range cursor.Chan() {
+				paths = append(paths, path)
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return err
+		}
+
+		require.Len(t, paths, 0)
+		return nil
+	}))
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+)
+
+type nodeQuery struct {
+	ctx  context.Context
+	tx   *transaction
+	spec *querySpec
+}
+
+func newNodeQuery(ctx context.Context, tx *transaction) graph.NodeQuery {
+	return &nodeQuery{
+		ctx:  ctx,
+		tx:   tx,
+		spec: &querySpec{},
+	}
+}
+
+func (s *nodeQuery) Execute(delegate func(results graph.Result) error, finalCriteria ...graph.Criteria) error {
+	for _, criteria := range finalCriteria {
+		s.spec.apply(criteria)
+	}
+
+	evaluator := s.tx.evaluator()
+
+	if err := s.spec.prepare(); err != nil {
+		return err
+	} else if candidates, err := evaluator.matchNodes(query.NodeSymbol, s.spec.filter()); err != nil {
+		return err
+	} else if rows, err := evaluator.results(s.spec, candidates); err != nil {
+		return err
+	} else {
+		result := newResult(rows)
+		defer result.Close()
+
+		return delegate(result)
+	}
+}
+
+func (s *nodeQuery) Debug() (string, map[string]any) {
+	return s.spec.debug()
+}
+
+func (s *nodeQuery) Delete() error {
+	if err := s.spec.prepare(); err != nil {
+		return err
+	} else if store, err := s.tx.writableStore(); err != nil {
+		return err
+	} else if candidates, err := (evaluator{store: store}).matchNodes(query.NodeSymbol, s.spec.filter()); err != nil {
+		return err
+	} else {
+		for _, candidate := range candidates {
+			store.deleteNode(candidate.node.ID)
+		}
+
+		return nil
+	}
+}
+
+func (s *nodeQuery) OrderBy(criteria ...graph.Criteria) graph.NodeQuery {
+	s.spec.apply(query.OrderBy(criteria...))
+	return s
+}
+
+func (s *nodeQuery) Offset(offset int) graph.NodeQuery {
+	s.spec.apply(query.Offset(offset))
+	return s
+}
+
+func (s *nodeQuery) Limit(limit int) graph.NodeQuery {
+	s.spec.apply(query.Limit(limit))
+	return s
+}
+
+func (s *nodeQuery) Filter(criteria graph.Criteria) graph.NodeQuery {
+	s.spec.apply(query.Where(criteria))
+	return s
+}
+
+func (s *nodeQuery) Filterf(criteriaDelegate graph.CriteriaProvider) graph.NodeQuery {
+	return s.Filter(criteriaDelegate())
+}
+
+func (s *nodeQuery) Count() (int64, error) {
+	var count int64
+
+	err := s.Execute(func(results graph.Result) error {
+		if !results.Next() {
+			return graph.ErrNoResultsFound
+		}
+
+		return results.Scan(&count)
+	}, query.Returning(
+		query.Count(query.Node()),
+	))
+
+	return count, err
+}
+
+func (s *nodeQuery) Update(properties *graph.Properties) error {
+	if err := s.spec.prepare(); err != nil {
+		return err
+	} else if store, err := s.tx.writableStore(); err != nil {
+		return err
+	} else if candidates, err := (evaluator{store: store}).matchNodes(query.NodeSymbol, s.spec.filter()); err != nil {
+		return err
+	} else {
+		for _, candidate := range candidates {
+			store.putNode(graph.NewNode(candidate.node.ID, updateProperties(candidate.node.Properties, properties), candidate.node.Kinds...))
+		}
+
+		return nil
+	}
+}
+
+func (s *nodeQuery) First() (*graph.Node, error) {
+	var node graph.Node
+
+	err := s.Execute(func(results graph.Result) error {
+		if !results.Next() {
+			return graph.ErrNoResultsFound
+		}
+
+		return results.Scan(&node)
+	}, query.Returning(
+		query.Node(),
+	), query.Limit(1))
+
+	return &node, err
+}
+
+func (s *nodeQuery) Fetch(delegate func(cursor graph.Cursor[*graph.Node]) error) error {
+	return s.Execute(func(result graph.Result) error {
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (*graph.Node, error) {
+			var node graph.Node
+			return &node, scanner.Scan(&node)
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}, query.Returning(
+		query.Node(),
+	))
+}
+
+func (s *nodeQuery) FetchIDs(delegate func(cursor graph.Cursor[graph.ID]) error) error {
+	return s.Execute(func(result graph.Result) error {
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (graph.ID, error) {
+			var nodeID graph.ID
+			return nodeID, scanner.Scan(&nodeID)
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}, query.Returning(
+		query.NodeID(),
+	))
+}
+
+func (s *nodeQuery) FetchKinds(delegate func(cursor graph.Cursor[graph.KindsResult]) error) error {
+	return s.Execute(func(result graph.Result) error {
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (graph.KindsResult, error) {
+			var (
+				nodeID    graph.ID
+				nodeKinds graph.Kinds
+				err       = scanner.Scan(&nodeID, &nodeKinds)
+			)
+
+			return graph.KindsResult{
+				ID:    nodeID,
+				Kinds: nodeKinds,
+			}, err
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}, query.Returning(
+		query.NodeID(),
+		query.KindsOf(query.Node()),
+	))
+}
@@ -0,0 +1,630 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/specterops/bloodhound/cypher/model"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/query/neo4j"
+)
+
+// querySpec accumulates the criteria handed to a node or relationship query. It mirrors the clause handling of the
+// Neo4j query builder: repeated filters replace the previous filter while ordering, paging and projection criteria
+// may be supplied either directly or as part of the returning criteria.
+type querySpec struct {
+	where     *model.Where
+	order     *model.Order
+	skip      *model.Skip
+	limit     *model.Limit
+	returning *model.Return
+	errors    []error
+}
+
+func (s *querySpec) apply(criteria graph.Criteria) {
+	switch typedCriteria := criteria.(type) {
+	case *model.Where:
+		s.where = typedCriteria
+
+	case *model.Return:
+		s.returning = typedCriteria
+
+	case *model.Limit:
+		s.limit = typedCriteria
+
+	case *model.Skip:
+		s.skip = typedCriteria
+
+	case *model.Order:
+		s.order = typedCriteria
+
+	default:
+		s.errors = append(s.errors, fmt.Errorf("invalid type for dawgs query: %T", criteria))
+	}
+}
+
+func (s *querySpec) filter() graph.Criteria {
+	if s.where == nil {
+		return nil
+	}
+
+	return s.where
+}
+
+func (s *querySpec) projection() *model.Projection {
+	if s.returning == nil {
+		return nil
+	}
+
+	return s.returning.Projection
+}
+
+func (s *querySpec) sortOrder() *model.Order {
+	if s.order == nil && s.projection() != nil {
+		return s.projection().Order
+	}
+
+	return s.order
+}
+
+func (s *querySpec) skipCount() (int, error) {
+	if s.skip != nil {
+		return literalInt(s.skip.Value)
+	} else if projection := s.projection(); projection != nil && projection.Skip != nil {
+		return literalInt(projection.Skip.Value)
+	}
+
+	return 0, nil
+}
+
+func (s *querySpec) limitCount() (int, error) {
+	if s.limit != nil {
+		return literalInt(s.limit.Value)
+	} else if projection := s.projection(); projection != nil && projection.Limit != nil {
+		return literalInt(projection.Limit.Value)
+	}
+
+	return -1, nil
+}
+
+// prepare returns any errors collected while building the query, including errors recorded in the criteria model
+// itself.
+func (s *querySpec) prepare() error {
+	var (
+		modelErrors = append([]error{}, s.errors...)
+		criteriaSet []graph.Criteria
+	)
+
+	if s.where != nil {
+		criteriaSet = append(criteriaSet, s.where)
+	}
+
+	if order := s.sortOrder(); order != nil {
+		criteriaSet = append(criteriaSet, order)
+	}
+
+	if projection := s.projection(); projection != nil {
+		criteriaSet = append(criteriaSet, projection)
+	}
+
+	for _, criteria := range criteriaSet {
+		if err := model.Walk(criteria, func(parent, element any) error {
+			if errorNode, typeOK := element.(model.Fallible); typeOK {
+				modelErrors = append(modelErrors, errorNode.Errors()...)
+			}
+
+			return nil
+		}, nil); err != nil {
+			modelErrors = append(modelErrors, err)
+		}
+	}
+
+	return errors.Join(modelErrors...)
+}
+
+// debug renders the Cypher equivalent of the query spec. The in-memory driver does not execute Cypher but the
+// rendered statement is useful when comparing results against other drivers.
+func (s *querySpec) debug() (string, map[string]any) {
+	queryBuilder := neo4j.NewEmptyQueryBuilder()
+
+	if s.where != nil {
+		queryBuilder.Apply(s.where)
+	}
+
+	if s.returning != nil {
+		queryBuilder.Apply(model.Copy(s.returning))
+	}
+
+	if s.order != nil {
+		queryBuilder.Apply(s.order)
+	}
+
+	if s.skip != nil {
+		queryBuilder.Apply(s.skip)
+	}
+
+	if s.limit != nil {
+		queryBuilder.Apply(s.limit)
+	}
+
+	if err := queryBuilder.Prepare(); err != nil {
+		return "", nil
+	}
+
+	rendered, _ := queryBuilder.Render()
+	return rendered, queryBuilder.Parameters
+}
+
+func literalInt(expression model.Expression) (int, error) {
+	if value, err := (evaluator{}).evaluate(expression, bindings{}); err != nil {
+		return 0, err
+	} else if intValue, err := asInt64(value); err != nil {
+		return 0, err
+	} else {
+		return int(intValue), nil
+	}
+}
+
+func nodeScope(symbol string, node *graph.Node) bindings {
+	switch symbol {
+	case query.RelationshipStartSymbol:
+		return bindings{
+			start: node,
+		}
+
+	case query.RelationshipEndSymbol:
+		return bindings{
+			end: node,
+		}
+
+	default:
+		return bindings{
+			node: node,
+		}
+	}
+}
+
+func (s evaluator) relationshipScope(relationship *graph.Relationship) bindings {
+	return bindings{
+		start:        s.store.nodes[relationship.StartID],
+		relationship: relationship,
+		end:          s.store.nodes[relationship.EndID],
+	}
+}
+
+// matchNodes returns the nodes, bound to the given symbol, that satisfy the given criteria. Nodes are returned in ID
+// order.
+func (s evaluator) matchNodes(symbol string, criteria graph.Criteria) ([]bindings, error) {
+	var (
+		matched      []bindings
+		candidateIDs []graph.ID
+	)
+
+	if constrainedIDs, isConstrained := identityConstraints(criteria)[symbol]; isConstrained {
+		candidateIDs = constrainedIDs.sorted()
+	} else {
+		candidateIDs = s.store.nodeIDs()
+	}
+
+	for _, candidateID := range candidateIDs {
+		if node, found := s.store.nodes[candidateID]; found {
+			scope := nodeScope(symbol, node)
+
+			if isMatch, err := s.matches(criteria, scope); err != nil {
+				return nil, err
+			} else if isMatch {
+				matched = append(matched, scope)
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// matchRelationships returns the relationships that satisfy the given criteria along with their start and end nodes.
+// Relationships are returned in ID order.
+func (s evaluator) matchRelationships(criteria graph.Criteria) ([]bindings, error) {
+	var (
+		matched      []bindings
+		candidateIDs []graph.ID
+		constraints  = identityConstraints(criteria)
+	)
+
+	if relationshipIDs, isConstrained := constraints[query.RelationshipSymbol]; isConstrained {
+		candidateIDs = relationshipIDs.sorted()
+	} else if startIDs, isConstrained := constraints[query.RelationshipStartSymbol]; isConstrained {
+		relationshipIDs := idSet{}
+
+		for startID := range startIDs {
+			for relationshipID := range s.store.outbound[startID] {
+				relationshipIDs[relationshipID] = struct{}{}
+			}
+		}
+
+		candidateIDs = relationshipIDs.sorted()
+	} else if endIDs, isConstrained := constraints[query.RelationshipEndSymbol]; isConstrained {
+		relationshipIDs := idSet{}
+
+		for endID := range endIDs {
+			for relationshipID := range s.store.inbound[endID] {
+				relationshipIDs[relationshipID] = struct{}{}
+			}
+		}
+
+		candidateIDs = relationshipIDs.sorted()
+	} else {
+		candidateIDs = s.store.relationshipIDs()
+	}
+
+	for _, candidateID := range candidateIDs {
+		if relationship, found := s.store.relationships[candidateID]; found {
+			scope := s.relationshipScope(relationship)
+
+			if isMatch, err := s.matches(criteria, scope); err != nil {
+				return nil, err
+			} else if isMatch {
+				matched = append(matched, scope)
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// compareSortKeys orders two values for sorting. Null values sort after all other values in ascending order as they
+// do in Cypher.
+func compareSortKeys(left, right any) int {
+	if left == nil && right == nil {
+		return 0
+	} else if left == nil {
+		return 1
+	} else if right == nil {
+		return -1
+	} else if ordering, comparable := compareValues(left, right); comparable {
+		return ordering
+	}
+
+	return 0
+}
+
+func (s evaluator) sort(order *model.Order, candidates []bindings) error {
+	if order == nil || len(order.Items) == 0 {
+		return nil
+	}
+
+	sortKeys := make(map[int][]any, len(candidates))
+
+	for idx, candidate := range candidates {
+		keys := make([]any, len(order.Items))
+
+		for itemIdx, item := range order.Items {
+			if value, err := s.evaluate(item.Expression, candidate); err != nil {
+				return err
+			} else {
+				keys[itemIdx] = value
+			}
+		}
+
+		sortKeys[idx] = keys
+	}
+
+	indices := make([]int, len(candidates))
+	for idx := range indices {
+		indices[idx] = idx
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		var (
+			leftKeys  = sortKeys[indices[i]]
+			rightKeys = sortKeys[indices[j]]
+		)
+
+		for itemIdx, item := range order.Items {
+			if ordering := compareSortKeys(leftKeys[itemIdx], rightKeys[itemIdx]); ordering != 0 {
+				if item.Ascending {
+					return ordering < 0
+				}
+
+				return ordering > 0
+			}
+		}
+
+		return false
+	})
+
+	sorted := make([]bindings, len(candidates))
+	for idx, candidateIdx := range indices {
+		sorted[idx] = candidates[candidateIdx]
+	}
+
+	copy(candidates, sorted)
+	return nil
+}
+
+func isFunction(expression model.Expression, name string) bool {
+	if invocation, isInvocation := expression.(*model.FunctionInvocation); isInvocation {
+		return strings.EqualFold(invocation.Name, name)
+	}
+
+	return false
+}
+
+// exportValue detaches entity values from the store before they are handed to a caller.
+func exportValue(value any) any {
+	switch typedValue := value.(type) {
+	case *graph.Node:
+		return copyNode(typedValue)
+
+	case *graph.Relationship:
+		return copyRelationship(typedValue)
+
+	case graph.Kinds:
+		return typedValue.Copy()
+
+	default:
+		return value
+	}
+}
+
+func rowKey(row []any) string {
+	builder := strings.Builder{}
+
+	for _, value := range row {
+		switch typedValue := value.(type) {
+		case *graph.Node:
+			builder.WriteString(fmt.Sprintf("n%d", typedValue.ID))
+
+		case *graph.Relationship:
+			builder.WriteString(fmt.Sprintf("r%d", typedValue.ID))
+
+		default:
+			builder.WriteString(fmt.Sprintf("%T:%v", normalize(value), normalize(value)))
+		}
+
+		builder.WriteRune('|')
+	}
+
+	return builder.String()
+}
+
+func page[T any](values []T, skip, limit int) []T {
+	if skip > 0 {
+		if skip >= len(values) {
+			return nil
+		}
+
+		values = values[skip:]
+	}
+
+	if limit >= 0 && limit < len(values) {
+		values = values[:limit]
+	}
+
+	return values
+}
+
+// results evaluates the projection of the given query spec against the matched candidates and returns the projected
+// rows. Clauses are applied in the same order as Cypher: ordering, projection, distinct and then paging.
+func (s evaluator) results(spec *querySpec, candidates []bindings) ([][]any, error) {
+	projection := spec.projection()
+
+	if projection == nil || len(projection.Items) == 0 {
+		return nil, fmt.Errorf("query has no projection")
+	}
+
+	var rows [][]any
+
+	if skip, err := spec.skipCount(); err != nil {
+		return nil, err
+	} else if limit, err := spec.limitCount(); err != nil {
+		return nil, err
+	} else if len(projection.Items) == 1 && isFunction(projection.Items[0].Expression, "count") {
+		// Aggregate results always produce a single row
+		rows = page([][]any{{int64(len(candidates))}}, skip, limit)
+	} else if err := s.sort(spec.sortOrder(), candidates); err != nil {
+		return nil, err
+	} else {
+		distinct := projection.Distinct
+		rows = make([][]any, 0, len(candidates))
+
+		for _, item := range projection.Items {
+			if isFunction(item.Expression, "count") {
+				return nil, fmt.Errorf("aggregate functions may only be projected alone")
+			} else if isFunction(item.Expression, "distinct") {
+				distinct = true
+			}
+		}
+
+		for _, candidate := range candidates {
+			row := make([]any, len(projection.Items))
+
+			for idx, item := range projection.Items {
+				if value, err := s.evaluate(item.Expression, candidate); err != nil {
+					return nil, err
+				} else {
+					row[idx] = exportValue(value)
+				}
+			}
+
+			rows = append(rows, row)
+		}
+
+		if distinct {
+			var (
+				seen         = map[string]struct{}{}
+				distinctRows = rows[:0]
+			)
+
+			for _, row := range rows {
+				key := rowKey(row)
+
+				if _, hasKey := seen[key]; !hasKey {
+					seen[key] = struct{}{}
+					distinctRows = append(distinctRows, row)
+				}
+			}
+
+			rows = distinctRows
+		}
+
+		rows = page(rows, skip, limit)
+	}
+
+	return rows, nil
+}
+
+// allShortestPaths finds all shortest outbound paths between the start nodes and end nodes described by the given
+// criteria. Criteria that reference only the start symbol select start nodes, criteria that reference only the end
+// symbol select end nodes and all remaining criteria must hold for every relationship along a path.
+func (s evaluator) allShortestPaths(criteria graph.Criteria) ([]graph.Path, error) {
+	var (
+		startTerms        []graph.Criteria
+		endTerms          []graph.Criteria
+		relationshipTerms []graph.Criteria
+	)
+
+	for _, term := range terms(criteria) {
+		if symbols, err := referencedSymbols(term); err != nil {
+			return nil, err
+		} else {
+			_, hasStart := symbols[query.RelationshipStartSymbol]
+			_, hasEnd := symbols[query.RelationshipEndSymbol]
+			_, hasRelationship := symbols[query.RelationshipSymbol]
+
+			switch {
+			case hasStart && hasEnd:
+				return nil, fmt.Errorf("shortest path criteria may not reference both the start and end nodes in a single term")
+			case hasStart && !hasRelationship:
+				startTerms = append(startTerms, term)
+			case hasEnd && !hasRelationship:
+				endTerms = append(endTerms, term)
+			default:
+				relationshipTerms = append(relationshipTerms, term)
+			}
+		}
+	}
+
+	var (
+		paths              []graph.Path
+		relationshipFilter = query.And(relationshipTerms...)
+	)
+
+	if startScopes, err := s.matchNodes(query.RelationshipStartSymbol, query.And(startTerms...)); err != nil {
+		return nil, err
+	} else if endScopes, err := s.matchNodes(query.RelationshipEndSymbol, query.And(endTerms...)); err != nil {
+		return nil, err
+	} else {
+		endIDs := make(idSet, len(endScopes))
+
+		for _, endScope := range endScopes {
+			endIDs[endScope.end.ID] = struct{}{}
+		}
+
+		for _, startScope := range startScopes {
+			if startPaths, err := s.shortestPathsFrom(startScope.start, endIDs, relationshipFilter); err != nil {
+				return nil, err
+			} else {
+				paths = append(paths, startPaths...)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+func (s evaluator) shortestPathsFrom(start *graph.Node, endIDs idSet, relationshipFilter graph.Criteria) ([]graph.Path, error) {
+	var (
+		paths        []graph.Path
+		depths       = map[graph.ID]int{start.ID: 0}
+		predecessors = map[graph.ID][]*graph.Relationship{}
+		frontier     = []graph.ID{start.ID}
+		remaining    = len(endIDs)
+	)
+
+	if _, isEnd := endIDs[start.ID]; isEnd {
+		remaining--
+	}
+
+	// Expand the search one depth at a time, recording every relationship that reaches a node at its shortest depth
+	for depth := 1; len(frontier) > 0 && remaining > 0; depth++ {
+		var nextFrontier []graph.ID
+
+		for _, nodeID := range frontier {
+			for _, relationshipID := range s.store.adjacent(nodeID, graph.DirectionOutbound) {
+				relationship := s.store.relationships[relationshipID]
+
+				if reachedDepth, reached := depths[relationship.EndID]; reached && reachedDepth < depth {
+					continue
+				}
+
+				if isMatch, err := s.matches(relationshipFilter, s.relationshipScope(relationship)); err != nil {
+					return nil, err
+				} else if !isMatch {
+					continue
+				}
+
+				if _, reached := depths[relationship.EndID]; !reached {
+					depths[relationship.EndID] = depth
+					nextFrontier = append(nextFrontier, relationship.EndID)
+
+					if _, isEnd := endIDs[relationship.EndID]; isEnd {
+						remaining--
+					}
+				}
+
+				predecessors[relationship.EndID] = append(predecessors[relationship.EndID], relationship)
+			}
+		}
+
+		frontier = nextFrontier
+	}
+
+	var walk func(nodeID graph.ID, edges []*graph.Relationship)
+	walk = func(nodeID graph.ID, edges []*graph.Relationship) {
+		if nodeID == start.ID {
+			path := graph.Path{
+				Nodes: []*graph.Node{copyNode(start)},
+			}
+
+			// Edges are collected walking backward from the end node
+			for idx := len(edges) - 1; idx >= 0; idx-- {
+				path.Edges = append(path.Edges, copyRelationship(edges[idx]))
+				path.Nodes = append(path.Nodes, copyNode(s.store.nodes[edges[idx].EndID]))
+			}
+
+			paths = append(paths, path)
+			return
+		}
+
+		for _, relationship := range predecessors[nodeID] {
+			walk(relationship.StartID, append(edges[:len(edges):len(edges)], relationship))
+		}
+	}
+
+	for _, endID := range endIDs.sorted() {
+		if depth, reached := depths[endID]; reached && depth > 0 {
+			walk(endID, nil)
+		}
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,323 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+)
+
+func directionToReturnCriteria(direction graph.Direction) (graph.Criteria, error) {
+	switch direction {
+	case graph.DirectionInbound:
+		// Return the relationship and the end node
+		return query.Returning(
+			query.Relationship(),
+			query.End(),
+		), nil
+
+	case graph.DirectionOutbound:
+		// Return the relationship and the start node
+		return query.Returning(
+			query.Relationship(),
+			query.Start(),
+		), nil
+
+	default:
+		return nil, fmt.Errorf("bad direction: %d", direction)
+	}
+}
+
+type relationshipQuery struct {
+	ctx  context.Context
+	tx   *transaction
+	spec *querySpec
+}
+
+func newRelationshipQuery(ctx context.Context, tx *transaction) graph.RelationshipQuery {
+	return &relationshipQuery{
+		ctx:  ctx,
+		tx:   tx,
+		spec: &querySpec{},
+	}
+}
+
+func (s *relationshipQuery) Execute(delegate func(results graph.Result) error, finalCriteria ...graph.Criteria) error {
+	for _, criteria := range finalCriteria {
+		s.spec.apply(criteria)
+	}
+
+	evaluator := s.tx.evaluator()
+
+	if err := s.spec.prepare(); err != nil {
+		return err
+	} else if candidates, err := evaluator.matchRelationships(s.spec.filter()); err != nil {
+		return err
+	} else if rows, err := evaluator.results(s.spec, candidates); err != nil {
+		return err
+	} else {
+		result := newResult(rows)
+		defer result.Close()
+
+		return delegate(result)
+	}
+}
+
+func (s *relationshipQuery) Debug() (string, map[string]any) {
+	return s.spec.debug()
+}
+
+func (s *relationshipQuery) Update(properties *graph.Properties) error {
+	if err := s.spec.prepare(); err != nil {
+		return err
+	} else if store, err := s.tx.writableStore(); err != nil {
+		return err
+	} else if candidates, err := (evaluator{store: store}).matchRelationships(s.spec.filter()); err != nil {
+		return err
+	} else {
+		for _, candidate := range candidates {
+			relationship := candidate.relationship
+
+			store.putRelationship(graph.NewRelationship(
+				relationship.ID,
+				relationship.StartID,
+				relationship.EndID,
+				updateProperties(relationship.Properties, properties),
+				relationship.Kind,
+			))
+		}
+
+		return nil
+	}
+}
+
+func (s *relationshipQuery) Delete() error {
+	if err := s.spec.prepare(); err != nil {
+		return err
+	} else if store, err := s.tx.writableStore(); err != nil {
+		return err
+	} else if candidates, err := (evaluator{store: store}).matchRelationships(s.spec.filter()); err != nil {
+		return err
+	} else {
+		for _, candidate := range candidates {
+			store.deleteRelationship(candidate.relationship.ID)
+		}
+
+		return nil
+	}
+}
+
+func (s *relationshipQuery) OrderBy(criteria ...graph.Criteria) graph.RelationshipQuery {
+	s.spec.apply(query.OrderBy(criteria...))
+	return s
+}
+
+func (s *relationshipQuery) Offset(offset int) graph.RelationshipQuery {
+	s.spec.apply(query.Offset(offset))
+	return s
+}
+
+func (s *relationshipQuery) Limit(limit int) graph.RelationshipQuery {
+	s.spec.apply(query.Limit(limit))
+	return s
+}
+
+func (s *relationshipQuery) Filter(criteria graph.Criteria) graph.RelationshipQuery {
+	s.spec.apply(query.Where(criteria))
+	return s
+}
+
+func (s *relationshipQuery) Filterf(criteriaDelegate graph.CriteriaProvider) graph.RelationshipQuery {
+	return s.Filter(criteriaDelegate())
+}
+
+func (s *relationshipQuery) Count() (int64, error) {
+	var count int64
+
+	err := s.Execute(func(results graph.Result) error {
+		if !results.Next() {
+			return graph.ErrNoResultsFound
+		}
+
+		return results.Scan(&count)
+	}, query.Returning(
+		query.Count(query.Relationship()),
+	))
+
+	return count, err
+}
+
+func (s *relationshipQuery) FetchAllShortestPaths(delegate func(cursor graph.Cursor[graph.Path]) error) error {
+	if err := s.spec.prepare(); err != nil {
+		return err
+	} else if paths, err := s.tx.evaluator().allShortestPaths(s.spec.filter()); err != nil {
+		return err
+	} else {
+		rows := make([][]any, len(paths))
+
+		for idx, path := range paths {
+			rows[idx] = []any{path}
+		}
+
+		result := newResult(rows)
+		defer result.Close()
+
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (graph.Path, error) {
+			var (
+				nextPath graph.Path
+				err      = scanner.Scan(&nextPath)
+			)
+
+			return nextPath, err
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}
+}
+
+func (s *relationshipQuery) FetchTriples(delegate func(cursor graph.Cursor[graph.RelationshipTripleResult]) error) error {
+	return s.Execute(func(result graph.Result) error {
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (graph.RelationshipTripleResult, error) {
+			var (
+				startID        graph.ID
+				relationshipID graph.ID
+				endID          graph.ID
+				err            = scanner.Scan(&startID, &relationshipID, &endID)
+			)
+
+			return graph.RelationshipTripleResult{
+				ID:      relationshipID,
+				StartID: startID,
+				EndID:   endID,
+			}, err
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}, query.Returning(
+		query.Distinct(query.StartID()),
+		query.RelationshipID(),
+		query.EndID(),
+	))
+}
+
+func (s *relationshipQuery) FetchKinds(delegate func(cursor graph.Cursor[graph.RelationshipKindsResult]) error) error {
+	return s.Execute(func(result graph.Result) error {
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (graph.RelationshipKindsResult, error) {
+			var (
+				startID          graph.ID
+				relationshipID   graph.ID
+				relationshipKind graph.Kind
+				endID            graph.ID
+				err              = scanner.Scan(&startID, &relationshipID, &relationshipKind, &endID)
+			)
+
+			return graph.RelationshipKindsResult{
+				RelationshipTripleResult: graph.RelationshipTripleResult{
+					ID:      relationshipID,
+					StartID: startID,
+					EndID:   endID,
+				},
+				Kind: relationshipKind,
+			}, err
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}, query.Returning(
+		query.StartID(),
+		query.RelationshipID(),
+		query.KindsOf(query.Relationship()),
+		query.EndID(),
+	))
+}
+
+func (s *relationshipQuery) First() (*graph.Relationship, error) {
+	var relationship graph.Relationship
+
+	err := s.Execute(func(results graph.Result) error {
+		if !results.Next() {
+			return graph.ErrNoResultsFound
+		}
+
+		return results.Scan(&relationship)
+	}, query.Returning(
+		query.Relationship(),
+	), query.Limit(1))
+
+	return &relationship, err
+}
+
+func (s *relationshipQuery) Fetch(delegate func(cursor graph.Cursor[*graph.Relationship]) error) error {
+	return s.Execute(func(result graph.Result) error {
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (*graph.Relationship, error) {
+			var relationship graph.Relationship
+			return &relationship, scanner.Scan(&relationship)
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}, query.Returning(
+		query.Relationship(),
+	))
+}
+
+func (s *relationshipQuery) FetchDirection(direction graph.Direction, delegate func(cursor graph.Cursor[graph.DirectionalResult]) error) error {
+	if returnCriteria, err := directionToReturnCriteria(direction); err != nil {
+		return err
+	} else {
+		return s.Execute(func(result graph.Result) error {
+			cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (graph.DirectionalResult, error) {
+				var (
+					relationship graph.Relationship
+					node         graph.Node
+				)
+
+				if err := scanner.Scan(&relationship, &node); err != nil {
+					return graph.DirectionalResult{}, err
+				}
+
+				return graph.DirectionalResult{
+					Direction:    direction,
+					Relationship: &relationship,
+					Node:         &node,
+				}, nil
+			})
+
+			defer cursor.Close()
+			return delegate(cursor)
+		}, returnCriteria)
+	}
+}
+
+func (s *relationshipQuery) FetchIDs(delegate func(cursor graph.Cursor[graph.ID]) error) error {
+	return s.Execute(func(result graph.Result) error {
+		cursor := graph.NewResultIterator(s.ctx, result, func(scanner graph.Scanner) (graph.ID, error) {
+			var relationshipID graph.ID
+			return relationshipID, scanner.Scan(&relationshipID)
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}, query.Returning(
+		query.RelationshipID(),
+	))
+}
@@ -0,0 +1,319 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+func asInt64(value any) (int64, error) {
+	switch typedValue := normalize(value).(type) {
+	case int64:
+		return typedValue, nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T will not negotiate to int64", value)
+	}
+}
+
+func asFloat64(value any) (float64, error) {
+	switch typedValue := normalize(value).(type) {
+	case int64:
+		return float64(typedValue), nil
+	case float64:
+		return typedValue, nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T will not negotiate to float64", value)
+	}
+}
+
+func asTime(value any) (time.Time, error) {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return typedValue, nil
+
+	case string:
+		return time.Parse(time.RFC3339Nano, typedValue)
+
+	case float64:
+		return time.Unix(int64(typedValue), 0), nil
+
+	case int64:
+		return time.Unix(typedValue, 0), nil
+
+	default:
+		return time.Time{}, fmt.Errorf("unexpected type %T will not negotiate to time.Time", value)
+	}
+}
+
+func asKinds(value any) (graph.Kinds, error) {
+	switch typedValue := value.(type) {
+	case graph.Kinds:
+		return typedValue.Copy(), nil
+
+	default:
+		if strs, isStrings := asStrings(value); !isStrings {
+			return nil, fmt.Errorf("unexpected type %T will not negotiate to graph.Kinds", value)
+		} else {
+			return graph.StringsToKinds(strs), nil
+		}
+	}
+}
+
+// mapNumeric maps numeric raw values into any numeric target type using reflection.
+func mapNumeric(target, rawValue any) error {
+	targetValue := reflect.ValueOf(target)
+
+	if targetValue.Kind() != reflect.Pointer || targetValue.IsNil() {
+		return fmt.Errorf("unsupported scan type %T", target)
+	}
+
+	element := targetValue.Elem()
+
+	switch element.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value, err := asInt64(rawValue); err != nil {
+			return err
+		} else if element.OverflowInt(value) {
+			return fmt.Errorf("value %d overflows %s", value, element.Type())
+		} else {
+			element.SetInt(value)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value, err := asInt64(rawValue); err != nil {
+			return err
+		} else if value < 0 || element.OverflowUint(uint64(value)) {
+			return fmt.Errorf("value %d overflows %s", value, element.Type())
+		} else {
+			element.SetUint(uint64(value))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if value, err := asFloat64(rawValue); err != nil {
+			return err
+		} else {
+			element.SetFloat(value)
+		}
+
+	default:
+		return fmt.Errorf("unsupported scan type %T", target)
+	}
+
+	return nil
+}
+
+func mapValue(target, rawValue any) error {
+	switch typedTarget := target.(type) {
+	case *any:
+		*typedTarget = rawValue
+
+	case *graph.ID:
+		if value, err := asInt64(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = graph.ID(value)
+		}
+
+	case *bool:
+		if value, typeOK := rawValue.(bool); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to bool", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	case *graph.Kind:
+		switch typedValue := rawValue.(type) {
+		case graph.Kind:
+			*typedTarget = typedValue
+		case string:
+			*typedTarget = graph.StringKind(typedValue)
+		default:
+			return fmt.Errorf("unexpected type %T will not negotiate to graph.Kind", rawValue)
+		}
+
+	case *string:
+		if value, typeOK := asString(rawValue); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to string", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	case *[]graph.Kind:
+		if value, err := asKinds(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = value
+		}
+
+	case *graph.Kinds:
+		if value, err := asKinds(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = value
+		}
+
+	case *[]string:
+		if value, typeOK := asStrings(rawValue); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to []string", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	case *time.Time:
+		if value, err := asTime(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = value
+		}
+
+	case *graph.Node:
+		if value, typeOK := rawValue.(*graph.Node); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to *graph.Node", rawValue)
+		} else {
+			*typedTarget = *value
+		}
+
+	case *graph.Relationship:
+		if value, typeOK := rawValue.(*graph.Relationship); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to *graph.Relationship", rawValue)
+		} else {
+			*typedTarget = *value
+		}
+
+	case *graph.Path:
+		if value, typeOK := rawValue.(graph.Path); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to graph.Path", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	default:
+		return mapNumeric(target, rawValue)
+	}
+
+	return nil
+}
+
+type ValueMapper struct {
+	values []any
+	idx    int
+}
+
+func NewValueMapper(values []any) *ValueMapper {
+	return &ValueMapper{
+		values: values,
+		idx:    0,
+	}
+}
+
+func (s *ValueMapper) Next() (any, error) {
+	if s.idx >= len(s.values) {
+		return nil, fmt.Errorf("attempting to get more values than returned - saw %d but wanted %d", len(s.values), s.idx+1)
+	}
+
+	nextValue := s.values[s.idx]
+	s.idx++
+
+	return nextValue, nil
+}
+
+func (s *ValueMapper) Map(target any) error {
+	if rawValue, err := s.Next(); err != nil {
+		return err
+	} else {
+		return mapValue(target, rawValue)
+	}
+}
+
+func (s *ValueMapper) MapOptions(targets ...any) (any, error) {
+	if rawValue, err := s.Next(); err != nil {
+		return nil, err
+	} else {
+		for _, target := range targets {
+			if mapValue(target, rawValue) == nil {
+				return target, nil
+			}
+		}
+
+		return nil, fmt.Errorf("no matching target given for type: %T", rawValue)
+	}
+}
+
+func (s *ValueMapper) Scan(targets ...any) error {
+	for _, target := range targets {
+		if err := s.Map(target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// result is a fully materialized graph.Result. All rows are computed when the query is executed so that the result
+// remains valid regardless of what the transaction does afterward.
+type result struct {
+	rows [][]any
+	idx  int
+	err  error
+}
+
+func newResult(rows [][]any) graph.Result {
+	return &result{
+		rows: rows,
+		idx:  -1,
+	}
+}
+
+func newErrorResult(err error) graph.Result {
+	return &result{
+		idx: -1,
+		err: err,
+	}
+}
+
+func (s *result) Next() bool {
+	if s.err != nil || s.idx+1 >= len(s.rows) {
+		return false
+	}
+
+	s.idx++
+	return true
+}
+
+func (s *result) Values() graph.ValueMapper {
+	if s.idx < 0 || s.idx >= len(s.rows) {
+		return NewValueMapper(nil)
+	}
+
+	return NewValueMapper(s.rows[s.idx])
+}
+
+func (s *result) Scan(targets ...any) error {
+	return s.Values().Scan(targets...)
+}
+
+func (s *result) Error() error {
+	return s.err
+}
+
+func (s *result) Close() {
+	// Results are fully materialized and hold no resources that require release
+}
@@ -0,0 +1,296 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+type idSet map[graph.ID]struct{}
+
+func (s idSet) copy() idSet {
+	copied := make(idSet, len(s)+1)
+
+	for id := range s {
+		copied[id] = struct{}{}
+	}
+
+	return copied
+}
+
+func (s idSet) sorted() []graph.ID {
+	ids := make([]graph.ID, 0, len(s))
+
+	for id := range s {
+		ids = append(ids, id)
+	}
+
+	sortIDs(ids)
+	return ids
+}
+
+func sortIDs(ids []graph.ID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+}
+
+func copyPropertyMap(properties *graph.Properties) map[string]any {
+	copied := map[string]any{}
+
+	if properties != nil {
+		for key, value := range properties.Map {
+			copied[key] = value
+		}
+	}
+
+	return copied
+}
+
+// copyNode returns a detached copy of a stored node. Entities held by a store are never handed to callers directly
+// so that mutations made by the caller can not leak into committed state.
+func copyNode(node *graph.Node) *graph.Node {
+	return graph.NewNode(node.ID, graph.AsProperties(copyPropertyMap(node.Properties)), node.Kinds.Copy()...)
+}
+
+// copyRelationship returns a detached copy of a stored relationship.
+func copyRelationship(relationship *graph.Relationship) *graph.Relationship {
+	return graph.NewRelationship(
+		relationship.ID,
+		relationship.StartID,
+		relationship.EndID,
+		graph.AsProperties(copyPropertyMap(relationship.Properties)),
+		relationship.Kind,
+	)
+}
+
+// store is a complete, point-in-time copy of the graph. Stored entities are treated as immutable: every mutation
+// replaces the affected entity in the maps of the store rather than modifying it in place. This allows a store to be
+// cloned cheaply for each write transaction while committed stores remain safe to read without holding a lock.
+type store struct {
+	nodes              map[graph.ID]*graph.Node
+	relationships      map[graph.ID]*graph.Relationship
+	outbound           map[graph.ID]idSet
+	inbound            map[graph.ID]idSet
+	nextNodeID         graph.ID
+	nextRelationshipID graph.ID
+}
+
+func newStore() *store {
+	return &store{
+		nodes:         map[graph.ID]*graph.Node{},
+		relationships: map[graph.ID]*graph.Relationship{},
+		outbound:      map[graph.ID]idSet{},
+		inbound:       map[graph.ID]idSet{},
+	}
+}
+
+func (s *store) clone() *store {
+	cloned := &store{
+		nodes:              make(map[graph.ID]*graph.Node, len(s.nodes)),
+		relationships:      make(map[graph.ID]*graph.Relationship, len(s.relationships)),
+		outbound:           make(map[graph.ID]idSet, len(s.outbound)),
+		inbound:            make(map[graph.ID]idSet, len(s.inbound)),
+		nextNodeID:         s.nextNodeID,
+		nextRelationshipID: s.nextRelationshipID,
+	}
+
+	for id, node := range s.nodes {
+		cloned.nodes[id] = node
+	}
+
+	for id, relationship := range s.relationships {
+		cloned.relationships[id] = relationship
+	}
+
+	// Adjacency sets are shared with the source store and are copied before modification
+	for id, relationships := range s.outbound {
+		cloned.outbound[id] = relationships
+	}
+
+	for id, relationships := range s.inbound {
+		cloned.inbound[id] = relationships
+	}
+
+	return cloned
+}
+
+func (s *store) nodeIDs() []graph.ID {
+	ids := make([]graph.ID, 0, len(s.nodes))
+
+	for id := range s.nodes {
+		ids = append(ids, id)
+	}
+
+	sortIDs(ids)
+	return ids
+}
+
+func (s *store) relationshipIDs() []graph.ID {
+	ids := make([]graph.ID, 0, len(s.relationships))
+
+	for id := range s.relationships {
+		ids = append(ids, id)
+	}
+
+	sortIDs(ids)
+	return ids
+}
+
+// adjacent returns the IDs of all relationships attached to the given node in the given direction. Outbound
+// relationships start at the node while inbound relationships end at the node.
+func (s *store) adjacent(nodeID graph.ID, direction graph.Direction) []graph.ID {
+	switch direction {
+	case graph.DirectionOutbound:
+		return s.outbound[nodeID].sorted()
+
+	case graph.DirectionInbound:
+		return s.inbound[nodeID].sorted()
+
+	default:
+		ids := s.outbound[nodeID].copy()
+
+		for id := range s.inbound[nodeID] {
+			ids[id] = struct{}{}
+		}
+
+		return ids.sorted()
+	}
+}
+
+func (s *store) degree(nodeID graph.ID) int {
+	return len(s.outbound[nodeID]) + len(s.inbound[nodeID])
+}
+
+func (s *store) createNode(properties *graph.Properties, kinds graph.Kinds) *graph.Node {
+	node := graph.NewNode(s.nextNodeID, graph.AsProperties(copyPropertyMap(properties)), kinds.Copy()...)
+
+	s.nextNodeID++
+	s.nodes[node.ID] = node
+
+	return node
+}
+
+func (s *store) putNode(node *graph.Node) {
+	s.nodes[node.ID] = node
+}
+
+func (s *store) deleteNode(id graph.ID) {
+	// Node deletion always detaches the node from the graph first
+	for _, relationshipID := range s.adjacent(id, graph.DirectionBoth) {
+		s.deleteRelationship(relationshipID)
+	}
+
+	delete(s.nodes, id)
+	delete(s.outbound, id)
+	delete(s.inbound, id)
+}
+
+func (s *store) createRelationship(startID, endID graph.ID, kind graph.Kind, properties *graph.Properties) (*graph.Relationship, error) {
+	if _, found := s.nodes[startID]; !found {
+		return nil, fmt.Errorf("start node %d: %w", startID, graph.ErrNoResultsFound)
+	} else if _, found := s.nodes[endID]; !found {
+		return nil, fmt.Errorf("end node %d: %w", endID, graph.ErrNoResultsFound)
+	}
+
+	relationship := graph.NewRelationship(s.nextRelationshipID, startID, endID, graph.AsProperties(copyPropertyMap(properties)), kind)
+
+	s.nextRelationshipID++
+	s.relationships[relationship.ID] = relationship
+
+	s.outbound[startID] = s.outbound[startID].copy()
+	s.outbound[startID][relationship.ID] = struct{}{}
+
+	s.inbound[endID] = s.inbound[endID].copy()
+	s.inbound[endID][relationship.ID] = struct{}{}
+
+	return relationship, nil
+}
+
+func (s *store) putRelationship(relationship *graph.Relationship) {
+	s.relationships[relationship.ID] = relationship
+}
+
+func (s *store) deleteRelationship(id graph.ID) {
+	if relationship, found := s.relationships[id]; found {
+		if outbound := s.outbound[relationship.StartID].copy(); len(outbound) > 1 {
+			delete(outbound, id)
+			s.outbound[relationship.StartID] = outbound
+		} else {
+			delete(s.outbound, relationship.StartID)
+		}
+
+		if inbound := s.inbound[relationship.EndID].copy(); len(inbound) > 1 {
+			delete(inbound, id)
+			s.inbound[relationship.EndID] = inbound
+		} else {
+			delete(s.inbound, relationship.EndID)
+		}
+
+		delete(s.relationships, id)
+	}
+}
+
+// findNode returns the node with the lowest ID that carries the given kind and matches all given property values.
+func (s *store) findNode(kind graph.Kind, properties map[string]any) *graph.Node {
+	var found *graph.Node
+
+	for _, node := range s.nodes {
+		if found != nil && found.ID < node.ID {
+			continue
+		}
+
+		if kind != nil && !node.Kinds.ContainsOneOf(kind) {
+			continue
+		}
+
+		if propertiesMatch(node.Properties, properties) {
+			found = node
+		}
+	}
+
+	return found
+}
+
+// findRelationship returns the first relationship, by ID, between the given nodes that is of the given kind and
+// matches all given property values.
+func (s *store) findRelationship(startID, endID graph.ID, kind graph.Kind, properties map[string]any) *graph.Relationship {
+	for _, id := range s.outbound[startID].sorted() {
+		relationship := s.relationships[id]
+
+		if relationship.EndID == endID && relationship.Kind.Is(kind) && propertiesMatch(relationship.Properties, properties) {
+			return relationship
+		}
+	}
+
+	return nil
+}
+
+func propertiesMatch(properties *graph.Properties, expected map[string]any) bool {
+	for key, value := range expected {
+		if properties == nil || !properties.Exists(key) {
+			return false
+		} else if !equalValues(properties.Get(key).Any(), value) {
+			return false
+		}
+	}
+
+	return true
+}
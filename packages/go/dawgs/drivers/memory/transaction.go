@@ -0,0 +1,348 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+const (
+	// lastSeenProperty is stamped on the start and end nodes of relationships merged by UpdateRelationshipBy to match
+	// the behavior of the Neo4j driver.
+	lastSeenProperty = "lastseen"
+)
+
+var (
+	ErrReadOnlyTransaction = errors.New("write operation attempted in a read transaction")
+)
+
+// updateProperties returns a new property set containing the existing properties with the modifications and deletions
+// tracked by the given properties applied.
+func updateProperties(existing, properties *graph.Properties) *graph.Properties {
+	updated := copyPropertyMap(existing)
+
+	if properties != nil {
+		for key, value := range properties.ModifiedProperties() {
+			updated[key] = value
+		}
+
+		for _, key := range properties.DeletedProperties() {
+			delete(updated, key)
+		}
+	}
+
+	return graph.AsProperties(updated)
+}
+
+// mergeProperties returns a new property set with the given values merged into the existing properties. As with the
+// Cypher += operator, a nil value removes the property.
+func mergeProperties(existing *graph.Properties, values map[string]any) *graph.Properties {
+	merged := copyPropertyMap(existing)
+
+	for key, value := range values {
+		if value == nil {
+			delete(merged, key)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	return graph.AsProperties(merged)
+}
+
+func identityProperties(properties *graph.Properties, keys []string) map[string]any {
+	identity := make(map[string]any, len(keys))
+
+	for _, key := range keys {
+		identity[key] = properties.Get(key).Any()
+	}
+
+	return identity
+}
+
+// mergeNode finds the node identified by the given kind and identity properties and updates it with the given
+// properties and kinds. If no such node exists it is created.
+func mergeNode(store *store, identityKind graph.Kind, identity, properties map[string]any, kinds graph.Kinds) *graph.Node {
+	if existing := store.findNode(identityKind, identity); existing != nil {
+		node := graph.NewNode(existing.ID, mergeProperties(existing.Properties, properties), existing.Kinds.Copy().Add(kinds...)...)
+		store.putNode(node)
+
+		return node
+	} else {
+		var nodeKinds graph.Kinds
+
+		if identityKind != nil {
+			nodeKinds = nodeKinds.Add(identityKind)
+		}
+
+		return store.createNode(mergeProperties(graph.AsProperties(identity), properties), nodeKinds.Add(kinds...))
+	}
+}
+
+// mergeRelationship finds the relationship of the given kind between the given nodes that matches the given identity
+// properties and updates it with the given properties. If no such relationship exists it is created.
+func mergeRelationship(store *store, startID, endID graph.ID, kind graph.Kind, identity, properties map[string]any) (*graph.Relationship, error) {
+	if existing := store.findRelationship(startID, endID, kind, identity); existing != nil {
+		relationship := graph.NewRelationship(existing.ID, existing.StartID, existing.EndID, mergeProperties(existing.Properties, properties), existing.Kind)
+		store.putRelationship(relationship)
+
+		return relationship, nil
+	}
+
+	return store.createRelationship(startID, endID, kind, mergeProperties(graph.AsProperties(identity), properties))
+}
+
+// transaction reads from the snapshot of the store that was committed when the transaction began. The first write
+// clones the snapshot into a working store that is published to the driver on commit. Work that is not committed is
+// discarded.
+type transaction struct {
+	ctx      context.Context
+	db       *driver
+	writable bool
+	snapshot *store
+	working  *store
+}
+
+func newTransaction(ctx context.Context, db *driver, writable bool) *transaction {
+	return &transaction{
+		ctx:      ctx,
+		db:       db,
+		writable: writable,
+		snapshot: db.current(),
+	}
+}
+
+func (s *transaction) current() *store {
+	if s.working != nil {
+		return s.working
+	}
+
+	return s.snapshot
+}
+
+func (s *transaction) evaluator() evaluator {
+	return evaluator{
+		store: s.current(),
+	}
+}
+
+func (s *transaction) writableStore() (*store, error) {
+	if !s.writable {
+		return nil, ErrReadOnlyTransaction
+	}
+
+	if s.working == nil {
+		s.working = s.snapshot.clone()
+	}
+
+	return s.working, nil
+}
+
+func (s *transaction) Nodes() graph.NodeQuery {
+	return newNodeQuery(s.ctx, s)
+}
+
+func (s *transaction) Relationships() graph.RelationshipQuery {
+	return newRelationshipQuery(s.ctx, s)
+}
+
+func (s *transaction) CreateNode(properties *graph.Properties, kinds ...graph.Kind) (*graph.Node, error) {
+	if store, err := s.writableStore(); err != nil {
+		return nil, err
+	} else {
+		return copyNode(store.createNode(properties, kinds)), nil
+	}
+}
+
+func (s *transaction) UpdateNode(node *graph.Node) error {
+	if store, err := s.writableStore(); err != nil {
+		return err
+	} else if existing, found := store.nodes[node.ID]; found {
+		kinds := existing.Kinds.Copy().Add(node.AddedKinds...).Exclude(node.DeletedKinds)
+		store.putNode(graph.NewNode(existing.ID, updateProperties(existing.Properties, node.Properties), kinds...))
+	}
+
+	return nil
+}
+
+func (s *transaction) updateNodeBy(update graph.NodeUpdate) error {
+	if store, err := s.writableStore(); err != nil {
+		return err
+	} else {
+		mergeNode(store, update.IdentityKind, identityProperties(update.Node.Properties, update.IdentityProperties), update.Node.Properties.Map, update.Node.Kinds)
+		return nil
+	}
+}
+
+func (s *transaction) UpdateNodeBy(update graph.NodeUpdate) error {
+	return s.updateNodeBy(update)
+}
+
+func (s *transaction) CreateRelationship(startNode, endNode *graph.Node, kind graph.Kind, properties *graph.Properties) (*graph.Relationship, error) {
+	return s.CreateRelationshipByIDs(startNode.ID, endNode.ID, kind, properties)
+}
+
+func (s *transaction) CreateRelationshipByIDs(startNodeID, endNodeID graph.ID, kind graph.Kind, properties *graph.Properties) (*graph.Relationship, error) {
+	if store, err := s.writableStore(); err != nil {
+		return nil, err
+	} else if relationship, err := store.createRelationship(startNodeID, endNodeID, kind, properties); err != nil {
+		return nil, err
+	} else {
+		return copyRelationship(relationship), nil
+	}
+}
+
+func (s *transaction) UpdateRelationship(relationship *graph.Relationship) error {
+	if store, err := s.writableStore(); err != nil {
+		return err
+	} else if existing, found := store.relationships[relationship.ID]; found {
+		store.putRelationship(graph.NewRelationship(
+			existing.ID,
+			existing.StartID,
+			existing.EndID,
+			updateProperties(existing.Properties, relationship.Properties),
+			existing.Kind,
+		))
+	}
+
+	return nil
+}
+
+func (s *transaction) updateRelationshipBy(update graph.RelationshipUpdate) error {
+	if store, err := s.writableStore(); err != nil {
+		return err
+	} else {
+		var (
+			lastSeen = map[string]any{
+				lastSeenProperty: time.Now().UTC(),
+			}
+
+			start = mergeNode(store, update.StartIdentityKind, update.StartIdentityPropertiesMap(), lastSeen, update.Start.Kinds)
+			end   = mergeNode(store, update.EndIdentityKind, update.EndIdentityPropertiesMap(), lastSeen, update.End.Kinds)
+		)
+
+		_, err := mergeRelationship(store, start.ID, end.ID, update.Relationship.Kind, update.IdentityPropertiesMap(), update.Relationship.Properties.Map)
+		return err
+	}
+}
+
+func (s *transaction) UpdateRelationshipBy(update graph.RelationshipUpdate) error {
+	return s.updateRelationshipBy(update)
+}
+
+func (s *transaction) Run(query string, parameters map[string]any) graph.Result {
+	// Raw statements can not be interpreted without a query engine
+	return newErrorResult(graph.NewError(query, graph.ErrUnsupportedDatabaseOperation))
+}
+
+func (s *transaction) Commit() error {
+	if s.working != nil {
+		s.db.publish(s.working)
+
+		s.snapshot = s.working
+		s.working = nil
+	}
+
+	return nil
+}
+
+type batchTransaction struct {
+	innerTx *transaction
+}
+
+func newBatchOperation(ctx context.Context, db *driver) *batchTransaction {
+	return &batchTransaction{
+		innerTx: newTransaction(ctx, db, true),
+	}
+}
+
+func (s *batchTransaction) Nodes() graph.NodeQuery {
+	return s.innerTx.Nodes()
+}
+
+func (s *batchTransaction) Relationships() graph.RelationshipQuery {
+	return s.innerTx.Relationships()
+}
+
+func (s *batchTransaction) CreateNode(properties *graph.Properties, kinds ...graph.Kind) error {
+	_, err := s.innerTx.CreateNode(properties, kinds...)
+	return err
+}
+
+func (s *batchTransaction) DeleteNode(id graph.ID) error {
+	if store, err := s.innerTx.writableStore(); err != nil {
+		return err
+	} else {
+		store.deleteNode(id)
+		return nil
+	}
+}
+
+func (s *batchTransaction) UpdateNodeBy(update graph.NodeUpdate) error {
+	return s.innerTx.updateNodeBy(update)
+}
+
+func (s *batchTransaction) CreateRelationship(startNode, endNode *graph.Node, kind graph.Kind, properties *graph.Properties) error {
+	if startNode.ID == graph.UnregisteredNodeID {
+		if newStartNode, err := s.innerTx.CreateNode(startNode.Properties, startNode.Kinds...); err != nil {
+			return err
+		} else {
+			startNode = newStartNode
+		}
+	}
+
+	if endNode.ID == graph.UnregisteredNodeID {
+		if newEndNode, err := s.innerTx.CreateNode(endNode.Properties, endNode.Kinds...); err != nil {
+			return err
+		} else {
+			endNode = newEndNode
+		}
+	}
+
+	return s.CreateRelationshipByIDs(startNode.ID, endNode.ID, kind, properties)
+}
+
+func (s *batchTransaction) CreateRelationshipByIDs(startNodeID, endNodeID graph.ID, kind graph.Kind, properties *graph.Properties) error {
+	// Batch relationship creation merges on the relationship kind between the two nodes, as the Neo4j driver does
+	if store, err := s.innerTx.writableStore(); err != nil {
+		return err
+	} else {
+		_, err := mergeRelationship(store, startNodeID, endNodeID, kind, nil, copyPropertyMap(properties))
+		return err
+	}
+}
+
+func (s *batchTransaction) DeleteRelationship(id graph.ID) error {
+	if store, err := s.innerTx.writableStore(); err != nil {
+		return err
+	} else {
+		store.deleteRelationship(id)
+		return nil
+	}
+}
+
+func (s *batchTransaction) UpdateRelationshipBy(update graph.RelationshipUpdate) error {
+	return s.innerTx.updateRelationshipBy(update)
+}
+
+func (s *batchTransaction) Commit() error {
+	return s.innerTx.Commit()
+}
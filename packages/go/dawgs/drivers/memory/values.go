@@ -0,0 +1,238 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+// normalize converts the given value into a canonical type for comparison: all integer types, including graph.ID,
+// become int64, all floating point types become float64 and kinds become their string representation.
+func normalize(value any) any {
+	switch typedValue := value.(type) {
+	case graph.ID:
+		return int64(typedValue)
+	case int:
+		return int64(typedValue)
+	case int8:
+		return int64(typedValue)
+	case int16:
+		return int64(typedValue)
+	case int32:
+		return int64(typedValue)
+	case uint:
+		return int64(typedValue)
+	case uint8:
+		return int64(typedValue)
+	case uint16:
+		return int64(typedValue)
+	case uint32:
+		return int64(typedValue)
+	case uint64:
+		if typedValue > math.MaxInt64 {
+			return float64(typedValue)
+		}
+
+		return int64(typedValue)
+	case float32:
+		return float64(typedValue)
+	case graph.Kind:
+		return typedValue.String()
+	default:
+		return value
+	}
+}
+
+func parseTime(value string) (time.Time, bool) {
+	if parsedTime, err := time.Parse(time.RFC3339Nano, value); err != nil {
+		return time.Time{}, false
+	} else {
+		return parsedTime, true
+	}
+}
+
+func isList(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// equalValues compares two values for equality after normalizing them. Lists are equal when they contain equal values
+// in the same order.
+func equalValues(left, right any) bool {
+	var (
+		normalizedLeft  = normalize(left)
+		normalizedRight = normalize(right)
+	)
+
+	if ordering, comparable := compareValues(normalizedLeft, normalizedRight); comparable {
+		return ordering == 0
+	}
+
+	var (
+		leftValue  = reflect.ValueOf(normalizedLeft)
+		rightValue = reflect.ValueOf(normalizedRight)
+	)
+
+	if isList(leftValue) && isList(rightValue) {
+		if leftValue.Len() != rightValue.Len() {
+			return false
+		}
+
+		for idx := 0; idx < leftValue.Len(); idx++ {
+			if !equalValues(leftValue.Index(idx).Interface(), rightValue.Index(idx).Interface()) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return reflect.DeepEqual(normalizedLeft, normalizedRight)
+}
+
+func compareOrdered[T int64 | float64 | string](left, right T) int {
+	if left < right {
+		return -1
+	} else if left > right {
+		return 1
+	}
+
+	return 0
+}
+
+// compareValues returns the ordering of two values along with a boolean that is false when the two values can not be
+// ordered against each other.
+func compareValues(left, right any) (int, bool) {
+	var (
+		normalizedLeft  = normalize(left)
+		normalizedRight = normalize(right)
+	)
+
+	switch typedLeft := normalizedLeft.(type) {
+	case int64:
+		switch typedRight := normalizedRight.(type) {
+		case int64:
+			return compareOrdered(typedLeft, typedRight), true
+		case float64:
+			return compareOrdered(float64(typedLeft), typedRight), true
+		}
+
+	case float64:
+		switch typedRight := normalizedRight.(type) {
+		case int64:
+			return compareOrdered(typedLeft, float64(typedRight)), true
+		case float64:
+			return compareOrdered(typedLeft, typedRight), true
+		}
+
+	case string:
+		switch typedRight := normalizedRight.(type) {
+		case string:
+			return compareOrdered(typedLeft, typedRight), true
+		case time.Time:
+			if leftTime, isTime := parseTime(typedLeft); isTime {
+				return leftTime.Compare(typedRight), true
+			}
+		}
+
+	case time.Time:
+		switch typedRight := normalizedRight.(type) {
+		case time.Time:
+			return typedLeft.Compare(typedRight), true
+		case string:
+			if rightTime, isTime := parseTime(typedRight); isTime {
+				return typedLeft.Compare(rightTime), true
+			}
+		}
+
+	case bool:
+		if typedRight, typeOK := normalizedRight.(bool); typeOK {
+			if typedLeft == typedRight {
+				return 0, true
+			} else if typedRight {
+				return -1, true
+			}
+
+			return 1, true
+		}
+	}
+
+	return 0, false
+}
+
+// listValues returns the elements of the given value if it is a list.
+func listValues(value any) ([]any, bool) {
+	reflectedValue := reflect.ValueOf(value)
+
+	if !isList(reflectedValue) {
+		return nil, false
+	}
+
+	values := make([]any, reflectedValue.Len())
+
+	for idx := 0; idx < reflectedValue.Len(); idx++ {
+		values[idx] = reflectedValue.Index(idx).Interface()
+	}
+
+	return values, true
+}
+
+func asString(value any) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case graph.Kind:
+		return typedValue.String(), true
+	default:
+		return "", false
+	}
+}
+
+func asStrings(value any) ([]string, bool) {
+	switch typedValue := value.(type) {
+	case []string:
+		return typedValue, true
+
+	case graph.Kinds:
+		return typedValue.Strings(), true
+
+	default:
+		if values, isList := listValues(value); !isList {
+			return nil, false
+		} else {
+			strs := make([]string, len(values))
+
+			for idx, value := range values {
+				if str, isString := asString(value); !isString {
+					return nil, false
+				} else {
+					strs[idx] = str
+				}
+			}
+
+			return strs, true
+		}
+	}
+}
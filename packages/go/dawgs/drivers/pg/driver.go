@@ -0,0 +1,164 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+const (
+	DriverName = "pg"
+
+	DefaultBatchWriteSize = 20_000
+	DefaultWriteFlushSize = DefaultBatchWriteSize * 5
+
+	defaultTransactionTimeout = time.Minute * 15
+)
+
+func readOptions() pgx.TxOptions {
+	return pgx.TxOptions{
+		AccessMode: pgx.ReadOnly,
+	}
+}
+
+func writeOptions() pgx.TxOptions {
+	return pgx.TxOptions{
+		AccessMode: pgx.ReadWrite,
+	}
+}
+
+// driver is a graph.Database that stores the graph in the node and edge tables of a PostgreSQL database. Writes made
+// by batch operations are committed every time their count reaches the batch write size while writes made by write
+// transactions are committed every time their count reaches the write flush size.
+type driver struct {
+	pool                      *pgxpool.Pool
+	defaultTransactionTimeout time.Duration
+	batchWriteSize            int
+	writeFlushSize            int
+}
+
+// NewDatabase returns a graph.Database backed by the given connection pool. The graph tables are created if they do
+// not exist.
+func NewDatabase(ctx context.Context, pool *pgxpool.Pool) (graph.Database, error) {
+	if err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		return assertGraphSchema(ctx, tx)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &driver{
+		pool:                      pool,
+		defaultTransactionTimeout: defaultTransactionTimeout,
+		batchWriteSize:            DefaultBatchWriteSize,
+		writeFlushSize:            DefaultWriteFlushSize,
+	}, nil
+}
+
+func (s *driver) SetBatchWriteSize(size int) {
+	s.batchWriteSize = size
+}
+
+func (s *driver) SetWriteFlushSize(size int) {
+	s.writeFlushSize = size
+}
+
+func (s *driver) transaction(ctx context.Context, txOptions pgx.TxOptions, flushSize int, delegate func(tx *transaction) error, options []graph.TransactionOption) error {
+	cfg := graph.TransactionConfig{
+		Timeout: s.defaultTransactionTimeout,
+	}
+
+	// Apply the transaction options
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	if conn, err := s.pool.Acquire(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return graph.ErrContextTimedOut
+		}
+
+		return err
+	} else {
+		defer conn.Release()
+
+		tx := newTransaction(ctx, conn, txOptions, flushSize)
+		defer tx.Close()
+
+		if err := delegate(tx); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}
+}
+
+func (s *driver) BatchOperation(ctx context.Context, batchDelegate graph.BatchDelegate) error {
+	return s.transaction(ctx, writeOptions(), s.batchWriteSize, func(tx *transaction) error {
+		return batchDelegate(newBatchOperation(tx))
+	}, nil)
+}
+
+func (s *driver) ReadTransaction(ctx context.Context, txDelegate graph.TransactionDelegate, options ...graph.TransactionOption) error {
+	return s.transaction(ctx, readOptions(), 0, func(tx *transaction) error {
+		return txDelegate(tx)
+	}, options)
+}
+
+func (s *driver) WriteTransaction(ctx context.Context, txDelegate graph.TransactionDelegate, options ...graph.TransactionOption) error {
+	return s.transaction(ctx, writeOptions(), s.writeFlushSize, func(tx *transaction) error {
+		return txDelegate(tx)
+	}, options)
+}
+
+func (s *driver) FetchSchema(ctx context.Context) (*graph.Schema, error) {
+	var schema *graph.Schema
+
+	return schema, pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		fetchedSchema, err := fetchSchema(ctx, tx)
+		schema = fetchedSchema
+
+		return err
+	})
+}
+
+func (s *driver) AssertSchema(ctx context.Context, schema *graph.Schema) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		return assertSchema(ctx, tx, schema)
+	})
+}
+
+func (s *driver) Run(ctx context.Context, query string, parameters map[string]any) error {
+	return graph.NewError(query, graph.ErrUnsupportedDatabaseOperation)
+}
+
+func (s *driver) Close() error {
+	s.pool.Close()
+	return nil
+}
@@ -0,0 +1,533 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/specterops/bloodhound/cypher/model"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+)
+
+const (
+	// shortestPathMaxDepth bounds the depth of the breadth-first expansion that computes shortest paths. Cycles in the
+	// graph would otherwise keep the recursive query from terminating.
+	shortestPathMaxDepth = 15
+)
+
+var (
+	ErrNotPGTransaction = errors.New("transaction is not a PostgreSQL graph transaction")
+)
+
+// terms flattens the top level conjunctions of the given expression into a list of terms that must all hold.
+func terms(expression graph.Criteria) []graph.Criteria {
+	switch typedExpression := expression.(type) {
+	case nil:
+		return nil
+
+	case *model.Where:
+		var flattened []graph.Criteria
+
+		for _, subExpression := range typedExpression.Expressions {
+			flattened = append(flattened, terms(subExpression)...)
+		}
+
+		return flattened
+
+	case *model.Conjunction:
+		var flattened []graph.Criteria
+
+		for _, subExpression := range typedExpression.Expressions {
+			flattened = append(flattened, terms(subExpression)...)
+		}
+
+		return flattened
+
+	case *model.Parenthetical:
+		switch typedExpression.Expression.(type) {
+		case *model.Conjunction, *model.Parenthetical:
+			return terms(typedExpression.Expression)
+		}
+	}
+
+	return []graph.Criteria{expression}
+}
+
+// hopJoins renders the joins against the node table required by criteria that reference the start or end node of a
+// single hop.
+func hopJoins(criteria ...graph.Criteria) (string, error) {
+	if symbols, err := referencedSymbols(criteria...); err != nil {
+		return "", err
+	} else {
+		builder := strings.Builder{}
+
+		if _, hasStart := symbols[query.RelationshipStartSymbol]; hasStart {
+			builder.WriteString(" join node s on s.id = r.start_id")
+		}
+
+		if _, hasEnd := symbols[query.RelationshipEndSymbol]; hasEnd {
+			builder.WriteString(" join node e on e.id = r.end_id")
+		}
+
+		return builder.String(), nil
+	}
+}
+
+// shortestPathCriteria splits the criteria of an all shortest paths query into criteria that select start nodes,
+// criteria that select end nodes and the criteria that must hold for every relationship along a path.
+func shortestPathCriteria(criteria graph.Criteria) (graph.Criteria, graph.Criteria, graph.Criteria, error) {
+	var (
+		startTerms        []graph.Criteria
+		endTerms          []graph.Criteria
+		relationshipTerms []graph.Criteria
+	)
+
+	for _, term := range terms(criteria) {
+		if symbols, err := referencedSymbols(term); err != nil {
+			return nil, nil, nil, err
+		} else {
+			_, hasStart := symbols[query.RelationshipStartSymbol]
+			_, hasEnd := symbols[query.RelationshipEndSymbol]
+			_, hasRelationship := symbols[query.RelationshipSymbol]
+
+			switch {
+			case hasStart && hasEnd:
+				return nil, nil, nil, fmt.Errorf("shortest path criteria may not reference both the start and end nodes in a single term")
+			case hasStart && !hasRelationship:
+				startTerms = append(startTerms, term)
+			case hasEnd && !hasRelationship:
+				endTerms = append(endTerms, term)
+			default:
+				relationshipTerms = append(relationshipTerms, term)
+			}
+		}
+	}
+
+	var startCriteria, endCriteria, relationshipCriteria graph.Criteria
+
+	if len(startTerms) > 0 {
+		startCriteria = query.And(startTerms...)
+	}
+
+	if len(endTerms) > 0 {
+		endCriteria = query.And(endTerms...)
+	}
+
+	if len(relationshipTerms) > 0 {
+		relationshipCriteria = query.And(relationshipTerms...)
+	}
+
+	return startCriteria, endCriteria, relationshipCriteria, nil
+}
+
+// shortestPathStatement renders a query that returns the edges of the shortest path DAG of every start node. The
+// recursive reachable expression expands outward from each start node one depth at a time and the distances
+// expression keeps the shallowest depth at which each node was reached. An edge is part of the shortest path DAG of a
+// start node when it leads from a node at one depth to a node at the next depth. Each edge row carries the ID of its
+// start node and whether its end node satisfies the end node criteria.
+func shortestPathStatement(startCriteria, endCriteria, relationshipCriteria graph.Criteria) (statement, error) {
+	translator := &translator{}
+
+	if startPredicate, err := translator.predicate(startCriteria); err != nil {
+		return statement{}, err
+	} else if relationshipPredicate, err := translator.predicate(relationshipCriteria); err != nil {
+		return statement{}, err
+	} else if endPredicate, err := translator.predicate(endCriteria); err != nil {
+		return statement{}, err
+	} else if expansionJoins, err := hopJoins(relationshipCriteria); err != nil {
+		return statement{}, err
+	} else if edgeJoins, err := hopJoins(relationshipCriteria, endCriteria); err != nil {
+		return statement{}, err
+	} else {
+		if endPredicate == "" {
+			endPredicate = "true"
+		}
+
+		sql := "with recursive reachable (root_id, node_id, depth) as (" +
+			"select s.id, s.id, 0 from node s" + whereClause(startPredicate) +
+			" union " +
+			"select t.root_id, r.end_id, t.depth + 1 from reachable t join edge r on r.start_id = t.node_id" + expansionJoins +
+			whereClause("t.depth < "+strconv.Itoa(shortestPathMaxDepth), relationshipPredicate) +
+			"), distances as (" +
+			"select root_id, node_id, min(depth) as depth from reachable group by root_id, node_id" +
+			") " +
+			"select to_jsonb(d.root_id), to_jsonb(coalesce(" + endPredicate + ", false)), r.id, r.start_id, r.end_id, r.kind, r.properties " +
+			"from distances d join edge r on r.start_id = d.node_id " +
+			"join distances reached on reached.root_id = d.root_id and reached.node_id = r.end_id and reached.depth = d.depth + 1" +
+			edgeJoins + whereClause(relationshipPredicate)
+
+		return statement{
+			sql:        sql,
+			parameters: translator.parameters,
+			columns:    []resultColumn{valueColumn, valueColumn, relationshipColumn},
+		}, nil
+	}
+}
+
+// fetchNodes loads the nodes with the given IDs.
+func (s *transaction) fetchNodes(ids []graph.ID) (map[graph.ID]*graph.Node, error) {
+	nodes := make(map[graph.ID]*graph.Node, len(ids))
+
+	if len(ids) == 0 {
+		return nodes, nil
+	}
+
+	if rows, err := s.query(statement{
+		sql:        "select id, kinds, properties from node where id = any($1::int8[])",
+		parameters: []any{idsToInt64Slice(ids)},
+		columns:    []resultColumn{nodeColumn},
+	}); err != nil {
+		return nil, err
+	} else {
+		for _, row := range rows {
+			node := row[0].(*graph.Node)
+			nodes[node.ID] = node
+		}
+
+		return nodes, nil
+	}
+}
+
+// fetchRelationships loads the edges with the given IDs.
+func (s *transaction) fetchRelationships(ids []graph.ID) (map[graph.ID]*graph.Relationship, error) {
+	relationships := make(map[graph.ID]*graph.Relationship, len(ids))
+
+	if len(ids) == 0 {
+		return relationships, nil
+	}
+
+	if rows, err := s.query(statement{
+		sql:        "select id, start_id, end_id, kind, properties from edge where id = any($1::int8[])",
+		parameters: []any{idsToInt64Slice(ids)},
+		columns:    []resultColumn{relationshipColumn},
+	}); err != nil {
+		return nil, err
+	} else {
+		for _, row := range rows {
+			relationship := row[0].(*graph.Relationship)
+			relationships[relationship.ID] = relationship
+		}
+
+		return relationships, nil
+	}
+}
+
+// allShortestPaths finds all shortest outbound paths between the start nodes and end nodes described by the given
+// criteria. Criteria that reference only the start symbol select start nodes, criteria that reference only the end
+// symbol select end nodes and all remaining criteria must hold for every relationship along a path. Paths longer than
+// the shortest path max depth are not found.
+func (s *transaction) allShortestPaths(criteria graph.Criteria) ([]graph.Path, error) {
+	type rootDAG struct {
+		predecessors map[graph.ID][]*graph.Relationship
+		ends         []graph.ID
+	}
+
+	var (
+		dags    = map[graph.ID]*rootDAG{}
+		roots   []graph.ID
+		nodeIDs = map[graph.ID]struct{}{}
+		paths   []graph.Path
+	)
+
+	if startCriteria, endCriteria, relationshipCriteria, err := shortestPathCriteria(criteria); err != nil {
+		return nil, err
+	} else if stmt, err := shortestPathStatement(startCriteria, endCriteria, relationshipCriteria); err != nil {
+		return nil, err
+	} else if rows, err := s.query(stmt); err != nil {
+		return nil, err
+	} else {
+		for _, row := range rows {
+			rawRootID, err := asInt64(row[0])
+			if err != nil {
+				return nil, err
+			}
+
+			var (
+				rootID       = graph.ID(rawRootID)
+				satisfied, _ = row[1].(bool)
+				relationship = row[2].(*graph.Relationship)
+				dag, found   = dags[rootID]
+			)
+
+			if !found {
+				dag = &rootDAG{
+					predecessors: map[graph.ID][]*graph.Relationship{},
+				}
+
+				dags[rootID] = dag
+				roots = append(roots, rootID)
+				nodeIDs[rootID] = struct{}{}
+			}
+
+			if _, visited := dag.predecessors[relationship.EndID]; !visited && satisfied {
+				dag.ends = append(dag.ends, relationship.EndID)
+			}
+
+			dag.predecessors[relationship.EndID] = append(dag.predecessors[relationship.EndID], relationship)
+			nodeIDs[relationship.EndID] = struct{}{}
+		}
+	}
+
+	fetchIDs := make([]graph.ID, 0, len(nodeIDs))
+
+	for nodeID := range nodeIDs {
+		fetchIDs = append(fetchIDs, nodeID)
+	}
+
+	nodes, err := s.fetchNodes(fetchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Results are ordered by start node and then by end node to keep the output of the query stable
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i] < roots[j]
+	})
+
+	for _, rootID := range roots {
+		dag := dags[rootID]
+
+		sort.Slice(dag.ends, func(i, j int) bool {
+			return dag.ends[i] < dag.ends[j]
+		})
+
+		var walk func(nodeID graph.ID, edges []*graph.Relationship)
+		walk = func(nodeID graph.ID, edges []*graph.Relationship) {
+			if nodeID == rootID {
+				path := graph.Path{
+					Nodes: []*graph.Node{nodes[rootID]},
+				}
+
+				// Edges are collected walking backward from the end node
+				for idx := len(edges) - 1; idx >= 0; idx-- {
+					path.Edges = append(path.Edges, edges[idx])
+					path.Nodes = append(path.Nodes, nodes[edges[idx].EndID])
+				}
+
+				paths = append(paths, path)
+				return
+			}
+
+			for _, relationship := range dag.predecessors[nodeID] {
+				walk(relationship.StartID, append(edges[:len(edges):len(edges)], relationship))
+			}
+		}
+
+		for _, endID := range dag.ends {
+			walk(endID, nil)
+		}
+	}
+
+	return paths, nil
+}
+
+// TraversalPlan describes a traversal that is executed as a single recursive query. The traversal expands from the
+// root node in the given direction along every relationship that satisfies the branch query and never visits a node
+// twice along the same path. Branch criteria may reference the relationship and the start and end nodes of each hop.
+// A MaxDepth of zero does not limit the depth of the traversal.
+type TraversalPlan struct {
+	Root        graph.ID
+	Direction   graph.Direction
+	BranchQuery graph.CriteriaProvider
+	MaxDepth    int
+	Skip        int
+	Limit       int
+}
+
+// traversalStatement renders a query that returns the edge IDs of every terminal path of the given plan. A path is
+// terminal when its last node has no further branches that satisfy the plan or when it reaches the max depth of the
+// plan. Paths are ordered by their edge IDs.
+func traversalStatement(plan TraversalPlan) (statement, error) {
+	var (
+		translator   = &translator{}
+		branchQuery  graph.Criteria
+		nextColumn   string
+		joinColumn   string
+		depthLimited = "false"
+	)
+
+	switch plan.Direction {
+	case graph.DirectionOutbound:
+		nextColumn, joinColumn = "end_id", "start_id"
+
+	case graph.DirectionInbound:
+		nextColumn, joinColumn = "start_id", "end_id"
+
+	default:
+		return statement{}, fmt.Errorf("invalid direction %d", plan.Direction)
+	}
+
+	if plan.BranchQuery != nil {
+		branchQuery = plan.BranchQuery()
+	}
+
+	if plan.MaxDepth > 0 {
+		depthLimited = "t.depth >= " + strconv.Itoa(plan.MaxDepth)
+	}
+
+	if rootParameter, err := translator.parameter(plan.Root, sqlInt8); err != nil {
+		return statement{}, err
+	} else if branchPredicate, err := translator.predicate(branchQuery); err != nil {
+		return statement{}, err
+	} else if joins, err := hopJoins(branchQuery); err != nil {
+		return statement{}, err
+	} else {
+		var (
+			builder       = strings.Builder{}
+			expansionTerm = "r." + nextColumn + " <> all(t.nodes)"
+		)
+
+		if plan.MaxDepth > 0 {
+			expansionTerm += " and t.depth < " + strconv.Itoa(plan.MaxDepth)
+		}
+
+		builder.WriteString("with recursive traversal (next_id, depth, path, nodes) as (")
+		builder.WriteString("select r." + nextColumn + ", 1, array[r.id], array[r." + joinColumn + ", r." + nextColumn + "] from edge r" + joins)
+		builder.WriteString(whereClause("r."+joinColumn+" = "+rootParameter, "r."+nextColumn+" <> r."+joinColumn, branchPredicate))
+		builder.WriteString(" union all ")
+		builder.WriteString("select r." + nextColumn + ", t.depth + 1, t.path || r.id, t.nodes || r." + nextColumn + " from traversal t join edge r on r." + joinColumn + " = t.next_id" + joins)
+		builder.WriteString(whereClause(expansionTerm, branchPredicate))
+		builder.WriteString(") select to_jsonb(t.path) from traversal t where " + depthLimited + " or not exists (")
+		builder.WriteString("select 1 from edge r" + joins)
+		builder.WriteString(whereClause("r."+joinColumn+" = t.next_id", "r."+nextColumn+" <> all(t.nodes)", branchPredicate))
+		builder.WriteString(") order by t.path")
+
+		if plan.Skip > 0 {
+			builder.WriteString(" offset ")
+			builder.WriteString(strconv.Itoa(plan.Skip))
+		}
+
+		if plan.Limit > 0 {
+			builder.WriteString(" limit ")
+			builder.WriteString(strconv.Itoa(plan.Limit))
+		}
+
+		return statement{
+			sql:        builder.String(),
+			parameters: translator.parameters,
+			columns:    []resultColumn{valueColumn},
+		}, nil
+	}
+}
+
+func (s *transaction) traverse(plan TraversalPlan) ([]graph.Path, error) {
+	var (
+		edgePaths       [][]graph.ID
+		relationshipIDs []graph.ID
+		nodeIDs         = []graph.ID{plan.Root}
+		paths           []graph.Path
+	)
+
+	if stmt, err := traversalStatement(plan); err != nil {
+		return nil, err
+	} else if rows, err := s.query(stmt); err != nil {
+		return nil, err
+	} else {
+		for _, row := range rows {
+			if rawIDs, err := asInt64Slice(row[0]); err != nil {
+				return nil, err
+			} else {
+				edgePath := make([]graph.ID, len(rawIDs))
+
+				for idx, rawID := range rawIDs {
+					edgePath[idx] = graph.ID(rawID)
+				}
+
+				edgePaths = append(edgePaths, edgePath)
+				relationshipIDs = append(relationshipIDs, edgePath...)
+			}
+		}
+	}
+
+	relationships, err := s.fetchRelationships(relationshipIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, relationship := range relationships {
+		nodeIDs = append(nodeIDs, relationship.StartID, relationship.EndID)
+	}
+
+	nodes, err := s.fetchNodes(nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edgePath := range edgePaths {
+		var (
+			path   = graph.AllocatePath(len(edgePath))
+			nextID = plan.Root
+		)
+
+		path.Nodes[0] = nodes[nextID]
+
+		for idx, relationshipID := range edgePath {
+			relationship := relationships[relationshipID]
+
+			if relationship.StartID == nextID {
+				nextID = relationship.EndID
+			} else {
+				nextID = relationship.StartID
+			}
+
+			path.Edges[idx] = relationship
+			path.Nodes[idx+1] = nodes[nextID]
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// Traverse executes the given traversal plan as a single recursive query and hands every terminal path of the
+// traversal to the given delegate. It returns the same paths as ops.TraversePaths for plans without descent or path
+// filters. The given transaction must belong to a PostgreSQL graph database.
+func Traverse(ctx context.Context, tx graph.Transaction, plan TraversalPlan, delegate func(cursor graph.Cursor[graph.Path]) error) error {
+	if pgTx, typeOK := tx.(*transaction); !typeOK {
+		return ErrNotPGTransaction
+	} else if paths, err := pgTx.traverse(plan); err != nil {
+		return err
+	} else {
+		rows := make([][]any, len(paths))
+
+		for idx, path := range paths {
+			rows[idx] = []any{path}
+		}
+
+		result := newResult(rows)
+		defer result.Close()
+
+		cursor := graph.NewResultIterator(ctx, result, func(scanner graph.Scanner) (graph.Path, error) {
+			var (
+				nextPath graph.Path
+				err      = scanner.Scan(&nextPath)
+			)
+
+			return nextPath, err
+		})
+
+		defer cursor.Close()
+		return delegate(cursor)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specterops/bloodhound/dawgs"
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+func newPGDB(connectionStr string) (graph.Database, error) {
+	if pool, err := pgxpool.New(context.Background(), connectionStr); err != nil {
+		return nil, fmt.Errorf("unable to connect to PostgreSQL: %w", err)
+	} else if db, err := NewDatabase(context.Background(), pool); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("unable to initialize the PostgreSQL graph schema: %w", err)
+	} else {
+		return db, nil
+	}
+}
+
+func init() {
+	dawgs.Register(DriverName, func(cfg any) (graph.Database, error) {
+		if connectionStr, typeOK := cfg.(string); !typeOK {
+			return nil, fmt.Errorf("expected string for configuration type but got %T", cfg)
+		} else {
+			return newPGDB(connectionStr)
+		}
+	})
+}
@@ -0,0 +1,513 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/specterops/bloodhound/cypher/model"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+)
+
+var (
+	ErrAmbiguousQueryVariables = errors.New("query mixes node and relationship query variables")
+)
+
+// statement is a rendered SQL statement along with its parameters and, for selections, the layout of its result
+// columns.
+type statement struct {
+	sql        string
+	parameters []any
+	columns    []resultColumn
+}
+
+// querySpec accumulates the criteria handed to a node or relationship query. It mirrors the clause handling of the
+// Neo4j query builder: repeated filters replace the previous filter while ordering, paging and projection criteria
+// may be supplied either directly or as part of the returning criteria.
+type querySpec struct {
+	relationships bool
+	where         *model.Where
+	order         *model.Order
+	skip          *model.Skip
+	limit         *model.Limit
+	returning     *model.Return
+	errors        []error
+}
+
+func (s *querySpec) apply(criteria graph.Criteria) {
+	switch typedCriteria := criteria.(type) {
+	case *model.Where:
+		s.where = typedCriteria
+
+	case *model.Return:
+		s.returning = typedCriteria
+
+	case *model.Limit:
+		s.limit = typedCriteria
+
+	case *model.Skip:
+		s.skip = typedCriteria
+
+	case *model.Order:
+		s.order = typedCriteria
+
+	default:
+		s.errors = append(s.errors, fmt.Errorf("invalid type for dawgs query: %T", criteria))
+	}
+}
+
+func (s *querySpec) projection() *model.Projection {
+	if s.returning == nil {
+		return nil
+	}
+
+	return s.returning.Projection
+}
+
+func (s *querySpec) sortOrder() *model.Order {
+	if s.order == nil && s.projection() != nil {
+		return s.projection().Order
+	}
+
+	return s.order
+}
+
+func (s *querySpec) skipExpression() model.Expression {
+	if s.skip != nil {
+		return s.skip.Value
+	} else if projection := s.projection(); projection != nil && projection.Skip != nil {
+		return projection.Skip.Value
+	}
+
+	return nil
+}
+
+func (s *querySpec) limitExpression() model.Expression {
+	if s.limit != nil {
+		return s.limit.Value
+	} else if projection := s.projection(); projection != nil && projection.Limit != nil {
+		return projection.Limit.Value
+	}
+
+	return nil
+}
+
+// prepare returns any errors collected while building the query, including errors recorded in the criteria model
+// itself. It also validates that node and relationship query variables are not mixed.
+func (s *querySpec) prepare() error {
+	var (
+		modelErrors = append([]error{}, s.errors...)
+		criteriaSet []graph.Criteria
+	)
+
+	if s.where != nil {
+		criteriaSet = append(criteriaSet, s.where)
+	}
+
+	if order := s.sortOrder(); order != nil {
+		criteriaSet = append(criteriaSet, order)
+	}
+
+	if projection := s.projection(); projection != nil {
+		criteriaSet = append(criteriaSet, projection)
+	}
+
+	for _, criteria := range criteriaSet {
+		if err := model.Walk(criteria, func(parent, element any) error {
+			if errorNode, typeOK := element.(model.Fallible); typeOK {
+				modelErrors = append(modelErrors, errorNode.Errors()...)
+			}
+
+			return nil
+		}, nil); err != nil {
+			modelErrors = append(modelErrors, err)
+		}
+	}
+
+	if symbols, err := referencedSymbols(criteriaSet...); err != nil {
+		modelErrors = append(modelErrors, err)
+	} else {
+		for symbol := range symbols {
+			if isRelationshipSymbol := symbol != query.NodeSymbol; isRelationshipSymbol != s.relationships {
+				modelErrors = append(modelErrors, ErrAmbiguousQueryVariables)
+				break
+			}
+		}
+	}
+
+	return errors.Join(modelErrors...)
+}
+
+// joinedSymbols returns the node symbols of a relationship query that require a join against the node table.
+func (s *querySpec) joinedSymbols() (map[string]struct{}, error) {
+	var criteriaSet []graph.Criteria
+
+	if s.where != nil {
+		criteriaSet = append(criteriaSet, s.where)
+	}
+
+	if order := s.sortOrder(); order != nil {
+		criteriaSet = append(criteriaSet, order)
+	}
+
+	if projection := s.projection(); projection != nil {
+		criteriaSet = append(criteriaSet, projection)
+	}
+
+	return referencedSymbols(criteriaSet...)
+}
+
+// source renders the from clause of the query. Relationship queries join the node table only for the start and end
+// nodes that the query references.
+func (s *querySpec) source() (string, error) {
+	if !s.relationships {
+		return "node " + query.NodeSymbol, nil
+	} else if symbols, err := s.joinedSymbols(); err != nil {
+		return "", err
+	} else {
+		source := "edge " + query.RelationshipSymbol
+
+		if _, joinStart := symbols[query.RelationshipStartSymbol]; joinStart {
+			source += " join node s on s.id = r.start_id"
+		}
+
+		if _, joinEnd := symbols[query.RelationshipEndSymbol]; joinEnd {
+			source += " join node e on e.id = r.end_id"
+		}
+
+		return source, nil
+	}
+}
+
+// joinPredicates renders the join conditions for statements, such as update and delete, that can not use join
+// clauses. The returned using clause lists the joined node tables.
+func (s *querySpec) joinPredicates() (string, []string, error) {
+	var (
+		tables     []string
+		predicates []string
+	)
+
+	if !s.relationships {
+		return "", nil, nil
+	} else if symbols, err := s.joinedSymbols(); err != nil {
+		return "", nil, err
+	} else {
+		if _, joinStart := symbols[query.RelationshipStartSymbol]; joinStart {
+			tables = append(tables, "node s")
+			predicates = append(predicates, "s.id = r.start_id")
+		}
+
+		if _, joinEnd := symbols[query.RelationshipEndSymbol]; joinEnd {
+			tables = append(tables, "node e")
+			predicates = append(predicates, "e.id = r.end_id")
+		}
+
+		return strings.Join(tables, ", "), predicates, nil
+	}
+}
+
+func whereClause(predicates ...string) string {
+	var nonEmpty []string
+
+	for _, predicate := range predicates {
+		if predicate != "" {
+			nonEmpty = append(nonEmpty, predicate)
+		}
+	}
+
+	if len(nonEmpty) == 0 {
+		return ""
+	}
+
+	return " where " + strings.Join(nonEmpty, " and ")
+}
+
+func entityColumns(symbol string) (string, resultColumn) {
+	if symbol == query.RelationshipSymbol {
+		return "r.id, r.start_id, r.end_id, r.kind, r.properties", relationshipColumn
+	}
+
+	return symbol + ".id, " + symbol + ".kinds, " + symbol + ".properties", nodeColumn
+}
+
+// projectionItem renders a single projected expression. Entities are expanded into their columns while all other
+// values are rendered as JSONB.
+func (s *translator) projectionItem(expression model.Expression) (string, resultColumn, bool, bool, error) {
+	var (
+		distinct  = false
+		aggregate = false
+	)
+
+	if isFunction(expression, "distinct") {
+		if argument, err := functionArgument(expression.(*model.FunctionInvocation)); err != nil {
+			return "", valueColumn, false, false, err
+		} else {
+			distinct = true
+			expression = argument
+		}
+	}
+
+	switch typedExpression := expression.(type) {
+	case *model.Variable:
+		if !isValidSymbol(typedExpression.Symbol) {
+			return "", valueColumn, false, false, fmt.Errorf("%w: unknown variable reference %s", ErrUnsupportedExpression, typedExpression.Symbol)
+		}
+
+		rendered, column := entityColumns(typedExpression.Symbol)
+		return rendered, column, distinct, aggregate, nil
+
+	case *model.FunctionInvocation:
+		if strings.EqualFold(typedExpression.Name, "count") {
+			aggregate = true
+
+			if argument, err := functionArgument(typedExpression); err != nil {
+				return "", valueColumn, false, false, err
+			} else if _, isVariable := argument.(*model.Variable); isVariable {
+				return "to_jsonb(count(*))", valueColumn, distinct, aggregate, nil
+			} else if rendered, _, err := s.value(argument, sqlJSONB); err != nil {
+				return "", valueColumn, false, false, err
+			} else {
+				return "to_jsonb(count(" + rendered + "))", valueColumn, distinct, aggregate, nil
+			}
+		}
+	}
+
+	if rendered, _, err := s.value(expression, sqlJSONB); err != nil {
+		return "", valueColumn, false, false, err
+	} else {
+		return "to_jsonb(" + rendered + ")", valueColumn, distinct, aggregate, nil
+	}
+}
+
+func (s *translator) orderBy(order *model.Order) (string, error) {
+	var rendered []string
+
+	for _, item := range order.Items {
+		var renderedItem string
+
+		if variable, isVariable := item.Expression.(*model.Variable); isVariable {
+			if !isValidSymbol(variable.Symbol) {
+				return "", fmt.Errorf("%w: unknown variable reference %s", ErrUnsupportedExpression, variable.Symbol)
+			}
+
+			renderedItem = variable.Symbol + ".id"
+		} else if renderedValue, _, err := s.value(item.Expression, sqlJSONB); err != nil {
+			return "", err
+		} else {
+			renderedItem = renderedValue
+		}
+
+		if item.Ascending {
+			rendered = append(rendered, renderedItem+" asc")
+		} else {
+			rendered = append(rendered, renderedItem+" desc")
+		}
+	}
+
+	return strings.Join(rendered, ", "), nil
+}
+
+func literalInt(expression model.Expression) (int, error) {
+	if value, isValue := parameterValue(expression); !isValue {
+		return 0, fmt.Errorf("%w: expected a literal integer but got %T", ErrUnsupportedExpression, expression)
+	} else if intValue, err := asInt64(value); err != nil {
+		return 0, err
+	} else {
+		return int(intValue), nil
+	}
+}
+
+// selection renders the select statement for the query spec.
+func (s *querySpec) selection() (statement, error) {
+	var (
+		translator = &translator{}
+		builder    = strings.Builder{}
+		columns    []resultColumn
+		rendered   []string
+		distinct   bool
+		aggregate  bool
+	)
+
+	if projection := s.projection(); projection == nil || len(projection.Items) == 0 {
+		return statement{}, fmt.Errorf("query does not specify a projection")
+	} else {
+		distinct = projection.Distinct
+
+		for _, item := range projection.Items {
+			if renderedItem, column, itemDistinct, itemAggregate, err := translator.projectionItem(item.Expression); err != nil {
+				return statement{}, err
+			} else {
+				rendered = append(rendered, renderedItem)
+				columns = append(columns, column)
+				distinct = distinct || itemDistinct
+				aggregate = aggregate || itemAggregate
+			}
+		}
+	}
+
+	builder.WriteString("select ")
+
+	if distinct {
+		builder.WriteString("distinct ")
+	}
+
+	builder.WriteString(strings.Join(rendered, ", "))
+
+	if source, err := s.source(); err != nil {
+		return statement{}, err
+	} else if predicate, err := translator.predicate(s.where); err != nil {
+		return statement{}, err
+	} else {
+		builder.WriteString(" from ")
+		builder.WriteString(source)
+		builder.WriteString(whereClause(predicate))
+	}
+
+	// Ordering is meaningless for aggregate projections, which produce a single row
+	if order := s.sortOrder(); order != nil && len(order.Items) > 0 && !aggregate {
+		if renderedOrder, err := translator.orderBy(order); err != nil {
+			return statement{}, err
+		} else {
+			builder.WriteString(" order by ")
+			builder.WriteString(renderedOrder)
+		}
+	}
+
+	if skipExpression := s.skipExpression(); skipExpression != nil {
+		if skip, err := literalInt(skipExpression); err != nil {
+			return statement{}, err
+		} else if skip > 0 {
+			builder.WriteString(" offset ")
+			builder.WriteString(strconv.Itoa(skip))
+		}
+	}
+
+	if limitExpression := s.limitExpression(); limitExpression != nil {
+		if limit, err := literalInt(limitExpression); err != nil {
+			return statement{}, err
+		} else if limit > 0 {
+			builder.WriteString(" limit ")
+			builder.WriteString(strconv.Itoa(limit))
+		}
+	}
+
+	return statement{
+		sql:        builder.String(),
+		parameters: translator.parameters,
+		columns:    columns,
+	}, nil
+}
+
+// update renders an update statement that applies the given property changes to all entities matched by the query
+// spec.
+func (s *querySpec) update(properties *graph.Properties) (statement, error) {
+	var (
+		translator  = &translator{}
+		set, remove = propertyChanges(properties)
+		table       = "node n"
+		symbol      = query.NodeSymbol
+	)
+
+	if s.relationships {
+		table = "edge r"
+		symbol = query.RelationshipSymbol
+	}
+
+	if renderedSet, err := translator.parameter(set, sqlJSONB); err != nil {
+		return statement{}, err
+	} else if renderedRemove, err := translator.parameter(remove, sqlTextArray); err != nil {
+		return statement{}, err
+	} else if joinedTables, joinPredicates, err := s.joinPredicates(); err != nil {
+		return statement{}, err
+	} else if predicate, err := translator.predicate(s.where); err != nil {
+		return statement{}, err
+	} else {
+		sql := "update " + table + " set properties = (" + symbol + ".properties || " + renderedSet + ") - " + renderedRemove
+
+		if joinedTables != "" {
+			sql += " from " + joinedTables
+		}
+
+		return statement{
+			sql:        sql + whereClause(append(joinPredicates, predicate)...),
+			parameters: translator.parameters,
+		}, nil
+	}
+}
+
+// deletion renders a delete statement for all entities matched by the query spec. Deleting a node also deletes its
+// edges by way of the foreign key constraints of the edge table.
+func (s *querySpec) deletion() (statement, error) {
+	var (
+		translator = &translator{}
+		table      = "node n"
+	)
+
+	if s.relationships {
+		table = "edge r"
+	}
+
+	if joinedTables, joinPredicates, err := s.joinPredicates(); err != nil {
+		return statement{}, err
+	} else if predicate, err := translator.predicate(s.where); err != nil {
+		return statement{}, err
+	} else {
+		sql := "delete from " + table
+
+		if joinedTables != "" {
+			sql += " using " + joinedTables
+		}
+
+		return statement{
+			sql:        sql + whereClause(append(joinPredicates, predicate)...),
+			parameters: translator.parameters,
+		}, nil
+	}
+}
+
+// debug renders the select statement of the query spec and its parameters keyed by their placeholder.
+func (s *querySpec) debug() (string, map[string]any) {
+	spec := *s
+
+	if spec.returning == nil {
+		if spec.relationships {
+			spec.returning = query.Returning(query.Relationship())
+		} else {
+			spec.returning = query.Returning(query.Node())
+		}
+	}
+
+	if rendered, err := spec.selection(); err != nil {
+		return "", nil
+	} else {
+		parameters := make(map[string]any, len(rendered.parameters))
+
+		for idx, parameter := range rendered.parameters {
+			if content, isBytes := parameter.([]byte); isBytes {
+				parameters[strconv.Itoa(idx+1)] = string(content)
+			} else {
+				parameters[strconv.Itoa(idx+1)] = parameter
+			}
+		}
+
+		return rendered.sql, parameters
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+// resultColumn describes how one projected item of a query maps to the columns of a result row.
+type resultColumn int
+
+const (
+	// valueColumn is a single JSONB column
+	valueColumn resultColumn = iota
+
+	// nodeColumn spans the id, kinds and properties columns of a node
+	nodeColumn
+
+	// relationshipColumn spans the id, start_id, end_id, kind and properties columns of an edge
+	relationshipColumn
+)
+
+func scanTargets(columns []resultColumn) []any {
+	var targets []any
+
+	for _, column := range columns {
+		switch column {
+		case nodeColumn:
+			targets = append(targets, new(int64), new([]string), new([]byte))
+
+		case relationshipColumn:
+			targets = append(targets, new(int64), new(int64), new(int64), new(string), new([]byte))
+
+		default:
+			targets = append(targets, new([]byte))
+		}
+	}
+
+	return targets
+}
+
+// assembleRow converts the scanned columns of a row into the values of each projected item.
+func assembleRow(columns []resultColumn, targets []any) ([]any, error) {
+	var (
+		row    = make([]any, len(columns))
+		offset = 0
+	)
+
+	for idx, column := range columns {
+		switch column {
+		case nodeColumn:
+			if properties, err := decodeProperties(*targets[offset+2].(*[]byte)); err != nil {
+				return nil, err
+			} else {
+				row[idx] = graph.NewNode(
+					graph.ID(*targets[offset].(*int64)),
+					properties,
+					graph.StringsToKinds(*targets[offset+1].(*[]string))...,
+				)
+			}
+
+			offset += 3
+
+		case relationshipColumn:
+			if properties, err := decodeProperties(*targets[offset+4].(*[]byte)); err != nil {
+				return nil, err
+			} else {
+				row[idx] = graph.NewRelationship(
+					graph.ID(*targets[offset].(*int64)),
+					graph.ID(*targets[offset+1].(*int64)),
+					graph.ID(*targets[offset+2].(*int64)),
+					properties,
+					graph.StringKind(*targets[offset+3].(*string)),
+				)
+			}
+
+			offset += 5
+
+		default:
+			if value, err := decodeJSON(*targets[offset].(*[]byte)); err != nil {
+				return nil, err
+			} else {
+				row[idx] = value
+			}
+
+			offset += 1
+		}
+	}
+
+	return row, nil
+}
+
+// scanRows reads all rows of the given result. Rows are materialized before they are handed to callers so that the
+// connection of the transaction is free to serve queries issued while the caller iterates the result.
+func scanRows(rows pgx.Rows, columns []resultColumn) ([][]any, error) {
+	defer rows.Close()
+
+	var (
+		scanned [][]any
+		targets = scanTargets(columns)
+	)
+
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		} else if row, err := assembleRow(columns, targets); err != nil {
+			return nil, err
+		} else {
+			scanned = append(scanned, row)
+		}
+	}
+
+	return scanned, rows.Err()
+}
+
+// mapNumeric maps numeric raw values into any numeric target type using reflection.
+func mapNumeric(target, rawValue any) error {
+	targetValue := reflect.ValueOf(target)
+
+	if targetValue.Kind() != reflect.Pointer || targetValue.IsNil() {
+		return fmt.Errorf("unsupported scan type %T", target)
+	}
+
+	element := targetValue.Elem()
+
+	switch element.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value, err := asInt64(rawValue); err != nil {
+			return err
+		} else if element.OverflowInt(value) {
+			return fmt.Errorf("value %d overflows %s", value, element.Type())
+		} else {
+			element.SetInt(value)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value, err := asInt64(rawValue); err != nil {
+			return err
+		} else if value < 0 || element.OverflowUint(uint64(value)) {
+			return fmt.Errorf("value %d overflows %s", value, element.Type())
+		} else {
+			element.SetUint(uint64(value))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if value, err := asFloat64(rawValue); err != nil {
+			return err
+		} else {
+			element.SetFloat(value)
+		}
+
+	default:
+		return fmt.Errorf("unsupported scan type %T", target)
+	}
+
+	return nil
+}
+
+func mapValue(target, rawValue any) error {
+	switch typedTarget := target.(type) {
+	case *any:
+		*typedTarget = rawValue
+
+	case *graph.ID:
+		if value, err := asInt64(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = graph.ID(value)
+		}
+
+	case *bool:
+		if value, typeOK := rawValue.(bool); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to bool", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	case *graph.Kind:
+		if value, typeOK := asString(rawValue); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to graph.Kind", rawValue)
+		} else {
+			*typedTarget = graph.StringKind(value)
+		}
+
+	case *string:
+		if value, typeOK := asString(rawValue); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to string", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	case *[]graph.Kind:
+		if value, err := asKinds(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = value
+		}
+
+	case *graph.Kinds:
+		if value, err := asKinds(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = value
+		}
+
+	case *[]string:
+		if value, typeOK := asStrings(rawValue); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to []string", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	case *time.Time:
+		if value, err := asTime(rawValue); err != nil {
+			return err
+		} else {
+			*typedTarget = value
+		}
+
+	case *graph.Node:
+		if value, typeOK := rawValue.(*graph.Node); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to *graph.Node", rawValue)
+		} else {
+			*typedTarget = *value
+		}
+
+	case *graph.Relationship:
+		if value, typeOK := rawValue.(*graph.Relationship); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to *graph.Relationship", rawValue)
+		} else {
+			*typedTarget = *value
+		}
+
+	case *graph.Path:
+		if value, typeOK := rawValue.(graph.Path); !typeOK {
+			return fmt.Errorf("unexpected type %T will not negotiate to graph.Path", rawValue)
+		} else {
+			*typedTarget = value
+		}
+
+	default:
+		return mapNumeric(target, rawValue)
+	}
+
+	return nil
+}
+
+type ValueMapper struct {
+	values []any
+	idx    int
+}
+
+func NewValueMapper(values []any) *ValueMapper {
+	return &ValueMapper{
+		values: values,
+		idx:    0,
+	}
+}
+
+func (s *ValueMapper) Next() (any, error) {
+	if s.idx >= len(s.values) {
+		return nil, fmt.Errorf("attempting to get more values than returned - saw %d but wanted %d", len(s.values), s.idx+1)
+	}
+
+	nextValue := s.values[s.idx]
+	s.idx++
+
+	return nextValue, nil
+}
+
+func (s *ValueMapper) Map(target any) error {
+	if rawValue, err := s.Next(); err != nil {
+		return err
+	} else {
+		return mapValue(target, rawValue)
+	}
+}
+
+func (s *ValueMapper) MapOptions(targets ...any) (any, error) {
+	if rawValue, err := s.Next(); err != nil {
+		return nil, err
+	} else {
+		for _, target := range targets {
+			if mapValue(target, rawValue) == nil {
+				return target, nil
+			}
+		}
+
+		return nil, fmt.Errorf("no matching target given for type: %T", rawValue)
+	}
+}
+
+func (s *ValueMapper) Scan(targets ...any) error {
+	for _, target := range targets {
+		if err := s.Map(target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type result struct {
+	rows [][]any
+	idx  int
+	err  error
+}
+
+func newResult(rows [][]any) graph.Result {
+	return &result{
+		rows: rows,
+		idx:  -1,
+	}
+}
+
+func newErrorResult(err error) graph.Result {
+	return &result{
+		idx: -1,
+		err: err,
+	}
+}
+
+func (s *result) Next() bool {
+	if s.err != nil || s.idx+1 >= len(s.rows) {
+		return false
+	}
+
+	s.idx++
+	return true
+}
+
+func (s *result) Values() graph.ValueMapper {
+	if s.idx < 0 || s.idx >= len(s.rows) {
+		return NewValueMapper(nil)
+	}
+
+	return NewValueMapper(s.rows[s.idx])
+}
+
+func (s *result) Scan(targets ...any) error {
+	return s.Values().Scan(targets...)
+}
+
+func (s *result) Error() error {
+	return s.err
+}
+
+func (s *result) Close() {
+	// Results are fully materialized and hold no resources that require release
+}
@@ -0,0 +1,247 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+// graphSchemaStatements create the tables that store the graph. Node kinds are stored as a text array and all
+// properties are stored as JSONB. Deleting a node deletes all edges attached to it.
+var graphSchemaStatements = []string{
+	`create table if not exists node (
+	id bigserial primary key,
+	kinds text[] not null default '{}',
+	properties jsonb not null default '{}'
+)`,
+	`create index if not exists node_kinds_index on node using gin (kinds)`,
+	`create index if not exists node_properties_index on node using gin (properties jsonb_path_ops)`,
+	`create table if not exists edge (
+	id bigserial primary key,
+	start_id bigint not null references node (id) on delete cascade,
+	end_id bigint not null references node (id) on delete cascade,
+	kind text not null,
+	properties jsonb not null default '{}'
+)`,
+	`create index if not exists edge_start_id_index on edge (start_id)`,
+	`create index if not exists edge_end_id_index on edge (end_id)`,
+	`create index if not exists edge_kind_index on edge (kind)`,
+	`create index if not exists edge_properties_index on edge using gin (properties jsonb_path_ops)`,
+}
+
+const (
+	// Text search indexes are trigram indexes, which require the pg_trgm extension
+	createTrigramExtensionStatement = `create extension if not exists pg_trgm`
+
+	fetchPropertyIndicesStatement = `select indexname, indexdef from pg_indexes where schemaname = current_schema() and tablename = 'node'`
+)
+
+var (
+	// Property indexes are partial expression indexes over a single property of all nodes of a given kind, for example:
+	// CREATE INDEX user_name_index ON public.node USING btree (((properties ->> 'name'::text))) WHERE (kinds @> ARRAY['User'::text])
+	indexKindPattern     = regexp.MustCompile(`kinds @> ARRAY\['((?:[^']|'')*)'::text\]`)
+	indexPropertyPattern = regexp.MustCompile(`\(properties ->> '((?:[^']|'')*)'::text\)`)
+)
+
+func unquoteLiteral(value string) string {
+	return strings.ReplaceAll(value, "''", "'")
+}
+
+func quoteIdentifier(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+// parseIndexDefinition returns the kind and property of a property index definition. Indexes that are not property
+// indexes, such as the indexes that back the graph tables, are not matched.
+func parseIndexDefinition(definition string) (graph.Kind, string, graph.IndexType, bool) {
+	if kindMatch := indexKindPattern.FindStringSubmatch(definition); kindMatch == nil {
+		return nil, "", graph.UnsupportedIndex, false
+	} else if propertyMatch := indexPropertyPattern.FindStringSubmatch(definition); propertyMatch == nil {
+		return nil, "", graph.UnsupportedIndex, false
+	} else {
+		indexType := graph.BTreeIndex
+
+		if strings.Contains(definition, " USING gin ") {
+			indexType = graph.FullTextSearchIndex
+		}
+
+		return graph.StringKind(unquoteLiteral(kindMatch[1])), unquoteLiteral(propertyMatch[1]), indexType, true
+	}
+}
+
+func isUniqueIndexDefinition(definition string) bool {
+	return strings.HasPrefix(definition, "CREATE UNIQUE INDEX")
+}
+
+// propertyIndexStatement renders the statement that creates an index for the given property of all nodes of the given
+// kind. Text search indexes are trigram indexes, which serve the like expressions that string comparisons translate to.
+func propertyIndexStatement(name string, kind graph.Kind, property string, indexType graph.IndexType, unique bool) string {
+	builder := strings.Builder{}
+
+	if unique {
+		builder.WriteString("create unique index if not exists ")
+	} else {
+		builder.WriteString("create index if not exists ")
+	}
+
+	builder.WriteString(quoteIdentifier(name))
+
+	if indexType == graph.FullTextSearchIndex && !unique {
+		builder.WriteString(" on node using gin ((properties ->> ")
+		builder.WriteString(quoteLiteral(property))
+		builder.WriteString(") gin_trgm_ops)")
+	} else {
+		builder.WriteString(" on node using btree ((properties ->> ")
+		builder.WriteString(quoteLiteral(property))
+		builder.WriteString("))")
+	}
+
+	builder.WriteString(" where kinds @> array[")
+	builder.WriteString(quoteLiteral(kind.String()))
+	builder.WriteString("]::text[]")
+
+	return builder.String()
+}
+
+func dropIndexStatement(name string) string {
+	return "drop index if exists " + quoteIdentifier(name)
+}
+
+func assertGraphSchema(ctx context.Context, tx pgx.Tx) error {
+	for _, statement := range graphSchemaStatements {
+		if _, err := tx.Exec(ctx, statement); err != nil {
+			return graph.NewError(statement, err)
+		}
+	}
+
+	return nil
+}
+
+func fetchSchema(ctx context.Context, tx pgx.Tx) (*graph.Schema, error) {
+	schema := graph.NewSchema()
+
+	if rows, err := tx.Query(ctx, fetchPropertyIndicesStatement); err != nil {
+		return nil, graph.NewError(fetchPropertyIndicesStatement, err)
+	} else {
+		defer rows.Close()
+
+		var (
+			name       string
+			definition string
+		)
+
+		for rows.Next() {
+			if err := rows.Scan(&name, &definition); err != nil {
+				return nil, err
+			}
+
+			if kind, property, indexType, matched := parseIndexDefinition(definition); matched {
+				if isUniqueIndexDefinition(definition) {
+					schema.EnsureKind(kind).Constraint(property, name, indexType)
+				} else {
+					schema.EnsureKind(kind).Index(property, name, indexType)
+				}
+			}
+		}
+
+		return schema, rows.Err()
+	}
+}
+
+// schemaStatements returns the statements required to migrate the existing schema to the required schema.
+func schemaStatements(requiredSchema, existingSchema *graph.Schema) []string {
+	var (
+		drops   []string
+		creates []string
+	)
+
+	for kind, existingKindSchema := range existingSchema.Kinds {
+		requiredKindSchema, hasRequirement := requiredSchema.Kinds[kind]
+
+		for property, indexSchema := range existingKindSchema.PropertyIndices {
+			if !hasRequirement {
+				drops = append(drops, dropIndexStatement(indexSchema.Name))
+			} else if requiredIndexSchema, found := requiredKindSchema.PropertyIndices[property]; !found || !indexSchema.Equals(requiredIndexSchema) {
+				drops = append(drops, dropIndexStatement(indexSchema.Name))
+			}
+		}
+
+		for property, constraintSchema := range existingKindSchema.PropertyConstraints {
+			if !hasRequirement {
+				drops = append(drops, dropIndexStatement(constraintSchema.Name))
+			} else if requiredConstraintSchema, found := requiredKindSchema.PropertyConstraints[property]; !found || !constraintSchema.Equals(requiredConstraintSchema) {
+				drops = append(drops, dropIndexStatement(constraintSchema.Name))
+			}
+		}
+	}
+
+	for kind, requiredKindSchema := range requiredSchema.Kinds {
+		existingKindSchema, hasExisting := existingSchema.Kinds[kind]
+
+		for property, requiredIndexSchema := range requiredKindSchema.PropertyIndices {
+			if hasExisting {
+				if indexSchema, found := existingKindSchema.PropertyIndices[property]; found && indexSchema.Equals(requiredIndexSchema) {
+					continue
+				}
+			}
+
+			creates = append(creates, propertyIndexStatement(requiredIndexSchema.Name, kind, property, requiredIndexSchema.IndexType, false))
+		}
+
+		for property, requiredConstraintSchema := range requiredKindSchema.PropertyConstraints {
+			if hasExisting {
+				if constraintSchema, found := existingKindSchema.PropertyConstraints[property]; found && constraintSchema.Equals(requiredConstraintSchema) {
+					continue
+				}
+			}
+
+			creates = append(creates, propertyIndexStatement(requiredConstraintSchema.Name, kind, property, requiredConstraintSchema.IndexType, true))
+		}
+	}
+
+	return append(drops, creates...)
+}
+
+func assertSchema(ctx context.Context, tx pgx.Tx, requiredSchema *graph.Schema) error {
+	if err := assertGraphSchema(ctx, tx); err != nil {
+		return err
+	} else if existingSchema, err := fetchSchema(ctx, tx); err != nil {
+		return fmt.Errorf("could not load schema: %w", err)
+	} else {
+		statements := schemaStatements(requiredSchema, existingSchema)
+
+		if len(statements) > 0 {
+			if _, err := tx.Exec(ctx, createTrigramExtensionStatement); err != nil {
+				return graph.NewError(createTrigramExtensionStatement, err)
+			}
+		}
+
+		for _, statement := range statements {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return graph.NewError(statement, err)
+			}
+		}
+
+		return nil
+	}
+}
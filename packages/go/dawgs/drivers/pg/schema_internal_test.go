@@ -0,0 +1,61 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndexDefinition(t *testing.T) {
+	kind, property, indexType, matched := parseIndexDefinition(`CREATE INDEX user_name_index ON public.node USING gin (((properties ->> 'name'::text)) gin_trgm_ops) WHERE (kinds @> ARRAY['User'::text])`)
+	require.True(t, matched)
+	require.Equal(t, graph.StringKind("User"), kind)
+	require.Equal(t, "name", property)
+	require.Equal(t, graph.FullTextSearchIndex, indexType)
+
+	kind, property, indexType, matched = parseIndexDefinition(`CREATE UNIQUE INDEX base_object''id_constraint ON public.node USING btree (((properties ->> 'object''id'::text))) WHERE (kinds @> ARRAY['Base'::text])`)
+	require.True(t, matched)
+	require.Equal(t, graph.StringKind("Base"), kind)
+	require.Equal(t, "object'id", property)
+	require.Equal(t, graph.BTreeIndex, indexType)
+
+	_, _, _, matched = parseIndexDefinition(`CREATE INDEX node_kinds_index ON public.node USING gin (kinds)`)
+	require.False(t, matched)
+}
+
+func TestSchemaStatements(t *testing.T) {
+	var (
+		userKind       = graph.StringKind("User")
+		computerKind   = graph.StringKind("Computer")
+		requiredSchema = graph.NewSchema()
+		existingSchema = graph.NewSchema()
+	)
+
+	requiredSchema.EnsureKind(userKind).IndexProperty("name", graph.FullTextSearchIndex)
+	requiredSchema.EnsureKind(userKind).ConstrainProperty("objectid", graph.BTreeIndex)
+
+	existingSchema.EnsureKind(userKind).ConstrainProperty("objectid", graph.BTreeIndex)
+	existingSchema.EnsureKind(computerKind).IndexProperty("name", graph.BTreeIndex)
+
+	require.Equal(t, []string{
+		`drop index if exists "computer_name_index"`,
+		`create index if not exists "user_name_index" on node using gin ((properties ->> 'name') gin_trgm_ops) where kinds @> array['User']::text[]`,
+	}, schemaStatements(requiredSchema, existingSchema))
+}
@@ -0,0 +1,434 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+const (
+	// lastSeenProperty is stamped on the start and end nodes of relationships merged by UpdateRelationshipBy to match
+	// the behavior of the Neo4j driver.
+	lastSeenProperty = "lastseen"
+
+	createNodeStatement = `insert into node (kinds, properties) values ($1::text[], $2::jsonb) returning id`
+
+	updateNodeStatement = `update node set kinds = array(select kind from unnest(node.kinds || $2::text[]) with ordinality as merged(kind, idx) where kind <> all($3::text[]) group by kind order by min(idx)), properties = (node.properties || $4::jsonb) - $5::text[] where node.id = $1::int8`
+
+	// mergeNodeStatement updates the first node that carries the identity kinds and identity properties or creates it
+	// if no such node exists. Parameters are the identity kinds, the identity properties, the kinds to add, the
+	// properties to set and the property keys to remove.
+	mergeNodeStatement = `with existing as (
+	select id from node where kinds @> $1::text[] and properties @> $2::jsonb order by id limit 1
+), updated as (
+	update node set kinds = array(select kind from unnest(node.kinds || $3::text[]) with ordinality as merged(kind, idx) group by kind order by min(idx)), properties = (node.properties || $4::jsonb) - $5::text[]
+	where node.id in (select id from existing) returning node.id
+), inserted as (
+	insert into node (kinds, properties) select array(select kind from unnest($1::text[] || $3::text[]) with ordinality as merged(kind, idx) group by kind order by min(idx)), ($2::jsonb || $4::jsonb) - $5::text[]
+	where not exists (select 1 from existing) returning node.id
+)
+select id from updated union all select id from inserted`
+
+	createRelationshipStatement = `insert into edge (start_id, end_id, kind, properties) select $1::int8, $2::int8, $3::text, $4::jsonb where exists (select 1 from node where id = $1::int8) and exists (select 1 from node where id = $2::int8) returning id`
+
+	updateRelationshipStatement = `update edge set properties = (edge.properties || $2::jsonb) - $3::text[] where edge.id = $1::int8`
+
+	// mergeRelationshipStatement updates the first edge of the given kind between the given nodes that carries the
+	// identity properties or creates it if no such edge exists. Parameters are the start node ID, the end node ID, the
+	// edge kind, the identity properties, the properties to set and the property keys to remove.
+	mergeRelationshipStatement = `with existing as (
+	select id from edge where start_id = $1::int8 and end_id = $2::int8 and kind = $3::text and properties @> $4::jsonb order by id limit 1
+), updated as (
+	update edge set properties = (edge.properties || $5::jsonb) - $6::text[]
+	where edge.id in (select id from existing) returning edge.id
+), inserted as (
+	insert into edge (start_id, end_id, kind, properties) select $1::int8, $2::int8, $3::text, ($4::jsonb || $5::jsonb) - $6::text[]
+	where not exists (select 1 from existing) returning edge.id
+)
+select id from updated union all select id from inserted`
+
+	deleteNodeStatement         = `delete from node where id = $1::int8`
+	deleteRelationshipStatement = `delete from edge where id = $1::int8`
+)
+
+var (
+	ErrReadOnlyTransaction = errors.New("write operation attempted in a read transaction")
+)
+
+// transaction wraps a PostgreSQL transaction. Much like the Neo4j driver, writable transactions are committed and
+// replaced by a new transaction every time the number of writes reaches the write flush size to keep the size of a
+// single PostgreSQL transaction bounded.
+type transaction struct {
+	ctx            context.Context
+	conn           *pgxpool.Conn
+	txOptions      pgx.TxOptions
+	innerTx        pgx.Tx
+	writes         int
+	writeFlushSize int
+}
+
+func newTransaction(ctx context.Context, conn *pgxpool.Conn, txOptions pgx.TxOptions, writeFlushSize int) *transaction {
+	return &transaction{
+		ctx:            ctx,
+		conn:           conn,
+		txOptions:      txOptions,
+		writeFlushSize: writeFlushSize,
+	}
+}
+
+func (s *transaction) writable() bool {
+	return s.txOptions.AccessMode != pgx.ReadOnly
+}
+
+func (s *transaction) currentTx() (pgx.Tx, error) {
+	if s.innerTx == nil {
+		if innerTx, err := s.conn.BeginTx(s.ctx, s.txOptions); err != nil {
+			return nil, err
+		} else {
+			s.innerTx = innerTx
+		}
+	}
+
+	return s.innerTx, nil
+}
+
+func (s *transaction) logWrites(writes int) error {
+	if s.writes += writes; s.writeFlushSize > 0 && s.writes >= s.writeFlushSize {
+		s.writes = 0
+		return s.Commit()
+	}
+
+	return nil
+}
+
+// query executes the given statement and returns its materialized rows.
+func (s *transaction) query(stmt statement) ([][]any, error) {
+	if innerTx, err := s.currentTx(); err != nil {
+		return nil, err
+	} else if rows, err := innerTx.Query(s.ctx, stmt.sql, stmt.parameters...); err != nil {
+		return nil, graph.NewError(stmt.sql, err)
+	} else if scanned, err := scanRows(rows, stmt.columns); err != nil {
+		return nil, graph.NewError(stmt.sql, err)
+	} else {
+		return scanned, nil
+	}
+}
+
+// queryID executes the given statement and returns the ID in the first column of its first row.
+func (s *transaction) queryID(sql string, parameters ...any) (graph.ID, error) {
+	var id int64
+
+	if innerTx, err := s.currentTx(); err != nil {
+		return 0, err
+	} else if err := innerTx.QueryRow(s.ctx, sql, parameters...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, graph.ErrNoResultsFound
+		}
+
+		return 0, graph.NewError(sql, err)
+	}
+
+	return graph.ID(id), nil
+}
+
+// exec executes the given statement. Statements that modify the graph must be executed in a writable transaction.
+func (s *transaction) exec(stmt statement) error {
+	if !s.writable() {
+		return ErrReadOnlyTransaction
+	} else if innerTx, err := s.currentTx(); err != nil {
+		return err
+	} else if _, err := innerTx.Exec(s.ctx, stmt.sql, stmt.parameters...); err != nil {
+		return graph.NewError(stmt.sql, err)
+	}
+
+	return s.logWrites(1)
+}
+
+func (s *transaction) Nodes() graph.NodeQuery {
+	return newNodeQuery(s.ctx, s)
+}
+
+func (s *transaction) Relationships() graph.RelationshipQuery {
+	return newRelationshipQuery(s.ctx, s)
+}
+
+func (s *transaction) createNode(properties *graph.Properties, kinds graph.Kinds) (graph.ID, error) {
+	var propertyMap map[string]any
+
+	if properties != nil {
+		propertyMap = properties.Map
+	}
+
+	if !s.writable() {
+		return 0, ErrReadOnlyTransaction
+	} else if content, err := marshalProperties(propertyMap); err != nil {
+		return 0, err
+	} else if id, err := s.queryID(createNodeStatement, kinds.Strings(), content); err != nil {
+		return 0, err
+	} else {
+		return id, s.logWrites(1)
+	}
+}
+
+func (s *transaction) CreateNode(properties *graph.Properties, kinds ...graph.Kind) (*graph.Node, error) {
+	if properties == nil {
+		properties = graph.NewProperties()
+	}
+
+	if id, err := s.createNode(properties, kinds); err != nil {
+		return nil, err
+	} else {
+		return graph.NewNode(id, properties, kinds...), nil
+	}
+}
+
+func (s *transaction) UpdateNode(node *graph.Node) error {
+	set, remove := propertyChanges(node.Properties)
+
+	if content, err := marshalProperties(set); err != nil {
+		return err
+	} else {
+		return s.exec(statement{
+			sql:        updateNodeStatement,
+			parameters: []any{node.ID.Int64(), node.AddedKinds.Strings(), node.DeletedKinds.Strings(), content, remove},
+		})
+	}
+}
+
+func (s *transaction) mergeNode(identityKind graph.Kind, identity, properties map[string]any, kinds graph.Kinds) (graph.ID, error) {
+	var (
+		identityKinds = []string{}
+		set, remove   = splitProperties(properties)
+	)
+
+	if identityKind != nil {
+		identityKinds = append(identityKinds, identityKind.String())
+	}
+
+	if !s.writable() {
+		return 0, ErrReadOnlyTransaction
+	} else if identityContent, err := marshalProperties(identity); err != nil {
+		return 0, err
+	} else if setContent, err := marshalProperties(set); err != nil {
+		return 0, err
+	} else if id, err := s.queryID(mergeNodeStatement, identityKinds, identityContent, kinds.Strings(), setContent, remove); err != nil {
+		return 0, err
+	} else {
+		return id, s.logWrites(1)
+	}
+}
+
+func (s *transaction) updateNodeBy(update graph.NodeUpdate) error {
+	identity := make(map[string]any, len(update.IdentityProperties))
+
+	for _, key := range update.IdentityProperties {
+		identity[key] = update.Node.Properties.Get(key).Any()
+	}
+
+	_, err := s.mergeNode(update.IdentityKind, identity, update.Node.Properties.Map, update.Node.Kinds)
+	return err
+}
+
+func (s *transaction) UpdateNodeBy(update graph.NodeUpdate) error {
+	return s.updateNodeBy(update)
+}
+
+func (s *transaction) CreateRelationship(startNode, endNode *graph.Node, kind graph.Kind, properties *graph.Properties) (*graph.Relationship, error) {
+	return s.CreateRelationshipByIDs(startNode.ID, endNode.ID, kind, properties)
+}
+
+func (s *transaction) CreateRelationshipByIDs(startNodeID, endNodeID graph.ID, kind graph.Kind, properties *graph.Properties) (*graph.Relationship, error) {
+	if properties == nil {
+		properties = graph.NewProperties()
+	}
+
+	if !s.writable() {
+		return nil, ErrReadOnlyTransaction
+	} else if content, err := marshalProperties(properties.Map); err != nil {
+		return nil, err
+	} else if id, err := s.queryID(createRelationshipStatement, startNodeID.Int64(), endNodeID.Int64(), kind.String(), content); err != nil {
+		return nil, err
+	} else {
+		return graph.NewRelationship(id, startNodeID, endNodeID, properties, kind), s.logWrites(1)
+	}
+}
+
+func (s *transaction) UpdateRelationship(relationship *graph.Relationship) error {
+	set, remove := propertyChanges(relationship.Properties)
+
+	if content, err := marshalProperties(set); err != nil {
+		return err
+	} else {
+		return s.exec(statement{
+			sql:        updateRelationshipStatement,
+			parameters: []any{relationship.ID.Int64(), content, remove},
+		})
+	}
+}
+
+func (s *transaction) mergeRelationship(startID, endID graph.ID, kind graph.Kind, identity, properties map[string]any) error {
+	set, remove := splitProperties(properties)
+
+	if !s.writable() {
+		return ErrReadOnlyTransaction
+	} else if identityContent, err := marshalProperties(identity); err != nil {
+		return err
+	} else if setContent, err := marshalProperties(set); err != nil {
+		return err
+	} else if _, err := s.queryID(mergeRelationshipStatement, startID.Int64(), endID.Int64(), kind.String(), identityContent, setContent, remove); err != nil {
+		return err
+	}
+
+	return s.logWrites(1)
+}
+
+func (s *transaction) updateRelationshipBy(update graph.RelationshipUpdate) error {
+	lastSeen := map[string]any{
+		lastSeenProperty: time.Now().UTC(),
+	}
+
+	if startID, err := s.mergeNode(update.StartIdentityKind, update.StartIdentityPropertiesMap(), lastSeen, update.Start.Kinds); err != nil {
+		return err
+	} else if endID, err := s.mergeNode(update.EndIdentityKind, update.EndIdentityPropertiesMap(), lastSeen, update.End.Kinds); err != nil {
+		return err
+	} else {
+		return s.mergeRelationship(startID, endID, update.Relationship.Kind, update.IdentityPropertiesMap(), update.Relationship.Properties.Map)
+	}
+}
+
+func (s *transaction) UpdateRelationshipBy(update graph.RelationshipUpdate) error {
+	return s.updateRelationshipBy(update)
+}
+
+func (s *transaction) Run(query string, parameters map[string]any) graph.Result {
+	// Raw statements are Cypher and can not be run until the driver is able to translate them
+	return newErrorResult(graph.NewError(query, graph.ErrUnsupportedDatabaseOperation))
+}
+
+func (s *transaction) Commit() error {
+	if s.innerTx != nil {
+		innerTx := s.innerTx
+		s.innerTx = nil
+
+		return innerTx.Commit(s.ctx)
+	}
+
+	return nil
+}
+
+// Close rolls back any work that has not been committed.
+func (s *transaction) Close() error {
+	if s.innerTx != nil {
+		innerTx := s.innerTx
+		s.innerTx = nil
+
+		return innerTx.Rollback(s.ctx)
+	}
+
+	return nil
+}
+
+type batchTransaction struct {
+	innerTx *transaction
+}
+
+func newBatchOperation(innerTx *transaction) *batchTransaction {
+	return &batchTransaction{
+		innerTx: innerTx,
+	}
+}
+
+func (s *batchTransaction) Nodes() graph.NodeQuery {
+	return s.innerTx.Nodes()
+}
+
+func (s *batchTransaction) Relationships() graph.RelationshipQuery {
+	return s.innerTx.Relationships()
+}
+
+func (s *batchTransaction) CreateNode(properties *graph.Properties, kinds ...graph.Kind) error {
+	_, err := s.innerTx.createNode(properties, kinds)
+	return err
+}
+
+func (s *batchTransaction) DeleteNode(id graph.ID) error {
+	return s.innerTx.exec(statement{
+		sql:        deleteNodeStatement,
+		parameters: []any{id.Int64()},
+	})
+}
+
+func (s *batchTransaction) UpdateNodeBy(update graph.NodeUpdate) error {
+	return s.innerTx.updateNodeBy(update)
+}
+
+func (s *batchTransaction) CreateRelationship(startNode, endNode *graph.Node, kind graph.Kind, properties *graph.Properties) error {
+	var (
+		startID = startNode.ID
+		endID   = endNode.ID
+	)
+
+	if startID == graph.UnregisteredNodeID {
+		if newStartID, err := s.innerTx.createNode(startNode.Properties, startNode.Kinds); err != nil {
+			return err
+		} else {
+			startID = newStartID
+		}
+	}
+
+	if endID == graph.UnregisteredNodeID {
+		if newEndID, err := s.innerTx.createNode(endNode.Properties, endNode.Kinds); err != nil {
+			return err
+		} else {
+			endID = newEndID
+		}
+	}
+
+	return s.CreateRelationshipByIDs(startID, endID, kind, properties)
+}
+
+func (s *batchTransaction) CreateRelationshipByIDs(startNodeID, endNodeID graph.ID, kind graph.Kind, properties *graph.Properties) error {
+	var propertyMap map[string]any
+
+	if properties != nil {
+		propertyMap = properties.Map
+	}
+
+	// Batch relationship creation merges on the relationship kind between the two nodes, as the Neo4j driver does
+	return s.innerTx.mergeRelationship(startNodeID, endNodeID, kind, nil, propertyMap)
+}
+
+func (s *batchTransaction) DeleteRelationship(id graph.ID) error {
+	return s.innerTx.exec(statement{
+		sql:        deleteRelationshipStatement,
+		parameters: []any{id.Int64()},
+	})
+}
+
+func (s *batchTransaction) UpdateRelationshipBy(update graph.RelationshipUpdate) error {
+	return s.innerTx.updateRelationshipBy(update)
+}
+
+func (s *batchTransaction) Commit() error {
+	return s.innerTx.Commit()
+}
@@ -0,0 +1,712 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/specterops/bloodhound/cypher/model"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+)
+
+var (
+	ErrUnsupportedExpression = errors.New("unsupported expression")
+)
+
+// sqlType describes the SQL type an expression is rendered as. Parameters are cast to the type of the expression they
+// are compared against so that PostgreSQL does not have to infer parameter types.
+type sqlType int
+
+const (
+	sqlJSONB sqlType = iota
+	sqlText
+	sqlTextArray
+	sqlInt8
+	sqlInt8Array
+	sqlTimestamp
+	sqlBoolean
+)
+
+// likeEscaper escapes the wildcard characters of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// translator renders the dawgs criteria model as SQL. Node symbols are rendered as aliases of the node table and the
+// relationship symbol is rendered as an alias of the edge table. Parameter values are collected in the order in which
+// they are referenced.
+type translator struct {
+	parameters []any
+}
+
+func (s *translator) bind(value any) string {
+	s.parameters = append(s.parameters, value)
+	return "$" + strconv.Itoa(len(s.parameters))
+}
+
+func isNodeSymbol(symbol string) bool {
+	switch symbol {
+	case query.NodeSymbol, query.RelationshipStartSymbol, query.RelationshipEndSymbol:
+		return true
+
+	default:
+		return false
+	}
+}
+
+func isValidSymbol(symbol string) bool {
+	return isNodeSymbol(symbol) || symbol == query.RelationshipSymbol
+}
+
+func variableSymbol(expression model.Expression) (string, error) {
+	if variable, typeOK := expression.(*model.Variable); !typeOK {
+		return "", fmt.Errorf("%w: expected a variable reference but got %T", ErrUnsupportedExpression, expression)
+	} else if !isValidSymbol(variable.Symbol) {
+		return "", fmt.Errorf("%w: unknown variable reference %s", ErrUnsupportedExpression, variable.Symbol)
+	} else {
+		return variable.Symbol, nil
+	}
+}
+
+func idsToInt64Slice(ids []graph.ID) []int64 {
+	int64Slice := make([]int64, len(ids))
+
+	for idx, id := range ids {
+		int64Slice[idx] = id.Int64()
+	}
+
+	return int64Slice
+}
+
+// normalizeValue converts graph types into values that may be bound as query parameters.
+func normalizeValue(value any) any {
+	switch typedValue := value.(type) {
+	case graph.ID:
+		return typedValue.Int64()
+
+	case []graph.ID:
+		return idsToInt64Slice(typedValue)
+
+	case graph.Kind:
+		return typedValue.String()
+
+	case graph.Kinds:
+		return typedValue.Strings()
+
+	case time.Time:
+		return typedValue.UTC()
+
+	default:
+		return value
+	}
+}
+
+func asInt64Slice(value any) ([]int64, error) {
+	switch typedValue := normalizeValue(value).(type) {
+	case []int64:
+		return typedValue, nil
+
+	case []int:
+		int64Slice := make([]int64, len(typedValue))
+
+		for idx, nextValue := range typedValue {
+			int64Slice[idx] = int64(nextValue)
+		}
+
+		return int64Slice, nil
+
+	case []any:
+		int64Slice := make([]int64, len(typedValue))
+
+		for idx, nextValue := range typedValue {
+			if int64Value, err := asInt64(nextValue); err != nil {
+				return nil, err
+			} else {
+				int64Slice[idx] = int64Value
+			}
+		}
+
+		return int64Slice, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected type %T will not negotiate to []int64", value)
+	}
+}
+
+// parameter binds the given value as a parameter of the given SQL type.
+func (s *translator) parameter(value any, as sqlType) (string, error) {
+	switch as {
+	case sqlInt8:
+		if int64Value, err := asInt64(normalizeValue(value)); err != nil {
+			return "", err
+		} else {
+			return s.bind(int64Value) + "::int8", nil
+		}
+
+	case sqlInt8Array:
+		if int64Slice, err := asInt64Slice(value); err != nil {
+			return "", err
+		} else {
+			return s.bind(int64Slice) + "::int8[]", nil
+		}
+
+	case sqlText:
+		if stringValue, typeOK := asString(normalizeValue(value)); !typeOK {
+			return "", fmt.Errorf("unexpected type %T will not negotiate to string", value)
+		} else {
+			return s.bind(stringValue) + "::text", nil
+		}
+
+	case sqlTextArray:
+		if stringSlice, typeOK := asStrings(normalizeValue(value)); !typeOK {
+			return "", fmt.Errorf("unexpected type %T will not negotiate to []string", value)
+		} else {
+			return s.bind(stringSlice) + "::text[]", nil
+		}
+
+	case sqlTimestamp:
+		if timeValue, typeOK := value.(time.Time); !typeOK {
+			return "", fmt.Errorf("unexpected type %T will not negotiate to time.Time", value)
+		} else {
+			return s.bind(timeValue.UTC()) + "::timestamptz", nil
+		}
+
+	case sqlBoolean:
+		if boolValue, typeOK := value.(bool); !typeOK {
+			return "", fmt.Errorf("unexpected type %T will not negotiate to bool", value)
+		} else {
+			return s.bind(boolValue) + "::bool", nil
+		}
+
+	default:
+		if content, err := json.Marshal(normalizeValue(value)); err != nil {
+			return "", err
+		} else {
+			return s.bind(content) + "::jsonb", nil
+		}
+	}
+}
+
+// parameterValue returns the value of a parameter or literal expression.
+func parameterValue(expression model.Expression) (any, bool) {
+	switch typedExpression := expression.(type) {
+	case *model.Parameter:
+		return typedExpression.Value, true
+
+	case *model.Literal:
+		if typedExpression.Null {
+			return nil, true
+		}
+
+		return typedExpression.Value, true
+
+	default:
+		return nil, false
+	}
+}
+
+func isFunction(expression model.Expression, name string) bool {
+	if invocation, isInvocation := expression.(*model.FunctionInvocation); isInvocation {
+		return strings.EqualFold(invocation.Name, name)
+	}
+
+	return false
+}
+
+func functionArgument(invocation *model.FunctionInvocation) (model.Expression, error) {
+	if len(invocation.Arguments) != 1 {
+		return nil, fmt.Errorf("%w: function %s expects exactly one argument", ErrUnsupportedExpression, invocation.Name)
+	}
+
+	return invocation.Arguments[0], nil
+}
+
+// propertyLookup renders a property lookup. Properties are rendered as JSONB unless a text or timestamp
+// representation was requested.
+func (s *translator) propertyLookup(lookup *model.PropertyLookup, as sqlType) (string, sqlType, error) {
+	if len(lookup.Symbols) != 1 {
+		return "", sqlJSONB, fmt.Errorf("%w: unsupported property lookup", ErrUnsupportedExpression)
+	} else if symbol, err := variableSymbol(lookup.Atom); err != nil {
+		return "", sqlJSONB, err
+	} else {
+		propertyName := quoteLiteral(lookup.Symbols[0])
+
+		switch as {
+		case sqlText:
+			return "(" + symbol + ".properties ->> " + propertyName + ")", sqlText, nil
+
+		case sqlTimestamp:
+			return "(" + symbol + ".properties ->> " + propertyName + ")::timestamptz", sqlTimestamp, nil
+
+		default:
+			return "(" + symbol + ".properties -> " + propertyName + ")", sqlJSONB, nil
+		}
+	}
+}
+
+func (s *translator) functionInvocation(invocation *model.FunctionInvocation) (string, sqlType, error) {
+	if argument, err := functionArgument(invocation); err != nil {
+		return "", sqlJSONB, err
+	} else {
+		switch strings.ToLower(invocation.Name) {
+		case "id":
+			if symbol, err := variableSymbol(argument); err != nil {
+				return "", sqlJSONB, err
+			} else {
+				return symbol + ".id", sqlInt8, nil
+			}
+
+		case "labels":
+			if symbol, err := variableSymbol(argument); err != nil {
+				return "", sqlJSONB, err
+			} else if !isNodeSymbol(symbol) {
+				return "", sqlJSONB, fmt.Errorf("%w: labels() expects a node reference", ErrUnsupportedExpression)
+			} else {
+				return symbol + ".kinds", sqlTextArray, nil
+			}
+
+		case "type":
+			if symbol, err := variableSymbol(argument); err != nil {
+				return "", sqlJSONB, err
+			} else if symbol != query.RelationshipSymbol {
+				return "", sqlJSONB, fmt.Errorf("%w: type() expects a relationship reference", ErrUnsupportedExpression)
+			} else {
+				return symbol + ".kind", sqlText, nil
+			}
+
+		case "tolower", "toupper":
+			if rendered, _, err := s.value(argument, sqlText); err != nil {
+				return "", sqlJSONB, err
+			} else if strings.EqualFold(invocation.Name, "tolower") {
+				return "lower(" + rendered + ")", sqlText, nil
+			} else {
+				return "upper(" + rendered + ")", sqlText, nil
+			}
+
+		default:
+			return "", sqlJSONB, fmt.Errorf("%w: function %s", ErrUnsupportedExpression, invocation.Name)
+		}
+	}
+}
+
+// value renders an expression that produces a value. The given type is a hint for expressions that may be rendered
+// as more than one SQL type.
+func (s *translator) value(expression model.Expression, as sqlType) (string, sqlType, error) {
+	switch typedExpression := expression.(type) {
+	case *model.PropertyLookup:
+		return s.propertyLookup(typedExpression, as)
+
+	case *model.FunctionInvocation:
+		return s.functionInvocation(typedExpression)
+
+	case *model.Parenthetical:
+		if rendered, renderedType, err := s.value(typedExpression.Expression, as); err != nil {
+			return "", sqlJSONB, err
+		} else {
+			return "(" + rendered + ")", renderedType, nil
+		}
+
+	case *model.Parameter, *model.Literal:
+		if value, _ := parameterValue(typedExpression); value == nil {
+			return "null", as, nil
+		} else if rendered, err := s.parameter(value, as); err != nil {
+			return "", sqlJSONB, err
+		} else {
+			return rendered, as, nil
+		}
+
+	default:
+		return "", sqlJSONB, fmt.Errorf("%w: %T", ErrUnsupportedExpression, expression)
+	}
+}
+
+func isScalarJSON(value any) bool {
+	switch normalizeValue(value).(type) {
+	case string, bool, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+
+	default:
+		return false
+	}
+}
+
+func comparisonOperator(operator model.Operator) (string, bool) {
+	switch operator {
+	case model.OperatorEquals:
+		return "=", true
+	case model.OperatorNotEquals:
+		return "<>", true
+	case model.OperatorGreaterThan:
+		return ">", true
+	case model.OperatorGreaterThanOrEqualTo:
+		return ">=", true
+	case model.OperatorLessThan:
+		return "<", true
+	case model.OperatorLessThanOrEqualTo:
+		return "<=", true
+	default:
+		return "", false
+	}
+}
+
+// stringComparison renders the Cypher string operators as LIKE and regular expression matches against the text
+// representation of the left operand.
+func (s *translator) stringComparison(left model.Expression, operator model.Operator, right model.Expression) (string, error) {
+	if renderedLeft, _, err := s.value(left, sqlText); err != nil {
+		return "", err
+	} else if rightValue, isValue := parameterValue(right); !isValue {
+		if renderedRight, _, err := s.value(right, sqlText); err != nil {
+			return "", err
+		} else {
+			switch operator {
+			case model.OperatorStartsWith:
+				return renderedLeft + " like " + renderedRight + " || '%'", nil
+			case model.OperatorEndsWith:
+				return renderedLeft + " like '%' || " + renderedRight, nil
+			case model.OperatorContains:
+				return renderedLeft + " like '%' || " + renderedRight + " || '%'", nil
+			default:
+				return renderedLeft + " ~ ('^(?:' || " + renderedRight + " || ')$')", nil
+			}
+		}
+	} else if rightValue == nil {
+		return "null", nil
+	} else if stringValue, typeOK := asString(normalizeValue(rightValue)); !typeOK {
+		return "", fmt.Errorf("unexpected type %T for string operator %s", rightValue, operator)
+	} else {
+		switch operator {
+		case model.OperatorStartsWith:
+			return renderedLeft + " like " + s.bind(likeEscaper.Replace(stringValue)+"%") + "::text", nil
+		case model.OperatorEndsWith:
+			return renderedLeft + " like " + s.bind("%"+likeEscaper.Replace(stringValue)) + "::text", nil
+		case model.OperatorContains:
+			return renderedLeft + " like " + s.bind("%"+likeEscaper.Replace(stringValue)+"%") + "::text", nil
+		default:
+			// Cypher regular expressions must match the entire string
+			return renderedLeft + " ~ " + s.bind("^(?:"+stringValue+")$") + "::text", nil
+		}
+	}
+}
+
+// membership renders the in operator. Identifiers and kinds are compared against typed arrays while properties are
+// tested for containment in a JSONB array.
+func (s *translator) membership(left model.Expression, right model.Expression) (string, error) {
+	if renderedLeft, leftType, err := s.value(left, sqlJSONB); err != nil {
+		return "", err
+	} else if rightValue, isValue := parameterValue(right); !isValue {
+		return "", fmt.Errorf("%w: in operator expects a parameter list", ErrUnsupportedExpression)
+	} else if rightValue == nil {
+		return "null", nil
+	} else {
+		switch leftType {
+		case sqlInt8:
+			if renderedRight, err := s.parameter(rightValue, sqlInt8Array); err != nil {
+				return "", err
+			} else {
+				return renderedLeft + " = any(" + renderedRight + ")", nil
+			}
+
+		case sqlText:
+			if renderedRight, err := s.parameter(rightValue, sqlTextArray); err != nil {
+				return "", err
+			} else {
+				return renderedLeft + " = any(" + renderedRight + ")", nil
+			}
+
+		case sqlJSONB:
+			if renderedRight, err := s.parameter(rightValue, sqlJSONB); err != nil {
+				return "", err
+			} else {
+				return renderedRight + " @> jsonb_build_array(" + renderedLeft + ")", nil
+			}
+
+		default:
+			return "", fmt.Errorf("%w: in operator for left operand %s", ErrUnsupportedExpression, renderedLeft)
+		}
+	}
+}
+
+func (s *translator) comparisonPartial(left model.Expression, partial *model.PartialComparison) (string, error) {
+	switch partial.Operator {
+	case model.OperatorStartsWith, model.OperatorEndsWith, model.OperatorContains, model.OperatorRegexMatch:
+		return s.stringComparison(left, partial.Operator, partial.Right)
+
+	case model.OperatorIn:
+		return s.membership(left, partial.Right)
+
+	case model.OperatorIs, model.OperatorIsNot:
+		if rightValue, isValue := parameterValue(partial.Right); !isValue || rightValue != nil {
+			return "", fmt.Errorf("%w: %s expects a null literal", ErrUnsupportedExpression, partial.Operator)
+		} else if renderedLeft, _, err := s.value(left, sqlJSONB); err != nil {
+			return "", err
+		} else if partial.Operator == model.OperatorIs {
+			return renderedLeft + " is null", nil
+		} else {
+			return renderedLeft + " is not null", nil
+		}
+	}
+
+	operator, supported := comparisonOperator(partial.Operator)
+	if !supported {
+		return "", fmt.Errorf("%w: operator %s", ErrUnsupportedExpression, partial.Operator)
+	}
+
+	rightValue, rightIsValue := parameterValue(partial.Right)
+
+	if rightIsValue && rightValue == nil {
+		// Comparisons against null are always null
+		return "null", nil
+	}
+
+	if lookup, isLookup := left.(*model.PropertyLookup); isLookup && rightIsValue {
+		if _, isTime := rightValue.(time.Time); isTime {
+			// Timestamps are stored as RFC3339 strings and must be compared as timestamps
+			if renderedLeft, _, err := s.propertyLookup(lookup, sqlTimestamp); err != nil {
+				return "", err
+			} else if renderedRight, err := s.parameter(rightValue, sqlTimestamp); err != nil {
+				return "", err
+			} else {
+				return renderedLeft + " " + operator + " " + renderedRight, nil
+			}
+		}
+
+		if partial.Operator == model.OperatorEquals && isScalarJSON(rightValue) {
+			// Equality against a scalar is rendered as a containment check so that it may be served by the GIN index
+			// on the properties column
+			if symbol, err := variableSymbol(lookup.Atom); err != nil {
+				return "", err
+			} else if len(lookup.Symbols) != 1 {
+				return "", fmt.Errorf("%w: unsupported property lookup", ErrUnsupportedExpression)
+			} else if renderedRight, err := s.parameter(map[string]any{lookup.Symbols[0]: rightValue}, sqlJSONB); err != nil {
+				return "", err
+			} else {
+				return symbol + ".properties @> " + renderedRight, nil
+			}
+		}
+	}
+
+	if renderedLeft, leftType, err := s.value(left, sqlJSONB); err != nil {
+		return "", err
+	} else if renderedRight, _, err := s.value(partial.Right, leftType); err != nil {
+		return "", err
+	} else {
+		return renderedLeft + " " + operator + " " + renderedRight, nil
+	}
+}
+
+func (s *translator) comparison(comparison *model.Comparison) (string, error) {
+	var (
+		left     = comparison.Left
+		rendered []string
+	)
+
+	// Chained comparisons such as a < b < c are rendered as a conjunction of each pairwise comparison
+	for _, partial := range comparison.Partials {
+		if renderedPartial, err := s.comparisonPartial(left, partial); err != nil {
+			return "", err
+		} else {
+			rendered = append(rendered, renderedPartial)
+		}
+
+		left = partial.Right
+	}
+
+	if len(rendered) == 1 {
+		return rendered[0], nil
+	}
+
+	return "(" + strings.Join(rendered, " and ") + ")", nil
+}
+
+func (s *translator) kindMatcher(matcher *model.KindMatcher) (string, error) {
+	if symbol, err := variableSymbol(matcher.Reference); err != nil {
+		return "", err
+	} else if renderedKinds, err := s.parameter(matcher.Kinds, sqlTextArray); err != nil {
+		return "", err
+	} else if isNodeSymbol(symbol) {
+		// Nodes must have all the given kinds
+		return symbol + ".kinds @> " + renderedKinds, nil
+	} else {
+		// Relationships have a single kind that must be one of the given kinds
+		return symbol + ".kind = any(" + renderedKinds + ")", nil
+	}
+}
+
+// relationshipPattern renders a pattern predicate as an existence check against the edge table. Only the single hop
+// patterns created by query.HasRelationships are supported.
+func (s *translator) relationshipPattern(patternParts []*model.PatternPart) (string, error) {
+	var rendered []string
+
+	for _, patternPart := range patternParts {
+		if len(patternPart.PatternElements) != 3 {
+			return "", fmt.Errorf("%w: only single hop relationship patterns are supported", ErrUnsupportedExpression)
+		} else if nodePattern, isNodePattern := patternPart.PatternElements[0].AsNodePattern(); !isNodePattern || !isNodeSymbol(nodePattern.Binding) {
+			return "", fmt.Errorf("%w: relationship patterns must start with a bound node", ErrUnsupportedExpression)
+		} else if relationshipPattern, isRelationshipPattern := patternPart.PatternElements[1].AsRelationshipPattern(); !isRelationshipPattern {
+			return "", fmt.Errorf("%w: expected a relationship pattern", ErrUnsupportedExpression)
+		} else {
+			var (
+				symbol    = nodePattern.Binding
+				predicate string
+			)
+
+			switch relationshipPattern.Direction {
+			case graph.DirectionOutbound:
+				predicate = "edge.start_id = " + symbol + ".id"
+
+			case graph.DirectionInbound:
+				predicate = "edge.end_id = " + symbol + ".id"
+
+			default:
+				predicate = "(edge.start_id = " + symbol + ".id or edge.end_id = " + symbol + ".id)"
+			}
+
+			if len(relationshipPattern.Kinds) > 0 {
+				if renderedKinds, err := s.parameter(relationshipPattern.Kinds, sqlTextArray); err != nil {
+					return "", err
+				} else {
+					predicate += " and edge.kind = any(" + renderedKinds + ")"
+				}
+			}
+
+			rendered = append(rendered, "exists (select 1 from edge where "+predicate+")")
+		}
+	}
+
+	return strings.Join(rendered, " and "), nil
+}
+
+func isStringComparison(expression model.Expression) bool {
+	if comparison, isComparison := expression.(*model.Comparison); isComparison && len(comparison.Partials) > 0 {
+		switch comparison.FirstPartial().Operator {
+		case model.OperatorStartsWith, model.OperatorEndsWith, model.OperatorContains:
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *translator) joined(expressions []model.Expression, operator string) (string, error) {
+	var rendered []string
+
+	for _, expression := range expressions {
+		if renderedExpression, err := s.predicate(expression); err != nil {
+			return "", err
+		} else if renderedExpression != "" {
+			rendered = append(rendered, renderedExpression)
+		}
+	}
+
+	switch len(rendered) {
+	case 0:
+		return "", nil
+
+	case 1:
+		return rendered[0], nil
+
+	default:
+		return "(" + strings.Join(rendered, " "+operator+" ") + ")", nil
+	}
+}
+
+// predicate renders a boolean expression. Empty expressions render as an empty string.
+func (s *translator) predicate(expression graph.Criteria) (string, error) {
+	switch typedExpression := expression.(type) {
+	case nil:
+		return "", nil
+
+	case *model.Where:
+		return s.joined(typedExpression.Expressions, "and")
+
+	case *model.Conjunction:
+		return s.joined(typedExpression.Expressions, "and")
+
+	case *model.Disjunction:
+		return s.joined(typedExpression.Expressions, "or")
+
+	case *model.ExclusiveDisjunction:
+		return s.joined(typedExpression.Expressions, "<>")
+
+	case *model.Parenthetical:
+		return s.predicate(typedExpression.Expression)
+
+	case *model.Negation:
+		if rendered, err := s.predicate(typedExpression.Expression); err != nil {
+			return "", err
+		} else if rendered == "" {
+			return "", nil
+		} else if isStringComparison(typedExpression.Expression) {
+			// Negated string comparisons against null values are true, matching the Neo4j query builder's rewrite
+			return "not coalesce(" + rendered + ", false)", nil
+		} else {
+			return "not (" + rendered + ")", nil
+		}
+
+	case *model.KindMatcher:
+		return s.kindMatcher(typedExpression)
+
+	case *model.Comparison:
+		return s.comparison(typedExpression)
+
+	case []*model.PatternPart:
+		return s.relationshipPattern(typedExpression)
+
+	case *model.Literal:
+		if boolValue, typeOK := typedExpression.Value.(bool); typeOK {
+			return strconv.FormatBool(boolValue), nil
+		}
+
+		return "", fmt.Errorf("%w: literal %v is not a boolean", ErrUnsupportedExpression, typedExpression.Value)
+
+	default:
+		return "", fmt.Errorf("%w: %T", ErrUnsupportedExpression, expression)
+	}
+}
+
+// referencedSymbols returns the set of query symbols referenced by the given criteria.
+func referencedSymbols(criteria ...graph.Criteria) (map[string]struct{}, error) {
+	symbols := map[string]struct{}{}
+
+	for _, nextCriteria := range criteria {
+		if err := model.Walk(nextCriteria, func(parent, element any) error {
+			switch typedElement := element.(type) {
+			case *model.Variable:
+				symbols[typedElement.Symbol] = struct{}{}
+
+			case *model.NodePattern:
+				if typedElement.Binding != "" {
+					symbols[typedElement.Binding] = struct{}{}
+				}
+			}
+
+			return nil
+		}, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return symbols, nil
+}
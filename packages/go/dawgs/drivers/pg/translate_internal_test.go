@@ -0,0 +1,209 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	userKind     = graph.StringKind("User")
+	memberOfKind = graph.StringKind("MemberOf")
+)
+
+func renderSelection(t *testing.T, spec *querySpec, criteria ...graph.Criteria) statement {
+	for _, nextCriteria := range criteria {
+		spec.apply(nextCriteria)
+	}
+
+	require.Nil(t, spec.prepare())
+
+	rendered, err := spec.selection()
+	require.Nil(t, err)
+
+	return rendered
+}
+
+func TestQuerySpec_NodeSelection(t *testing.T) {
+	rendered := renderSelection(t, &querySpec{},
+		query.Where(query.And(
+			query.Kind(query.Node(), userKind),
+			query.Equals(query.NodeProperty("name"), "bob"),
+			query.Not(query.StringContains(query.NodeProperty("description"), "50%")),
+			query.After(query.NodeProperty("lastseen"), time.Unix(0, 0)),
+		)),
+		query.Returning(query.Node()),
+		query.OrderBy(query.Order(query.NodeProperty("name"), query.Descending())),
+		query.Offset(10),
+		query.Limit(5),
+	)
+
+	require.Equal(t, "select n.id, n.kinds, n.properties from node n where "+
+		"(n.kinds @> $1::text[] and n.properties @> $2::jsonb and not coalesce((n.properties ->> 'description') like $3::text, false) and "+
+		"(n.properties ->> 'lastseen')::timestamptz > $4::timestamptz) "+
+		"order by (n.properties -> 'name') desc offset 10 limit 5", rendered.sql)
+
+	require.Equal(t, []any{
+		[]string{"User"},
+		[]byte(`{"name":"bob"}`),
+		`%50\%%`,
+		time.Unix(0, 0).UTC(),
+	}, rendered.parameters)
+
+	require.Equal(t, []resultColumn{nodeColumn}, rendered.columns)
+}
+
+func TestQuerySpec_Count(t *testing.T) {
+	rendered := renderSelection(t, &querySpec{},
+		query.Where(query.InIDs(query.NodeID(), 1, 2)),
+		query.Returning(query.Count(query.Node())),
+		query.OrderBy(query.Order(query.Node(), query.Ascending())),
+	)
+
+	// Ordering is dropped for aggregate projections
+	require.Equal(t, "select to_jsonb(count(*)) from node n where n.id = any($1::int8[])", rendered.sql)
+	require.Equal(t, []any{[]int64{1, 2}}, rendered.parameters)
+}
+
+func TestQuerySpec_RelationshipSelection(t *testing.T) {
+	rendered := renderSelection(t, &querySpec{relationships: true},
+		query.Where(query.And(
+			query.Kind(query.Relationship(), memberOfKind),
+			query.InIDs(query.StartID(), 1),
+			query.Kind(query.End(), userKind),
+		)),
+		query.Returning(query.Relationship(), query.End()),
+	)
+
+	require.Equal(t, "select r.id, r.start_id, r.end_id, r.kind, r.properties, e.id, e.kinds, e.properties "+
+		"from edge r join node s on s.id = r.start_id join node e on e.id = r.end_id "+
+		"where (r.kind = any($1::text[]) and s.id = any($2::int8[]) and e.kinds @> $3::text[])", rendered.sql)
+
+	require.Equal(t, []resultColumn{relationshipColumn, nodeColumn}, rendered.columns)
+}
+
+func TestQuerySpec_AmbiguousVariables(t *testing.T) {
+	spec := &querySpec{}
+	spec.apply(query.Where(query.Equals(query.StartProperty("name"), "bob")))
+
+	require.ErrorIs(t, spec.prepare(), ErrAmbiguousQueryVariables)
+}
+
+func TestQuerySpec_UpdateAndDeletion(t *testing.T) {
+	spec := &querySpec{relationships: true}
+	spec.apply(query.Where(query.Equals(query.StartProperty("name"), "bob")))
+
+	update, err := spec.update(graph.NewProperties().Set("enabled", true).Delete("description"))
+	require.Nil(t, err)
+	require.Equal(t, "update edge r set properties = (r.properties || $1::jsonb) - $2::text[] from node s "+
+		"where s.id = r.start_id and s.properties @> $3::jsonb", update.sql)
+	require.Equal(t, []any{[]byte(`{"enabled":true}`), []string{"description"}, []byte(`{"name":"bob"}`)}, update.parameters)
+
+	deletion, err := spec.deletion()
+	require.Nil(t, err)
+	require.Equal(t, "delete from edge r using node s where s.id = r.start_id and s.properties @> $1::jsonb", deletion.sql)
+}
+
+func TestTranslator_Predicates(t *testing.T) {
+	testCases := []struct {
+		Criteria   graph.Criteria
+		SQL        string
+		Parameters []any
+	}{{
+		Criteria:   query.In(query.NodeProperty("name"), []string{"a", "b"}),
+		SQL:        "$1::jsonb @> jsonb_build_array((n.properties -> 'name'))",
+		Parameters: []any{[]byte(`["a","b"]`)},
+	}, {
+		Criteria:   query.KindIn(query.Relationship(), memberOfKind, graph.StringKind("AdminTo")),
+		SQL:        "(r.kind = any($1::text[]) or r.kind = any($2::text[]))",
+		Parameters: []any{[]string{"MemberOf"}, []string{"AdminTo"}},
+	}, {
+		Criteria: query.IsNull(query.NodeProperty("name")),
+		SQL:      "(n.properties -> 'name') is null",
+	}, {
+		Criteria:   query.Or(query.GreaterThan(query.NodeProperty("count"), 1), query.StringStartsWith(query.NodeProperty("name"), "a_")),
+		SQL:        "((n.properties -> 'count') > $1::jsonb or (n.properties ->> 'name') like $2::text)",
+		Parameters: []any{[]byte(`1`), `a\_%`},
+	}, {
+		Criteria:   query.Equals(query.NodeProperty("name"), nil),
+		SQL:        "null",
+		Parameters: nil,
+	}}
+
+	for _, testCase := range testCases {
+		translator := &translator{}
+
+		rendered, err := translator.predicate(testCase.Criteria)
+		require.Nil(t, err)
+		require.Equal(t, testCase.SQL, rendered)
+		require.Equal(t, testCase.Parameters, translator.parameters)
+	}
+}
+
+func TestShortestPathCriteria(t *testing.T) {
+	startCriteria, endCriteria, relationshipCriteria, err := shortestPathCriteria(query.And(
+		query.InIDs(query.StartID(), 1),
+		query.InIDs(query.EndID(), 2),
+		query.Kind(query.Relationship(), memberOfKind),
+	))
+
+	require.Nil(t, err)
+	require.NotNil(t, startCriteria)
+	require.NotNil(t, endCriteria)
+	require.NotNil(t, relationshipCriteria)
+
+	rendered, err := shortestPathStatement(startCriteria, endCriteria, relationshipCriteria)
+	require.Nil(t, err)
+	require.Contains(t, rendered.sql, "select s.id, s.id, 0 from node s where s.id = any($1::int8[])")
+	require.Contains(t, rendered.sql, "where t.depth < 15 and r.kind = any($2::text[])")
+	require.Contains(t, rendered.sql, "to_jsonb(coalesce(e.id = any($3::int8[]), false))")
+	require.Equal(t, []resultColumn{valueColumn, valueColumn, relationshipColumn}, rendered.columns)
+
+	// Terms may not relate the start and end nodes of a path to each other
+	_, _, _, err = shortestPathCriteria(query.Or(query.Kind(query.Start(), userKind), query.Kind(query.End(), userKind)))
+	require.NotNil(t, err)
+}
+
+func TestTraversalStatement(t *testing.T) {
+	rendered, err := traversalStatement(TraversalPlan{
+		Root:      1,
+		Direction: graph.DirectionInbound,
+		BranchQuery: func() graph.Criteria {
+			return query.Kind(query.Relationship(), memberOfKind)
+		},
+		MaxDepth: 3,
+		Limit:    10,
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, "with recursive traversal (next_id, depth, path, nodes) as ("+
+		"select r.start_id, 1, array[r.id], array[r.end_id, r.start_id] from edge r where r.end_id = $1::int8 and r.start_id <> r.end_id and r.kind = any($2::text[]) "+
+		"union all "+
+		"select r.start_id, t.depth + 1, t.path || r.id, t.nodes || r.start_id from traversal t join edge r on r.end_id = t.next_id where r.start_id <> all(t.nodes) and t.depth < 3 and r.kind = any($2::text[])"+
+		") select to_jsonb(t.path) from traversal t where t.depth >= 3 or not exists ("+
+		"select 1 from edge r where r.end_id = t.next_id and r.start_id <> all(t.nodes) and r.kind = any($2::text[])"+
+		") order by t.path limit 10", rendered.sql)
+	require.Equal(t, []any{int64(1), []string{"MemberOf"}}, rendered.parameters)
+
+	_, err = traversalStatement(TraversalPlan{Root: 1, Direction: graph.DirectionBoth})
+	require.NotNil(t, err)
+}
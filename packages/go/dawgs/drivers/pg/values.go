@@ -0,0 +1,263 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package pg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+)
+
+func asInt64(value any) (int64, error) {
+	switch typedValue := value.(type) {
+	case int:
+		return int64(typedValue), nil
+	case int8:
+		return int64(typedValue), nil
+	case int16:
+		return int64(typedValue), nil
+	case int32:
+		return int64(typedValue), nil
+	case int64:
+		return typedValue, nil
+	case uint8:
+		return int64(typedValue), nil
+	case uint16:
+		return int64(typedValue), nil
+	case uint32:
+		return int64(typedValue), nil
+	case uint64:
+		if typedValue > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", typedValue)
+		}
+
+		return int64(typedValue), nil
+	case graph.ID:
+		return typedValue.Int64(), nil
+	case float64:
+		if typedValue != math.Trunc(typedValue) {
+			return 0, fmt.Errorf("value %f is not an integer", typedValue)
+		}
+
+		return int64(typedValue), nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T will not negotiate to int64", value)
+	}
+}
+
+func asFloat64(value any) (float64, error) {
+	switch typedValue := value.(type) {
+	case float32:
+		return float64(typedValue), nil
+	case float64:
+		return typedValue, nil
+	default:
+		if int64Value, err := asInt64(value); err != nil {
+			return 0, fmt.Errorf("unexpected type %T will not negotiate to float64", value)
+		} else {
+			return float64(int64Value), nil
+		}
+	}
+}
+
+func asString(value any) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case graph.Kind:
+		return typedValue.String(), true
+	default:
+		return "", false
+	}
+}
+
+func asStrings(value any) ([]string, bool) {
+	switch typedValue := value.(type) {
+	case []string:
+		return typedValue, true
+
+	case graph.Kinds:
+		return typedValue.Strings(), true
+
+	case []any:
+		strs := make([]string, len(typedValue))
+
+		for idx, nextValue := range typedValue {
+			if str, typeOK := asString(nextValue); !typeOK {
+				return nil, false
+			} else {
+				strs[idx] = str
+			}
+		}
+
+		return strs, true
+
+	default:
+		return nil, false
+	}
+}
+
+func asTime(value any) (time.Time, error) {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return typedValue, nil
+
+	case string:
+		return time.Parse(time.RFC3339Nano, typedValue)
+
+	case float64:
+		return time.Unix(int64(typedValue), 0), nil
+
+	case int64:
+		return time.Unix(typedValue, 0), nil
+
+	default:
+		return time.Time{}, fmt.Errorf("unexpected type %T will not negotiate to time.Time", value)
+	}
+}
+
+func asKinds(value any) (graph.Kinds, error) {
+	switch typedValue := value.(type) {
+	case graph.Kinds:
+		return typedValue.Copy(), nil
+
+	default:
+		if strs, isStrings := asStrings(value); !isStrings {
+			return nil, fmt.Errorf("unexpected type %T will not negotiate to graph.Kinds", value)
+		} else {
+			return graph.StringsToKinds(strs), nil
+		}
+	}
+}
+
+// convertNumbers replaces the json.Number values of a decoded JSON document with int64 values where the number is
+// integral and float64 values otherwise. This preserves the integer property types that callers expect.
+func convertNumbers(value any) any {
+	switch typedValue := value.(type) {
+	case json.Number:
+		if int64Value, err := typedValue.Int64(); err == nil {
+			return int64Value
+		} else if float64Value, err := typedValue.Float64(); err == nil {
+			return float64Value
+		} else {
+			return typedValue.String()
+		}
+
+	case map[string]any:
+		for key, nextValue := range typedValue {
+			typedValue[key] = convertNumbers(nextValue)
+		}
+
+		return typedValue
+
+	case []any:
+		for idx, nextValue := range typedValue {
+			typedValue[idx] = convertNumbers(nextValue)
+		}
+
+		return typedValue
+
+	default:
+		return value
+	}
+}
+
+func decodeJSON(content []byte) (any, error) {
+	if content == nil {
+		return nil, nil
+	}
+
+	var (
+		value   any
+		decoder = json.NewDecoder(bytes.NewReader(content))
+	)
+
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return convertNumbers(value), nil
+}
+
+func decodeProperties(content []byte) (*graph.Properties, error) {
+	if value, err := decodeJSON(content); err != nil {
+		return nil, err
+	} else if value == nil {
+		return graph.NewProperties(), nil
+	} else if propertyMap, typeOK := value.(map[string]any); !typeOK {
+		return nil, fmt.Errorf("unexpected type %T for properties", value)
+	} else {
+		return graph.AsProperties(propertyMap), nil
+	}
+}
+
+// splitProperties separates the given property values into the values to set and the keys to remove. As with the
+// Cypher += operator, a nil value removes the property.
+func splitProperties(values map[string]any) (map[string]any, []string) {
+	var (
+		set    = make(map[string]any, len(values))
+		remove = []string{}
+	)
+
+	for key, value := range values {
+		if value == nil {
+			remove = append(remove, key)
+		} else {
+			set[key] = normalizeValue(value)
+		}
+	}
+
+	return set, remove
+}
+
+// propertyChanges returns the modified properties and the keys of the deleted properties tracked by the given
+// properties.
+func propertyChanges(properties *graph.Properties) (map[string]any, []string) {
+	if properties == nil {
+		return map[string]any{}, []string{}
+	}
+
+	var (
+		modified = properties.ModifiedProperties()
+		set      = make(map[string]any, len(modified))
+		remove   = append([]string{}, properties.DeletedProperties()...)
+	)
+
+	for key, value := range modified {
+		set[key] = normalizeValue(value)
+	}
+
+	return set, remove
+}
+
+func marshalProperties(properties map[string]any) ([]byte, error) {
+	normalized := make(map[string]any, len(properties))
+
+	for key, value := range properties {
+		if value != nil {
+			normalized[key] = normalizeValue(value)
+		}
+	}
+
+	return json.Marshal(normalized)
+}
@@ -0,0 +1,283 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package traversal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/util/size"
+)
+
+// BidirectionalPlan describes a search for the shortest paths from any node in a set of sources to any node in a set
+// of targets. Criteria filters the relationships that may be traversed. MaxDepth limits the length of returned paths
+// and Limit caps the number of returned paths. Both are unbounded when zero.
+type BidirectionalPlan struct {
+	Sources  graph.NodeSet
+	Targets  graph.NodeSet
+	Criteria graph.Criteria
+	MaxDepth int
+	Limit    int
+}
+
+type labeledSegment struct {
+	rootID  graph.ID
+	segment *graph.PathSegment
+}
+
+// bidirectionalSearch is one side of a bidirectional breadth-first search. Every node reached is labeled with the
+// segment that first reached it from each of the search's roots so that the shortest path from each root is tracked
+// independently.
+type bidirectionalSearch struct {
+	direction graph.Direction
+	depth     int
+	roots     []*graph.PathSegment
+	reached   map[graph.ID]map[graph.ID]*graph.PathSegment
+	frontier  []labeledSegment
+}
+
+func newBidirectionalSearch(direction graph.Direction, roots graph.NodeSet) *bidirectionalSearch {
+	search := &bidirectionalSearch{
+		direction: direction,
+		reached:   map[graph.ID]map[graph.ID]*graph.PathSegment{},
+	}
+
+	for _, root := range roots {
+		rootSegment := graph.NewRootPathSegment(root)
+
+		search.roots = append(search.roots, rootSegment)
+		search.reached[root.ID] = map[graph.ID]*graph.PathSegment{
+			root.ID: rootSegment,
+		}
+		search.frontier = append(search.frontier, labeledSegment{
+			rootID:  root.ID,
+			segment: rootSegment,
+		})
+	}
+
+	return search
+}
+
+func (s *bidirectionalSearch) SizeOf() size.Size {
+	var treeSize size.Size
+
+	for _, root := range s.roots {
+		treeSize += root.SizeOf()
+	}
+
+	return treeSize
+}
+
+// expand fetches the relationships of the current frontier in a single query and descends into every node that has
+// not yet been reached from the same root. The delegate is called for each newly labeled segment.
+func (s *bidirectionalSearch) expand(tx graph.Transaction, criteria graph.Criteria, delegate func(next labeledSegment)) error {
+	var (
+		frontierByNode = map[graph.ID][]labeledSegment{}
+		frontierIDs    = make([]graph.ID, 0, len(s.frontier))
+		nextFrontier   []labeledSegment
+		filters        []graph.Criteria
+	)
+
+	for _, next := range s.frontier {
+		if _, seen := frontierByNode[next.segment.Node.ID]; !seen {
+			frontierIDs = append(frontierIDs, next.segment.Node.ID)
+		}
+
+		frontierByNode[next.segment.Node.ID] = append(frontierByNode[next.segment.Node.ID], next)
+	}
+
+	if criteria != nil {
+		filters = append(filters, criteria)
+	}
+
+	if s.direction == graph.DirectionOutbound {
+		filters = append(filters, query.InIDs(query.StartID(), frontierIDs...))
+	} else {
+		filters = append(filters, query.InIDs(query.EndID(), frontierIDs...))
+	}
+
+	// The direction of a fetch describes the relationship from the perspective of the returned node which is the
+	// reverse of the traversal direction
+	if fetchDirection, err := s.direction.Reverse(); err != nil {
+		return err
+	} else if err := tx.Relationships().Filter(query.And(filters...)).FetchDirection(fetchDirection, func(cursor graph.Cursor[graph.DirectionalResult]) error {
+		for result := range cursor.Chan() {
+			if trunkID, err := s.direction.Pick(result.Relationship); err != nil {
+				return err
+			} else {
+				for _, trunk := range frontierByNode[trunkID] {
+					labels, hasLabels := s.reached[result.Node.ID]
+
+					if !hasLabels {
+						labels = map[graph.ID]*graph.PathSegment{}
+						s.reached[result.Node.ID] = labels
+					} else if _, reachedFromRoot := labels[trunk.rootID]; reachedFromRoot {
+						continue
+					}
+
+					next := labeledSegment{
+						rootID:  trunk.rootID,
+						segment: trunk.segment.Descend(result.Node, result.Relationship),
+					}
+
+					labels[next.rootID] = next.segment
+					nextFrontier = append(nextFrontier, next)
+
+					delegate(next)
+				}
+			}
+		}
+
+		return cursor.Error()
+	}); err != nil {
+		return err
+	}
+
+	s.frontier = nextFrontier
+	s.depth++
+
+	return nil
+}
+
+// joinSegments joins a segment rooted at a source with a segment rooted at a target that both end at the same node
+func joinSegments(sourceSegment, targetSegment *graph.PathSegment) graph.Path {
+	path := sourceSegment.Path()
+
+	for cursor := targetSegment; cursor.Trunk != nil; cursor = cursor.Trunk {
+		path.Nodes = append(path.Nodes, cursor.Trunk.Node)
+		path.Edges = append(path.Edges, cursor.Edge)
+	}
+
+	return path
+}
+
+// ShortestPathsBetween returns the shortest path between every pair of source and target nodes in the given plan
+// that are connected, ordered by ascending path length.
+//
+// Outbound paths from the sources and inbound paths from the targets are searched with a bidirectional breadth-first
+// search that always expands the side with the smaller frontier. Each expansion fetches the relationships of an entire
+// frontier with a single query. Once a source and target pair is joined no shorter path may exist between them so the
+// search may stop as soon as Limit pairs have been joined.
+func ShortestPathsBetween(ctx context.Context, db graph.Database, plan BidirectionalPlan) (graph.PathSet, error) {
+	type pathKey struct {
+		sourceID graph.ID
+		targetID graph.ID
+	}
+
+	var (
+		paths          graph.PathSet
+		joined         = map[pathKey]struct{}{}
+		sourceSearch   = newBidirectionalSearch(graph.DirectionOutbound, plan.Sources)
+		targetSearch   = newBidirectionalSearch(graph.DirectionInbound, plan.Targets)
+		pathsAvailable = func() bool {
+			return plan.Limit <= 0 || len(paths) < plan.Limit
+		}
+	)
+
+	// joinIfReached joins the given segment with every segment of the other search that reached the same node
+	joinIfReached := func(next labeledSegment, other *bidirectionalSearch, fromSource bool) {
+		for otherRootID, otherSegment := range other.reached[next.segment.Node.ID] {
+			key := pathKey{
+				sourceID: next.rootID,
+				targetID: otherRootID,
+			}
+
+			sourceSegment, targetSegment := next.segment, otherSegment
+
+			if !fromSource {
+				key.sourceID, key.targetID = otherRootID, next.rootID
+				sourceSegment, targetSegment = otherSegment, next.segment
+			}
+
+			if key.sourceID == key.targetID {
+				continue
+			}
+
+			if _, isJoined := joined[key]; !isJoined {
+				joined[key] = struct{}{}
+				paths = append(paths, joinSegments(sourceSegment, targetSegment))
+			}
+		}
+	}
+
+	// Sources that are also targets are joined before any expansion
+	for _, source := range sourceSearch.frontier {
+		joinIfReached(source, targetSearch, true)
+	}
+
+	err := db.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		for pathsAvailable() && (plan.MaxDepth <= 0 || sourceSearch.depth+targetSearch.depth < plan.MaxDepth) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var (
+				searchSize         = sourceSearch.SizeOf() + targetSearch.SizeOf()
+				sourceFrontierSize = len(sourceSearch.frontier)
+				targetFrontierSize = len(targetSearch.frontier)
+			)
+
+			if searchSize > ops.TraversalMemoryLimit {
+				return fmt.Errorf("%w - Limit: %.2f MB - Memory In-Use: %.2f MB", ops.ErrTraversalMemoryLimit, ops.TraversalMemoryLimit.Mebibytes(), searchSize.Mebibytes())
+			}
+
+			if sourceFrontierSize == 0 && targetFrontierSize == 0 {
+				break
+			}
+
+			if targetFrontierSize == 0 || (sourceFrontierSize > 0 && sourceFrontierSize <= targetFrontierSize) {
+				if err := sourceSearch.expand(tx, plan.Criteria, func(next labeledSegment) {
+					joinIfReached(next, targetSearch, true)
+				}); err != nil {
+					return err
+				}
+			} else if err := targetSearch.expand(tx, plan.Criteria, func(next labeledSegment) {
+				joinIfReached(next, sourceSearch, false)
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Every path joined during the same expansion has the same length so paths are ranked by their source and target
+	// for stable results
+	sort.SliceStable(paths, func(i, j int) bool {
+		if len(paths[i].Edges) != len(paths[j].Edges) {
+			return len(paths[i].Edges) < len(paths[j].Edges)
+		} else if paths[i].Root().ID != paths[j].Root().ID {
+			return paths[i].Root().ID < paths[j].Root().ID
+		}
+
+		return paths[i].Terminal().ID < paths[j].Terminal().ID
+	})
+
+	if plan.Limit > 0 && len(paths) > plan.Limit {
+		paths = paths[:plan.Limit]
+	}
+
+	return paths, nil
+}
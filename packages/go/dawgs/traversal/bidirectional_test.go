@@ -0,0 +1,143 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package traversal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/traversal"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	kindEdge = graph.StringKind("Edge")
+)
+
+type bidirectionalGraph struct {
+	UserA    *graph.Node
+	UserB    *graph.Node
+	UserC    *graph.Node
+	Group    *graph.Node
+	Computer *graph.Node
+	DCA      *graph.Node
+	DCB      *graph.Node
+}
+
+// newBidirectionalGraph creates the following graph:
+//
+//	(UserA) -[Edge]-> (Group) -[Edge]-> (DCA)
+//	(UserB) -[Edge]-> (Group) -[Edge]-> (Computer) -[Edge]-> (DCB)
+//	(UserB) -[Edge]-> (DCB)
+//	(UserC) -[Excluded]-> (DCA)
+func newBidirectionalGraph(t *testing.T, db graph.Database) bidirectionalGraph {
+	var harness bidirectionalGraph
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(name string) *graph.Node {
+			node, err := tx.CreateNode(graph.AsProperties(map[string]any{"name": name}), kindNode)
+			require.Nil(t, err)
+
+			return node
+		}
+
+		newRelationship := func(start, end *graph.Node, kind graph.Kind) {
+			_, err := tx.CreateRelationshipByIDs(start.ID, end.ID, kind, graph.NewProperties())
+			require.Nil(t, err)
+		}
+
+		harness.UserA = newNode("user a")
+		harness.UserB = newNode("user b")
+		harness.UserC = newNode("user c")
+		harness.Group = newNode("group")
+		harness.Computer = newNode("computer")
+		harness.DCA = newNode("dc a")
+		harness.DCB = newNode("dc b")
+
+		newRelationship(harness.UserA, harness.Group, kindEdge)
+		newRelationship(harness.UserB, harness.Group, kindEdge)
+		newRelationship(harness.Group, harness.DCA, kindEdge)
+		newRelationship(harness.Group, harness.Computer, kindEdge)
+		newRelationship(harness.Computer, harness.DCB, kindEdge)
+		newRelationship(harness.UserB, harness.DCB, kindEdge)
+		newRelationship(harness.UserC, harness.DCA, kindExcluded)
+
+		return nil
+	}))
+
+	return harness
+}
+
+func TestShortestPathsBetween(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newBidirectionalGraph(t, db)
+		plan    = traversal.BidirectionalPlan{
+			Sources:  graph.NewNodeSet(harness.UserA, harness.UserB, harness.UserC),
+			Targets:  graph.NewNodeSet(harness.DCA, harness.DCB),
+			Criteria: query.Not(query.Kind(query.Relationship(), kindExcluded)),
+		}
+	)
+
+	paths, err := traversal.ShortestPathsBetween(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 4)
+
+	// Paths are ranked by length and then by their source and target
+	require.Equal(t, []graph.ID{harness.UserB.ID, harness.DCB.ID}, pathNodeIDs(paths[0]))
+	require.Equal(t, []graph.ID{harness.UserA.ID, harness.Group.ID, harness.DCA.ID}, pathNodeIDs(paths[1]))
+	require.Equal(t, []graph.ID{harness.UserB.ID, harness.Group.ID, harness.DCA.ID}, pathNodeIDs(paths[2]))
+	require.Equal(t, []graph.ID{harness.UserA.ID, harness.Group.ID, harness.Computer.ID, harness.DCB.ID}, pathNodeIDs(paths[3]))
+	require.Len(t, paths[3].Edges, 3)
+
+	// Cap the number of paths returned
+	plan.Limit = 2
+
+	paths, err = traversal.ShortestPathsBetween(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 2)
+	require.Equal(t, []graph.ID{harness.UserB.ID, harness.DCB.ID}, pathNodeIDs(paths[0]))
+	require.Equal(t, []graph.ID{harness.UserA.ID, harness.Group.ID, harness.DCA.ID}, pathNodeIDs(paths[1]))
+
+	// Limit the length of returned paths
+	plan.Limit = 0
+	plan.MaxDepth = 2
+
+	paths, err = traversal.ShortestPathsBetween(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 3)
+
+	// Nodes that are both a source and a target are not joined with themselves
+	paths, err = traversal.ShortestPathsBetween(context.Background(), db, traversal.BidirectionalPlan{
+		Sources: graph.NewNodeSet(harness.UserA),
+		Targets: graph.NewNodeSet(harness.UserA, harness.DCA),
+	})
+	require.Nil(t, err)
+	require.Len(t, paths, 1)
+	require.Equal(t, []graph.ID{harness.UserA.ID, harness.Group.ID, harness.DCA.ID}, pathNodeIDs(paths[0]))
+
+	// Without relationship criteria the excluded relationship is traversed
+	plan.Criteria = nil
+
+	paths, err = traversal.ShortestPathsBetween(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 4)
+	require.Equal(t, []graph.ID{harness.UserC.ID, harness.DCA.ID}, pathNodeIDs(paths[1]))
+}
@@ -0,0 +1,348 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package traversal
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/ops"
+	"github.com/specterops/bloodhound/dawgs/query"
+)
+
+// CostFunction returns the cost of descending into the given path segment. The cost is added to the cost of the path
+// that the segment extends. Returning false excludes the segment from traversal. Costs must not be negative.
+type CostFunction = func(next *graph.PathSegment) (float64, bool)
+
+// KindCostFunction is a CostFunction constructor that assigns a cost to each segment by the kind of its edge. Kinds
+// not present in the given cost map are assigned the default cost.
+func KindCostFunction(kindCosts map[string]float64, defaultCost float64) CostFunction {
+	return func(next *graph.PathSegment) (float64, bool) {
+		if cost, hasCost := kindCosts[next.Edge.Kind.String()]; hasCost {
+			return cost, true
+		}
+
+		return defaultCost, true
+	}
+}
+
+// WeightedPlan describes a search for the K cheapest paths from a root node to any node that satisfies the target
+// function.
+type WeightedPlan struct {
+	Root      *graph.Node
+	Direction graph.Direction
+	Criteria  graph.Criteria
+	Cost      CostFunction
+	Target    func(node *graph.Node) bool
+	K         int
+	MaxDepth  int
+}
+
+// WeightedPath is a path along with the summed cost of its segments.
+type WeightedPath struct {
+	Path graph.Path
+	Cost float64
+}
+
+type weightedSegment struct {
+	segment *graph.PathSegment
+	cost    float64
+	depth   int
+}
+
+// weightedSegmentHeap orders segments by their path cost and then by their depth so that cheaper and shorter paths
+// are expanded first
+type weightedSegmentHeap []weightedSegment
+
+func (s weightedSegmentHeap) Len() int {
+	return len(s)
+}
+
+func (s weightedSegmentHeap) Less(i, j int) bool {
+	if s[i].cost == s[j].cost {
+		return s[i].depth < s[j].depth
+	}
+
+	return s[i].cost < s[j].cost
+}
+
+func (s weightedSegmentHeap) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s *weightedSegmentHeap) Push(value any) {
+	*s = append(*s, value.(weightedSegment))
+}
+
+func (s *weightedSegmentHeap) Pop() any {
+	var (
+		current = *s
+		last    = len(current) - 1
+		popped  = current[last]
+	)
+
+	*s = current[:last]
+	return popped
+}
+
+// weightedExpansionCriteria returns the criteria for fetching the relationships that continue a path from the given
+// node in the given direction
+func weightedExpansionCriteria(direction graph.Direction, criteria graph.Criteria, node *graph.Node) graph.Criteria {
+	nodeCriteria := query.Equals(query.EndID(), node.ID)
+
+	if direction == graph.DirectionOutbound {
+		nodeCriteria = query.Equals(query.StartID(), node.ID)
+	}
+
+	if criteria == nil {
+		return nodeCriteria
+	}
+
+	return query.And(criteria, nodeCriteria)
+}
+
+// weightedState identifies a settled node. Nodes are settled once per depth when the search is depth limited since a
+// cheaper but deeper path to a node may not be extended as far as a more expensive but shallower one.
+type weightedState struct {
+	node  graph.ID
+	depth int
+}
+
+// weightedPathKey returns a key that uniquely identifies the given path by its relationships
+func weightedPathKey(path graph.Path) string {
+	key := strings.Builder{}
+
+	for _, edge := range path.Edges {
+		key.WriteString(edge.ID.String())
+		key.WriteString(",")
+	}
+
+	return key.String()
+}
+
+// sharesRootPath returns true if both paths traverse the same relationships up to the given depth
+func sharesRootPath(path, other graph.Path, depth int) bool {
+	if len(path.Edges) <= depth || len(other.Edges) <= depth {
+		return false
+	}
+
+	for idx := 0; idx < depth; idx++ {
+		if path.Edges[idx].ID != other.Edges[idx].ID {
+			return false
+		}
+	}
+
+	return true
+}
+
+type weightedSearch struct {
+	ctx            context.Context
+	tx             graph.Transaction
+	plan           WeightedPlan
+	fetchDirection graph.Direction
+}
+
+// descend rebuilds the given path up to the given depth as a new path tree and returns its terminal segment along
+// with the cost of reaching it
+func (s weightedSearch) descend(path graph.Path, depth int) weightedSegment {
+	next := weightedSegment{
+		segment: graph.NewRootPathSegment(path.Nodes[0]),
+	}
+
+	for idx := 0; idx < depth; idx++ {
+		next.segment = next.segment.Descend(path.Nodes[idx+1], path.Edges[idx])
+		next.depth++
+
+		if cost, traversable := s.plan.Cost(next.segment); traversable {
+			next.cost += cost
+		}
+	}
+
+	return next
+}
+
+// cheapestPath returns the cheapest acyclic path that extends the given trunk to a target node without traversing any
+// of the excluded relationships. Only the relationships of settled nodes are fetched, which keeps the search
+// independent of the underlying graph driver.
+func (s weightedSearch) cheapestPath(trunk weightedSegment, excludedEdges map[graph.ID]struct{}) (weightedSegment, bool, error) {
+	var (
+		root     = trunk.segment
+		settled  = map[weightedState]struct{}{}
+		frontier = &weightedSegmentHeap{trunk}
+	)
+
+	for root.Trunk != nil {
+		root = root.Trunk
+	}
+
+	for frontier.Len() > 0 {
+		if err := s.ctx.Err(); err != nil {
+			return weightedSegment{}, false, err
+		}
+
+		if treeSize := root.SizeOf(); treeSize > ops.TraversalMemoryLimit {
+			return weightedSegment{}, false, fmt.Errorf("%w - Limit: %.2f MB - Memory In-Use: %.2f MB", ops.ErrTraversalMemoryLimit, ops.TraversalMemoryLimit.Mebibytes(), treeSize.Mebibytes())
+		}
+
+		var (
+			next  = heap.Pop(frontier).(weightedSegment)
+			state = weightedState{
+				node: next.segment.Node.ID,
+			}
+		)
+
+		if s.plan.MaxDepth > 0 {
+			state.depth = next.depth
+		}
+
+		if _, isSettled := settled[state]; isSettled {
+			continue
+		}
+
+		settled[state] = struct{}{}
+
+		if next.depth > 0 && s.plan.Target(next.segment.Node) {
+			return next, true, nil
+		}
+
+		if s.plan.MaxDepth > 0 && next.depth >= s.plan.MaxDepth {
+			continue
+		}
+
+		if err := s.tx.Relationships().Filter(weightedExpansionCriteria(s.plan.Direction, s.plan.Criteria, next.segment.Node)).FetchDirection(s.fetchDirection, func(cursor graph.Cursor[graph.DirectionalResult]) error {
+			for result := range cursor.Chan() {
+				if _, isExcluded := excludedEdges[result.Relationship.ID]; isExcluded {
+					continue
+				}
+
+				descendingSegment := next.segment.Descend(result.Node, result.Relationship)
+
+				if descendingSegment.IsCycle() {
+					continue
+				}
+
+				if cost, traversable := s.plan.Cost(descendingSegment); traversable && cost >= 0 {
+					heap.Push(frontier, weightedSegment{
+						segment: descendingSegment,
+						cost:    next.cost + cost,
+						depth:   next.depth + 1,
+					})
+				}
+			}
+
+			return cursor.Error()
+		}); err != nil {
+			return weightedSegment{}, false, err
+		}
+	}
+
+	return weightedSegment{}, false, nil
+}
+
+// KShortestPaths returns up to K of the cheapest acyclic paths from the root of the given plan to nodes that satisfy
+// the plan's target function, ordered by ascending cost. Paths end at the first target node that they reach.
+//
+// Paths are enumerated with Yen's algorithm. After the cheapest path is found, each following path is the cheapest
+// deviation from a previously found path: for every node of the last path found, a best-first search looks for the
+// cheapest path that shares the last path's nodes up to that node but leaves it by a relationship that no previously
+// found path with the same prefix has taken.
+func KShortestPaths(ctx context.Context, db graph.Database, plan WeightedPlan) ([]WeightedPath, error) {
+	if plan.K <= 0 {
+		return nil, nil
+	}
+
+	// The direction of a fetch describes the relationship from the perspective of the returned node which is the
+	// reverse of the traversal direction
+	fetchDirection, err := plan.Direction.Reverse()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		paths      []WeightedPath
+		candidates = &weightedSegmentHeap{}
+		discovered = map[string]struct{}{}
+	)
+
+	err = db.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		search := weightedSearch{
+			ctx:            ctx,
+			tx:             tx,
+			plan:           plan,
+			fetchDirection: fetchDirection,
+		}
+
+		if cheapest, found, err := search.cheapestPath(weightedSegment{segment: graph.NewRootPathSegment(plan.Root)}, nil); err != nil || !found {
+			return err
+		} else {
+			heap.Push(candidates, cheapest)
+		}
+
+		for candidates.Len() > 0 && len(paths) < plan.K {
+			var (
+				next     = heap.Pop(candidates).(weightedSegment)
+				nextPath = next.segment.Path()
+			)
+
+			paths = append(paths, WeightedPath{
+				Path: nextPath,
+				Cost: next.cost,
+			})
+
+			discovered[weightedPathKey(nextPath)] = struct{}{}
+
+			if len(paths) == plan.K {
+				break
+			}
+
+			// The terminal node of a path is a target and can not be deviated from
+			for spurDepth := 0; spurDepth < next.depth; spurDepth++ {
+				excludedEdges := map[graph.ID]struct{}{}
+
+				for _, path := range paths {
+					if sharesRootPath(path.Path, nextPath, spurDepth) {
+						excludedEdges[path.Path.Edges[spurDepth].ID] = struct{}{}
+					}
+				}
+
+				// Nodes of the root path are excluded from the deviation since paths must be acyclic
+				if deviation, found, err := search.cheapestPath(search.descend(nextPath, spurDepth), excludedEdges); err != nil {
+					return err
+				} else if found {
+					deviationPath := deviation.segment.Path()
+
+					if _, isDiscovered := discovered[weightedPathKey(deviationPath)]; !isDiscovered {
+						discovered[weightedPathKey(deviationPath)] = struct{}{}
+
+						// Rebuild the deviation so that the candidate does not retain the search tree it was found in
+						candidate := search.descend(deviationPath, deviation.depth)
+						candidate.cost = deviation.cost
+
+						heap.Push(candidates, candidate)
+					}
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return paths, err
+}
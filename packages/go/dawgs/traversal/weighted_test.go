@@ -0,0 +1,248 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package traversal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specterops/bloodhound/dawgs/drivers/memory"
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/dawgs/query"
+	"github.com/specterops/bloodhound/dawgs/traversal"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	kindNode     = graph.StringKind("Node")
+	kindCheap    = graph.StringKind("Cheap")
+	kindExpense  = graph.StringKind("Expensive")
+	kindExcluded = graph.StringKind("Excluded")
+)
+
+type weightedGraph struct {
+	Start  *graph.Node
+	A      *graph.Node
+	B      *graph.Node
+	C      *graph.Node
+	Target *graph.Node
+}
+
+// newWeightedGraph creates the following graph:
+//
+//	(Start) -[Expensive]-> (Target)
+//	(Start) -[Cheap]-> (A) -[Cheap]-> (B) -[Cheap]-> (Target)
+//	(Start) -[Cheap]-> (C) -[Expensive]-> (Target)
+//	(Start) -[Excluded]-> (Target)
+//	(B) -[Cheap]-> (A)
+func newWeightedGraph(t *testing.T, db graph.Database) weightedGraph {
+	var harness weightedGraph
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(name string) *graph.Node {
+			node, err := tx.CreateNode(graph.AsProperties(map[string]any{"name": name}), kindNode)
+			require.Nil(t, err)
+
+			return node
+		}
+
+		newRelationship := func(start, end *graph.Node, kind graph.Kind) {
+			_, err := tx.CreateRelationshipByIDs(start.ID, end.ID, kind, graph.NewProperties())
+			require.Nil(t, err)
+		}
+
+		harness.Start = newNode("start")
+		harness.A = newNode("a")
+		harness.B = newNode("b")
+		harness.C = newNode("c")
+		harness.Target = newNode("target")
+
+		newRelationship(harness.Start, harness.Target, kindExpense)
+		newRelationship(harness.Start, harness.A, kindCheap)
+		newRelationship(harness.A, harness.B, kindCheap)
+		newRelationship(harness.B, harness.Target, kindCheap)
+		newRelationship(harness.B, harness.A, kindCheap)
+		newRelationship(harness.Start, harness.C, kindCheap)
+		newRelationship(harness.C, harness.Target, kindExpense)
+		newRelationship(harness.Start, harness.Target, kindExcluded)
+
+		return nil
+	}))
+
+	return harness
+}
+
+func pathNodeIDs(path graph.Path) []graph.ID {
+	var ids []graph.ID
+
+	for _, node := range path.Nodes {
+		ids = append(ids, node.ID)
+	}
+
+	return ids
+}
+
+func TestKShortestPaths(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newWeightedGraph(t, db)
+		plan    = traversal.WeightedPlan{
+			Root:      harness.Start,
+			Direction: graph.DirectionOutbound,
+			Criteria:  query.Not(query.Kind(query.Relationship(), kindExcluded)),
+			Cost: traversal.KindCostFunction(map[string]float64{
+				kindCheap.String():   1,
+				kindExpense.String(): 10,
+			}, 5),
+			Target: func(node *graph.Node) bool {
+				return node.ID == harness.Target.ID
+			},
+			K: 5,
+		}
+	)
+
+	paths, err := traversal.KShortestPaths(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 3)
+
+	// The cheapest path is the longest
+	require.Equal(t, float64(3), paths[0].Cost)
+	require.Equal(t, []graph.ID{harness.Start.ID, harness.A.ID, harness.B.ID, harness.Target.ID}, pathNodeIDs(paths[0].Path))
+
+	require.Equal(t, float64(10), paths[1].Cost)
+	require.Equal(t, []graph.ID{harness.Start.ID, harness.Target.ID}, pathNodeIDs(paths[1].Path))
+
+	require.Equal(t, float64(11), paths[2].Cost)
+	require.Equal(t, []graph.ID{harness.Start.ID, harness.C.ID, harness.Target.ID}, pathNodeIDs(paths[2].Path))
+
+	// Limit the number of paths returned
+	plan.K = 1
+
+	paths, err = traversal.KShortestPaths(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 1)
+	require.Equal(t, float64(3), paths[0].Cost)
+
+	// Limit the depth of returned paths
+	plan.K = 5
+	plan.MaxDepth = 2
+
+	paths, err = traversal.KShortestPaths(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 2)
+	require.Equal(t, float64(10), paths[0].Cost)
+	require.Equal(t, float64(11), paths[1].Cost)
+
+	// Exclude edges by cost function
+	plan.MaxDepth = 0
+	plan.Cost = func(next *graph.PathSegment) (float64, bool) {
+		return 1, next.Edge.Kind.Is(kindCheap)
+	}
+
+	paths, err = traversal.KShortestPaths(context.Background(), db, plan)
+	require.Nil(t, err)
+	require.Len(t, paths, 1)
+	require.Equal(t, float64(3), paths[0].Cost)
+}
+
+func TestKShortestPaths_Inbound(t *testing.T) {
+	var (
+		db      = memory.NewDatabase()
+		harness = newWeightedGraph(t, db)
+	)
+
+	paths, err := traversal.KShortestPaths(context.Background(), db, traversal.WeightedPlan{
+		Root:      harness.Target,
+		Direction: graph.DirectionInbound,
+		Cost:      traversal.KindCostFunction(nil, 1),
+		Target: func(node *graph.Node) bool {
+			return node.ID == harness.Start.ID
+		},
+		K: 2,
+	})
+
+	require.Nil(t, err)
+	require.Len(t, paths, 2)
+	require.Equal(t, float64(1), paths[0].Cost)
+	require.Equal(t, float64(1), paths[1].Cost)
+}
+
+func TestKShortestPaths_RevisitedPrefixes(t *testing.T) {
+	// Creates the following graph where both of the cheapest paths to C pass through A, which leaves the more
+	// expensive path through B and C as the only way to reach the target without revisiting A:
+	//
+	//	(Start) -[2]-> (A) -[3]-> (Target)
+	//	(Start) -[4]-> (B) -[4]-> (C) -[4]-> (A)
+	//	(A) -[1]-> (B)
+	//	(A) -[2]-> (C)
+	var (
+		db                   = memory.NewDatabase()
+		start, a, b, c, goal *graph.Node
+	)
+
+	require.Nil(t, db.WriteTransaction(context.Background(), func(tx graph.Transaction) error {
+		newNode := func(name string) *graph.Node {
+			node, err := tx.CreateNode(graph.AsProperties(map[string]any{"name": name}), kindNode)
+			require.Nil(t, err)
+
+			return node
+		}
+
+		newRelationship := func(start, end *graph.Node, cost float64) {
+			_, err := tx.CreateRelationshipByIDs(start.ID, end.ID, kindCheap, graph.AsProperties(map[string]any{"cost": cost}))
+			require.Nil(t, err)
+		}
+
+		start = newNode("start")
+		a = newNode("a")
+		b = newNode("b")
+		c = newNode("c")
+		goal = newNode("target")
+
+		newRelationship(start, a, 2)
+		newRelationship(a, goal, 3)
+		newRelationship(start, b, 4)
+		newRelationship(b, c, 4)
+		newRelationship(c, a, 4)
+		newRelationship(a, b, 1)
+		newRelationship(a, c, 2)
+
+		return nil
+	}))
+
+	paths, err := traversal.KShortestPaths(context.Background(), db, traversal.WeightedPlan{
+		Root:      start,
+		Direction: graph.DirectionOutbound,
+		Cost: func(next *graph.PathSegment) (float64, bool) {
+			cost, err := next.Edge.Properties.Get("cost").Float64()
+			return cost, err == nil
+		},
+		Target: func(node *graph.Node) bool {
+			return node.ID == goal.ID
+		},
+		K: 2,
+	})
+
+	require.Nil(t, err)
+	require.Len(t, paths, 2)
+
+	require.Equal(t, float64(5), paths[0].Cost)
+	require.Equal(t, []graph.ID{start.ID, a.ID, goal.ID}, pathNodeIDs(paths[0].Path))
+
+	require.Equal(t, float64(15), paths[1].Cost)
+	require.Equal(t, []graph.ID{start.ID, b.ID, c.ID, a.ID, goal.ID}, pathNodeIDs(paths[1].Path))
+}
@@ -379,3 +379,105 @@ func ParseUserRightData(userRight UserRightsAssignmentAPIResult, computer Comput
 
 	return relationships
 }
+
+// ConvertEnterpriseCAToNode converts the enterprise CA into an ingestible node and folds the CA registry collection
+// results into node properties so that post-processing does not need to inspect the raw collector output.
+func ConvertEnterpriseCAToNode(enterpriseCA EnterpriseCA) IngestibleNode {
+	node := ConvertObjectToNode(enterpriseCA.IngestBase, ad.EnterpriseCA)
+
+	if node.PropertyMap == nil {
+		node.PropertyMap = make(map[string]any)
+	}
+
+	if enterpriseCA.CARegistryData.IsUserSpecifiesSanEnabled.Collected {
+		node.PropertyMap[ad.IsUserSpecifiesSanEnabled.String()] = enterpriseCA.CARegistryData.IsUserSpecifiesSanEnabled.Value
+	}
+
+	if enterpriseCA.CARegistryData.EnrollmentAgentRestrictions.Collected {
+		node.PropertyMap[ad.HasEnrollmentAgentRestrictions.String()] = len(enterpriseCA.CARegistryData.EnrollmentAgentRestrictions.Restrictions) > 0
+	}
+
+	hasVulnerableEndpoint := false
+	for _, endpoint := range enterpriseCA.HttpEnrollmentEndpoints {
+		if !endpoint.Collected {
+			continue
+		}
+
+		// Web enrollment is relayable when it is served over plain HTTP or when it is served over HTTPS without
+		// Extended Protection for Authentication
+		if strings.HasPrefix(strings.ToLower(endpoint.Url), "http://") || !endpoint.EPAEnforced {
+			hasVulnerableEndpoint = true
+			break
+		}
+	}
+
+	node.PropertyMap[ad.HasVulnerableEndpoint.String()] = hasVulnerableEndpoint
+	return node
+}
+
+// ParseEnterpriseCAMiscData parses HostsCAService, PublishedTo and the CA security descriptor stored in the CA registry
+func ParseEnterpriseCAMiscData(enterpriseCA EnterpriseCA) []IngestibleRelationship {
+	relationships := make([]IngestibleRelationship, 0)
+
+	if enterpriseCA.HostingComputer != "" {
+		relationships = append(relationships, IngestibleRelationship{
+			Source:     enterpriseCA.HostingComputer,
+			SourceType: ad.Computer,
+			Target:     enterpriseCA.ObjectIdentifier,
+			TargetType: ad.EnterpriseCA,
+			RelType:    ad.HostsCAService,
+			RelProps:   map[string]any{"isacl": false},
+		})
+	}
+
+	for _, template := range enterpriseCA.EnabledCertTemplates {
+		relationships = append(relationships, IngestibleRelationship{
+			Source:     template.ObjectIdentifier,
+			SourceType: ad.CertTemplate,
+			Target:     enterpriseCA.ObjectIdentifier,
+			TargetType: ad.EnterpriseCA,
+			RelType:    ad.PublishedTo,
+			RelProps:   map[string]any{"isacl": false},
+		})
+	}
+
+	if enterpriseCA.CARegistryData.CASecurity.Collected {
+		relationships = append(relationships, ParseACEData(enterpriseCA.CARegistryData.CASecurity.Data, enterpriseCA.ObjectIdentifier, ad.EnterpriseCA)...)
+	}
+
+	return relationships
+}
+
+func ParseRootCAMiscData(rootCA RootCA) []IngestibleRelationship {
+	relationships := make([]IngestibleRelationship, 0)
+
+	if rootCA.DomainSID != "" {
+		relationships = append(relationships, IngestibleRelationship{
+			Source:     rootCA.ObjectIdentifier,
+			SourceType: ad.RootCA,
+			Target:     rootCA.DomainSID,
+			TargetType: ad.Domain,
+			RelType:    ad.RootCAFor,
+			RelProps:   map[string]any{"isacl": false},
+		})
+	}
+
+	return relationships
+}
+
+func ParseNTAuthStoreData(ntAuthStore NTAuthStore) []IngestibleRelationship {
+	relationships := make([]IngestibleRelationship, 0)
+
+	if ntAuthStore.DomainSID != "" {
+		relationships = append(relationships, IngestibleRelationship{
+			Source:     ntAuthStore.ObjectIdentifier,
+			SourceType: ad.NTAuthStore,
+			Target:     ntAuthStore.DomainSID,
+			TargetType: ad.Domain,
+			RelType:    ad.NTAuthStoreFor,
+			RelProps:   map[string]any{"isacl": false},
+		})
+	}
+
+	return relationships
+}
@@ -726,15 +726,22 @@ func ConvertAzureRoleEligibilityScheduleInstanceToRels(roleEligibilityScheduleIn
 		scope = strings.ToUpper(roleEligibilityScheduleInstance.DirectoryScopeId[1:])
 	}
 
+	relProps := map[string]any{
+		azure.Scope.String(): scope,
+	}
+
+	// Eligibility without an end date is permanent
+	if endDateTime, err := time.Parse(time.RFC3339, roleEligibilityScheduleInstance.EndDateTime); err == nil {
+		relProps[common.ValidUntil.String()] = endDateTime.UTC()
+	}
+
 	relationships = append(relationships, IngestibleRelationship{
 		Source:     strings.ToUpper(roleEligibilityScheduleInstance.PrincipalId),
 		SourceType: azure.Entity,
 		TargetType: azure.Role,
 		Target:     roleObjectId,
-		RelProps: map[string]any{
-			azure.Scope.String(): scope,
-		},
-		RelType: azure.EligibleRole,
+		RelProps:   relProps,
+		RelType:    azure.EligibleRole,
 	})
 
 	return relationships
@@ -0,0 +1,236 @@
+// Copyright 2023 Specter Ops, Inc.
+// 
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// 
+// SPDX-License-Identifier: Apache-2.0
+
+package ein
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/specterops/bloodhound/dawgs/graph"
+	"github.com/specterops/bloodhound/errors"
+	"github.com/specterops/bloodhound/graphschema/common"
+)
+
+const (
+	GenericObjectKindNode = "node"
+	GenericObjectKindEdge = "edge"
+
+	ErrInvalidGenericObjectKind = errors.Error("generic object kind must be node or edge")
+	ErrInvalidKindName          = errors.Error("invalid kind name")
+	ErrInvalidPropertyName      = errors.Error("invalid property name")
+	ErrMissingKinds             = errors.Error("node must have at least one kind")
+	ErrMissingIdentity          = errors.Error("missing identity property")
+)
+
+// genericNamePattern restricts the kind and identity property names accepted by generic ingest. Both end up in the
+// text of graph queries as labels, relationship types and property keys rather than being passed as parameters.
+var genericNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+func validateKindName(name string) error {
+	if !genericNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidKindName, name)
+	}
+
+	return nil
+}
+
+func validateIdentity(properties map[string]any, identityProperties []string) error {
+	for _, identityProperty := range identityProperties {
+		if !genericNamePattern.MatchString(identityProperty) {
+			return fmt.Errorf("%w: %q", ErrInvalidPropertyName, identityProperty)
+		} else if value, found := properties[identityProperty]; !found || value == nil {
+			return fmt.Errorf("%w: %s", ErrMissingIdentity, identityProperty)
+		}
+	}
+
+	return nil
+}
+
+// GenericNode is a node of arbitrary kinds submitted by a collector for a system that has no built-in schema, such as
+// GitHub, Okta or AWS IAM. The node is matched against existing nodes of the identity kind by the values of its
+// identity properties. The identity kind defaults to the first of the node's kinds and the identity properties
+// default to objectid.
+type GenericNode struct {
+	Kinds              []string       `json:"kinds"`
+	IdentityKind       string         `json:"identity_kind"`
+	IdentityProperties []string       `json:"identity_properties"`
+	Properties         map[string]any `json:"properties"`
+}
+
+func (s GenericNode) identityKind() string {
+	if s.IdentityKind != "" {
+		return s.IdentityKind
+	}
+
+	return s.Kinds[0]
+}
+
+func (s GenericNode) identityProperties() []string {
+	if len(s.IdentityProperties) > 0 {
+		return s.IdentityProperties
+	}
+
+	return []string{common.ObjectID.String()}
+}
+
+func (s GenericNode) Validate() error {
+	if len(s.Kinds) == 0 {
+		return ErrMissingKinds
+	}
+
+	hasIdentityKind := false
+
+	for _, kind := range s.Kinds {
+		if err := validateKindName(kind); err != nil {
+			return err
+		} else if kind == s.identityKind() {
+			hasIdentityKind = true
+		}
+	}
+
+	if !hasIdentityKind {
+		return fmt.Errorf("%w: identity kind %q is not one of the node's kinds", ErrInvalidKindName, s.IdentityKind)
+	}
+
+	return validateIdentity(s.Properties, s.identityProperties())
+}
+
+// GenericEndpoint references the start or end node of a GenericEdge by kind and the values of its identity properties.
+// Endpoints may reference built-in kinds, for example an AD User by objectid, to link generic data to existing nodes.
+type GenericEndpoint struct {
+	Kind     string         `json:"kind"`
+	Identity map[string]any `json:"identity"`
+}
+
+// identityProperties returns the names of the endpoint identity properties in a stable order so that updates that
+// match on the same properties are batched together
+func (s GenericEndpoint) identityProperties() []string {
+	identityProperties := make([]string, 0, len(s.Identity))
+
+	for identityProperty := range s.Identity {
+		identityProperties = append(identityProperties, identityProperty)
+	}
+
+	sort.Strings(identityProperties)
+	return identityProperties
+}
+
+func (s GenericEndpoint) Validate() error {
+	if err := validateKindName(s.Kind); err != nil {
+		return err
+	} else if len(s.Identity) == 0 {
+		return fmt.Errorf("%w: endpoint of kind %s has no identity", ErrMissingIdentity, s.Kind)
+	}
+
+	return validateIdentity(s.Identity, s.identityProperties())
+}
+
+// GenericEdge is a relationship of an arbitrary kind between two nodes submitted by a generic collector
+type GenericEdge struct {
+	Kind       string          `json:"kind"`
+	Start      GenericEndpoint `json:"start"`
+	End        GenericEndpoint `json:"end"`
+	Properties map[string]any  `json:"properties"`
+}
+
+func (s GenericEdge) Validate() error {
+	if err := validateKindName(s.Kind); err != nil {
+		return err
+	} else if err := s.Start.Validate(); err != nil {
+		return fmt.Errorf("start: %w", err)
+	} else if err := s.End.Validate(); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+
+	return nil
+}
+
+// GenericObject is a single element of generic collector output. Like Azure output, each element carries a kind that
+// selects whether its data is a GenericNode or a GenericEdge.
+type GenericObject struct {
+	Kind string
+	Node GenericNode
+	Edge GenericEdge
+}
+
+func (s *GenericObject) UnmarshalJSON(data []byte) error {
+	var base struct {
+		Kind string          `json:"kind"`
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &base); err != nil {
+		return err
+	}
+
+	s.Kind = base.Kind
+
+	switch base.Kind {
+	case GenericObjectKindNode:
+		return json.Unmarshal(base.Data, &s.Node)
+
+	case GenericObjectKindEdge:
+		return json.Unmarshal(base.Data, &s.Edge)
+
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidGenericObjectKind, base.Kind)
+	}
+}
+
+// Validate checks the names and identities of the object once decoded. Objects that fail validation must not be
+// ingested.
+func (s GenericObject) Validate() error {
+	if s.Kind == GenericObjectKindEdge {
+		return s.Edge.Validate()
+	}
+
+	return s.Node.Validate()
+}
+
+func copyProperties(properties map[string]any) map[string]any {
+	propertyMap := make(map[string]any, len(properties))
+
+	for key, value := range properties {
+		propertyMap[key] = value
+	}
+
+	return propertyMap
+}
+
+func ConvertGenericNode(node GenericNode) IngestibleGenericNode {
+	return IngestibleGenericNode{
+		Kinds:              graph.StringsToKinds(node.Kinds),
+		IdentityKind:       graph.StringKind(node.identityKind()),
+		IdentityProperties: node.identityProperties(),
+		PropertyMap:        copyProperties(node.Properties),
+	}
+}
+
+func ConvertGenericEdge(edge GenericEdge) IngestibleGenericRelationship {
+	return IngestibleGenericRelationship{
+		Kind:                    graph.StringKind(edge.Kind),
+		StartKind:               graph.StringKind(edge.Start.Kind),
+		StartIdentityProperties: edge.Start.identityProperties(),
+		StartIdentity:           copyProperties(edge.Start.Identity),
+		EndKind:                 graph.StringKind(edge.End.Kind),
+		EndIdentityProperties:   edge.End.identityProperties(),
+		EndIdentity:             copyProperties(edge.End.Identity),
+		PropertyMap:             copyProperties(edge.Properties),
+	}
+}
@@ -177,3 +177,60 @@ type OU struct {
 	ChildObjects []TypedPrincipal
 	Links        []GPLink
 }
+
+type CASecurity struct {
+	APIResult
+	Data []ACE
+}
+
+type EnrollmentAgentRestriction struct {
+	AccessType   string
+	Agent        TypedPrincipal
+	Targets      []TypedPrincipal
+	Template     TypedPrincipal
+	AllTemplates bool
+}
+
+type EnrollmentAgentRestrictionsAPIResult struct {
+	APIResult
+	Restrictions []EnrollmentAgentRestriction
+}
+
+type IsUserSpecifiesSanEnabledAPIResult struct {
+	APIResult
+	Value bool
+}
+
+type CARegistryData struct {
+	CASecurity                  CASecurity
+	EnrollmentAgentRestrictions EnrollmentAgentRestrictionsAPIResult
+	IsUserSpecifiesSanEnabled   IsUserSpecifiesSanEnabledAPIResult
+}
+
+type CAEnrollmentEndpoint struct {
+	APIResult
+	Url         string
+	EPAEnforced bool
+}
+
+type EnterpriseCA struct {
+	IngestBase
+	HostingComputer         string
+	CARegistryData          CARegistryData
+	EnabledCertTemplates    []TypedPrincipal
+	HttpEnrollmentEndpoints []CAEnrollmentEndpoint
+}
+
+type CertTemplate IngestBase
+
+type AIACA IngestBase
+
+type RootCA struct {
+	IngestBase
+	DomainSID string
+}
+
+type NTAuthStore struct {
+	IngestBase
+	DomainSID string
+}
@@ -47,6 +47,28 @@ func (s IngestibleNode) IsValid() bool {
 	return s.ObjectID != ""
 }
 
+// IngestibleGenericNode is a node of generic ingest that is matched by the declared identity kind and properties
+// rather than by objectid
+type IngestibleGenericNode struct {
+	Kinds              graph.Kinds
+	IdentityKind       graph.Kind
+	IdentityProperties []string
+	PropertyMap        map[string]any
+}
+
+// IngestibleGenericRelationship is a relationship of generic ingest whose endpoints are matched by the declared
+// identity kind and properties rather than by objectid
+type IngestibleGenericRelationship struct {
+	Kind                    graph.Kind
+	StartKind               graph.Kind
+	StartIdentityProperties []string
+	StartIdentity           map[string]any
+	EndKind                 graph.Kind
+	EndIdentityProperties   []string
+	EndIdentity             map[string]any
+	PropertyMap             map[string]any
+}
+
 type ParsedLocalGroupData struct {
 	Relationships []IngestibleRelationship
 	Nodes         []IngestibleNode
@@ -35,6 +35,11 @@ var (
 	Domain                          = graph.StringKind("Domain")
 	LocalGroup                      = graph.StringKind("ADLocalGroup")
 	LocalUser                       = graph.StringKind("ADLocalUser")
+	AIACA                           = graph.StringKind("AIACA")
+	RootCA                          = graph.StringKind("RootCA")
+	EnterpriseCA                    = graph.StringKind("EnterpriseCA")
+	NTAuthStore                     = graph.StringKind("NTAuthStore")
+	CertTemplate                    = graph.StringKind("CertTemplate")
 	Owns                            = graph.StringKind("Owns")
 	GenericAll                      = graph.StringKind("GenericAll")
 	GenericWrite                    = graph.StringKind("GenericWrite")
@@ -72,35 +77,69 @@ var (
 	RemoteInteractiveLogonPrivilege = graph.StringKind("RemoteInteractiveLogonPrivilege")
 	SyncLAPSPassword                = graph.StringKind("SyncLAPSPassword")
 	WriteAccountRestrictions        = graph.StringKind("WriteAccountRestrictions")
+	Enroll                          = graph.StringKind("Enroll")
+	ManageCA                        = graph.StringKind("ManageCA")
+	ManageCertificates              = graph.StringKind("ManageCertificates")
+	PublishedTo                     = graph.StringKind("PublishedTo")
+	HostsCAService                  = graph.StringKind("HostsCAService")
+	RootCAFor                       = graph.StringKind("RootCAFor")
+	NTAuthStoreFor                  = graph.StringKind("NTAuthStoreFor")
+	TrustedForNTAuth                = graph.StringKind("TrustedForNTAuth")
+	IssuedSignedBy                  = graph.StringKind("IssuedSignedBy")
+	EnterpriseCAFor                 = graph.StringKind("EnterpriseCAFor")
+	GoldenCert                      = graph.StringKind("GoldenCert")
+	ADCSESC1                        = graph.StringKind("ADCSESC1")
+	ADCSESC3                        = graph.StringKind("ADCSESC3")
+	ADCSESC4                        = graph.StringKind("ADCSESC4")
+	ADCSESC6                        = graph.StringKind("ADCSESC6")
+	ADCSESC8                        = graph.StringKind("ADCSESC8")
+	CoerceToTGT                     = graph.StringKind("CoerceToTGT")
+	AdminToViaRBCD                  = graph.StringKind("AdminToViaRBCD")
+	SyncedToEntraUser               = graph.StringKind("SyncedToEntraUser")
+	HostsEntraSyncAccount           = graph.StringKind("HostsEntraSyncAccount")
 )
 
 type Property string
 
 const (
-	AdminCount              Property = "admincount"
-	DistinguishedName       Property = "distinguishedname"
-	DomainFQDN              Property = "domain"
-	DomainSID               Property = "domainsid"
-	Sensitive               Property = "sensitive"
-	HighValue               Property = "highvalue"
-	BlocksInheritance       Property = "blocksinheritance"
-	IsACL                   Property = "isacl"
-	IsACLProtected          Property = "isaclprotected"
-	Enforced                Property = "enforced"
-	Department              Property = "department"
-	HasSPN                  Property = "hasspn"
-	UnconstrainedDelegation Property = "unconstraineddelegation"
-	LastLogon               Property = "lastlogon"
-	LastLogonTimestamp      Property = "lastlogontimestamp"
-	IsPrimaryGroup          Property = "isprimarygroup"
-	HasLAPS                 Property = "haslaps"
-	DontRequirePreAuth      Property = "dontreqpreauth"
-	LogonType               Property = "logontype"
-	HasURA                  Property = "hasura"
+	AdminCount                     Property = "admincount"
+	DistinguishedName              Property = "distinguishedname"
+	DomainFQDN                     Property = "domain"
+	DomainSID                      Property = "domainsid"
+	Sensitive                      Property = "sensitive"
+	HighValue                      Property = "highvalue"
+	BlocksInheritance              Property = "blocksinheritance"
+	IsACL                          Property = "isacl"
+	IsACLProtected                 Property = "isaclprotected"
+	Enforced                       Property = "enforced"
+	Department                     Property = "department"
+	HasSPN                         Property = "hasspn"
+	UnconstrainedDelegation        Property = "unconstraineddelegation"
+	LastLogon                      Property = "lastlogon"
+	LastLogonTimestamp             Property = "lastlogontimestamp"
+	IsPrimaryGroup                 Property = "isprimarygroup"
+	HasLAPS                        Property = "haslaps"
+	DontRequirePreAuth             Property = "dontreqpreauth"
+	LogonType                      Property = "logontype"
+	HasURA                         Property = "hasura"
+	CertThumbprint                 Property = "certthumbprint"
+	CertThumbprints                Property = "certthumbprints"
+	CertChain                      Property = "certchain"
+	CAName                         Property = "caname"
+	EnrolleeSuppliesSubject        Property = "enrolleesuppliessubject"
+	RequiresManagerApproval        Property = "requiresmanagerapproval"
+	AuthenticationEnabled          Property = "authenticationenabled"
+	EKUs                           Property = "ekus"
+	CertificateApplicationPolicy   Property = "certificateapplicationpolicy"
+	AuthorizedSignatures           Property = "authorizedsignatures"
+	SchemaVersion                  Property = "schemaversion"
+	IsUserSpecifiesSanEnabled      Property = "isuserspecifiessanenabled"
+	HasEnrollmentAgentRestrictions Property = "hasenrollmentagentrestrictions"
+	HasVulnerableEndpoint          Property = "hasvulnerableendpoint"
 )
 
 func AllProperties() []Property {
-	return []Property{AdminCount, DistinguishedName, DomainFQDN, DomainSID, Sensitive, HighValue, BlocksInheritance, IsACL, IsACLProtected, Enforced, Department, HasSPN, UnconstrainedDelegation, LastLogon, LastLogonTimestamp, IsPrimaryGroup, HasLAPS, DontRequirePreAuth, LogonType, HasURA}
+	return []Property{AdminCount, DistinguishedName, DomainFQDN, DomainSID, Sensitive, HighValue, BlocksInheritance, IsACL, IsACLProtected, Enforced, Department, HasSPN, UnconstrainedDelegation, LastLogon, LastLogonTimestamp, IsPrimaryGroup, HasLAPS, DontRequirePreAuth, LogonType, HasURA, CertThumbprint, CertThumbprints, CertChain, CAName, EnrolleeSuppliesSubject, RequiresManagerApproval, AuthenticationEnabled, EKUs, CertificateApplicationPolicy, AuthorizedSignatures, SchemaVersion, IsUserSpecifiesSanEnabled, HasEnrollmentAgentRestrictions, HasVulnerableEndpoint}
 }
 func ParseProperty(source string) (Property, error) {
 	switch source {
@@ -144,6 +183,34 @@ func ParseProperty(source string) (Property, error) {
 		return LogonType, nil
 	case "hasura":
 		return HasURA, nil
+	case "certthumbprint":
+		return CertThumbprint, nil
+	case "certthumbprints":
+		return CertThumbprints, nil
+	case "certchain":
+		return CertChain, nil
+	case "caname":
+		return CAName, nil
+	case "enrolleesuppliessubject":
+		return EnrolleeSuppliesSubject, nil
+	case "requiresmanagerapproval":
+		return RequiresManagerApproval, nil
+	case "authenticationenabled":
+		return AuthenticationEnabled, nil
+	case "ekus":
+		return EKUs, nil
+	case "certificateapplicationpolicy":
+		return CertificateApplicationPolicy, nil
+	case "authorizedsignatures":
+		return AuthorizedSignatures, nil
+	case "schemaversion":
+		return SchemaVersion, nil
+	case "isuserspecifiessanenabled":
+		return IsUserSpecifiesSanEnabled, nil
+	case "hasenrollmentagentrestrictions":
+		return HasEnrollmentAgentRestrictions, nil
+	case "hasvulnerableendpoint":
+		return HasVulnerableEndpoint, nil
 	default:
 		return "", errors.New("Invalid enumeration value: " + source)
 	}
@@ -190,6 +257,34 @@ func (s Property) String() string {
 		return string(LogonType)
 	case HasURA:
 		return string(HasURA)
+	case CertThumbprint:
+		return string(CertThumbprint)
+	case CertThumbprints:
+		return string(CertThumbprints)
+	case CertChain:
+		return string(CertChain)
+	case CAName:
+		return string(CAName)
+	case EnrolleeSuppliesSubject:
+		return string(EnrolleeSuppliesSubject)
+	case RequiresManagerApproval:
+		return string(RequiresManagerApproval)
+	case AuthenticationEnabled:
+		return string(AuthenticationEnabled)
+	case EKUs:
+		return string(EKUs)
+	case CertificateApplicationPolicy:
+		return string(CertificateApplicationPolicy)
+	case AuthorizedSignatures:
+		return string(AuthorizedSignatures)
+	case SchemaVersion:
+		return string(SchemaVersion)
+	case IsUserSpecifiesSanEnabled:
+		return string(IsUserSpecifiesSanEnabled)
+	case HasEnrollmentAgentRestrictions:
+		return string(HasEnrollmentAgentRestrictions)
+	case HasVulnerableEndpoint:
+		return string(HasVulnerableEndpoint)
 	default:
 		panic("Invalid enumeration case: " + string(s))
 	}
@@ -236,6 +331,34 @@ func (s Property) Name() string {
 		return "Logon Type"
 	case HasURA:
 		return "Has User Rights Assignment Collection"
+	case CertThumbprint:
+		return "Certificate Thumbprint"
+	case CertThumbprints:
+		return "Certificate Thumbprints"
+	case CertChain:
+		return "Certificate Chain"
+	case CAName:
+		return "CA Name"
+	case EnrolleeSuppliesSubject:
+		return "Enrollee Supplies Subject"
+	case RequiresManagerApproval:
+		return "Requires Manager Approval"
+	case AuthenticationEnabled:
+		return "Authentication Enabled"
+	case EKUs:
+		return "Enhanced Key Usage"
+	case CertificateApplicationPolicy:
+		return "Certificate Application Policies"
+	case AuthorizedSignatures:
+		return "Authorized Signatures Required"
+	case SchemaVersion:
+		return "Schema Version"
+	case IsUserSpecifiesSanEnabled:
+		return "User Specified SAN Enabled"
+	case HasEnrollmentAgentRestrictions:
+		return "Has Enrollment Agent Restrictions"
+	case HasVulnerableEndpoint:
+		return "Has Vulnerable Web Enrollment Endpoint"
 	default:
 		panic("Invalid enumeration case: " + string(s))
 	}
@@ -249,16 +372,16 @@ func (s Property) Is(others ...graph.Kind) bool {
 	return false
 }
 func Nodes() []graph.Kind {
-	return []graph.Kind{Entity, User, Computer, Group, GPO, OU, Container, Domain, LocalGroup, LocalUser}
+	return []graph.Kind{Entity, User, Computer, Group, GPO, OU, Container, Domain, LocalGroup, LocalUser, AIACA, RootCA, EnterpriseCA, NTAuthStore, CertTemplate}
 }
 func Relationships() []graph.Kind {
-	return []graph.Kind{Owns, GenericAll, GenericWrite, WriteOwner, WriteDACL, MemberOf, ForceChangePassword, AllExtendedRights, AddMember, HasSession, Contains, GPLink, AllowedToDelegate, GetChanges, GetChangesAll, GetChangesInFilteredSet, TrustedBy, AllowedToAct, AdminTo, CanPSRemote, CanRDP, ExecuteDCOM, HasSIDHistory, AddSelf, DCSync, ReadLAPSPassword, ReadGMSAPassword, DumpSMSAPassword, SQLAdmin, AddAllowedToAct, WriteSPN, AddKeyCredentialLink, LocalToComputer, MemberOfLocalGroup, RemoteInteractiveLogonPrivilege, SyncLAPSPassword, WriteAccountRestrictions}
+	return []graph.Kind{Owns, GenericAll, GenericWrite, WriteOwner, WriteDACL, MemberOf, ForceChangePassword, AllExtendedRights, AddMember, HasSession, Contains, GPLink, AllowedToDelegate, GetChanges, GetChangesAll, GetChangesInFilteredSet, TrustedBy, AllowedToAct, AdminTo, CanPSRemote, CanRDP, ExecuteDCOM, HasSIDHistory, AddSelf, DCSync, ReadLAPSPassword, ReadGMSAPassword, DumpSMSAPassword, SQLAdmin, AddAllowedToAct, WriteSPN, AddKeyCredentialLink, LocalToComputer, MemberOfLocalGroup, RemoteInteractiveLogonPrivilege, SyncLAPSPassword, WriteAccountRestrictions, Enroll, ManageCA, ManageCertificates, PublishedTo, HostsCAService, RootCAFor, NTAuthStoreFor, TrustedForNTAuth, IssuedSignedBy, EnterpriseCAFor, GoldenCert, ADCSESC1, ADCSESC3, ADCSESC4, ADCSESC6, ADCSESC8, CoerceToTGT, AdminToViaRBCD, SyncedToEntraUser, HostsEntraSyncAccount}
 }
 func ACLRelationships() []graph.Kind {
-	return []graph.Kind{AllExtendedRights, ForceChangePassword, AddMember, AddAllowedToAct, GenericAll, WriteDACL, WriteOwner, GenericWrite, ReadLAPSPassword, ReadGMSAPassword, Owns, AddSelf, WriteSPN, AddKeyCredentialLink, GetChanges, GetChangesAll, GetChangesInFilteredSet, WriteAccountRestrictions, SyncLAPSPassword, DCSync}
+	return []graph.Kind{AllExtendedRights, ForceChangePassword, AddMember, AddAllowedToAct, GenericAll, WriteDACL, WriteOwner, GenericWrite, ReadLAPSPassword, ReadGMSAPassword, Owns, AddSelf, WriteSPN, AddKeyCredentialLink, GetChanges, GetChangesAll, GetChangesInFilteredSet, WriteAccountRestrictions, SyncLAPSPassword, DCSync, Enroll, ManageCA, ManageCertificates}
 }
 func PathfindingRelationships() []graph.Kind {
-	return []graph.Kind{Owns, GenericAll, GenericWrite, WriteOwner, WriteDACL, MemberOf, ForceChangePassword, AllExtendedRights, AddMember, HasSession, Contains, GPLink, AllowedToDelegate, TrustedBy, AllowedToAct, AdminTo, CanPSRemote, CanRDP, ExecuteDCOM, HasSIDHistory, AddSelf, DCSync, ReadLAPSPassword, ReadGMSAPassword, DumpSMSAPassword, SQLAdmin, AddAllowedToAct, WriteSPN, AddKeyCredentialLink, SyncLAPSPassword, WriteAccountRestrictions}
+	return []graph.Kind{Owns, GenericAll, GenericWrite, WriteOwner, WriteDACL, MemberOf, ForceChangePassword, AllExtendedRights, AddMember, HasSession, Contains, GPLink, AllowedToDelegate, TrustedBy, AllowedToAct, AdminTo, CanPSRemote, CanRDP, ExecuteDCOM, HasSIDHistory, AddSelf, DCSync, ReadLAPSPassword, ReadGMSAPassword, DumpSMSAPassword, SQLAdmin, AddAllowedToAct, WriteSPN, AddKeyCredentialLink, SyncLAPSPassword, WriteAccountRestrictions, GoldenCert, ADCSESC1, ADCSESC3, ADCSESC4, ADCSESC6, ADCSESC8, CoerceToTGT, AdminToViaRBCD, SyncedToEntraUser, HostsEntraSyncAccount}
 }
 func IsACLKind(s graph.Kind) bool {
 	for _, acl := range ACLRelationships() {
@@ -269,5 +392,5 @@ func IsACLKind(s graph.Kind) bool {
 	return false
 }
 func NodeKinds() []graph.Kind {
-	return []graph.Kind{Entity, User, Computer, Group, GPO, OU, Container, Domain, LocalGroup, LocalUser}
+	return []graph.Kind{Entity, User, Computer, Group, GPO, OU, Container, Domain, LocalGroup, LocalUser, AIACA, RootCA, EnterpriseCA, NTAuthStore, CertTemplate}
 }
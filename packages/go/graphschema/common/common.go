@@ -31,25 +31,31 @@ var (
 type Property string
 
 const (
-	ObjectID        Property = "objectid"
-	Name            Property = "name"
-	DisplayName     Property = "displayname"
-	Description     Property = "description"
-	OwnerObjectID   Property = "owner_objectid"
-	Collected       Property = "collected"
-	OperatingSystem Property = "operatingsystem"
-	SystemTags      Property = "system_tags"
-	UserTags        Property = "user_tags"
-	LastSeen        Property = "lastseen"
-	WhenCreated     Property = "whencreated"
-	Enabled         Property = "enabled"
-	PasswordLastSet Property = "pwdlastset"
-	Title           Property = "title"
-	Email           Property = "email"
+	ObjectID         Property = "objectid"
+	Name             Property = "name"
+	DisplayName      Property = "displayname"
+	Description      Property = "description"
+	OwnerObjectID    Property = "owner_objectid"
+	Collected        Property = "collected"
+	OperatingSystem  Property = "operatingsystem"
+	SystemTags       Property = "system_tags"
+	UserTags         Property = "user_tags"
+	LastSeen         Property = "lastseen"
+	WhenCreated      Property = "whencreated"
+	Enabled          Property = "enabled"
+	PasswordLastSet  Property = "pwdlastset"
+	Title            Property = "title"
+	Email            Property = "email"
+	Stale            Property = "stale"
+	InboundExposure  Property = "inboundexposure"
+	OutboundExposure Property = "outboundexposure"
+	PostProcessed    Property = "post_processed"
+	ObservedAt       Property = "observed_at"
+	ValidUntil       Property = "valid_until"
 )
 
 func AllProperties() []Property {
-	return []Property{ObjectID, Name, DisplayName, Description, OwnerObjectID, Collected, OperatingSystem, SystemTags, UserTags, LastSeen, WhenCreated, Enabled, PasswordLastSet, Title, Email}
+	return []Property{ObjectID, Name, DisplayName, Description, OwnerObjectID, Collected, OperatingSystem, SystemTags, UserTags, LastSeen, WhenCreated, Enabled, PasswordLastSet, Title, Email, Stale, InboundExposure, OutboundExposure, PostProcessed, ObservedAt, ValidUntil}
 }
 func ParseProperty(source string) (Property, error) {
 	switch source {
@@ -83,6 +89,18 @@ func ParseProperty(source string) (Property, error) {
 		return Title, nil
 	case "email":
 		return Email, nil
+	case "stale":
+		return Stale, nil
+	case "inboundexposure":
+		return InboundExposure, nil
+	case "outboundexposure":
+		return OutboundExposure, nil
+	case "post_processed":
+		return PostProcessed, nil
+	case "observed_at":
+		return ObservedAt, nil
+	case "valid_until":
+		return ValidUntil, nil
 	default:
 		return "", errors.New("Invalid enumeration value: " + source)
 	}
@@ -119,6 +137,18 @@ func (s Property) String() string {
 		return string(Title)
 	case Email:
 		return string(Email)
+	case Stale:
+		return string(Stale)
+	case InboundExposure:
+		return string(InboundExposure)
+	case OutboundExposure:
+		return string(OutboundExposure)
+	case PostProcessed:
+		return string(PostProcessed)
+	case ObservedAt:
+		return string(ObservedAt)
+	case ValidUntil:
+		return string(ValidUntil)
 	default:
 		panic("Invalid enumeration case: " + string(s))
 	}
@@ -155,6 +185,18 @@ func (s Property) Name() string {
 		return "Title"
 	case Email:
 		return "Email"
+	case Stale:
+		return "Stale"
+	case InboundExposure:
+		return "Inbound Exposure"
+	case OutboundExposure:
+		return "Outbound Exposure"
+	case PostProcessed:
+		return "Post Processed"
+	case ObservedAt:
+		return "Observed At"
+	case ValidUntil:
+		return "Valid Until"
 	default:
 		panic("Invalid enumeration case: " + string(s))
 	}
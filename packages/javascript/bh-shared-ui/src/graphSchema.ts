@@ -25,6 +25,11 @@ export enum ActiveDirectoryNodeKind {
     Domain = 'Domain',
     LocalGroup = 'ADLocalGroup',
     LocalUser = 'ADLocalUser',
+    AIACA = 'AIACA',
+    RootCA = 'RootCA',
+    EnterpriseCA = 'EnterpriseCA',
+    NTAuthStore = 'NTAuthStore',
+    CertTemplate = 'CertTemplate',
 }
 export function ActiveDirectoryNodeKindToDisplay(value: ActiveDirectoryNodeKind): string | undefined {
     switch (value) {
@@ -48,6 +53,16 @@ export function ActiveDirectoryNodeKindToDisplay(value: ActiveDirectoryNodeKind)
             return 'LocalGroup';
         case ActiveDirectoryNodeKind.LocalUser:
             return 'LocalUser';
+        case ActiveDirectoryNodeKind.AIACA:
+            return 'AIACA';
+        case ActiveDirectoryNodeKind.RootCA:
+            return 'RootCA';
+        case ActiveDirectoryNodeKind.EnterpriseCA:
+            return 'EnterpriseCA';
+        case ActiveDirectoryNodeKind.NTAuthStore:
+            return 'NTAuthStore';
+        case ActiveDirectoryNodeKind.CertTemplate:
+            return 'CertTemplate';
         default:
             return undefined;
     }
@@ -90,6 +105,26 @@ export enum ActiveDirectoryRelationshipKind {
     RemoteInteractiveLogonPrivilege = 'RemoteInteractiveLogonPrivilege',
     SyncLAPSPassword = 'SyncLAPSPassword',
     WriteAccountRestrictions = 'WriteAccountRestrictions',
+    Enroll = 'Enroll',
+    ManageCA = 'ManageCA',
+    ManageCertificates = 'ManageCertificates',
+    PublishedTo = 'PublishedTo',
+    HostsCAService = 'HostsCAService',
+    RootCAFor = 'RootCAFor',
+    NTAuthStoreFor = 'NTAuthStoreFor',
+    TrustedForNTAuth = 'TrustedForNTAuth',
+    IssuedSignedBy = 'IssuedSignedBy',
+    EnterpriseCAFor = 'EnterpriseCAFor',
+    GoldenCert = 'GoldenCert',
+    ADCSESC1 = 'ADCSESC1',
+    ADCSESC3 = 'ADCSESC3',
+    ADCSESC4 = 'ADCSESC4',
+    ADCSESC6 = 'ADCSESC6',
+    ADCSESC8 = 'ADCSESC8',
+    CoerceToTGT = 'CoerceToTGT',
+    AdminToViaRBCD = 'AdminToViaRBCD',
+    SyncedToEntraUser = 'SyncedToEntraUser',
+    HostsEntraSyncAccount = 'HostsEntraSyncAccount',
 }
 export function ActiveDirectoryRelationshipKindToDisplay(value: ActiveDirectoryRelationshipKind): string | undefined {
     switch (value) {
@@ -167,6 +202,46 @@ export function ActiveDirectoryRelationshipKindToDisplay(value: ActiveDirectoryR
             return 'SyncLAPSPassword';
         case ActiveDirectoryRelationshipKind.WriteAccountRestrictions:
             return 'WriteAccountRestrictions';
+        case ActiveDirectoryRelationshipKind.Enroll:
+            return 'Enroll';
+        case ActiveDirectoryRelationshipKind.ManageCA:
+            return 'ManageCA';
+        case ActiveDirectoryRelationshipKind.ManageCertificates:
+            return 'ManageCertificates';
+        case ActiveDirectoryRelationshipKind.PublishedTo:
+            return 'PublishedTo';
+        case ActiveDirectoryRelationshipKind.HostsCAService:
+            return 'HostsCAService';
+        case ActiveDirectoryRelationshipKind.RootCAFor:
+            return 'RootCAFor';
+        case ActiveDirectoryRelationshipKind.NTAuthStoreFor:
+            return 'NTAuthStoreFor';
+        case ActiveDirectoryRelationshipKind.TrustedForNTAuth:
+            return 'TrustedForNTAuth';
+        case ActiveDirectoryRelationshipKind.IssuedSignedBy:
+            return 'IssuedSignedBy';
+        case ActiveDirectoryRelationshipKind.EnterpriseCAFor:
+            return 'EnterpriseCAFor';
+        case ActiveDirectoryRelationshipKind.GoldenCert:
+            return 'GoldenCert';
+        case ActiveDirectoryRelationshipKind.ADCSESC1:
+            return 'ADCSESC1';
+        case ActiveDirectoryRelationshipKind.ADCSESC3:
+            return 'ADCSESC3';
+        case ActiveDirectoryRelationshipKind.ADCSESC4:
+            return 'ADCSESC4';
+        case ActiveDirectoryRelationshipKind.ADCSESC6:
+            return 'ADCSESC6';
+        case ActiveDirectoryRelationshipKind.ADCSESC8:
+            return 'ADCSESC8';
+        case ActiveDirectoryRelationshipKind.CoerceToTGT:
+            return 'CoerceToTGT';
+        case ActiveDirectoryRelationshipKind.AdminToViaRBCD:
+            return 'AdminToViaRBCD';
+        case ActiveDirectoryRelationshipKind.SyncedToEntraUser:
+            return 'SyncedToEntraUser';
+        case ActiveDirectoryRelationshipKind.HostsEntraSyncAccount:
+            return 'HostsEntraSyncAccount';
         default:
             return undefined;
     }
@@ -182,6 +257,11 @@ export enum ActiveDirectoryKind {
     Domain = 'Domain',
     LocalGroup = 'ADLocalGroup',
     LocalUser = 'ADLocalUser',
+    AIACA = 'AIACA',
+    RootCA = 'RootCA',
+    EnterpriseCA = 'EnterpriseCA',
+    NTAuthStore = 'NTAuthStore',
+    CertTemplate = 'CertTemplate',
     Owns = 'Owns',
     GenericAll = 'GenericAll',
     GenericWrite = 'GenericWrite',
@@ -219,6 +299,26 @@ export enum ActiveDirectoryKind {
     RemoteInteractiveLogonPrivilege = 'RemoteInteractiveLogonPrivilege',
     SyncLAPSPassword = 'SyncLAPSPassword',
     WriteAccountRestrictions = 'WriteAccountRestrictions',
+    Enroll = 'Enroll',
+    ManageCA = 'ManageCA',
+    ManageCertificates = 'ManageCertificates',
+    PublishedTo = 'PublishedTo',
+    HostsCAService = 'HostsCAService',
+    RootCAFor = 'RootCAFor',
+    NTAuthStoreFor = 'NTAuthStoreFor',
+    TrustedForNTAuth = 'TrustedForNTAuth',
+    IssuedSignedBy = 'IssuedSignedBy',
+    EnterpriseCAFor = 'EnterpriseCAFor',
+    GoldenCert = 'GoldenCert',
+    ADCSESC1 = 'ADCSESC1',
+    ADCSESC3 = 'ADCSESC3',
+    ADCSESC4 = 'ADCSESC4',
+    ADCSESC6 = 'ADCSESC6',
+    ADCSESC8 = 'ADCSESC8',
+    CoerceToTGT = 'CoerceToTGT',
+    AdminToViaRBCD = 'AdminToViaRBCD',
+    SyncedToEntraUser = 'SyncedToEntraUser',
+    HostsEntraSyncAccount = 'HostsEntraSyncAccount',
 }
 export function ActiveDirectoryKindToDisplay(value: ActiveDirectoryKind): string | undefined {
     switch (value) {
@@ -242,6 +342,16 @@ export function ActiveDirectoryKindToDisplay(value: ActiveDirectoryKind): string
             return 'LocalGroup';
         case ActiveDirectoryKind.LocalUser:
             return 'LocalUser';
+        case ActiveDirectoryKind.AIACA:
+            return 'AIACA';
+        case ActiveDirectoryKind.RootCA:
+            return 'RootCA';
+        case ActiveDirectoryKind.EnterpriseCA:
+            return 'EnterpriseCA';
+        case ActiveDirectoryKind.NTAuthStore:
+            return 'NTAuthStore';
+        case ActiveDirectoryKind.CertTemplate:
+            return 'CertTemplate';
         case ActiveDirectoryKind.Owns:
             return 'Owns';
         case ActiveDirectoryKind.GenericAll:
@@ -316,6 +426,46 @@ export function ActiveDirectoryKindToDisplay(value: ActiveDirectoryKind): string
             return 'SyncLAPSPassword';
         case ActiveDirectoryKind.WriteAccountRestrictions:
             return 'WriteAccountRestrictions';
+        case ActiveDirectoryKind.Enroll:
+            return 'Enroll';
+        case ActiveDirectoryKind.ManageCA:
+            return 'ManageCA';
+        case ActiveDirectoryKind.ManageCertificates:
+            return 'ManageCertificates';
+        case ActiveDirectoryKind.PublishedTo:
+            return 'PublishedTo';
+        case ActiveDirectoryKind.HostsCAService:
+            return 'HostsCAService';
+        case ActiveDirectoryKind.RootCAFor:
+            return 'RootCAFor';
+        case ActiveDirectoryKind.NTAuthStoreFor:
+            return 'NTAuthStoreFor';
+        case ActiveDirectoryKind.TrustedForNTAuth:
+            return 'TrustedForNTAuth';
+        case ActiveDirectoryKind.IssuedSignedBy:
+            return 'IssuedSignedBy';
+        case ActiveDirectoryKind.EnterpriseCAFor:
+            return 'EnterpriseCAFor';
+        case ActiveDirectoryKind.GoldenCert:
+            return 'GoldenCert';
+        case ActiveDirectoryKind.ADCSESC1:
+            return 'ADCSESC1';
+        case ActiveDirectoryKind.ADCSESC3:
+            return 'ADCSESC3';
+        case ActiveDirectoryKind.ADCSESC4:
+            return 'ADCSESC4';
+        case ActiveDirectoryKind.ADCSESC6:
+            return 'ADCSESC6';
+        case ActiveDirectoryKind.ADCSESC8:
+            return 'ADCSESC8';
+        case ActiveDirectoryKind.CoerceToTGT:
+            return 'CoerceToTGT';
+        case ActiveDirectoryKind.AdminToViaRBCD:
+            return 'AdminToViaRBCD';
+        case ActiveDirectoryKind.SyncedToEntraUser:
+            return 'SyncedToEntraUser';
+        case ActiveDirectoryKind.HostsEntraSyncAccount:
+            return 'HostsEntraSyncAccount';
         default:
             return undefined;
     }
@@ -341,6 +491,20 @@ export enum ActiveDirectoryKindProperties {
     DontRequirePreAuth = 'dontreqpreauth',
     LogonType = 'logontype',
     HasURA = 'hasura',
+    CertThumbprint = 'certthumbprint',
+    CertThumbprints = 'certthumbprints',
+    CertChain = 'certchain',
+    CAName = 'caname',
+    EnrolleeSuppliesSubject = 'enrolleesuppliessubject',
+    RequiresManagerApproval = 'requiresmanagerapproval',
+    AuthenticationEnabled = 'authenticationenabled',
+    EKUs = 'ekus',
+    CertificateApplicationPolicy = 'certificateapplicationpolicy',
+    AuthorizedSignatures = 'authorizedsignatures',
+    SchemaVersion = 'schemaversion',
+    IsUserSpecifiesSanEnabled = 'isuserspecifiessanenabled',
+    HasEnrollmentAgentRestrictions = 'hasenrollmentagentrestrictions',
+    HasVulnerableEndpoint = 'hasvulnerableendpoint',
 }
 export function ActiveDirectoryKindPropertiesToDisplay(value: ActiveDirectoryKindProperties): string | undefined {
     switch (value) {
@@ -384,6 +548,34 @@ export function ActiveDirectoryKindPropertiesToDisplay(value: ActiveDirectoryKin
             return 'Logon Type';
         case ActiveDirectoryKindProperties.HasURA:
             return 'Has User Rights Assignment Collection';
+        case ActiveDirectoryKindProperties.CertThumbprint:
+            return 'Certificate Thumbprint';
+        case ActiveDirectoryKindProperties.CertThumbprints:
+            return 'Certificate Thumbprints';
+        case ActiveDirectoryKindProperties.CertChain:
+            return 'Certificate Chain';
+        case ActiveDirectoryKindProperties.CAName:
+            return 'CA Name';
+        case ActiveDirectoryKindProperties.EnrolleeSuppliesSubject:
+            return 'Enrollee Supplies Subject';
+        case ActiveDirectoryKindProperties.RequiresManagerApproval:
+            return 'Requires Manager Approval';
+        case ActiveDirectoryKindProperties.AuthenticationEnabled:
+            return 'Authentication Enabled';
+        case ActiveDirectoryKindProperties.EKUs:
+            return 'Enhanced Key Usage';
+        case ActiveDirectoryKindProperties.CertificateApplicationPolicy:
+            return 'Certificate Application Policies';
+        case ActiveDirectoryKindProperties.AuthorizedSignatures:
+            return 'Authorized Signatures Required';
+        case ActiveDirectoryKindProperties.SchemaVersion:
+            return 'Schema Version';
+        case ActiveDirectoryKindProperties.IsUserSpecifiesSanEnabled:
+            return 'User Specified SAN Enabled';
+        case ActiveDirectoryKindProperties.HasEnrollmentAgentRestrictions:
+            return 'Has Enrollment Agent Restrictions';
+        case ActiveDirectoryKindProperties.HasVulnerableEndpoint:
+            return 'Has Vulnerable Web Enrollment Endpoint';
         default:
             return undefined;
     }
@@ -421,6 +613,16 @@ export function ActiveDirectoryPathfindingEdges(): ActiveDirectoryRelationshipKi
         ActiveDirectoryRelationshipKind.AddKeyCredentialLink,
         ActiveDirectoryRelationshipKind.SyncLAPSPassword,
         ActiveDirectoryRelationshipKind.WriteAccountRestrictions,
+        ActiveDirectoryRelationshipKind.GoldenCert,
+        ActiveDirectoryRelationshipKind.ADCSESC1,
+        ActiveDirectoryRelationshipKind.ADCSESC3,
+        ActiveDirectoryRelationshipKind.ADCSESC4,
+        ActiveDirectoryRelationshipKind.ADCSESC6,
+        ActiveDirectoryRelationshipKind.ADCSESC8,
+        ActiveDirectoryRelationshipKind.CoerceToTGT,
+        ActiveDirectoryRelationshipKind.AdminToViaRBCD,
+        ActiveDirectoryRelationshipKind.SyncedToEntraUser,
+        ActiveDirectoryRelationshipKind.HostsEntraSyncAccount,
     ];
 }
 export enum AzureNodeKind {
@@ -540,6 +742,7 @@ export enum AzureRelationshipKind {
     AZMGAddSecret = 'AZMGAddSecret',
     AZMGGrantAppRoles = 'AZMGGrantAppRoles',
     AZMGGrantRole = 'AZMGGrantRole',
+    SyncedToADUser = 'SyncedToADUser',
 }
 export function AzureRelationshipKindToDisplay(value: AzureRelationshipKind): string | undefined {
     switch (value) {
@@ -639,6 +842,8 @@ export function AzureRelationshipKindToDisplay(value: AzureRelationshipKind): st
             return 'AZMGGrantAppRoles';
         case AzureRelationshipKind.AZMGGrantRole:
             return 'AZMGGrantRole';
+        case AzureRelationshipKind.SyncedToADUser:
+            return 'SyncedToADUser';
         default:
             return undefined;
     }
@@ -712,6 +917,7 @@ export enum AzureKind {
     AZMGAddSecret = 'AZMGAddSecret',
     AZMGGrantAppRoles = 'AZMGGrantAppRoles',
     AZMGGrantRole = 'AZMGGrantRole',
+    SyncedToADUser = 'SyncedToADUser',
 }
 export function AzureKindToDisplay(value: AzureKind): string | undefined {
     switch (value) {
@@ -851,6 +1057,8 @@ export function AzureKindToDisplay(value: AzureKind): string | undefined {
             return 'AZMGGrantAppRoles';
         case AzureKind.AZMGGrantRole:
             return 'AZMGGrantRole';
+        case AzureKind.SyncedToADUser:
+            return 'SyncedToADUser';
         default:
             return undefined;
     }
@@ -995,5 +1203,6 @@ export function AzurePathfindingEdges(): AzureRelationshipKind[] {
         AzureRelationshipKind.AZMGAddSecret,
         AzureRelationshipKind.AZMGGrantAppRoles,
         AzureRelationshipKind.AZMGGrantRole,
+        AzureRelationshipKind.SyncedToADUser,
     ];
 }